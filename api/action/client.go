@@ -76,8 +76,9 @@ func (c *Client) ListCompleted(arg params.Entities) (params.ActionsByReceivers,
 	return results, err
 }
 
-// Cancel attempts to cancel a queued up Action from running.
-func (c *Client) Cancel(arg params.Actions) (params.ActionResults, error) {
+// Cancel takes a list of ActionTags and attempts to cancel each of the
+// corresponding queued up Actions from running.
+func (c *Client) Cancel(arg params.Entities) (params.ActionResults, error) {
 	results := params.ActionResults{}
 	err := c.facade.FacadeCall("Cancel", arg, &results)
 	return results, err
@@ -0,0 +1,35 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides methods that the Juju client command uses to query
+// the audit log of an environment.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new `Client` based on an existing authenticated API
+// connection.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "AuditLog")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// Entries returns the audit log entries that match the given filter,
+// oldest first.
+func (c *Client) Entries(filter params.AuditLogFilter) ([]params.AuditLogEntry, error) {
+	var results params.AuditLogResults
+	if err := c.facade.FacadeCall("Entries", filter, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Entries, nil
+}
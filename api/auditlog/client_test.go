@@ -0,0 +1,51 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/auditlog"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/audit"
+	jujutesting "github.com/juju/juju/juju/testing"
+)
+
+type auditlogSuite struct {
+	jujutesting.JujuConnSuite
+
+	client *auditlog.Client
+}
+
+var _ = gc.Suite(&auditlogSuite{})
+
+func (s *auditlogSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.client = auditlog.NewClient(s.APIState)
+	c.Assert(s.client, gc.NotNil)
+}
+
+func (s *auditlogSuite) TestEntries(c *gc.C) {
+	timestamp := time.Date(2014, 11, 5, 10, 0, 0, 0, time.UTC)
+	err := s.State.AddAuditEntry(audit.Entry{
+		Timestamp: timestamp,
+		User:      "user-bob",
+		Facade:    "UserManager",
+		Method:    "SetPassword",
+		Arguments: `{"Changes":[{"Password":"<redacted>"}]}`,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	entries, err := s.client.Entries(params.AuditLogFilter{User: "user-bob"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 1)
+	c.Assert(entries[0].Timestamp.Equal(timestamp), jc.IsTrue)
+	c.Assert(entries[0].User, gc.Equals, "user-bob")
+	c.Assert(entries[0].Facade, gc.Equals, "UserManager")
+	c.Assert(entries[0].Method, gc.Equals, "SetPassword")
+	c.Assert(entries[0].Arguments, gc.Equals, `{"Changes":[{"Password":"<redacted>"}]}`)
+}
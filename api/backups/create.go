@@ -9,11 +9,15 @@ import (
 	"github.com/juju/juju/apiserver/params"
 )
 
-// Create sends a request to create a backup of juju's state.  It
-// returns the metadata associated with the resulting backup.
-func (c *Client) Create(notes string) (*params.BackupsMetadataResult, error) {
+// Create sends a request to create a backup of juju's state.  The
+// backup is encrypted with the key unless it is nil.  It returns the
+// metadata associated with the resulting backup.
+func (c *Client) Create(notes string, key []byte) (*params.BackupsMetadataResult, error) {
 	var result params.BackupsMetadataResult
-	args := params.BackupsCreateArgs{Notes: notes}
+	args := params.BackupsCreateArgs{
+		Notes:         notes,
+		EncryptionKey: key,
+	}
 	if err := c.facade.FacadeCall("Create", args, &result); err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -27,6 +27,7 @@ func (s *createSuite) TestCreate(c *gc.C) {
 			c.Assert(paramsIn, gc.FitsTypeOf, params.BackupsCreateArgs{})
 			p := paramsIn.(params.BackupsCreateArgs)
 			c.Check(p.Notes, gc.Equals, "important")
+			c.Check(p.EncryptionKey, gc.IsNil)
 
 			if result, ok := resp.(*params.BackupsMetadataResult); ok {
 				*result = apiserverbackups.ResultFromMetadata(s.Meta)
@@ -39,9 +40,29 @@ func (s *createSuite) TestCreate(c *gc.C) {
 	)
 	defer cleanup()
 
-	result, err := s.client.Create("important")
+	result, err := s.client.Create("important", nil)
 	c.Assert(err, jc.ErrorIsNil)
 
 	meta := backupstesting.UpdateNotes(s.Meta, "important")
 	s.checkMetadataResult(c, result, meta)
 }
+
+func (s *createSuite) TestCreateEncrypted(c *gc.C) {
+	cleanup := backups.PatchClientFacadeCall(s.client,
+		func(req string, paramsIn interface{}, resp interface{}) error {
+			c.Assert(paramsIn, gc.FitsTypeOf, params.BackupsCreateArgs{})
+			p := paramsIn.(params.BackupsCreateArgs)
+			c.Check(p.EncryptionKey, jc.DeepEquals, []byte("some secret key!"))
+
+			result := resp.(*params.BackupsMetadataResult)
+			*result = apiserverbackups.ResultFromMetadata(s.Meta)
+			result.KeyID = "0123456789abcdef"
+			return nil
+		},
+	)
+	defer cleanup()
+
+	result, err := s.client.Create("", []byte("some secret key!"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.KeyID, gc.Equals, "0123456789abcdef")
+}
@@ -4,8 +4,12 @@
 package backups
 
 import (
+	"crypto/sha1"
+	"encoding/base64"
+	"hash"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/juju/errors"
 
@@ -13,7 +17,9 @@ import (
 	"github.com/juju/juju/apiserver/params"
 )
 
-// Download returns an io.ReadCloser for the given backup id.
+// Download returns an io.ReadCloser for the given backup id.  The
+// archive is checked against the checksum sent by the server as it is
+// read: if they do not match, reading the end of the archive fails.
 func (c *Client) Download(id string) (io.ReadCloser, error) {
 	// Send the request.
 	args := params.BackupsDownloadArgs{
@@ -33,5 +39,47 @@ func (c *Client) Download(id string) (io.ReadCloser, error) {
 		return nil, errors.Trace(failure)
 	}
 
-	return resp.Body, nil
+	checksum := digestChecksum(resp.Header.Get("Digest"))
+	if checksum == "" {
+		return resp.Body, nil
+	}
+	return &verifyingReader{
+		ReadCloser: resp.Body,
+		hasher:     sha1.New(),
+		expected:   checksum,
+	}, nil
+}
+
+// digestChecksum returns the SHA checksum in the value of a Digest
+// header, or "" if there is none.
+func digestChecksum(digest string) string {
+	prefix := string(apihttp.DigestSHA) + "="
+	for _, part := range strings.Split(digest, ",") {
+		part = strings.TrimSpace(part)
+		if strings.HasPrefix(part, prefix) {
+			return strings.TrimPrefix(part, prefix)
+		}
+	}
+	return ""
+}
+
+// verifyingReader checks the data read against the expected SHA-1
+// checksum when the end of the data is reached.
+type verifyingReader struct {
+	io.ReadCloser
+	hasher   hash.Hash
+	expected string
+}
+
+// Read implements io.Reader.
+func (v *verifyingReader) Read(p []byte) (int, error) {
+	n, err := v.ReadCloser.Read(p)
+	v.hasher.Write(p[:n])
+	if err == io.EOF {
+		checksum := base64.StdEncoding.EncodeToString(v.hasher.Sum(nil))
+		if checksum != v.expected {
+			return n, errors.Errorf("downloaded archive checksum mismatch: expected %q, got %q", v.expected, checksum)
+		}
+	}
+	return n, err
 }
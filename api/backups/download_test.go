@@ -54,3 +54,31 @@ func (s *downloadSuite) TestErrorRequest(c *gc.C) {
 	c.Check(errors.Cause(err), gc.FitsTypeOf, &params.Error{})
 	c.Check(err, gc.ErrorMatches, "something went wrong!")
 }
+
+func (s *downloadSuite) setDigest(checksum string) {
+	s.FakeClient.Response.Header.Set("Digest", string(apiserverhttp.DigestSHA)+"="+checksum)
+}
+
+func (s *downloadSuite) TestChecksumVerified(c *gc.C) {
+	s.setSuccess(c, "<compressed archive data>")
+	// The base64-encoded SHA-1 of the data.
+	s.setDigest("YuLeCCL75ZT/frYSABmKhamUh58=")
+
+	resultArchive, err := s.client.Download("spam")
+	c.Assert(err, jc.ErrorIsNil)
+
+	resultData, err := ioutil.ReadAll(resultArchive)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(resultData), gc.Equals, "<compressed archive data>")
+}
+
+func (s *downloadSuite) TestChecksumMismatch(c *gc.C) {
+	s.setSuccess(c, "<compressed archive data>")
+	s.setDigest("<bogus checksum>")
+
+	resultArchive, err := s.client.Download("spam")
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = ioutil.ReadAll(resultArchive)
+	c.Check(err, gc.ErrorMatches, `downloaded archive checksum mismatch: expected "<bogus checksum>", got ".*"`)
+}
@@ -0,0 +1,43 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// PrepareRestore puts the API server into restore mode, in which it
+// refuses all requests except Restore.
+func (c *Client) PrepareRestore() error {
+	if err := c.facade.FacadeCall("PrepareRestore", nil, nil); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// Restore replaces the state server's state and configuration with the
+// ones in the identified backup, which is decrypted with the key if it
+// is encrypted. The API server restarts once done, so the client must
+// reconnect before calling FinishRestore.
+func (c *Client) Restore(id string, key []byte) error {
+	args := params.RestoreArgs{
+		BackupId:      id,
+		EncryptionKey: key,
+	}
+	if err := c.facade.FacadeCall("Restore", args, nil); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// FinishRestore tells the restarted API server that the restore was
+// checked by the client.
+func (c *Client) FinishRestore() error {
+	if err := c.facade.FacadeCall("FinishRestore", nil, nil); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/backups"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type restoreSuite struct {
+	backupsSuite
+}
+
+var _ = gc.Suite(&restoreSuite{})
+
+func (s *restoreSuite) TestPrepareRestore(c *gc.C) {
+	cleanup := backups.PatchClientFacadeCall(s.client,
+		func(req string, paramsIn interface{}, resp interface{}) error {
+			c.Check(req, gc.Equals, "PrepareRestore")
+			c.Check(paramsIn, gc.IsNil)
+			c.Check(resp, gc.IsNil)
+			return nil
+		},
+	)
+	defer cleanup()
+
+	err := s.client.PrepareRestore()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *restoreSuite) TestRestore(c *gc.C) {
+	cleanup := backups.PatchClientFacadeCall(s.client,
+		func(req string, paramsIn interface{}, resp interface{}) error {
+			c.Check(req, gc.Equals, "Restore")
+
+			c.Assert(paramsIn, gc.FitsTypeOf, params.RestoreArgs{})
+			c.Check(paramsIn, jc.DeepEquals, params.RestoreArgs{
+				BackupId:      "spam",
+				EncryptionKey: []byte("some secret key!"),
+			})
+
+			c.Check(resp, gc.IsNil)
+			return nil
+		},
+	)
+	defer cleanup()
+
+	err := s.client.Restore("spam", []byte("some secret key!"))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *restoreSuite) TestFinishRestore(c *gc.C) {
+	cleanup := backups.PatchClientFacadeCall(s.client,
+		func(req string, paramsIn interface{}, resp interface{}) error {
+			c.Check(req, gc.Equals, "FinishRestore")
+			c.Check(paramsIn, gc.IsNil)
+			c.Check(resp, gc.IsNil)
+			return nil
+		},
+	)
+	defer cleanup()
+
+	err := s.client.FinishRestore()
+	c.Assert(err, jc.ErrorIsNil)
+}
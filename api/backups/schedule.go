@@ -0,0 +1,29 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Schedule returns the backups schedule and retention policy of the
+// environment, along with the outcome of the last scheduled backup.
+func (c *Client) Schedule() (*params.BackupsScheduleResult, error) {
+	var result params.BackupsScheduleResult
+	if err := c.facade.FacadeCall("Schedule", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &result, nil
+}
+
+// SetSchedule changes the backups schedule and retention policy of
+// the environment.
+func (c *Client) SetSchedule(args params.BackupsSetScheduleArgs) error {
+	if err := c.facade.FacadeCall("SetSchedule", args, nil); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
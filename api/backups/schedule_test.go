@@ -0,0 +1,63 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/backups"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type scheduleSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&scheduleSuite{})
+
+func (s *scheduleSuite) TestSchedule(c *gc.C) {
+	cleanup := backups.PatchClientFacadeCall(s.client,
+		func(req string, paramsIn interface{}, resp interface{}) error {
+			c.Check(req, gc.Equals, "Schedule")
+
+			c.Check(paramsIn, gc.IsNil)
+
+			if result, ok := resp.(*params.BackupsScheduleResult); ok {
+				result.Schedule = "@daily"
+				result.KeepLast = 3
+			} else {
+				c.Fatalf("wrong output structure")
+			}
+			return nil
+		},
+	)
+	defer cleanup()
+
+	result, err := s.client.Schedule()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result, jc.DeepEquals, &params.BackupsScheduleResult{
+		Schedule: "@daily",
+		KeepLast: 3,
+	})
+}
+
+func (s *scheduleSuite) TestSetSchedule(c *gc.C) {
+	schedule := "@weekly"
+	args := params.BackupsSetScheduleArgs{Schedule: &schedule}
+	cleanup := backups.PatchClientFacadeCall(s.client,
+		func(req string, paramsIn interface{}, resp interface{}) error {
+			c.Check(req, gc.Equals, "SetSchedule")
+
+			c.Check(paramsIn, jc.DeepEquals, args)
+
+			c.Check(resp, gc.IsNil)
+			return nil
+		},
+	)
+	defer cleanup()
+
+	err := s.client.SetSchedule(args)
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/storage"
 	"github.com/juju/juju/tools"
 	"github.com/juju/juju/version"
 )
@@ -81,6 +82,10 @@ type MachineStatus struct {
 	Jobs          []multiwatcher.MachineJob
 	HasVote       bool
 	WantsVote     bool
+
+	// AvailabilityZone holds the availability zone the machine's
+	// instance was started in, if known.
+	AvailabilityZone string
 }
 
 // ServiceStatus holds status info about a service.
@@ -107,6 +112,11 @@ type UnitStatus struct {
 	Life           string
 	Err            error
 
+	// WorkloadStatus and WorkloadStatusInfo hold the status of the
+	// unit's workload, as reported by its charm.
+	WorkloadStatus     params.Status
+	WorkloadStatusInfo string
+
 	Machine       string
 	OpenedPorts   []string
 	PublicAddress string
@@ -150,6 +160,10 @@ type Status struct {
 	Services        map[string]ServiceStatus
 	Networks        map[string]NetworkStatus
 	Relations       []RelationStatus
+
+	// StateServersDegraded reports whether the state servers'
+	// voting quorum depends on a single availability zone.
+	StateServersDegraded bool
 }
 
 // Status returns the status of the juju environment.
@@ -162,6 +176,78 @@ func (c *Client) Status(patterns []string) (*Status, error) {
 	return &result, nil
 }
 
+// StatusHistory returns the recorded status transitions of the given
+// unit or machine, newest first. At most size entries are returned
+// when size is positive; if before is not zero, only entries recorded
+// before that time are returned.
+func (c *Client) StatusHistory(tag names.Tag, size int, before time.Time) ([]params.StatusHistoryEntry, error) {
+	var result params.StatusHistoryResults
+	p := params.StatusHistory{Tag: tag.String(), Size: size}
+	if !before.IsZero() {
+		p.Before = &before
+	}
+	if err := c.facade.FacadeCall("StatusHistory", p, &result); err != nil {
+		return nil, err
+	}
+	return result.Statuses, nil
+}
+
+// ListStorage returns the details of all storage instances in the
+// environment.
+func (c *Client) ListStorage() ([]params.StorageInstance, error) {
+	var result params.StorageInstances
+	if err := c.facade.FacadeCall("ListStorage", nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Instances, nil
+}
+
+// ShowStorage returns the details of the storage instances with the
+// given ids.
+func (c *Client) ShowStorage(ids ...string) ([]params.StorageInstanceResult, error) {
+	var results params.StorageInstanceResults
+	args := params.StorageInstanceIds{Ids: ids}
+	if err := c.facade.FacadeCall("ShowStorage", args, &results); err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(ids) {
+		return nil, errors.Errorf("expected %d results, got %d", len(ids), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+// AddSubnet adds the subnet described by args to juju, as part of the
+// space named in args.Space if it is not empty.
+func (c *Client) AddSubnet(args params.AddSubnet) error {
+	return c.facade.FacadeCall("AddSubnet", args, nil)
+}
+
+// ListSubnets returns the details of all subnets known to juju.
+func (c *Client) ListSubnets() ([]params.Subnet, error) {
+	var result params.Subnets
+	if err := c.facade.FacadeCall("ListSubnets", nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Subnets, nil
+}
+
+// CreateSpace creates a space with the given name, holding the subnets
+// with the given CIDRs.
+func (c *Client) CreateSpace(name string, subnets ...string) error {
+	args := params.CreateSpace{Name: name, Subnets: subnets}
+	return c.facade.FacadeCall("CreateSpace", args, nil)
+}
+
+// ListSpaces returns all spaces, along with the CIDRs of the subnets
+// that belong to each.
+func (c *Client) ListSpaces() ([]params.Space, error) {
+	var result params.Spaces
+	if err := c.facade.FacadeCall("ListSpaces", nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Spaces, nil
+}
+
 // LegacyMachineStatus holds just the instance-id of a machine.
 type LegacyMachineStatus struct {
 	InstanceId string // Not type instance.Id just to match original api.
@@ -330,9 +416,10 @@ func (c *Client) ForceDestroyMachines(machines ...string) error {
 }
 
 // ServiceExpose changes the juju-managed firewall to expose any ports that
-// were also explicitly marked by units as open.
-func (c *Client) ServiceExpose(service string) error {
-	params := params.ServiceExpose{ServiceName: service}
+// were also explicitly marked by units as open. If any source CIDRs are
+// given, the ports are only accessible from addresses within them.
+func (c *Client) ServiceExpose(service string, sourceCIDRs ...string) error {
+	params := params.ServiceExpose{ServiceName: service, SourceCIDRs: sourceCIDRs}
 	return c.facade.FacadeCall("ServiceExpose", params, nil)
 }
 
@@ -360,6 +447,23 @@ func (c *Client) ServiceDeployWithNetworks(charmURL string, serviceName string,
 	return c.facade.FacadeCall("ServiceDeployWithNetworks", params, nil)
 }
 
+// ServiceDeployWithStorage works exactly like ServiceDeployWithNetworks,
+// but also creates storage instances for each unit of the service
+// according to the given storage directives.
+func (c *Client) ServiceDeployWithStorage(charmURL string, serviceName string, numUnits int, configYAML string, cons constraints.Value, toMachineSpec string, networks []string, directives []storage.Directive) error {
+	params := params.ServiceDeploy{
+		ServiceName:   serviceName,
+		CharmUrl:      charmURL,
+		NumUnits:      numUnits,
+		ConfigYAML:    configYAML,
+		Constraints:   cons,
+		ToMachineSpec: toMachineSpec,
+		Networks:      networks,
+		Storage:       directives,
+	}
+	return c.facade.FacadeCall("ServiceDeployWithStorage", params, nil)
+}
+
 // ServiceDeploy obtains the charm, either locally or from the charm store,
 // and deploys it.
 func (c *Client) ServiceDeploy(charmURL string, serviceName string, numUnits int, configYAML string, cons constraints.Value, toMachineSpec string) error {
@@ -544,6 +648,35 @@ func (c *Client) UnshareEnvironment(users []names.UserTag) error {
 	return result.Combine()
 }
 
+// GrantEnvironmentAccess gives the user the given level of access to
+// the environment. Access is one of "read", "write" or "admin".
+func (c *Client) GrantEnvironmentAccess(user names.UserTag, access string) error {
+	return c.modifyEnvironmentAccess(user, params.GrantEnvAccess, access)
+}
+
+// RevokeEnvironmentAccess takes away the given level of access to the
+// environment from the user, leaving them with the level below it.
+// Revoking read access removes the user from the environment.
+func (c *Client) RevokeEnvironmentAccess(user names.UserTag, access string) error {
+	return c.modifyEnvironmentAccess(user, params.RevokeEnvAccess, access)
+}
+
+func (c *Client) modifyEnvironmentAccess(user names.UserTag, action params.EnvironAccessAction, access string) error {
+	args := params.ModifyEnvironAccess{
+		Changes: []params.ModifyEnvironUserAccess{{
+			UserTag: user.String(),
+			Action:  action,
+			Access:  access,
+		}},
+	}
+	var result params.ErrorResults
+	err := c.facade.FacadeCall("ModifyEnvironmentAccess", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return result.OneError()
+}
+
 // WatchAll holds the id of the newly-created AllWatcher.
 type WatchAll struct {
 	AllWatcherId string
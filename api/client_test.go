@@ -203,6 +203,31 @@ func (s *clientSuite) TestUnshareEnvironmentRealAPIServer(c *gc.C) {
 	c.Assert(errors.IsNotFound(err), jc.IsTrue)
 }
 
+func (s *clientSuite) TestGrantEnvironmentAccessRealAPIServer(c *gc.C) {
+	client := s.APIState.Client()
+	user := s.Factory.MakeUser(c, &factory.UserParams{NoEnvUser: true})
+	err := client.GrantEnvironmentAccess(user.UserTag(), "read")
+	c.Assert(err, jc.ErrorIsNil)
+
+	envUser, err := s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentReadAccess)
+
+	err = client.GrantEnvironmentAccess(user.UserTag(), "read")
+	c.Assert(err, gc.ErrorMatches, `could not grant access: user ".*" already has "read" access`)
+}
+
+func (s *clientSuite) TestRevokeEnvironmentAccessRealAPIServer(c *gc.C) {
+	client := s.APIState.Client()
+	user := s.Factory.MakeUser(c, &factory.UserParams{Access: state.EnvironmentAdminAccess})
+	err := client.RevokeEnvironmentAccess(user.UserTag(), "admin")
+	c.Assert(err, jc.ErrorIsNil)
+
+	envUser, err := s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentWriteAccess)
+}
+
 func (s *clientSuite) TestWatchDebugLogConnected(c *gc.C) {
 	// Shows both the unmarshalling of a real error, and
 	// that the api server is connected.
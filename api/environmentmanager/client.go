@@ -0,0 +1,56 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environmentmanager
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+var logger = loggo.GetLogger("juju.api.environmentmanager")
+
+// Client provides methods that the Juju client command uses to interact
+// with the environments hosted by the Juju Server.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new `Client` based on an existing authenticated API
+// connection.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "EnvironmentManager")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// CreateEnvironment creates a new environment owned by the given user,
+// using the state server environment's configuration for any attributes
+// not given in config.
+func (c *Client) CreateEnvironment(owner names.UserTag, config map[string]interface{}) (params.Environment, error) {
+	var result params.Environment
+	args := params.EnvironmentCreateArgs{
+		OwnerTag: owner.String(),
+		Config:   config,
+	}
+	if err := c.facade.FacadeCall("CreateEnvironment", args, &result); err != nil {
+		return result, errors.Trace(err)
+	}
+	logger.Infof("created environment %q (%s)", result.Name, result.UUID)
+	return result, nil
+}
+
+// ListEnvironments returns the environments that the specified user has
+// access to.
+func (c *Client) ListEnvironments(user names.UserTag) ([]params.Environment, error) {
+	var result params.EnvironmentList
+	err := c.facade.FacadeCall("ListEnvironments", params.Entity{user.String()}, &result)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Environments, nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environmentmanager_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/environmentmanager"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type environmentmanagerSuite struct {
+	jujutesting.JujuConnSuite
+
+	envmanager *environmentmanager.Client
+}
+
+var _ = gc.Suite(&environmentmanagerSuite{})
+
+func (s *environmentmanagerSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.envmanager = environmentmanager.NewClient(s.APIState)
+	c.Assert(s.envmanager, gc.NotNil)
+}
+
+func (s *environmentmanagerSuite) TestCreateEnvironment(c *gc.C) {
+	owner := s.AdminUserTag(c)
+	env, err := s.envmanager.CreateEnvironment(owner, map[string]interface{}{
+		"name":         "new-env",
+		"state-server": false,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env.Name, gc.Equals, "new-env")
+	c.Assert(env.OwnerTag, gc.Equals, owner.String())
+	c.Assert(env.ServerUUID, gc.Equals, s.State.EnvironUUID())
+
+	stateEnv, err := s.State.GetEnvironment(names.NewEnvironTag(env.UUID))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stateEnv.Name(), gc.Equals, "new-env")
+}
+
+func (s *environmentmanagerSuite) TestCreateEnvironmentError(c *gc.C) {
+	_, err := s.envmanager.CreateEnvironment(s.AdminUserTag(c), map[string]interface{}{
+		"name": "new-env",
+		"type": "ec2",
+	})
+	c.Assert(err, gc.ErrorMatches, `specified type "ec2" does not match the state server's "dummy"`)
+}
+
+func (s *environmentmanagerSuite) TestListEnvironments(c *gc.C) {
+	owner := names.NewLocalUserTag("alex")
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "alex", NoEnvUser: true})
+	st := s.Factory.MakeEnvironment(c, &factory.EnvParams{Name: "first", Owner: owner})
+	defer st.Close()
+
+	envs, err := s.envmanager.ListEnvironments(owner)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envs, gc.HasLen, 1)
+	c.Assert(envs[0].Name, gc.Equals, "first")
+	c.Assert(envs[0].UUID, gc.Equals, st.EnvironUUID())
+	c.Assert(envs[0].OwnerTag, gc.Equals, owner.String())
+}
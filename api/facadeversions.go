@@ -11,6 +11,7 @@ package api
 var facadeVersions = map[string]int{
 	"Agent":                1,
 	"AllWatcher":           0,
+	"AuditLog":             0,
 	"Backups":              0,
 	"Deployer":             0,
 	"DiskManager":          1,
@@ -20,8 +21,10 @@ var facadeVersions = map[string]int{
 	"Networker":            0,
 	"StringsWatcher":       0,
 	"Environment":          0,
+	"EnvironmentManager":   1,
 	"KeyManager":           0,
 	"Logger":               0,
+	"LogSink":              1,
 	"MetricsManager":       0,
 	"Pinger":               0,
 	"Provisioner":          0,
@@ -34,9 +37,10 @@ var facadeVersions = map[string]int{
 	"Upgrader":             0,
 	"Firewaller":           1,
 	"Rsyslog":              0,
-	"Uniter":               1,
+	"Uniter":               2,
 	"Action":               0,
 	"Service":              1,
+	"StorageProvisioner":   1,
 }
 
 // bestVersion tries to find the newest version in the version list that we can
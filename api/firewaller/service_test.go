@@ -94,3 +94,19 @@ func (s *serviceSuite) TestIsExposed(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(isExposed, jc.IsFalse)
 }
+
+func (s *serviceSuite) TestExposedSourceCIDRs(c *gc.C) {
+	err := s.service.SetExposed("203.0.113.4/32", "10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+
+	sourceCIDRs, err := s.apiService.ExposedSourceCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sourceCIDRs, jc.DeepEquals, []string{"10.0.0.0/8", "203.0.113.4/32"})
+
+	err = s.service.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+
+	sourceCIDRs, err = s.apiService.ExposedSourceCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sourceCIDRs, gc.HasLen, 0)
+}
@@ -0,0 +1,28 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logsink
+
+import (
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+const logSinkFacade = "LogSink"
+
+// State provides access to the LogSink API facade.
+type State struct {
+	facade base.FacadeCaller
+}
+
+// NewState creates a new client-side LogSink facade.
+func NewState(caller base.APICaller) *State {
+	return &State{facade: base.NewFacadeCaller(caller, logSinkFacade)}
+}
+
+// WriteLogs sends the given log records to be stored in state. The
+// records are recorded as logged by the authenticated agent.
+func (st *State) WriteLogs(records []params.LogRecord) error {
+	args := params.LogRecords{Records: records}
+	return st.facade.FacadeCall("WriteLogs", args, nil)
+}
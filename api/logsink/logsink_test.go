@@ -0,0 +1,58 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logsink_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/logsink"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&LogSinkSuite{})
+
+type LogSinkSuite struct {
+	coretesting.BaseSuite
+}
+
+func (s *LogSinkSuite) TestWriteLogs(c *gc.C) {
+	records := []params.LogRecord{{
+		Time:     time.Date(2014, 11, 5, 10, 0, 0, 0, time.UTC),
+		Module:   "juju.worker",
+		Location: "worker.go:42",
+		Level:    loggo.INFO,
+		Message:  "started",
+	}}
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "LogSink")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "WriteLogs")
+		c.Check(arg, jc.DeepEquals, params.LogRecords{Records: records})
+		c.Check(result, gc.IsNil)
+		callCount++
+		return nil
+	})
+
+	st := logsink.NewState(apiCaller)
+	err := st.WriteLogs(records)
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *LogSinkSuite) TestWriteLogsError(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return errors.New("boom")
+	})
+	st := logsink.NewState(apiCaller)
+	err := st.WriteLogs(nil)
+	c.Check(err, gc.ErrorMatches, "boom")
+}
@@ -19,11 +19,13 @@ import (
 	"github.com/juju/juju/api/firewaller"
 	"github.com/juju/juju/api/keyupdater"
 	apilogger "github.com/juju/juju/api/logger"
+	"github.com/juju/juju/api/logsink"
 	"github.com/juju/juju/api/machiner"
 	"github.com/juju/juju/api/networker"
 	"github.com/juju/juju/api/provisioner"
 	"github.com/juju/juju/api/reboot"
 	"github.com/juju/juju/api/rsyslog"
+	"github.com/juju/juju/api/storageprovisioner"
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/api/upgrader"
 	"github.com/juju/juju/apiserver/params"
@@ -220,6 +222,16 @@ func (st *State) DiskManager() (*diskmanager.State, error) {
 	return diskmanager.NewState(st, machineTag), nil
 }
 
+// StorageProvisioner returns a version of the state that provides
+// functionality required by the storageprovisioner worker.
+func (st *State) StorageProvisioner() (*storageprovisioner.State, error) {
+	machineTag, ok := st.authTag.(names.MachineTag)
+	if !ok {
+		return nil, errors.Errorf("expected MachineTag, got %#v", st.authTag)
+	}
+	return storageprovisioner.NewState(st, machineTag), nil
+}
+
 // Firewaller returns a version of the state that provides functionality
 // required by the firewaller worker.
 func (st *State) Firewaller() *firewaller.State {
@@ -262,6 +274,11 @@ func (st *State) Logger() *apilogger.State {
 	return apilogger.NewState(st)
 }
 
+// LogSink returns access to the LogSink API
+func (st *State) LogSink() *logsink.State {
+	return logsink.NewState(st)
+}
+
 // KeyUpdater returns access to the KeyUpdater API
 func (st *State) KeyUpdater() *keyupdater.State {
 	return keyupdater.NewState(st)
@@ -0,0 +1,61 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+const storageProvisionerFacade = "StorageProvisioner"
+
+// State provides access to a storageprovisioner worker's view of the state.
+type State struct {
+	facade base.FacadeCaller
+	tag    names.MachineTag
+}
+
+// NewState creates a new client-side StorageProvisioner facade.
+func NewState(caller base.APICaller, authTag names.MachineTag) *State {
+	return &State{
+		base.NewFacadeCaller(caller, storageProvisionerFacade),
+		authTag,
+	}
+}
+
+// StorageInstances returns the storage instances owned by the units
+// assigned to the machine identified by the authenticated machine tag.
+func (st *State) StorageInstances() ([]params.StorageInstance, error) {
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: st.tag.String()}},
+	}
+	var results params.StorageInstancesResults
+	err := st.facade.FacadeCall("StorageInstances", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Result, nil
+}
+
+// SetProvisioned records the location and filesystem of each of the
+// given storage instances.
+func (st *State) SetProvisioned(instances ...params.StorageProvisioned) error {
+	args := params.SetStorageProvisioned{Instances: instances}
+	var results params.ErrorResults
+	err := st.facade.FacadeCall("SetProvisioned", args, &results)
+	if err != nil {
+		return err
+	}
+	return results.Combine()
+}
@@ -0,0 +1,106 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner_test
+
+import (
+	"errors"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/storageprovisioner"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/storage"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&StorageProvisionerSuite{})
+
+type StorageProvisionerSuite struct {
+	coretesting.BaseSuite
+}
+
+func (s *StorageProvisionerSuite) TestStorageInstances(c *gc.C) {
+	instances := []params.StorageInstance{{
+		Id:     "data/0",
+		Name:   "data",
+		Owner:  "postgresql/0",
+		Source: "loop",
+		Size:   1024,
+	}}
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "StorageInstances")
+		c.Check(arg, gc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: "machine-123"}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.StorageInstancesResults{})
+		*(result.(*params.StorageInstancesResults)) = params.StorageInstancesResults{
+			Results: []params.StorageInstancesResult{{Result: instances}},
+		}
+		callCount++
+		return nil
+	})
+
+	st := storageprovisioner.NewState(apiCaller, names.NewMachineTag("123"))
+	result, err := st.StorageInstances()
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(result, jc.DeepEquals, instances)
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *StorageProvisionerSuite) TestStorageInstancesResultError(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.StorageInstancesResults)) = params.StorageInstancesResults{
+			Results: []params.StorageInstancesResult{{
+				Error: &params.Error{Message: "permission denied", Code: params.CodeUnauthorized},
+			}},
+		}
+		return nil
+	})
+	st := storageprovisioner.NewState(apiCaller, names.NewMachineTag("123"))
+	_, err := st.StorageInstances()
+	c.Check(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *StorageProvisionerSuite) TestSetProvisioned(c *gc.C) {
+	provisioned := params.StorageProvisioned{
+		Id:         "data/0",
+		Location:   "/var/lib/juju/storage/data/0",
+		Filesystem: storage.Filesystem{Type: "ext4"},
+	}
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(request, gc.Equals, "SetProvisioned")
+		c.Check(arg, gc.DeepEquals, params.SetStorageProvisioned{
+			Instances: []params.StorageProvisioned{provisioned},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: nil}},
+		}
+		callCount++
+		return nil
+	})
+
+	st := storageprovisioner.NewState(apiCaller, names.NewMachineTag("123"))
+	err := st.SetProvisioned(provisioned)
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *StorageProvisionerSuite) TestSetProvisionedError(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return errors.New("boom")
+	})
+	st := storageprovisioner.NewState(apiCaller, names.NewMachineTag("123"))
+	err := st.SetProvisioned(params.StorageProvisioned{Id: "data/0"})
+	c.Check(err, gc.ErrorMatches, "boom")
+}
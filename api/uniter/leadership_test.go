@@ -0,0 +1,113 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/uniter"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/leadership"
+	"github.com/juju/juju/lease"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type leadershipSuite struct {
+	uniterSuite
+
+	apiUnit    *uniter.Unit
+	apiService *uniter.Service
+}
+
+var _ = gc.Suite(&leadershipSuite{})
+
+func (s *leadershipSuite) SetUpTest(c *gc.C) {
+	s.uniterSuite.SetUpTest(c)
+
+	var err error
+	s.apiUnit, err = s.uniter.Unit(s.wordpressUnit.Tag().(names.UnitTag))
+	c.Assert(err, jc.ErrorIsNil)
+	s.apiService, err = s.apiUnit.Service()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *leadershipSuite) TestIsLeader(c *gc.C) {
+	isLeader, err := s.apiUnit.IsLeader()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(isLeader, jc.IsTrue)
+}
+
+func (s *leadershipSuite) TestIsLeaderOtherUnitLeads(c *gc.C) {
+	s.claimLeadership(c, "wordpress/1")
+
+	isLeader, err := s.apiUnit.IsLeader()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(isLeader, jc.IsFalse)
+}
+
+func (s *leadershipSuite) TestLeaderSettings(c *gc.C) {
+	settings, err := s.apiService.LeaderSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.HasLen, 0)
+
+	err = s.apiUnit.MergeLeaderSettings(map[string]string{"foo": "bar", "baz": "qux"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.apiUnit.MergeLeaderSettings(map[string]string{"baz": ""})
+	c.Assert(err, jc.ErrorIsNil)
+
+	settings, err = s.apiService.LeaderSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, map[string]string{"foo": "bar"})
+}
+
+func (s *leadershipSuite) TestMergeLeaderSettingsNotLeader(c *gc.C) {
+	s.claimLeadership(c, "wordpress/1")
+
+	err := s.apiUnit.MergeLeaderSettings(map[string]string{"foo": "bar"})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(params.IsCodeUnauthorized(err), jc.IsTrue)
+}
+
+func (s *leadershipSuite) TestWatchLeaderSettings(c *gc.C) {
+	w, err := s.apiService.WatchLeaderSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.BackingState, w)
+
+	// Initial event.
+	wc.AssertOneChange()
+
+	err = s.wordpressService.MergeLeaderSettings(map[string]string{"foo": "bar"})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
+func (s *leadershipSuite) TestV1NotImplemented(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV1)
+	apiUnit, err := s.uniter.Unit(s.wordpressUnit.Tag().(names.UnitTag))
+	c.Assert(err, jc.ErrorIsNil)
+	apiService, err := apiUnit.Service()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = apiUnit.IsLeader()
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	err = apiUnit.MergeLeaderSettings(map[string]string{"foo": "bar"})
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	_, err = apiService.LeaderSettings()
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	_, err = apiService.WatchLeaderSettings()
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
+
+func (s *leadershipSuite) claimLeadership(c *gc.C, unitName string) {
+	manager := leadership.NewLeadershipManager(lease.Manager())
+	_, err := manager.ClaimLeadership(s.wordpressService.Name(), unitName)
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -6,6 +6,7 @@ package uniter
 import (
 	"fmt"
 
+	"github.com/juju/errors"
 	"github.com/juju/names"
 	"gopkg.in/juju/charm.v4"
 
@@ -157,3 +158,53 @@ func (s *Service) ownerTag() (names.UserTag, error) {
 	}
 	return names.ParseUserTag(result.Result)
 }
+
+// LeaderSettings returns the settings written by the service's leader.
+func (s *Service) LeaderSettings() (map[string]string, error) {
+	if s.st.BestAPIVersion() < 2 {
+		// LeaderSettings() was introduced in UniterAPIV2.
+		return nil, errors.NotImplementedf("service.LeaderSettings() (need V2+)")
+	}
+	var results params.LeaderSettingsResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.tag.String()}},
+	}
+	err := s.st.facade.FacadeCall("LeaderSettings", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Settings, nil
+}
+
+// WatchLeaderSettings returns a watcher that notifies when the
+// service's leader settings change.
+func (s *Service) WatchLeaderSettings() (watcher.NotifyWatcher, error) {
+	if s.st.BestAPIVersion() < 2 {
+		// WatchLeaderSettings() was introduced in UniterAPIV2.
+		return nil, errors.NotImplementedf("service.WatchLeaderSettings() (need V2+)")
+	}
+	var results params.NotifyWatchResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.tag.String()}},
+	}
+	err := s.st.facade.FacadeCall("WatchLeaderSettings", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	w := watcher.NewNotifyWatcher(s.st.facade.RawAPICaller(), result)
+	return w, nil
+}
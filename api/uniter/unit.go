@@ -644,3 +644,94 @@ func (u *Unit) WatchMeterStatus() (watcher.NotifyWatcher, error) {
 	w := watcher.NewNotifyWatcher(u.st.facade.RawAPICaller(), result)
 	return w, nil
 }
+
+// IsLeader reports whether the unit is the leader of its service.
+func (u *Unit) IsLeader() (bool, error) {
+	if u.st.BestAPIVersion() < 2 {
+		// IsLeader() was introduced in UniterAPIV2.
+		return false, errors.NotImplementedf("unit.IsLeader() (need V2+)")
+	}
+	var results params.BoolResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("IsLeader", args, &results)
+	if err != nil {
+		return false, err
+	}
+	if len(results.Results) != 1 {
+		return false, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.Result, nil
+}
+
+// MergeLeaderSettings merges the given settings into the leader
+// settings of the unit's service. Keys with empty values are removed.
+// The call fails unless the unit is its service's leader.
+func (u *Unit) MergeLeaderSettings(settings map[string]string) error {
+	if u.st.BestAPIVersion() < 2 {
+		// MergeLeaderSettings() was introduced in UniterAPIV2.
+		return errors.NotImplementedf("unit.MergeLeaderSettings() (need V2+)")
+	}
+	var results params.ErrorResults
+	args := params.MergeLeaderSettingsParams{
+		Params: []params.MergeLeaderSettingsParam{{
+			Unit:     u.tag.String(),
+			Settings: settings,
+		}},
+	}
+	err := u.st.facade.FacadeCall("MergeLeaderSettings", args, &results)
+	if err != nil {
+		return err
+	}
+	return results.OneError()
+}
+
+// SetWorkloadStatus sets the status of the unit's workload, along with
+// an optional message for the operator.
+func (u *Unit) SetWorkloadStatus(status params.Status, info string) error {
+	if u.st.BestAPIVersion() < 2 {
+		// SetWorkloadStatus() was introduced in UniterAPIV2.
+		return errors.NotImplementedf("unit.SetWorkloadStatus() (need V2+)")
+	}
+	var result params.ErrorResults
+	args := params.SetStatus{
+		Entities: []params.EntityStatus{
+			{Tag: u.tag.String(), Status: status, Info: info},
+		},
+	}
+	err := u.st.facade.FacadeCall("SetWorkloadStatus", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
+// WorkloadStatus returns the status of the unit's workload, as last
+// reported by its charm, along with its message.
+func (u *Unit) WorkloadStatus() (params.Status, string, error) {
+	if u.st.BestAPIVersion() < 2 {
+		// WorkloadStatus() was introduced in UniterAPIV2.
+		return "", "", errors.NotImplementedf("unit.WorkloadStatus() (need V2+)")
+	}
+	var results params.StatusResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("WorkloadStatus", args, &results)
+	if err != nil {
+		return "", "", err
+	}
+	if len(results.Results) != 1 {
+		return "", "", fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return "", "", result.Error
+	}
+	return result.Status, result.Info, nil
+}
@@ -71,6 +71,36 @@ func (s *unitSuite) TestSetStatus(c *gc.C) {
 	c.Assert(data, gc.HasLen, 0)
 }
 
+func (s *unitSuite) TestWorkloadStatus(c *gc.C) {
+	status, info, err := s.apiUnit.WorkloadStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, params.StatusUnknown)
+	c.Assert(info, gc.Equals, "")
+
+	err = s.apiUnit.SetWorkloadStatus(params.StatusBlocked, "need a database")
+	c.Assert(err, jc.ErrorIsNil)
+
+	status, info, err = s.apiUnit.WorkloadStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, params.StatusBlocked)
+	c.Assert(info, gc.Equals, "need a database")
+
+	stateStatus, _, err := s.wordpressUnit.WorkloadStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stateStatus, gc.Equals, state.StatusBlocked)
+}
+
+func (s *unitSuite) TestWorkloadStatusV1NotImplemented(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV1)
+	apiUnit, err := s.uniter.Unit(s.wordpressUnit.Tag().(names.UnitTag))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = apiUnit.SetWorkloadStatus(params.StatusActive, "")
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	_, _, err = apiUnit.WorkloadStatus()
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
+
 func (s *unitSuite) TestEnsureDead(c *gc.C) {
 	c.Assert(s.wordpressUnit.Life(), gc.Equals, state.Alive)
 
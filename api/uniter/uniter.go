@@ -58,9 +58,14 @@ func newStateV1(caller base.APICaller, authTag names.UnitTag) *State {
 	return newStateForVersion(caller, authTag, 1)
 }
 
+// newStateV2 creates a new client-side Uniter facade, version 2.
+func newStateV2(caller base.APICaller, authTag names.UnitTag) *State {
+	return newStateForVersion(caller, authTag, 2)
+}
+
 // NewState creates a new client-side Uniter facade.
 // Defined like this to allow patching during tests.
-var NewState = newStateV1
+var NewState = newStateV2
 
 // BestAPIVersion returns the API version that we were able to
 // determine is supported by both the client and the API Server.
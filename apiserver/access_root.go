@@ -0,0 +1,132 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+	"github.com/juju/juju/state"
+)
+
+// accessRoot restricts API calls to those permitted by the level of
+// access the logged in user has to the environment.
+type accessRoot struct {
+	rpc.MethodFinder
+	access state.EnvironmentAccess
+}
+
+// newAccessRoot returns a new accessRoot.
+func newAccessRoot(finder rpc.MethodFinder, access state.EnvironmentAccess) *accessRoot {
+	return &accessRoot{finder, access}
+}
+
+// readAccessMethods lists, by facade, the methods that only inspect
+// the environment and so may be called by users with read access.
+var readAccessMethods = map[string]set.Strings{
+	"Client": set.NewStrings(
+		"APIHostPorts",
+		"AgentVersion",
+		"CharmInfo",
+		"EnvironmentGet",
+		"EnvironmentInfo",
+		"FindTools",
+		"FullStatus",
+		"GetAnnotations",
+		"GetEnvironmentConstraints",
+		"GetServiceConstraints",
+		"ListSpaces",
+		"ListStorage",
+		"ListSubnets",
+		"PrivateAddress",
+		"PublicAddress",
+		"ResolveCharms",
+		"ServiceCharmRelations",
+		"ServiceGet",
+		"ServiceGetCharmURL",
+		"ShowStorage",
+		"Status",
+		"StatusHistory",
+		"WatchAll",
+	),
+	"Action": set.NewStrings(
+		"Actions",
+		"FindActionTagsByPrefix",
+		"ListAll",
+		"ListCompleted",
+		"ListPending",
+		"ServicesCharmActions",
+	),
+	"AllWatcher":  set.NewStrings("Next", "Stop"),
+	"KeyManager":  set.NewStrings("ListKeys"),
+	"Pinger":      set.NewStrings("Ping", "Stop"),
+	"UserManager": set.NewStrings("SetPassword", "UserInfo"),
+	// The environment manager checks its own permissions. Creating an
+	// environment needs more than read access, as the new environment
+	// is derived from the configuration of the state server.
+	"EnvironmentManager": set.NewStrings("ListEnvironments"),
+}
+
+// adminAccessFacades lists the facades that may only be used by users
+// with admin access.
+var adminAccessFacades = set.NewStrings(
+	"AuditLog",
+	"Backups",
+	"HighAvailability",
+)
+
+// adminAccessMethods lists, by facade, the methods that change the
+// environment itself, and so may only be called by users with admin
+// access.
+var adminAccessMethods = map[string]set.Strings{
+	"Client": set.NewStrings(
+		"AbortCurrentUpgrade",
+		"DestroyEnvironment",
+		"EnsureAvailability",
+		"EnvironmentSet",
+		"EnvironmentUnset",
+		"ModifyEnvironmentAccess",
+		"SetEnvironAgentVersion",
+		"ShareEnvironment",
+	),
+	"KeyManager": set.NewStrings(
+		"AddKeys",
+		"DeleteKeys",
+		"ImportKeys",
+	),
+}
+
+// requiredAccess returns the level of access to the environment
+// needed to call the given method. Methods not otherwise listed
+// require write access.
+func requiredAccess(rootName, methodName string) state.EnvironmentAccess {
+	if adminAccessFacades.Contains(rootName) || adminAccessMethods[rootName].Contains(methodName) {
+		return state.EnvironmentAdminAccess
+	}
+	if readAccessMethods[rootName].Contains(methodName) {
+		return state.EnvironmentReadAccess
+	}
+	return state.EnvironmentWriteAccess
+}
+
+// IsMethodAllowedForAccess reports whether a user with the given level
+// of access to an environment may call the given method.
+func IsMethodAllowedForAccess(access state.EnvironmentAccess, rootName, methodName string) bool {
+	return access.Includes(requiredAccess(rootName, methodName))
+}
+
+// FindMethod returns common.ErrPerm for any API call that needs a
+// higher level of access than the logged in user has.
+func (r *accessRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	caller, err := r.MethodFinder.FindMethod(rootName, version, methodName)
+	if err != nil {
+		return nil, err
+	}
+	if !IsMethodAllowedForAccess(r.access, rootName, methodName) {
+		return nil, common.ErrPerm
+	}
+	return caller, nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+)
+
+type accessRootSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&accessRootSuite{})
+
+func (r *accessRootSuite) TestReadAccessFindsReadMethod(c *gc.C) {
+	root := apiserver.TestingAccessRoot(nil, state.EnvironmentReadAccess)
+
+	caller, err := root.FindMethod("Client", 0, "FullStatus")
+
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(caller, gc.NotNil)
+}
+
+func (r *accessRootSuite) TestReadAccessCannotFindWriteMethod(c *gc.C) {
+	root := apiserver.TestingAccessRoot(nil, state.EnvironmentReadAccess)
+
+	caller, err := root.FindMethod("Client", 0, "ServiceDeploy")
+
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(err, jc.Satisfies, params.IsCodeUnauthorized)
+	c.Assert(caller, gc.IsNil)
+}
+
+func (r *accessRootSuite) TestWriteAccessFindsWriteMethod(c *gc.C) {
+	root := apiserver.TestingAccessRoot(nil, state.EnvironmentWriteAccess)
+
+	caller, err := root.FindMethod("Client", 0, "DestroyMachines")
+
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(caller, gc.NotNil)
+}
+
+func (r *accessRootSuite) TestWriteAccessCannotFindAdminMethod(c *gc.C) {
+	root := apiserver.TestingAccessRoot(nil, state.EnvironmentWriteAccess)
+
+	caller, err := root.FindMethod("Client", 0, "ShareEnvironment")
+
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(caller, gc.IsNil)
+}
+
+func (r *accessRootSuite) TestFindNonExistentMethod(c *gc.C) {
+	root := apiserver.TestingAccessRoot(nil, state.EnvironmentReadAccess)
+
+	caller, err := root.FindMethod("Foo", 0, "Bar")
+
+	c.Assert(err, gc.ErrorMatches, "unknown object type \"Foo\"")
+	c.Assert(caller, gc.IsNil)
+}
+
+func (r *accessRootSuite) TestIsMethodAllowedForAccess(c *gc.C) {
+	for i, test := range []struct {
+		rootName   string
+		methodName string
+		read       bool
+		write      bool
+	}{
+		{"Client", "FullStatus", true, true},
+		{"Client", "ServiceGet", true, true},
+		{"Client", "EnvironmentGet", true, true},
+		{"Client", "ServiceDeploy", false, true},
+		{"Client", "DestroyMachines", false, true},
+		{"Client", "EnvironmentSet", false, false},
+		{"Client", "DestroyEnvironment", false, false},
+		{"Client", "ModifyEnvironmentAccess", false, false},
+		{"AllWatcher", "Next", true, true},
+		{"Pinger", "Ping", true, true},
+		{"Service", "ServicesDeploy", false, true},
+		{"KeyManager", "ListKeys", true, true},
+		{"KeyManager", "AddKeys", false, false},
+		{"Backups", "List", false, false},
+		{"HighAvailability", "EnsureAvailability", false, false},
+		{"EnvironmentManager", "ListEnvironments", true, true},
+		{"EnvironmentManager", "CreateEnvironment", false, true},
+	} {
+		c.Logf("test %d: %s.%s", i, test.rootName, test.methodName)
+		c.Check(apiserver.IsMethodAllowedForAccess(state.EnvironmentReadAccess, test.rootName, test.methodName), gc.Equals, test.read)
+		c.Check(apiserver.IsMethodAllowedForAccess(state.EnvironmentWriteAccess, test.rootName, test.methodName), gc.Equals, test.write)
+		c.Check(apiserver.IsMethodAllowedForAccess(state.EnvironmentAdminAccess, test.rootName, test.methodName), jc.IsTrue)
+	}
+}
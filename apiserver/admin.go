@@ -105,7 +105,7 @@ func (a *admin) doLogin(req params.LoginRequest) (params.LoginResultV1, error) {
 	}
 
 	// authedApi is the API method finder we'll use after getting logged in.
-	var authedApi rpc.MethodFinder = newApiRoot(a.srv, a.root.resources, a.root)
+	var authedApi rpc.MethodFinder = newApiRoot(a.root.state, a.root.resources, a.root)
 
 	// Use the login validation function, if one was specified.
 	if a.srv.validator != nil {
@@ -136,7 +136,14 @@ func (a *admin) doLogin(req params.LoginRequest) (params.LoginResultV1, error) {
 		isUser = true
 	}
 
-	entity, err := doCheckCreds(a.srv.state, req)
+	entity, err := doCheckCreds(a.root.state, req)
+	if err == common.ErrBadCreds && a.root.state != a.srv.state {
+		// The agents of state server machines log into hosted
+		// environments to run their workers.
+		if machine, ssErr := checkStateServerCreds(a.srv.state, req); ssErr == nil {
+			entity, err = machine, nil
+		}
+	}
 	if err != nil {
 		if a.maintenanceInProgress() {
 			// An upgrade, restore or similar operation is in
@@ -152,6 +159,18 @@ func (a *admin) doLogin(req params.LoginRequest) (params.LoginResultV1, error) {
 	}
 	a.root.entity = entity
 
+	// Restrict users to the API calls permitted by their level of
+	// access to the environment.
+	if user, ok := entity.Tag().(names.UserTag); ok {
+		envUser, err := a.root.state.EnvironmentUser(user)
+		if err != nil {
+			return fail, errors.Trace(err)
+		}
+		if access := envUser.Access(); access != state.EnvironmentAdminAccess {
+			authedApi = newAccessRoot(authedApi, access)
+		}
+	}
+
 	if a.reqNotifier != nil {
 		a.reqNotifier.login(entity.Tag().String())
 	}
@@ -160,7 +179,7 @@ func (a *admin) doLogin(req params.LoginRequest) (params.LoginResultV1, error) {
 	// to serve to them.
 	a.loggedIn = true
 
-	if err := startPingerIfAgent(a.root, entity); err != nil {
+	if err := startPingerIfAgent(a.srv, a.root, entity); err != nil {
 		return fail, err
 	}
 
@@ -255,6 +274,23 @@ func checkCreds(st *state.State, req params.LoginRequest) (state.Entity, error)
 	return entity, nil
 }
 
+// checkStateServerCreds checks the credentials of a machine agent of
+// one of the state server machines in the given state server
+// environment.
+func checkStateServerCreds(st *state.State, req params.LoginRequest) (state.Entity, error) {
+	if kind, err := names.TagKind(req.AuthTag); err != nil || kind != names.MachineTagKind {
+		return nil, common.ErrBadCreds
+	}
+	entity, err := doCheckCreds(st, req)
+	if err != nil {
+		return nil, err
+	}
+	if machine, ok := entity.(*state.Machine); !ok || !machine.IsManager() {
+		return nil, common.ErrBadCreds
+	}
+	return entity, nil
+}
+
 func getAndUpdateLastLoginForEntity(entity state.Entity) *time.Time {
 	if user, ok := entity.(*state.User); ok {
 		result := user.LastLogin()
@@ -290,29 +326,49 @@ func (p *machinePinger) Stop() error {
 	return p.Pinger.Kill()
 }
 
-func startPingerIfAgent(root *apiHandler, entity state.Entity) error {
-	// A machine or unit agent has connected, so start a pinger to
-	// announce it's now alive, and set up the API pinger
-	// so that the connection will be terminated if a sufficient
-	// interval passes between pings.
+// agentConnection wraps a presence.Connection.
+type agentConnection struct {
+	*presence.Connection
+}
+
+// Stop implements Resource.Stop() as Connection.Close(), needed at
+// connection closing time to report the agent as gone.
+func (c *agentConnection) Stop() error {
+	return c.Connection.Close()
+}
+
+func startPingerIfAgent(srv *Server, root *apiHandler, entity state.Entity) error {
+	// A machine or unit agent has connected, so record its
+	// connection (or, failing that, start a pinger) to announce
+	// it's now alive, and set up the API pinger so that the
+	// connection will be terminated if a sufficient interval
+	// passes between pings.
 	agentPresencer, ok := entity.(presence.Presencer)
 	if !ok {
 		return nil
 	}
 
-	pinger, err := agentPresencer.SetAgentPresence()
-	if err != nil {
-		return err
+	if connPresencer, ok := entity.(presence.ConnectionPresencer); ok && srv.presence != nil {
+		conn, err := connPresencer.SetAgentConnected(srv.presence)
+		if err != nil {
+			return err
+		}
+		root.getResources().Register(&agentConnection{conn})
+	} else {
+		pinger, err := agentPresencer.SetAgentPresence()
+		if err != nil {
+			return err
+		}
+		root.getResources().Register(&machinePinger{pinger})
 	}
 
-	root.getResources().Register(&machinePinger{pinger})
 	action := func() {
 		if err := root.getRpcConn().Close(); err != nil {
 			logger.Errorf("error closing the RPC connection: %v", err)
 		}
 	}
 	pingTimeout := newPingTimeout(action, maxClientPingInterval)
-	err = root.getResources().RegisterNamed("pingTimeout", pingTimeout)
+	err := root.getResources().RegisterNamed("pingTimeout", pingTimeout)
 	if err != nil {
 		return err
 	}
@@ -17,6 +17,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/environmentmanager"
 	"github.com/juju/juju/apiserver"
 	"github.com/juju/juju/apiserver/params"
 	jujutesting "github.com/juju/juju/juju/testing"
@@ -533,6 +534,108 @@ func (s *loginSuite) TestNonEnvironUserLoginFails(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
 }
 
+func (s *loginSuite) TestReadAccessUserLoginRestrictsAPI(c *gc.C) {
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Password: "dummy-password",
+		Access:   state.EnvironmentReadAccess,
+	})
+
+	info.Tag = user.UserTag()
+	info.Password = "dummy-password"
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	_, err = st.Client().Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = st.Client().DestroyMachines("0")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(err, jc.Satisfies, params.IsCodeUnauthorized)
+}
+
+func (s *loginSuite) TestReadAccessUserCannotCreateEnvironment(c *gc.C) {
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Password: "dummy-password",
+		Access:   state.EnvironmentReadAccess,
+	})
+
+	info.Tag = user.UserTag()
+	info.Password = "dummy-password"
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	_, err = environmentmanager.NewClient(st).CreateEnvironment(user.UserTag(), map[string]interface{}{
+		"name": "sneaky",
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(err, jc.Satisfies, params.IsCodeUnauthorized)
+}
+
+func (s *loginSuite) TestLoginToHostedEnvironment(c *gc.C) {
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	user := s.Factory.MakeUser(c, &factory.UserParams{Password: "dummy-password"})
+	envState := s.Factory.MakeEnvironment(c, &factory.EnvParams{Owner: user.UserTag()})
+	defer envState.Close()
+
+	info.Tag = user.UserTag()
+	info.Password = "dummy-password"
+	info.EnvironTag = envState.EnvironTag()
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+	envTag, err := st.EnvironTag()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envTag, gc.Equals, envState.EnvironTag())
+}
+
+func (s *loginSuite) TestLoginToUnknownEnvironment(c *gc.C) {
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	uuid, err := utils.NewUUID()
+	c.Assert(err, jc.ErrorIsNil)
+
+	info.Tag = s.AdminUserTag(c)
+	info.Password = "dummy-secret"
+	info.EnvironTag = names.NewEnvironTag(uuid.String())
+	_, err = api.Open(info, fastDialOpts)
+	c.Assert(err, gc.ErrorMatches, `unknown environment: "`+uuid.String()+`"`)
+}
+
+func (s *loginSuite) TestStateServerMachineLoginToHostedEnvironment(c *gc.C) {
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	envState := s.Factory.MakeEnvironment(c, nil)
+	defer envState.Close()
+
+	login := func(job state.MachineJob) error {
+		machine := s.Factory.MakeMachine(c, &factory.MachineParams{
+			Jobs:     []state.MachineJob{job},
+			Password: "machine-password-1234",
+			Nonce:    "fake_nonce",
+		})
+		info.Tag = machine.Tag()
+		info.Password = "machine-password-1234"
+		info.Nonce = "fake_nonce"
+		info.EnvironTag = envState.EnvironTag()
+		st, err := api.Open(info, fastDialOpts)
+		if err == nil {
+			st.Close()
+		}
+		return err
+	}
+	// Only the agents of state server machines may log into hosted
+	// environments with the state server's credentials.
+	c.Assert(login(state.JobManageEnviron), jc.ErrorIsNil)
+	c.Assert(login(state.JobHostUnits), gc.ErrorMatches, "invalid entity name or password")
+}
+
 func (s *loginV0Suite) TestLoginReportsEnvironTag(c *gc.C) {
 	st, cleanup := s.setupServer(c)
 	defer cleanup()
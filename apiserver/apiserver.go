@@ -14,6 +14,7 @@ import (
 
 	"code.google.com/p/go.net/websocket"
 	"github.com/bmizerany/pat"
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
 	"github.com/juju/utils"
@@ -24,6 +25,7 @@ import (
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/rpc/jsoncodec"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/presence"
 )
 
 var logger = loggo.GetLogger("juju.apiserver")
@@ -44,6 +46,12 @@ type Server struct {
 	limiter           utils.Limiter
 	validator         LoginValidator
 	adminApiFactories map[int]adminApiFactory
+	auditor           *auditor
+	metrics           *apiMetrics
+
+	// presence, if not nil, records the agents connected to the
+	// server so that they are reported alive without pinging.
+	presence *presence.Recorder
 
 	mu          sync.Mutex // protects the fields that follow
 	environUUID string
@@ -85,11 +93,19 @@ func NewServer(s *state.State, lis net.Listener, cfg ServerConfig) (*Server, err
 		logDir:    cfg.LogDir,
 		limiter:   utils.NewLimiter(loginRateLimit),
 		validator: cfg.Validator,
+		auditor:   newAuditor(s),
+		metrics:   newAPIMetrics(),
 		adminApiFactories: map[int]adminApiFactory{
 			0: newAdminApiV0,
 			1: newAdminApiV1,
 		},
 	}
+	if cfg.Tag != nil {
+		srv.presence, err = s.NewPresenceRecorder(cfg.Tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 	// TODO(rog) check that *srvRoot is a valid type for using
 	// as an RPC server.
 	lis = tls.NewListener(lis, &tls.Config{
@@ -111,6 +127,17 @@ func (srv *Server) Stop() error {
 	return srv.tomb.Wait()
 }
 
+// stopPresence stops recording the agents connected to the server,
+// so that they are immediately reported as dead.
+func (srv *Server) stopPresence() {
+	if srv.presence == nil {
+		return
+	}
+	if err := srv.presence.Stop(); err != nil {
+		logger.Errorf("error stopping presence recorder: %v", err)
+	}
+}
+
 // Kill implements worker.Worker.Kill.
 func (srv *Server) Kill() {
 	srv.tomb.Kill(nil)
@@ -192,6 +219,7 @@ func handleAll(mux *pat.PatternServeMux, pattern string, handler http.Handler) {
 
 func (srv *Server) run(lis net.Listener) {
 	defer srv.tomb.Done()
+	defer srv.stopPresence()
 	defer srv.wg.Wait() // wait for any outstanding requests to complete.
 	srv.wg.Add(1)
 	go func() {
@@ -205,6 +233,11 @@ func (srv *Server) run(lis net.Listener) {
 		srv.tomb.Kill(err)
 		srv.wg.Done()
 	}()
+	srv.wg.Add(1)
+	go func() {
+		srv.auditor.run(srv.tomb.Dying())
+		srv.wg.Done()
+	}()
 	// for pat based handlers, they are matched in-order of being
 	// registered, first match wins. So more specific ones have to be
 	// registered first.
@@ -237,6 +270,11 @@ func (srv *Server) run(lis net.Listener) {
 	handleAll(mux, "/environment/:envuuid/backups",
 		&backupHandler{httpHandler{state: srv.state}},
 	)
+	handleAll(mux, "/environment/:envuuid/metrics",
+		&metricsHandler{
+			httpHandler: httpHandler{state: srv.state},
+			metrics:     srv.metrics},
+	)
 	handleAll(mux, "/environment/:envuuid/api", http.HandlerFunc(srv.apiHandler))
 	// For backwards compatibility we register all the old paths
 	handleAll(mux, "/log",
@@ -259,6 +297,11 @@ func (srv *Server) run(lis net.Listener) {
 			httpHandler{state: srv.state},
 		}},
 	)
+	handleAll(mux, "/metrics",
+		&metricsHandler{
+			httpHandler: httpHandler{state: srv.state},
+			metrics:     srv.metrics},
+	)
 	handleAll(mux, "/", http.HandlerFunc(srv.apiHandler))
 	// The error from http.Serve is not interesting.
 	http.Serve(lis, mux)
@@ -294,7 +337,11 @@ func (srv *Server) Addr() string {
 	return srv.addr
 }
 
-func (srv *Server) validateEnvironUUID(envUUID string) error {
+// stateForEnviron returns the state of the environment with the given
+// UUID, which must be hosted by this server. An empty UUID addresses
+// the state server environment. Any state returned other than
+// srv.state must be closed by the caller.
+func (srv *Server) stateForEnviron(envUUID string) (*state.State, error) {
 	if envUUID == "" {
 		// We allow the environUUID to be empty for 2 cases
 		// 1) Compatibility with older clients
@@ -303,16 +350,34 @@ func (srv *Server) validateEnvironUUID(envUUID string) error {
 		//    threaded that information all the way back to the 'juju
 		//    bootstrap' process to be able to cache the value until
 		//    after we've connected one time.
-		return nil
+		return srv.state, nil
 	}
 	if srv.getEnvironUUID() == "" {
 		env, err := srv.state.Environment()
 		if err != nil {
-			return err
+			return nil, err
 		}
 		srv.setEnvironUUID(env.UUID())
 	}
-	return srv.checkEnvironUUID(envUUID)
+	if srv.checkEnvironUUID(envUUID) == nil {
+		return srv.state, nil
+	}
+
+	// The environment may be one hosted by the state server.
+	if !names.IsValidEnvironment(envUUID) {
+		return nil, common.UnknownEnvironmentError(envUUID)
+	}
+	envTag := names.NewEnvironTag(envUUID)
+	env, err := srv.state.GetEnvironment(envTag)
+	if errors.IsNotFound(err) {
+		return nil, common.UnknownEnvironmentError(envUUID)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if env.ServerTag() != srv.state.EnvironTag() {
+		return nil, common.UnknownEnvironmentError(envUUID)
+	}
+	return srv.state.ForEnviron(envTag)
 }
 
 // checkEnvironUUID checks if the expected envionUUID matches the
@@ -343,18 +408,27 @@ func (srv *Server) serveConn(wsConn *websocket.Conn, reqNotifier *requestNotifie
 	if loggo.GetLogger("juju.rpc.jsoncodec").EffectiveLogLevel() <= loggo.TRACE {
 		codec.SetLogging(true)
 	}
-	var notifier rpc.RequestNotifier
+	st, err := srv.stateForEnviron(envUUID)
+	if err == nil {
+		if st != srv.state {
+			defer st.Close()
+		}
+		envUUID = st.EnvironUUID()
+	}
+	notifiers := requestNotifiers{
+		newAuditNotifier(srv.auditor, reqNotifier, envUUID),
+		metricsNotifier{srv.metrics},
+	}
 	if logger.EffectiveLogLevel() <= loggo.DEBUG {
 		// Incur request monitoring overhead only if we
 		// know we'll need it.
-		notifier = reqNotifier
+		notifiers = append(notifiers, reqNotifier)
 	}
-	conn := rpc.NewConn(codec, notifier)
+	conn := rpc.NewConn(codec, notifiers)
 
-	var err error
 	var h *apiHandler
-	if err = srv.validateEnvironUUID(envUUID); err == nil {
-		h, err = newApiHandler(srv, conn, reqNotifier)
+	if err == nil {
+		h, err = newApiHandler(srv, st, conn, reqNotifier)
 	}
 	if err != nil {
 		conn.Serve(&errRoot{err}, serverError)
@@ -364,6 +438,8 @@ func (srv *Server) serveConn(wsConn *websocket.Conn, reqNotifier *requestNotifie
 			adminApis[apiVersion] = factory(srv, h, reqNotifier)
 		}
 		conn.ServeFinder(newAnonRoot(h, adminApis), serverError)
+		srv.metrics.addConn(h.resources)
+		defer srv.metrics.removeConn(h.resources)
 	}
 	conn.Start()
 	select {
@@ -0,0 +1,262 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/audit"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/state"
+)
+
+// auditQueueSize holds the number of audit entries that may be
+// waiting to be written before further entries are dropped.
+const auditQueueSize = 1000
+
+// auditor records audit entries in state. Entries are written by a
+// single goroutine so that recording them never blocks the RPC
+// server. Each entry is written to the environment it was made
+// against.
+type auditor struct {
+	st      *state.State
+	entries chan audit.Entry
+
+	// mu guards hosted, which holds the states of the hosted
+	// environments that entries have been written to. A state is
+	// closed when its environment becomes dead or is removed, and
+	// the rest are closed when run returns.
+	mu     sync.Mutex
+	hosted map[string]*state.State
+}
+
+func newAuditor(st *state.State) *auditor {
+	return &auditor{
+		st:      st,
+		entries: make(chan audit.Entry, auditQueueSize),
+		hosted:  make(map[string]*state.State),
+	}
+}
+
+// add queues the given entry to be written.
+func (a *auditor) add(entry audit.Entry) {
+	select {
+	case a.entries <- entry:
+	default:
+		logger.Warningf("audit queue full, dropping entry for %s %s", entry.User, entry.Operation())
+	}
+}
+
+// run writes queued entries until the dying channel is closed.
+func (a *auditor) run(dying <-chan struct{}) {
+	defer a.closeHosted()
+	w := a.st.WatchEnvironments()
+	defer w.Stop()
+	changes := w.Changes()
+	for {
+		select {
+		case <-dying:
+			return
+		case uuids, ok := <-changes:
+			if !ok {
+				// Keep writing entries; hosted states are then
+				// only closed when run returns.
+				logger.Errorf("environments watcher stopped: %v", w.Err())
+				changes = nil
+				continue
+			}
+			a.closeDead(uuids)
+		case entry := <-a.entries:
+			if err := a.write(entry); err != nil {
+				logger.Errorf("cannot record audit entry: %v", err)
+			}
+		}
+	}
+}
+
+// write records the entry in the state of its environment.
+func (a *auditor) write(entry audit.Entry) error {
+	st, err := a.stateFor(entry.EnvUUID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return st.AddAuditEntry(entry)
+}
+
+// stateFor returns the state of the environment with the given UUID.
+// The states of hosted environments are opened when first needed and
+// kept until the environment dies or run returns.
+func (a *auditor) stateFor(envUUID string) (*state.State, error) {
+	if envUUID == "" || envUUID == a.st.EnvironUUID() {
+		return a.st, nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if st, ok := a.hosted[envUUID]; ok {
+		return st, nil
+	}
+	if !names.IsValidEnvironment(envUUID) {
+		return nil, errors.NotValidf("environment UUID %q", envUUID)
+	}
+	st, err := a.st.ForEnviron(names.NewEnvironTag(envUUID))
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot open environment %q", envUUID)
+	}
+	a.hosted[envUUID] = st
+	return st, nil
+}
+
+// closeDead closes the states of those of the given hosted
+// environments that are dead or have been removed.
+func (a *auditor) closeDead(envUUIDs []string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, envUUID := range envUUIDs {
+		if _, ok := a.hosted[envUUID]; !ok {
+			continue
+		}
+		env, err := a.st.GetEnvironment(names.NewEnvironTag(envUUID))
+		if err == nil && env.Life() != state.Dead {
+			continue
+		} else if err != nil && !errors.IsNotFound(err) {
+			logger.Errorf("cannot get environment %q: %v", envUUID, err)
+			continue
+		}
+		a.closeState(envUUID)
+	}
+}
+
+// closeHosted closes the states of the hosted environments.
+func (a *auditor) closeHosted() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for envUUID := range a.hosted {
+		a.closeState(envUUID)
+	}
+}
+
+// closeState closes the state of the given hosted environment. It must
+// be called with mu held.
+func (a *auditor) closeState(envUUID string) {
+	if err := a.hosted[envUUID].Close(); err != nil {
+		logger.Errorf("cannot close state of environment %q: %v", envUUID, err)
+	}
+	delete(a.hosted, envUUID)
+}
+
+// auditNotifier is an rpc.RequestNotifier that records the API calls
+// made by users on a single connection to the environment with the
+// given UUID.
+type auditNotifier struct {
+	auditor     *auditor
+	reqNotifier *requestNotifier
+	envUUID     string
+
+	mu      sync.Mutex
+	pending map[uint64]string
+}
+
+func newAuditNotifier(a *auditor, reqNotifier *requestNotifier, envUUID string) *auditNotifier {
+	return &auditNotifier{
+		auditor:     a,
+		reqNotifier: reqNotifier,
+		envUUID:     envUUID,
+		pending:     make(map[uint64]string),
+	}
+}
+
+// shouldAudit reports whether a request made by the entity with the
+// given tag should be recorded. Only requests made by users are
+// audited, and requests that merely keep the connection alive or
+// poll watchers are ignored.
+func shouldAudit(tag string, req rpc.Request) bool {
+	t, err := names.ParseTag(tag)
+	if err != nil || t.Kind() != names.UserTagKind {
+		return false
+	}
+	switch {
+	case req.Type == "Admin", req.Type == "Pinger":
+		return false
+	case strings.HasSuffix(req.Type, "Watcher"):
+		return false
+	}
+	return true
+}
+
+// ServerRequest implements rpc.RequestNotifier.
+func (n *auditNotifier) ServerRequest(hdr *rpc.Header, body interface{}) {
+	if !shouldAudit(n.reqNotifier.tag(), hdr.Request) {
+		return
+	}
+	summary := audit.SummarizeArguments(body)
+	n.mu.Lock()
+	n.pending[hdr.RequestId] = summary
+	n.mu.Unlock()
+}
+
+// ServerReply implements rpc.RequestNotifier.
+func (n *auditNotifier) ServerReply(req rpc.Request, hdr *rpc.Header, body interface{}, timeSpent time.Duration) {
+	n.mu.Lock()
+	summary, ok := n.pending[hdr.RequestId]
+	delete(n.pending, hdr.RequestId)
+	n.mu.Unlock()
+	if !ok {
+		return
+	}
+	n.auditor.add(audit.Entry{
+		Timestamp: time.Now(),
+		EnvUUID:   n.envUUID,
+		User:      n.reqNotifier.tag(),
+		Facade:    req.Type,
+		Version:   req.Version,
+		Method:    req.Action,
+		Arguments: summary,
+		Error:     hdr.Error,
+	})
+}
+
+// ClientRequest implements rpc.RequestNotifier.
+func (n *auditNotifier) ClientRequest(hdr *rpc.Header, body interface{}) {
+}
+
+// ClientReply implements rpc.RequestNotifier.
+func (n *auditNotifier) ClientReply(req rpc.Request, hdr *rpc.Header, body interface{}) {
+}
+
+// requestNotifiers is an rpc.RequestNotifier that passes every
+// notification on to each of its members.
+type requestNotifiers []rpc.RequestNotifier
+
+// ServerRequest implements rpc.RequestNotifier.
+func (ns requestNotifiers) ServerRequest(hdr *rpc.Header, body interface{}) {
+	for _, n := range ns {
+		n.ServerRequest(hdr, body)
+	}
+}
+
+// ServerReply implements rpc.RequestNotifier.
+func (ns requestNotifiers) ServerReply(req rpc.Request, hdr *rpc.Header, body interface{}, timeSpent time.Duration) {
+	for _, n := range ns {
+		n.ServerReply(req, hdr, body, timeSpent)
+	}
+}
+
+// ClientRequest implements rpc.RequestNotifier.
+func (ns requestNotifiers) ClientRequest(hdr *rpc.Header, body interface{}) {
+	for _, n := range ns {
+		n.ClientRequest(hdr, body)
+	}
+}
+
+// ClientReply implements rpc.RequestNotifier.
+func (ns requestNotifiers) ClientReply(req rpc.Request, hdr *rpc.Header, body interface{}) {
+	for _, n := range ns {
+		n.ClientReply(req, hdr, body)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"encoding/base64"
+	"strings"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/backups"
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/audit"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type auditSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&auditSuite{})
+
+func (s *auditSuite) waitForEntries(c *gc.C, filter state.AuditFilter, count int) []audit.Entry {
+	return waitForEntries(c, s.State, filter, count)
+}
+
+// waitForEntries waits until the audit log of the given state holds at
+// least count entries matching the filter, and returns them.
+func waitForEntries(c *gc.C, st *state.State, filter state.AuditFilter, count int) []audit.Entry {
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		entries, err := st.AuditEntries(filter)
+		c.Assert(err, jc.ErrorIsNil)
+		if len(entries) >= count || !a.HasNext() {
+			return entries
+		}
+	}
+	panic("unreachable")
+}
+
+func (s *auditSuite) TestUserCallsAreAudited(c *gc.C) {
+	_, err := s.APIState.Client().EnvironmentGet()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.APIState.Client().DestroyMachines("42")
+	c.Assert(err, gc.NotNil)
+
+	entries := s.waitForEntries(c, state.AuditFilter{Facade: "Client"}, 2)
+	c.Assert(entries, gc.HasLen, 2)
+
+	c.Check(entries[0].User, gc.Equals, s.AdminUserTag(c).String())
+	c.Check(entries[0].EnvUUID, gc.Equals, s.State.EnvironUUID())
+	c.Check(entries[0].Operation(), gc.Equals, "Client.EnvironmentGet")
+	c.Check(entries[0].Outcome(), gc.Equals, "success")
+
+	c.Check(entries[1].Operation(), gc.Equals, "Client.DestroyMachines")
+	c.Check(entries[1].Arguments, gc.Matches, `.*"42".*`)
+	c.Check(entries[1].Outcome(), gc.Equals, "failure")
+	c.Check(entries[1].Error, gc.Not(gc.Equals), "")
+}
+
+func (s *auditSuite) TestHostedEnvironmentCallsAreAudited(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Password: "dummy-password"})
+	envState := s.Factory.MakeEnvironment(c, &factory.EnvParams{Owner: user.UserTag()})
+	defer envState.Close()
+
+	info := s.APIInfo(c)
+	info.Tag = user.UserTag()
+	info.Password = "dummy-password"
+	info.EnvironTag = envState.EnvironTag()
+	st, err := api.Open(info, api.DialOpts{})
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+	_, err = st.Client().EnvironmentGet()
+	c.Assert(err, jc.ErrorIsNil)
+
+	filter := state.AuditFilter{User: user.Tag().String()}
+	entries := waitForEntries(c, envState, filter, 1)
+	c.Assert(entries, gc.HasLen, 1)
+	c.Check(entries[0].EnvUUID, gc.Equals, envState.EnvironUUID())
+	c.Check(entries[0].Operation(), gc.Equals, "Client.EnvironmentGet")
+
+	// The call is not recorded against the state server environment.
+	entries, err = s.State.AuditEntries(filter)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(entries, gc.HasLen, 0)
+}
+
+func (s *auditSuite) TestHostedEnvironmentStateClosedWhenDead(c *gc.C) {
+	envState := s.Factory.MakeEnvironment(c, nil)
+	defer envState.Close()
+
+	a := apiserver.NewAuditor(s.State)
+	dying := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		apiserver.RunAuditor(a, dying)
+		close(done)
+	}()
+	defer func() {
+		close(dying)
+		<-done
+	}()
+
+	apiserver.AddAuditEntry(a, audit.Entry{
+		Timestamp: time.Now(),
+		EnvUUID:   envState.EnvironUUID(),
+		User:      s.AdminUserTag(c).String(),
+		Facade:    "Client",
+		Method:    "EnvironmentGet",
+	})
+	waitForEntries(c, envState, state.AuditFilter{}, 1)
+	c.Assert(apiserver.AuditorHostedEnvironments(a), jc.DeepEquals, []string{envState.EnvironUUID()})
+
+	env, err := envState.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	err = env.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = env.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	for attempt := coretesting.LongAttempt.Start(); attempt.Next(); {
+		s.State.StartSync()
+		if len(apiserver.AuditorHostedEnvironments(a)) == 0 {
+			return
+		}
+	}
+	c.Fatalf("state of dead environment not closed")
+}
+
+func (s *auditSuite) TestBackupEncryptionKeysAreNotAudited(c *gc.C) {
+	key := []byte("some secret key!")
+	client := backups.NewClient(s.APIState)
+	// Whether or not the backup can be created here, the call is
+	// audited.
+	client.Create("keyed", key)
+
+	entries := s.waitForEntries(c, state.AuditFilter{Facade: "Backups"}, 1)
+	c.Assert(entries, gc.HasLen, 1)
+	c.Check(entries[0].Operation(), gc.Equals, "Backups.Create")
+	c.Check(entries[0].Arguments, gc.Matches, `.*"EncryptionKey":"<redacted>".*`)
+	c.Check(strings.Contains(entries[0].Arguments, base64.StdEncoding.EncodeToString(key)), jc.IsFalse)
+}
+
+func (s *auditSuite) TestAgentCallsAreNotAudited(c *gc.C) {
+	st, _ := s.OpenAPIAsNewMachine(c)
+	_, err := st.Environment().EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	err = st.Ping()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Make an audited call so we know when the auditor has caught up.
+	_, err = s.APIState.Client().EnvironmentGet()
+	c.Assert(err, jc.ErrorIsNil)
+	entries := s.waitForEntries(c, state.AuditFilter{}, 1)
+	for _, entry := range entries {
+		c.Check(entry.User, gc.Equals, s.AdminUserTag(c).String())
+		c.Check(entry.Facade, gc.Not(gc.Equals), "Pinger")
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.auditlog")
+
+func init() {
+	common.RegisterStandardFacade("AuditLog", 0, NewAuditLogAPI)
+}
+
+// AuditLog defines the methods on the auditlog API end point.
+type AuditLog interface {
+	Entries(args params.AuditLogFilter) (params.AuditLogResults, error)
+}
+
+// AuditLogAPI implements the AuditLog interface and is the concrete
+// implementation of the api end point.
+type AuditLogAPI struct {
+	state      *state.State
+	authorizer common.Authorizer
+}
+
+var _ AuditLog = (*AuditLogAPI)(nil)
+
+// NewAuditLogAPI creates a new server-side AuditLog API facade.
+func NewAuditLogAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*AuditLogAPI, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &AuditLogAPI{
+		state:      st,
+		authorizer: authorizer,
+	}, nil
+}
+
+// permissionCheck returns an error if the authenticated user may not
+// read the audit log.
+func (api *AuditLogAPI) permissionCheck() error {
+	// TODO(PERMISSIONS): for now only the owner of the environment
+	// may read its audit log.
+	env, err := api.state.Environment()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if api.authorizer.GetAuthTag() != env.Owner() {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// Entries returns the audit log entries of the environment that
+// match the given filter, oldest first.
+func (api *AuditLogAPI) Entries(args params.AuditLogFilter) (params.AuditLogResults, error) {
+	var result params.AuditLogResults
+	if err := api.permissionCheck(); err != nil {
+		return result, errors.Trace(err)
+	}
+	entries, err := api.state.AuditEntries(state.AuditFilter{
+		User:   args.User,
+		Facade: args.Facade,
+		Method: args.Method,
+		After:  args.After,
+		Before: args.Before,
+		Limit:  args.Limit,
+	})
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Entries = make([]params.AuditLogEntry, len(entries))
+	for i, entry := range entries {
+		result.Entries[i] = params.AuditLogEntry{
+			Timestamp: entry.Timestamp,
+			EnvUUID:   entry.EnvUUID,
+			User:      entry.User,
+			Facade:    entry.Facade,
+			Version:   entry.Version,
+			Method:    entry.Method,
+			Arguments: entry.Arguments,
+			Error:     entry.Error,
+		}
+	}
+	logger.Debugf("returning %d audit entries", len(entries))
+	return result, nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog_test
+
+import (
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/auditlog"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/audit"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type auditLogSuite struct {
+	jujutesting.JujuConnSuite
+
+	api        *auditlog.AuditLogAPI
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&auditLogSuite{})
+
+var epoch = time.Date(2014, 11, 5, 10, 0, 0, 0, time.UTC)
+
+func (s *auditLogSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	var err error
+	s.api, err = auditlog.NewAuditLogAPI(s.State, nil, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	for i, entry := range []audit.Entry{{
+		User:   "user-admin",
+		Facade: "Client",
+		Method: "ServiceDeploy",
+	}, {
+		User:   "user-bob",
+		Facade: "Client",
+		Method: "DestroyMachines",
+		Error:  "permission denied",
+	}} {
+		entry.Timestamp = epoch.Add(time.Duration(i) * time.Minute)
+		err := s.State.AddAuditEntry(entry)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *auditLogSuite) TestNewAuditLogAPIRefusesNonClient(c *gc.C) {
+	anAuthoriser := s.authorizer
+	anAuthoriser.Tag = names.NewMachineTag("1")
+	endPoint, err := auditlog.NewAuditLogAPI(s.State, nil, anAuthoriser)
+	c.Assert(endPoint, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *auditLogSuite) TestEntries(c *gc.C) {
+	result, err := s.api.Entries(params.AuditLogFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Entries, gc.HasLen, 2)
+	entry := result.Entries[1]
+	c.Assert(entry.Timestamp.Equal(epoch.Add(time.Minute)), jc.IsTrue)
+	entry.Timestamp = time.Time{}
+	c.Assert(entry, gc.DeepEquals, params.AuditLogEntry{
+		EnvUUID: s.State.EnvironUUID(),
+		User:    "user-bob",
+		Facade:  "Client",
+		Method:  "DestroyMachines",
+		Error:   "permission denied",
+	})
+}
+
+func (s *auditLogSuite) TestEntriesFiltered(c *gc.C) {
+	result, err := s.api.Entries(params.AuditLogFilter{
+		User:  "user-admin",
+		After: epoch,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Entries, gc.HasLen, 1)
+	c.Assert(result.Entries[0].Method, gc.Equals, "ServiceDeploy")
+}
+
+func (s *auditLogSuite) TestEntriesRefusedForNonOwner(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	api, err := auditlog.NewAuditLogAPI(s.State, nil, apiservertesting.FakeAuthorizer{
+		Tag: user.Tag(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.Entries(params.AuditLogFilter{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
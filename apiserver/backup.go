@@ -15,6 +15,7 @@ import (
 	apiserverbackups "github.com/juju/juju/apiserver/backups"
 	apihttp "github.com/juju/juju/apiserver/http"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/backups"
 )
@@ -22,7 +23,7 @@ import (
 // TODO(ericsnow) This file should be in the apiserver/backups package.
 
 var newBackups = func(st *state.State) (backups.Backups, io.Closer) {
-	stor := backups.NewStorage(st)
+	stor := backups.NewConfiguredStorage(st, environs.ProviderStorage)
 	return backups.NewBackups(stor), stor
 }
 
@@ -11,6 +11,7 @@ import (
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/backups"
 )
@@ -77,7 +78,7 @@ func extractResourceValue(resources *common.Resources, key string) (string, erro
 }
 
 var newBackups = func(st *state.State) (backups.Backups, io.Closer) {
-	stor := backups.NewStorage(st)
+	stor := backups.NewConfiguredStorage(st, environs.ProviderStorage)
 	return backups.NewBackups(stor), stor
 }
 
@@ -100,6 +101,8 @@ func ResultFromMetadata(meta *backups.Metadata) params.BackupsMetadataResult {
 		result.Finished = *meta.Finished
 	}
 	result.Notes = meta.Notes
+	result.KeyID = meta.KeyID
+	result.Scheduled = meta.Scheduled
 
 	result.Environment = meta.Origin.Environment
 	result.Machine = meta.Origin.Machine
@@ -123,6 +126,8 @@ func MetadataFromResult(result params.BackupsMetadataResult) *backups.Metadata {
 	meta.Origin.Hostname = result.Hostname
 	meta.Origin.Version = result.Version
 	meta.Notes = result.Notes
+	meta.KeyID = result.KeyID
+	meta.Scheduled = result.Scheduled
 	meta.SetFileInfo(result.Size, result.Checksum, result.ChecksumFormat)
 	return meta
 }
@@ -28,19 +28,7 @@ func (a *API) Create(args params.BackupsCreateArgs) (p params.BackupsMetadataRes
 		return p, errors.Annotatef(err, "HA not ready; try again later")
 	}
 
-	mgoInfo := a.st.MongoConnectionInfo()
-	dbInfo, err := backups.NewDBInfo(mgoInfo, session)
-	if err != nil {
-		return p, errors.Trace(err)
-	}
-
-	meta, err := backups.NewMetadataState(a.st, a.machineID)
-	if err != nil {
-		return p, errors.Trace(err)
-	}
-	meta.Notes = args.Notes
-
-	err = backupsMethods.Create(meta, a.paths, dbInfo)
+	meta, err := backups.CreateBackup(backupsMethods, a.st, session, a.paths, a.machineID, args.Notes, false, args.EncryptionKey)
 	if err != nil {
 		return p, errors.Trace(err)
 	}
@@ -42,6 +42,22 @@ func (s *backupsSuite) TestCreateNotes(c *gc.C) {
 	c.Check(result, gc.DeepEquals, expected)
 }
 
+func (s *backupsSuite) TestCreateEncrypted(c *gc.C) {
+	s.PatchValue(backups.WaitUntilReady,
+		func(*mgo.Session, int) error { return nil },
+	)
+	s.meta.KeyID = "0123456789abcdef"
+	fake := s.setBackups(c, s.meta, "")
+	args := params.BackupsCreateArgs{
+		EncryptionKey: []byte("some secret key!"),
+	}
+	result, err := s.api.Create(args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(fake.KeyArg, jc.DeepEquals, []byte("some secret key!"))
+	c.Check(result.KeyID, gc.Equals, "0123456789abcdef")
+}
+
 func (s *backupsSuite) TestCreateError(c *gc.C) {
 	s.setBackups(c, nil, "failed!")
 	s.PatchValue(backups.WaitUntilReady,
@@ -4,6 +4,8 @@
 package backups
 
 var (
-	NewBackups     = &newBackups
-	WaitUntilReady = &waitUntilReady
+	NewBackups       = &newBackups
+	WaitUntilReady   = &waitUntilReady
+	RestartAgent     = &restartAgent
+	IsPartialRestore = &isPartialRestore
 )
@@ -0,0 +1,122 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"os"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+)
+
+// restartDelay is how long the agent waits after a successful restore
+// before restarting, so the reply to the client gets out first.
+const restartDelay = 2 * time.Second
+
+// isPartialRestore reports whether a restore failed after replacing the
+// database.
+var isPartialRestore = backups.IsPartialRestore
+
+// restartAgent makes the machine agent exit after a delay, so that it
+// is restarted by its init system with the restored configuration.
+var restartAgent = func() {
+	go func() {
+		time.Sleep(restartDelay)
+		logger.Infof("restarting agent after restore")
+		os.Exit(1)
+	}()
+}
+
+// PrepareRestore puts the API server into restore mode, in which it
+// refuses all requests except Restore.
+func (a *API) PrepareRestore() error {
+	info, err := a.st.EnsureRestoreInfo()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(info.SetStatus(state.RestorePending))
+}
+
+// Restore replaces the state and configuration of the state server
+// the API server is running on with the ones in the given backup. The
+// API server must have been put into restore mode with PrepareRestore.
+// Once the restore is done the agent restarts, so clients have to
+// reconnect and call FinishRestore.
+func (a *API) Restore(p params.RestoreArgs) error {
+	machine, err := a.st.Machine(a.machineID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	instanceId, err := machine.InstanceId()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	addrs := machine.Addresses()
+	args := backups.RestoreArgs{
+		MachineId:      a.machineID,
+		InstanceId:     instanceId,
+		PrivateAddress: network.SelectInternalAddress(addrs, false),
+		PublicAddress:  network.SelectPublicAddress(addrs),
+		EncryptionKey:  p.EncryptionKey,
+	}
+
+	info, err := a.st.EnsureRestoreInfo()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := info.SetStatus(state.RestoreInProgress); err != nil {
+		return errors.Annotate(err, "cannot start restore")
+	}
+
+	backups, closer := newBackups(a.st)
+	defer closer.Close()
+
+	if err := backups.Restore(p.BackupId, a.paths, args); err != nil {
+		logger.Errorf("restore failed: %v", err)
+		if isPartialRestore(err) {
+			// The database has been replaced, so the state server
+			// stays blocked until a restore succeeds. The status is
+			// recorded in the restored database.
+			if err := a.setRestoreStatus(state.RestorePartial); err != nil {
+				logger.Errorf("cannot set restore status: %v", err)
+			}
+			return errors.Annotate(err, "restore partially applied; the state server stays in restore mode until a restore succeeds")
+		}
+		// Leave restore mode so the state server is usable again.
+		if err := info.SetStatus(state.RestoreFailed); err != nil {
+			logger.Errorf("cannot set restore status: %v", err)
+		}
+		return errors.Annotate(err, "restore failed")
+	}
+	restartAgent()
+	return nil
+}
+
+// setRestoreStatus sets the status of the current restore, reading the
+// restore info afresh in case the database has been replaced.
+func (a *API) setRestoreStatus(status state.RestoreStatus) error {
+	info, err := a.st.EnsureRestoreInfo()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(info.SetStatus(status))
+}
+
+// FinishRestore marks a finished restore as checked by the client,
+// once it has reconnected to the restarted API server.
+func (a *API) FinishRestore() error {
+	info, err := a.st.EnsureRestoreInfo()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if info.Status() != state.RestoreFinished {
+		return errors.Errorf("restore is not finished: status is %q", info.Status())
+	}
+	return errors.Trace(info.SetStatus(state.RestoreChecked))
+}
@@ -0,0 +1,137 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	backupsAPI "github.com/juju/juju/apiserver/backups"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+)
+
+func (s *backupsSuite) setUpRestore(c *gc.C) *bool {
+	m, err := s.State.AddMachine("quantal", state.JobManageEnviron)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetProvisioned("inst-0", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetAddresses(
+		network.NewAddress("10.0.0.1", network.ScopeCloudLocal),
+		network.NewAddress("1.2.3.4", network.ScopePublic),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.resources.RegisterNamed("machineID", common.StringResource(m.Id()))
+	s.api, err = backupsAPI.NewAPI(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	restarted := false
+	s.PatchValue(backupsAPI.RestartAgent, func() { restarted = true })
+	return &restarted
+}
+
+func (s *backupsSuite) checkRestoreStatus(c *gc.C, expected state.RestoreStatus) {
+	info, err := s.State.EnsureRestoreInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(info.Status(), gc.Equals, expected)
+}
+
+func (s *backupsSuite) TestPrepareRestore(c *gc.C) {
+	err := s.api.PrepareRestore()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkRestoreStatus(c, state.RestorePending)
+}
+
+func (s *backupsSuite) TestRestore(c *gc.C) {
+	restarted := s.setUpRestore(c)
+	fake := s.setBackups(c, nil, "")
+	err := s.api.PrepareRestore()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.api.Restore(params.RestoreArgs{
+		BackupId:      "some-id",
+		EncryptionKey: []byte("some secret key!"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(fake.Calls, jc.DeepEquals, []string{"Restore"})
+	c.Check(fake.IDArg, gc.Equals, "some-id")
+	c.Check(fake.PathsArg.DataDir, gc.Equals, "/var/lib/juju")
+	c.Check(fake.RestoreArgsArg, jc.DeepEquals, backups.RestoreArgs{
+		MachineId:      "0",
+		InstanceId:     instance.Id("inst-0"),
+		PrivateAddress: "10.0.0.1",
+		PublicAddress:  "1.2.3.4",
+		EncryptionKey:  []byte("some secret key!"),
+	})
+	c.Check(*restarted, jc.IsTrue)
+	// The fake does not touch the restore status, which the real
+	// restore sets to RestoreFinished in the restored database.
+	s.checkRestoreStatus(c, state.RestoreInProgress)
+}
+
+func (s *backupsSuite) TestRestoreNotPrepared(c *gc.C) {
+	restarted := s.setUpRestore(c)
+	fake := s.setBackups(c, nil, "")
+
+	err := s.api.Restore(params.RestoreArgs{BackupId: "some-id"})
+	c.Check(err, gc.ErrorMatches, `cannot start restore: cannot set restore status to "RESTORING": .*`)
+	c.Check(fake.Calls, gc.HasLen, 0)
+	c.Check(*restarted, jc.IsFalse)
+}
+
+func (s *backupsSuite) TestRestoreError(c *gc.C) {
+	restarted := s.setUpRestore(c)
+	s.setBackups(c, nil, "failed!")
+	err := s.api.PrepareRestore()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.api.Restore(params.RestoreArgs{BackupId: "some-id"})
+	c.Check(err, gc.ErrorMatches, "restore failed: failed!")
+	c.Check(*restarted, jc.IsFalse)
+	s.checkRestoreStatus(c, state.RestoreFailed)
+}
+
+func (s *backupsSuite) TestRestorePartial(c *gc.C) {
+	restarted := s.setUpRestore(c)
+	s.setBackups(c, nil, "failed!")
+	s.PatchValue(backupsAPI.IsPartialRestore, func(error) bool { return true })
+	err := s.api.PrepareRestore()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.api.Restore(params.RestoreArgs{BackupId: "some-id"})
+	c.Check(err, gc.ErrorMatches, "restore partially applied; the state server stays in restore mode until a restore succeeds: failed!")
+	c.Check(*restarted, jc.IsFalse)
+	s.checkRestoreStatus(c, state.RestorePartial)
+
+	// The restore can be tried again.
+	s.setBackups(c, nil, "")
+	err = s.api.Restore(params.RestoreArgs{BackupId: "some-id"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(*restarted, jc.IsTrue)
+}
+
+func (s *backupsSuite) TestFinishRestore(c *gc.C) {
+	info, err := s.State.EnsureRestoreInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	err = info.SetStatus(state.RestoreFinished)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.api.FinishRestore()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkRestoreStatus(c, state.RestoreChecked)
+}
+
+func (s *backupsSuite) TestFinishRestoreNotFinished(c *gc.C) {
+	err := s.api.PrepareRestore()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.api.FinishRestore()
+	c.Check(err, gc.ErrorMatches, `restore is not finished: status is "PENDING"`)
+}
@@ -0,0 +1,76 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/utils/cron"
+)
+
+// Schedule is the API method that returns the backups schedule and
+// retention policy of the environment, along with the outcome of the
+// last scheduled backup.
+func (a *API) Schedule() (params.BackupsScheduleResult, error) {
+	var result params.BackupsScheduleResult
+	cfg, err := a.st.EnvironConfig()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	if spec, ok := cfg.BackupsSchedule(); ok {
+		schedule, err := cron.Parse(spec)
+		if err != nil {
+			return result, errors.Trace(err)
+		}
+		result.Schedule = spec
+		result.NextRun = schedule.Next(time.Now()).UTC()
+	}
+	result.KeepLast = cfg.BackupsKeepLast()
+	result.KeepDaily = cfg.BackupsKeepDaily()
+	result.KeepWeekly = cfg.BackupsKeepWeekly()
+
+	status, info, data, err := a.st.BackupsScheduleStatus()
+	if errors.IsNotFound(err) {
+		return result, nil
+	} else if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Status = string(status)
+	result.StatusInfo = info
+	result.StatusData = data
+	return result, nil
+}
+
+// SetSchedule is the API method that changes the backups schedule
+// and retention policy of the environment.
+func (a *API) SetSchedule(args params.BackupsSetScheduleArgs) error {
+	attrs := make(map[string]interface{})
+	var remove []string
+	if args.Schedule != nil {
+		if *args.Schedule == "" {
+			remove = append(remove, config.BackupsScheduleKey)
+		} else {
+			attrs[config.BackupsScheduleKey] = *args.Schedule
+		}
+	}
+	for key, value := range map[string]*int{
+		config.BackupsKeepLastKey:   args.KeepLast,
+		config.BackupsKeepDailyKey:  args.KeepDaily,
+		config.BackupsKeepWeeklyKey: args.KeepWeekly,
+	} {
+		switch {
+		case value == nil:
+		case *value == 0:
+			remove = append(remove, key)
+		default:
+			attrs[key] = *value
+		}
+	}
+	err := a.st.UpdateEnvironConfig(attrs, remove, nil)
+	return errors.Trace(err)
+}
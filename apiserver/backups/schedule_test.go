@@ -0,0 +1,81 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func (s *backupsSuite) TestScheduleNotScheduled(c *gc.C) {
+	result, err := s.api.Schedule()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result, jc.DeepEquals, params.BackupsScheduleResult{})
+}
+
+func (s *backupsSuite) TestSchedule(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"backups-schedule":   "@daily",
+		"backups-keep-daily": 7,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetBackupsScheduleStatus(state.StatusError, "cannot create backup: boom", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.api.Schedule()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.Schedule, gc.Equals, "@daily")
+	c.Check(result.KeepLast, gc.Equals, 0)
+	c.Check(result.KeepDaily, gc.Equals, 7)
+	c.Check(result.KeepWeekly, gc.Equals, 0)
+	c.Check(result.NextRun.Hour(), gc.Equals, 0)
+	c.Check(result.NextRun.Minute(), gc.Equals, 0)
+	c.Check(result.Status, gc.Equals, "error")
+	c.Check(result.StatusInfo, gc.Equals, "cannot create backup: boom")
+}
+
+func (s *backupsSuite) TestSetSchedule(c *gc.C) {
+	schedule, keepLast, keepWeekly := "0 3 * * *", 5, 4
+	err := s.api.SetSchedule(params.BackupsSetScheduleArgs{
+		Schedule:   &schedule,
+		KeepLast:   &keepLast,
+		KeepWeekly: &keepWeekly,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	cfg, err := s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	spec, ok := cfg.BackupsSchedule()
+	c.Check(ok, jc.IsTrue)
+	c.Check(spec, gc.Equals, "0 3 * * *")
+	c.Check(cfg.BackupsKeepLast(), gc.Equals, 5)
+	c.Check(cfg.BackupsKeepWeekly(), gc.Equals, 4)
+
+	// Empty and zero values unset the settings, while the others
+	// are left alone.
+	schedule, keepLast = "", 0
+	err = s.api.SetSchedule(params.BackupsSetScheduleArgs{
+		Schedule: &schedule,
+		KeepLast: &keepLast,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	cfg, err = s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok = cfg.BackupsSchedule()
+	c.Check(ok, jc.IsFalse)
+	c.Check(cfg.BackupsKeepLast(), gc.Equals, 0)
+	c.Check(cfg.BackupsKeepWeekly(), gc.Equals, 4)
+}
+
+func (s *backupsSuite) TestSetScheduleInvalid(c *gc.C) {
+	schedule := "every tuesday"
+	err := s.api.SetSchedule(params.BackupsSetScheduleArgs{
+		Schedule: &schedule,
+	})
+	c.Check(err, gc.ErrorMatches, "invalid backups-schedule: .*")
+}
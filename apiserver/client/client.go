@@ -19,6 +19,7 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/highavailability"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/manual"
 	"github.com/juju/juju/instance"
@@ -38,6 +39,7 @@ var (
 	logger = loggo.GetLogger("juju.apiserver.client")
 
 	newStateStorage = storage.NewStorage
+	newEnviron      = environs.New
 )
 
 type API struct {
@@ -234,7 +236,8 @@ func (c *Client) PrivateAddress(p params.PrivateAddress) (results params.Private
 }
 
 // ServiceExpose changes the juju-managed firewall to expose any ports that
-// were also explicitly marked by units as open.
+// were also explicitly marked by units as open, optionally restricted to
+// the given source CIDRs.
 // TODO(mattyw, all): This api call should be move to the new service facade. The client api version will then need bumping.
 func (c *Client) ServiceExpose(args params.ServiceExpose) error {
 	if err := c.check.ChangeAllowed(); err != nil {
@@ -244,7 +247,30 @@ func (c *Client) ServiceExpose(args params.ServiceExpose) error {
 	if err != nil {
 		return err
 	}
-	return svc.SetExposed()
+	if len(args.SourceCIDRs) > 0 {
+		if err := c.checkIngressRestrictions(); err != nil {
+			return errors.Annotatef(err, "cannot expose service %q", args.ServiceName)
+		}
+	}
+	return svc.SetExposed(args.SourceCIDRs...)
+}
+
+// checkIngressRestrictions returns an error satisfying
+// errors.IsNotSupported if the environment's firewall cannot restrict
+// opened ports to source CIDRs.
+func (c *Client) checkIngressRestrictions() error {
+	cfg, err := c.api.state.EnvironConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	env, err := newEnviron(cfg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if restricter, ok := env.(environs.IngressRestricter); !ok || !restricter.SupportsIngressRestrictions() {
+		return errors.NotSupportedf("restricting exposed ports to source CIDRs in %q provider", cfg.Type())
+	}
+	return nil
 }
 
 // ServiceUnexpose changes the juju-managed firewall to unexpose any ports that
@@ -344,6 +370,7 @@ func (c *Client) ServiceDeploy(args params.ServiceDeploy) error {
 			Constraints:    args.Constraints,
 			ToMachineSpec:  args.ToMachineSpec,
 			Networks:       requestedNetworks,
+			Storage:        args.Storage,
 		})
 	return err
 }
@@ -356,6 +383,15 @@ func (c *Client) ServiceDeployWithNetworks(args params.ServiceDeploy) error {
 	return c.ServiceDeploy(args)
 }
 
+// ServiceDeployWithStorage works exactly like ServiceDeploy, but
+// allows specifying the storage instances to create for each unit of
+// the service. Clients call it rather than ServiceDeploy when storage
+// is requested, so that older API servers, which would silently ignore
+// the storage directives, reject the call instead.
+func (c *Client) ServiceDeployWithStorage(args params.ServiceDeploy) error {
+	return c.ServiceDeploy(args)
+}
+
 // ServiceUpdate updates the service attributes, including charm URL,
 // minimum number of units, settings and constraints.
 // All parameters in params.ServiceUpdate except the service name are optional.
@@ -511,7 +547,7 @@ func addServiceUnits(state *state.State, args params.AddServiceUnits) ([]*state.
 	if args.NumUnits < 1 {
 		return nil, fmt.Errorf("must add at least one unit")
 	}
-	if args.NumUnits > 1 && args.ToMachineSpec != "" {
+	if args.NumUnits > 1 && args.ToMachineSpec != "" && !jjj.IsZonePlacement(args.ToMachineSpec) {
 		return nil, fmt.Errorf("cannot use NumUnits with ToMachineSpec")
 	}
 
@@ -932,6 +968,80 @@ func (c *Client) ShareEnvironment(args params.ModifyEnvironUsers) (result params
 	return result, nil
 }
 
+// ModifyEnvironmentAccess grants or revokes levels of access to the
+// environment for the given user(s). Granting access to a user who
+// cannot yet access the environment shares it with them; revoking
+// read access removes them from the environment altogether.
+func (c *Client) ModifyEnvironmentAccess(args params.ModifyEnvironAccess) (result params.ErrorResults, err error) {
+	var createdBy names.UserTag
+	var ok bool
+	if createdBy, ok = c.api.auth.GetAuthTag().(names.UserTag); !ok {
+		return result, errors.Errorf("api connection is not through a user")
+	}
+
+	result = params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Changes)),
+	}
+	if len(args.Changes) == 0 {
+		return result, nil
+	}
+
+	env, err := c.api.state.Environment()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, arg := range args.Changes {
+		err := c.modifyEnvironmentAccess(env.Owner(), createdBy, arg)
+		if err != nil {
+			err = errors.Annotatef(err, "could not %s access", arg.Action)
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+func (c *Client) modifyEnvironmentAccess(owner, createdBy names.UserTag, arg params.ModifyEnvironUserAccess) error {
+	user, err := names.ParseUserTag(arg.UserTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	access := state.EnvironmentAccess(arg.Access)
+	if err := access.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if user.Username() == owner.Username() {
+		return errors.Errorf("cannot change access for environment owner %q", user.Username())
+	}
+
+	envUser, err := c.api.state.EnvironmentUser(user)
+	if err != nil && !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	switch arg.Action {
+	case params.GrantEnvAccess:
+		if envUser == nil {
+			_, err := c.api.state.AddEnvironmentUserWithAccess(user, createdBy, access)
+			return errors.Trace(err)
+		}
+		if envUser.Access().Includes(access) {
+			return errors.Errorf("user %q already has %q access", user.Username(), access)
+		}
+		return errors.Trace(envUser.SetAccess(access))
+	case params.RevokeEnvAccess:
+		if envUser == nil || !envUser.Access().Includes(access) {
+			return errors.Errorf("user %q does not have %q access", user.Username(), access)
+		}
+		switch access {
+		case state.EnvironmentAdminAccess:
+			return errors.Trace(envUser.SetAccess(state.EnvironmentWriteAccess))
+		case state.EnvironmentWriteAccess:
+			return errors.Trace(envUser.SetAccess(state.EnvironmentReadAccess))
+		}
+		return errors.Trace(c.api.state.RemoveEnvironmentUser(user))
+	}
+	return errors.Errorf("unknown action %q", arg.Action)
+}
+
 // GetAnnotations returns annotations about a given entity.
 func (c *Client) GetAnnotations(args params.GetAnnotations) (params.GetAnnotationsResults, error) {
 	nothing := params.GetAnnotationsResults{}
@@ -27,6 +27,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/manual"
 	toolstesting "github.com/juju/juju/environs/tools/testing"
@@ -37,6 +38,7 @@ import (
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/state/presence"
 	"github.com/juju/juju/state/storage"
+	jujustorage "github.com/juju/juju/storage"
 	"github.com/juju/juju/testcharms"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
@@ -149,6 +151,85 @@ func (s *serverSuite) TestUnshareEnvironment(c *gc.C) {
 	c.Assert(errors.IsNotFound(err), jc.IsTrue)
 }
 
+func (s *serverSuite) modifyEnvironmentAccess(c *gc.C, user names.UserTag, action params.EnvironAccessAction, access state.EnvironmentAccess) error {
+	args := params.ModifyEnvironAccess{
+		Changes: []params.ModifyEnvironUserAccess{{
+			UserTag: user.String(),
+			Action:  action,
+			Access:  string(access),
+		}}}
+	result, err := s.client.ModifyEnvironmentAccess(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	return result.OneError()
+}
+
+func (s *serverSuite) assertEnvironmentAccess(c *gc.C, user names.UserTag, access state.EnvironmentAccess) {
+	envUser, err := s.State.EnvironmentUser(user)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, access)
+}
+
+func (s *serverSuite) TestGrantAccessAddsEnvironmentUser(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar", NoEnvUser: true})
+	err := s.modifyEnvironmentAccess(c, user.UserTag(), params.GrantEnvAccess, state.EnvironmentReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertEnvironmentAccess(c, user.UserTag(), state.EnvironmentReadAccess)
+	envUser, err := s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.CreatedBy(), gc.Equals, dummy.AdminUserTag().Username())
+}
+
+func (s *serverSuite) TestGrantAccessRaisesAccess(c *gc.C) {
+	user := s.Factory.MakeEnvUser(c, &factory.EnvUserParams{Access: state.EnvironmentReadAccess})
+	err := s.modifyEnvironmentAccess(c, user.UserTag(), params.GrantEnvAccess, state.EnvironmentAdminAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertEnvironmentAccess(c, user.UserTag(), state.EnvironmentAdminAccess)
+}
+
+func (s *serverSuite) TestGrantExistingAccessFails(c *gc.C) {
+	user := s.Factory.MakeEnvUser(c, &factory.EnvUserParams{Access: state.EnvironmentWriteAccess})
+	err := s.modifyEnvironmentAccess(c, user.UserTag(), params.GrantEnvAccess, state.EnvironmentReadAccess)
+	c.Assert(err, gc.ErrorMatches, `could not grant access: user ".*" already has "read" access`)
+	s.assertEnvironmentAccess(c, user.UserTag(), state.EnvironmentWriteAccess)
+}
+
+func (s *serverSuite) TestGrantInvalidAccessFails(c *gc.C) {
+	user := s.Factory.MakeEnvUser(c, nil)
+	err := s.modifyEnvironmentAccess(c, user.UserTag(), params.GrantEnvAccess, "superuser")
+	c.Assert(err, gc.ErrorMatches, `could not grant access: environment access "superuser" not valid`)
+}
+
+func (s *serverSuite) TestRevokeAccessLowersAccess(c *gc.C) {
+	user := s.Factory.MakeEnvUser(c, &factory.EnvUserParams{Access: state.EnvironmentAdminAccess})
+	err := s.modifyEnvironmentAccess(c, user.UserTag(), params.RevokeEnvAccess, state.EnvironmentWriteAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertEnvironmentAccess(c, user.UserTag(), state.EnvironmentReadAccess)
+}
+
+func (s *serverSuite) TestRevokeReadAccessRemovesEnvironmentUser(c *gc.C) {
+	user := s.Factory.MakeEnvUser(c, nil)
+	err := s.modifyEnvironmentAccess(c, user.UserTag(), params.RevokeEnvAccess, state.EnvironmentReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *serverSuite) TestRevokeMissingAccessFails(c *gc.C) {
+	user := s.Factory.MakeEnvUser(c, &factory.EnvUserParams{Access: state.EnvironmentReadAccess})
+	err := s.modifyEnvironmentAccess(c, user.UserTag(), params.RevokeEnvAccess, state.EnvironmentAdminAccess)
+	c.Assert(err, gc.ErrorMatches, `could not revoke access: user ".*" does not have "admin" access`)
+	s.assertEnvironmentAccess(c, user.UserTag(), state.EnvironmentReadAccess)
+}
+
+func (s *serverSuite) TestModifyOwnerAccessFails(c *gc.C) {
+	err := s.modifyEnvironmentAccess(c, s.AdminUserTag(c), params.RevokeEnvAccess, state.EnvironmentAdminAccess)
+	c.Assert(err, gc.ErrorMatches, `could not revoke access: cannot change access for environment owner ".*"`)
+	s.assertEnvironmentAccess(c, s.AdminUserTag(c), state.EnvironmentAdminAccess)
+}
+
 func (s *serverSuite) TestShareEnvironmentAddLocalUser(c *gc.C) {
 	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar", NoEnvUser: true})
 	args := params.ModifyEnvironUsers{
@@ -769,6 +850,15 @@ func (s *clientSuite) TestClientAddUnitToMachineNotFound(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `cannot add units for service "dummy" to machine 42: machine 42 not found`)
 }
 
+func (s *clientSuite) TestClientAddUnitsToZone(c *gc.C) {
+	s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	// Multiple units may be placed in a zone; each one is given a new
+	// machine carrying the placement directive, which the dummy
+	// provider's prechecker rejects.
+	_, err := s.APIState.Client().AddServiceUnits("dummy", 2, "zone=az1")
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "dummy/0" to machine: cannot add a new machine: zone=az1 placement is invalid`)
+}
+
 func (s *clientSuite) TestClientCharmInfo(c *gc.C) {
 	var clientCharmInfoTests = []struct {
 		about           string
@@ -1000,6 +1090,45 @@ func (s *clientSuite) TestClientServiceExpose(c *gc.C) {
 	}
 }
 
+func (s *clientSuite) TestClientServiceExposeSourceCIDRs(c *gc.C) {
+	s.AddTestingService(c, "dummy-service", s.AddTestingCharm(c, "dummy"))
+	err := s.APIState.Client().ServiceExpose("dummy-service", "10.0.0.0/8", "203.0.113.4/32")
+	c.Assert(err, jc.ErrorIsNil)
+	service, err := s.State.Service("dummy-service")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.IsExposed(), jc.IsTrue)
+	c.Assert(service.ExposedSourceCIDRs(), jc.DeepEquals, []string{"10.0.0.0/8", "203.0.113.4/32"})
+
+	err = s.APIState.Client().ServiceExpose("dummy-service", "bad")
+	c.Assert(err, gc.ErrorMatches, `cannot expose service "dummy-service": source CIDR "bad" not valid`)
+}
+
+// noIngressRestrictionsEnviron is an environ whose firewall cannot
+// restrict opened ports to source CIDRs.
+type noIngressRestrictionsEnviron struct {
+	environs.Environ
+}
+
+func (s *clientSuite) TestClientServiceExposeSourceCIDRsNotSupported(c *gc.C) {
+	s.PatchValue(client.NewEnviron, func(cfg *config.Config) (environs.Environ, error) {
+		env, err := environs.New(cfg)
+		return noIngressRestrictionsEnviron{env}, err
+	})
+	s.AddTestingService(c, "dummy-service", s.AddTestingCharm(c, "dummy"))
+	err := s.APIState.Client().ServiceExpose("dummy-service", "10.0.0.0/8")
+	c.Assert(err, gc.ErrorMatches, `cannot expose service "dummy-service": restricting exposed ports to source CIDRs in "dummy" provider not supported`)
+	service, err := s.State.Service("dummy-service")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.IsExposed(), jc.IsFalse)
+
+	// Exposing to all addresses still works.
+	err = s.APIState.Client().ServiceExpose("dummy-service")
+	c.Assert(err, jc.ErrorIsNil)
+	err = service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.IsExposed(), jc.IsTrue)
+}
+
 func (s *clientSuite) setupServiceExpose(c *gc.C) {
 	charm := s.AddTestingCharm(c, "dummy")
 	serviceNames := []string{"dummy-service", "exposed-service"}
@@ -1379,6 +1508,24 @@ func (s *clientSuite) TestClientServiceDeployWithNetworks(c *gc.C) {
 	c.Assert(serviceCons, gc.DeepEquals, cons)
 }
 
+func (s *clientSuite) TestClientServiceDeployWithStorage(c *gc.C) {
+	s.makeMockCharmStore()
+	curl, _ := addCharm(c, "dummy")
+	directives := []jujustorage.Directive{
+		{Name: "data", Source: "loop", Count: 2, Size: 1024},
+	}
+	err := s.APIState.Client().ServiceDeployWithStorage(
+		curl.String(), "service", 2, "", constraints.Value{}, "", nil, directives,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	service, err := s.State.Service("service")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.StorageDirectives(), jc.DeepEquals, directives)
+	instances, err := s.State.AllStorageInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instances, gc.HasLen, 4)
+}
+
 func (s *clientSuite) setupServiceDeploy(c *gc.C, args string) (*charm.URL, charm.Charm, constraints.Value) {
 	s.makeMockCharmStore()
 	curl, bundle := addCharm(c, "dummy")
@@ -8,6 +8,7 @@ var (
 	RemoteParamsForMachine  = remoteParamsForMachine
 	GetAllUnitNames         = getAllUnitNames
 	NewStateStorage         = &newStateStorage
+	NewEnviron              = &newEnviron
 )
 
 var MachineJobFromParams = machineJobFromParams
@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
 	"github.com/juju/juju/version"
 )
 
@@ -150,11 +151,11 @@ func (s *permSuite) TestOperationPerm(c *gc.C) {
 	}, {
 		about: "Client.EnvironmentSet",
 		op:    opClientEnvironmentSet,
-		allow: []names.Tag{userAdmin, userOther},
+		allow: []names.Tag{userAdmin},
 	}, {
 		about: "Client.SetEnvironAgentVersion",
 		op:    opClientSetEnvironAgentVersion,
-		allow: []names.Tag{userAdmin, userOther},
+		allow: []names.Tag{userAdmin},
 	}, {
 		about: "Client.WatchAll",
 		op:    opClientWatchAll,
@@ -189,6 +190,81 @@ func (s *permSuite) TestOperationPerm(c *gc.C) {
 	}
 }
 
+func (s *permSuite) TestReadAccessOperationPerm(c *gc.C) {
+	s.setUpScenario(c)
+	reader := s.Factory.MakeUser(c, &factory.UserParams{
+		Name:   "reader",
+		Access: state.EnvironmentReadAccess,
+	})
+	setDefaultPassword(c, reader)
+	for i, t := range []struct {
+		about string
+		op    func(c *gc.C, st *api.State, mst *state.State) (reset func(), err error)
+		allow bool
+	}{{
+		about: "Client.Status",
+		op:    opClientStatus,
+		allow: true,
+	}, {
+		about: "Client.ServiceGet",
+		op:    opClientServiceGet,
+		allow: true,
+	}, {
+		about: "Client.GetAnnotations",
+		op:    opClientGetAnnotations,
+		allow: true,
+	}, {
+		about: "Client.GetServiceConstraints",
+		op:    opClientGetServiceConstraints,
+		allow: true,
+	}, {
+		about: "Client.EnvironmentGet",
+		op:    opClientEnvironmentGet,
+		allow: true,
+	}, {
+		about: "Client.WatchAll",
+		op:    opClientWatchAll,
+		allow: true,
+	}, {
+		about: "Client.CharmInfo",
+		op:    opClientCharmInfo,
+		allow: true,
+	}, {
+		about: "Client.ServiceSet",
+		op:    opClientServiceSet,
+	}, {
+		about: "Client.ServiceDeploy",
+		op:    opClientServiceDeploy,
+	}, {
+		about: "Client.ServiceDestroy",
+		op:    opClientServiceDestroy,
+	}, {
+		about: "Client.SetAnnotations",
+		op:    opClientSetAnnotations,
+	}, {
+		about: "Client.AddRelation",
+		op:    opClientAddRelation,
+	}, {
+		about: "Client.DestroyMachines",
+		op:    opClientDestroyMachines,
+	}, {
+		about: "Client.EnvironmentSet",
+		op:    opClientEnvironmentSet,
+	}} {
+		c.Logf("test %d; %s", i, t.about)
+		st := s.openAs(c, reader.Tag())
+		reset, err := t.op(c, st, s.State)
+		if t.allow {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, "permission denied")
+			c.Check(err, jc.Satisfies, params.IsCodeUnauthorized)
+		}
+		reset()
+		st.Close()
+	}
+}
+
 func opClientCharmInfo(c *gc.C, st *api.State, mst *state.State) (func(), error) {
 	info, err := st.Client().CharmInfo("local:quantal/wordpress-3")
 	if err != nil {
@@ -447,6 +523,13 @@ func opClientSetEnvironAgentVersion(c *gc.C, st *api.State, mst *state.State) (f
 	}, nil
 }
 
+func opClientDestroyMachines(c *gc.C, st *api.State, mst *state.State) (func(), error) {
+	// This is only used where the call is expected to be refused,
+	// so there is nothing to undo.
+	err := st.Client().DestroyMachines("2")
+	return func() {}, err
+}
+
 func opClientWatchAll(c *gc.C, st *api.State, mst *state.State) (func(), error) {
 	watcher, err := st.Client().WatchAll()
 	if err == nil {
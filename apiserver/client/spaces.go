@@ -0,0 +1,82 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// AddSubnet adds a subnet to juju, optionally as part of an existing
+// space.
+func (c *Client) AddSubnet(args params.AddSubnet) error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	_, err := c.api.state.AddSubnet(state.SubnetInfo{
+		CIDR:             args.CIDR,
+		ProviderId:       args.ProviderId,
+		VLANTag:          args.VLANTag,
+		AvailabilityZone: args.Zone,
+		SpaceName:        args.Space,
+	})
+	return errors.Trace(err)
+}
+
+// ListSubnets returns the details of all subnets known to juju.
+func (c *Client) ListSubnets() (params.Subnets, error) {
+	subnets, err := c.api.state.AllSubnets()
+	if err != nil {
+		return params.Subnets{}, errors.Trace(err)
+	}
+	result := params.Subnets{
+		Subnets: make([]params.Subnet, len(subnets)),
+	}
+	for i, subnet := range subnets {
+		result.Subnets[i] = params.Subnet{
+			CIDR:       subnet.CIDR(),
+			ProviderId: subnet.ProviderId(),
+			VLANTag:    subnet.VLANTag(),
+			Zone:       subnet.AvailabilityZone(),
+			Space:      subnet.SpaceName(),
+			Life:       params.Life(subnet.Life().String()),
+		}
+	}
+	return result, nil
+}
+
+// CreateSpace creates a space holding the subnets with the given
+// CIDRs.
+func (c *Client) CreateSpace(args params.CreateSpace) error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	_, err := c.api.state.AddSpace(args.Name, args.Subnets)
+	return errors.Trace(err)
+}
+
+// ListSpaces returns all spaces, along with the CIDRs of the subnets
+// that belong to each.
+func (c *Client) ListSpaces() (params.Spaces, error) {
+	spaces, err := c.api.state.AllSpaces()
+	if err != nil {
+		return params.Spaces{}, errors.Trace(err)
+	}
+	result := params.Spaces{
+		Spaces: make([]params.Space, len(spaces)),
+	}
+	for i, space := range spaces {
+		subnets, err := space.Subnets()
+		if err != nil {
+			return params.Spaces{}, errors.Trace(err)
+		}
+		result.Spaces[i].Name = space.Name()
+		for _, subnet := range subnets {
+			result.Spaces[i].Subnets = append(result.Spaces[i].Subnets, subnet.CIDR())
+		}
+	}
+	return result, nil
+}
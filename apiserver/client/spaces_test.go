@@ -0,0 +1,81 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+type spacesSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&spacesSuite{})
+
+func (s *spacesSuite) TestAddAndListSubnets(c *gc.C) {
+	client := s.APIState.Client()
+	err := client.AddSubnet(params.AddSubnet{
+		CIDR:       "10.0.1.0/24",
+		ProviderId: "subnet-1",
+		VLANTag:    42,
+		Zone:       "zone1",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = client.AddSubnet(params.AddSubnet{CIDR: "10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	subnets, err := client.ListSubnets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnets, jc.DeepEquals, []params.Subnet{{
+		CIDR: "10.0.0.0/24",
+		Life: params.Alive,
+	}, {
+		CIDR:       "10.0.1.0/24",
+		ProviderId: "subnet-1",
+		VLANTag:    42,
+		Zone:       "zone1",
+		Life:       params.Alive,
+	}})
+}
+
+func (s *spacesSuite) TestAddSubnetErrors(c *gc.C) {
+	client := s.APIState.Client()
+	err := client.AddSubnet(params.AddSubnet{CIDR: "invalid"})
+	c.Assert(err, gc.ErrorMatches, "cannot add subnet invalid: invalid CIDR: invalid CIDR address: invalid")
+
+	err = client.AddSubnet(params.AddSubnet{CIDR: "10.0.0.0/24", Space: "db"})
+	c.Assert(err, gc.ErrorMatches, `cannot add subnet 10.0.0.0/24: space "db" not found`)
+	c.Assert(params.IsCodeNotFound(err), jc.IsTrue)
+}
+
+func (s *spacesSuite) TestCreateAndListSpaces(c *gc.C) {
+	client := s.APIState.Client()
+	for _, cidr := range []string{"10.0.0.0/24", "10.0.1.0/24"} {
+		err := client.AddSubnet(params.AddSubnet{CIDR: cidr})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err := client.CreateSpace("db", "10.0.1.0/24", "10.0.0.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	err = client.CreateSpace("admin")
+	c.Assert(err, jc.ErrorIsNil)
+	err = client.AddSubnet(params.AddSubnet{CIDR: "10.0.2.0/24", Space: "admin"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	spaces, err := client.ListSpaces()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spaces, jc.DeepEquals, []params.Space{{
+		Name:    "admin",
+		Subnets: []string{"10.0.2.0/24"},
+	}, {
+		Name:    "db",
+		Subnets: []string{"10.0.0.0/24", "10.0.1.0/24"},
+	}})
+
+	err = client.CreateSpace("db")
+	c.Assert(err, gc.ErrorMatches, `cannot add space "db": space "db" already exists`)
+	c.Assert(params.IsCodeAlreadyExists(err), jc.IsTrue)
+}
@@ -8,10 +8,12 @@ import (
 	"strings"
 
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	"github.com/juju/utils/set"
 	"gopkg.in/juju/charm.v4"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/network"
@@ -38,6 +40,10 @@ func (c *Client) FullStatus(args params.StatusParams) (api.Status, error) {
 	} else if context.networks, err = fetchNetworks(c.api.state); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch networks")
 	}
+	degraded, err := stateServersDegraded(c.api.state)
+	if err != nil {
+		return noStatus, errors.Annotate(err, "could not fetch state server info")
+	}
 
 	logger.Debugf("Services: %v", context.services)
 
@@ -118,9 +124,24 @@ func (c *Client) FullStatus(args params.StatusParams) (api.Status, error) {
 		Services:        context.processServices(),
 		Networks:        context.processNetworks(),
 		Relations:       context.processRelations(),
+
+		StateServersDegraded: degraded,
 	}, nil
 }
 
+// stateServersDegraded reports whether the state servers hosting
+// the given environment have been marked as degraded.
+func stateServersDegraded(st *state.State) (bool, error) {
+	info, err := st.StateServerInfo()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if info.EnvironmentTag != st.EnvironTag() {
+		return false, nil
+	}
+	return info.Degraded, nil
+}
+
 // Status is a stub version of FullStatus that was introduced in 1.16
 func (c *Client) Status() (api.LegacyStatus, error) {
 	var legacyStatus api.LegacyStatus
@@ -360,6 +381,9 @@ func makeMachineStatus(machine *state.Machine) (status api.MachineStatus) {
 		}
 	} else {
 		status.Hardware = hc.String()
+		if hc.AvailabilityZone != nil {
+			status.AvailabilityZone = *hc.AvailabilityZone
+		}
 	}
 	status.Containers = make(map[string]api.MachineStatus)
 	return
@@ -524,6 +548,7 @@ func (context *statusContext) processUnit(unit *state.Unit, serviceCharm string)
 		status.Charm = curl.String()
 	}
 	status.Agent, status.AgentState, status.AgentStateInfo = processAgent(unit)
+	status.WorkloadStatus, status.WorkloadStatusInfo = processWorkload(unit)
 	status.AgentVersion = status.Agent.Version
 	status.Life = status.Agent.Life
 	status.Err = status.Agent.Err
@@ -540,6 +565,16 @@ func (context *statusContext) processUnit(unit *state.Unit, serviceCharm string)
 	return
 }
 
+// processWorkload retrieves the status of the unit's workload as
+// reported by its charm.
+func processWorkload(unit *state.Unit) (params.Status, string) {
+	status, info, err := unit.WorkloadStatus()
+	if err != nil {
+		return params.StatusUnknown, ""
+	}
+	return params.Status(status), info
+}
+
 func (context *statusContext) unitByName(name string) *state.Unit {
 	serviceName := strings.Split(name, "/")[0]
 	return context.units[serviceName][name]
@@ -659,3 +694,45 @@ func processLife(entity lifer) string {
 	}
 	return ""
 }
+
+// StatusHistory returns the recorded status transitions of the given
+// unit or machine, newest first.
+func (c *Client) StatusHistory(args params.StatusHistory) (params.StatusHistoryResults, error) {
+	nothing := params.StatusHistoryResults{}
+	tag, err := names.ParseTag(args.Tag)
+	if err != nil {
+		return nothing, errors.Trace(err)
+	}
+	entity0, err := c.api.state.FindEntity(tag)
+	if err != nil {
+		return nothing, errors.Trace(err)
+	}
+	entity, ok := entity0.(statusHistoryGetter)
+	if !ok {
+		return nothing, common.NotSupportedError(tag, "status history")
+	}
+	filter := state.StatusHistoryFilter{Size: args.Size}
+	if args.Before != nil {
+		filter.Before = *args.Before
+	}
+	history, err := entity.StatusHistory(filter)
+	if err != nil {
+		return nothing, errors.Trace(err)
+	}
+	result := params.StatusHistoryResults{
+		Statuses: make([]params.StatusHistoryEntry, len(history)),
+	}
+	for i, info := range history {
+		result.Statuses[i] = params.StatusHistoryEntry{
+			Status:  params.Status(info.Status),
+			Info:    info.Info,
+			Data:    info.Data,
+			Updated: info.Updated,
+		}
+	}
+	return result, nil
+}
+
+type statusHistoryGetter interface {
+	StatusHistory(filter state.StatusHistoryFilter) ([]state.StatusInfo, error)
+}
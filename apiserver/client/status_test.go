@@ -4,10 +4,14 @@
 package client_test
 
 import (
+	"time"
+
+	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/client"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing/factory"
@@ -37,6 +41,7 @@ func (s *statusSuite) TestFullStatus(c *gc.C) {
 	c.Check(status.Services, gc.HasLen, 0)
 	c.Check(status.Machines, gc.HasLen, 1)
 	c.Check(status.Networks, gc.HasLen, 0)
+	c.Check(status.StateServersDegraded, jc.IsFalse)
 	resultMachine, ok := status.Machines[machine.Id()]
 	if !ok {
 		c.Fatalf("Missing machine with id %q", machine.Id())
@@ -45,6 +50,60 @@ func (s *statusSuite) TestFullStatus(c *gc.C) {
 	c.Check(resultMachine.Series, gc.Equals, machine.Series())
 }
 
+func (s *statusSuite) TestFullStatusAvailabilityZone(c *gc.C) {
+	machine := s.addMachine(c)
+	zone := "a-zone"
+	err := machine.SetProvisioned("i-zoned", "fake_nonce", &instance.HardwareCharacteristics{
+		AvailabilityZone: &zone,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	status, err := s.APIState.Client().Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	resultMachine, ok := status.Machines[machine.Id()]
+	c.Assert(ok, jc.IsTrue)
+	c.Check(resultMachine.AvailabilityZone, gc.Equals, "a-zone")
+}
+
+func (s *statusSuite) TestFullStatusStateServersDegraded(c *gc.C) {
+	err := s.State.SetStateServersDegraded(true)
+	c.Assert(err, jc.ErrorIsNil)
+	status, err := s.APIState.Client().Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(status.StateServersDegraded, jc.IsTrue)
+}
+
+func (s *statusSuite) TestStatusHistory(c *gc.C) {
+	machine := s.addMachine(c)
+	err := machine.SetStatus(state.StatusStarted, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetStatus(state.StatusError, "cannot start instance", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	client := s.APIState.Client()
+	history, err := client.StatusHistory(machine.Tag(), 0, time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 2)
+	c.Check(history[0].Status, gc.Equals, params.StatusError)
+	c.Check(history[0].Info, gc.Equals, "cannot start instance")
+	c.Check(history[1].Status, gc.Equals, params.StatusStarted)
+
+	history, err = client.StatusHistory(machine.Tag(), 1, time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Check(history[0].Status, gc.Equals, params.StatusError)
+
+	history, err = client.StatusHistory(machine.Tag(), 0, history[0].Updated)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Check(history[0].Status, gc.Equals, params.StatusStarted)
+}
+
+func (s *statusSuite) TestStatusHistoryNotSupported(c *gc.C) {
+	client := s.APIState.Client()
+	_, err := client.StatusHistory(names.NewEnvironTag(s.State.EnvironUUID()), 0, time.Time{})
+	c.Assert(err, gc.ErrorMatches, `entity "environment-.*" does not support status history`)
+}
+
 func (s *statusSuite) TestLegacyStatus(c *gc.C) {
 	machine := s.addMachine(c)
 	instanceId := "i-fakeinstance"
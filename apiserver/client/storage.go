@@ -0,0 +1,44 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// ListStorage returns the details of all storage instances in the
+// environment.
+func (c *Client) ListStorage() (params.StorageInstances, error) {
+	instances, err := c.api.state.AllStorageInstances()
+	if err != nil {
+		return params.StorageInstances{}, errors.Trace(err)
+	}
+	result := params.StorageInstances{
+		Instances: make([]params.StorageInstance, len(instances)),
+	}
+	for i, instance := range instances {
+		result.Instances[i] = common.StorageInstanceParams(instance)
+	}
+	return result, nil
+}
+
+// ShowStorage returns the details of the storage instances with the
+// given ids.
+func (c *Client) ShowStorage(args params.StorageInstanceIds) (params.StorageInstanceResults, error) {
+	results := params.StorageInstanceResults{
+		Results: make([]params.StorageInstanceResult, len(args.Ids)),
+	}
+	for i, id := range args.Ids {
+		instance, err := c.api.state.StorageInstance(id)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = common.StorageInstanceParams(instance)
+	}
+	return results, nil
+}
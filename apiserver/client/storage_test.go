@@ -0,0 +1,67 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/storage"
+)
+
+type storageSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&storageSuite{})
+
+func (s *storageSuite) SetUpTest(c *gc.C) {
+	s.baseSuite.SetUpTest(c)
+	service := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	err := service.SetStorageDirectives([]storage.Directive{
+		{Name: "data", Source: storage.LoopSource, Count: 1, Size: 1024},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *storageSuite) TestListStorage(c *gc.C) {
+	instances, err := s.APIState.Client().ListStorage()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instances, jc.DeepEquals, []params.StorageInstance{{
+		Id:     "data/0",
+		Name:   "data",
+		Owner:  "wordpress/0",
+		Source: "loop",
+		Size:   1024,
+	}})
+}
+
+func (s *storageSuite) TestShowStorage(c *gc.C) {
+	instance, err := s.State.StorageInstance("data/0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = instance.SetProvisioned("/srv/data", storage.Filesystem{Type: "ext4"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.APIState.Client().ShowStorage("data/0", "data/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.StorageInstanceResult{{
+		Result: params.StorageInstance{
+			Id:         "data/0",
+			Name:       "data",
+			Owner:      "wordpress/0",
+			Source:     "loop",
+			Size:       1024,
+			Location:   "/srv/data",
+			Filesystem: &storage.Filesystem{Type: "ext4"},
+		},
+	}, {
+		Error: &params.Error{
+			Code:    params.CodeNotFound,
+			Message: `storage instance "data/1" not found`,
+		},
+	}})
+}
@@ -23,11 +23,15 @@ type Resources struct {
 	mu        sync.Mutex
 	maxId     uint64
 	resources map[string]Resource
+	// registered holds the ids of the resources registered with
+	// Register rather than RegisterNamed.
+	registered map[string]bool
 }
 
 func NewResources() *Resources {
 	return &Resources{
-		resources: make(map[string]Resource),
+		resources:  make(map[string]Resource),
+		registered: make(map[string]bool),
 	}
 }
 
@@ -48,6 +52,7 @@ func (rs *Resources) Register(r Resource) string {
 	rs.maxId++
 	id := strconv.FormatUint(rs.maxId, 10)
 	rs.resources[id] = r
+	rs.registered[id] = true
 	return id
 }
 
@@ -90,6 +95,7 @@ func (rs *Resources) Stop(id string) error {
 	rs.mu.Lock()
 	defer rs.mu.Unlock()
 	delete(rs.resources, id)
+	delete(rs.registered, id)
 	return err
 }
 
@@ -103,6 +109,7 @@ func (rs *Resources) StopAll() {
 		}
 	}
 	rs.resources = make(map[string]Resource)
+	rs.registered = make(map[string]bool)
 }
 
 // Count returns the number of resources currently held.
@@ -112,6 +119,16 @@ func (rs *Resources) Count() int {
 	return len(rs.resources)
 }
 
+// CountRegistered returns the number of resources currently held
+// that were registered with Register, such as the watchers handed
+// out to clients. Resources registered with RegisterNamed are not
+// counted.
+func (rs *Resources) CountRegistered() int {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return len(rs.registered)
+}
+
 // StringResource is just a regular 'string' that matches the Resource
 // interface.
 type StringResource string
@@ -47,6 +47,25 @@ func (resourceSuite) TestRegisterNamedGetCount(c *gc.C) {
 	c.Check(rs.Get("fake1"), gc.Equals, r1)
 }
 
+func (resourceSuite) TestCountRegistered(c *gc.C) {
+	rs := common.NewResources()
+	defer rs.StopAll()
+	rs.Register(&fakeResource{})
+	id := rs.Register(&fakeResource{})
+	err := rs.RegisterNamed("fake1", &fakeResource{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(rs.Count(), gc.Equals, 3)
+	c.Check(rs.CountRegistered(), gc.Equals, 2)
+
+	err = rs.Stop(id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(rs.Count(), gc.Equals, 2)
+	c.Check(rs.CountRegistered(), gc.Equals, 1)
+
+	rs.StopAll()
+	c.Check(rs.CountRegistered(), gc.Equals, 0)
+}
+
 func (resourceSuite) TestRegisterNamedRepeatedName(c *gc.C) {
 	rs := common.NewResources()
 	defer rs.StopAll()
@@ -0,0 +1,24 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// StorageInstanceParams returns the API representation of the given
+// storage instance.
+func StorageInstanceParams(instance *state.StorageInstance) params.StorageInstance {
+	return params.StorageInstance{
+		Id:         instance.Id(),
+		Name:       instance.Name(),
+		Owner:      instance.Owner(),
+		Source:     instance.Source(),
+		Size:       instance.Size(),
+		Options:    instance.Options(),
+		Location:   instance.Location(),
+		Filesystem: instance.Filesystem(),
+	}
+}
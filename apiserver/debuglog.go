@@ -18,10 +18,12 @@ import (
 	"code.google.com/p/go.net/websocket"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
+	"github.com/juju/utils/featureflag"
 	"github.com/juju/utils/tailer"
 	"launchpad.net/tomb"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/feature"
 )
 
 // debugLogHandler takes requests to watch the debug log.
@@ -48,6 +50,9 @@ var maxLinesReached = fmt.Errorf("max lines reached")
 //      - has no meaning if 'replay' is true
 //   level -> string one of [TRACE, DEBUG, INFO, WARNING, ERROR]
 //   replay -> string - one of [true, false], if true, start the file from the start
+//
+// When the db-log feature flag is set, the log records stored in state
+// are streamed instead of the lines of all-machines.log.
 func (h *debugLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	server := websocket.Server{
 		Handler: func(socket *websocket.Conn) {
@@ -68,6 +73,10 @@ func (h *debugLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 				socket.Close()
 				return
 			}
+			if featureflag.Enabled(feature.DbLog) {
+				h.serveFromDB(socket, stream)
+				return
+			}
 			// Open log file.
 			logLocation := filepath.Join(h.logDir, "all-machines.log")
 			logFile, err := os.Open(logLocation)
@@ -16,6 +16,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing"
 )
 
@@ -453,3 +454,32 @@ func checkAgentParsing(c *gc.C, about, line, tag, name string) {
 	c.Assert(logLine.LogLineAgentTag(), gc.Equals, tag)
 	c.Assert(logLine.LogLineAgentName(), gc.Equals, name)
 }
+
+func (s *debugInternalSuite) TestEntityFilterTag(c *gc.C) {
+	for filter, expected := range map[string]string{
+		"machine-0":      "machine-0",
+		"unit-mysql-*":   "unit-mysql-*",
+		"0":              "machine-0",
+		"0/lxc/1":        "machine-0-lxc-1",
+		"1*":             "machine-1*",
+		"mysql/0":        "unit-mysql-0",
+		"mysql/*":        "unit-mysql-*",
+		"*":              "*",
+		"unknown-entity": "unknown-entity",
+	} {
+		c.Check(entityFilterTag(filter), gc.Equals, expected, gc.Commentf("filter %q", filter))
+	}
+}
+
+func (s *debugInternalSuite) TestFormatLogRecord(c *gc.C) {
+	line := formatLogRecord(state.LogRecord{
+		Time:     time.Date(2014, 3, 24, 22, 34, 25, 0, time.UTC),
+		Entity:   "machine-0",
+		Module:   "juju.cmd.jujud",
+		Location: "machine.go:127",
+		Level:    loggo.INFO,
+		Message:  "machine agent machine-0 start",
+	})
+	c.Assert(line, gc.Equals, "machine-0: 2014-03-24 22:34:25 INFO juju.cmd.jujud machine.go:127 machine agent machine-0 start\n")
+	c.Assert(parseLogLine(line).agentTag, gc.Equals, "machine-0")
+}
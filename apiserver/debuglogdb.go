@@ -0,0 +1,191 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"code.google.com/p/go.net/websocket"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/state"
+)
+
+// dbLogPollInterval holds how often the log records stored in state
+// are checked for new records to stream.
+var dbLogPollInterval = time.Second
+
+// serveFromDB streams the log records stored in state to the socket,
+// filtered according to the given log stream.
+func (h *debugLogHandler) serveFromDB(socket *websocket.Conn, stream *logStream) {
+	dbStream := newDBLogStream(h.state, stream)
+	if err := dbStream.position(); err != nil {
+		h.sendError(socket, fmt.Errorf("cannot position log stream: %v", err))
+		socket.Close()
+		return
+	}
+	if err := h.sendError(socket, nil); err != nil {
+		logger.Errorf("could not send good log stream start")
+		socket.Close()
+		return
+	}
+	go func() {
+		defer dbStream.tomb.Done()
+		defer socket.Close()
+		dbStream.tomb.Kill(dbStream.loop(socket))
+	}()
+	if err := dbStream.tomb.Wait(); err != nil {
+		if err != maxLinesReached {
+			logger.Errorf("debug-log handler error: %v", err)
+		}
+	}
+}
+
+// dbLogStream polls the log records stored in state and writes the
+// ones matching its filter to a writer, in the same format as lines
+// in all-machines.log.
+type dbLogStream struct {
+	tomb         tomb.Tomb
+	st           *state.State
+	filter       state.LogFilter
+	backlog      uint
+	fromTheStart bool
+	maxLines     uint
+	lineCount    uint
+	pending      []state.LogRecord
+}
+
+func newDBLogStream(st *state.State, stream *logStream) *dbLogStream {
+	return &dbLogStream{
+		st: st,
+		filter: state.LogFilter{
+			IncludeEntity: entityFilterTags(stream.includeEntity),
+			ExcludeEntity: entityFilterTags(stream.excludeEntity),
+			IncludeModule: stream.includeModule,
+			ExcludeModule: stream.excludeModule,
+			MinLevel:      stream.filterLevel,
+		},
+		backlog:      stream.backlog,
+		fromTheStart: stream.fromTheStart,
+		maxLines:     stream.maxLines,
+	}
+}
+
+// position determines the first record to be streamed: the first
+// stored record when replaying, and otherwise the first record stored
+// after the backlog of matching records.
+func (s *dbLogStream) position() error {
+	if s.fromTheStart {
+		return nil
+	}
+	if s.backlog > 0 {
+		filter := s.filter
+		filter.Last = int(s.backlog)
+		records, err := s.st.Logs(filter)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if len(records) > 0 {
+			s.pending = records
+			s.filter.After = records[len(records)-1].Id
+			return nil
+		}
+	}
+	records, err := s.st.Logs(state.LogFilter{Last: 1})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(records) > 0 {
+		s.filter.After = records[0].Id
+	}
+	return nil
+}
+
+// loop writes the backlog to the writer, and then polls for new
+// matching records until the stream is stopped or the maximum number
+// of lines has been written.
+func (s *dbLogStream) loop(w io.Writer) error {
+	if err := s.write(w, s.pending); err != nil {
+		return err
+	}
+	s.pending = nil
+	for {
+		if s.fromTheStart {
+			// The first poll replays all stored records.
+			s.fromTheStart = false
+		} else {
+			select {
+			case <-s.tomb.Dying():
+				return nil
+			case <-time.After(dbLogPollInterval):
+			}
+		}
+		records, err := s.st.Logs(s.filter)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := s.write(w, records); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *dbLogStream) write(w io.Writer, records []state.LogRecord) error {
+	for _, record := range records {
+		if _, err := io.WriteString(w, formatLogRecord(record)); err != nil {
+			return err
+		}
+		s.filter.After = record.Id
+		s.lineCount++
+		if s.maxLines > 0 && s.lineCount >= s.maxLines {
+			return maxLinesReached
+		}
+	}
+	return nil
+}
+
+// formatLogRecord formats the record as rsyslog writes it to
+// all-machines.log.
+func formatLogRecord(record state.LogRecord) string {
+	return fmt.Sprintf("%s: %s %s %s %s %s\n",
+		record.Entity,
+		record.Time.UTC().Format("2006-01-02 15:04:05"),
+		record.Level,
+		record.Module,
+		record.Location,
+		record.Message,
+	)
+}
+
+// entityFilterTags converts entity filters given as entity names, as
+// in "mysql/0", "mysql/*" or "1", into the corresponding tag filters.
+// Filters that are already tags are returned unchanged.
+func entityFilterTags(filters []string) []string {
+	if len(filters) == 0 {
+		return nil
+	}
+	tags := make([]string, len(filters))
+	for i, filter := range filters {
+		tags[i] = entityFilterTag(filter)
+	}
+	return tags
+}
+
+func entityFilterTag(filter string) string {
+	switch {
+	case strings.HasPrefix(filter, names.MachineTagKind+"-"),
+		strings.HasPrefix(filter, names.UnitTagKind+"-"):
+		return filter
+	case filter != "" && filter[0] >= '0' && filter[0] <= '9':
+		return names.MachineTagKind + "-" + strings.Replace(filter, "/", "-", -1)
+	case strings.Contains(filter, "/"):
+		return names.UnitTagKind + "-" + strings.Replace(filter, "/", "-", -1)
+	}
+	return filter
+}
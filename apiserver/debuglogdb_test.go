@@ -0,0 +1,131 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"io"
+	"net/url"
+	"time"
+
+	"code.google.com/p/go.net/websocket"
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/feature"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+)
+
+type debugLogDBSuite struct {
+	authHttpSuite
+}
+
+var _ = gc.Suite(&debugLogDBSuite{})
+
+func (s *debugLogDBSuite) SetUpTest(c *gc.C) {
+	s.authHttpSuite.SetUpTest(c)
+	s.SetFeatureFlags(feature.DbLog)
+	s.PatchValue(apiserver.DBLogPollInterval, 10*time.Millisecond)
+}
+
+var dbLogTime = time.Date(2014, 11, 5, 10, 0, 0, 0, time.UTC)
+
+func (s *debugLogDBSuite) addLog(c *gc.C, entity, message string) {
+	err := s.State.AddLogs([]state.LogRecord{{
+		Time:     dbLogTime,
+		Entity:   entity,
+		Module:   "juju.worker",
+		Location: "worker.go:42",
+		Level:    loggo.INFO,
+		Message:  message,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func dbLogLine(entity, message string) string {
+	return entity + ": 2014-11-05 10:00:00 INFO juju.worker worker.go:42 " + message
+}
+
+func (s *debugLogDBSuite) openWebsocket(c *gc.C, values url.Values) *bufio.Reader {
+	server := s.baseURL(c)
+	server.Scheme = "wss"
+	server.Path = "/log"
+	server.RawQuery = values.Encode()
+	config, err := websocket.NewConfig(server.String(), "http://localhost/")
+	c.Assert(err, jc.ErrorIsNil)
+	config.Header = utils.BasicAuthHeader(s.userTag, s.password)
+	caCerts := x509.NewCertPool()
+	c.Assert(caCerts.AppendCertsFromPEM([]byte(testing.CACert)), jc.IsTrue)
+	config.TlsConfig = &tls.Config{RootCAs: caCerts, ServerName: "anything"}
+	conn, err := websocket.DialConfig(config)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(_ *gc.C) { conn.Close() })
+
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadSlice('\n')
+	c.Assert(err, jc.ErrorIsNil)
+	var errResult params.ErrorResult
+	err = json.Unmarshal(line, &errResult)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errResult.Error, gc.IsNil)
+	return reader
+}
+
+func readLines(c *gc.C, reader *bufio.Reader, count int) []string {
+	var lines []string
+	for len(lines) < count {
+		line, err := reader.ReadString('\n')
+		c.Assert(err, jc.ErrorIsNil)
+		lines = append(lines, line[:len(line)-1])
+	}
+	return lines
+}
+
+func (s *debugLogDBSuite) TestReadsFromEnd(c *gc.C) {
+	s.addLog(c, "machine-0", "old")
+	reader := s.openWebsocket(c, nil)
+	s.addLog(c, "machine-0", "new")
+	c.Assert(readLines(c, reader, 1), jc.DeepEquals, []string{
+		dbLogLine("machine-0", "new"),
+	})
+}
+
+func (s *debugLogDBSuite) TestReplay(c *gc.C) {
+	s.addLog(c, "machine-0", "first")
+	s.addLog(c, "unit-mysql-0", "second")
+	reader := s.openWebsocket(c, url.Values{"replay": {"true"}})
+	s.addLog(c, "machine-1", "third")
+	c.Assert(readLines(c, reader, 3), jc.DeepEquals, []string{
+		dbLogLine("machine-0", "first"),
+		dbLogLine("unit-mysql-0", "second"),
+		dbLogLine("machine-1", "third"),
+	})
+}
+
+func (s *debugLogDBSuite) TestBacklogWithFilterAndMaxLines(c *gc.C) {
+	s.addLog(c, "unit-mysql-0", "first")
+	s.addLog(c, "unit-mysql-0", "second")
+	s.addLog(c, "machine-0", "machine")
+	reader := s.openWebsocket(c, url.Values{
+		"includeEntity": {"mysql/0"},
+		"backlog":       {"1"},
+		"maxLines":      {"2"},
+	})
+	s.addLog(c, "machine-0", "machine again")
+	s.addLog(c, "unit-mysql-0", "third")
+	c.Assert(readLines(c, reader, 2), jc.DeepEquals, []string{
+		dbLogLine("unit-mysql-0", "second"),
+		dbLogLine("unit-mysql-0", "third"),
+	})
+	_, err := reader.ReadByte()
+	c.Assert(err, gc.Equals, io.EOF)
+}
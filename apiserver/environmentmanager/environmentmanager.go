@@ -0,0 +1,263 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The environmentmanager package defines an API end point for functions
+// dealing with the environments hosted by a state server.
+package environmentmanager
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.environmentmanager")
+
+func init() {
+	common.RegisterStandardFacade("EnvironmentManager", 1, NewEnvironmentManagerAPI)
+}
+
+// EnvironmentManager defines the methods on the environmentmanager API end
+// point.
+type EnvironmentManager interface {
+	CreateEnvironment(args params.EnvironmentCreateArgs) (params.Environment, error)
+	ListEnvironments(user params.Entity) (params.EnvironmentList, error)
+}
+
+// EnvironmentManagerAPI implements the environment manager interface and is
+// the concrete implementation of the api end point.
+type EnvironmentManagerAPI struct {
+	state      *state.State
+	authorizer common.Authorizer
+	check      *common.BlockChecker
+}
+
+var _ EnvironmentManager = (*EnvironmentManagerAPI)(nil)
+
+// NewEnvironmentManagerAPI creates a new api server endpoint for managing
+// environments.
+func NewEnvironmentManagerAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*EnvironmentManagerAPI, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+
+	return &EnvironmentManagerAPI{
+		state:      st,
+		authorizer: authorizer,
+		check:      common.NewBlockChecker(st),
+	}, nil
+}
+
+// authCheck checks that the logged in user may act on behalf of the
+// given user.
+func (em *EnvironmentManagerAPI) authCheck(user names.UserTag) error {
+	authTag, ok := em.authorizer.GetAuthTag().(names.UserTag)
+	if !ok {
+		return errors.Trace(common.ErrPerm)
+	}
+	if authTag == user {
+		return nil
+	}
+	// TODO(thumper): PERMISSIONS Change this permission check when we have
+	// real permissions. For now, the owner of the state server environment
+	// may act on behalf of any user.
+	ssEnv, err := em.state.StateServerEnvironment()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if authTag != ssEnv.Owner() {
+		return errors.Trace(common.ErrPerm)
+	}
+	return nil
+}
+
+// restrictedConfigKeys holds the attributes of a new environment that must
+// match the state server environment, as hosted environments share the
+// state server's provider and API server.
+var restrictedConfigKeys = []string{
+	"type",
+	"ca-cert",
+	"state-port",
+	"api-port",
+	"syslog-port",
+}
+
+// inheritedConfigKeys holds the generic environment settings that a new
+// environment takes from the state server environment when they are not
+// specified. Secrets and provider-specific settings are never inherited;
+// they must be supplied by the caller.
+var inheritedConfigKeys = []string{
+	"type",
+	"ca-cert",
+	"state-port",
+	"api-port",
+	"syslog-port",
+	"authorized-keys",
+	"agent-version",
+	"agent-stream",
+	"agent-metadata-url",
+	"image-stream",
+	"image-metadata-url",
+	"default-series",
+	"firewall-mode",
+	"development",
+	"logging-config",
+	"ssl-hostname-verification",
+}
+
+// stateServerConfig returns the configuration of the state server
+// environment.
+func (em *EnvironmentManagerAPI) stateServerConfig() (*config.Config, error) {
+	ssEnv, err := em.state.StateServerEnvironment()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ssState, err := em.state.ForEnviron(ssEnv.EnvironTag())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer ssState.Close()
+	return ssState.EnvironConfig()
+}
+
+// newEnvironmentConfig returns the configuration of a new environment
+// with the given attributes. Generic attributes that are not given are
+// taken from the state server environment, and the new environment gets
+// a UUID of its own.
+func (em *EnvironmentManagerAPI) newEnvironmentConfig(attrs map[string]interface{}) (*config.Config, error) {
+	ssConfig, err := em.stateServerConfig()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get state server environment config")
+	}
+	ssAttrs := ssConfig.AllAttrs()
+	for _, key := range restrictedConfigKeys {
+		// Values sent over the API may not have the same type as the
+		// stored ones, so they are compared in their printed form.
+		value, ok := attrs[key]
+		if ok && fmt.Sprint(value) != fmt.Sprint(ssAttrs[key]) {
+			return nil, errors.Errorf("specified %s %q does not match the state server's %q", key, value, ssAttrs[key])
+		}
+	}
+	if _, ok := attrs["name"]; !ok {
+		return nil, errors.New("environment name not specified")
+	}
+
+	newAttrs := make(map[string]interface{})
+	for _, key := range inheritedConfigKeys {
+		if value, ok := ssAttrs[key]; ok {
+			newAttrs[key] = value
+		}
+	}
+	for key, value := range attrs {
+		newAttrs[key] = value
+	}
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot generate environment uuid")
+	}
+	newAttrs["uuid"] = uuid.String()
+
+	cfg, err := config.New(config.NoDefaults, newAttrs)
+	if err != nil {
+		return nil, errors.Annotate(err, "environment config not valid")
+	}
+	provider, err := environs.Provider(cfg.Type())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cfg, err = provider.Validate(cfg, nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "environment config not valid")
+	}
+	return cfg, nil
+}
+
+// CreateEnvironment creates a new environment hosted by the state server,
+// using the state server environment's configuration for any generic
+// attributes that are not specified. Credentials and other provider
+// settings must be given in the arguments.
+func (em *EnvironmentManagerAPI) CreateEnvironment(args params.EnvironmentCreateArgs) (params.Environment, error) {
+	var result params.Environment
+	if err := em.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	ownerTag, err := names.ParseUserTag(args.OwnerTag)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	if err := em.authCheck(ownerTag); err != nil {
+		return result, errors.Trace(err)
+	}
+	// Only local users can own environments for now.
+	if _, err := em.state.User(ownerTag); err != nil {
+		return result, errors.Annotate(err, "cannot get owner")
+	}
+
+	cfg, err := em.newEnvironmentConfig(args.Config)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	existing, err := em.state.EnvironmentsForUser(ownerTag)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for _, env := range existing {
+		if env.Owner() == ownerTag && env.Name() == cfg.Name() {
+			return result, errors.AlreadyExistsf("environment %q for %s", cfg.Name(), ownerTag.Username())
+		}
+	}
+
+	env, st, err := em.state.NewEnvironment(cfg, ownerTag)
+	if err != nil {
+		return result, errors.Annotate(err, "cannot create environment")
+	}
+	defer st.Close()
+	logger.Infof("created environment %q (%s) for %s", env.Name(), env.UUID(), ownerTag.Username())
+
+	return environmentParams(env), nil
+}
+
+// ListEnvironments returns the environments that the specified user has
+// access to.
+func (em *EnvironmentManagerAPI) ListEnvironments(user params.Entity) (params.EnvironmentList, error) {
+	result := params.EnvironmentList{}
+	userTag, err := names.ParseUserTag(user.Tag)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	if err := em.authCheck(userTag); err != nil {
+		return result, errors.Trace(err)
+	}
+
+	environments, err := em.state.EnvironmentsForUser(userTag)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for _, env := range environments {
+		result.Environments = append(result.Environments, environmentParams(env))
+	}
+	return result, nil
+}
+
+func environmentParams(env *state.Environment) params.Environment {
+	return params.Environment{
+		Name:       env.Name(),
+		UUID:       env.UUID(),
+		OwnerTag:   env.Owner().String(),
+		ServerUUID: env.ServerTag().Id(),
+		Life:       env.Life().String(),
+	}
+}
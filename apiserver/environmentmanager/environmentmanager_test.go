@@ -0,0 +1,203 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environmentmanager_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/environmentmanager"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type envManagerSuite struct {
+	jujutesting.JujuConnSuite
+
+	envmanager *environmentmanager.EnvironmentManagerAPI
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&envManagerSuite{})
+
+func (s *envManagerSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	var err error
+	s.envmanager, err = environmentmanager.NewEnvironmentManagerAPI(s.State, nil, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *envManagerSuite) TestNewAPIRefusesNonClient(c *gc.C) {
+	anAuthoriser := s.authorizer
+	anAuthoriser.Tag = names.NewUnitTag("mysql/0")
+	endPoint, err := environmentmanager.NewEnvironmentManagerAPI(s.State, nil, anAuthoriser)
+	c.Assert(endPoint, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *envManagerSuite) createArgs(owner names.UserTag) params.EnvironmentCreateArgs {
+	return params.EnvironmentCreateArgs{
+		OwnerTag: owner.String(),
+		Config: map[string]interface{}{
+			"name":         "test-env",
+			"state-server": false,
+		},
+	}
+}
+
+func (s *envManagerSuite) TestCreateEnvironment(c *gc.C) {
+	owner := s.AdminUserTag(c)
+	result, err := s.envmanager.CreateEnvironment(s.createArgs(owner))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Name, gc.Equals, "test-env")
+	c.Assert(result.OwnerTag, gc.Equals, owner.String())
+	c.Assert(result.ServerUUID, gc.Equals, s.State.EnvironUUID())
+	c.Assert(result.UUID, gc.Not(gc.Equals), s.State.EnvironUUID())
+	c.Assert(result.Life, gc.Equals, "alive")
+
+	env, err := s.State.GetEnvironment(names.NewEnvironTag(result.UUID))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env.Name(), gc.Equals, "test-env")
+	c.Assert(env.Owner(), gc.Equals, owner)
+
+	// The new environment's config is based on the state server's.
+	st, err := s.State.ForEnviron(env.EnvironTag())
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+	cfg, err := st.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	ssCfg, err := s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	uuid, _ := cfg.UUID()
+	c.Assert(uuid, gc.Equals, result.UUID)
+	c.Assert(cfg.Type(), gc.Equals, ssCfg.Type())
+	c.Assert(cfg.AuthorizedKeys(), gc.Equals, ssCfg.AuthorizedKeys())
+}
+
+func (s *envManagerSuite) TestCreateEnvironmentDoesNotInheritSecrets(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"secret":       "state-server-secret",
+		"admin-secret": "top-secret",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := s.envmanager.CreateEnvironment(s.createArgs(s.AdminUserTag(c)))
+	c.Assert(err, jc.ErrorIsNil)
+
+	st, err := s.State.ForEnviron(names.NewEnvironTag(result.UUID))
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+	cfg, err := st.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.AllAttrs()["secret"], gc.Equals, "pork")
+	c.Assert(cfg.AdminSecret(), gc.Equals, "")
+}
+
+func (s *envManagerSuite) TestCreateEnvironmentMissingProviderConfig(c *gc.C) {
+	args := s.createArgs(s.AdminUserTag(c))
+	delete(args.Config, "state-server")
+	_, err := s.envmanager.CreateEnvironment(args)
+	c.Assert(err, gc.ErrorMatches, "environment config not valid: .*state-server.*")
+}
+
+func (s *envManagerSuite) TestCreateEnvironmentDuplicateName(c *gc.C) {
+	owner := s.AdminUserTag(c)
+	_, err := s.envmanager.CreateEnvironment(s.createArgs(owner))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.envmanager.CreateEnvironment(s.createArgs(owner))
+	c.Assert(err, gc.ErrorMatches, `environment "test-env" for admin@local already exists`)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *envManagerSuite) TestCreateEnvironmentRestrictedConfig(c *gc.C) {
+	args := s.createArgs(s.AdminUserTag(c))
+	args.Config["type"] = "ec2"
+	_, err := s.envmanager.CreateEnvironment(args)
+	c.Assert(err, gc.ErrorMatches, `specified type "ec2" does not match the state server's "dummy"`)
+}
+
+func (s *envManagerSuite) TestCreateEnvironmentSameRestrictedConfig(c *gc.C) {
+	cfg, err := s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	args := s.createArgs(s.AdminUserTag(c))
+	args.Config["type"] = "dummy"
+	args.Config["state-port"] = float64(cfg.StatePort())
+	_, err = s.envmanager.CreateEnvironment(args)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *envManagerSuite) TestCreateEnvironmentNoName(c *gc.C) {
+	args := s.createArgs(s.AdminUserTag(c))
+	delete(args.Config, "name")
+	_, err := s.envmanager.CreateEnvironment(args)
+	c.Assert(err, gc.ErrorMatches, "environment name not specified")
+}
+
+func (s *envManagerSuite) TestCreateEnvironmentUnknownOwner(c *gc.C) {
+	owner := names.NewLocalUserTag("nobody")
+	_, err := s.envmanager.CreateEnvironment(s.createArgs(owner))
+	c.Assert(err, gc.ErrorMatches, `cannot get owner: user "nobody" not found`)
+}
+
+func (s *envManagerSuite) TestCreateEnvironmentForOtherUser(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
+	envmanager, err := environmentmanager.NewEnvironmentManagerAPI(
+		s.State, nil, apiservertesting.FakeAuthorizer{Tag: alex.Tag()})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = envmanager.CreateEnvironment(s.createArgs(s.AdminUserTag(c)))
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+
+	// The state server environment's owner may create environments for
+	// other users.
+	result, err := s.envmanager.CreateEnvironment(s.createArgs(alex.UserTag()))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OwnerTag, gc.Equals, alex.Tag().String())
+}
+
+func (s *envManagerSuite) TestBlockCreateEnvironment(c *gc.C) {
+	s.AssertConfigParameterUpdated(c, "block-all-changes", true)
+	_, err := s.envmanager.CreateEnvironment(s.createArgs(s.AdminUserTag(c)))
+	c.Assert(errors.Cause(err), gc.ErrorMatches, common.ErrOperationBlocked.Error())
+}
+
+func (s *envManagerSuite) TestListEnvironments(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex", NoEnvUser: true})
+	st := s.Factory.MakeEnvironment(c, &factory.EnvParams{
+		Name: "alex-env", Owner: alex.UserTag(),
+	})
+	defer st.Close()
+
+	result, err := s.envmanager.ListEnvironments(params.Entity{alex.Tag().String()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Environments, jc.DeepEquals, []params.Environment{{
+		Name:       "alex-env",
+		UUID:       st.EnvironUUID(),
+		OwnerTag:   alex.Tag().String(),
+		ServerUUID: s.State.EnvironUUID(),
+		Life:       "alive",
+	}})
+
+	result, err = s.envmanager.ListEnvironments(params.Entity{s.AdminUserTag(c).String()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Environments, gc.HasLen, 1)
+	c.Assert(result.Environments[0].UUID, gc.Equals, s.State.EnvironUUID())
+}
+
+func (s *envManagerSuite) TestListEnvironmentsForOtherUser(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
+	envmanager, err := environmentmanager.NewEnvironmentManagerAPI(
+		s.State, nil, apiservertesting.FakeAuthorizer{Tag: alex.Tag()})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = envmanager.ListEnvironments(params.Entity{s.AdminUserTag(c).String()})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
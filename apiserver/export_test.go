@@ -6,9 +6,11 @@ package apiserver
 import (
 	"fmt"
 	"reflect"
+	"sort"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/audit"
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/state"
 	"github.com/juju/names"
@@ -22,8 +24,39 @@ var (
 	NewBackups            = &newBackups
 	ParseLogLine          = parseLogLine
 	AgentMatchesFilter    = agentMatchesFilter
+	DBLogPollInterval     = &dbLogPollInterval
+	StateMetricsTTL       = &stateMetricsTTL
 )
 
+// NewAuditor returns an auditor writing to the given state.
+func NewAuditor(st *state.State) *auditor {
+	return newAuditor(st)
+}
+
+// RunAuditor writes the entries queued on the auditor until the dying
+// channel is closed.
+func RunAuditor(a *auditor, dying <-chan struct{}) {
+	a.run(dying)
+}
+
+// AddAuditEntry queues the entry on the auditor.
+func AddAuditEntry(a *auditor, entry audit.Entry) {
+	a.add(entry)
+}
+
+// AuditorHostedEnvironments returns the UUIDs of the hosted environments
+// whose states the auditor holds open.
+func AuditorHostedEnvironments(a *auditor) []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var uuids []string
+	for uuid := range a.hosted {
+		uuids = append(uuids, uuid)
+	}
+	sort.Strings(uuids)
+	return uuids
+}
+
 func ApiHandlerWithEntity(entity state.Entity) *apiHandler {
 	return &apiHandler{entity: entity}
 }
@@ -56,8 +89,7 @@ func NewErrRoot(err error) *errRoot {
 // Just enough to let you probe some of the interfaces of ApiHandler, but not
 // enough to actually do any RPC calls
 func TestingApiRoot(st *state.State) rpc.MethodFinder {
-	srv := &Server{state: st}
-	h := newApiRoot(srv, common.NewResources(), nil)
+	h := newApiRoot(st, common.NewResources(), nil)
 	return h
 }
 
@@ -68,6 +100,13 @@ func TestingUpgradingRoot(st *state.State) rpc.MethodFinder {
 	return newUpgradingRoot(r)
 }
 
+// TestingAccessRoot returns a limited access root for a user with the
+// given level of access to the environment.
+func TestingAccessRoot(st *state.State, access state.EnvironmentAccess) rpc.MethodFinder {
+	r := TestingApiRoot(st)
+	return newAccessRoot(r, access)
+}
+
 type preFacadeAdminApi struct{}
 
 func newPreFacadeAdminApi(srv *Server, root *apiHandler, reqNotifier *requestNotifier) interface{} {
@@ -0,0 +1,10 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package highavailability
+
+var (
+	GetZonedEnviron   = &getZonedEnviron
+	ZonePlacement     = zonePlacement
+	SpreadAcrossZones = spreadAcrossZones
+)
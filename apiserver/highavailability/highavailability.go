@@ -11,6 +11,10 @@ import (
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	providercommon "github.com/juju/juju/provider/common"
+	"github.com/juju/juju/replicaset"
 	"github.com/juju/juju/state"
 )
 
@@ -111,9 +115,100 @@ func EnsureAvailabilitySingle(st *state.State, spec params.StateServersSpec) (pa
 		}
 		series = templateMachine.Series()
 	}
-	changes, err := st.EnsureAvailability(spec.NumStateServers, spec.Constraints, series, spec.Placement)
+	placement := spec.Placement
+	if len(placement) == 0 {
+		var err error
+		placement, err = zonePlacement(st)
+		if err != nil {
+			return params.StateServersChanges{}, errors.Annotate(err, "cannot spread state servers across availability zones")
+		}
+	}
+	changes, err := st.EnsureAvailability(spec.NumStateServers, spec.Constraints, series, placement)
 	if err != nil {
 		return params.StateServersChanges{}, err
 	}
 	return stateServersChanges(changes), nil
 }
+
+// getZonedEnviron returns the environment's provider if it supports
+// availability zones, and nil otherwise.
+var getZonedEnviron = func(st *state.State) (providercommon.ZonedEnviron, error) {
+	cfg, err := st.EnvironConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	env, err := environs.New(cfg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if zoned, ok := env.(providercommon.ZonedEnviron); ok {
+		return zoned, nil
+	}
+	return nil, nil
+}
+
+// zonePlacement returns placement directives that will spread any
+// new state server machines across the environment's availability
+// zones, starting with the zones holding the fewest existing state
+// servers. EnsureAvailability ignores any directives it does not need.
+// No directives are returned if the environment does not support
+// availability zones.
+func zonePlacement(st *state.State) ([]string, error) {
+	env, err := getZonedEnviron(st)
+	if err != nil || env == nil {
+		return nil, err
+	}
+	info, err := st.StateServerInfo()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var group []instance.Id
+	for _, id := range info.MachineIds {
+		m, err := st.Machine(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		instId, err := m.InstanceId()
+		if state.IsNotProvisionedError(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		group = append(group, instId)
+	}
+	if len(group) == 0 {
+		// AvailabilityZoneAllocations would consider every
+		// instance in the environment, not just state servers.
+		return nil, nil
+	}
+	zones, err := providercommon.AvailabilityZoneAllocations(env, group)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return spreadAcrossZones(zones, replicaset.MaxPeers), nil
+}
+
+// spreadAcrossZones returns count zone placement directives, each
+// choosing the zone with the lowest population after the previous
+// directives have been taken into account.
+func spreadAcrossZones(zones []providercommon.AvailabilityZoneInstances, count int) []string {
+	if len(zones) == 0 {
+		return nil
+	}
+	population := make([]int, len(zones))
+	for i, zone := range zones {
+		population[i] = len(zone.Instances)
+	}
+	placement := make([]string, count)
+	for i := range placement {
+		best := 0
+		for j := range zones {
+			if population[j] < population[best] {
+				best = j
+			}
+		}
+		population[best]++
+		placement[i] = "zone=" + zones[best].ZoneName
+	}
+	return placement
+}
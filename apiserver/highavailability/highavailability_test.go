@@ -14,7 +14,10 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju/testing"
+	providercommon "github.com/juju/juju/provider/common"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/presence"
 	coretesting "github.com/juju/juju/testing"
@@ -269,3 +272,81 @@ func (s *clientSuite) TestEnsureAvailabilityErrors(c *gc.C) {
 	_, err = s.ensureAvailability(c, 1, emptyCons, defaultSeries, nil)
 	c.Assert(err, gc.ErrorMatches, "failed to create new state server machines: cannot reduce state server count")
 }
+
+func (s *clientSuite) TestZonePlacementWithoutZones(c *gc.C) {
+	placement, err := highavailability.ZonePlacement(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(placement, gc.IsNil)
+}
+
+func (s *clientSuite) TestZonePlacementSpreadsAcrossZones(c *gc.C) {
+	env := &fakeZonedEnviron{
+		zones: []string{"zone-a", "zone-b", "zone-c"},
+		instanceZones: map[instance.Id]string{
+			"inst-0": "zone-a",
+		},
+	}
+	s.PatchValue(highavailability.GetZonedEnviron, func(*state.State) (providercommon.ZonedEnviron, error) {
+		return env, nil
+	})
+
+	// Unprovisioned state servers give no information about zones.
+	placement, err := highavailability.ZonePlacement(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(placement, gc.IsNil)
+
+	m, err := s.State.Machine("0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetProvisioned("inst-0", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	placement, err = highavailability.ZonePlacement(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(placement[:4], gc.DeepEquals, []string{
+		"zone=zone-b", "zone=zone-c", "zone=zone-b", "zone=zone-c",
+	})
+}
+
+func (s *clientSuite) TestSpreadAcrossZones(c *gc.C) {
+	zones := []providercommon.AvailabilityZoneInstances{{
+		ZoneName: "zone-c",
+	}, {
+		ZoneName:  "zone-a",
+		Instances: []instance.Id{"inst-0"},
+	}, {
+		ZoneName:  "zone-b",
+		Instances: []instance.Id{"inst-1", "inst-2"},
+	}}
+	placement := highavailability.SpreadAcrossZones(zones, 4)
+	c.Assert(placement, gc.DeepEquals, []string{
+		"zone=zone-c", "zone=zone-c", "zone=zone-a", "zone=zone-c",
+	})
+	c.Assert(highavailability.SpreadAcrossZones(nil, 3), gc.IsNil)
+}
+
+type fakeZonedEnviron struct {
+	environs.Environ
+	zones         []string
+	instanceZones map[instance.Id]string
+}
+
+type fakeZone string
+
+func (z fakeZone) Name() string    { return string(z) }
+func (z fakeZone) Available() bool { return true }
+
+func (e *fakeZonedEnviron) AvailabilityZones() ([]providercommon.AvailabilityZone, error) {
+	zones := make([]providercommon.AvailabilityZone, len(e.zones))
+	for i, zone := range e.zones {
+		zones[i] = fakeZone(zone)
+	}
+	return zones, nil
+}
+
+func (e *fakeZonedEnviron) InstanceAvailabilityZoneNames(ids []instance.Id) ([]string, error) {
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = e.instanceZones[id]
+	}
+	return names, nil
+}
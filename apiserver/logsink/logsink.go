@@ -0,0 +1,53 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logsink
+
+import (
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("LogSink", 1, NewLogSinkAPI)
+}
+
+// LogSinkAPI provides access to the LogSink API facade, used by
+// agents to store their log records in state.
+type LogSinkAPI struct {
+	st     *state.State
+	entity string
+}
+
+// NewLogSinkAPI creates a new server-side LogSink API facade.
+func NewLogSinkAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*LogSinkAPI, error) {
+	if !authorizer.AuthMachineAgent() && !authorizer.AuthUnitAgent() {
+		return nil, common.ErrPerm
+	}
+	return &LogSinkAPI{
+		st:     st,
+		entity: authorizer.GetAuthTag().String(),
+	}, nil
+}
+
+// WriteLogs stores the given log records, recording them as logged by
+// the authenticated agent.
+func (api *LogSinkAPI) WriteLogs(args params.LogRecords) error {
+	records := make([]state.LogRecord, len(args.Records))
+	for i, record := range args.Records {
+		records[i] = state.LogRecord{
+			Time:     record.Time,
+			Entity:   api.entity,
+			Module:   record.Module,
+			Location: record.Location,
+			Level:    record.Level,
+			Message:  record.Message,
+		}
+	}
+	return api.st.AddLogs(records)
+}
@@ -0,0 +1,75 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logsink_test
+
+import (
+	"time"
+
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/logsink"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+)
+
+type logSinkSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&logSinkSuite{})
+
+func (s *logSinkSuite) TestNewLogSinkAPIRefusesClients(c *gc.C) {
+	authorizer := apiservertesting.FakeAuthorizer{Tag: names.NewUserTag("admin")}
+	_, err := logsink.NewLogSinkAPI(s.State, common.NewResources(), authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *logSinkSuite) TestWriteLogs(c *gc.C) {
+	authorizer := apiservertesting.FakeAuthorizer{Tag: names.NewUnitTag("mysql/0")}
+	api, err := logsink.NewLogSinkAPI(s.State, common.NewResources(), authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	t0 := time.Date(2014, 11, 5, 10, 0, 0, 0, time.UTC)
+	err = api.WriteLogs(params.LogRecords{
+		Records: []params.LogRecord{{
+			Time:     t0,
+			Module:   "juju.worker.uniter",
+			Location: "uniter.go:42",
+			Level:    loggo.INFO,
+			Message:  "hook started",
+		}, {
+			Time:    t0.Add(time.Second),
+			Module:  "unit.mysql/0.install",
+			Level:   loggo.DEBUG,
+			Message: "installing",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	records, err := s.State.Logs(state.LogFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	for i := range records {
+		records[i].Id = ""
+	}
+	c.Assert(records, jc.DeepEquals, []state.LogRecord{{
+		Time:     t0,
+		Entity:   "unit-mysql-0",
+		Module:   "juju.worker.uniter",
+		Location: "uniter.go:42",
+		Level:    loggo.INFO,
+		Message:  "hook started",
+	}, {
+		Time:    t0.Add(time.Second),
+		Entity:  "unit-mysql-0",
+		Module:  "unit.mysql/0.install",
+		Level:   loggo.DEBUG,
+		Message: "installing",
+	}})
+}
@@ -0,0 +1,185 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/state"
+)
+
+// stateMetricsTTL is how long the metrics read from state are reused
+// before they are read again. Counting the pending transactions and
+// finding the latest charm metrics both scan whole collections, so
+// they are not repeated on every scrape.
+var stateMetricsTTL = 30 * time.Second
+
+// apiMetrics collects the internal metrics of the API server that are
+// exposed by the metrics handler: the requests served and the
+// resources held by the open connections.
+type apiMetrics struct {
+	mu       sync.Mutex
+	requests map[requestKey]*requestStats
+	conns    map[*common.Resources]bool
+
+	stateMu sync.Mutex // protects the field that follows
+	state   map[string]*stateMetrics
+}
+
+// stateMetrics holds the metrics read from the state of an
+// environment.
+type stateMetrics struct {
+	read                time.Time
+	pendingTransactions int
+	charmMetrics        []state.UnitMetric
+}
+
+type requestKey struct {
+	facade string
+	method string
+}
+
+type requestStats struct {
+	count   int64
+	errors  int64
+	seconds float64
+}
+
+func newAPIMetrics() *apiMetrics {
+	return &apiMetrics{
+		requests: make(map[requestKey]*requestStats),
+		conns:    make(map[*common.Resources]bool),
+		state:    make(map[string]*stateMetrics),
+	}
+}
+
+// stateMetrics returns the metrics read from the given state. They
+// are read again only when those last read for the state's
+// environment are older than stateMetricsTTL.
+func (m *apiMetrics) stateMetrics(st *state.State) (stateMetrics, error) {
+	m.stateMu.Lock()
+	defer m.stateMu.Unlock()
+	envUUID := st.EnvironUUID()
+	if cached := m.state[envUUID]; cached != nil && time.Since(cached.read) < stateMetricsTTL {
+		return *cached, nil
+	}
+	read := time.Now()
+	pending, err := st.PendingTransactionCount()
+	if err != nil {
+		return stateMetrics{}, errors.Annotate(err, "cannot count pending transactions")
+	}
+	charmMetrics, err := st.LatestMetrics()
+	if err != nil {
+		return stateMetrics{}, errors.Annotate(err, "cannot get charm metrics")
+	}
+	sm := &stateMetrics{
+		read:                read,
+		pendingTransactions: pending,
+		charmMetrics:        charmMetrics,
+	}
+	m.state[envUUID] = sm
+	return *sm, nil
+}
+
+// addConn records the resources of a newly opened connection.
+func (m *apiMetrics) addConn(resources *common.Resources) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.conns[resources] = true
+}
+
+// removeConn forgets the resources of a closed connection.
+func (m *apiMetrics) removeConn(resources *common.Resources) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.conns, resources)
+}
+
+// addRequest records a request served by the given facade method.
+func (m *apiMetrics) addRequest(facade, method string, timeSpent time.Duration, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := requestKey{facade, method}
+	stats := m.requests[key]
+	if stats == nil {
+		stats = &requestStats{}
+		m.requests[key] = stats
+	}
+	stats.count++
+	if failed {
+		stats.errors++
+	}
+	stats.seconds += timeSpent.Seconds()
+}
+
+// requestMetrics holds the metrics of the requests served by a
+// facade method.
+type requestMetrics struct {
+	requestKey
+	requestStats
+}
+
+// apiMetricsSnapshot holds the metrics collected at a point in time.
+type apiMetricsSnapshot struct {
+	requests    []requestMetrics
+	connections int
+	watchers    int
+}
+
+// snapshot returns the current metrics, with the request metrics
+// ordered by facade and method.
+func (m *apiMetrics) snapshot() apiMetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var s apiMetricsSnapshot
+	for key, stats := range m.requests {
+		s.requests = append(s.requests, requestMetrics{key, *stats})
+	}
+	sort.Sort(requestMetricsByKey(s.requests))
+	s.connections = len(m.conns)
+	for resources := range m.conns {
+		s.watchers += resources.CountRegistered()
+	}
+	return s
+}
+
+type requestMetricsByKey []requestMetrics
+
+func (r requestMetricsByKey) Len() int      { return len(r) }
+func (r requestMetricsByKey) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r requestMetricsByKey) Less(i, j int) bool {
+	if r[i].facade != r[j].facade {
+		return r[i].facade < r[j].facade
+	}
+	return r[i].method < r[j].method
+}
+
+// metricsNotifier is an rpc.RequestNotifier that records the requests
+// served on a connection in the server's metrics.
+type metricsNotifier struct {
+	metrics *apiMetrics
+}
+
+// ServerRequest implements rpc.RequestNotifier.
+func (n metricsNotifier) ServerRequest(hdr *rpc.Header, body interface{}) {
+}
+
+// ServerReply implements rpc.RequestNotifier.
+func (n metricsNotifier) ServerReply(req rpc.Request, hdr *rpc.Header, body interface{}, timeSpent time.Duration) {
+	n.metrics.addRequest(req.Type, req.Action, timeSpent, hdr.Error != "")
+}
+
+// ClientRequest implements rpc.RequestNotifier.
+func (n metricsNotifier) ClientRequest(hdr *rpc.Header, body interface{}) {
+}
+
+// ClientReply implements rpc.RequestNotifier.
+func (n metricsNotifier) ClientReply(req rpc.Request, hdr *rpc.Header, body interface{}) {
+}
@@ -16,3 +16,8 @@ func PatchHostAndCertPool(host string, certPool *x509.CertPool) func() {
 		restoreCertsPool()
 	}
 }
+
+var (
+	RetryDelay     = &retryDelay
+	WebhookTimeout = &webhookTimeout
+)
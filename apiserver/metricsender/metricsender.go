@@ -10,6 +10,7 @@ import (
 	"github.com/juju/loggo"
 
 	"github.com/juju/juju/apiserver/metricsender/wireformat"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
 )
 
@@ -21,6 +22,29 @@ type MetricSender interface {
 	Send([]*wireformat.MetricBatch) (*wireformat.Response, error)
 }
 
+// NewSender returns the sender selected by the given environment
+// configuration. Senders that deliver metrics anywhere retry failed
+// sends.
+func NewSender(cfg *config.Config) (MetricSender, error) {
+	var sender MetricSender
+	switch name := cfg.MetricsSender(); name {
+	case config.MetricsSenderNone:
+		return NopSender{}, nil
+	case config.MetricsSenderCollector:
+		sender = &DefaultSender{}
+	case config.MetricsSenderWebhook:
+		url, _ := cfg.MetricsWebhookURL()
+		auth, _ := cfg.MetricsWebhookAuth()
+		sender = &WebhookSender{URL: url, Auth: auth}
+	case config.MetricsSenderSpool:
+		dir, _ := cfg.MetricsSpoolDir()
+		sender = &SpoolSender{Dir: dir}
+	default:
+		return nil, errors.NotValidf("metrics sender %q", name)
+	}
+	return &RetrySender{Sender: sender}, nil
+}
+
 // SendMetrics will send any unsent metrics
 // over the MetricSender interface in batches
 // no larger than batchSize.
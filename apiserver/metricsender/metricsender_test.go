@@ -10,8 +10,10 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/metricsender"
+	"github.com/juju/juju/environs/config"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
 )
 
@@ -88,3 +90,42 @@ func (s *MetricSenderSuite) TestDontSendWithNopSender(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(sent, gc.Equals, 3)
 }
+
+func (s *MetricSenderSuite) TestNewSender(c *gc.C) {
+	for i, test := range []struct {
+		attrs    coretesting.Attrs
+		expected metricsender.MetricSender
+	}{{
+		attrs:    coretesting.Attrs{},
+		expected: metricsender.NopSender{},
+	}, {
+		attrs: coretesting.Attrs{"metrics-sender": "collector"},
+		expected: &metricsender.RetrySender{
+			Sender: &metricsender.DefaultSender{},
+		},
+	}, {
+		attrs: coretesting.Attrs{
+			"metrics-sender":       "webhook",
+			"metrics-webhook-url":  "https://metrics.example.com",
+			"metrics-webhook-auth": "Bearer s3cr3t",
+		},
+		expected: &metricsender.RetrySender{
+			Sender: &metricsender.WebhookSender{URL: "https://metrics.example.com", Auth: "Bearer s3cr3t"},
+		},
+	}, {
+		attrs: coretesting.Attrs{
+			"metrics-sender":    "spool",
+			"metrics-spool-dir": "/var/spool/juju-metrics",
+		},
+		expected: &metricsender.RetrySender{
+			Sender: &metricsender.SpoolSender{Dir: "/var/spool/juju-metrics"},
+		},
+	}} {
+		c.Logf("test %d: %v", i, test.attrs)
+		cfg, err := config.New(config.NoDefaults, coretesting.FakeConfig().Merge(test.attrs))
+		c.Assert(err, jc.ErrorIsNil)
+		sender, err := metricsender.NewSender(cfg)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(sender, jc.DeepEquals, test.expected)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsender
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/metricsender/wireformat"
+)
+
+var (
+	// retryAttempts holds the number of times RetrySender tries to
+	// send metrics before giving up.
+	retryAttempts = 4
+
+	// retryDelay holds the delay after the first failed attempt to
+	// send metrics. It doubles after each further failure.
+	retryDelay = 500 * time.Millisecond
+)
+
+// RetrySender retries failed sends of another sender, backing off
+// exponentially between attempts.
+type RetrySender struct {
+	Sender MetricSender
+}
+
+// Send implements MetricSender.
+func (s *RetrySender) Send(batches []*wireformat.MetricBatch) (*wireformat.Response, error) {
+	delay := retryDelay
+	var err error
+	for attempt := 1; ; attempt++ {
+		var response *wireformat.Response
+		response, err = s.Sender.Send(batches)
+		if err == nil {
+			return response, nil
+		}
+		if attempt >= retryAttempts {
+			break
+		}
+		sendLogger.Warningf("failed to send metrics (attempt %d of %d), retrying in %v: %v", attempt, retryAttempts, delay, err)
+		time.Sleep(delay)
+		delay *= 2
+	}
+	return nil, errors.Annotatef(err, "giving up after %d attempts", retryAttempts)
+}
@@ -0,0 +1,59 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsender_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/metricsender"
+	"github.com/juju/juju/apiserver/metricsender/wireformat"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type RetrySenderSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&RetrySenderSuite{})
+
+func (s *RetrySenderSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.PatchValue(metricsender.RetryDelay, time.Duration(0))
+}
+
+// failingSender fails to send the given number of times before
+// delegating to a NopSender.
+type failingSender struct {
+	failures int
+	calls    int
+}
+
+func (f *failingSender) Send(batches []*wireformat.MetricBatch) (*wireformat.Response, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return nil, errors.New("boom")
+	}
+	return metricsender.NopSender{}.Send(batches)
+}
+
+func (s *RetrySenderSuite) TestRetriesUntilSuccess(c *gc.C) {
+	inner := &failingSender{failures: 2}
+	sender := &metricsender.RetrySender{Sender: inner}
+	response, err := sender.Send(webhookBatches)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(inner.calls, gc.Equals, 3)
+	c.Assert(response.EnvResponses["env-1"].AcknowledgedBatches, gc.HasLen, 2)
+}
+
+func (s *RetrySenderSuite) TestGivesUp(c *gc.C) {
+	inner := &failingSender{failures: 10}
+	sender := &metricsender.RetrySender{Sender: inner}
+	_, err := sender.Send(webhookBatches)
+	c.Assert(err, gc.ErrorMatches, "giving up after 4 attempts: boom")
+	c.Assert(inner.calls, gc.Equals, 4)
+}
@@ -0,0 +1,56 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsender
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/metricsender/wireformat"
+)
+
+// SpoolFileName holds the name of the file, in the spool directory,
+// that SpoolSender appends metrics to.
+const SpoolFileName = "metrics.jsonl"
+
+// SpoolSender appends metrics to a file in a local directory, one
+// JSON-encoded wireformat.MetricBatch per line. All the batches are
+// acknowledged once they have been synced to disk.
+type SpoolSender struct {
+	// Dir holds the spool directory. It is created if it does not
+	// exist.
+	Dir string
+}
+
+// Send implements MetricSender.
+func (s *SpoolSender) Send(batches []*wireformat.MetricBatch) (*wireformat.Response, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, batch := range batches {
+		// Encode writes a newline after each batch.
+		if err := enc.Encode(batch); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return nil, errors.Annotate(err, "cannot create metrics spool directory")
+	}
+	path := filepath.Join(s.Dir, SpoolFileName)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot open metrics spool file")
+	}
+	defer f.Close()
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		return nil, errors.Annotate(err, "cannot write metrics spool file")
+	}
+	if err := f.Sync(); err != nil {
+		return nil, errors.Annotate(err, "cannot sync metrics spool file")
+	}
+	return ackAll(batches)
+}
@@ -0,0 +1,50 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsender_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/metricsender"
+	"github.com/juju/juju/apiserver/metricsender/wireformat"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type SpoolSenderSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&SpoolSenderSuite{})
+
+var _ metricsender.MetricSender = (*metricsender.SpoolSender)(nil)
+
+func (s *SpoolSenderSuite) TestSendAppendsBatches(c *gc.C) {
+	dir := filepath.Join(c.MkDir(), "spool")
+	sender := &metricsender.SpoolSender{Dir: dir}
+	response, err := sender.Send(webhookBatches[:1])
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(response.EnvResponses["env-1"].AcknowledgedBatches, jc.DeepEquals, []string{"batch-1"})
+	_, err = sender.Send(webhookBatches[1:])
+	c.Assert(err, jc.ErrorIsNil)
+
+	f, err := os.Open(filepath.Join(dir, metricsender.SpoolFileName))
+	c.Assert(err, jc.ErrorIsNil)
+	defer f.Close()
+	var uuids []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var batch wireformat.MetricBatch
+		err := json.Unmarshal(scanner.Bytes(), &batch)
+		c.Assert(err, jc.ErrorIsNil)
+		uuids = append(uuids, batch.UUID)
+	}
+	c.Assert(scanner.Err(), jc.ErrorIsNil)
+	c.Assert(uuids, jc.DeepEquals, []string{"batch-1", "batch-2"})
+}
@@ -0,0 +1,86 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsender
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/apiserver/metricsender/wireformat"
+)
+
+// webhookTimeout bounds the time taken to post metrics to a webhook,
+// including reading its reply.
+var webhookTimeout = 30 * time.Second
+
+// WebhookSender posts metrics as JSON to an HTTP endpoint.
+//
+// The endpoint may reply with a wireformat.Response holding the
+// batches it has acknowledged, in which case only those batches are
+// marked as sent. An empty reply acknowledges all the batches posted.
+type WebhookSender struct {
+	// URL holds the URL of the endpoint.
+	URL string
+
+	// Auth holds the value of the Authorization header sent with
+	// each request, if any.
+	Auth string
+}
+
+// Send implements MetricSender.
+func (s *WebhookSender) Send(batches []*wireformat.MetricBatch) (*wireformat.Response, error) {
+	b, err := json.Marshal(batches)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	req, err := http.NewRequest("POST", s.URL, bytes.NewReader(b))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.Auth != "" {
+		req.Header.Set("Authorization", s.Auth)
+	}
+	client := &http.Client{Timeout: webhookTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot post metrics to %q", s.URL)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, errors.Errorf("cannot post metrics to %q: http %v", s.URL, resp.StatusCode)
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ackAll(batches)
+	}
+	var response wireformat.Response
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, errors.Annotate(err, "cannot parse metrics webhook response")
+	}
+	return &response, nil
+}
+
+// ackAll returns a response acknowledging all the given batches.
+func ackAll(batches []*wireformat.MetricBatch) (*wireformat.Response, error) {
+	envResponses := make(wireformat.EnvironmentResponses)
+	for _, batch := range batches {
+		envResponses.Ack(batch.EnvUUID, batch.UUID)
+	}
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &wireformat.Response{UUID: uuid.String(), EnvResponses: envResponses}, nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsender_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/metricsender"
+	"github.com/juju/juju/apiserver/metricsender/wireformat"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type WebhookSenderSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&WebhookSenderSuite{})
+
+var _ metricsender.MetricSender = (*metricsender.WebhookSender)(nil)
+
+var webhookBatches = []*wireformat.MetricBatch{
+	{UUID: "batch-1", EnvUUID: "env-1", UnitName: "metered/0"},
+	{UUID: "batch-2", EnvUUID: "env-1", UnitName: "metered/1"},
+}
+
+func (s *WebhookSenderSuite) TestSendAcknowledgesAllOnEmptyReply(c *gc.C) {
+	var received []wireformat.MetricBatch
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, gc.Equals, "POST")
+		c.Check(r.Header.Get("Content-Type"), gc.Equals, "application/json")
+		c.Check(r.Header.Get("Authorization"), gc.Equals, "Bearer s3cr3t")
+		err := json.NewDecoder(r.Body).Decode(&received)
+		c.Check(err, jc.ErrorIsNil)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sender := &metricsender.WebhookSender{URL: server.URL, Auth: "Bearer s3cr3t"}
+	response, err := sender.Send(webhookBatches)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(received, gc.HasLen, 2)
+	c.Assert(received[0].UnitName, gc.Equals, "metered/0")
+	c.Assert(response.EnvResponses["env-1"].AcknowledgedBatches, jc.DeepEquals, []string{"batch-1", "batch-2"})
+}
+
+func (s *WebhookSenderSuite) TestSendUsesAcknowledgementsInReply(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Header.Get("Authorization"), gc.Equals, "")
+		resp := make(wireformat.EnvironmentResponses)
+		resp.Ack("env-1", "batch-2")
+		err := json.NewEncoder(w).Encode(wireformat.Response{UUID: "response", EnvResponses: resp})
+		c.Check(err, jc.ErrorIsNil)
+	}))
+	defer server.Close()
+
+	sender := &metricsender.WebhookSender{URL: server.URL}
+	response, err := sender.Send(webhookBatches)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(response.UUID, gc.Equals, "response")
+	c.Assert(response.EnvResponses["env-1"].AcknowledgedBatches, jc.DeepEquals, []string{"batch-2"})
+}
+
+func (s *WebhookSenderSuite) TestSendError(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sender := &metricsender.WebhookSender{URL: server.URL}
+	_, err := sender.Send(webhookBatches)
+	c.Assert(err, gc.ErrorMatches, `cannot post metrics to ".*": http 503`)
+}
+
+func (s *WebhookSenderSuite) TestSendTimeout(c *gc.C) {
+	s.PatchValue(metricsender.WebhookTimeout, 10*time.Millisecond)
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+	}))
+	defer server.Close()
+	defer close(unblock)
+
+	sender := &metricsender.WebhookSender{URL: server.URL}
+	_, err := sender.Send(webhookBatches)
+	c.Assert(err, gc.ErrorMatches, `cannot post metrics to ".*": .*`)
+}
@@ -0,0 +1,149 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+
+	apihttp "github.com/juju/juju/apiserver/http"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// metricsContentType is the content type of the Prometheus text
+// exposition format.
+const metricsContentType = "text/plain; version=0.0.4"
+
+// metricsHandler exposes the charm metrics recorded by units and the
+// internal metrics of the API server in the Prometheus text format.
+type metricsHandler struct {
+	httpHandler
+	metrics *apiMetrics
+}
+
+func (h *metricsHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if err := h.validateEnvironUUID(req); err != nil {
+		h.sendError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err := h.authenticate(req); err != nil {
+		h.authError(w, h)
+		return
+	}
+	if req.Method != "GET" {
+		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("unsupported method: %q", req.Method))
+		return
+	}
+	var buf bytes.Buffer
+	if err := h.writeMetrics(&buf); err != nil {
+		h.sendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", metricsContentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// writeMetrics writes all the exposed metrics to w.
+func (h *metricsHandler) writeMetrics(w io.Writer) error {
+	sm, err := h.metrics.stateMetrics(h.state)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	writeAPIMetrics(w, h.metrics.snapshot())
+	writeMetricHeader(w, "juju_state_pending_transactions", "gauge",
+		"Number of transactions neither applied nor aborted.")
+	fmt.Fprintf(w, "juju_state_pending_transactions %d\n", sm.pendingTransactions)
+	writeCharmMetrics(w, sm.charmMetrics)
+	return nil
+}
+
+func writeAPIMetrics(w io.Writer, s apiMetricsSnapshot) {
+	writeMetricHeader(w, "juju_apiserver_requests_total", "counter",
+		"Number of API requests served.")
+	for _, r := range s.requests {
+		fmt.Fprintf(w, "juju_apiserver_requests_total%s %d\n", requestLabels(r), r.count)
+	}
+	writeMetricHeader(w, "juju_apiserver_request_errors_total", "counter",
+		"Number of API requests that returned an error.")
+	for _, r := range s.requests {
+		fmt.Fprintf(w, "juju_apiserver_request_errors_total%s %d\n", requestLabels(r), r.errors)
+	}
+	writeMetricHeader(w, "juju_apiserver_request_duration_seconds", "summary",
+		"Time spent serving API requests.")
+	for _, r := range s.requests {
+		labels := requestLabels(r)
+		fmt.Fprintf(w, "juju_apiserver_request_duration_seconds_sum%s %s\n", labels, formatFloat(r.seconds))
+		fmt.Fprintf(w, "juju_apiserver_request_duration_seconds_count%s %d\n", labels, r.count)
+	}
+	writeMetricHeader(w, "juju_apiserver_connections", "gauge",
+		"Number of open API connections.")
+	fmt.Fprintf(w, "juju_apiserver_connections %d\n", s.connections)
+	writeMetricHeader(w, "juju_apiserver_watchers", "gauge",
+		"Number of watchers held by open API connections.")
+	fmt.Fprintf(w, "juju_apiserver_watchers %d\n", s.watchers)
+}
+
+// writeCharmMetrics writes the latest value of each charm metric of
+// each unit, timestamped with the time it was recorded. Values that
+// are not numbers cannot be exposed, and are skipped.
+func writeCharmMetrics(w io.Writer, metrics []state.UnitMetric) {
+	writeMetricHeader(w, "juju_charm_metric", "gauge",
+		"Latest value of a metric recorded by a unit.")
+	for _, m := range metrics {
+		value, err := strconv.ParseFloat(m.Value, 64)
+		if err != nil {
+			logger.Debugf("skipping non-numeric metric %q of unit %q: %q", m.Key, m.Unit, m.Value)
+			continue
+		}
+		fmt.Fprintf(w, "juju_charm_metric{unit=%s,charm=%s,key=%s} %s %d\n",
+			quoteLabel(m.Unit),
+			quoteLabel(m.CharmURL),
+			quoteLabel(m.Key),
+			formatFloat(value),
+			m.Time.UnixNano()/1e6,
+		)
+	}
+}
+
+func writeMetricHeader(w io.Writer, name, metricType, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, metricType)
+}
+
+func requestLabels(r requestMetrics) string {
+	return fmt.Sprintf("{facade=%s,method=%s}", quoteLabel(r.facade), quoteLabel(r.method))
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// quoteLabel returns the given label value quoted as required by the
+// Prometheus text format.
+func quoteLabel(value string) string {
+	return `"` + labelEscaper.Replace(value) + `"`
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// sendError sends a JSON-encoded error response.
+func (h *metricsHandler) sendError(w http.ResponseWriter, statusCode int, message string) {
+	body, err := json.Marshal(&params.Error{Message: message})
+	if err != nil {
+		logger.Errorf("failed to serialize the error (%v): %v", message, err)
+		return
+	}
+	w.Header().Set("Content-Type", apihttp.CTypeJSON)
+	w.WriteHeader(statusCode)
+	w.Write(body)
+}
@@ -0,0 +1,122 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	apihttp "github.com/juju/juju/apiserver/http"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type metricsHandlerSuite struct {
+	authHttpSuite
+}
+
+var _ = gc.Suite(&metricsHandlerSuite{})
+
+func (s *metricsHandlerSuite) metricsURL(c *gc.C) string {
+	environ, err := s.State.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	uri := s.baseURL(c)
+	uri.Path = fmt.Sprintf("/environment/%s/metrics", environ.UUID())
+	return uri.String()
+}
+
+func (s *metricsHandlerSuite) checkErrorResponse(c *gc.C, resp *http.Response, statusCode int, msg string) {
+	body := assertResponse(c, resp, statusCode, apihttp.CTypeJSON)
+	var failure params.Error
+	err := json.Unmarshal(body, &failure)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(&failure, gc.ErrorMatches, msg)
+}
+
+func (s *metricsHandlerSuite) TestRequiresAuth(c *gc.C) {
+	resp, err := s.sendRequest(c, "", "", "GET", s.metricsURL(c), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkErrorResponse(c, resp, http.StatusUnauthorized, "unauthorized")
+}
+
+func (s *metricsHandlerSuite) TestRequiresGET(c *gc.C) {
+	resp, err := s.authRequest(c, "POST", s.metricsURL(c), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkErrorResponse(c, resp, http.StatusMethodNotAllowed, `unsupported method: "POST"`)
+}
+
+func (s *metricsHandlerSuite) TestUnknownEnvironment(c *gc.C) {
+	uri := s.baseURL(c)
+	uri.Path = "/environment/dead-beef-123456/metrics"
+	resp, err := s.authRequest(c, "GET", uri.String(), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkErrorResponse(c, resp, http.StatusNotFound, `unknown environment: "dead-beef-123456"`)
+}
+
+func (s *metricsHandlerSuite) getMetrics(c *gc.C, uri string) string {
+	resp, err := s.authRequest(c, "GET", uri, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	c.Assert(resp.Header.Get("Content-Type"), gc.Equals, "text/plain; version=0.0.4")
+	body, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	return string(body)
+}
+
+func (s *metricsHandlerSuite) TestCharmMetrics(c *gc.C) {
+	t := time.Date(2014, 11, 5, 10, 0, 0, 0, time.UTC)
+	s.Factory.MakeMetric(c, &factory.MetricParams{Time: &t, Metrics: []state.Metric{
+		{Key: "pings", Value: "5.5", Time: t},
+		{Key: "status", Value: "ok", Time: t},
+	}})
+	body := s.getMetrics(c, s.metricsURL(c))
+	c.Assert(body, jc.Contains,
+		"# TYPE juju_charm_metric gauge\n"+
+			`juju_charm_metric{unit="metered/0",charm="cs:quantal/metered",key="pings"} 5.5 1415181600000`+"\n")
+	c.Assert(body, gc.Not(jc.Contains), `key="status"`)
+}
+
+func (s *metricsHandlerSuite) TestStateMetricsCached(c *gc.C) {
+	s.PatchValue(apiserver.StateMetricsTTL, time.Hour)
+	body := s.getMetrics(c, s.metricsURL(c))
+	c.Assert(body, gc.Not(jc.Contains), `key="pings"`)
+	t := time.Date(2014, 11, 5, 10, 0, 0, 0, time.UTC)
+	s.Factory.MakeMetric(c, &factory.MetricParams{Time: &t, Metrics: []state.Metric{
+		{Key: "pings", Value: "5.5", Time: t},
+	}})
+	body = s.getMetrics(c, s.metricsURL(c))
+	c.Assert(body, gc.Not(jc.Contains), `key="pings"`)
+
+	s.PatchValue(apiserver.StateMetricsTTL, time.Duration(0))
+	body = s.getMetrics(c, s.metricsURL(c))
+	c.Assert(body, jc.Contains, `key="pings"`)
+}
+
+func (s *metricsHandlerSuite) TestControllerMetrics(c *gc.C) {
+	_, err := s.APIState.Client().EnvironmentGet()
+	c.Assert(err, jc.ErrorIsNil)
+	body := s.getMetrics(c, s.metricsURL(c))
+	c.Assert(body, jc.Contains, `juju_apiserver_requests_total{facade="Client",method="EnvironmentGet"} 1`+"\n")
+	c.Assert(body, jc.Contains, `juju_apiserver_request_errors_total{facade="Client",method="EnvironmentGet"} 0`+"\n")
+	c.Assert(body, jc.Contains, `juju_apiserver_request_duration_seconds_count{facade="Client",method="EnvironmentGet"} 1`+"\n")
+	c.Assert(body, gc.Matches, `(?s).*\njuju_apiserver_connections [1-9][0-9]*\n.*`)
+	c.Assert(body, gc.Matches, `(?s).*\njuju_apiserver_watchers [0-9]+\n.*`)
+	c.Assert(body, gc.Matches, `(?s).*\njuju_state_pending_transactions [0-9]+\n.*`)
+}
+
+func (s *metricsHandlerSuite) TestLegacyPath(c *gc.C) {
+	uri := s.baseURL(c)
+	uri.Path = "/metrics"
+	body := s.getMetrics(c, uri.String())
+	c.Assert(body, jc.Contains, "# TYPE juju_apiserver_requests_total counter\n")
+}
@@ -4,9 +4,14 @@
 package metricsmanager
 
 import (
+	"github.com/juju/testing"
+
 	"github.com/juju/juju/apiserver/metricsender"
+	"github.com/juju/juju/environs/config"
 )
 
-func PatchSender(s metricsender.MetricSender) {
-	sender = s
+func PatchSender(s metricsender.MetricSender) func() {
+	return testing.PatchValue(&newSender, func(*config.Config) (metricsender.MetricSender, error) {
+		return s, nil
+	})
 }
@@ -20,7 +20,7 @@ var (
 	logger            = loggo.GetLogger("juju.apiserver.metricsmanager")
 	maxBatchesPerSend = 1000
 
-	sender metricsender.MetricSender = &metricsender.NopSender{}
+	newSender = metricsender.NewSender
 )
 
 func init() {
@@ -104,7 +104,18 @@ func (api *MetricsManagerAPI) CleanupOldMetrics(args params.Entities) (params.Er
 	return result, nil
 }
 
-// SendMetrics will send any unsent metrics onto the metric collection service.
+// sender returns the metrics sender selected by the environment
+// configuration.
+func (api *MetricsManagerAPI) sender() (metricsender.MetricSender, error) {
+	cfg, err := api.state.EnvironConfig()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get environment config")
+	}
+	return newSender(cfg)
+}
+
+// SendMetrics will send any unsent metrics using the metrics sender
+// selected by the environment configuration.
 func (api *MetricsManagerAPI) SendMetrics(args params.Entities) (params.ErrorResults, error) {
 	result := params.ErrorResults{
 		Results: make([]params.ErrorResult, len(args.Entities)),
@@ -126,6 +137,11 @@ func (api *MetricsManagerAPI) SendMetrics(args params.Entities) (params.ErrorRes
 			result.Results[i].Error = common.ServerError(common.ErrPerm)
 			continue
 		}
+		sender, err := api.sender()
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
 		err = metricsender.SendMetrics(api.state, sender, maxBatchesPerSend)
 		if err != nil {
 			err = errors.Annotate(err, "failed to send metrics")
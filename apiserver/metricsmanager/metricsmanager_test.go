@@ -4,6 +4,8 @@
 package metricsmanager_test
 
 import (
+	"io/ioutil"
+	"path/filepath"
 	"time"
 
 	"github.com/juju/errors"
@@ -118,7 +120,7 @@ func (s *metricsManagerSuite) TestCleanupArgsIndependent(c *gc.C) {
 
 func (s *metricsManagerSuite) TestSendMetrics(c *gc.C) {
 	var sender metricsender.MockSender
-	metricsmanager.PatchSender(&sender)
+	defer metricsmanager.PatchSender(&sender)()
 	now := time.Now()
 	metric := state.Metric{"pings", "5", now}
 	s.Factory.MakeMetric(c, &factory.MetricParams{Unit: s.unit, Sent: true, Time: &now, Metrics: []state.Metric{metric}})
@@ -159,3 +161,27 @@ func (s *metricsManagerSuite) TestSendArgsIndependent(c *gc.C) {
 	c.Assert(result.Results[0], gc.DeepEquals, params.ErrorResult{Error: expectedError})
 	c.Assert(result.Results[1], gc.DeepEquals, params.ErrorResult{Error: nil})
 }
+
+func (s *metricsManagerSuite) TestSendMetricsWithConfiguredSender(c *gc.C) {
+	dir := c.MkDir()
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"metrics-sender":    "spool",
+		"metrics-spool-dir": dir,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	unsent := s.Factory.MakeMetric(c, &factory.MetricParams{Unit: s.unit})
+	args := params.Entities{Entities: []params.Entity{
+		{s.State.EnvironTag().String()},
+	}}
+	result, err := s.metricsmanager.SendMetrics(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0], gc.DeepEquals, params.ErrorResult{Error: nil})
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, metricsender.SpoolFileName))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), jc.Contains, unsent.UUID())
+	m, err := s.State.MetricBatch(unsent.UUID())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Sent(), jc.IsTrue)
+}
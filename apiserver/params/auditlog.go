@@ -0,0 +1,36 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import (
+	"time"
+)
+
+// AuditLogFilter holds the parameters for querying the audit log.
+// Zero-valued fields do not restrict the results.
+type AuditLogFilter struct {
+	User   string    `json:"user,omitempty"`
+	Facade string    `json:"facade,omitempty"`
+	Method string    `json:"method,omitempty"`
+	After  time.Time `json:"after,omitempty"`
+	Before time.Time `json:"before,omitempty"`
+	Limit  int       `json:"limit,omitempty"`
+}
+
+// AuditLogEntry holds a single audit log entry.
+type AuditLogEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	EnvUUID   string    `json:"env-uuid"`
+	User      string    `json:"user"`
+	Facade    string    `json:"facade"`
+	Version   int       `json:"version"`
+	Method    string    `json:"method"`
+	Arguments string    `json:"arguments,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// AuditLogResults holds the results of an audit log query.
+type AuditLogResults struct {
+	Entries []AuditLogEntry `json:"entries"`
+}
@@ -12,6 +12,8 @@ import (
 // BackupsCreateArgs holds the args for the API Create method.
 type BackupsCreateArgs struct {
 	Notes string
+	// EncryptionKey is the key to encrypt the backup with, if any.
+	EncryptionKey []byte
 }
 
 // BackupsInfoArgs holds the args for the API Info method.
@@ -62,8 +64,41 @@ type BackupsMetadataResult struct {
 	Started     time.Time
 	Finished    time.Time // May be zero...
 	Notes       string
+	KeyID       string // Empty if the backup is not encrypted.
+	Scheduled   bool
 	Environment string
 	Machine     string
 	Hostname    string
 	Version     version.Number
 }
+
+// BackupsScheduleResult holds the backups schedule and retention
+// policy of an environment, as returned by the API Schedule method.
+type BackupsScheduleResult struct {
+	Schedule   string // May be empty if backups are not scheduled.
+	KeepLast   int
+	KeepDaily  int
+	KeepWeekly int
+
+	NextRun    time.Time // May be zero...
+	Status     string    // May be empty if no backup was scheduled yet.
+	StatusInfo string
+	StatusData map[string]interface{}
+}
+
+// BackupsSetScheduleArgs holds the args for the API SetSchedule
+// method. Nil fields are left unchanged, while empty and zero values
+// unset the corresponding setting.
+type BackupsSetScheduleArgs struct {
+	Schedule   *string
+	KeepLast   *int
+	KeepDaily  *int
+	KeepWeekly *int
+}
+
+// RestoreArgs holds the args for the API Restore method.
+type RestoreArgs struct {
+	BackupId string
+	// EncryptionKey is the key the backup is encrypted with, if any.
+	EncryptionKey []byte
+}
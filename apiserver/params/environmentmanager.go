@@ -0,0 +1,31 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// Environment holds the result of an API call returning a name and UUID
+// for an environment and the tag of the server in which it is running.
+type Environment struct {
+	Name       string `json:"name"`
+	UUID       string `json:"uuid"`
+	OwnerTag   string `json:"owner-tag"`
+	ServerUUID string `json:"server-uuid"`
+	Life       string `json:"life"`
+}
+
+// EnvironmentList holds information about a list of environments.
+type EnvironmentList struct {
+	Environments []Environment `json:"environments"`
+}
+
+// EnvironmentCreateArgs holds the arguments that are necessary to create
+// an environment.
+type EnvironmentCreateArgs struct {
+	// OwnerTag represents the user that will own the new environment.
+	OwnerTag string `json:"owner-tag"`
+
+	// Config defines the environment config, which includes the name of
+	// the environment. Attributes that are not given are taken from the
+	// state server environment.
+	Config map[string]interface{} `json:"config"`
+}
@@ -6,6 +6,7 @@ package params
 import (
 	"time"
 
+	"github.com/juju/loggo"
 	"github.com/juju/utils/exec"
 
 	"github.com/juju/juju/constraints"
@@ -202,6 +203,34 @@ type ConfigSettingsResults struct {
 	Results []ConfigSettingsResult
 }
 
+// LeaderSettings holds the settings shared by a service's leader with
+// its followers.
+type LeaderSettings map[string]string
+
+// LeaderSettingsResult holds a service's leader settings or an error.
+type LeaderSettingsResult struct {
+	Error    *Error
+	Settings LeaderSettings
+}
+
+// LeaderSettingsResults holds multiple leader settings maps or errors.
+type LeaderSettingsResults struct {
+	Results []LeaderSettingsResult
+}
+
+// MergeLeaderSettingsParam holds a unit tag and the leader settings
+// that unit wants to merge into its service's leader settings.
+type MergeLeaderSettingsParam struct {
+	Unit     string
+	Settings LeaderSettings
+}
+
+// MergeLeaderSettingsParams holds the arguments for the
+// MergeLeaderSettings API call.
+type MergeLeaderSettingsParams struct {
+	Params []MergeLeaderSettingsParam
+}
+
 // EnvironConfig holds an environment configuration.
 type EnvironConfig map[string]interface{}
 
@@ -623,6 +652,15 @@ type ProvisioningInfo struct {
 	Placement   string
 	Networks    []string
 	Jobs        []multiwatcher.MachineJob
+
+	// Subnets holds the subnets matching the spaces constraint of
+	// the machine, if any, that it may be started in.
+	Subnets []network.BasicInfo
+
+	// Services holds the names of the services of the principal
+	// units assigned to the machine, which make up its distribution
+	// group.
+	Services []string
 }
 
 // ProvisioningInfoResult holds machine provisioning info or an error.
@@ -692,9 +730,51 @@ type BlockDeviceResults struct {
 	Results []BlockDeviceResult `json:"results,omitempty"`
 }
 
+// StorageInstancesResult holds the result of an API call to retrieve
+// the storage instances of an entity.
+type StorageInstancesResult struct {
+	Result []StorageInstance `json:"result,omitempty"`
+	Error  *Error            `json:"error,omitempty"`
+}
+
+// StorageInstancesResults holds the result of an API call to retrieve
+// the storage instances of multiple entities.
+type StorageInstancesResults struct {
+	Results []StorageInstancesResult `json:"results,omitempty"`
+}
+
+// StorageProvisioned holds the parameters for recording that a storage
+// instance has been provisioned at the given location.
+type StorageProvisioned struct {
+	Id         string             `json:"id"`
+	Location   string             `json:"location"`
+	Filesystem storage.Filesystem `json:"filesystem"`
+}
+
+// SetStorageProvisioned holds the parameters for recording that a set
+// of storage instances have been provisioned.
+type SetStorageProvisioned struct {
+	Instances []StorageProvisioned `json:"instances"`
+}
+
 // DatastoreFilesystem holds the parameters for recording information about
 // the filesystem corresponding to the specified datastore.
 type DatastoreFilesystem struct {
 	DatastoreId storage.DatastoreId `json:"datastoreid"`
 	Filesystem  storage.Filesystem  `json:"filesystem"`
 }
+
+// LogRecord holds a structured log record sent by an agent.
+type LogRecord struct {
+	Time     time.Time   `json:"time"`
+	Module   string      `json:"module"`
+	Location string      `json:"location"`
+	Level    loggo.Level `json:"level"`
+	Message  string      `json:"message"`
+}
+
+// LogRecords holds the log records sent by an agent in a single
+// API call.
+type LogRecords struct {
+	Records []LogRecord `json:"records"`
+}
@@ -186,6 +186,7 @@ type ServiceDeploy struct {
 	Constraints   constraints.Value
 	ToMachineSpec string
 	Networks      []string
+	Storage       []storage.Directive
 }
 
 // ServiceUpdate holds the parameters for making the ServiceUpdate call.
@@ -209,6 +210,7 @@ type ServiceSetCharm struct {
 // ServiceExpose holds the parameters for making the ServiceExpose call.
 type ServiceExpose struct {
 	ServiceName string
+	SourceCIDRs []string `json:",omitempty"`
 }
 
 // ServiceSet holds the parameters for a ServiceSet
@@ -543,6 +545,31 @@ type ModifyEnvironUser struct {
 	Action  EnvironAction `json:"action"`
 }
 
+// ModifyEnvironAccess holds the parameters for making Client
+// ModifyEnvironmentAccess calls.
+type ModifyEnvironAccess struct {
+	Changes []ModifyEnvironUserAccess
+}
+
+// EnvironAccessAction is a change that can be made to a user's access
+// to an environment.
+type EnvironAccessAction string
+
+// Changes that can be made to a user's access to an environment.
+const (
+	GrantEnvAccess  EnvironAccessAction = "grant"
+	RevokeEnvAccess EnvironAccessAction = "revoke"
+)
+
+// ModifyEnvironUserAccess stores the parameters used to change a single
+// user's access in a Client.ModifyEnvironmentAccess call. Access is one
+// of "read", "write" or "admin".
+type ModifyEnvironUserAccess struct {
+	UserTag string              `json:"user-tag"`
+	Action  EnvironAccessAction `json:"action"`
+	Access  string              `json:"access"`
+}
+
 // SetEnvironAgentVersion contains the arguments for
 // SetEnvironAgentVersion client API call.
 type SetEnvironAgentVersion struct {
@@ -561,6 +588,35 @@ type StatusParams struct {
 	Patterns []string
 }
 
+// StatusHistory holds the parameters for the StatusHistory call.
+type StatusHistory struct {
+	// Tag identifies the unit or machine whose history is returned.
+	Tag string
+
+	// Size holds the maximum number of entries to return; zero
+	// means no limit.
+	Size int
+
+	// Before, if set, restricts the results to entries recorded
+	// strictly before the given time.
+	Before *time.Time
+}
+
+// StatusHistoryEntry holds a status of an entity as recorded in its
+// status history.
+type StatusHistoryEntry struct {
+	Status  Status
+	Info    string
+	Data    map[string]interface{}
+	Updated time.Time
+}
+
+// StatusHistoryResults holds the result of a StatusHistory call,
+// newest entry first.
+type StatusHistoryResults struct {
+	Statuses []StatusHistoryEntry
+}
+
 // SetRsyslogCertParams holds parameters for the SetRsyslogCert call.
 type SetRsyslogCertParams struct {
 	CACert []byte
@@ -776,6 +832,29 @@ const (
 	StatusDown = Status(multiwatcher.StatusDown)
 )
 
+const (
+	// The following statuses describe the workload of a unit, as
+	// reported by its charm, rather than the unit agent.
+
+	// The charm has not reported the workload status.
+	StatusUnknown = Status(multiwatcher.StatusUnknown)
+
+	// The workload is not yet available because the unit is
+	// installing or reconfiguring it.
+	StatusMaintenance = Status(multiwatcher.StatusMaintenance)
+
+	// The workload is waiting for some other part of the
+	// environment, such as a related service, to become available.
+	StatusWaiting = Status(multiwatcher.StatusWaiting)
+
+	// The workload cannot run until an operator intervenes, for
+	// instance by adding a relation or changing the configuration.
+	StatusBlocked = Status(multiwatcher.StatusBlocked)
+
+	// The workload is running and ready to serve.
+	StatusActive = Status(multiwatcher.StatusActive)
+)
+
 // DatastoreResult holds the result of an API call to retrieve details
 // of a datastore.
 type DatastoreResult struct {
@@ -788,3 +867,84 @@ type DatastoreResult struct {
 type DatastoreResults struct {
 	Results []DatastoreResult `json:"results,omitempty"`
 }
+
+// StorageInstance holds the details of a storage instance owned by a
+// unit. Location and Filesystem are only set once the storage instance
+// has been provisioned on the unit's machine.
+type StorageInstance struct {
+	Id         string              `json:"id"`
+	Name       string              `json:"name"`
+	Owner      string              `json:"owner"`
+	Source     string              `json:"source"`
+	Size       uint64              `json:"size"`
+	Options    string              `json:"options,omitempty"`
+	Location   string              `json:"location,omitempty"`
+	Filesystem *storage.Filesystem `json:"filesystem,omitempty"`
+}
+
+// StorageInstances holds a list of storage instances.
+type StorageInstances struct {
+	Instances []StorageInstance `json:"instances,omitempty"`
+}
+
+// StorageInstanceIds holds the ids of a set of storage instances.
+type StorageInstanceIds struct {
+	Ids []string `json:"ids"`
+}
+
+// StorageInstanceResult holds the result of an API call to retrieve
+// details of a storage instance.
+type StorageInstanceResult struct {
+	Result StorageInstance `json:"result"`
+	Error  *Error          `json:"error,omitempty"`
+}
+
+// StorageInstanceResults holds the result of an API call to retrieve
+// details of multiple storage instances.
+type StorageInstanceResults struct {
+	Results []StorageInstanceResult `json:"results,omitempty"`
+}
+
+// Subnet holds the details of a subnet known to juju.
+type Subnet struct {
+	CIDR       string `json:"cidr"`
+	ProviderId string `json:"provider-id,omitempty"`
+	VLANTag    int    `json:"vlan-tag,omitempty"`
+	Zone       string `json:"zone,omitempty"`
+	Space      string `json:"space,omitempty"`
+	Life       Life   `json:"life"`
+}
+
+// Subnets holds a list of subnets.
+type Subnets struct {
+	Subnets []Subnet `json:"subnets,omitempty"`
+}
+
+// AddSubnet holds the arguments for adding a subnet to juju. If Space
+// is set, the space must already exist.
+type AddSubnet struct {
+	CIDR       string `json:"cidr"`
+	ProviderId string `json:"provider-id,omitempty"`
+	VLANTag    int    `json:"vlan-tag,omitempty"`
+	Zone       string `json:"zone,omitempty"`
+	Space      string `json:"space,omitempty"`
+}
+
+// Space holds the details of a space and the CIDRs of the subnets
+// that belong to it.
+type Space struct {
+	Name    string   `json:"name"`
+	Subnets []string `json:"subnets,omitempty"`
+}
+
+// Spaces holds a list of spaces.
+type Spaces struct {
+	Spaces []Space `json:"spaces,omitempty"`
+}
+
+// CreateSpace holds the arguments for creating a space holding the
+// subnets with the given CIDRs.
+type CreateSpace struct {
+	Name    string   `json:"name"`
+	Subnets []string `json:"subnets,omitempty"`
+}
@@ -6,6 +6,8 @@ package provisioner
 import (
 	"fmt"
 
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
 	"github.com/juju/names"
 	"github.com/juju/utils/set"
 
@@ -14,11 +16,14 @@ import (
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/container"
 	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/state/watcher"
 )
 
+var logger = loggo.GetLogger("juju.apiserver.provisioner")
+
 func init() {
 	common.RegisterStandardFacade("Provisioner", 0, NewProvisionerAPI)
 }
@@ -358,14 +363,14 @@ func (p *ProvisionerAPI) ProvisioningInfo(args params.Entities) (params.Provisio
 		}
 		machine, err := p.getMachine(canAccess, tag)
 		if err == nil {
-			result.Results[i].Result, err = getProvisioningInfo(machine)
+			result.Results[i].Result, err = getProvisioningInfo(p.st, machine)
 		}
 		result.Results[i].Error = common.ServerError(err)
 	}
 	return result, nil
 }
 
-func getProvisioningInfo(m *state.Machine) (*params.ProvisioningInfo, error) {
+func getProvisioningInfo(st *state.State, m *state.Machine) (*params.ProvisioningInfo, error) {
 	cons, err := m.Constraints()
 	if err != nil {
 		return nil, err
@@ -380,19 +385,100 @@ func getProvisioningInfo(m *state.Machine) (*params.ProvisioningInfo, error) {
 	if err != nil {
 		return nil, err
 	}
+	subnets, err := spaceSubnets(st, cons)
+	if err != nil {
+		return nil, err
+	}
 	var jobs []multiwatcher.MachineJob
 	for _, job := range m.Jobs() {
 		jobs = append(jobs, job.ToParams())
 	}
+	services, err := principalServices(m)
+	if err != nil {
+		return nil, err
+	}
 	return &params.ProvisioningInfo{
 		Constraints: cons,
 		Series:      m.Series(),
 		Placement:   m.Placement(),
 		Networks:    networks,
 		Jobs:        jobs,
+		Subnets:     subnets,
+		Services:    services,
 	}, nil
 }
 
+// principalServices returns the sorted names of the services of the
+// principal units assigned to the given machine.
+func principalServices(m *state.Machine) ([]string, error) {
+	units, err := m.Units()
+	if err != nil {
+		return nil, err
+	}
+	services := make(set.Strings)
+	for _, unit := range units {
+		if unit.IsPrincipal() {
+			services.Add(unit.ServiceName())
+		}
+	}
+	if services.Size() == 0 {
+		return nil, nil
+	}
+	return services.SortedValues(), nil
+}
+
+// spaceSubnets returns the subnets a machine with the given
+// constraints may be started in, according to its spaces constraint:
+// the alive subnets of the included spaces (or all known subnets, if
+// only exclusions are given), less those in any excluded space.
+// Unknown spaces are taken to hold no subnets. Subnets without a
+// provider id are skipped, as the provider cannot be asked to start
+// an instance in them.
+func spaceSubnets(st *state.State, cons constraints.Value) ([]network.BasicInfo, error) {
+	if !cons.HaveSpaces() {
+		return nil, nil
+	}
+	included := cons.IncludeSpaces()
+	excluded := set.NewStrings(cons.ExcludeSpaces()...)
+	var candidates []*state.Subnet
+	if len(included) == 0 {
+		all, err := st.AllSubnets()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		candidates = all
+	}
+	for _, name := range included {
+		space, err := st.Space(name)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		subnets, err := space.Subnets()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		candidates = append(candidates, subnets...)
+	}
+	var result []network.BasicInfo
+	for _, subnet := range candidates {
+		if subnet.Life() != state.Alive || excluded.Contains(subnet.SpaceName()) {
+			continue
+		}
+		if subnet.ProviderId() == "" {
+			logger.Warningf("skipping subnet %q in space %q: no provider id", subnet.CIDR(), subnet.SpaceName())
+			continue
+		}
+		result = append(result, network.BasicInfo{
+			CIDR:       subnet.CIDR(),
+			ProviderId: network.Id(subnet.ProviderId()),
+			VLANTag:    subnet.VLANTag(),
+		})
+	}
+	return result, nil
+}
+
 // DistributionGroup returns, for each given machine entity,
 // a slice of instance.Ids that belong to the same distribution
 // group as that machine. This information may be used to
@@ -453,16 +539,13 @@ func environManagerInstances(st *state.State) ([]instance.Id, error) {
 // commonServiceInstances returns instances with
 // services in common with the specified machine.
 func commonServiceInstances(st *state.State, m *state.Machine) ([]instance.Id, error) {
-	units, err := m.Units()
+	services, err := principalServices(m)
 	if err != nil {
 		return nil, err
 	}
 	instanceIdSet := make(set.Strings)
-	for _, unit := range units {
-		if !unit.IsPrincipal() {
-			continue
-		}
-		instanceIds, err := state.ServiceInstances(st, unit.ServiceName())
+	for _, service := range services {
+		instanceIds, err := state.ServiceInstances(st, service)
 		if err != nil {
 			return nil, err
 		}
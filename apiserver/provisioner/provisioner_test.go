@@ -765,6 +765,66 @@ func (s *withoutStateServerSuite) TestProvisioningInfo(c *gc.C) {
 	})
 }
 
+func (s *withoutStateServerSuite) TestProvisioningInfoWithSpaces(c *gc.C) {
+	for _, info := range []state.SubnetInfo{
+		{CIDR: "10.0.0.0/24", ProviderId: "subnet-0"},
+		{CIDR: "10.0.1.0/24", ProviderId: "subnet-1", VLANTag: 42},
+		{CIDR: "10.0.2.0/24", ProviderId: "subnet-2"},
+		{CIDR: "10.0.3.0/24", ProviderId: "subnet-3"},
+		// Subnets without a provider id are never returned.
+		{CIDR: "10.0.4.0/24"},
+	} {
+		_, err := s.State.AddSubnet(info)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	_, err := s.State.AddSpace("db", []string{"10.0.0.0/24", "10.0.1.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("admin", []string{"10.0.2.0/24", "10.0.4.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	for i, test := range []struct {
+		spaces  string
+		subnets []network.BasicInfo
+	}{{
+		spaces: "db",
+		subnets: []network.BasicInfo{
+			{CIDR: "10.0.0.0/24", ProviderId: "subnet-0"},
+			{CIDR: "10.0.1.0/24", ProviderId: "subnet-1", VLANTag: 42},
+		},
+	}, {
+		spaces: "db,admin,unknown",
+		subnets: []network.BasicInfo{
+			{CIDR: "10.0.0.0/24", ProviderId: "subnet-0"},
+			{CIDR: "10.0.1.0/24", ProviderId: "subnet-1", VLANTag: 42},
+			{CIDR: "10.0.2.0/24", ProviderId: "subnet-2"},
+		},
+	}, {
+		spaces: "^db",
+		subnets: []network.BasicInfo{
+			{CIDR: "10.0.2.0/24", ProviderId: "subnet-2"},
+			{CIDR: "10.0.3.0/24", ProviderId: "subnet-3"},
+		},
+	}, {
+		spaces: "unknown",
+	}} {
+		c.Logf("test %d: spaces=%s", i, test.spaces)
+		template := state.MachineTemplate{
+			Series:      "quantal",
+			Jobs:        []state.MachineJob{state.JobHostUnits},
+			Constraints: constraints.MustParse("spaces=" + test.spaces),
+		}
+		machine, err := s.State.AddOneMachine(template)
+		c.Assert(err, jc.ErrorIsNil)
+
+		args := params.Entities{Entities: []params.Entity{{Tag: machine.Tag().String()}}}
+		result, err := s.provisioner.ProvisioningInfo(args)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(result.Results, gc.HasLen, 1)
+		c.Assert(result.Results[0].Error, gc.IsNil)
+		c.Check(result.Results[0].Result.Subnets, jc.DeepEquals, test.subnets)
+	}
+}
+
 func (s *withoutStateServerSuite) TestProvisioningInfoPermissions(c *gc.C) {
 	// Login as a machine agent for machine 0.
 	anAuthorizer := s.authorizer
@@ -799,6 +859,28 @@ func (s *withoutStateServerSuite) TestProvisioningInfoPermissions(c *gc.C) {
 	})
 }
 
+func (s *withoutStateServerSuite) TestProvisioningInfoServices(c *gc.C) {
+	for _, name := range []string{"wordpress", "mysql"} {
+		svc := s.AddTestingService(c, name, s.AddTestingCharm(c, name))
+		unit, err := svc.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.AssignToMachine(s.machines[0])
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: s.machines[0].Tag().String()},
+		{Tag: s.machines[1].Tag().String()},
+	}}
+	results, err := s.provisioner.ProvisioningInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Check(results.Results[0].Result.Services, jc.DeepEquals, []string{"mysql", "wordpress"})
+	c.Assert(results.Results[1].Error, gc.IsNil)
+	c.Check(results.Results[1].Result.Services, gc.HasLen, 0)
+}
+
 func (s *withoutStateServerSuite) TestConstraints(c *gc.C) {
 	// Add a machine with some constraints.
 	cons := constraints.MustParse("cpu-cores=123", "mem=8G", "networks=net3,^net4")
@@ -39,7 +39,7 @@ func newRestoreInProgressRoot(finder rpc.MethodFinder) *restoreInProgressRoot {
 }
 
 // FindMethod extended srvRoot.FindMethod. It returns aboutToRestoreError
-// for all API calls except Client.Restore
+// for all API calls except Backups.Restore
 // for use while Juju is preparing to restore a backup.
 func (r *aboutToRestoreRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
 	caller, err := r.MethodFinder.FindMethod(rootName, version, methodName)
@@ -53,7 +53,7 @@ func (r *aboutToRestoreRoot) FindMethod(rootName string, version int, methodName
 }
 
 var allowedMethodsAboutToRestore = set.NewStrings(
-	"Client.Restore", // for "juju restore"
+	"Backups.Restore", // for "juju backups restore"
 )
 
 func isMethodAllowedAboutToRestore(rootName, methodName string) bool {
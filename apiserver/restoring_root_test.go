@@ -4,7 +4,7 @@
 package apiserver_test
 
 import (
-	_ "github.com/juju/testing/checkers"
+	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver"
@@ -17,25 +17,23 @@ type restoreRootSuite struct {
 
 var _ = gc.Suite(&restoreRootSuite{})
 
-// TODO(perrito666): Uncomment when Restore lands.
-//func (r *restoreRootSuite) TestFindAllowedMethodWhenPreparing(c *gc.C) {
-//	root := apiserver.TestingAboutToRestoreRoot(nil)
-//
-//	caller, err := root.FindMethod("Client", 0, "Restore")
-//
-//	c.Assert(err, jc.ErrorIsNil)
-//	c.Assert(caller, gc.NotNil)
-//}
-
-// TODO(perrito666): Uncomment when Restore lands.
-//func (r *restoreRootSuite) TestNothingAllowedMethodWhenPreparing(c *gc.C) {
-//	root := apiserver.TestingRestoreInProgressRoot(nil)
-//
-//	caller, err := root.FindMethod("Client", 0, "Restore")
-//
-//	c.Assert(err, jc.ErrorIsNil)
-//	c.Assert(caller, gc.NotNil)
-//}
+func (r *restoreRootSuite) TestFindAllowedMethodWhenPreparing(c *gc.C) {
+	root := apiserver.TestingAboutToRestoreRoot(nil)
+
+	caller, err := root.FindMethod("Backups", 0, "Restore")
+
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(caller, gc.NotNil)
+}
+
+func (r *restoreRootSuite) TestNothingAllowedMethodWhenRestoring(c *gc.C) {
+	root := apiserver.TestingRestoreInProgressRoot(nil)
+
+	caller, err := root.FindMethod("Backups", 0, "Restore")
+
+	c.Assert(err, gc.ErrorMatches, "juju restore is in progress - Juju api is off to prevent data loss")
+	c.Assert(caller, gc.IsNil)
+}
 
 func (r *restoreRootSuite) TestFindDisallowedMethodWhenPreparing(c *gc.C) {
 	root := apiserver.TestingAboutToRestoreRoot(nil)
@@ -50,10 +50,11 @@ type apiHandler struct {
 
 var _ = (*apiHandler)(nil)
 
-// newApiHandler returns a new apiHandler.
-func newApiHandler(srv *Server, rpcConn *rpc.Conn, reqNotifier *requestNotifier) (*apiHandler, error) {
+// newApiHandler returns a new apiHandler serving the given state, which
+// is that of the environment the client connected to.
+func newApiHandler(srv *Server, st *state.State, rpcConn *rpc.Conn, reqNotifier *requestNotifier) (*apiHandler, error) {
 	r := &apiHandler{
-		state:     srv.state,
+		state:     st,
 		resources: common.NewResources(),
 		rpcConn:   rpcConn,
 	}
@@ -124,10 +125,10 @@ type apiRoot struct {
 	objectCache map[objectKey]reflect.Value
 }
 
-// newApiRoot returns a new apiRoot.
-func newApiRoot(srv *Server, resources *common.Resources, authorizer common.Authorizer) *apiRoot {
+// newApiRoot returns a new apiRoot serving the given state.
+func newApiRoot(st *state.State, resources *common.Resources, authorizer common.Authorizer) *apiRoot {
 	r := &apiRoot{
-		state:       srv.state,
+		state:       st,
 		resources:   resources,
 		authorizer:  authorizer,
 		objectCache: make(map[objectKey]reflect.Value),
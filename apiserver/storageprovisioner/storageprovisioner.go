@@ -0,0 +1,129 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("StorageProvisioner", 1, NewStorageProvisionerAPI)
+}
+
+var logger = loggo.GetLogger("juju.apiserver.storageprovisioner")
+
+// StorageProvisionerAPI provides access to the StorageProvisioner API
+// facade, used by machine agents to provision the storage instances of
+// the units assigned to their machines.
+type StorageProvisionerAPI struct {
+	st          *state.State
+	authorizer  common.Authorizer
+	getAuthFunc common.GetAuthFunc
+}
+
+// NewStorageProvisionerAPI creates a new server-side StorageProvisioner
+// API facade.
+func NewStorageProvisionerAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*StorageProvisionerAPI, error) {
+
+	if !authorizer.AuthMachineAgent() {
+		return nil, common.ErrPerm
+	}
+
+	authEntityTag := authorizer.GetAuthTag()
+	getAuthFunc := func() (common.AuthFunc, error) {
+		return func(tag names.Tag) bool {
+			// A machine agent can always access its own machine.
+			return tag == authEntityTag
+		}, nil
+	}
+
+	return &StorageProvisionerAPI{
+		st:          st,
+		authorizer:  authorizer,
+		getAuthFunc: getAuthFunc,
+	}, nil
+}
+
+// StorageInstances returns the storage instances owned by the units
+// assigned to each of the given machines.
+func (s *StorageProvisionerAPI) StorageInstances(args params.Entities) (params.StorageInstancesResults, error) {
+	result := params.StorageInstancesResults{
+		Results: make([]params.StorageInstancesResult, len(args.Entities)),
+	}
+	canAccess, err := s.getAuthFunc()
+	if err != nil {
+		return result, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil || !canAccess(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		instances, err := s.machineStorageInstances(tag.Id())
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Result = make([]params.StorageInstance, len(instances))
+		for j, instance := range instances {
+			result.Results[i].Result[j] = common.StorageInstanceParams(instance)
+		}
+	}
+	return result, nil
+}
+
+// SetProvisioned records the location and filesystem of each of the
+// given storage instances. The storage instances must be owned by
+// units assigned to the authenticated machine.
+func (s *StorageProvisionerAPI) SetProvisioned(args params.SetStorageProvisioned) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Instances)),
+	}
+	canAccess, err := s.getAuthFunc()
+	if err != nil {
+		return result, err
+	}
+	for i, arg := range args.Instances {
+		err := s.setProvisioned(canAccess, arg)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (s *StorageProvisionerAPI) machineStorageInstances(machineId string) ([]*state.StorageInstance, error) {
+	machine, err := s.st.Machine(machineId)
+	if err != nil {
+		return nil, err
+	}
+	return machine.StorageInstances()
+}
+
+func (s *StorageProvisionerAPI) setProvisioned(canAccess common.AuthFunc, arg params.StorageProvisioned) error {
+	instance, err := s.st.StorageInstance(arg.Id)
+	if err != nil {
+		// Don't reveal whether a storage instance exists to
+		// machines that are not entitled to access it.
+		logger.Debugf("cannot get storage instance %q: %v", arg.Id, err)
+		return common.ErrPerm
+	}
+	unit, err := s.st.Unit(instance.Owner())
+	if err != nil {
+		return common.ErrPerm
+	}
+	machineId, err := unit.AssignedMachineId()
+	if err != nil || !canAccess(names.NewMachineTag(machineId)) {
+		return common.ErrPerm
+	}
+	return instance.SetProvisioned(arg.Location, arg.Filesystem)
+}
@@ -0,0 +1,123 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/storageprovisioner"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/storage"
+)
+
+type storageProvisionerSuite struct {
+	jujutesting.JujuConnSuite
+
+	machine    *state.Machine
+	unit       *state.Unit
+	authorizer apiservertesting.FakeAuthorizer
+	api        *storageprovisioner.StorageProvisionerAPI
+}
+
+var _ = gc.Suite(&storageProvisionerSuite{})
+
+func (s *storageProvisionerSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+
+	service := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	err := service.SetStorageDirectives([]storage.Directive{
+		{Name: "data", Source: storage.LoopSource, Count: 1, Size: 1024},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.unit, err = service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	s.machine, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.AssignToMachine(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.authorizer = apiservertesting.FakeAuthorizer{Tag: s.machine.Tag()}
+	s.api, err = storageprovisioner.NewStorageProvisionerAPI(s.State, common.NewResources(), s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *storageProvisionerSuite) TestNewStorageProvisionerAPINonMachine(c *gc.C) {
+	authorizer := apiservertesting.FakeAuthorizer{Tag: s.unit.Tag()}
+	_, err := storageprovisioner.NewStorageProvisionerAPI(s.State, common.NewResources(), authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *storageProvisionerSuite) TestStorageInstances(c *gc.C) {
+	results, err := s.api.StorageInstances(params.Entities{
+		Entities: []params.Entity{
+			{Tag: s.machine.Tag().String()},
+			{Tag: "machine-42"},
+			{Tag: s.unit.Tag().String()},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.StorageInstancesResults{
+		Results: []params.StorageInstancesResult{{
+			Result: []params.StorageInstance{{
+				Id:     "data/0",
+				Name:   "data",
+				Owner:  "wordpress/0",
+				Source: "loop",
+				Size:   1024,
+			}},
+		}, {
+			Error: apiservertesting.ErrUnauthorized,
+		}, {
+			Error: apiservertesting.ErrUnauthorized,
+		}},
+	})
+}
+
+func (s *storageProvisionerSuite) TestSetProvisioned(c *gc.C) {
+	fs := storage.Filesystem{Type: "ext4"}
+	results, err := s.api.SetProvisioned(params.SetStorageProvisioned{
+		Instances: []params.StorageProvisioned{
+			{Id: "data/0", Location: "/var/lib/juju/storage/data/0", Filesystem: fs},
+			{Id: "data/42", Location: "/mnt", Filesystem: fs},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: nil},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	instance, err := s.State.StorageInstance("data/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instance.Location(), gc.Equals, "/var/lib/juju/storage/data/0")
+	c.Assert(instance.Filesystem(), jc.DeepEquals, &fs)
+}
+
+func (s *storageProvisionerSuite) TestSetProvisionedOtherMachine(c *gc.C) {
+	other, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	authorizer := apiservertesting.FakeAuthorizer{Tag: other.Tag()}
+	api, err := storageprovisioner.NewStorageProvisionerAPI(s.State, common.NewResources(), authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := api.SetProvisioned(params.SetStorageProvisioned{
+		Instances: []params.StorageProvisioned{
+			{Id: "data/0", Location: "/mnt"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{Error: apiservertesting.ErrUnauthorized}},
+	})
+	instance, err := s.State.StorageInstance("data/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instance.Provisioned(), jc.IsFalse)
+}
@@ -4,5 +4,6 @@
 package uniter
 
 var (
-	GetZone = &getZone
+	GetZone           = &getZone
+	LeadershipManager = &leadershipManager
 )
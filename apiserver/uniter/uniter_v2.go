@@ -0,0 +1,237 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The uniter package implements the API interface used by the uniter
+// worker. This file contains the API facade version 2.
+package uniter
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/leadership"
+	"github.com/juju/juju/lease"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+func init() {
+	common.RegisterStandardFacade("Uniter", 2, NewUniterAPIV2)
+}
+
+// leadershipManager is used to check and claim service leadership on
+// behalf of units. It is a variable so it can be replaced in tests.
+var leadershipManager leadership.LeadershipManager = leadership.NewLeadershipManager(lease.Manager())
+
+// UniterAPIV2 implements the API facade version 2, used by the uniter
+// worker.
+type UniterAPIV2 struct {
+	UniterAPIV1
+}
+
+// NewUniterAPIV2 creates a new instance of the Uniter API, version 2.
+func NewUniterAPIV2(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*UniterAPIV2, error) {
+	baseAPI, err := NewUniterAPIV1(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &UniterAPIV2{
+		UniterAPIV1: *baseAPI,
+	}, nil
+}
+
+// IsLeader reports whether each given unit is currently the leader of
+// its service. Leadership is claimed on behalf of the unit if no
+// other unit holds it.
+func (u *UniterAPIV2) IsLeader(args params.Entities) (params.BoolResults, error) {
+	result := params.BoolResults{
+		Results: make([]params.BoolResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.BoolResults{}, err
+	}
+	for i, entity := range args.Entities {
+		unit, err := u.getAccessibleUnit(canAccess, entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		isLeader, err := claimLeadership(unit)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Result = isLeader
+	}
+	return result, nil
+}
+
+// LeaderSettings returns the leader settings of each given service.
+func (u *UniterAPIV2) LeaderSettings(args params.Entities) (params.LeaderSettingsResults, error) {
+	result := params.LeaderSettingsResults{
+		Results: make([]params.LeaderSettingsResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessService()
+	if err != nil {
+		return params.LeaderSettingsResults{}, err
+	}
+	for i, entity := range args.Entities {
+		service, err := u.getAccessibleService(canAccess, entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		settings, err := service.LeaderSettings()
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Settings = params.LeaderSettings(settings)
+	}
+	return result, nil
+}
+
+// MergeLeaderSettings merges the given settings into the leader
+// settings of each given unit's service. The request is refused
+// unless the unit is its service's leader.
+func (u *UniterAPIV2) MergeLeaderSettings(args params.MergeLeaderSettingsParams) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Params)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Params {
+		unit, err := u.getAccessibleUnit(canAccess, arg.Unit)
+		if err == nil {
+			err = mergeLeaderSettings(unit, arg.Settings)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// WatchLeaderSettings returns a NotifyWatcher for observing changes to
+// the leader settings of each given service.
+func (u *UniterAPIV2) WatchLeaderSettings(args params.Entities) (params.NotifyWatchResults, error) {
+	result := params.NotifyWatchResults{
+		Results: make([]params.NotifyWatchResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessService()
+	if err != nil {
+		return params.NotifyWatchResults{}, err
+	}
+	for i, entity := range args.Entities {
+		service, err := u.getAccessibleService(canAccess, entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		watch := service.WatchLeaderSettings()
+		// Consume the initial event. Technically, API
+		// calls to Watch 'transmit' the initial event
+		// in the Watch response. But NotifyWatchers
+		// have no state to transmit.
+		if _, ok := <-watch.Changes(); ok {
+			result.Results[i].NotifyWatcherId = u.resources.Register(watch)
+		} else {
+			result.Results[i].Error = common.ServerError(watcher.EnsureErr(watch))
+		}
+	}
+	return result, nil
+}
+
+// SetWorkloadStatus sets the status of the workload of each given
+// unit, as reported by its charm.
+func (u *UniterAPIV2) SetWorkloadStatus(args params.SetStatus) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Entities {
+		unit, err := u.getAccessibleUnit(canAccess, arg.Tag)
+		if err == nil {
+			err = unit.SetWorkloadStatus(state.Status(arg.Status), arg.Info)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// WorkloadStatus returns the status of the workload of each given
+// unit, as last reported by its charm.
+func (u *UniterAPIV2) WorkloadStatus(args params.Entities) (params.StatusResults, error) {
+	result := params.StatusResults{
+		Results: make([]params.StatusResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.StatusResults{}, err
+	}
+	for i, entity := range args.Entities {
+		unit, err := u.getAccessibleUnit(canAccess, entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		status, info, err := unit.WorkloadStatus()
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Id = unit.Name()
+		result.Results[i].Status = params.Status(status)
+		result.Results[i].Info = info
+	}
+	return result, nil
+}
+
+func (u *UniterAPIV2) getAccessibleUnit(canAccess common.AuthFunc, unitTag string) (*state.Unit, error) {
+	tag, err := names.ParseUnitTag(unitTag)
+	if err != nil || !canAccess(tag) {
+		return nil, common.ErrPerm
+	}
+	return u.getUnit(tag)
+}
+
+func (u *UniterAPIV2) getAccessibleService(canAccess common.AuthFunc, serviceTag string) (*state.Service, error) {
+	tag, err := names.ParseServiceTag(serviceTag)
+	if err != nil || !canAccess(tag) {
+		return nil, common.ErrPerm
+	}
+	return u.getService(tag)
+}
+
+// claimLeadership claims leadership of the unit's service on behalf of
+// the unit, and reports whether the unit is the leader.
+func claimLeadership(unit *state.Unit) (bool, error) {
+	_, err := leadershipManager.ClaimLeadership(unit.ServiceName(), unit.Name())
+	if errors.Cause(err) == leadership.LeadershipClaimDeniedErr {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	return true, nil
+}
+
+func mergeLeaderSettings(unit *state.Unit, settings params.LeaderSettings) error {
+	isLeader, err := claimLeadership(unit)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !isLeader {
+		return common.ErrPerm
+	}
+	service, err := unit.Service()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return service.MergeLeaderSettings(settings)
+}
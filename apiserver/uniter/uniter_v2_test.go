@@ -0,0 +1,233 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/apiserver/uniter"
+	"github.com/juju/juju/leadership"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type uniterV2Suite struct {
+	uniterBaseSuite
+
+	uniter  *uniter.UniterAPIV2
+	leaders map[string]string
+}
+
+var _ = gc.Suite(&uniterV2Suite{})
+
+func (s *uniterV2Suite) SetUpTest(c *gc.C) {
+	s.uniterBaseSuite.setUpTest(c)
+
+	s.leaders = make(map[string]string)
+	s.PatchValue(uniter.LeadershipManager, &fakeLeadershipManager{s.leaders})
+
+	uniterAPIV2, err := uniter.NewUniterAPIV2(
+		s.State,
+		s.resources,
+		s.authorizer,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	s.uniter = uniterAPIV2
+}
+
+func (s *uniterV2Suite) TestUniterFailsWithNonUnitAgentUser(c *gc.C) {
+	factory := func(st *state.State, res *common.Resources, auth common.Authorizer) error {
+		_, err := uniter.NewUniterAPIV2(st, res, auth)
+		return err
+	}
+	s.testUniterFailsWithNonUnitAgentUser(c, factory)
+}
+
+func (s *uniterV2Suite) TestIsLeader(c *gc.C) {
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+		{Tag: "service-wordpress"},
+	}}
+	result, err := s.uniter.IsLeader(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.BoolResults{
+		Results: []params.BoolResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Result: true},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+	c.Assert(s.leaders["wordpress"], gc.Equals, "wordpress/0")
+
+	s.leaders["wordpress"] = "wordpress/1"
+	result, err = s.uniter.IsLeader(params.Entities{Entities: []params.Entity{
+		{Tag: "unit-wordpress-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.BoolResults{
+		Results: []params.BoolResult{{Result: false}},
+	})
+}
+
+func (s *uniterV2Suite) TestLeaderSettings(c *gc.C) {
+	err := s.wordpress.MergeLeaderSettings(map[string]string{"foo": "bar"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "service-mysql"},
+		{Tag: "service-wordpress"},
+		{Tag: "unit-wordpress-0"},
+	}}
+	result, err := s.uniter.LeaderSettings(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.LeaderSettingsResults{
+		Results: []params.LeaderSettingsResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Settings: params.LeaderSettings{"foo": "bar"}},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *uniterV2Suite) TestMergeLeaderSettings(c *gc.C) {
+	args := params.MergeLeaderSettingsParams{Params: []params.MergeLeaderSettingsParam{
+		{Unit: "unit-mysql-0", Settings: params.LeaderSettings{"foo": "bar"}},
+		{Unit: "unit-wordpress-0", Settings: params.LeaderSettings{"foo": "bar"}},
+	}}
+	result, err := s.uniter.MergeLeaderSettings(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+		},
+	})
+	settings, err := s.wordpress.LeaderSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, map[string]string{"foo": "bar"})
+}
+
+func (s *uniterV2Suite) TestMergeLeaderSettingsNotLeader(c *gc.C) {
+	s.leaders["wordpress"] = "wordpress/1"
+	args := params.MergeLeaderSettingsParams{Params: []params.MergeLeaderSettingsParam{
+		{Unit: "unit-wordpress-0", Settings: params.LeaderSettings{"foo": "bar"}},
+	}}
+	result, err := s.uniter.MergeLeaderSettings(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+	settings, err := s.wordpress.LeaderSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.HasLen, 0)
+}
+
+func (s *uniterV2Suite) TestWatchLeaderSettings(c *gc.C) {
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "service-mysql"},
+		{Tag: "service-wordpress"},
+	}}
+	result, err := s.uniter.WatchLeaderSettings(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.NotifyWatchResults{
+		Results: []params.NotifyWatchResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{NotifyWatcherId: "1"},
+		},
+	})
+
+	// Verify the resource was registered and stop when done
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	resource := s.resources.Get("1")
+	defer statetesting.AssertStop(c, resource)
+
+	// Check that the Watch has consumed the initial event ("returned" in
+	// the Watch call)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, resource.(state.NotifyWatcher))
+	wc.AssertNoChange()
+
+	err = s.wordpress.MergeLeaderSettings(map[string]string{"foo": "bar"})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
+func (s *uniterV2Suite) TestSetWorkloadStatus(c *gc.C) {
+	args := params.SetStatus{Entities: []params.EntityStatus{
+		{Tag: "unit-mysql-0", Status: params.StatusActive},
+		{Tag: "unit-wordpress-0", Status: params.StatusBlocked, Info: "need a database"},
+		{Tag: "unit-wordpress-0", Status: params.StatusStarted},
+		{Tag: "unit-foo-42", Status: params.StatusActive},
+	}}
+	result, err := s.uniter.SetWorkloadStatus(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{&params.Error{Message: `cannot set invalid workload status "started"`}},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	status, info, err := s.wordpressUnit.WorkloadStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.StatusBlocked)
+	c.Assert(info, gc.Equals, "need a database")
+}
+
+func (s *uniterV2Suite) TestWorkloadStatus(c *gc.C) {
+	err := s.wordpressUnit.SetWorkloadStatus(state.StatusWaiting, "waiting for mysql")
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "service-wordpress"},
+	}}
+	result, err := s.uniter.WorkloadStatus(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.StatusResults{
+		Results: []params.StatusResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Id: "wordpress/0", Status: params.StatusWaiting, Info: "waiting for mysql"},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+// fakeLeadershipManager implements leadership.LeadershipManager,
+// granting leadership to the first unit that claims it.
+type fakeLeadershipManager struct {
+	leaders map[string]string
+}
+
+func (m *fakeLeadershipManager) ClaimLeadership(serviceId, unitId string) (time.Duration, error) {
+	if leader, ok := m.leaders[serviceId]; ok && leader != unitId {
+		return 0, leadership.LeadershipClaimDeniedErr
+	}
+	m.leaders[serviceId] = unitId
+	return time.Minute, nil
+}
+
+func (m *fakeLeadershipManager) ReleaseLeadership(serviceId, unitId string) error {
+	delete(m.leaders, serviceId)
+	return nil
+}
+
+func (m *fakeLeadershipManager) BlockUntilLeadershipReleased(serviceId string) error {
+	return nil
+}
@@ -26,6 +26,11 @@ var inUpgradeError = errors.New("upgrade in progress - Juju functionality is lim
 
 var allowedMethodsDuringUpgrades = set.NewStrings(
 	"FullStatus",     // for "juju status"
+	"StatusHistory",  // for "juju status-history"
+	"ListStorage",    // for "juju storage list"
+	"ShowStorage",    // for "juju storage show"
+	"ListSubnets",    // for "juju subnet list"
+	"ListSpaces",     // for "juju space list"
 	"EnvironmentGet", // for "juju ssh"
 	"PrivateAddress", // for "juju ssh"
 	"PublicAddress",  // for "juju ssh"
@@ -5,13 +5,23 @@
 package audit
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/juju/loggo"
 )
 
 var logger = loggo.GetLogger("audit")
 
+// MaxArgumentsLength is the maximum length of the argument summary
+// recorded in an Entry.
+const MaxArgumentsLength = 1024
+
+// redacted replaces the values of secret arguments in summaries.
+const redacted = "<redacted>"
+
 // Tagger represents anything that implements a Tag method.
 type Tagger interface {
 	Tag() string
@@ -29,3 +39,103 @@ func Audit(user Tagger, format string, args ...interface{}) {
 	// recorded, not Audit itself.
 	logger.Logf(loggo.INFO, fmt.Sprintf("%s: %s", user.Tag(), format), args...)
 }
+
+// Entry describes an operation performed by a user against an
+// environment through the API.
+type Entry struct {
+	// Timestamp records when the operation completed.
+	Timestamp time.Time
+
+	// EnvUUID identifies the environment the operation was
+	// performed against.
+	EnvUUID string
+
+	// User holds the tag of the entity that performed the operation.
+	User string
+
+	// Facade, Version and Method identify the API call that was made.
+	Facade  string
+	Version int
+	Method  string
+
+	// Arguments holds a summary of the arguments of the call, as
+	// returned by SummarizeArguments.
+	Arguments string
+
+	// Error holds the error returned by the call, if any.
+	Error string
+}
+
+// Operation returns the API call recorded by the entry in the
+// form "Facade.Method".
+func (e Entry) Operation() string {
+	return e.Facade + "." + e.Method
+}
+
+// Outcome returns "success" if the audited operation completed
+// without error and "failure" otherwise.
+func (e Entry) Outcome() string {
+	if e.Error == "" {
+		return "success"
+	}
+	return "failure"
+}
+
+// SummarizeArguments returns a JSON summary of the given call
+// arguments suitable for recording in an Entry. The values of
+// any fields that look like passwords or secrets are redacted and
+// the summary is truncated to MaxArgumentsLength bytes.
+func SummarizeArguments(args interface{}) string {
+	if args == nil {
+		return ""
+	}
+	data, err := json.Marshal(args)
+	if err != nil {
+		return fmt.Sprintf("<cannot summarize arguments: %v>", err)
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return fmt.Sprintf("<cannot summarize arguments: %v>", err)
+	}
+	if data, err = json.Marshal(redact(generic)); err != nil {
+		return fmt.Sprintf("<cannot summarize arguments: %v>", err)
+	}
+	summary := string(data)
+	if len(summary) > MaxArgumentsLength {
+		summary = summary[:MaxArgumentsLength-3] + "..."
+	}
+	return summary
+}
+
+// redact replaces, in place, the values of any map entries in v
+// whose keys suggest they hold secrets.
+func redact(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if isSecret(key) {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redact(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redact(value)
+		}
+	}
+	return v
+}
+
+func isSecret(key string) bool {
+	key = strings.ToLower(key)
+	if key == "key" {
+		return true
+	}
+	for _, word := range []string{"password", "secret", "private-key", "privatekey", "credentials", "webhook-auth", "encryptionkey", "encryption-key"} {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
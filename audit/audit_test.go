@@ -4,6 +4,7 @@
 package audit
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/juju/loggo"
@@ -56,3 +57,66 @@ func (*auditSuite) TestAuditWithEmptyTagWillPanic(c *gc.C) {
 	f := func() { Audit(&mockUser{}, "should never be written") }
 	c.Assert(f, gc.PanicMatches, "user tag cannot be blank")
 }
+
+func (*auditSuite) TestEntryOutcome(c *gc.C) {
+	entry := Entry{Facade: "Client", Method: "ServiceDeploy"}
+	c.Assert(entry.Operation(), gc.Equals, "Client.ServiceDeploy")
+	c.Assert(entry.Outcome(), gc.Equals, "success")
+	entry.Error = "boom"
+	c.Assert(entry.Outcome(), gc.Equals, "failure")
+}
+
+func (*auditSuite) TestSummarizeArguments(c *gc.C) {
+	args := struct {
+		ServiceName string
+		NumUnits    int
+		Users       []map[string]string
+	}{
+		ServiceName: "wordpress",
+		NumUnits:    2,
+		Users: []map[string]string{{
+			"Username": "bob",
+			"Password": "sekrit",
+		}},
+	}
+	summary := SummarizeArguments(args)
+	c.Assert(summary, gc.Equals,
+		`{"NumUnits":2,"ServiceName":"wordpress","Users":[{"Password":"<redacted>","Username":"bob"}]}`)
+}
+
+func (*auditSuite) TestSummarizeArgumentsRedactsWebhookAuth(c *gc.C) {
+	args := map[string]interface{}{
+		"Config": map[string]interface{}{
+			"metrics-sender":       "webhook",
+			"metrics-webhook-auth": "Bearer sekrit",
+		},
+	}
+	summary := SummarizeArguments(args)
+	c.Assert(summary, gc.Equals,
+		`{"Config":{"metrics-sender":"webhook","metrics-webhook-auth":"<redacted>"}}`)
+}
+
+func (*auditSuite) TestSummarizeArgumentsRedactsEncryptionKeys(c *gc.C) {
+	args := struct {
+		BackupId      string
+		EncryptionKey []byte
+		Options       map[string]string
+	}{
+		BackupId:      "some-id",
+		EncryptionKey: []byte("some secret key!"),
+		Options:       map[string]string{"key": "sekrit"},
+	}
+	summary := SummarizeArguments(args)
+	c.Assert(summary, gc.Equals,
+		`{"BackupId":"some-id","EncryptionKey":"<redacted>","Options":{"key":"<redacted>"}}`)
+}
+
+func (*auditSuite) TestSummarizeArgumentsNil(c *gc.C) {
+	c.Assert(SummarizeArguments(nil), gc.Equals, "")
+}
+
+func (*auditSuite) TestSummarizeArgumentsTruncates(c *gc.C) {
+	summary := SummarizeArguments(strings.Repeat("x", 2*MaxArgumentsLength))
+	c.Assert(summary, gc.HasLen, MaxArgumentsLength)
+	c.Assert(strings.HasSuffix(summary, "..."), jc.IsTrue)
+}
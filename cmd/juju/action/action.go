@@ -0,0 +1,149 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"io"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/juju/charm.v4"
+
+	"github.com/juju/juju/api/action"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const actionDoc = `
+"juju action" executes and manages actions on units; it queues up new
+actions, monitors the status of running actions, and retrieves the results
+of completed actions.
+
+Actions are identified by the ID printed when they are queued. Any unique
+prefix of an ID may be used in its place.
+`
+
+const actionPurpose = "execute, manage, monitor, and retrieve results of actions"
+
+// NewSuperCommand returns a new action super-command.
+func NewSuperCommand() cmd.Command {
+	actionCmd := cmd.NewSuperCommand(cmd.SuperCommandParams{
+		Name:        "action",
+		Doc:         actionDoc,
+		UsagePrefix: "juju",
+		Purpose:     actionPurpose,
+	})
+	actionCmd.Register(envcmd.Wrap(&DefinedCommand{}))
+	actionCmd.Register(envcmd.Wrap(&DoCommand{}))
+	actionCmd.Register(envcmd.Wrap(&FetchCommand{}))
+	actionCmd.Register(envcmd.Wrap(&StatusCommand{}))
+	actionCmd.Register(envcmd.Wrap(&CancelCommand{}))
+	return actionCmd
+}
+
+// APIClient represents the action API client functionality used by
+// the action command.
+type APIClient interface {
+	io.Closer
+
+	// Enqueue takes a list of Actions and queues them up to be executed by
+	// the designated ActionReceiver.
+	Enqueue(params.Actions) (params.ActionResults, error)
+
+	// Actions takes a list of ActionTags, and returns the full Action for
+	// each ID.
+	Actions(params.Entities) (params.ActionResults, error)
+
+	// FindActionTagsByPrefix takes a list of string prefixes and finds
+	// corresponding ActionTags that match that prefix.
+	FindActionTagsByPrefix(params.FindTags) (params.FindTagsResults, error)
+
+	// Cancel attempts to cancel the given queued up Actions.
+	Cancel(params.Entities) (params.ActionResults, error)
+
+	// ServiceCharmActions returns the charm.Actions for the given service.
+	ServiceCharmActions(params.Entity) (*charm.Actions, error)
+}
+
+// ActionCommandBase is the base type for action sub-commands.
+type ActionCommandBase struct {
+	envcmd.EnvCommandBase
+}
+
+// NewActionAPIClient returns a client for the action api endpoint.
+func (c *ActionCommandBase) NewActionAPIClient() (APIClient, error) {
+	return newAPIClient(c)
+}
+
+var newAPIClient = func(c *ActionCommandBase) (APIClient, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return action.NewClient(root), nil
+}
+
+// getActionTagsByPrefix returns the tags of all actions whose IDs start
+// with the given prefix.
+func getActionTagsByPrefix(api APIClient, prefix string) ([]names.ActionTag, error) {
+	results, err := api.FindActionTagsByPrefix(params.FindTags{Prefixes: []string{prefix}})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var tags []names.ActionTag
+	for _, entity := range results.Matches[prefix] {
+		tag, err := names.ParseActionTag(entity.Tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// getActionTagByPrefix returns the tag of the single action whose ID
+// starts with the given prefix. It is an error if the prefix matches
+// no action, or more than one.
+func getActionTagByPrefix(api APIClient, prefix string) (names.ActionTag, error) {
+	tags, err := getActionTagsByPrefix(api, prefix)
+	if err != nil {
+		return names.ActionTag{}, errors.Trace(err)
+	}
+	switch len(tags) {
+	case 0:
+		return names.ActionTag{}, errors.Errorf("actions for identifier %q not found", prefix)
+	case 1:
+		return tags[0], nil
+	}
+	ids := make([]string, len(tags))
+	for i, tag := range tags {
+		ids[i] = tag.Id()
+	}
+	return names.ActionTag{}, errors.Errorf("identifier %q matched multiple actions %v", prefix, ids)
+}
+
+// formatActionResult returns the given result in a form suitable for
+// output by the cmd formatters.
+func formatActionResult(result params.ActionResult) map[string]interface{} {
+	out := map[string]interface{}{
+		"status": result.Status,
+	}
+	if result.Action != nil {
+		if tag, err := names.ParseActionTag(result.Action.Tag); err == nil {
+			out["id"] = tag.Id()
+		}
+		if tag, err := names.ParseUnitTag(result.Action.Receiver); err == nil {
+			out["unit"] = tag.Id()
+		}
+		out["action"] = result.Action.Name
+	}
+	if result.Message != "" {
+		out["message"] = result.Message
+	}
+	if len(result.Output) > 0 {
+		out["results"] = result.Output
+	}
+	return out
+}
@@ -0,0 +1,181 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v4"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/action"
+	"github.com/juju/juju/testing"
+)
+
+const (
+	validActionId  = "f47ac10b-58cc-4372-a567-0e02b2c3d479"
+	validActionId2 = "f47ac10b-58cc-4372-a567-0e02b2c3d480"
+)
+
+var expectedSubCommmandNames = []string{
+	"cancel",
+	"defined",
+	"do",
+	"fetch",
+	"help",
+	"status",
+}
+
+type BaseActionSuite struct {
+	testing.FakeJujuHomeSuite
+	client *fakeAPIClient
+}
+
+func (s *BaseActionSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.client = &fakeAPIClient{
+		actionResults: make(map[string]params.ActionResult),
+	}
+	s.PatchValue(action.NewAPIClient, func(c *action.ActionCommandBase) (action.APIClient, error) {
+		return s.client, nil
+	})
+}
+
+// addAction adds an action with the given ID and status to the fake
+// API client.
+func (s *BaseActionSuite) addAction(id, status string) {
+	tag := names.NewActionTag(id).String()
+	s.client.actionResults[tag] = params.ActionResult{
+		Action: &params.Action{
+			Tag:      tag,
+			Receiver: names.NewUnitTag("mysql/0").String(),
+			Name:     "backup",
+		},
+		Status: status,
+	}
+}
+
+type actionSuite struct {
+	BaseActionSuite
+}
+
+var _ = gc.Suite(&actionSuite{})
+
+func (s *actionSuite) TestHelp(c *gc.C) {
+	ctx, err := testing.RunCommand(c, action.NewSuperCommand(), "--help")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Matches, "(?s)usage: juju action <command> .+")
+
+	// Check that we have registered all the sub commands by
+	// inspecting the help output.
+	var namesFound []string
+	commandHelp := strings.SplitAfter(testing.Stdout(ctx), "commands:")[1]
+	commandHelp = strings.TrimSpace(commandHelp)
+	for _, line := range strings.Split(commandHelp, "\n") {
+		name := strings.TrimSpace(strings.Split(line, " - ")[0])
+		namesFound = append(namesFound, name)
+	}
+	c.Check(namesFound, gc.DeepEquals, expectedSubCommmandNames)
+}
+
+type fakeAPIClient struct {
+	actionResults map[string]params.ActionResult
+	charmActions  *charm.Actions
+	err           error
+
+	// onActions, if set, is called after each call to Actions.
+	onActions func()
+
+	enqueued  []params.Action
+	cancelled []string
+}
+
+func (c *fakeAPIClient) Close() error {
+	return nil
+}
+
+func (c *fakeAPIClient) Enqueue(args params.Actions) (params.ActionResults, error) {
+	if c.err != nil {
+		return params.ActionResults{}, c.err
+	}
+	c.enqueued = append(c.enqueued, args.Actions...)
+	results := params.ActionResults{Results: make([]params.ActionResult, len(args.Actions))}
+	for i, arg := range args.Actions {
+		queued := arg
+		queued.Tag = names.NewActionTag(validActionId).String()
+		results.Results[i] = params.ActionResult{
+			Action: &queued,
+			Status: params.ActionPending,
+		}
+	}
+	return results, nil
+}
+
+func (c *fakeAPIClient) Actions(args params.Entities) (params.ActionResults, error) {
+	if c.err != nil {
+		return params.ActionResults{}, c.err
+	}
+	results := params.ActionResults{Results: make([]params.ActionResult, len(args.Entities))}
+	for i, entity := range args.Entities {
+		result, ok := c.actionResults[entity.Tag]
+		if !ok {
+			result.Error = &params.Error{Message: "action not found", Code: params.CodeNotFound}
+		}
+		results.Results[i] = result
+	}
+	if c.onActions != nil {
+		c.onActions()
+	}
+	return results, nil
+}
+
+func (c *fakeAPIClient) FindActionTagsByPrefix(args params.FindTags) (params.FindTagsResults, error) {
+	if c.err != nil {
+		return params.FindTagsResults{}, c.err
+	}
+	results := params.FindTagsResults{Matches: make(map[string][]params.Entity)}
+	for _, prefix := range args.Prefixes {
+		var tags []string
+		for tag := range c.actionResults {
+			if strings.HasPrefix(tag, "action-"+prefix) {
+				tags = append(tags, tag)
+			}
+		}
+		sort.Strings(tags)
+		for _, tag := range tags {
+			results.Matches[prefix] = append(results.Matches[prefix], params.Entity{Tag: tag})
+		}
+	}
+	return results, nil
+}
+
+func (c *fakeAPIClient) Cancel(args params.Entities) (params.ActionResults, error) {
+	if c.err != nil {
+		return params.ActionResults{}, c.err
+	}
+	results := params.ActionResults{Results: make([]params.ActionResult, len(args.Entities))}
+	for i, entity := range args.Entities {
+		c.cancelled = append(c.cancelled, entity.Tag)
+		result := c.actionResults[entity.Tag]
+		if result.Status != params.ActionPending {
+			result.Error = &params.Error{Message: "action is not pending"}
+		} else {
+			result.Status = params.ActionCancelled
+			c.actionResults[entity.Tag] = result
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
+func (c *fakeAPIClient) ServiceCharmActions(arg params.Entity) (*charm.Actions, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.charmActions, nil
+}
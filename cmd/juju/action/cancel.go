@@ -0,0 +1,94 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+const cancelDoc = `
+Cancel the pending actions with the given IDs or partial IDs. Only actions
+that have not yet started running can be cancelled.
+`
+
+// CancelCommand cancels pending actions.
+type CancelCommand struct {
+	ActionCommandBase
+	out          cmd.Output
+	requestedIds []string
+}
+
+// Info implements Command.Info.
+func (c *CancelCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "cancel",
+		Args:    "<action ID> [<action ID>...]",
+		Purpose: "cancel pending actions",
+		Doc:     cancelDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *CancelCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+// Init implements Command.Init.
+func (c *CancelCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no action IDs specified")
+	}
+	c.requestedIds = args
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *CancelCommand) Run(ctx *cmd.Context) error {
+	api, err := c.NewActionAPIClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	entities := params.Entities{Entities: make([]params.Entity, len(c.requestedIds))}
+	for i, id := range c.requestedIds {
+		tag, err := getActionTagByPrefix(api, id)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		entities.Entities[i] = params.Entity{Tag: tag.String()}
+	}
+
+	results, err := api.Cancel(entities)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(results.Results) != len(entities.Entities) {
+		return errors.Errorf("expected %d results, got %d", len(entities.Entities), len(results.Results))
+	}
+	out := make([]map[string]interface{}, len(results.Results))
+	var failed bool
+	for i, result := range results.Results {
+		if result.Error != nil {
+			failed = true
+			out[i] = map[string]interface{}{
+				"id":    c.requestedIds[i],
+				"error": result.Error.Error(),
+			}
+			continue
+		}
+		out[i] = formatActionResult(result)
+	}
+	if err := c.out.Write(ctx, map[string]interface{}{"cancelled": out}); err != nil {
+		return errors.Trace(err)
+	}
+	if failed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/action"
+	"github.com/juju/juju/testing"
+)
+
+type CancelSuite struct {
+	BaseActionSuite
+}
+
+var _ = gc.Suite(&CancelSuite{})
+
+func (s *CancelSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(envcmd.Wrap(&action.CancelCommand{}), nil)
+	c.Assert(err, gc.ErrorMatches, "no action IDs specified")
+}
+
+func (s *CancelSuite) TestRun(c *gc.C) {
+	s.addAction(validActionId, params.ActionPending)
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&action.CancelCommand{}), validActionId[:8])
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.client.cancelled, jc.DeepEquals, []string{names.NewActionTag(validActionId).String()})
+	c.Check(testing.Stdout(ctx), gc.Equals, `
+cancelled:
+- action: backup
+  id: `+validActionId+`
+  status: cancelled
+  unit: mysql/0
+`[1:])
+}
+
+func (s *CancelSuite) TestRunNotPending(c *gc.C) {
+	s.addAction(validActionId, params.ActionCompleted)
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&action.CancelCommand{}), validActionId)
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Check(testing.Stdout(ctx), gc.Equals, `
+cancelled:
+- error: action is not pending
+  id: `+validActionId+`
+`[1:])
+}
+
+func (s *CancelSuite) TestRunNotFound(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&action.CancelCommand{}), validActionId)
+	c.Assert(err, gc.ErrorMatches, `actions for identifier ".*" not found`)
+	c.Check(s.client.cancelled, gc.HasLen, 0)
+}
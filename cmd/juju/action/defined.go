@@ -0,0 +1,82 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+const definedDoc = `
+Show the actions available to run on the units of a service, as defined
+by the service's charm. The description of each action is shown, along
+with the JSON schema of the parameters it accepts.
+`
+
+// DefinedCommand lists actions defined by the charm of a given service.
+type DefinedCommand struct {
+	ActionCommandBase
+	out        cmd.Output
+	serviceTag names.ServiceTag
+}
+
+// Info implements Command.Info.
+func (c *DefinedCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "defined",
+		Args:    "<service name>",
+		Purpose: "show actions defined for a service",
+		Doc:     definedDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *DefinedCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+// Init implements Command.Init.
+func (c *DefinedCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no service name specified")
+	case 1:
+		if !names.IsValidService(args[0]) {
+			return errors.Errorf("invalid service name %q", args[0])
+		}
+		c.serviceTag = names.NewServiceTag(args[0])
+		return nil
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *DefinedCommand) Run(ctx *cmd.Context) error {
+	api, err := c.NewActionAPIClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	actions, err := api.ServiceCharmActions(params.Entity{Tag: c.serviceTag.String()})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if actions == nil || len(actions.ActionSpecs) == 0 {
+		ctx.Infof("no actions defined for service %q", c.serviceTag.Id())
+		return nil
+	}
+	out := make(map[string]interface{})
+	for name, spec := range actions.ActionSpecs {
+		out[name] = map[string]interface{}{
+			"description": spec.Description,
+			"params":      spec.Params,
+		}
+	}
+	return c.out.Write(ctx, out)
+}
@@ -0,0 +1,86 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v4"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/action"
+	"github.com/juju/juju/testing"
+)
+
+type DefinedSuite struct {
+	BaseActionSuite
+}
+
+var _ = gc.Suite(&DefinedSuite{})
+
+func (s *DefinedSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		errMatch string
+	}{{
+		errMatch: "no service name specified",
+	}, {
+		args:     []string{"mysql/0"},
+		errMatch: `invalid service name "mysql/0"`,
+	}, {
+		args:     []string{"mysql", "extra"},
+		errMatch: `unrecognized args: \["extra"\]`,
+	}, {
+		args: []string{"mysql"},
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := testing.InitCommand(envcmd.Wrap(&action.DefinedCommand{}), test.args)
+		if test.errMatch != "" {
+			c.Check(err, gc.ErrorMatches, test.errMatch)
+		} else {
+			c.Check(err, jc.ErrorIsNil)
+		}
+	}
+}
+
+func (s *DefinedSuite) TestRun(c *gc.C) {
+	s.client.charmActions = &charm.Actions{
+		ActionSpecs: map[string]charm.ActionSpec{
+			"snapshot": {
+				Description: "Take a snapshot of the database.",
+				Params: map[string]interface{}{
+					"type":  "object",
+					"title": "snapshot",
+					"properties": map[string]interface{}{
+						"outfile": map[string]interface{}{
+							"type":        "string",
+							"description": "The file to write out to.",
+						},
+					},
+				},
+			},
+		},
+	}
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&action.DefinedCommand{}), "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, `
+snapshot:
+  description: Take a snapshot of the database.
+  params:
+    properties:
+      outfile:
+        description: The file to write out to.
+        type: string
+    title: snapshot
+    type: object
+`[1:])
+}
+
+func (s *DefinedSuite) TestRunNoActions(c *gc.C) {
+	s.client.charmActions = &charm.Actions{}
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&action.DefinedCommand{}), "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, "")
+	c.Check(testing.Stderr(ctx), gc.Equals, "no actions defined for service \"mysql\"\n")
+}
@@ -0,0 +1,228 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	goyaml "gopkg.in/yaml.v1"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+const doDoc = `
+Queue an action for execution on a given unit, with the given parameters.
+The action ID is printed, and may be used to retrieve the results of the
+action with "juju action fetch".
+
+Parameters may be given in a YAML file passed with --params, and on the
+command line as key=value pairs. Nested keys are separated by dots, and
+values given on the command line override those in the file. Values are
+interpreted as YAML, so "count=3" passes the integer 3.
+
+Examples:
+
+    juju action do mysql/3 backup
+    juju action do mysql/3 backup --params parameters.yaml
+    juju action do mysql/3 backup out=out.tar.bz2 file.kind=xz
+`
+
+// DoCommand enqueues an Action for running on the given unit with given
+// params.
+type DoCommand struct {
+	ActionCommandBase
+	unitTag    names.UnitTag
+	actionName string
+	paramsYAML cmd.FileVar
+	args       [][]string
+}
+
+// Info implements Command.Info.
+func (c *DoCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "do",
+		Args:    "<unit> <action name> [key.key.key...=value]",
+		Purpose: "queue an action for execution",
+		Doc:     doDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *DoCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.Var(&c.paramsYAML, "params", "path to yaml-formatted params file")
+}
+
+// Init implements Command.Init.
+func (c *DoCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no unit specified")
+	case 1:
+		return errors.New("no action specified")
+	}
+	unitName, actionName := args[0], args[1]
+	if !names.IsValidUnit(unitName) {
+		return errors.Errorf("invalid unit name %q", unitName)
+	}
+	c.unitTag = names.NewUnitTag(unitName)
+	c.actionName = actionName
+	for _, arg := range args[2:] {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return errors.Errorf("argument %q must be of the form key.key.key...=value", arg)
+		}
+		keys := strings.Split(parts[0], ".")
+		for _, key := range keys {
+			if key == "" {
+				return errors.Errorf("invalid key %q in argument %q", parts[0], arg)
+			}
+		}
+		c.args = append(c.args, append(keys, parts[1]))
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *DoCommand) Run(ctx *cmd.Context) error {
+	actionParams, err := c.actionParams(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	api, err := c.NewActionAPIClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	results, err := api.Enqueue(params.Actions{
+		Actions: []params.Action{{
+			Receiver:   c.unitTag.String(),
+			Name:       c.actionName,
+			Parameters: actionParams,
+		}},
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.Action == nil {
+		return errors.New("action failed to enqueue")
+	}
+	tag, err := names.ParseActionTag(result.Action.Tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	fmt.Fprintf(ctx.Stdout, "Action queued with id: %s\n", tag.Id())
+	return nil
+}
+
+// actionParams returns the parameters for the action, read from the
+// params file and overridden by any given on the command line.
+func (c *DoCommand) actionParams(ctx *cmd.Context) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	if c.paramsYAML.Path != "" {
+		data, err := c.paramsYAML.Read(ctx)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var fileParams map[string]interface{}
+		if err := goyaml.Unmarshal(data, &fileParams); err != nil {
+			return nil, errors.Annotatef(err, "cannot parse params file %q", c.paramsYAML.Path)
+		}
+		conformed, err := conform(fileParams)
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid params file %q", c.paramsYAML.Path)
+		}
+		result = conformed.(map[string]interface{})
+	}
+	for _, arg := range c.args {
+		keys, value := arg[:len(arg)-1], arg[len(arg)-1]
+		var parsed interface{}
+		if err := goyaml.Unmarshal([]byte(value), &parsed); err != nil || parsed == nil {
+			parsed = value
+		}
+		conformed, err := conform(parsed)
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid value for %q", strings.Join(keys, "."))
+		}
+		if err := setNested(result, keys, conformed); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return result, nil
+}
+
+// setNested sets the value at the given path of keys in the given map,
+// creating intermediate maps as necessary.
+func setNested(m map[string]interface{}, keys []string, value interface{}) error {
+	for i, key := range keys[:len(keys)-1] {
+		next, ok := m[key]
+		if !ok {
+			next = make(map[string]interface{})
+			m[key] = next
+		}
+		nested, ok := next.(map[string]interface{})
+		if !ok {
+			return errors.Errorf("key %q already has a non-map value", strings.Join(keys[:i+1], "."))
+		}
+		m = nested
+	}
+	m[keys[len(keys)-1]] = value
+	return nil
+}
+
+// conform ensures all keys of any nested maps are strings, so that the
+// value may be serialised as JSON. goyaml unmarshals nested maps as
+// map[interface{}]interface{}.
+func conform(input interface{}) (interface{}, error) {
+	switch typed := input.(type) {
+	case map[string]interface{}:
+		newMap := make(map[string]interface{})
+		for key, value := range typed {
+			newValue, err := conform(value)
+			if err != nil {
+				return nil, err
+			}
+			newMap[key] = newValue
+		}
+		return newMap, nil
+	case map[interface{}]interface{}:
+		newMap := make(map[string]interface{})
+		for key, value := range typed {
+			typedKey, ok := key.(string)
+			if !ok {
+				return nil, errors.Errorf("map keyed with non-string value %v", key)
+			}
+			newValue, err := conform(value)
+			if err != nil {
+				return nil, err
+			}
+			newMap[typedKey] = newValue
+		}
+		return newMap, nil
+	case []interface{}:
+		newSlice := make([]interface{}, len(typed))
+		for i, elem := range typed {
+			newElem, err := conform(elem)
+			if err != nil {
+				return nil, err
+			}
+			newSlice[i] = newElem
+		}
+		return newSlice, nil
+	}
+	return input, nil
+}
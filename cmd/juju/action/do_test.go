@@ -0,0 +1,110 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/action"
+	"github.com/juju/juju/testing"
+)
+
+type DoSuite struct {
+	BaseActionSuite
+}
+
+var _ = gc.Suite(&DoSuite{})
+
+func (s *DoSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		errMatch string
+	}{{
+		errMatch: "no unit specified",
+	}, {
+		args:     []string{"mysql/0"},
+		errMatch: "no action specified",
+	}, {
+		args:     []string{"mysql", "backup"},
+		errMatch: `invalid unit name "mysql"`,
+	}, {
+		args:     []string{"mysql/0", "backup", "out"},
+		errMatch: `argument "out" must be of the form key.key.key...=value`,
+	}, {
+		args:     []string{"mysql/0", "backup", "out..kind=xz"},
+		errMatch: `invalid key "out..kind" in argument "out..kind=xz"`,
+	}, {
+		args: []string{"mysql/0", "backup", "out.kind=xz", "count=3"},
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := testing.InitCommand(envcmd.Wrap(&action.DoCommand{}), test.args)
+		if test.errMatch != "" {
+			c.Check(err, gc.ErrorMatches, test.errMatch)
+		} else {
+			c.Check(err, jc.ErrorIsNil)
+		}
+	}
+}
+
+func (s *DoSuite) TestRun(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&action.DoCommand{}),
+		"mysql/0", "backup", "out.file=backup.tar", "out.kind=xz", "count=3")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, "Action queued with id: "+validActionId+"\n")
+	c.Assert(s.client.enqueued, gc.HasLen, 1)
+	c.Check(s.client.enqueued[0], jc.DeepEquals, params.Action{
+		Receiver: "unit-mysql-0",
+		Name:     "backup",
+		Parameters: map[string]interface{}{
+			"out": map[string]interface{}{
+				"file": "backup.tar",
+				"kind": "xz",
+			},
+			"count": 3,
+		},
+	})
+}
+
+func (s *DoSuite) TestRunParamsFile(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "params.yaml")
+	err := ioutil.WriteFile(path, []byte(`
+out:
+  file: backup.tar
+  kind: gz
+compress: true
+`), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = testing.RunCommand(c, envcmd.Wrap(&action.DoCommand{}),
+		"mysql/0", "backup", "--params", path, "out.kind=xz")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.client.enqueued, gc.HasLen, 1)
+	c.Check(s.client.enqueued[0].Parameters, jc.DeepEquals, map[string]interface{}{
+		"out": map[string]interface{}{
+			"file": "backup.tar",
+			"kind": "xz",
+		},
+		"compress": true,
+	})
+}
+
+func (s *DoSuite) TestRunConflictingKeys(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&action.DoCommand{}),
+		"mysql/0", "backup", "out=file", "out.kind=xz")
+	c.Assert(err, gc.ErrorMatches, `key "out" already has a non-map value`)
+	c.Assert(s.client.enqueued, gc.HasLen, 0)
+}
+
+func (s *DoSuite) TestRunError(c *gc.C) {
+	s.client.err = errors.New("boom")
+	_, err := testing.RunCommand(c, envcmd.Wrap(&action.DoCommand{}), "mysql/0", "backup")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -0,0 +1,129 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+const fetchDoc = `
+Show the results returned by an action with the given ID. A partial ID may
+also be used. To block until the result is known completed or failed, use
+the --wait flag with a duration, as in --wait 5s or --wait 1h. Use --wait 0
+to wait indefinitely. If units are left off, seconds are assumed.
+`
+
+// fetchPollInterval holds the interval between checks on the status of
+// an action while waiting for it to finish.
+var fetchPollInterval = time.Second
+
+// FetchCommand fetches the results of an action by ID.
+type FetchCommand struct {
+	ActionCommandBase
+	out         cmd.Output
+	requestedId string
+	wait        string
+	timeout     time.Duration
+}
+
+// Info implements Command.Info.
+func (c *FetchCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "fetch",
+		Args:    "<action ID>",
+		Purpose: "show results of an action by ID",
+		Doc:     fetchDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *FetchCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+	f.StringVar(&c.wait, "wait", "", "wait for results, with optional timeout")
+}
+
+// Init implements Command.Init.
+func (c *FetchCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no action ID specified")
+	case 1:
+		c.requestedId = args[0]
+	default:
+		return cmd.CheckEmpty(args[1:])
+	}
+	c.timeout = -1
+	if c.wait != "" {
+		wait := c.wait
+		if _, err := time.ParseDuration(wait); err != nil {
+			// A bare number is a count of seconds.
+			wait += "s"
+		}
+		timeout, err := time.ParseDuration(wait)
+		if err != nil || timeout < 0 {
+			return errors.Errorf("invalid --wait value %q", c.wait)
+		}
+		c.timeout = timeout
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *FetchCommand) Run(ctx *cmd.Context) error {
+	api, err := c.NewActionAPIClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	tag, err := getActionTagByPrefix(api, c.requestedId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	var timeout <-chan time.Time
+	if c.timeout > 0 {
+		timeout = time.After(c.timeout)
+	}
+	for {
+		result, err := fetchActionResult(api, tag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if c.timeout < 0 || result.Status != params.ActionPending {
+			return c.out.Write(ctx, formatActionResult(result))
+		}
+		select {
+		case <-timeout:
+			return c.out.Write(ctx, formatActionResult(result))
+		case <-time.After(fetchPollInterval):
+		}
+	}
+}
+
+// fetchActionResult returns the current result of the action with the
+// given tag.
+func fetchActionResult(api APIClient, tag names.ActionTag) (params.ActionResult, error) {
+	results, err := api.Actions(params.Entities{
+		Entities: []params.Entity{{Tag: tag.String()}},
+	})
+	if err != nil {
+		return params.ActionResult{}, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return params.ActionResult{}, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return params.ActionResult{}, result.Error
+	}
+	return result, nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/action"
+	"github.com/juju/juju/testing"
+)
+
+type FetchSuite struct {
+	BaseActionSuite
+}
+
+var _ = gc.Suite(&FetchSuite{})
+
+func (s *FetchSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		errMatch string
+	}{{
+		errMatch: "no action ID specified",
+	}, {
+		args:     []string{validActionId, "extra"},
+		errMatch: `unrecognized args: \["extra"\]`,
+	}, {
+		args:     []string{validActionId, "--wait", "soon"},
+		errMatch: `invalid --wait value "soon"`,
+	}, {
+		args:     []string{validActionId, "--wait", "-5s"},
+		errMatch: `invalid --wait value "-5s"`,
+	}, {
+		args: []string{validActionId, "--wait", "5"},
+	}, {
+		args: []string{validActionId, "--wait", "1m"},
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := testing.InitCommand(envcmd.Wrap(&action.FetchCommand{}), test.args)
+		if test.errMatch != "" {
+			c.Check(err, gc.ErrorMatches, test.errMatch)
+		} else {
+			c.Check(err, jc.ErrorIsNil)
+		}
+	}
+}
+
+func (s *FetchSuite) TestRunCompleted(c *gc.C) {
+	s.addAction(validActionId, params.ActionCompleted)
+	tag := names.NewActionTag(validActionId).String()
+	result := s.client.actionResults[tag]
+	result.Message = "all done"
+	result.Output = map[string]interface{}{"outfile": "backup.tar"}
+	s.client.actionResults[tag] = result
+
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&action.FetchCommand{}), "f47ac10b-58cc-4372-a567-0e02b2c3d479")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, `
+action: backup
+id: f47ac10b-58cc-4372-a567-0e02b2c3d479
+message: all done
+results:
+  outfile: backup.tar
+status: completed
+unit: mysql/0
+`[1:])
+}
+
+func (s *FetchSuite) TestRunPendingNoWait(c *gc.C) {
+	s.addAction(validActionId, params.ActionPending)
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&action.FetchCommand{}), validActionId, "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals,
+		`{"action":"backup","id":"`+validActionId+`","status":"pending","unit":"mysql/0"}`+"\n")
+}
+
+func (s *FetchSuite) TestRunWaitTimeout(c *gc.C) {
+	s.PatchValue(action.FetchPollInterval, time.Millisecond)
+	s.addAction(validActionId, params.ActionPending)
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&action.FetchCommand{}), validActionId, "--wait", "10ms")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Matches, "(?s).*status: pending.*")
+}
+
+func (s *FetchSuite) TestRunWaitCompletes(c *gc.C) {
+	s.PatchValue(action.FetchPollInterval, time.Millisecond)
+	s.addAction(validActionId, params.ActionPending)
+	tag := names.NewActionTag(validActionId).String()
+	s.client.onActions = func() {
+		result := s.client.actionResults[tag]
+		result.Status = params.ActionCompleted
+		s.client.actionResults[tag] = result
+	}
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&action.FetchCommand{}), validActionId, "--wait", "0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Matches, "(?s).*status: completed.*")
+}
+
+func (s *FetchSuite) TestRunPrefix(c *gc.C) {
+	s.addAction(validActionId, params.ActionCompleted)
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&action.FetchCommand{}), "f47a")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Matches, "(?s).*id: "+validActionId+".*")
+}
+
+func (s *FetchSuite) TestRunPrefixNotFound(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&action.FetchCommand{}), "f47a")
+	c.Assert(err, gc.ErrorMatches, `actions for identifier "f47a" not found`)
+}
+
+func (s *FetchSuite) TestRunPrefixAmbiguous(c *gc.C) {
+	s.addAction(validActionId, params.ActionCompleted)
+	s.addAction(validActionId2, params.ActionCompleted)
+	_, err := testing.RunCommand(c, envcmd.Wrap(&action.FetchCommand{}), "f47a")
+	c.Assert(err, gc.ErrorMatches, `identifier "f47a" matched multiple actions \[`+validActionId+` `+validActionId2+`\]`)
+}
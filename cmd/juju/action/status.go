@@ -0,0 +1,96 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+const statusDoc = `
+Show the status of actions matching the given ID or partial ID. If no ID
+is given, the status of all actions in the environment is shown.
+`
+
+// StatusCommand shows the status of an action or actions.
+type StatusCommand struct {
+	ActionCommandBase
+	out         cmd.Output
+	requestedId string
+}
+
+// Info implements Command.Info.
+func (c *StatusCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "status",
+		Args:    "[<action ID>|<action ID prefix>]",
+		Purpose: "show results of all actions filtered by optional ID prefix",
+		Doc:     statusDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *StatusCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+// Init implements Command.Init.
+func (c *StatusCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return nil
+	case 1:
+		c.requestedId = args[0]
+		return nil
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *StatusCommand) Run(ctx *cmd.Context) error {
+	api, err := c.NewActionAPIClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	tags, err := getActionTagsByPrefix(api, c.requestedId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(tags) == 0 {
+		if c.requestedId == "" {
+			ctx.Infof("no actions found")
+			return nil
+		}
+		return errors.Errorf("actions for identifier %q not found", c.requestedId)
+	}
+
+	entities := params.Entities{Entities: make([]params.Entity, len(tags))}
+	for i, tag := range tags {
+		entities.Entities[i] = params.Entity{Tag: tag.String()}
+	}
+	results, err := api.Actions(entities)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	out := make([]map[string]interface{}, len(results.Results))
+	for i, result := range results.Results {
+		if result.Error != nil {
+			out[i] = map[string]interface{}{
+				"id":    tags[i].Id(),
+				"error": result.Error.Error(),
+			}
+			continue
+		}
+		out[i] = map[string]interface{}{
+			"id":     tags[i].Id(),
+			"status": result.Status,
+		}
+	}
+	return c.out.Write(ctx, map[string]interface{}{"actions": out})
+}
@@ -0,0 +1,68 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/action"
+	"github.com/juju/juju/testing"
+)
+
+type StatusSuite struct {
+	BaseActionSuite
+}
+
+var _ = gc.Suite(&StatusSuite{})
+
+func (s *StatusSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(envcmd.Wrap(&action.StatusCommand{}), []string{"a", "b"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["b"\]`)
+}
+
+func (s *StatusSuite) TestRunAll(c *gc.C) {
+	s.addAction(validActionId, params.ActionCompleted)
+	s.addAction(validActionId2, params.ActionPending)
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&action.StatusCommand{}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, `
+actions:
+- id: `+validActionId+`
+  status: completed
+- id: `+validActionId2+`
+  status: pending
+`[1:])
+}
+
+func (s *StatusSuite) TestRunPrefix(c *gc.C) {
+	s.addAction(validActionId, params.ActionCompleted)
+	s.addAction(validActionId2, params.ActionPending)
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&action.StatusCommand{}),
+		validActionId2[:30], "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals,
+		`{"actions":[{"id":"`+validActionId2+`","status":"pending"}]}`+"\n")
+}
+
+func (s *StatusSuite) TestRunNoActions(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&action.StatusCommand{}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, "")
+	c.Check(testing.Stderr(ctx), gc.Equals, "no actions found\n")
+}
+
+func (s *StatusSuite) TestRunPrefixNotFound(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&action.StatusCommand{}), "dead")
+	c.Assert(err, gc.ErrorMatches, `actions for identifier "dead" not found`)
+}
+
+func (s *StatusSuite) TestRunError(c *gc.C) {
+	s.client.err = errors.New("boom")
+	_, err := testing.RunCommand(c, envcmd.Wrap(&action.StatusCommand{}))
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
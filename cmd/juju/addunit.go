@@ -16,6 +16,7 @@ import (
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/juju"
 	"github.com/juju/juju/provider"
 )
 
@@ -28,14 +29,14 @@ type UnitCommandBase struct {
 
 func (c *UnitCommandBase) SetFlags(f *gnuflag.FlagSet) {
 	f.IntVar(&c.NumUnits, "num-units", 1, "")
-	f.StringVar(&c.ToMachineSpec, "to", "", "the machine or container to deploy the unit in, bypasses constraints")
+	f.StringVar(&c.ToMachineSpec, "to", "", "the machine or container to deploy the unit in, bypasses constraints; or zone=<name> to use new machines in that zone")
 }
 
 func (c *UnitCommandBase) Init(args []string) error {
 	if c.NumUnits < 1 {
 		return errors.New("--num-units must be a positive integer")
 	}
-	if c.ToMachineSpec != "" {
+	if c.ToMachineSpec != "" && !juju.IsZonePlacement(c.ToMachineSpec) {
 		if c.NumUnits > 1 {
 			return errors.New("cannot use --num-units > 1 with --to")
 		}
@@ -82,13 +83,18 @@ have already been deployed via juju deploy.
 
 By default, services are deployed to newly provisioned machines.  Alternatively,
 service units can be added to a specific existing machine using the --to
-argument.
+argument, or to new machines in a particular availability zone using a
+zone=<name> placement directive. On providers with availability zones, new
+machines for the units of a service are otherwise spread across the zones
+allowed by the zones constraint.
 
 Examples:
  juju add-unit mysql -n 5          (Add 5 mysql units on 5 new machines)
  juju add-unit mysql --to 23       (Add a mysql unit to machine 23)
  juju add-unit mysql --to 24/lxc/3 (Add unit to lxc container 3 on host machine 24)
  juju add-unit mysql --to lxc:25   (Add unit to a new lxc container on host machine 25)
+ juju add-unit mysql -n 2 --to zone=us-east-1a
+                                   (Add 2 mysql units on new machines in zone us-east-1a)
 `
 
 func (c *AddUnitCommand) Info() *cmd.Info {
@@ -40,6 +40,9 @@ var initAddUnitErrorTests = []struct {
 	}, {
 		args: []string{"some-service-name", "-n", "2", "--to", "123"},
 		err:  `cannot use --num-units > 1 with --to`,
+	}, {
+		args: []string{"some-service-name", "--to", "zone="},
+		err:  `invalid --to parameter "zone="`,
 	},
 }
 
@@ -51,6 +54,14 @@ func (s *AddUnitSuite) TestInitErrors(c *gc.C) {
 	}
 }
 
+func (s *AddUnitSuite) TestInitZonePlacement(c *gc.C) {
+	command := &AddUnitCommand{}
+	err := testing.InitCommand(envcmd.Wrap(command), []string{"some-service-name", "-n", "2", "--to", "zone=az1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(command.NumUnits, gc.Equals, 2)
+	c.Assert(command.ToMachineSpec, gc.Equals, "zone=az1")
+}
+
 func runAddUnit(c *gc.C, args ...string) error {
 	_, err := testing.RunCommand(c, envcmd.Wrap(&AddUnitCommand{}), args...)
 	return err
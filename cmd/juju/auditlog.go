@@ -0,0 +1,223 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/auditlog"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/audit"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const auditLogDoc = `
+Show the audit log of the environment. The audit log records every API
+call made by a user: who made it, the operation called with a summary
+of its arguments, whether it succeeded and when.
+
+Entries can be filtered by user, by operation and by time. An operation
+is given either as a facade name (e.g. "Client") or as a facade and
+method (e.g. "Client.ServiceDeploy"). Times are given either as a date
+(2006-01-02) or as an RFC 3339 timestamp (2006-01-02T15:04:05Z). A date
+given to --to includes the whole of that day.
+
+Examples:
+
+    juju audit-log --user bob
+    juju audit-log --operation Client.DestroyMachines --from 2014-11-01
+    juju audit-log --limit 20 --format yaml
+`
+
+// AuditLogCommand shows the audit log of an environment.
+type AuditLogCommand struct {
+	envcmd.EnvCommandBase
+	out cmd.Output
+
+	user      string
+	operation string
+	from      string
+	to        string
+	limit     int
+
+	filter params.AuditLogFilter
+}
+
+// Info implements Command.Info.
+func (c *AuditLogCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "audit-log",
+		Purpose: "show the audit log of the environment",
+		Doc:     auditLogDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *AuditLogCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.user, "user", "", "only show operations performed by this user")
+	f.StringVar(&c.operation, "operation", "", "only show this operation (Facade or Facade.Method)")
+	f.StringVar(&c.from, "from", "", "only show operations performed at or after this time")
+	f.StringVar(&c.to, "to", "", "only show operations performed at or before this time")
+	f.IntVar(&c.limit, "limit", 0, "show at most this many of the most recent entries")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatAuditLogTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *AuditLogCommand) Init(args []string) (err error) {
+	switch {
+	case c.user == "":
+	case names.IsValidUserName(c.user):
+		c.filter.User = names.NewLocalUserTag(c.user).String()
+	case names.IsValidUser(c.user):
+		c.filter.User = names.NewUserTag(c.user).String()
+	default:
+		return errors.Errorf("invalid user name %q", c.user)
+	}
+	if c.operation != "" {
+		parts := strings.Split(c.operation, ".")
+		if len(parts) > 2 || parts[0] == "" || (len(parts) == 2 && parts[1] == "") {
+			return errors.Errorf("invalid operation %q", c.operation)
+		}
+		c.filter.Facade = parts[0]
+		if len(parts) == 2 {
+			c.filter.Method = parts[1]
+		}
+	}
+	if c.filter.After, err = parseAuditTime(c.from, false); err != nil {
+		return errors.Annotate(err, "invalid --from value")
+	}
+	if c.filter.Before, err = parseAuditTime(c.to, true); err != nil {
+		return errors.Annotate(err, "invalid --to value")
+	}
+	if c.limit < 0 {
+		return errors.Errorf("invalid limit %d", c.limit)
+	}
+	c.filter.Limit = c.limit
+	return cmd.CheckEmpty(args)
+}
+
+// parseAuditTime parses a time given either as a date or as an RFC 3339
+// timestamp. A date stands for the start of the day, or for its last
+// instant if endOfDay is true. An empty string results in the zero
+// time.
+func parseAuditTime(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		if endOfDay {
+			t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.Errorf("%q is neither a date nor an RFC 3339 time", value)
+	}
+	return t, nil
+}
+
+// AuditLogAPI defines the API methods that the audit-log command uses.
+type AuditLogAPI interface {
+	Entries(filter params.AuditLogFilter) ([]params.AuditLogEntry, error)
+	Close() error
+}
+
+var getAuditLogAPI = func(c *AuditLogCommand) (AuditLogAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return auditlog.NewClient(root), nil
+}
+
+// auditLogEntry holds an audit log entry formatted for output.
+type auditLogEntry struct {
+	Time      string `yaml:"time" json:"time"`
+	User      string `yaml:"user" json:"user"`
+	Operation string `yaml:"operation" json:"operation"`
+	Arguments string `yaml:"arguments,omitempty" json:"arguments,omitempty"`
+	Outcome   string `yaml:"outcome" json:"outcome"`
+	Error     string `yaml:"error,omitempty" json:"error,omitempty"`
+}
+
+// Run implements Command.Run.
+func (c *AuditLogCommand) Run(ctx *cmd.Context) error {
+	client, err := getAuditLogAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	entries, err := client.Entries(c.filter)
+	if err != nil {
+		return err
+	}
+	formatted := make([]auditLogEntry, len(entries))
+	for i, entry := range entries {
+		e := audit.Entry{
+			Timestamp: entry.Timestamp,
+			EnvUUID:   entry.EnvUUID,
+			User:      entry.User,
+			Facade:    entry.Facade,
+			Version:   entry.Version,
+			Method:    entry.Method,
+			Arguments: entry.Arguments,
+			Error:     entry.Error,
+		}
+		user := e.User
+		if tag, err := names.ParseUserTag(user); err == nil {
+			user = tag.Username()
+		}
+		formatted[i] = auditLogEntry{
+			Time:      e.Timestamp.UTC().Format(time.RFC3339),
+			User:      user,
+			Operation: e.Operation(),
+			Arguments: e.Arguments,
+			Outcome:   e.Outcome(),
+			Error:     e.Error,
+		}
+	}
+	return c.out.Write(ctx, formatted)
+}
+
+func formatAuditLogTabular(value interface{}) ([]byte, error) {
+	entries, ok := value.([]auditLogEntry)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", entries, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	fmt.Fprintf(tw, "TIME\tUSER\tOPERATION\tOUTCOME\n")
+	for _, entry := range entries {
+		outcome := entry.Outcome
+		if entry.Error != "" {
+			outcome += ": " + entry.Error
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", entry.Time, entry.User, entry.Operation, outcome)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
@@ -0,0 +1,166 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type AuditLogSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeAuditLogAPI
+}
+
+var _ = gc.Suite(&AuditLogSuite{})
+
+type fakeAuditLogAPI struct {
+	filter  params.AuditLogFilter
+	entries []params.AuditLogEntry
+	err     error
+}
+
+func (f *fakeAuditLogAPI) Entries(filter params.AuditLogFilter) ([]params.AuditLogEntry, error) {
+	f.filter = filter
+	return f.entries, f.err
+}
+
+func (f *fakeAuditLogAPI) Close() error {
+	return nil
+}
+
+func (s *AuditLogSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeAuditLogAPI{
+		entries: []params.AuditLogEntry{{
+			Timestamp: time.Date(2014, 11, 5, 10, 0, 0, 0, time.UTC),
+			User:      "user-admin@local",
+			Facade:    "Client",
+			Method:    "ServiceDeploy",
+			Arguments: `{"ServiceName":"mysql"}`,
+		}, {
+			Timestamp: time.Date(2014, 11, 5, 10, 1, 0, 0, time.UTC),
+			User:      "user-bob@local",
+			Facade:    "Client",
+			Method:    "DestroyMachines",
+			Error:     "permission denied",
+		}},
+	}
+	s.PatchValue(&getAuditLogAPI, func(c *AuditLogCommand) (AuditLogAPI, error) {
+		return s.fake, nil
+	})
+}
+
+func (s *AuditLogSuite) TestArgParsing(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		expected params.AuditLogFilter
+		errMatch string
+	}{{
+		expected: params.AuditLogFilter{},
+	}, {
+		args:     []string{"--user", "bob"},
+		expected: params.AuditLogFilter{User: "user-bob@local"},
+	}, {
+		args:     []string{"--user", "bob!"},
+		errMatch: `invalid user name "bob!"`,
+	}, {
+		args:     []string{"--operation", "Client"},
+		expected: params.AuditLogFilter{Facade: "Client"},
+	}, {
+		args:     []string{"--operation", "Client.ServiceDeploy"},
+		expected: params.AuditLogFilter{Facade: "Client", Method: "ServiceDeploy"},
+	}, {
+		args:     []string{"--operation", "Client."},
+		errMatch: `invalid operation "Client."`,
+	}, {
+		args: []string{"--from", "2014-11-01", "--to", "2014-11-02T12:30:00Z"},
+		expected: params.AuditLogFilter{
+			After:  time.Date(2014, 11, 1, 0, 0, 0, 0, time.UTC),
+			Before: time.Date(2014, 11, 2, 12, 30, 0, 0, time.UTC),
+		},
+	}, {
+		args: []string{"--from", "2014-11-01", "--to", "2014-11-01"},
+		expected: params.AuditLogFilter{
+			After:  time.Date(2014, 11, 1, 0, 0, 0, 0, time.UTC),
+			Before: time.Date(2014, 11, 1, 23, 59, 59, 999999999, time.UTC),
+		},
+	}, {
+		args:     []string{"--from", "yesterday"},
+		errMatch: `invalid --from value: "yesterday" is neither a date nor an RFC 3339 time`,
+	}, {
+		args:     []string{"--limit", "10"},
+		expected: params.AuditLogFilter{Limit: 10},
+	}, {
+		args:     []string{"--limit", "-1"},
+		errMatch: `invalid limit -1`,
+	}, {
+		args:     []string{"extra"},
+		errMatch: `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := &AuditLogCommand{}
+		err := testing.InitCommand(envcmd.Wrap(command), test.args)
+		if test.errMatch != "" {
+			c.Check(err, gc.ErrorMatches, test.errMatch)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(command.filter, jc.DeepEquals, test.expected)
+	}
+}
+
+func (s *AuditLogSuite) TestRunTabular(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&AuditLogCommand{}), "--user", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.filter, jc.DeepEquals, params.AuditLogFilter{User: "user-admin@local"})
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"TIME                  USER   OPERATION               OUTCOME\n"+
+		"2014-11-05T10:00:00Z  admin  Client.ServiceDeploy    success\n"+
+		"2014-11-05T10:01:00Z  bob    Client.DestroyMachines  failure: permission denied\n")
+}
+
+func (s *AuditLogSuite) TestRunYAML(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&AuditLogCommand{}), "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	var result []map[string]string
+	err = goyaml.Unmarshal(ctx.Stdout.(*bytes.Buffer).Bytes(), &result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, []map[string]string{{
+		"time":      "2014-11-05T10:00:00Z",
+		"user":      "admin",
+		"operation": "Client.ServiceDeploy",
+		"arguments": `{"ServiceName":"mysql"}`,
+		"outcome":   "success",
+	}, {
+		"time":      "2014-11-05T10:01:00Z",
+		"user":      "bob",
+		"operation": "Client.DestroyMachines",
+		"outcome":   "failure",
+		"error":     "permission denied",
+	}})
+}
+
+func (s *AuditLogSuite) TestRunJSON(c *gc.C) {
+	s.fake.entries = s.fake.entries[1:]
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&AuditLogCommand{}), "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `[{"time":"2014-11-05T10:01:00Z","user":"bob",`+
+		`"operation":"Client.DestroyMachines","outcome":"failure","error":"permission denied"}]`+"\n")
+}
+
+func (s *AuditLogSuite) TestRunError(c *gc.C) {
+	s.fake.err = errors.New("boom")
+	_, err := testing.RunCommand(c, envcmd.Wrap(&AuditLogCommand{}))
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -4,17 +4,23 @@
 package backups
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/loggo"
 
 	"github.com/juju/juju/api/backups"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
+	statebackups "github.com/juju/juju/state/backups"
 )
 
+var logger = loggo.GetLogger("juju.cmd.juju.backups")
+
 var backupsDoc = `
 "juju backups" is used to manage backups of the state of a juju environment.
 `
@@ -44,6 +50,8 @@ func NewCommand() cmd.Command {
 	backupsCmd.Register(envcmd.Wrap(&DownloadCommand{}))
 	backupsCmd.Register(envcmd.Wrap(&UploadCommand{}))
 	backupsCmd.Register(envcmd.Wrap(&RemoveCommand{}))
+	backupsCmd.Register(envcmd.Wrap(&ScheduleCommand{}))
+	backupsCmd.Register(envcmd.Wrap(&RestoreCommand{}))
 	return &backupsCmd
 }
 
@@ -51,8 +59,9 @@ func NewCommand() cmd.Command {
 // the backups command.
 type APIClient interface {
 	io.Closer
-	// Create sends an RPC request to create a new backup.
-	Create(notes string) (*params.BackupsMetadataResult, error)
+	// Create sends an RPC request to create a new backup, encrypted
+	// with the key unless it is nil.
+	Create(notes string, key []byte) (*params.BackupsMetadataResult, error)
 	// Info gets the backup's metadata.
 	Info(id string) (*params.BackupsMetadataResult, error)
 	// List gets all stored metadata.
@@ -63,6 +72,17 @@ type APIClient interface {
 	Upload(ar io.Reader, meta params.BackupsMetadataResult) (string, error)
 	// Remove removes the stored backup.
 	Remove(id string) error
+	// Schedule gets the backups schedule and retention policy.
+	Schedule() (*params.BackupsScheduleResult, error)
+	// SetSchedule changes the backups schedule and retention policy.
+	SetSchedule(args params.BackupsSetScheduleArgs) error
+	// PrepareRestore puts the API server into restore mode.
+	PrepareRestore() error
+	// Restore restores the stored backup on the state server,
+	// decrypting it with the key if it is encrypted.
+	Restore(id string, key []byte) error
+	// FinishRestore confirms the restore once the API server is back.
+	FinishRestore() error
 }
 
 // CommandBase is the base type for backups sub-commands.
@@ -83,6 +103,20 @@ var newAPIClient = func(c *CommandBase) (APIClient, error) {
 	return backups.NewClient(root), nil
 }
 
+// readKeyFile returns the backup encryption key in the named file.  A
+// trailing newline is not part of the key.
+func (c *CommandBase) readKeyFile(ctx *cmd.Context, filename string) ([]byte, error) {
+	data, err := ioutil.ReadFile(ctx.AbsPath(filename))
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read encryption key")
+	}
+	key := bytes.TrimSuffix(bytes.TrimSuffix(data, []byte("\n")), []byte("\r"))
+	if err := statebackups.ValidateKey(key); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return key, nil
+}
+
 // dumpMetadata writes the formatted backup metadata to stdout.
 func (c *CommandBase) dumpMetadata(ctx *cmd.Context, result *params.BackupsMetadataResult) {
 	fmt.Fprintf(ctx.Stdout, "backup ID:       %q\n", result.ID)
@@ -94,6 +128,12 @@ func (c *CommandBase) dumpMetadata(ctx *cmd.Context, result *params.BackupsMetad
 	fmt.Fprintf(ctx.Stdout, "started:         %v\n", result.Started)
 	fmt.Fprintf(ctx.Stdout, "finished:        %v\n", result.Finished)
 	fmt.Fprintf(ctx.Stdout, "notes:           %q\n", result.Notes)
+	if result.KeyID != "" {
+		fmt.Fprintf(ctx.Stdout, "encryption key:  %q\n", result.KeyID)
+	}
+	if result.Scheduled {
+		fmt.Fprintf(ctx.Stdout, "scheduled:       true\n")
+	}
 
 	fmt.Fprintf(ctx.Stdout, "environment ID:  %q\n", result.Environment)
 	fmt.Fprintf(ctx.Stdout, "machine ID:      %q\n", result.Machine)
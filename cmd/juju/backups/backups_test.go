@@ -19,6 +19,8 @@ var expectedSubCommmandNames = []string{
 	"info",
 	"list",
 	"remove",
+	"restore",
+	"schedule",
 	"upload",
 }
 
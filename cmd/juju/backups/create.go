@@ -32,6 +32,11 @@ The --download option may be used without the --filename option.  In
 that case, the backup archive will be stored in the current working
 directory with a name matching juju-backup-<date>-<time>.tar.gz.
 
+The --key-file option encrypts the backup archive with the key in the
+given file, which must be at least 16 bytes long.  The key is not
+stored by juju: keep it safe, as the backup cannot be restored without
+it.
+
 WARNING: Remotely stored backups will be lost when the environment is
 destroyed.  Furthermore, the remotely backup is not guaranteed to be
 available.
@@ -53,6 +58,8 @@ type CreateCommand struct {
 	Filename string
 	// Notes is the custom message to associated with the new backup.
 	Notes string
+	// KeyFile is the file holding the key to encrypt the backup with.
+	KeyFile string
 }
 
 // Info implements Command.Info.
@@ -70,6 +77,7 @@ func (c *CreateCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.Quiet, "quiet", false, "do not print the metadata")
 	f.BoolVar(&c.NoDownload, "no-download", false, "do not download the archive")
 	f.StringVar(&c.Filename, "filename", notset, "download to this file")
+	f.StringVar(&c.KeyFile, "key-file", "", "encrypt the backup with the key in this file")
 }
 
 // Init implements Command.Init.
@@ -98,7 +106,14 @@ func (c *CreateCommand) Run(ctx *cmd.Context) error {
 	}
 	defer client.Close()
 
-	result, err := client.Create(c.Notes)
+	var key []byte
+	if c.KeyFile != "" {
+		if key, err = c.readKeyFile(ctx, c.KeyFile); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	result, err := client.Create(c.Notes, key)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -147,6 +147,29 @@ func (s *createSuite) TestNoDownload(c *gc.C) {
 	c.Check(s.subcommand.Filename, gc.Equals, backups.NotSet)
 }
 
+func (s *createSuite) TestKeyFile(c *gc.C) {
+	client := s.setSuccess()
+	s.subcommand.NoDownload = true
+	s.subcommand.KeyFile = writeKeyFile(c, "some secret key!")
+	ctx := cmdtesting.Context(c)
+	err := s.subcommand.Run(ctx)
+	c.Assert(err, jc.ErrorIsNil)
+
+	client.Check(c, "", "", "Create")
+	c.Check(string(client.key), gc.Equals, "some secret key!")
+}
+
+func (s *createSuite) TestKeyFileTooShort(c *gc.C) {
+	client := s.setSuccess()
+	s.subcommand.NoDownload = true
+	s.subcommand.KeyFile = writeKeyFile(c, "short")
+	ctx := cmdtesting.Context(c)
+	err := s.subcommand.Run(ctx)
+
+	c.Check(err, gc.ErrorMatches, "encryption key too short: need at least 16 bytes, got 5")
+	c.Check(client.calls, gc.HasLen, 0)
+}
+
 func (s *createSuite) TestFilenameAndNoDownload(c *gc.C) {
 	s.setSuccess()
 	_, err := testing.RunCommand(c, s.command, "create", "--no-download", "--filename", "backup.tgz")
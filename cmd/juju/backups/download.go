@@ -20,6 +20,10 @@ const downloadDoc = `
 
 If --filename is not used, the archive is downloaded to a temporary
 location and the filename is printed to stdout.
+
+The archive is checked against the checksum recorded when the backup
+was created.  Encrypted archives are downloaded as they are stored,
+unless --decrypt is used with the --key-file holding their key.
 `
 
 // DownloadCommand is the sub-command for downloading a backup archive.
@@ -29,6 +33,11 @@ type DownloadCommand struct {
 	Filename string
 	// ID is the backup ID to download.
 	ID string
+	// Decrypt means the archive should be decrypted as it is
+	// downloaded.
+	Decrypt bool
+	// KeyFile is the file holding the key to decrypt the archive with.
+	KeyFile string
 }
 
 // Info implements Command.Info.
@@ -44,6 +53,8 @@ func (c *DownloadCommand) Info() *cmd.Info {
 // SetFlags implements Command.SetFlags.
 func (c *DownloadCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.Filename, "filename", "", "download target")
+	f.BoolVar(&c.Decrypt, "decrypt", false, "decrypt the archive")
+	f.StringVar(&c.KeyFile, "key-file", "", "the file holding the key to decrypt the archive with")
 }
 
 // Init implements Command.Init.
@@ -56,6 +67,12 @@ func (c *DownloadCommand) Init(args []string) error {
 		return errors.Trace(err)
 	}
 	c.ID = id
+	if c.Decrypt && c.KeyFile == "" {
+		return errors.New("--decrypt requires --key-file")
+	}
+	if !c.Decrypt && c.KeyFile != "" {
+		return errors.New("--key-file requires --decrypt")
+	}
 	return nil
 }
 
@@ -74,6 +91,18 @@ func (c *DownloadCommand) Run(ctx *cmd.Context) error {
 	}
 	defer resultArchive.Close()
 
+	source := io.Reader(resultArchive)
+	if c.Decrypt {
+		key, err := c.readKeyFile(ctx, c.KeyFile)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		source, err = backups.NewDecryptingReader(resultArchive, key)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+
 	// Prepare the local archive.
 	filename := c.ResolveFilename()
 	archive, err := os.Create(filename)
@@ -82,9 +111,14 @@ func (c *DownloadCommand) Run(ctx *cmd.Context) error {
 	}
 	defer archive.Close()
 
-	// Write out the archive.
-	_, err = io.Copy(archive, resultArchive)
+	// Write out the archive.  It is removed if it turns out to be
+	// corrupt.
+	_, err = io.Copy(archive, source)
 	if err != nil {
+		archive.Close()
+		if rerr := os.Remove(filename); rerr != nil {
+			logger.Errorf("cannot remove %q: %v", filename, rerr)
+		}
 		return errors.Annotate(err, "while creating local archive file")
 	}
 
@@ -4,12 +4,18 @@
 package backups_test
 
 import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+
 	"github.com/juju/cmd/cmdtesting"
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/cmd/juju/backups"
+	statebackups "github.com/juju/juju/state/backups"
 	"github.com/juju/juju/testing"
 )
 
@@ -89,3 +95,48 @@ func (s *downloadSuite) TestError(c *gc.C) {
 
 	c.Check(errors.Cause(err), gc.ErrorMatches, "failed!")
 }
+
+func (s *downloadSuite) setEncrypted(c *gc.C, key string) {
+	client := s.setSuccess()
+	var buf bytes.Buffer
+	encrypter, err := statebackups.NewEncryptingWriter(&buf, []byte(key))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = io.WriteString(encrypter, s.data)
+	c.Assert(err, jc.ErrorIsNil)
+	err = encrypter.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	client.archive = ioutil.NopCloser(&buf)
+}
+
+func (s *downloadSuite) TestDecrypt(c *gc.C) {
+	s.setEncrypted(c, "some secret key!")
+	s.subcommand.Decrypt = true
+	s.subcommand.KeyFile = writeKeyFile(c, "some secret key!")
+	ctx := cmdtesting.Context(c)
+	err := s.subcommand.Run(ctx)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.filename = "juju-backup-" + s.metaresult.ID + ".tar.gz"
+	s.checkStd(c, ctx, s.filename+"\n", "")
+	s.checkArchive(c)
+}
+
+func (s *downloadSuite) TestDecryptWrongKey(c *gc.C) {
+	s.setEncrypted(c, "some secret key!")
+	s.subcommand.Decrypt = true
+	s.subcommand.KeyFile = writeKeyFile(c, "another secret!!")
+	ctx := cmdtesting.Context(c)
+	err := s.subcommand.Run(ctx)
+
+	c.Check(err, gc.ErrorMatches, "backup archive is encrypted with key .*, not .*")
+	_, err = os.Stat(s.subcommand.ResolveFilename())
+	c.Check(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *downloadSuite) TestDecryptNeedsKeyFile(c *gc.C) {
+	err := testing.InitCommand(&backups.DownloadCommand{}, []string{"--decrypt", "spam"})
+	c.Check(err, gc.ErrorMatches, "--decrypt requires --key-file")
+
+	err = testing.InitCommand(&backups.DownloadCommand{}, []string{"--key-file", "backup.key", "spam"})
+	c.Check(err, gc.ErrorMatches, "--key-file requires --decrypt")
+}
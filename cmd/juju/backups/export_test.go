@@ -9,5 +9,7 @@ const (
 )
 
 var (
-	NewAPIClient = &newAPIClient
+	NewAPIClient     = &newAPIClient
+	BootstrapEnviron = &bootstrapEnviron
+	ReconnectAttempt = &reconnectAttempt
 )
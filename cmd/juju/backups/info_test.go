@@ -51,6 +51,19 @@ func (s *infoSuite) TestOkay(c *gc.C) {
 	s.checkStd(c, ctx, out, "")
 }
 
+func (s *infoSuite) TestScheduled(c *gc.C) {
+	s.metaresult.Scheduled = true
+	s.setSuccess()
+	ctx := cmdtesting.Context(c)
+	err := s.subcommand.Run(ctx)
+	c.Check(err, jc.ErrorIsNil)
+
+	out := strings.Replace(MetaResultString,
+		"notes:           \"\"\n",
+		"notes:           \"\"\nscheduled:       true\n", 1)
+	s.checkStd(c, ctx, out, "")
+}
+
 func (s *infoSuite) TestError(c *gc.C) {
 	s.setFailure("failed!")
 	ctx := cmdtesting.Context(c)
@@ -4,10 +4,13 @@
 package backups_test
 
 import (
+	"archive/tar"
 	"bytes"
+	"compress/gzip"
 	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -150,15 +153,55 @@ func (s *BaseBackupsSuite) checkStd(c *gc.C, ctx *cmd.Context, out, err string)
 	s.checkString(c, ctx.Stderr.(*bytes.Buffer).String(), err)
 }
 
-type fakeAPIClient struct {
-	metaresult *params.BackupsMetadataResult
-	archive    io.ReadCloser
-	err        error
+// writeArchive writes a minimal backup archive to the named file.
+func writeArchive(c *gc.C, filename string) {
+	archive, err := os.Create(filename)
+	c.Assert(err, jc.ErrorIsNil)
+	defer archive.Close()
+
+	compressed := gzip.NewWriter(archive)
+	defer compressed.Close()
 
-	calls []string
-	args  []string
-	idArg string
-	notes string
+	tarball := tar.NewWriter(compressed)
+	defer tarball.Close()
+
+	var files = []struct{ Name, Body string }{
+		{"root.tar", "<state config files>"},
+		{"dump/oplog.bson", "<something here>"},
+	}
+	for _, file := range files {
+		hdr := &tar.Header{
+			Name: file.Name,
+			Size: int64(len(file.Body)),
+		}
+		err := tarball.WriteHeader(hdr)
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = tarball.Write([]byte(file.Body))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+// writeKeyFile writes the encryption key to a new file, followed by a
+// newline, and returns the file's name.
+func writeKeyFile(c *gc.C, key string) string {
+	filename := filepath.Join(c.MkDir(), "backup.key")
+	err := ioutil.WriteFile(filename, []byte(key+"\n"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	return filename
+}
+
+type fakeAPIClient struct {
+	metaresult     *params.BackupsMetadataResult
+	archive        io.ReadCloser
+	scheduleresult *params.BackupsScheduleResult
+	err            error
+
+	calls        []string
+	args         []string
+	idArg        string
+	notes        string
+	key          []byte
+	scheduleArgs params.BackupsSetScheduleArgs
 }
 
 func (f *fakeAPIClient) Check(c *gc.C, id, notes string, calls ...string) {
@@ -167,10 +210,11 @@ func (f *fakeAPIClient) Check(c *gc.C, id, notes string, calls ...string) {
 	c.Check(f.notes, gc.Equals, notes)
 }
 
-func (c *fakeAPIClient) Create(notes string) (*params.BackupsMetadataResult, error) {
+func (c *fakeAPIClient) Create(notes string, key []byte) (*params.BackupsMetadataResult, error) {
 	c.calls = append(c.calls, "Create")
-	c.args = append(c.args, "notes")
+	c.args = append(c.args, "notes", "key")
 	c.notes = notes
+	c.key = key
 	if c.err != nil {
 		return nil, c.err
 	}
@@ -225,6 +269,42 @@ func (c *fakeAPIClient) Remove(id string) error {
 	return nil
 }
 
+func (c *fakeAPIClient) Schedule() (*params.BackupsScheduleResult, error) {
+	c.calls = append(c.calls, "Schedule")
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.scheduleresult, nil
+}
+
+func (c *fakeAPIClient) SetSchedule(args params.BackupsSetScheduleArgs) error {
+	c.calls = append(c.calls, "SetSchedule")
+	c.args = append(c.args, "args")
+	c.scheduleArgs = args
+	if c.err != nil {
+		return c.err
+	}
+	return nil
+}
+
+func (c *fakeAPIClient) PrepareRestore() error {
+	c.calls = append(c.calls, "PrepareRestore")
+	return c.err
+}
+
+func (c *fakeAPIClient) Restore(id string, key []byte) error {
+	c.calls = append(c.calls, "Restore")
+	c.args = append(c.args, "id", "key")
+	c.idArg = id
+	c.key = key
+	return c.err
+}
+
+func (c *fakeAPIClient) FinishRestore() error {
+	c.calls = append(c.calls, "FinishRestore")
+	return c.err
+}
+
 func (c *fakeAPIClient) Close() error {
 	return nil
 }
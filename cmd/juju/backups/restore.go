@@ -0,0 +1,244 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/bootstrap"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/provider/common"
+)
+
+const restoreDoc = `
+"restore" replaces the state of the environment with the one in a
+backup, which is either one stored in the environment, given by its ID,
+or a local archive file given with --file.
+
+The backup is restored on the state server it was created on.  If that
+state server is gone, use -b with --file to bootstrap a new one first,
+with the given --constraints.  The new state server takes over the
+machine ID and configuration of the old one, and all the other machine
+agents in the environment are pointed to it.
+
+Encrypted backups are decrypted with the key in the given --key-file.
+The archive is checked against its checksum before anything is
+restored.
+
+While the backup is restored the API server refuses all other requests.
+Once done it restarts, and "restore" reconnects to it to confirm that
+the environment is usable again.
+`
+
+// RestoreCommand is the sub-command for restoring a backup.
+type RestoreCommand struct {
+	CommandBase
+	// ID is the ID of the stored backup to restore.
+	ID string
+	// Filename is the local backup archive to upload and restore.
+	Filename string
+	// Bootstrap means a new state server is bootstrapped to restore
+	// the backup on.
+	Bootstrap bool
+	// Constraints are the constraints for the new state server.
+	Constraints constraints.Value
+	// KeyFile is the file holding the key to decrypt the backup with.
+	KeyFile string
+}
+
+// Info implements Command.Info.
+func (c *RestoreCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "restore",
+		Args:    "[<ID>]",
+		Purpose: "restore a backup of juju's state",
+		Doc:     restoreDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *RestoreCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.Filename, "file", "", "upload and restore this local backup archive")
+	f.BoolVar(&c.Bootstrap, "b", false, "bootstrap a new state server to restore the backup on")
+	f.Var(constraints.ConstraintsValue{Target: &c.Constraints}, "constraints", "set constraints for the new state server")
+	f.StringVar(&c.KeyFile, "key-file", "", "decrypt the backup with the key in this file")
+}
+
+// Init implements Command.Init.
+func (c *RestoreCommand) Init(args []string) error {
+	if len(args) > 0 {
+		c.ID, args = args[0], args[1:]
+	}
+	if err := cmd.CheckEmpty(args); err != nil {
+		return errors.Trace(err)
+	}
+	switch {
+	case c.ID == "" && c.Filename == "":
+		return errors.New("missing ID or --file")
+	case c.ID != "" && c.Filename != "":
+		return errors.New("cannot mix ID and --file")
+	case c.Bootstrap && c.Filename == "":
+		return errors.New("-b requires --file, as a new state server has no stored backups")
+	}
+	return nil
+}
+
+// reconnectAttempt is used to wait for the API server to come back
+// after a bootstrap or a restore.
+var reconnectAttempt = utils.AttemptStrategy{
+	Total: 5 * time.Minute,
+	Delay: 10 * time.Second,
+}
+
+// Run implements Command.Run.
+func (c *RestoreCommand) Run(ctx *cmd.Context) error {
+	var key []byte
+	if c.KeyFile != "" {
+		var err error
+		if key, err = c.readKeyFile(ctx, c.KeyFile); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	if c.Bootstrap {
+		fmt.Fprintln(ctx.Stdout, "bootstrapping a new state server")
+		if err := c.rebootstrap(ctx); err != nil {
+			return errors.Annotate(err, "cannot bootstrap new state server")
+		}
+	}
+
+	client, err := c.dialAPIClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	id := c.ID
+	if c.Filename != "" {
+		fmt.Fprintln(ctx.Stdout, "uploading backup archive")
+		archive, meta, err := c.getArchive(c.Filename)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		defer archive.Close()
+		id, err = client.Upload(archive, *meta)
+		if err != nil {
+			return errors.Annotate(err, "cannot upload backup archive")
+		}
+	}
+
+	fmt.Fprintln(ctx.Stdout, "preparing state server for restore")
+	if err := client.PrepareRestore(); err != nil {
+		return errors.Annotate(err, "cannot prepare restore")
+	}
+	fmt.Fprintf(ctx.Stdout, "restoring backup %s\n", id)
+	if err := client.Restore(id, key); err != nil {
+		return errors.Annotate(err, "cannot restore backup")
+	}
+
+	fmt.Fprintln(ctx.Stdout, "waiting for the restored state server")
+	if err := c.finishRestore(); err != nil {
+		return errors.Trace(err)
+	}
+	fmt.Fprintln(ctx.Stdout, "restore complete")
+	return nil
+}
+
+// dialAPIClient connects to the API server, retrying while it is not
+// ready yet.
+func (c *RestoreCommand) dialAPIClient() (client APIClient, err error) {
+	for a := reconnectAttempt.Start(); a.Next(); {
+		client, err = c.NewAPIClient()
+		if err == nil {
+			return client, nil
+		}
+		logger.Debugf("API server not ready yet: %v", err)
+	}
+	return nil, errors.Annotate(err, "cannot connect to API server")
+}
+
+// finishRestore reconnects to the restarted API server and tells it
+// the restore is done.
+func (c *RestoreCommand) finishRestore() (err error) {
+	for a := reconnectAttempt.Start(); a.Next(); {
+		var client APIClient
+		client, err = c.NewAPIClient()
+		if err != nil {
+			logger.Debugf("restored API server not ready yet: %v", err)
+			continue
+		}
+		err = client.FinishRestore()
+		client.Close()
+		if err == nil {
+			return nil
+		}
+		logger.Debugf("restore not finished yet: %v", err)
+	}
+	return errors.Annotate(err, "cannot finish restore")
+}
+
+// rebootstrap bootstraps a new state server for the environment,
+// after checking that the old ones are gone.
+func (c *RestoreCommand) rebootstrap(ctx *cmd.Context) error {
+	store, err := configstore.Default()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	cfg, err := c.Config(store)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return bootstrapEnviron(ctx, cfg, c.Constraints)
+}
+
+var bootstrapEnviron = func(ctx *cmd.Context, cfg *config.Config, cons constraints.Value) error {
+	// Turn on safe mode so that the newly bootstrapped instance
+	// will not destroy all the instances it does not know about.
+	cfg, err := cfg.Apply(map[string]interface{}{
+		"provisioner-safe-mode": true,
+	})
+	if err != nil {
+		return errors.Annotate(err, "cannot enable provisioner-safe-mode")
+	}
+	env, err := environs.New(cfg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	instanceIds, err := env.StateServerInstances()
+	if err != nil {
+		return errors.Annotate(err, "cannot determine state server instances")
+	}
+	if len(instanceIds) == 0 {
+		return errors.New("no instances found; perhaps the environment was not bootstrapped")
+	}
+	inst, err := env.Instances(instanceIds)
+	if err == nil {
+		return errors.Errorf("old state server instance %q still seems to exist; will not replace", inst)
+	}
+	if err != environs.ErrNoInstances {
+		return errors.Annotate(err, "cannot detect whether old instance is still running")
+	}
+	// Remove the storage so that we can bootstrap without the provider complaining.
+	if env, ok := env.(environs.EnvironStorage); ok {
+		if err := env.Storage().Remove(common.StateFile); err != nil {
+			return errors.Annotatef(err, "cannot remove %q from storage", common.StateFile)
+		}
+	}
+
+	args := bootstrap.BootstrapParams{Constraints: cons}
+	if err := bootstrap.Bootstrap(envcmd.BootstrapContextNoVerify(ctx), env, args); err != nil {
+		return errors.Annotate(err, "cannot bootstrap new instance")
+	}
+	return nil
+}
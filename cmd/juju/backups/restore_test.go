@@ -0,0 +1,134 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/backups"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/testing"
+)
+
+type restoreSuite struct {
+	BaseBackupsSuite
+	subcommand *backups.RestoreCommand
+}
+
+var _ = gc.Suite(&restoreSuite{})
+
+func (s *restoreSuite) SetUpTest(c *gc.C) {
+	s.BaseBackupsSuite.SetUpTest(c)
+	s.subcommand = &backups.RestoreCommand{}
+	s.PatchValue(backups.ReconnectAttempt, utils.AttemptStrategy{})
+	s.PatchValue(backups.BootstrapEnviron, func(*cmd.Context, *config.Config, constraints.Value) error {
+		c.Fatalf("unexpected bootstrap")
+		return nil
+	})
+}
+
+func (s *restoreSuite) TestHelp(c *gc.C) {
+	ctx, err := testing.RunCommand(c, s.command, "restore", "--help")
+	c.Assert(err, jc.ErrorIsNil)
+
+	info := s.subcommand.Info()
+	expected := `(?sm)usage: juju backups restore \[options\] \[<ID>\]$.*`
+	c.Check(testing.Stdout(ctx), gc.Matches, expected)
+	expected = "(?sm).*^purpose: " + info.Purpose + "$.*"
+	c.Check(testing.Stdout(ctx), gc.Matches, expected)
+	expected = "(?sm).*^" + strings.Replace(info.Doc, "*", `\*`, -1) + "$.*"
+	c.Check(testing.Stdout(ctx), gc.Matches, expected)
+}
+
+func (s *restoreSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: nil,
+		err:  "missing ID or --file",
+	}, {
+		args: []string{"--file", "backup.tar.gz", "spam"},
+		err:  "cannot mix ID and --file",
+	}, {
+		args: []string{"-b", "spam"},
+		err:  "-b requires --file, as a new state server has no stored backups",
+	}, {
+		args: []string{"spam", "eggs"},
+		err:  `unrecognized args: \["eggs"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := testing.InitCommand(&backups.RestoreCommand{}, test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *restoreSuite) TestRestoreID(c *gc.C) {
+	client := s.setSuccess()
+	ctx, err := testing.RunCommand(c, s.command, "restore", "spam")
+	c.Assert(err, jc.ErrorIsNil)
+
+	out := `
+preparing state server for restore
+restoring backup spam
+waiting for the restored state server
+restore complete
+`[1:]
+	s.checkStd(c, ctx, out, "")
+	client.Check(c, "spam", "", "PrepareRestore", "Restore", "FinishRestore")
+}
+
+func (s *restoreSuite) TestRestoreKeyFile(c *gc.C) {
+	client := s.setSuccess()
+	keyFile := writeKeyFile(c, "some secret key!")
+	_, err := testing.RunCommand(c, s.command, "restore", "--key-file", keyFile, "spam")
+	c.Assert(err, jc.ErrorIsNil)
+
+	client.Check(c, "spam", "", "PrepareRestore", "Restore", "FinishRestore")
+	c.Check(string(client.key), gc.Equals, "some secret key!")
+}
+
+func (s *restoreSuite) TestRestoreFileBootstrap(c *gc.C) {
+	filename := filepath.Join(c.MkDir(), "backup.tar.gz")
+	writeArchive(c, filename)
+	var bootstrapCons constraints.Value
+	s.PatchValue(backups.BootstrapEnviron, func(_ *cmd.Context, _ *config.Config, cons constraints.Value) error {
+		bootstrapCons = cons
+		return nil
+	})
+	client := s.setSuccess()
+
+	ctx, err := testing.RunCommand(c, s.command, "restore",
+		"--file", filename, "-b", "--constraints", "mem=4G")
+	c.Assert(err, jc.ErrorIsNil)
+
+	out := `
+bootstrapping a new state server
+uploading backup archive
+preparing state server for restore
+restoring backup spam
+waiting for the restored state server
+restore complete
+`[1:]
+	s.checkStd(c, ctx, out, "")
+	c.Check(bootstrapCons, jc.DeepEquals, constraints.MustParse("mem=4G"))
+	client.Check(c, "spam", "", "PrepareRestore", "Restore", "FinishRestore")
+}
+
+func (s *restoreSuite) TestRestoreError(c *gc.C) {
+	client := s.setFailure("failed!")
+	_, err := testing.RunCommand(c, s.command, "restore", "spam")
+
+	c.Check(errors.Cause(err), gc.ErrorMatches, "failed!")
+	c.Check(err, gc.ErrorMatches, "cannot prepare restore: failed!")
+	c.Check(client.calls, jc.DeepEquals, []string{"PrepareRestore"})
+}
@@ -0,0 +1,165 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/utils/cron"
+)
+
+const scheduleDoc = `
+"schedule" shows or changes the schedule on which the state servers
+create backups of juju's state, and how many of those scheduled backups
+are kept.  When run without options it shows the current schedule, the
+time of the next scheduled backup, and the outcome of the last one.
+
+The schedule is given in crontab format, as five fields: minute, hour,
+day of month, month and day of week, in UTC.  The shortcuts @hourly,
+@daily, @weekly, @monthly and @yearly are also accepted.  For example,
+"0 3 * * *" creates a backup every day at 03:00.
+
+Scheduled backups are kept if they are one of the --keep-last most
+recent ones, or the most recent one of one of the --keep-daily most
+recent days or --keep-weekly most recent weeks.  Older scheduled
+backups are removed after each new one is created.  Setting all three
+to 0 keeps every scheduled backup.  Backups created with "juju backups
+create" are never removed automatically.
+
+Use --disable to stop creating backups on schedule.
+`
+
+// ScheduleCommand is the sub-command for showing and changing the
+// backups schedule.
+type ScheduleCommand struct {
+	CommandBase
+	// Schedule is the new backups schedule, if not empty.
+	Schedule string
+	// Disable means backups should no longer be created on schedule.
+	Disable bool
+	// KeepLast, KeepDaily and KeepWeekly are the new retention
+	// policy settings, if set.
+	KeepLast   optionalInt
+	KeepDaily  optionalInt
+	KeepWeekly optionalInt
+}
+
+// Info implements Command.Info.
+func (c *ScheduleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "schedule",
+		Purpose: "show or change the backups schedule",
+		Doc:     scheduleDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *ScheduleCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.Schedule, "schedule", "", "create backups on this schedule")
+	f.BoolVar(&c.Disable, "disable", false, "stop creating backups on schedule")
+	f.Var(&c.KeepLast, "keep-last", "keep this many of the most recent scheduled backups")
+	f.Var(&c.KeepDaily, "keep-daily", "keep the last scheduled backup of this many days")
+	f.Var(&c.KeepWeekly, "keep-weekly", "keep the last scheduled backup of this many weeks")
+}
+
+// Init implements Command.Init.
+func (c *ScheduleCommand) Init(args []string) error {
+	if err := cmd.CheckEmpty(args); err != nil {
+		return errors.Trace(err)
+	}
+	if c.Disable && c.Schedule != "" {
+		return errors.Errorf("cannot mix --disable and --schedule")
+	}
+	if c.Schedule != "" {
+		if _, err := cron.Parse(c.Schedule); err != nil {
+			return errors.Annotate(err, "invalid schedule")
+		}
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *ScheduleCommand) Run(ctx *cmd.Context) error {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	args := params.BackupsSetScheduleArgs{
+		KeepLast:   c.KeepLast.value,
+		KeepDaily:  c.KeepDaily.value,
+		KeepWeekly: c.KeepWeekly.value,
+	}
+	switch {
+	case c.Disable:
+		args.Schedule = new(string)
+	case c.Schedule != "":
+		args.Schedule = &c.Schedule
+	}
+	if args != (params.BackupsSetScheduleArgs{}) {
+		if err := client.SetSchedule(args); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	result, err := client.Schedule()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	c.dumpSchedule(ctx, result)
+	return nil
+}
+
+// dumpSchedule writes the formatted backups schedule to stdout.
+func (c *ScheduleCommand) dumpSchedule(ctx *cmd.Context, result *params.BackupsScheduleResult) {
+	if result.Schedule == "" {
+		fmt.Fprintln(ctx.Stdout, "schedule:        (disabled)")
+	} else {
+		fmt.Fprintf(ctx.Stdout, "schedule:        %q\n", result.Schedule)
+		fmt.Fprintf(ctx.Stdout, "next backup:     %v\n", result.NextRun)
+	}
+	fmt.Fprintf(ctx.Stdout, "keep last:       %d\n", result.KeepLast)
+	fmt.Fprintf(ctx.Stdout, "keep daily:      %d\n", result.KeepDaily)
+	fmt.Fprintf(ctx.Stdout, "keep weekly:     %d\n", result.KeepWeekly)
+	if result.Status != "" {
+		fmt.Fprintf(ctx.Stdout, "last status:     %s\n", result.Status)
+	}
+	if result.StatusInfo != "" {
+		fmt.Fprintf(ctx.Stdout, "status info:     %q\n", result.StatusInfo)
+	}
+}
+
+// optionalInt is a non-negative integer flag that records whether it
+// was set.
+type optionalInt struct {
+	value *int
+}
+
+// Set implements gnuflag.Value.
+func (v *optionalInt) Set(s string) error {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return errors.Errorf("expected integer, got %q", s)
+	}
+	if n < 0 {
+		return errors.Errorf("expected non-negative integer, got %d", n)
+	}
+	v.value = &n
+	return nil
+}
+
+// String implements gnuflag.Value.
+func (v *optionalInt) String() string {
+	if v.value == nil {
+		return ""
+	}
+	return strconv.Itoa(*v.value)
+}
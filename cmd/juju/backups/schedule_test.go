@@ -0,0 +1,142 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/backups"
+	"github.com/juju/juju/testing"
+)
+
+type scheduleSuite struct {
+	BaseBackupsSuite
+	subcommand *backups.ScheduleCommand
+}
+
+var _ = gc.Suite(&scheduleSuite{})
+
+func (s *scheduleSuite) SetUpTest(c *gc.C) {
+	s.BaseBackupsSuite.SetUpTest(c)
+	s.subcommand = &backups.ScheduleCommand{}
+}
+
+func (s *scheduleSuite) setSchedule(result params.BackupsScheduleResult) *fakeAPIClient {
+	client := s.setSuccess()
+	client.scheduleresult = &result
+	return client
+}
+
+func (s *scheduleSuite) TestHelp(c *gc.C) {
+	ctx, err := testing.RunCommand(c, s.command, "schedule", "--help")
+	c.Assert(err, jc.ErrorIsNil)
+
+	info := s.subcommand.Info()
+	expected := "(?sm)usage: juju backups schedule \\[options\\]$.*"
+	c.Check(testing.Stdout(ctx), gc.Matches, expected)
+	expected = "(?sm).*^purpose: " + info.Purpose + "$.*"
+	c.Check(testing.Stdout(ctx), gc.Matches, expected)
+	expected = "(?sm).*^" + strings.Replace(info.Doc, "*", `\*`, -1) + "$.*"
+	c.Check(testing.Stdout(ctx), gc.Matches, expected)
+}
+
+func (s *scheduleSuite) TestShow(c *gc.C) {
+	client := s.setSchedule(params.BackupsScheduleResult{
+		Schedule:   "@daily",
+		KeepLast:   3,
+		NextRun:    time.Date(2015, 3, 2, 0, 0, 0, 0, time.UTC),
+		Status:     "error",
+		StatusInfo: "cannot create backup: boom",
+	})
+	ctx, err := testing.RunCommand(c, s.command, "schedule")
+	c.Assert(err, jc.ErrorIsNil)
+
+	out := `
+schedule:        "@daily"
+next backup:     2015-03-02 00:00:00 +0000 UTC
+keep last:       3
+keep daily:      0
+keep weekly:     0
+last status:     error
+status info:     "cannot create backup: boom"
+`[1:]
+	s.checkStd(c, ctx, out, "")
+	c.Check(client.calls, jc.DeepEquals, []string{"Schedule"})
+}
+
+func (s *scheduleSuite) TestShowDisabled(c *gc.C) {
+	s.setSchedule(params.BackupsScheduleResult{})
+	ctx, err := testing.RunCommand(c, s.command, "schedule")
+	c.Assert(err, jc.ErrorIsNil)
+
+	out := `
+schedule:        (disabled)
+keep last:       0
+keep daily:      0
+keep weekly:     0
+`[1:]
+	s.checkStd(c, ctx, out, "")
+}
+
+func (s *scheduleSuite) TestSet(c *gc.C) {
+	client := s.setSchedule(params.BackupsScheduleResult{})
+	_, err := testing.RunCommand(c, s.command, "schedule",
+		"--schedule", "0 3 * * *", "--keep-last", "7", "--keep-weekly", "0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(client.calls, jc.DeepEquals, []string{"SetSchedule", "Schedule"})
+	args := client.scheduleArgs
+	c.Assert(args.Schedule, gc.NotNil)
+	c.Check(*args.Schedule, gc.Equals, "0 3 * * *")
+	c.Assert(args.KeepLast, gc.NotNil)
+	c.Check(*args.KeepLast, gc.Equals, 7)
+	c.Check(args.KeepDaily, gc.IsNil)
+	c.Assert(args.KeepWeekly, gc.NotNil)
+	c.Check(*args.KeepWeekly, gc.Equals, 0)
+}
+
+func (s *scheduleSuite) TestDisable(c *gc.C) {
+	client := s.setSchedule(params.BackupsScheduleResult{})
+	_, err := testing.RunCommand(c, s.command, "schedule", "--disable")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(client.scheduleArgs.Schedule, gc.NotNil)
+	c.Check(*client.scheduleArgs.Schedule, gc.Equals, "")
+}
+
+func (s *scheduleSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"--disable", "--schedule", "@daily"},
+		err:  "cannot mix --disable and --schedule",
+	}, {
+		args: []string{"--schedule", "every day"},
+		err:  "invalid schedule: .*",
+	}, {
+		args: []string{"--keep-last", "-1"},
+		err:  `invalid value "-1" for flag --keep-last: expected non-negative integer, got -1`,
+	}, {
+		args: []string{"spam"},
+		err:  `unrecognized args: \["spam"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := testing.InitCommand(&backups.ScheduleCommand{}, test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *scheduleSuite) TestError(c *gc.C) {
+	s.setFailure("failed!")
+	_, err := testing.RunCommand(c, s.command, "schedule", "--keep-last", "2")
+
+	c.Check(errors.Cause(err), gc.ErrorMatches, "failed!")
+}
@@ -117,13 +117,37 @@ func (c *UploadCommand) getStoredMetadata(id string) (*params.BackupsMetadataRes
 	return stored, errors.Trace(err)
 }
 
-func (c *UploadCommand) getArchive(filename string) (io.ReadCloser, *params.BackupsMetadataResult, error) {
+func (c *CommandBase) getArchive(filename string) (io.ReadCloser, *params.BackupsMetadataResult, error) {
 
 	archive, err := os.Open(filename)
 	if err != nil {
 		return nil, nil, errors.Trace(err)
 	}
 
+	// The metadata in encrypted archives cannot be read, so only the
+	// file info is sent along with them.
+	keyID, err := backups.ArchiveKeyID(archive)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	_, err = archive.Seek(0, os.SEEK_SET)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if keyID != "" {
+		meta, err := backups.BuildMetadata(archive)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		meta.KeyID = keyID
+		_, err = archive.Seek(0, os.SEEK_SET)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		metaResult := apiserverbackups.ResultFromMetadata(meta)
+		return archive, &metaResult, nil
+	}
+
 	// Extract the metadata.
 	ad, err := backups.NewArchiveDataReader(archive)
 	if err != nil {
@@ -4,8 +4,6 @@
 package backups_test
 
 import (
-	"archive/tar"
-	"compress/gzip"
 	"os"
 	"strings"
 
@@ -45,30 +43,7 @@ func (s *uploadSuite) TearDownTest(c *gc.C) {
 }
 
 func (s *uploadSuite) createArchive(c *gc.C) {
-	archive, err := os.Create(s.filename)
-	c.Assert(err, jc.ErrorIsNil)
-	defer archive.Close()
-
-	compressed := gzip.NewWriter(archive)
-	defer compressed.Close()
-
-	tarball := tar.NewWriter(compressed)
-	defer tarball.Close()
-
-	var files = []struct{ Name, Body string }{
-		{"root.tar", "<state config files>"},
-		{"dump/oplog.bson", "<something here>"},
-	}
-	for _, file := range files {
-		hdr := &tar.Header{
-			Name: file.Name,
-			Size: int64(len(file.Body)),
-		}
-		err := tarball.WriteHeader(hdr)
-		c.Assert(err, jc.ErrorIsNil)
-		_, err = tarball.Write([]byte(file.Body))
-		c.Assert(err, jc.ErrorIsNil)
-	}
+	writeArchive(c, s.filename)
 }
 
 func (s *uploadSuite) TestHelp(c *gc.C) {
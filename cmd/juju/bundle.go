@@ -0,0 +1,665 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"gopkg.in/juju/charm.v4"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state/multiwatcher"
+)
+
+// bundleFileName holds the name of the file describing a bundle within
+// a bundle directory.
+const bundleFileName = "bundle.yaml"
+
+// isBundleSeries reports whether the given charm URL series denotes a
+// bundle rather than a charm.
+func isBundleSeries(series string) bool {
+	return series == "bundle"
+}
+
+// looksLikeBundlePath reports whether the given deploy argument names a
+// bundle on the local file system rather than a charm.
+func looksLikeBundlePath(name string) bool {
+	return strings.HasSuffix(name, ".yaml") ||
+		strings.HasPrefix(name, ".") ||
+		filepath.IsAbs(name)
+}
+
+// readBundle returns the bundle data named by the given deploy
+// argument, which may be a path to a bundle directory or file, a
+// local bundle URL such as local:bundle/wordpress-simple, or a charm
+// store bundle URL such as cs:bundle/wordpress-simple, fetched from the
+// charm store named by the environment configuration. It returns nil
+// data and no error if the argument does not name a bundle.
+func readBundle(ctx *cmd.Context, name, repoPath string, conf *config.Config) (*charm.BundleData, error) {
+	var r io.ReadCloser
+	var err error
+	if curl, inferErr := charm.InferURL(name, "fake"); inferErr == nil && !looksLikeBundlePath(name) {
+		if !isBundleSeries(curl.Series) {
+			return nil, nil
+		}
+		if curl.Schema == "local" {
+			r, err = openBundleFile(filepath.Join(ctx.AbsPath(repoPath), curl.Series, curl.Name))
+		} else {
+			r, err = openStoreBundle(conf.CharmStoreURL(), curl)
+		}
+	} else {
+		r, err = openBundleFile(ctx.AbsPath(name))
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot read bundle %q", name)
+	}
+	defer r.Close()
+	data, err := charm.ReadBundleData(r)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot parse bundle %q", name)
+	}
+	if err := data.Verify(verifyBundleConstraints); err != nil {
+		return nil, errors.Annotatef(err, "invalid bundle %q", name)
+	}
+	return data, nil
+}
+
+// openBundleFile opens the bundle file at the given path, which may
+// also be a bundle directory.
+func openBundleFile(path string) (io.ReadCloser, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		path = filepath.Join(path, bundleFileName)
+	}
+	return os.Open(path)
+}
+
+// openStoreBundle fetches the bundle file of the given bundle from the
+// charm store API at storeURL.
+func openStoreBundle(storeURL string, curl *charm.URL) (io.ReadCloser, error) {
+	path := curl.Series + "/" + curl.Name
+	if curl.Revision >= 0 {
+		path += fmt.Sprintf("-%d", curl.Revision)
+	}
+	if curl.User != "" {
+		path = "~" + curl.User + "/" + path
+	}
+	url := fmt.Sprintf("%s/v4/%s/archive/%s", strings.TrimSuffix(storeURL, "/"), path, bundleFileName)
+	resp, err := utils.GetValidatingHTTPClient().Get(url)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, errors.NotFoundf("bundle %s", curl)
+		}
+		return nil, errors.Errorf("charm store returned %s", resp.Status)
+	}
+	return resp.Body, nil
+}
+
+func verifyBundleConstraints(value string) error {
+	_, err := constraints.Parse(value)
+	return err
+}
+
+// bundleChange represents a single step needed to deploy a bundle.
+type bundleChange interface {
+	// String returns a description of the change.
+	String() string
+}
+
+// addCharmChange adds a charm to the environment.
+type addCharmChange struct {
+	curl *charm.URL
+}
+
+func (ch *addCharmChange) String() string {
+	return fmt.Sprintf("add charm %s", ch.curl)
+}
+
+// addMachineChange adds a new machine for the machine with the given
+// id in the bundle.
+type addMachineChange struct {
+	id          string
+	series      string
+	constraints string
+}
+
+func (ch *addMachineChange) String() string {
+	s := fmt.Sprintf("add new machine %s", ch.id)
+	if ch.series != "" {
+		s += fmt.Sprintf(" with series %s", ch.series)
+	}
+	if ch.constraints != "" {
+		s += fmt.Sprintf(" with constraints %q", ch.constraints)
+	}
+	return s
+}
+
+// deployChange deploys a service, initially without any units.
+type deployChange struct {
+	service     string
+	curl        *charm.URL
+	options     map[string]interface{}
+	constraints string
+}
+
+func (ch *deployChange) String() string {
+	return fmt.Sprintf("deploy service %s using %s", ch.service, ch.curl)
+}
+
+// addUnitChange adds a unit to a service, placed according to the
+// given bundle placement directive. The series is that of the service's
+// charm, used for any new container the unit is placed in.
+type addUnitChange struct {
+	service   string
+	series    string
+	placement string
+}
+
+func (ch *addUnitChange) String() string {
+	s := fmt.Sprintf("add unit of %s", ch.service)
+	if ch.placement != "" && ch.placement != "new" {
+		s += fmt.Sprintf(" to %s", ch.placement)
+	}
+	return s
+}
+
+// addRelationChange relates two service endpoints.
+type addRelationChange struct {
+	endpoints [2]string
+}
+
+func (ch *addRelationChange) String() string {
+	return fmt.Sprintf("add relation %s - %s", ch.endpoints[0], ch.endpoints[1])
+}
+
+// bundleChanges returns the changes needed to bring the environment
+// with the given status to the state described by the given bundle.
+// The charm URLs used by each service are given in curls. Services,
+// units, relations and machines that already exist are left alone, so
+// deploying the same bundle twice results in no changes the second
+// time.
+func bundleChanges(data *charm.BundleData, curls map[string]*charm.URL, status *api.Status) ([]bundleChange, error) {
+	var (
+		changes     []bundleChange
+		charms      []bundleChange
+		machines    []bundleChange
+		deploys     []bundleChange
+		units       []bundleChange
+		colocated   []*addUnitChange
+		relations   []bundleChange
+		addedCharms = make(map[string]bool)
+		usedMachine = make(map[string]bool)
+		unitCounts  = make(map[string]int)
+		existing    = existingMachines(data, status)
+	)
+	for _, name := range sortedServiceNames(data) {
+		spec := data.Services[name]
+		curl := curls[name]
+		service, exists := status.Services[name]
+		if exists {
+			existingURL, err := charm.ParseURL(service.Charm)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if !sameCharm(existingURL, curl) {
+				return nil, errors.Errorf("service %q already exists with charm %q", name, service.Charm)
+			}
+		} else {
+			if !addedCharms[curl.String()] {
+				addedCharms[curl.String()] = true
+				charms = append(charms, &addCharmChange{curl: curl})
+			}
+			deploys = append(deploys, &deployChange{
+				service:     name,
+				curl:        curl,
+				options:     spec.Options,
+				constraints: spec.Constraints,
+			})
+		}
+		unitCounts[name] = len(service.Units)
+		for i := len(service.Units); i < spec.NumUnits; i++ {
+			placement := "new"
+			if i < len(spec.To) {
+				placement = spec.To[i]
+			}
+			change := &addUnitChange{service: name, series: curl.Series, placement: placement}
+			_, target := splitPlacement(placement)
+			switch {
+			case strings.Contains(target, "/"):
+				colocated = append(colocated, change)
+				continue
+			case target != "new" && existing[target] == "":
+				usedMachine[target] = true
+			}
+			units = append(units, change)
+		}
+	}
+	// Units placed alongside other units must be added after the units
+	// they refer to.
+	for _, change := range units {
+		unitCounts[change.(*addUnitChange).service]++
+	}
+	for len(colocated) > 0 {
+		var remaining []*addUnitChange
+		for _, change := range colocated {
+			_, target := splitPlacement(change.placement)
+			service, index, err := parseUnitPlacement(target)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if index >= unitCounts[service] {
+				remaining = append(remaining, change)
+				continue
+			}
+			units = append(units, change)
+			unitCounts[change.service]++
+		}
+		if len(remaining) == len(colocated) {
+			return nil, errors.Errorf("cannot place unit of %q: placement %q refers to a unit that is never added", remaining[0].service, remaining[0].placement)
+		}
+		colocated = remaining
+	}
+	machineIds := make([]string, 0, len(usedMachine))
+	for id := range usedMachine {
+		machineIds = append(machineIds, id)
+	}
+	sort.Sort(byNumber(machineIds))
+	for _, id := range machineIds {
+		change := &addMachineChange{id: id}
+		if spec := data.Machines[id]; spec != nil {
+			change.series = spec.Series
+			change.constraints = spec.Constraints
+		}
+		machines = append(machines, change)
+	}
+	for _, relation := range data.Relations {
+		if relationExists(status, relation[0], relation[1]) {
+			continue
+		}
+		relations = append(relations, &addRelationChange{
+			endpoints: [2]string{relation[0], relation[1]},
+		})
+	}
+	changes = append(changes, charms...)
+	changes = append(changes, machines...)
+	changes = append(changes, deploys...)
+	changes = append(changes, units...)
+	changes = append(changes, relations...)
+	return changes, nil
+}
+
+// existingMachines returns a map from the ids of machines in the given
+// bundle to the ids of the machines in the environment they were
+// created as. The mapping is inferred from the placement of the units
+// of the bundle's services that already exist.
+func existingMachines(data *charm.BundleData, status *api.Status) map[string]string {
+	machines := make(map[string]string)
+	for _, name := range sortedServiceNames(data) {
+		spec := data.Services[name]
+		units := make([]string, 0, len(status.Services[name].Units))
+		for unit := range status.Services[name].Units {
+			units = append(units, unit)
+		}
+		sort.Sort(byUnitNumber(units))
+		for i, unit := range units {
+			if i >= len(spec.To) {
+				break
+			}
+			containerType, target := splitPlacement(spec.To[i])
+			if target == "new" || strings.Contains(target, "/") || machines[target] != "" {
+				continue
+			}
+			machine := status.Services[name].Units[unit].Machine
+			if containerType != "" {
+				// The unit is in a container; the bundle machine
+				// is its host.
+				machine = strings.SplitN(machine, "/", 2)[0]
+			}
+			if machine != "" {
+				machines[target] = machine
+			}
+		}
+	}
+	return machines
+}
+
+// sortedServiceNames returns the names of the services in the given
+// bundle in alphabetical order.
+func sortedServiceNames(data *charm.BundleData) []string {
+	names := make([]string, 0, len(data.Services))
+	for name := range data.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sameCharm reports whether a service using the existing charm may be
+// considered to be deployed using the wanted charm. The revision is
+// only significant if it is specified in the wanted URL.
+func sameCharm(existing, wanted *charm.URL) bool {
+	if wanted.Revision < 0 {
+		existing = existing.WithRevision(-1)
+	}
+	return *existing == *wanted
+}
+
+// splitPlacement splits a bundle placement directive into its container
+// type, if any, and the target it refers to.
+func splitPlacement(placement string) (containerType, target string) {
+	if i := strings.Index(placement, ":"); i >= 0 {
+		return placement[:i], placement[i+1:]
+	}
+	return "", placement
+}
+
+// parseUnitPlacement parses a placement target of the form
+// <service>/<index>.
+func parseUnitPlacement(target string) (string, int, error) {
+	parts := strings.SplitN(target, "/", 2)
+	index, err := strconv.Atoi(parts[1])
+	if err != nil || index < 0 {
+		return "", 0, errors.Errorf("invalid unit placement %q", target)
+	}
+	return parts[0], index, nil
+}
+
+// relationExists reports whether the two given endpoints are related
+// in the environment with the given status.
+func relationExists(status *api.Status, ep0, ep1 string) bool {
+	service0, relation0 := splitEndpoint(ep0)
+	service1, _ := splitEndpoint(ep1)
+	existing, ok := status.Services[service0]
+	if !ok {
+		return false
+	}
+	for name, related := range existing.Relations {
+		if relation0 != "" && name != relation0 {
+			continue
+		}
+		for _, service := range related {
+			if service == service1 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// splitEndpoint splits a bundle endpoint into its service and relation
+// names. The relation name may be empty.
+func splitEndpoint(ep string) (service, relation string) {
+	if i := strings.Index(ep, ":"); i >= 0 {
+		return ep[:i], ep[i+1:]
+	}
+	return ep, ""
+}
+
+// byNumber sorts machine ids numerically where possible.
+type byNumber []string
+
+func (b byNumber) Len() int      { return len(b) }
+func (b byNumber) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b byNumber) Less(i, j int) bool {
+	ni, erri := strconv.Atoi(b[i])
+	nj, errj := strconv.Atoi(b[j])
+	if erri == nil && errj == nil {
+		return ni < nj
+	}
+	return b[i] < b[j]
+}
+
+// bundleDeployer applies the changes needed to deploy a bundle.
+type bundleDeployer struct {
+	client   *api.Client
+	ctx      *cmd.Context
+	conf     *config.Config
+	repoPath string
+
+	// charms maps each charm URL in the bundle to the URL of the
+	// charm as added to the environment.
+	charms map[string]*charm.URL
+
+	// machines maps each machine id in the bundle to the id of the
+	// corresponding machine in the environment.
+	machines map[string]string
+
+	// units holds the names of the units of each service, in the
+	// order they were added.
+	units map[string][]string
+}
+
+// deployBundle deploys the given bundle, or only prints the changes
+// needed to deploy it if dryRun is true.
+func deployBundle(data *charm.BundleData, client *api.Client, ctx *cmd.Context, conf *config.Config, repoPath string, dryRun bool) error {
+	curls := make(map[string]*charm.URL)
+	for name, spec := range data.Services {
+		ref, err := charm.ParseReference(spec.Charm)
+		if err != nil {
+			return errors.Annotatef(err, "invalid charm for service %q", name)
+		}
+		if ref.Series == "" {
+			ref.Series = data.Series
+		}
+		curl, err := resolveCharmURL(ref.String(), client, conf)
+		if err != nil {
+			return errors.Annotatef(err, "cannot resolve charm for service %q", name)
+		}
+		curls[name] = curl
+	}
+	status, err := client.Status(nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	changes, err := bundleChanges(data, curls, status)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if dryRun {
+		if len(changes) == 0 {
+			fmt.Fprintln(ctx.Stdout, "No changes needed to deploy bundle.")
+			return nil
+		}
+		fmt.Fprintln(ctx.Stdout, "Changes to deploy bundle:")
+		for _, change := range changes {
+			fmt.Fprintf(ctx.Stdout, "- %s\n", change)
+		}
+		return nil
+	}
+
+	d := &bundleDeployer{
+		client:   client,
+		ctx:      ctx,
+		conf:     conf,
+		repoPath: repoPath,
+		charms:   make(map[string]*charm.URL),
+		machines: existingMachines(data, status),
+		units:    make(map[string][]string),
+	}
+	for name, service := range status.Services {
+		for unit := range service.Units {
+			d.units[name] = append(d.units[name], unit)
+		}
+		sort.Sort(byUnitNumber(d.units[name]))
+	}
+	for _, change := range changes {
+		ctx.Infof("%s", change)
+		if err := d.apply(change); err != nil {
+			return block.ProcessBlockedError(errors.Annotatef(err, "cannot %s", change), block.BlockChange)
+		}
+	}
+	ctx.Infof("Deploy of bundle completed.")
+	return nil
+}
+
+// apply applies a single change.
+func (d *bundleDeployer) apply(change bundleChange) error {
+	switch change := change.(type) {
+	case *addCharmChange:
+		repo, err := charm.InferRepository(change.curl.Reference(), d.ctx.AbsPath(d.repoPath))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		config.SpecializeCharmRepo(repo, d.conf)
+		curl, err := addCharmViaAPI(d.client, d.ctx, change.curl, repo)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		d.charms[change.curl.String()] = curl
+	case *addMachineChange:
+		cons, err := constraints.Parse(change.constraints)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		results, err := d.client.AddMachines([]params.AddMachineParams{{
+			Series:      change.series,
+			Constraints: cons,
+			Jobs:        []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+		}})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if results[0].Error != nil {
+			return results[0].Error
+		}
+		d.machines[change.id] = results[0].Machine
+	case *deployChange:
+		curl := d.charms[change.curl.String()]
+		if curl == nil {
+			curl = change.curl
+		}
+		var configYAML []byte
+		if len(change.options) > 0 {
+			var err error
+			configYAML, err = goyaml.Marshal(map[string]interface{}{change.service: change.options})
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+		cons, err := constraints.Parse(change.constraints)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return d.client.ServiceDeploy(curl.String(), change.service, 0, string(configYAML), cons, "")
+	case *addUnitChange:
+		machineSpec, err := d.machineSpec(change)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		units, err := d.client.AddServiceUnits(change.service, 1, machineSpec)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		d.units[change.service] = append(d.units[change.service], units...)
+	case *addRelationChange:
+		_, err := d.client.AddRelation(change.endpoints[0], change.endpoints[1])
+		return errors.Trace(err)
+	default:
+		return errors.Errorf("unknown change type %T", change)
+	}
+	return nil
+}
+
+// machineSpec returns the machine specification to use when adding the
+// unit of the given change, according to its bundle placement
+// directive.
+func (d *bundleDeployer) machineSpec(change *addUnitChange) (string, error) {
+	containerType, target := splitPlacement(change.placement)
+	var machine string
+	switch {
+	case target == "new":
+		if containerType == "" {
+			return "", nil
+		}
+		// A machine spec can only name a container on an existing
+		// machine, so the container and its new host are added
+		// first.
+		return d.addContainer(containerType, change.series)
+	case strings.Contains(target, "/"):
+		service, index, err := parseUnitPlacement(target)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		if index >= len(d.units[service]) {
+			return "", errors.Errorf("unit %q has not been added", target)
+		}
+		unit := d.units[service][index]
+		status, err := d.client.Status([]string{unit})
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		unitStatus, ok := status.Services[service].Units[unit]
+		if !ok || unitStatus.Machine == "" {
+			return "", errors.Errorf("unit %q is not assigned to a machine", unit)
+		}
+		machine = unitStatus.Machine
+	default:
+		var ok bool
+		if machine, ok = d.machines[target]; !ok {
+			return "", errors.Errorf("machine %q has not been added", target)
+		}
+	}
+	if containerType != "" {
+		return containerType + ":" + machine, nil
+	}
+	return machine, nil
+}
+
+// addContainer adds a new container of the given type and series on a
+// new machine, and returns the id of the container.
+func (d *bundleDeployer) addContainer(containerType, series string) (string, error) {
+	ctype, err := instance.ParseContainerType(containerType)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	results, err := d.client.AddMachines([]params.AddMachineParams{{
+		Series:        series,
+		ContainerType: ctype,
+		Jobs:          []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+	}})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if results[0].Error != nil {
+		return "", results[0].Error
+	}
+	return results[0].Machine, nil
+}
+
+// byUnitNumber sorts unit names of a single service by unit number.
+type byUnitNumber []string
+
+func (b byUnitNumber) Len() int      { return len(b) }
+func (b byUnitNumber) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b byUnitNumber) Less(i, j int) bool {
+	return unitNumber(b[i]) < unitNumber(b[j])
+}
+
+func unitNumber(unit string) int {
+	n, _ := strconv.Atoi(unit[strings.LastIndex(unit, "/")+1:])
+	return n
+}
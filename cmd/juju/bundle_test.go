@@ -0,0 +1,435 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v4"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/testcharms"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type BundleChangesSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&BundleChangesSuite{})
+
+const placementBundle = `
+services:
+    wordpress:
+        charm: wordpress
+        num_units: 2
+        to: ["1", "lxc:mysql/0"]
+    mysql:
+        charm: mysql
+        num_units: 1
+        to: ["lxc:1"]
+    logging:
+        charm: logging
+machines:
+    "1":
+        series: trusty
+relations:
+    - ["wordpress:db", "mysql:server"]
+    - ["wordpress:juju-info", "logging:info"]
+`
+
+func (s *BundleChangesSuite) changes(c *gc.C, bundle string, status *api.Status) ([]string, error) {
+	data, err := charm.ReadBundleData(strings.NewReader(bundle))
+	c.Assert(err, jc.ErrorIsNil)
+	curls := make(map[string]*charm.URL)
+	for name, spec := range data.Services {
+		curls[name] = charm.MustParseURL("cs:trusty/" + spec.Charm)
+	}
+	changes, err := bundleChanges(data, curls, status)
+	if err != nil {
+		return nil, err
+	}
+	descriptions := make([]string, len(changes))
+	for i, change := range changes {
+		descriptions[i] = change.String()
+	}
+	return descriptions, nil
+}
+
+func (s *BundleChangesSuite) TestEmptyEnvironment(c *gc.C) {
+	changes, err := s.changes(c, placementBundle, &api.Status{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes, jc.DeepEquals, []string{
+		"add charm cs:trusty/logging",
+		"add charm cs:trusty/mysql",
+		"add charm cs:trusty/wordpress",
+		"add new machine 1 with series trusty",
+		"deploy service logging using cs:trusty/logging",
+		"deploy service mysql using cs:trusty/mysql",
+		"deploy service wordpress using cs:trusty/wordpress",
+		"add unit of mysql to lxc:1",
+		"add unit of wordpress to 1",
+		"add unit of wordpress to lxc:mysql/0",
+		"add relation wordpress:db - mysql:server",
+		"add relation wordpress:juju-info - logging:info",
+	})
+}
+
+func (s *BundleChangesSuite) TestExistingServices(c *gc.C) {
+	status := &api.Status{
+		Services: map[string]api.ServiceStatus{
+			"mysql": {
+				Charm: "cs:trusty/mysql-3",
+				Units: map[string]api.UnitStatus{
+					"mysql/0": {Machine: "0"},
+				},
+				Relations: map[string][]string{
+					"server": {"wordpress"},
+				},
+			},
+			"wordpress": {
+				Charm: "cs:trusty/wordpress-1",
+				Units: map[string]api.UnitStatus{
+					"wordpress/0": {Machine: "1"},
+				},
+				Relations: map[string][]string{
+					"db": {"mysql"},
+				},
+			},
+		},
+	}
+	changes, err := s.changes(c, placementBundle, status)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes, jc.DeepEquals, []string{
+		"add charm cs:trusty/logging",
+		"deploy service logging using cs:trusty/logging",
+		"add unit of wordpress to lxc:mysql/0",
+		"add relation wordpress:juju-info - logging:info",
+	})
+}
+
+func (s *BundleChangesSuite) TestExistingMachines(c *gc.C) {
+	// The wordpress unit placed on bundle machine 1 already exists on
+	// machine 4, so the missing units placed on machine 1 go there
+	// rather than on a new machine.
+	status := &api.Status{
+		Services: map[string]api.ServiceStatus{
+			"wordpress": {
+				Charm: "cs:trusty/wordpress-1",
+				Units: map[string]api.UnitStatus{
+					"wordpress/0": {Machine: "4"},
+				},
+			},
+		},
+	}
+	changes, err := s.changes(c, `
+services:
+    wordpress:
+        charm: wordpress
+        num_units: 1
+        to: ["1"]
+    mysql:
+        charm: mysql
+        num_units: 1
+        to: ["lxc:1"]
+machines:
+    "1":
+        series: trusty
+`, status)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes, jc.DeepEquals, []string{
+		"add charm cs:trusty/mysql",
+		"deploy service mysql using cs:trusty/mysql",
+		"add unit of mysql to lxc:1",
+	})
+	c.Assert(existingMachines(mustReadBundle(c, placementBundle), status), jc.DeepEquals, map[string]string{
+		"1": "4",
+	})
+}
+
+func mustReadBundle(c *gc.C, bundle string) *charm.BundleData {
+	data, err := charm.ReadBundleData(strings.NewReader(bundle))
+	c.Assert(err, jc.ErrorIsNil)
+	return data
+}
+
+func (s *BundleChangesSuite) TestAlreadyDeployed(c *gc.C) {
+	status := &api.Status{
+		Services: map[string]api.ServiceStatus{
+			"mysql": {
+				Charm: "cs:trusty/mysql-3",
+				Units: map[string]api.UnitStatus{"mysql/0": {}},
+				Relations: map[string][]string{
+					"server": {"wordpress"},
+				},
+			},
+			"wordpress": {
+				Charm: "cs:trusty/wordpress-1",
+				Units: map[string]api.UnitStatus{"wordpress/0": {}},
+			},
+		},
+	}
+	changes, err := s.changes(c, `
+services:
+    wordpress:
+        charm: wordpress
+        num_units: 1
+    mysql:
+        charm: mysql
+        num_units: 1
+relations:
+    - ["mysql:server", "wordpress:db"]
+`, status)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes, gc.HasLen, 0)
+}
+
+func (s *BundleChangesSuite) TestServiceWithDifferentCharm(c *gc.C) {
+	status := &api.Status{
+		Services: map[string]api.ServiceStatus{
+			"mysql": {Charm: "cs:trusty/mysql-alternative-1"},
+		},
+	}
+	_, err := s.changes(c, placementBundle, status)
+	c.Assert(err, gc.ErrorMatches, `service "mysql" already exists with charm "cs:trusty/mysql-alternative-1"`)
+}
+
+func (s *BundleChangesSuite) TestUnplaceableUnit(c *gc.C) {
+	_, err := s.changes(c, `
+services:
+    wordpress:
+        charm: wordpress
+        num_units: 1
+        to: ["mysql/1"]
+    mysql:
+        charm: mysql
+        num_units: 1
+`, &api.Status{})
+	c.Assert(err, gc.ErrorMatches, `cannot place unit of "wordpress": placement "mysql/1" refers to a unit that is never added`)
+}
+
+type DeployBundleSuite struct {
+	testing.RepoSuite
+}
+
+var _ = gc.Suite(&DeployBundleSuite{})
+
+const localBundle = `
+services:
+    wordpress:
+        charm: local:wordpress
+        num_units: 1
+    mysql:
+        charm: local:mysql
+        num_units: 1
+relations:
+    - ["wordpress:db", "mysql:server"]
+`
+
+func (s *DeployBundleSuite) SetUpTest(c *gc.C) {
+	s.RepoSuite.SetUpTest(c)
+	testcharms.Repo.ClonedDirPath(s.SeriesPath, "wordpress")
+	testcharms.Repo.ClonedDirPath(s.SeriesPath, "mysql")
+}
+
+func (s *DeployBundleSuite) writeBundle(c *gc.C, dir, content string) string {
+	err := os.MkdirAll(dir, 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(filepath.Join(dir, bundleFileName), []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	return dir
+}
+
+func (s *DeployBundleSuite) assertDeployed(c *gc.C) {
+	s.AssertService(c, "wordpress", charm.MustParseURL("local:trusty/wordpress-3"), 1, 1)
+	s.AssertService(c, "mysql", charm.MustParseURL("local:trusty/mysql-1"), 1, 1)
+}
+
+func (s *DeployBundleSuite) TestDeployBundleDir(c *gc.C) {
+	dir := s.writeBundle(c, c.MkDir(), localBundle)
+	err := runDeploy(c, dir)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertDeployed(c)
+}
+
+func (s *DeployBundleSuite) TestDeployBundleFile(c *gc.C) {
+	dir := s.writeBundle(c, c.MkDir(), localBundle)
+	err := runDeploy(c, filepath.Join(dir, bundleFileName))
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertDeployed(c)
+}
+
+func (s *DeployBundleSuite) TestDeployLocalBundleURL(c *gc.C) {
+	s.writeBundle(c, filepath.Join(filepath.Dir(s.SeriesPath), "bundle", "wordpress-simple"), localBundle)
+	err := runDeploy(c, "local:bundle/wordpress-simple")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertDeployed(c)
+}
+
+func (s *DeployBundleSuite) setCharmStoreURL(c *gc.C, url string) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"charm-store-url": url}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *DeployBundleSuite) TestDeployStoreBundleURL(c *gc.C) {
+	var requested string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requested = req.URL.Path
+		fmt.Fprint(w, localBundle)
+	}))
+	defer server.Close()
+	s.setCharmStoreURL(c, server.URL)
+
+	err := runDeploy(c, "cs:~who/bundle/wordpress-simple-2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(requested, gc.Equals, "/v4/~who/bundle/wordpress-simple-2/archive/bundle.yaml")
+	s.assertDeployed(c)
+}
+
+func (s *DeployBundleSuite) TestDeployStoreBundleNotFound(c *gc.C) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	s.setCharmStoreURL(c, server.URL)
+
+	err := runDeploy(c, "cs:bundle/no-such-bundle")
+	c.Assert(err, gc.ErrorMatches, `cannot read bundle "cs:bundle/no-such-bundle": bundle cs:bundle/no-such-bundle not found`)
+}
+
+func (s *DeployBundleSuite) TestDeployBundleNewContainer(c *gc.C) {
+	err := runDeploy(c, testcharms.Repo.BundleDirPath("wordpress-lxc"))
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertDeployed(c)
+
+	// The unit is placed in a container on a new host machine.
+	unit, err := s.State.Unit("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId, gc.Matches, `\d+/lxc/0`)
+	machine, err := s.State.Machine(machineId)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.Series(), gc.Equals, "trusty")
+	hostId, ok := machine.ParentId()
+	c.Assert(ok, jc.IsTrue)
+	host, err := s.State.Machine(hostId)
+	c.Assert(err, jc.ErrorIsNil)
+	hostUnits, err := host.Units()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hostUnits, gc.HasLen, 0)
+}
+
+func (s *DeployBundleSuite) TestDeployBundleTwiceReusesMachines(c *gc.C) {
+	bundle := `
+services:
+    wordpress:
+        charm: local:wordpress
+        num_units: 1
+        to: ["1"]
+    mysql:
+        charm: local:mysql
+        num_units: %d
+        to: ["1", "1"]
+machines:
+    "1":
+        series: trusty
+`
+	dir := s.writeBundle(c, c.MkDir(), fmt.Sprintf(bundle, 1))
+	err := runDeploy(c, dir)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.State.Unit("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	machines, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	machineCount := len(machines)
+
+	// Adding a unit to the same bundle machine reuses the machine
+	// created by the first deploy.
+	s.writeBundle(c, dir, fmt.Sprintf(bundle, 2))
+	err = runDeploy(c, dir)
+	c.Assert(err, jc.ErrorIsNil)
+	machines, err = s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, machineCount)
+	unit, err = s.State.Unit("mysql/1")
+	c.Assert(err, jc.ErrorIsNil)
+	assigned, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(assigned, gc.Equals, machineId)
+}
+
+func (s *DeployBundleSuite) TestDeployBundleTwice(c *gc.C) {
+	dir := s.writeBundle(c, c.MkDir(), localBundle)
+	err := runDeploy(c, dir)
+	c.Assert(err, jc.ErrorIsNil)
+	err = runDeploy(c, dir)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertDeployed(c)
+
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&DeployCommand{}), dir, "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, "No changes needed to deploy bundle.\n")
+}
+
+func (s *DeployBundleSuite) TestDryRun(c *gc.C) {
+	dir := s.writeBundle(c, c.MkDir(), localBundle)
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&DeployCommand{}), dir, "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, `
+Changes to deploy bundle:
+- add charm local:trusty/mysql
+- add charm local:trusty/wordpress
+- deploy service mysql using local:trusty/mysql
+- deploy service wordpress using local:trusty/wordpress
+- add unit of mysql
+- add unit of wordpress
+- add relation wordpress:db - mysql:server
+`[1:])
+	services, err := s.State.AllServices()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(services, gc.HasLen, 0)
+}
+
+func (s *DeployBundleSuite) TestInvalidBundle(c *gc.C) {
+	dir := s.writeBundle(c, c.MkDir(), strings.Replace(localBundle, `"wordpress:db"`, `"foo:db"`, 1))
+	err := runDeploy(c, dir)
+	c.Assert(err, gc.ErrorMatches, fmt.Sprintf(`invalid bundle %q: .*foo.*`, dir))
+}
+
+func (s *DeployBundleSuite) TestBundleFlagErrors(c *gc.C) {
+	dir := s.writeBundle(c, c.MkDir(), localBundle)
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{dir, "myservice"},
+		err:  "cannot specify a service name when deploying a bundle",
+	}, {
+		args: []string{dir, "-n", "2"},
+		err:  "cannot use --num-units when deploying a bundle",
+	}, {
+		args: []string{dir, "--constraints", "mem=4G"},
+		err:  "cannot use --constraints when deploying a bundle",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := runDeploy(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *DeployBundleSuite) TestDryRunWithCharm(c *gc.C) {
+	err := runDeploy(c, "local:wordpress", "--dry-run")
+	c.Assert(err, gc.ErrorMatches, "--dry-run is only supported when deploying bundles")
+}
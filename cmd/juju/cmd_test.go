@@ -233,6 +233,13 @@ func (*CmdSuite) TestExposeCommandInit(c *gc.C) {
 	_, err := initExposeCommand()
 	c.Assert(err, gc.ErrorMatches, "no service name specified")
 
+	com, err := initExposeCommand("wordpress", "--from", "10.0.0.0/8,203.0.113.4/32")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(com.SourceCIDRs, jc.DeepEquals, []string{"10.0.0.0/8", "203.0.113.4/32"})
+
+	_, err = initExposeCommand("wordpress", "--from", "10.0.0.1")
+	c.Assert(err, gc.ErrorMatches, `source CIDR "10.0.0.1" not valid`)
+
 	// environment tested elsewhere
 }
 
@@ -21,6 +21,7 @@ import (
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/storage"
 )
 
 type DeployCommand struct {
@@ -31,8 +32,10 @@ type DeployCommand struct {
 	Config       cmd.FileVar
 	Constraints  constraints.Value
 	Networks     string
+	Storage      []storage.Directive
 	BumpRevision bool   // Remove this once the 1.16 support is dropped.
 	RepoPath     string // defaults to JUJU_REPOSITORY
+	DryRun       bool   // only valid when deploying bundles
 }
 
 const deployDoc = `
@@ -86,6 +89,11 @@ Examples:
    juju deploy mysql --to 23       (deploy to machine 23)
    juju deploy mysql --to 24/lxc/3 (deploy to lxc container 3 on host machine 24)
    juju deploy mysql --to lxc:25   (deploy to a new lxc container on host machine 25)
+   juju deploy mysql -n 3 --to zone=us-east-1a
+   (deploy 3 instances of mysql to new machines in zone us-east-1a)
+
+   juju deploy mysql -n 3 --constraints zones=us-east-1a,us-east-1b
+   (deploy 3 instances of mysql spread across zones us-east-1a and us-east-1b)
 
    juju deploy mysql -n 5 --constraints mem=8G
    (deploy 5 instances of mysql with at least 8 GB of RAM each)
@@ -103,6 +111,31 @@ to configure on it. The --networks argument instructs juju to add all the
 networks specified with it to all new machines deployed to host units of
 the service. Not supported on all providers.
 
+Storage can be requested for each unit of the service with the --storage
+argument, which may be given multiple times. Each value takes the form
+
+   <name>=<source>:[<count>x]<size>[,<options>]
+
+where size is given in MiB, or with one of the suffixes M, G, T or P. The
+"loop" source creates loop devices backed by files on the units' machines,
+which is useful for testing storage locally:
+
+   juju deploy postgresql --storage data=loop:2x1G
+
+Use "juju storage list" to see the storage instances created for units.
+
+Bundles, which describe a set of services along with their units and the
+relations between them, can also be deployed. A bundle is given either as
+a path to a bundle directory or bundle.yaml file, or as a local bundle URL
+(for example local:bundle/wordpress-simple) found in the local repository.
+Services, units and relations in the bundle that already exist in the
+environment are left alone, so a bundle may safely be deployed again. Use
+--dry-run to show the changes that would be made without applying them.
+
+Examples:
+   juju deploy ./wordpress-simple
+   juju deploy local:bundle/wordpress-simple --dry-run
+
 See Also:
    juju help constraints
    juju help set-constraints
@@ -126,7 +159,9 @@ func (c *DeployCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(&c.Config, "config", "path to yaml-formatted service config")
 	f.Var(constraints.ConstraintsValue{Target: &c.Constraints}, "constraints", "set service constraints")
 	f.StringVar(&c.Networks, "networks", "", "bind the service to specific networks")
+	f.Var(storageFlag{&c.Storage}, "storage", "create storage for each unit of the service")
 	f.StringVar(&c.RepoPath, "repository", os.Getenv(osenv.JujuRepositoryEnvKey), "local charm repository")
+	f.BoolVar(&c.DryRun, "dry-run", false, "show the changes needed to deploy a bundle without applying them")
 }
 
 func (c *DeployCommand) Init(args []string) error {
@@ -138,7 +173,7 @@ func (c *DeployCommand) Init(args []string) error {
 		c.ServiceName = args[1]
 		fallthrough
 	case 1:
-		if _, err := charm.InferURL(args[0], "fake"); err != nil {
+		if _, err := charm.InferURL(args[0], "fake"); err != nil && !looksLikeBundlePath(args[0]) {
 			return fmt.Errorf("invalid charm name %q", args[0])
 		}
 		c.CharmName = args[0]
@@ -166,6 +201,20 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 		return err
 	}
 
+	bundleData, err := readBundle(ctx, c.CharmName, c.RepoPath, conf)
+	if err != nil {
+		return err
+	}
+	if bundleData != nil {
+		if err := c.checkBundleFlags(); err != nil {
+			return err
+		}
+		return deployBundle(bundleData, client, ctx, conf, c.RepoPath, c.DryRun)
+	}
+	if c.DryRun {
+		return errors.New("--dry-run is only supported when deploying bundles")
+	}
+
 	curl, err := resolveCharmURL(c.CharmName, client, conf)
 	if err != nil {
 		return err
@@ -230,6 +279,22 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 			return err
 		}
 	}
+	if len(c.Storage) > 0 {
+		err = client.ServiceDeployWithStorage(
+			curl.String(),
+			serviceName,
+			numUnits,
+			string(configYAML),
+			c.Constraints,
+			c.ToMachineSpec,
+			requestedNetworks,
+			c.Storage,
+		)
+		if params.IsCodeNotImplemented(err) {
+			return errors.New("cannot use --storage: not supported by the API server")
+		}
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
 	err = client.ServiceDeployWithNetworks(
 		curl.String(),
 		serviceName,
@@ -254,6 +319,28 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 	return block.ProcessBlockedError(err, block.BlockChange)
 }
 
+// checkBundleFlags returns an error if any flags that only apply to
+// deploying a single charm were specified.
+func (c *DeployCommand) checkBundleFlags() error {
+	switch {
+	case c.ServiceName != "":
+		return errors.New("cannot specify a service name when deploying a bundle")
+	case c.NumUnits != 1:
+		return errors.New("cannot use --num-units when deploying a bundle")
+	case c.ToMachineSpec != "":
+		return errors.New("cannot use --to when deploying a bundle")
+	case c.Config.Path != "":
+		return errors.New("cannot use --config when deploying a bundle")
+	case !constraints.IsEmpty(&c.Constraints):
+		return errors.New("cannot use --constraints when deploying a bundle")
+	case c.Networks != "":
+		return errors.New("cannot use --networks when deploying a bundle")
+	case len(c.Storage) > 0:
+		return errors.New("cannot use --storage when deploying a bundle")
+	}
+	return nil
+}
+
 // addCharmViaAPI calls the appropriate client API calls to add the
 // given charm URL to state. Also displays the charm URL of the added
 // charm on stdout.
@@ -302,6 +389,39 @@ func parseNetworks(networksValue string) []string {
 	return networks
 }
 
+// storageFlag is a gnuflag.Value that parses storage directives,
+// accumulating them over multiple uses of the flag.
+type storageFlag struct {
+	directives *[]storage.Directive
+}
+
+// Set implements gnuflag.Value.Set.
+func (f storageFlag) Set(value string) error {
+	directive, err := storage.ParseDirective(value)
+	if err != nil {
+		return err
+	}
+	if directive.Count == 0 {
+		return fmt.Errorf("storage %q: size must be specified", directive.Name)
+	}
+	for _, existing := range *f.directives {
+		if existing.Name == directive.Name {
+			return fmt.Errorf("storage %q specified more than once", directive.Name)
+		}
+	}
+	*f.directives = append(*f.directives, *directive)
+	return nil
+}
+
+// String implements gnuflag.Value.String.
+func (f storageFlag) String() string {
+	values := make([]string, len(*f.directives))
+	for i, directive := range *f.directives {
+		values[i] = fmt.Sprintf("%s=%s:%dx%dM", directive.Name, directive.Source, directive.Count, directive.Size)
+	}
+	return strings.Join(values, " ")
+}
+
 // networkNamesToTags returns the given network names converted to
 // tags, or an error.
 func networkNamesToTags(networks []string) ([]string, error) {
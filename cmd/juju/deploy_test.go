@@ -20,6 +20,7 @@ import (
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/storage"
 	"github.com/juju/juju/testcharms"
 	coretesting "github.com/juju/juju/testing"
 )
@@ -60,9 +61,21 @@ var initErrorTests = []struct {
 	}, {
 		args: []string{"craziness", "burble1", "-n", "2", "--to", "123"},
 		err:  `cannot use --num-units > 1 with --to`,
+	}, {
+		args: []string{"craziness", "burble1", "--to", "zone="},
+		err:  `invalid --to parameter "zone="`,
 	}, {
 		args: []string{"craziness", "burble1", "--constraints", "gibber=plop"},
 		err:  `invalid value "gibber=plop" for flag --constraints: unknown constraint "gibber"`,
+	}, {
+		args: []string{"craziness", "burble1", "--storage", "data"},
+		err:  `invalid value "data" for flag --storage: storage name missing`,
+	}, {
+		args: []string{"craziness", "burble1", "--storage", "data=loop:"},
+		err:  `invalid value "data=loop:" for flag --storage: storage "data": size must be specified`,
+	}, {
+		args: []string{"craziness", "burble1", "--storage", "data=loop:1G", "--storage", "data=loop:2G"},
+		err:  `invalid value "data=loop:2G" for flag --storage: storage "data" specified more than once`,
 	},
 }
 
@@ -200,6 +213,25 @@ func (s *DeploySuite) TestNetworks(c *gc.C) {
 	c.Assert(cons, jc.DeepEquals, constraints.MustParse("mem=2G cpu-cores=2 networks=net1,net0,^net3,^net4"))
 }
 
+func (s *DeploySuite) TestStorage(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
+	err := runDeploy(c, "local:dummy", "-n", "2", "--storage", "data=loop:2x1G", "--storage", "logs=loop:512M")
+	c.Assert(err, jc.ErrorIsNil)
+	curl := charm.MustParseURL("local:trusty/dummy-1")
+	service, _ := s.AssertService(c, "dummy", curl, 2, 0)
+	c.Assert(service.StorageDirectives(), jc.DeepEquals, []storage.Directive{
+		{Name: "data", Source: "loop", Count: 2, Size: 1024},
+		{Name: "logs", Source: "loop", Count: 1, Size: 512},
+	})
+	units, err := service.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	for _, unit := range units {
+		instances, err := unit.StorageInstances()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(instances, gc.HasLen, 3)
+	}
+}
+
 func (s *DeploySuite) TestSubordinateConstraints(c *gc.C) {
 	testcharms.Repo.CharmArchivePath(s.SeriesPath, "logging")
 	err := runDeploy(c, "local:logging", "--constraints", "mem=1G")
@@ -0,0 +1,178 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environment
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils/keyvalues"
+	goyaml "gopkg.in/yaml.v1"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/environs/configstore"
+)
+
+const createCommandDoc = `
+Create a new environment hosted by the state server of the current
+environment.
+
+The new environment takes generic settings such as the provider type,
+CA certificate, ports, authorized keys and agent version from the state
+server's environment. Credentials and any other provider settings are not
+inherited and must be given in a YAML file with --config or as key=value
+pairs on the command line. The provider type, CA certificate and ports
+cannot be changed.
+
+An environment file (.jenv) for the new environment, using the current
+credentials, is written to the Juju home directory so that the new
+environment can be used with the -e flag or "juju switch".
+
+Examples:
+  # Create environment "staging", owned by the current user, with the
+  # provider settings in staging.yaml.
+  juju environment create staging --config staging.yaml
+
+  # Create environment "test" owned by user "alex", with its own
+  # authorized keys.
+  juju environment create test --owner alex authorized-keys="ssh-rsa ..."
+
+See Also:
+  juju environment list
+  juju environment destroy
+`
+
+// CreateCommand creates a new environment hosted by the state server.
+type CreateCommand struct {
+	EnvironmentCommandBase
+	Name       string
+	Owner      string
+	ConfigFile cmd.FileVar
+	Values     map[string]string
+}
+
+// Info implements Command.Info.
+func (c *CreateCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "create",
+		Args:    "<name> [key=value ...]",
+		Purpose: "creates a new hosted environment",
+		Doc:     createCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *CreateCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.Owner, "owner", "", "the user that will own the new environment")
+	f.Var(&c.ConfigFile, "config", "path to a YAML file with the new environment's config")
+}
+
+// Init implements Command.Init.
+func (c *CreateCommand) Init(args []string) (err error) {
+	if len(args) == 0 {
+		return errors.New("environment name not specified")
+	}
+	c.Name, args = args[0], args[1:]
+	if c.Owner != "" && !names.IsValidUser(c.Owner) {
+		return errors.Errorf("%q is not a valid user", c.Owner)
+	}
+	c.Values, err = keyvalues.Parse(args, true)
+	return err
+}
+
+// CreateEnvironmentAPI defines the environmentmanager API methods that the
+// create command uses.
+type CreateEnvironmentAPI interface {
+	CreateEnvironment(owner names.UserTag, config map[string]interface{}) (params.Environment, error)
+	Close() error
+}
+
+func (c *CreateCommand) getAPI() (CreateEnvironmentAPI, error) {
+	return c.NewEnvironmentManagerClient()
+}
+
+var getCreateEnvironmentAPI = (*CreateCommand).getAPI
+
+// Run implements Command.Run.
+func (c *CreateCommand) Run(ctx *cmd.Context) error {
+	store, err := configstore.Default()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Check the name is free before creating anything.
+	if _, err := store.ReadInfo(c.Name); err == nil {
+		return errors.Errorf("environment %q already exists", c.Name)
+	} else if !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+
+	attrs, err := c.config(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	creds, err := c.ConnectionCredentials()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	owner := creds.User
+	if c.Owner != "" {
+		owner = c.Owner
+	}
+	ownerTag := names.NewUserTag(owner)
+
+	client, err := getCreateEnvironmentAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	env, err := client.CreateEnvironment(ownerTag, attrs)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+
+	endpoint, err := c.ConnectionEndpoint(false)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	info := store.CreateInfo(c.Name)
+	info.SetAPIEndpoint(configstore.APIEndpoint{
+		Addresses:   endpoint.Addresses,
+		CACert:      endpoint.CACert,
+		EnvironUUID: env.UUID,
+	})
+	info.SetAPICredentials(creds)
+	if err := info.Write(); err != nil {
+		return errors.Annotatef(err, "cannot write environment info for %q", c.Name)
+	}
+	fmt.Fprintf(ctx.Stdout, "created environment %q (%s)\n", env.Name, env.UUID)
+	return nil
+}
+
+// config returns the attributes of the new environment given on the
+// command line.
+func (c *CreateCommand) config(ctx *cmd.Context) (map[string]interface{}, error) {
+	attrs := make(map[string]interface{})
+	if c.ConfigFile.Path != "" {
+		data, err := c.ConfigFile.Read(ctx)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot read config file")
+		}
+		if err := goyaml.Unmarshal(data, &attrs); err != nil {
+			return nil, errors.Annotate(err, "cannot parse config file")
+		}
+	}
+	for key, value := range c.Values {
+		attrs[key] = value
+	}
+	if name, ok := attrs["name"]; ok && name != c.Name {
+		return nil, errors.Errorf("config name %q does not match environment name %q", name, c.Name)
+	}
+	attrs["name"] = c.Name
+	return attrs, nil
+}
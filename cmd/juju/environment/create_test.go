@@ -0,0 +1,156 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environment_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/environment"
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/testing"
+)
+
+type CreateSuite struct {
+	BaseSuite
+	fake *fakeEnvManagerAPI
+}
+
+var _ = gc.Suite(&CreateSuite{})
+
+func (s *CreateSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.fake = &fakeEnvManagerAPI{}
+	s.PatchValue(environment.GetCreateEnvironmentAPI, func(*environment.CreateCommand) (environment.CreateEnvironmentAPI, error) {
+		return s.fake, nil
+	})
+}
+
+func (s *CreateSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&environment.CreateCommand{}), args...)
+}
+
+func (s *CreateSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args   []string
+		name   string
+		owner  string
+		values map[string]string
+		err    string
+	}{{
+		err: "environment name not specified",
+	}, {
+		args:   []string{"new-env"},
+		name:   "new-env",
+		values: map[string]string{},
+	}, {
+		args:   []string{"new-env", "--owner", "alex", "default-series=trusty"},
+		name:   "new-env",
+		owner:  "alex",
+		values: map[string]string{"default-series": "trusty"},
+	}, {
+		args: []string{"new-env", "--owner", "not/valid"},
+		err:  `"not/valid" is not a valid user`,
+	}, {
+		args: []string{"new-env", "no-value"},
+		err:  `expected "key=value", got "no-value"`,
+	}} {
+		c.Logf("test %d", i)
+		command := &environment.CreateCommand{}
+		err := testing.InitCommand(command, test.args)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(command.Name, gc.Equals, test.name)
+		c.Check(command.Owner, gc.Equals, test.owner)
+		c.Check(command.Values, jc.DeepEquals, test.values)
+	}
+}
+
+func (s *CreateSuite) TestCreate(c *gc.C) {
+	ctx, err := s.run(c, "new-env", "default-series=trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "created environment \"new-env\" (new-uuid)\n")
+	c.Assert(s.fake.owner, gc.Equals, names.NewUserTag("user-test"))
+	c.Assert(s.fake.attrs, jc.DeepEquals, map[string]interface{}{
+		"name":           "new-env",
+		"default-series": "trusty",
+	})
+
+	// The new environment can be reached through the same API servers
+	// with the same credentials.
+	info, err := s.store.ReadInfo("new-env")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.APIEndpoint(), jc.DeepEquals, configstore.APIEndpoint{
+		Addresses:   []string{"localhost:12345"},
+		CACert:      testing.CACert,
+		EnvironUUID: "new-uuid",
+	})
+	c.Assert(info.APICredentials(), jc.DeepEquals, configstore.APICredentials{
+		User:     "user-test",
+		Password: "password",
+	})
+}
+
+func (s *CreateSuite) TestCreateWithOwner(c *gc.C) {
+	_, err := s.run(c, "new-env", "--owner", "alex")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.owner, gc.Equals, names.NewUserTag("alex"))
+}
+
+func (s *CreateSuite) TestCreateConfigFile(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "config.yaml")
+	err := ioutil.WriteFile(path, []byte("default-series: precise\nlogging-config: <root>=DEBUG\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.run(c, "new-env", "--config", path, "default-series=trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.attrs, jc.DeepEquals, map[string]interface{}{
+		"name":           "new-env",
+		"default-series": "trusty",
+		"logging-config": "<root>=DEBUG",
+	})
+}
+
+func (s *CreateSuite) TestCreateNameMismatch(c *gc.C) {
+	_, err := s.run(c, "new-env", "name=other")
+	c.Assert(err, gc.ErrorMatches, `config name "other" does not match environment name "new-env"`)
+	c.Assert(s.fake.attrs, gc.IsNil)
+}
+
+func (s *CreateSuite) TestCreateExistingName(c *gc.C) {
+	_, err := s.run(c, "testing")
+	c.Assert(err, gc.ErrorMatches, `environment "testing" already exists`)
+	c.Assert(s.fake.attrs, gc.IsNil)
+}
+
+func (s *CreateSuite) TestCreateError(c *gc.C) {
+	s.fake.err = errors.New("bad environment")
+	_, err := s.run(c, "new-env")
+	c.Assert(err, gc.ErrorMatches, "bad environment")
+	_, err = s.store.ReadInfo("new-env")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CreateSuite) TestCreateBlocked(c *gc.C) {
+	s.fake.err = &params.Error{
+		Code:    params.CodeOperationBlocked,
+		Message: "The operation has been blocked.",
+	}
+	_, err := s.run(c, "new-env")
+	c.Assert(err, gc.ErrorMatches, cmd.ErrSilent.Error())
+	stripped := strings.Replace(c.GetTestLog(), "\n", "", -1)
+	c.Check(stripped, gc.Matches, ".*To unblock changes.*")
+}
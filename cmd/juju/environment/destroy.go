@@ -0,0 +1,167 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environment
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/juju"
+)
+
+const destroyCommandDoc = `
+Destroy an environment hosted by the state server of the current
+environment. All the machines of the hosted environment are terminated,
+and its environment file (.jenv) is removed.
+
+The state server's own environment cannot be destroyed with this command;
+use "juju destroy-environment" instead.
+
+See Also:
+   juju environment list
+   juju destroy-environment
+`
+
+const destroyEnvMsg = `
+WARNING! this command will destroy the %q environment
+hosted by its state server.
+This includes all machines, services, data and other resources.
+
+Continue [y/N]? `[1:]
+
+// DestroyCommand destroys an environment hosted by the state server.
+type DestroyCommand struct {
+	EnvironmentCommandBase
+	Name      string
+	assumeYes bool
+}
+
+// Info implements Command.Info.
+func (c *DestroyCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "destroy",
+		Args:    "<name>",
+		Purpose: "destroys a hosted environment",
+		Doc:     destroyCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *DestroyCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.assumeYes, "y", false, "do not ask for confirmation")
+	f.BoolVar(&c.assumeYes, "yes", false, "")
+}
+
+// Init implements Command.Init.
+func (c *DestroyCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("environment name not specified")
+	}
+	c.Name, args = args[0], args[1:]
+	return cmd.CheckEmpty(args)
+}
+
+// DestroyEnvironmentAPI defines the API methods of the hosted environment
+// that the destroy command uses.
+type DestroyEnvironmentAPI interface {
+	DestroyEnvironment() error
+	Close() error
+}
+
+func (c *DestroyCommand) getListAPI() (ListEnvironmentsAPI, error) {
+	return c.NewEnvironmentManagerClient()
+}
+
+func (c *DestroyCommand) getDestroyAPI() (DestroyEnvironmentAPI, error) {
+	return juju.NewAPIClientFromName(c.Name)
+}
+
+var (
+	getDestroyListAPI        = (*DestroyCommand).getListAPI
+	getDestroyEnvironmentAPI = (*DestroyCommand).getDestroyAPI
+)
+
+// Run implements Command.Run.
+func (c *DestroyCommand) Run(ctx *cmd.Context) error {
+	store, err := configstore.Default()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	info, err := store.ReadInfo(c.Name)
+	if err != nil {
+		return errors.Annotatef(err, "cannot read environment info for %q", c.Name)
+	}
+	uuid := info.APIEndpoint().EnvironUUID
+
+	env, err := c.hostedEnvironment(uuid)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if env.UUID == env.ServerUUID {
+		return errors.Errorf("%q is a state server environment; use juju destroy-environment", c.Name)
+	}
+
+	if !c.assumeYes {
+		fmt.Fprintf(ctx.Stdout, destroyEnvMsg, c.Name)
+		scanner := bufio.NewScanner(ctx.Stdin)
+		scanner.Scan()
+		if err := scanner.Err(); err != nil && err != io.EOF {
+			return errors.Annotate(err, "environment destruction aborted")
+		}
+		answer := strings.ToLower(scanner.Text())
+		if answer != "y" && answer != "yes" {
+			return errors.New("environment destruction aborted")
+		}
+	}
+
+	client, err := getDestroyEnvironmentAPI(c)
+	if err != nil {
+		return errors.Annotate(err, "cannot connect to API")
+	}
+	defer client.Close()
+	if err := client.DestroyEnvironment(); err != nil {
+		return block.ProcessBlockedError(err, block.BlockDestroy)
+	}
+	if err := info.Destroy(); err != nil {
+		return errors.Annotatef(err, "cannot remove environment info for %q", c.Name)
+	}
+	fmt.Fprintf(ctx.Stdout, "environment %q destroyed\n", c.Name)
+	return nil
+}
+
+// hostedEnvironment returns the details, as known to the state server of
+// the current environment, of the environment with the given UUID.
+func (c *DestroyCommand) hostedEnvironment(uuid string) (params.Environment, error) {
+	var result params.Environment
+	creds, err := c.ConnectionCredentials()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	client, err := getDestroyListAPI(c)
+	if err != nil {
+		return result, err
+	}
+	defer client.Close()
+
+	envs, err := client.ListEnvironments(names.NewUserTag(creds.User))
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for _, env := range envs {
+		if env.UUID == uuid {
+			return env, nil
+		}
+	}
+	return result, errors.NotFoundf("environment %q", c.Name)
+}
@@ -0,0 +1,137 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environment_test
+
+import (
+	"bytes"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/environment"
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/testing"
+)
+
+type DestroySuite struct {
+	BaseSuite
+	fake    *fakeEnvManagerAPI
+	destroy *fakeDestroyAPI
+}
+
+var _ = gc.Suite(&DestroySuite{})
+
+type fakeDestroyAPI struct {
+	destroyed bool
+	err       error
+}
+
+func (f *fakeDestroyAPI) DestroyEnvironment() error {
+	f.destroyed = true
+	return f.err
+}
+
+func (f *fakeDestroyAPI) Close() error {
+	return nil
+}
+
+func (s *DestroySuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.fake = &fakeEnvManagerAPI{
+		envs: []params.Environment{{
+			Name:       "testing",
+			UUID:       "server-uuid",
+			ServerUUID: "server-uuid",
+		}, {
+			Name:       "staging",
+			UUID:       "staging-uuid",
+			ServerUUID: "server-uuid",
+		}},
+	}
+	s.destroy = &fakeDestroyAPI{}
+	s.PatchValue(environment.GetDestroyListAPI, func(*environment.DestroyCommand) (environment.ListEnvironmentsAPI, error) {
+		return s.fake, nil
+	})
+	s.PatchValue(environment.GetDestroyEnvironmentAPI, func(*environment.DestroyCommand) (environment.DestroyEnvironmentAPI, error) {
+		return s.destroy, nil
+	})
+
+	info := s.store.CreateInfo("staging")
+	info.SetAPIEndpoint(configstore.APIEndpoint{
+		Addresses:   []string{"localhost:12345"},
+		CACert:      testing.CACert,
+		EnvironUUID: "staging-uuid",
+	})
+	err := info.Write()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *DestroySuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&environment.DestroyCommand{}), args...)
+}
+
+func (s *DestroySuite) TestInit(c *gc.C) {
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "environment name not specified")
+	_, err = s.run(c, "staging", "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *DestroySuite) TestDestroy(c *gc.C) {
+	ctx, err := s.run(c, "staging", "-y")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.destroy.destroyed, jc.IsTrue)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "environment \"staging\" destroyed\n")
+	_, err = s.store.ReadInfo("staging")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *DestroySuite) TestDestroyConfirmed(c *gc.C) {
+	ctx := testing.Context(c)
+	ctx.Stdin = bytes.NewBufferString("y\n")
+	command := envcmd.Wrap(&environment.DestroyCommand{})
+	err := testing.InitCommand(command, []string{"staging"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = command.Run(ctx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.destroy.destroyed, jc.IsTrue)
+}
+
+func (s *DestroySuite) TestDestroyAborted(c *gc.C) {
+	ctx := testing.Context(c)
+	ctx.Stdin = bytes.NewBufferString("n\n")
+	command := envcmd.Wrap(&environment.DestroyCommand{})
+	err := testing.InitCommand(command, []string{"staging"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = command.Run(ctx)
+	c.Assert(err, gc.ErrorMatches, "environment destruction aborted")
+	c.Assert(s.destroy.destroyed, jc.IsFalse)
+	_, err = s.store.ReadInfo("staging")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *DestroySuite) TestDestroyStateServerEnvironment(c *gc.C) {
+	_, err := s.run(c, "testing", "-y")
+	c.Assert(err, gc.ErrorMatches, `"testing" is a state server environment; use juju destroy-environment`)
+	c.Assert(s.destroy.destroyed, jc.IsFalse)
+}
+
+func (s *DestroySuite) TestDestroyUnknownEnvironment(c *gc.C) {
+	s.fake.envs = s.fake.envs[:1]
+	_, err := s.run(c, "staging", "-y")
+	c.Assert(err, gc.ErrorMatches, `environment "staging" not found`)
+	c.Assert(s.destroy.destroyed, jc.IsFalse)
+}
+
+func (s *DestroySuite) TestDestroyError(c *gc.C) {
+	s.destroy.err = errors.New("destroy failed")
+	_, err := s.run(c, "staging", "-y")
+	c.Assert(err, gc.ErrorMatches, "destroy failed")
+	_, err = s.store.ReadInfo("staging")
+	c.Assert(err, jc.ErrorIsNil)
+}
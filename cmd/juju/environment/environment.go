@@ -0,0 +1,52 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environment
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/api/environmentmanager"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+var logger = loggo.GetLogger("juju.cmd.juju.environment")
+
+const environmentCommandDoc = `
+"juju environment" is used to manage the environments hosted by the
+state server of the current Juju environment.
+`
+
+const environmentCommandPurpose = "manage hosted environments"
+
+// NewSuperCommand creates the environment supercommand and registers the
+// subcommands that it supports.
+func NewSuperCommand() cmd.Command {
+	environmentcmd := cmd.NewSuperCommand(cmd.SuperCommandParams{
+		Name:        "environment",
+		Doc:         environmentCommandDoc,
+		UsagePrefix: "juju",
+		Purpose:     environmentCommandPurpose,
+	})
+	environmentcmd.Register(envcmd.Wrap(&CreateCommand{}))
+	environmentcmd.Register(envcmd.Wrap(&DestroyCommand{}))
+	environmentcmd.Register(envcmd.Wrap(&ListCommand{}))
+	return environmentcmd
+}
+
+// EnvironmentCommandBase is a helper base structure that has a method to
+// get the environment manager client.
+type EnvironmentCommandBase struct {
+	envcmd.EnvCommandBase
+}
+
+// NewEnvironmentManagerClient returns an environmentmanager client for the
+// root api endpoint that the environment command returns.
+func (c *EnvironmentCommandBase) NewEnvironmentManagerClient() (*environmentmanager.Client, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	return environmentmanager.NewClient(root), nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environment_test
+
+import (
+	"os"
+	"strings"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/environment"
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/testing"
+)
+
+type EnvironmentCommandSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&EnvironmentCommandSuite{})
+
+var expectedEnvironmentCommmandNames = []string{
+	"create",
+	"destroy",
+	"help",
+	"list",
+}
+
+func (s *EnvironmentCommandSuite) TestHelp(c *gc.C) {
+	// Check the help output
+	ctx, err := testing.RunCommand(c, environment.NewSuperCommand(), "--help")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Check that we have registered all the sub commands by
+	// inspecting the help output.
+	var namesFound []string
+	commandHelp := strings.SplitAfter(testing.Stdout(ctx), "commands:")[1]
+	commandHelp = strings.TrimSpace(commandHelp)
+	for _, line := range strings.Split(commandHelp, "\n") {
+		namesFound = append(namesFound, strings.TrimSpace(strings.Split(line, " - ")[0]))
+	}
+	c.Assert(namesFound, gc.DeepEquals, expectedEnvironmentCommmandNames)
+}
+
+type BaseSuite struct {
+	testing.BaseSuite
+	store configstore.Storage
+}
+
+func (s *BaseSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.store = configstore.NewMem()
+	s.PatchValue(&configstore.Default, func() (configstore.Storage, error) {
+		return s.store, nil
+	})
+	os.Setenv(osenv.JujuEnvEnvKey, "testing")
+	info := s.store.CreateInfo("testing")
+	info.SetBootstrapConfig(map[string]interface{}{"random": "extra data"})
+	info.SetAPIEndpoint(configstore.APIEndpoint{
+		Addresses:   []string{"localhost:12345"},
+		CACert:      testing.CACert,
+		EnvironUUID: "server-uuid",
+	})
+	info.SetAPICredentials(configstore.APICredentials{
+		User:     "user-test",
+		Password: "password",
+	})
+	err := info.Write()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+// fakeEnvManagerAPI is a fake of the environmentmanager API, holding the
+// environments of a single state server.
+type fakeEnvManagerAPI struct {
+	envs  []params.Environment
+	err   error
+	owner names.UserTag
+	attrs map[string]interface{}
+	user  names.UserTag
+}
+
+func (f *fakeEnvManagerAPI) CreateEnvironment(owner names.UserTag, attrs map[string]interface{}) (params.Environment, error) {
+	f.owner = owner
+	f.attrs = attrs
+	if f.err != nil {
+		return params.Environment{}, f.err
+	}
+	env := params.Environment{
+		Name:       attrs["name"].(string),
+		UUID:       "new-uuid",
+		OwnerTag:   owner.String(),
+		ServerUUID: "server-uuid",
+		Life:       "alive",
+	}
+	f.envs = append(f.envs, env)
+	return env, nil
+}
+
+func (f *fakeEnvManagerAPI) ListEnvironments(user names.UserTag) ([]params.Environment, error) {
+	f.user = user
+	return f.envs, f.err
+}
+
+func (f *fakeEnvManagerAPI) Close() error {
+	return nil
+}
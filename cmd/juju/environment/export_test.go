@@ -0,0 +1,11 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environment
+
+var (
+	GetCreateEnvironmentAPI  = &getCreateEnvironmentAPI
+	GetListEnvironmentsAPI   = &getListEnvironmentsAPI
+	GetDestroyListAPI        = &getDestroyListAPI
+	GetDestroyEnvironmentAPI = &getDestroyEnvironmentAPI
+)
@@ -0,0 +1,141 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environment
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+const listCommandDoc = `
+List the environments hosted by the state server that the user has
+access to.
+
+See Also:
+   juju environment create
+`
+
+// ListCommand shows the environments a user has access to.
+type ListCommand struct {
+	EnvironmentCommandBase
+	User string
+	out  cmd.Output
+}
+
+// EnvironmentInfo holds the information on an environment shown by the
+// list command.
+type EnvironmentInfo struct {
+	Name  string `yaml:"name" json:"name"`
+	UUID  string `yaml:"uuid" json:"uuid"`
+	Owner string `yaml:"owner" json:"owner"`
+	Life  string `yaml:"life" json:"life"`
+}
+
+// Info implements Command.Info.
+func (c *ListCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Purpose: "shows the environments the user has access to",
+		Doc:     listCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *ListCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.User, "user", "", "the user to list environments for (defaults to the current user)")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *ListCommand) Init(args []string) error {
+	if c.User != "" && !names.IsValidUser(c.User) {
+		return errors.Errorf("%q is not a valid user", c.User)
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// ListEnvironmentsAPI defines the environmentmanager API methods that the
+// list command uses.
+type ListEnvironmentsAPI interface {
+	ListEnvironments(user names.UserTag) ([]params.Environment, error)
+	Close() error
+}
+
+func (c *ListCommand) getAPI() (ListEnvironmentsAPI, error) {
+	return c.NewEnvironmentManagerClient()
+}
+
+var getListEnvironmentsAPI = (*ListCommand).getAPI
+
+// Run implements Command.Run.
+func (c *ListCommand) Run(ctx *cmd.Context) error {
+	user := c.User
+	if user == "" {
+		creds, err := c.ConnectionCredentials()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		user = creds.User
+	}
+
+	client, err := getListEnvironmentsAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	envs, err := client.ListEnvironments(names.NewUserTag(user))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	infos := make([]EnvironmentInfo, len(envs))
+	for i, env := range envs {
+		owner := env.OwnerTag
+		if tag, err := names.ParseUserTag(env.OwnerTag); err == nil {
+			owner = tag.Username()
+		}
+		infos[i] = EnvironmentInfo{
+			Name:  env.Name,
+			UUID:  env.UUID,
+			Owner: owner,
+			Life:  env.Life,
+		}
+	}
+	return c.out.Write(ctx, infos)
+}
+
+func formatTabular(value interface{}) ([]byte, error) {
+	envs, ok := value.([]EnvironmentInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", envs, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	fmt.Fprintf(tw, "NAME\tOWNER\tLIFE\n")
+	for _, env := range envs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", env.Name, env.Owner, env.Life)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
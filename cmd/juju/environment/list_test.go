@@ -0,0 +1,84 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environment_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/environment"
+	"github.com/juju/juju/testing"
+)
+
+type ListSuite struct {
+	BaseSuite
+	fake *fakeEnvManagerAPI
+}
+
+var _ = gc.Suite(&ListSuite{})
+
+func (s *ListSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.fake = &fakeEnvManagerAPI{
+		envs: []params.Environment{{
+			Name:       "test-env",
+			UUID:       "server-uuid",
+			OwnerTag:   names.NewLocalUserTag("admin").String(),
+			ServerUUID: "server-uuid",
+			Life:       "alive",
+		}, {
+			Name:       "staging",
+			UUID:       "staging-uuid",
+			OwnerTag:   names.NewLocalUserTag("alex").String(),
+			ServerUUID: "server-uuid",
+			Life:       "dying",
+		}},
+	}
+	s.PatchValue(environment.GetListEnvironmentsAPI, func(*environment.ListCommand) (environment.ListEnvironmentsAPI, error) {
+		return s.fake, nil
+	})
+}
+
+func (s *ListSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&environment.ListCommand{}), args...)
+}
+
+func (s *ListSuite) TestList(c *gc.C) {
+	ctx, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.user, gc.Equals, names.NewUserTag("user-test"))
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"NAME      OWNER        LIFE\n"+
+		"test-env  admin@local  alive\n"+
+		"staging   alex@local   dying\n")
+}
+
+func (s *ListSuite) TestListYAML(c *gc.C) {
+	ctx, err := s.run(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"- name: test-env\n"+
+		"  uuid: server-uuid\n"+
+		"  owner: admin@local\n"+
+		"  life: alive\n"+
+		"- name: staging\n"+
+		"  uuid: staging-uuid\n"+
+		"  owner: alex@local\n"+
+		"  life: dying\n")
+}
+
+func (s *ListSuite) TestListForUser(c *gc.C) {
+	_, err := s.run(c, "--user", "alex")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.user, gc.Equals, names.NewUserTag("alex"))
+}
+
+func (s *ListSuite) TestListInvalidUser(c *gc.C) {
+	_, err := s.run(c, "--user", "not/valid")
+	c.Assert(err, gc.ErrorMatches, `"not/valid" is not a valid user`)
+}
@@ -0,0 +1,16 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environment_test
+
+import (
+	"testing"
+
+	gc "gopkg.in/check.v1"
+)
+
+// None of the tests in this package require mongo.
+
+func TestPackage(t *testing.T) {
+	gc.TestingT(t)
+}
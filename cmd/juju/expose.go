@@ -5,23 +5,41 @@ package main
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/version"
 )
 
+// minExposeFromVersion is the first API server version that restricts
+// exposed services to source CIDRs. Older servers ignore them, and so
+// would expose the service to every address.
+var minExposeFromVersion = version.MustParse("1.22-alpha1")
+
 // ExposeCommand is responsible exposing services.
 type ExposeCommand struct {
 	envcmd.EnvCommandBase
 	ServiceName string
+	SourceCIDRs []string
 }
 
 var jujuExposeHelp = `
 Adjusts firewall rules and similar security mechanisms of the provider, to
 allow the service to be accessed on its public address.
 
+By default the service is accessible from any address. Use --from to
+restrict access to a comma-separated list of source CIDRs, e.g.
+
+    juju expose wordpress --from 10.0.0.0/8,203.0.113.4/32
+
+Exposing an already exposed service replaces its source CIDRs. Only the
+EC2 and OpenStack providers can restrict access by source address; --from
+is rejected by the others.
 `
 
 func (c *ExposeCommand) Info() *cmd.Info {
@@ -33,11 +51,18 @@ func (c *ExposeCommand) Info() *cmd.Info {
 	}
 }
 
+func (c *ExposeCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.Var(cmd.NewStringsValue(nil, &c.SourceCIDRs), "from", "source CIDRs to restrict access to")
+}
+
 func (c *ExposeCommand) Init(args []string) error {
 	if len(args) == 0 {
 		return errors.New("no service name specified")
 	}
 	c.ServiceName = args[0]
+	if err := network.ValidateSourceCIDRs(c.SourceCIDRs); err != nil {
+		return err
+	}
 	return cmd.CheckEmpty(args[1:])
 }
 
@@ -49,5 +74,14 @@ func (c *ExposeCommand) Run(_ *cmd.Context) error {
 		return err
 	}
 	defer client.Close()
-	return block.ProcessBlockedError(client.ServiceExpose(c.ServiceName), block.BlockChange)
+	if len(c.SourceCIDRs) > 0 {
+		serverVersion, err := client.AgentVersion()
+		if err != nil {
+			return err
+		}
+		if serverVersion.Compare(minExposeFromVersion) < 0 {
+			return fmt.Errorf("--from is not supported by the API server (version %s); it requires version %s or later", serverVersion, minExposeFromVersion)
+		}
+	}
+	return block.ProcessBlockedError(client.ServiceExpose(c.ServiceName, c.SourceCIDRs...), block.BlockChange)
 }
@@ -13,6 +13,7 @@ import (
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/testcharms"
 	"github.com/juju/juju/testing"
+	"github.com/juju/juju/version"
 	"strings"
 )
 
@@ -49,6 +50,34 @@ func (s *ExposeSuite) TestExpose(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `service "nonexistent-service" not found`)
 }
 
+func (s *ExposeSuite) TestExposeFrom(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
+	err := runDeploy(c, "local:dummy", "some-service-name")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = runExpose(c, "some-service-name", "--from", "203.0.113.4/32,10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertExposed(c, "some-service-name")
+	svc, err := s.State.Service("some-service-name")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.ExposedSourceCIDRs(), jc.DeepEquals, []string{"10.0.0.0/8", "203.0.113.4/32"})
+}
+
+func (s *ExposeSuite) TestExposeFromOldServer(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
+	err := runDeploy(c, "local:dummy", "some-service-name")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// An older server would ignore the source CIDRs and expose the
+	// service to everyone, so it is not asked to.
+	s.PatchValue(&minExposeFromVersion, version.MustParse("99.0.0"))
+	err = runExpose(c, "some-service-name", "--from", "10.0.0.0/8")
+	c.Assert(err, gc.ErrorMatches, `--from is not supported by the API server \(version .*\); it requires version 99.0.0 or later`)
+	svc, err := s.State.Service("some-service-name")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.IsExposed(), jc.IsFalse)
+}
+
 func (s *ExposeSuite) TestBlockExpose(c *gc.C) {
 	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
 	err := runDeploy(c, "local:dummy", "some-service-name")
@@ -394,6 +394,22 @@ networks
    network. Positive network constraints do not imply the networks will be enabled,
    use the --networks argument for that, just that they could be enabled.
 
+spaces
+   Spaces defines the list of spaces the machine must (or must not) be started
+   in. Spaces are named groups of subnets, managed with "juju space" and "juju
+   subnet". Both positive and negative space constraints can be specified, the
+   latter have a "^" prefix to the name. Multiple spaces must be delimited by a
+   comma. Example: spaces=db,^dmz specifies to start machines in a subnet of the
+   "db" space, but not in one of the "dmz" space. Spaces are currently only
+   supported by the Amazon EC2 and MaaS environments.
+
+zones
+   Zones defines the list of availability zones the machine may be started in.
+   Multiple zones must be delimited by a comma. New machines for the units of a
+   service are spread across the allowed zones, starting each one in the zone
+   with the fewest units of that service. Only supported by environments with
+   availability zones. Example: zones=us-east-1a,us-east-1b
+
 instance-type
    Instance-type is the provider-specific name of a type of machine to deploy,
    for example m1.small on EC2 or A4 on Azure.  Specifying this constraint may
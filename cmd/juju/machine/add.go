@@ -65,6 +65,8 @@ Examples:
    juju machine add lxc                  (starts a new machine with an lxc container)
    juju machine add lxc -n 2             (starts 2 new machines with an lxc container)
    juju machine add lxc:4                (starts a new lxc container on machine 4)
+   juju machine add lxd:4                (starts a new lxd container on machine 4)
+   juju machine add docker:4             (starts a new docker container on machine 4)
    juju machine add --constraints mem=8G (starts a machine with at least 8GB RAM)
    juju machine add ssh:user@10.10.0.3   (manually provisions a machine with ssh)
    juju machine add zone=us-east-1a
@@ -61,6 +61,14 @@ func (s *AddMachineSuite) TestInit(c *gc.C) {
 			args:      []string{"lxc:4"},
 			count:     1,
 			placement: "lxc:4",
+		}, {
+			args:      []string{"lxd:4"},
+			count:     1,
+			placement: "lxd:4",
+		}, {
+			args:      []string{"docker:4"},
+			count:     1,
+			placement: "docker:4",
 		}, {
 			args:        []string{"--constraints", "mem=8G"},
 			count:       1,
@@ -13,9 +13,14 @@ import (
 
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/action"
 	"github.com/juju/juju/cmd/juju/backups"
 	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/juju/environment"
 	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/cmd/juju/space"
+	"github.com/juju/juju/cmd/juju/storage"
+	"github.com/juju/juju/cmd/juju/subnet"
 	"github.com/juju/juju/cmd/juju/user"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/juju"
@@ -121,9 +126,11 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 
 	// Reporting commands.
 	r.Register(wrapEnvCommand(&StatusCommand{}))
+	r.Register(wrapEnvCommand(&StatusHistoryCommand{}))
 	r.Register(&SwitchCommand{})
 	r.Register(wrapEnvCommand(&EndpointCommand{}))
 	r.Register(wrapEnvCommand(&APIInfoCommand{}))
+	r.Register(wrapEnvCommand(&AuditLogCommand{}))
 
 	// Error resolution and debugging commands.
 	r.Register(wrapEnvCommand(&RunCommand{}))
@@ -156,6 +163,9 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	// Charm tool commands.
 	r.Register(&HelpToolCommand{})
 
+	// Manage and control actions
+	r.Register(action.NewSuperCommand())
+
 	// Manage backups.
 	r.Register(backups.NewCommand())
 
@@ -165,6 +175,9 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	// Manage users and access
 	r.Register(user.NewSuperCommand())
 
+	// Manage hosted environments
+	r.Register(environment.NewSuperCommand())
+
 	// Manage machines
 	r.Register(machine.NewSuperCommand())
 	r.RegisterSuperAlias("add-machine", "machine", "add", twoDotOhDeprecation("machine add"))
@@ -172,6 +185,13 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.RegisterSuperAlias("destroy-machine", "machine", "remove", twoDotOhDeprecation("machine remove"))
 	r.RegisterSuperAlias("terminate-machine", "machine", "remove", twoDotOhDeprecation("machine remove"))
 
+	// Manage storage
+	r.Register(storage.NewSuperCommand())
+
+	// Manage subnets and spaces
+	r.Register(subnet.NewSuperCommand())
+	r.Register(space.NewSuperCommand())
+
 	// Manage state server availability.
 	r.Register(wrapEnvCommand(&EnsureAvailabilityCommand{}))
 
@@ -173,11 +173,13 @@ func (s *MainSuite) TestActualRunJujuArgOrder(c *gc.C) {
 }
 
 var commandNames = []string{
+	"action",
 	"add-machine",
 	"add-relation",
 	"add-unit",
 	"api-endpoints",
 	"api-info",
+	"audit-log",
 	"authorised-keys", // alias for authorized-keys
 	"authorized-keys",
 	"backups",
@@ -193,6 +195,7 @@ var commandNames = []string{
 	"destroy-unit",
 	"ensure-availability",
 	"env", // alias for switch
+	"environment",
 	"expose",
 	"generate-config", // alias for init
 	"get",
@@ -216,9 +219,13 @@ var commandNames = []string{
 	"set-constraints",
 	"set-env", // alias for set-environment
 	"set-environment",
+	"space",
 	"ssh",
 	"stat", // alias for status
 	"status",
+	"status-history",
+	"storage",
+	"subnet",
 	"switch",
 	"sync-tools",
 	"terminate-machine", // alias for destroy-machine
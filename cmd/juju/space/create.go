@@ -0,0 +1,92 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space
+
+import (
+	"net"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/network"
+)
+
+const createCommandDoc = `
+Create a space with the given name, holding the subnets with the given
+CIDRs. The subnets must already be known to juju (see "juju subnet add").
+A subnet belongs to at most one space, so any subnets already part of
+another space are moved to the new one.
+
+Examples:
+    juju space create db
+    juju space create db 10.0.1.0/24 10.0.2.0/24
+`
+
+// CreateCommand creates a space.
+type CreateCommand struct {
+	envcmd.EnvCommandBase
+	api CreateSpaceAPI
+
+	Name    string
+	Subnets []string
+}
+
+// CreateSpaceAPI defines the API methods that the space create
+// command uses.
+type CreateSpaceAPI interface {
+	CreateSpace(name string, subnets ...string) error
+	Close() error
+}
+
+// Info implements Command.Info.
+func (c *CreateCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "create",
+		Args:    "<name> [<CIDR> ...]",
+		Purpose: "create a space",
+		Doc:     createCommandDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *CreateCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no space name specified")
+	}
+	if !network.IsValidSpace(args[0]) {
+		return errors.Errorf("%q is not a valid space name", args[0])
+	}
+	c.Name = args[0]
+	seen := make(map[string]bool)
+	for _, cidr := range args[1:] {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return errors.Errorf("%q is not a valid CIDR", cidr)
+		}
+		if seen[cidr] {
+			return errors.Errorf("duplicate subnet %q specified", cidr)
+		}
+		seen[cidr] = true
+		c.Subnets = append(c.Subnets, cidr)
+	}
+	return nil
+}
+
+func (c *CreateCommand) getCreateSpaceAPI() (CreateSpaceAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewAPIClient()
+}
+
+// Run implements Command.Run.
+func (c *CreateCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getCreateSpaceAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	return client.CreateSpace(c.Name, c.Subnets...)
+}
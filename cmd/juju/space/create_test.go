@@ -0,0 +1,89 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/space"
+	"github.com/juju/juju/testing"
+)
+
+type CreateSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeSpaceAPI
+}
+
+var _ = gc.Suite(&CreateSuite{})
+
+type fakeSpaceAPI struct {
+	name    string
+	subnets []string
+	spaces  []params.Space
+	err     error
+}
+
+func (f *fakeSpaceAPI) CreateSpace(name string, subnets ...string) error {
+	f.name = name
+	f.subnets = subnets
+	return f.err
+}
+
+func (f *fakeSpaceAPI) ListSpaces() ([]params.Space, error) {
+	return f.spaces, f.err
+}
+
+func (f *fakeSpaceAPI) Close() error {
+	return nil
+}
+
+func (s *CreateSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeSpaceAPI{}
+}
+
+func (s *CreateSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(space.NewCreateCommand(s.fake)), args...)
+}
+
+func (s *CreateSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		errMatch string
+	}{{
+		errMatch: "no space name specified",
+	}, {
+		args:     []string{"Db"},
+		errMatch: `"Db" is not a valid space name`,
+	}, {
+		args:     []string{"db", "10.0.0.0"},
+		errMatch: `"10.0.0.0" is not a valid CIDR`,
+	}, {
+		args:     []string{"db", "10.0.0.0/24", "10.0.0.0/24"},
+		errMatch: `duplicate subnet "10.0.0.0/24" specified`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := s.run(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.errMatch)
+	}
+	c.Assert(s.fake.name, gc.Equals, "")
+}
+
+func (s *CreateSuite) TestCreate(c *gc.C) {
+	_, err := s.run(c, "db", "10.0.1.0/24", "10.0.0.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.name, gc.Equals, "db")
+	c.Assert(s.fake.subnets, jc.DeepEquals, []string{"10.0.1.0/24", "10.0.0.0/24"})
+}
+
+func (s *CreateSuite) TestCreateError(c *gc.C) {
+	s.fake.err = errors.New("boom")
+	_, err := s.run(c, "db")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -0,0 +1,18 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space
+
+// NewCreateCommand returns a CreateCommand with the api provided as specified.
+func NewCreateCommand(api CreateSpaceAPI) *CreateCommand {
+	return &CreateCommand{
+		api: api,
+	}
+}
+
+// NewListCommand returns a ListCommand with the api provided as specified.
+func NewListCommand(api ListSpacesAPI) *ListCommand {
+	return &ListCommand{
+		api: api,
+	}
+}
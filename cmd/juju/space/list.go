@@ -0,0 +1,125 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const listCommandDoc = `
+List the spaces in the environment, along with the CIDRs of the subnets
+that belong to each.
+
+Examples:
+    juju space list
+    juju space list --format yaml
+`
+
+// ListCommand lists the spaces in the environment.
+type ListCommand struct {
+	envcmd.EnvCommandBase
+	api ListSpacesAPI
+	out cmd.Output
+}
+
+// ListSpacesAPI defines the API methods that the space list command
+// uses.
+type ListSpacesAPI interface {
+	ListSpaces() ([]params.Space, error)
+	Close() error
+}
+
+// Info implements Command.Info.
+func (c *ListCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Purpose: "list spaces",
+		Doc:     listCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *ListCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatListTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *ListCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *ListCommand) getListSpacesAPI() (ListSpacesAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewAPIClient()
+}
+
+// Run implements Command.Run.
+func (c *ListCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getListSpacesAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	spaces, err := client.ListSpaces()
+	if err != nil {
+		return err
+	}
+	// Spaces are formatted as a map from name to subnet CIDRs.
+	formatted := make(map[string][]string, len(spaces))
+	for _, space := range spaces {
+		subnets := space.Subnets
+		if subnets == nil {
+			subnets = []string{}
+		}
+		formatted[space.Name] = subnets
+	}
+	return c.out.Write(ctx, formatted)
+}
+
+func formatListTabular(value interface{}) ([]byte, error) {
+	spaces, ok := value.(map[string][]string)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", spaces, value)
+	}
+	names := make([]string, 0, len(spaces))
+	for name := range spaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	fmt.Fprintf(tw, "SPACE\tSUBNETS\n")
+	for _, name := range names {
+		fmt.Fprintf(tw, "%s\t%s\n", name, strings.Join(spaces[name], ","))
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
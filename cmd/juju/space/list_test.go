@@ -0,0 +1,69 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/space"
+	"github.com/juju/juju/testing"
+)
+
+type ListSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeSpaceAPI
+}
+
+var _ = gc.Suite(&ListSuite{})
+
+func (s *ListSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeSpaceAPI{
+		spaces: []params.Space{{
+			Name:    "db",
+			Subnets: []string{"10.0.0.0/24", "10.0.1.0/24"},
+		}, {
+			Name: "admin",
+		}},
+	}
+}
+
+func (s *ListSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(space.NewListCommand(s.fake)), args...)
+}
+
+func (s *ListSuite) TestInit(c *gc.C) {
+	_, err := s.run(c, "db")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["db"\]`)
+}
+
+func (s *ListSuite) TestListTabular(c *gc.C) {
+	ctx, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"SPACE  SUBNETS\n"+
+		"admin  \n"+
+		"db     10.0.0.0/24,10.0.1.0/24\n")
+}
+
+func (s *ListSuite) TestListYAML(c *gc.C) {
+	ctx, err := s.run(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"admin: []\n"+
+		"db:\n"+
+		"- 10.0.0.0/24\n"+
+		"- 10.0.1.0/24\n")
+}
+
+func (s *ListSuite) TestListError(c *gc.C) {
+	s.fake.err = errors.New("boom")
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
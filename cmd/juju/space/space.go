@@ -0,0 +1,34 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space
+
+import (
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const spaceCommandDoc = `
+"juju space" provides commands to manage spaces. A space is a named group
+of subnets (see "juju help subnet"). The spaces constraint selects the
+subnets a machine may be started in by space, for example:
+
+    juju deploy mysql --constraints spaces=db,^dmz
+`
+
+const spaceCommandPurpose = "manage spaces"
+
+// NewSuperCommand creates the space supercommand and registers the
+// subcommands that it supports.
+func NewSuperCommand() cmd.Command {
+	spaceCmd := cmd.NewSuperCommand(cmd.SuperCommandParams{
+		Name:        "space",
+		Doc:         spaceCommandDoc,
+		UsagePrefix: "juju",
+		Purpose:     spaceCommandPurpose,
+	})
+	spaceCmd.Register(envcmd.Wrap(&CreateCommand{}))
+	spaceCmd.Register(envcmd.Wrap(&ListCommand{}))
+	return spaceCmd
+}
@@ -129,6 +129,8 @@ type formattedStatus struct {
 	Machines    map[string]machineStatus `json:"machines"`
 	Services    map[string]serviceStatus `json:"services"`
 	Networks    map[string]networkStatus `json:"networks,omitempty" yaml:",omitempty"`
+
+	StateServerAvailability string `json:"state-server-availability,omitempty" yaml:"state-server-availability,omitempty"`
 }
 
 type errorStatus struct {
@@ -136,19 +138,20 @@ type errorStatus struct {
 }
 
 type machineStatus struct {
-	Err            error                    `json:"-" yaml:",omitempty"`
-	AgentState     params.Status            `json:"agent-state,omitempty" yaml:"agent-state,omitempty"`
-	AgentStateInfo string                   `json:"agent-state-info,omitempty" yaml:"agent-state-info,omitempty"`
-	AgentVersion   string                   `json:"agent-version,omitempty" yaml:"agent-version,omitempty"`
-	DNSName        string                   `json:"dns-name,omitempty" yaml:"dns-name,omitempty"`
-	InstanceId     instance.Id              `json:"instance-id,omitempty" yaml:"instance-id,omitempty"`
-	InstanceState  string                   `json:"instance-state,omitempty" yaml:"instance-state,omitempty"`
-	Life           string                   `json:"life,omitempty" yaml:"life,omitempty"`
-	Series         string                   `json:"series,omitempty" yaml:"series,omitempty"`
-	Id             string                   `json:"-" yaml:"-"`
-	Containers     map[string]machineStatus `json:"containers,omitempty" yaml:"containers,omitempty"`
-	Hardware       string                   `json:"hardware,omitempty" yaml:"hardware,omitempty"`
-	HAStatus       string                   `json:"state-server-member-status,omitempty" yaml:"state-server-member-status,omitempty"`
+	Err              error                    `json:"-" yaml:",omitempty"`
+	AgentState       params.Status            `json:"agent-state,omitempty" yaml:"agent-state,omitempty"`
+	AgentStateInfo   string                   `json:"agent-state-info,omitempty" yaml:"agent-state-info,omitempty"`
+	AgentVersion     string                   `json:"agent-version,omitempty" yaml:"agent-version,omitempty"`
+	DNSName          string                   `json:"dns-name,omitempty" yaml:"dns-name,omitempty"`
+	InstanceId       instance.Id              `json:"instance-id,omitempty" yaml:"instance-id,omitempty"`
+	InstanceState    string                   `json:"instance-state,omitempty" yaml:"instance-state,omitempty"`
+	AvailabilityZone string                   `json:"availability-zone,omitempty" yaml:"availability-zone,omitempty"`
+	Life             string                   `json:"life,omitempty" yaml:"life,omitempty"`
+	Series           string                   `json:"series,omitempty" yaml:"series,omitempty"`
+	Id               string                   `json:"-" yaml:"-"`
+	Containers       map[string]machineStatus `json:"containers,omitempty" yaml:"containers,omitempty"`
+	Hardware         string                   `json:"hardware,omitempty" yaml:"hardware,omitempty"`
+	HAStatus         string                   `json:"state-server-member-status,omitempty" yaml:"state-server-member-status,omitempty"`
 }
 
 // A goyaml bug means we can't declare these types
@@ -204,16 +207,18 @@ func (s serviceStatus) GetYAML() (tag string, value interface{}) {
 }
 
 type unitStatus struct {
-	Err            error                 `json:"-" yaml:",omitempty"`
-	Charm          string                `json:"upgrading-from,omitempty" yaml:"upgrading-from,omitempty"`
-	AgentState     params.Status         `json:"agent-state,omitempty" yaml:"agent-state,omitempty"`
-	AgentStateInfo string                `json:"agent-state-info,omitempty" yaml:"agent-state-info,omitempty"`
-	AgentVersion   string                `json:"agent-version,omitempty" yaml:"agent-version,omitempty"`
-	Life           string                `json:"life,omitempty" yaml:"life,omitempty"`
-	Machine        string                `json:"machine,omitempty" yaml:"machine,omitempty"`
-	OpenedPorts    []string              `json:"open-ports,omitempty" yaml:"open-ports,omitempty"`
-	PublicAddress  string                `json:"public-address,omitempty" yaml:"public-address,omitempty"`
-	Subordinates   map[string]unitStatus `json:"subordinates,omitempty" yaml:"subordinates,omitempty"`
+	Err                error                 `json:"-" yaml:",omitempty"`
+	Charm              string                `json:"upgrading-from,omitempty" yaml:"upgrading-from,omitempty"`
+	AgentState         params.Status         `json:"agent-state,omitempty" yaml:"agent-state,omitempty"`
+	AgentStateInfo     string                `json:"agent-state-info,omitempty" yaml:"agent-state-info,omitempty"`
+	AgentVersion       string                `json:"agent-version,omitempty" yaml:"agent-version,omitempty"`
+	WorkloadStatus     params.Status         `json:"workload-status,omitempty" yaml:"workload-status,omitempty"`
+	WorkloadStatusInfo string                `json:"workload-status-info,omitempty" yaml:"workload-status-info,omitempty"`
+	Life               string                `json:"life,omitempty" yaml:"life,omitempty"`
+	Machine            string                `json:"machine,omitempty" yaml:"machine,omitempty"`
+	OpenedPorts        []string              `json:"open-ports,omitempty" yaml:"open-ports,omitempty"`
+	PublicAddress      string                `json:"public-address,omitempty" yaml:"public-address,omitempty"`
+	Subordinates       map[string]unitStatus `json:"subordinates,omitempty" yaml:"subordinates,omitempty"`
 }
 
 type unitStatusNoMarshal unitStatus
@@ -283,6 +288,9 @@ func (sf *statusFormatter) format() formattedStatus {
 		Machines:    make(map[string]machineStatus),
 		Services:    make(map[string]serviceStatus),
 	}
+	if sf.status.StateServersDegraded {
+		out.StateServerAvailability = "degraded"
+	}
 	for k, m := range sf.status.Machines {
 		out.Machines[k] = sf.formatMachine(m)
 	}
@@ -305,35 +313,37 @@ func (sf *statusFormatter) formatMachine(machine api.MachineStatus) machineStatu
 		// Older server
 		// TODO: this will go away at some point (v1.21?).
 		out = machineStatus{
-			AgentState:     machine.AgentState,
-			AgentStateInfo: machine.AgentStateInfo,
-			AgentVersion:   machine.AgentVersion,
-			Life:           machine.Life,
-			Err:            machine.Err,
-			DNSName:        machine.DNSName,
-			InstanceId:     machine.InstanceId,
-			InstanceState:  machine.InstanceState,
-			Series:         machine.Series,
-			Id:             machine.Id,
-			Containers:     make(map[string]machineStatus),
-			Hardware:       machine.Hardware,
+			AgentState:       machine.AgentState,
+			AgentStateInfo:   machine.AgentStateInfo,
+			AgentVersion:     machine.AgentVersion,
+			Life:             machine.Life,
+			Err:              machine.Err,
+			DNSName:          machine.DNSName,
+			InstanceId:       machine.InstanceId,
+			InstanceState:    machine.InstanceState,
+			AvailabilityZone: machine.AvailabilityZone,
+			Series:           machine.Series,
+			Id:               machine.Id,
+			Containers:       make(map[string]machineStatus),
+			Hardware:         machine.Hardware,
 		}
 	} else {
 		// New server
 		agent := machine.Agent
 		out = machineStatus{
-			AgentState:     machine.AgentState,
-			AgentStateInfo: adjustInfoIfAgentDown(machine.AgentState, agent.Status, agent.Info),
-			AgentVersion:   agent.Version,
-			Life:           agent.Life,
-			Err:            agent.Err,
-			DNSName:        machine.DNSName,
-			InstanceId:     machine.InstanceId,
-			InstanceState:  machine.InstanceState,
-			Series:         machine.Series,
-			Id:             machine.Id,
-			Containers:     make(map[string]machineStatus),
-			Hardware:       machine.Hardware,
+			AgentState:       machine.AgentState,
+			AgentStateInfo:   adjustInfoIfAgentDown(machine.AgentState, agent.Status, agent.Info),
+			AgentVersion:     agent.Version,
+			Life:             agent.Life,
+			Err:              agent.Err,
+			DNSName:          machine.DNSName,
+			InstanceId:       machine.InstanceId,
+			InstanceState:    machine.InstanceState,
+			AvailabilityZone: machine.AvailabilityZone,
+			Series:           machine.Series,
+			Id:               machine.Id,
+			Containers:       make(map[string]machineStatus),
+			Hardware:         machine.Hardware,
 		}
 	}
 
@@ -387,6 +397,12 @@ func (sf *statusFormatter) formatUnit(unit api.UnitStatus, serviceName string) u
 		Charm:          unit.Charm,
 		Subordinates:   make(map[string]unitStatus),
 	}
+	// The workload status is only shown once the charm has reported
+	// one; older servers do not report it at all.
+	if unit.WorkloadStatus != params.StatusUnknown {
+		out.WorkloadStatus = unit.WorkloadStatus
+		out.WorkloadStatusInfo = unit.WorkloadStatusInfo
+	}
 	for k, m := range unit.Subordinates {
 		out.Subordinates[k] = sf.formatUnit(m, serviceName)
 	}
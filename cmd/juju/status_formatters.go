@@ -96,6 +96,7 @@ func FormatTabular(value interface{}) ([]byte, error) {
 		p(
 			indent("", level*2, name),
 			u.AgentState,
+			u.WorkloadStatus,
 			u.AgentVersion,
 			u.Machine,
 			strings.Join(u.OpenedPorts, ","),
@@ -104,7 +105,7 @@ func FormatTabular(value interface{}) ([]byte, error) {
 	}
 
 	p("\n[Units]")
-	p("ID\tSTATE\tVERSION\tMACHINE\tPORTS\tPUBLIC-ADDRESS")
+	p("ID\tSTATE\tWORKLOAD\tVERSION\tMACHINE\tPORTS\tPUBLIC-ADDRESS")
 	for _, name := range sortStrings(stringKeysFromMap(units)) {
 		u := units[name]
 		pUnit(name, u, 0)
@@ -860,6 +860,49 @@ var statusTests = []testCase{
 				},
 			},
 		},
+	), test(
+		"a unit with a workload status reported by its charm",
+		addMachine{machineId: "0", job: state.JobManageEnviron},
+		setAddresses{"0", []network.Address{network.NewAddress("dummyenv-0.dns", network.ScopeUnknown)}},
+		startAliveMachine{"0"},
+		setMachineStatus{"0", state.StatusStarted, ""},
+
+		addMachine{machineId: "1", job: state.JobHostUnits},
+		setAddresses{"1", []network.Address{network.NewAddress("dummyenv-1.dns", network.ScopeUnknown)}},
+		startAliveMachine{"1"},
+		setMachineStatus{"1", state.StatusStarted, ""},
+
+		addCharm{"wordpress"},
+		addService{name: "wordpress", charm: "wordpress"},
+		addAliveUnit{"wordpress", "1"},
+		setUnitStatus{"wordpress/0", state.StatusStarted, "", nil},
+		setUnitWorkloadStatus{"wordpress/0", state.StatusBlocked, "need a database"},
+
+		expect{
+			"a unit with a workload status reported by its charm",
+			M{
+				"environment": "dummyenv",
+				"machines": M{
+					"0": machine0,
+					"1": machine1,
+				},
+				"services": M{
+					"wordpress": M{
+						"charm":   "cs:quantal/wordpress-3",
+						"exposed": false,
+						"units": M{
+							"wordpress/0": M{
+								"machine":              "1",
+								"agent-state":          "started",
+								"workload-status":      "blocked",
+								"workload-status-info": "need a database",
+								"public-address":       "dummyenv-1.dns",
+							},
+						},
+					},
+				},
+			},
+		},
 	), test(
 		"a unit with a hook relation error when the agent is down",
 		addMachine{machineId: "0", job: state.JobManageEnviron},
@@ -1916,6 +1959,19 @@ func (sus setUnitStatus) step(c *gc.C, ctx *context) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+type setUnitWorkloadStatus struct {
+	unitName   string
+	status     state.Status
+	statusInfo string
+}
+
+func (sus setUnitWorkloadStatus) step(c *gc.C, ctx *context) {
+	u, err := ctx.st.Unit(sus.unitName)
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.SetWorkloadStatus(sus.status, sus.statusInfo)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 type setUnitCharmURL struct {
 	unitName string
 	charm    string
@@ -2398,6 +2454,8 @@ func (s *StatusSuite) TestStatusWithFormatTabular(c *gc.C) {
 		setUnitsAlive{"logging"},
 		setUnitStatus{"logging/0", state.StatusStarted, "", nil},
 		setUnitStatus{"logging/1", state.StatusError, "somehow lost in all those logs", nil},
+		setUnitWorkloadStatus{"wordpress/0", state.StatusActive, ""},
+		setUnitWorkloadStatus{"mysql/0", state.StatusBlocked, "need more disk"},
 	}
 	for _, s := range steps {
 		s.step(c, ctx)
@@ -2421,11 +2479,11 @@ func (s *StatusSuite) TestStatusWithFormatTabular(c *gc.C) {
 			"wordpress  true    cs:quantal/wordpress-3 \n"+
 			"\n"+
 			"[Units]     \n"+
-			"ID          STATE   VERSION MACHINE PORTS PUBLIC-ADDRESS \n"+
-			"mysql/0     started         2             dummyenv-2.dns \n"+
-			"  logging/1 error                         dummyenv-2.dns \n"+
-			"wordpress/0 started         1             dummyenv-1.dns \n"+
-			"  logging/0 started                       dummyenv-1.dns \n"+
+			"ID          STATE   WORKLOAD VERSION MACHINE PORTS PUBLIC-ADDRESS \n"+
+			"mysql/0     started blocked          2             dummyenv-2.dns \n"+
+			"  logging/1 error                                  dummyenv-2.dns \n"+
+			"wordpress/0 started active           1             dummyenv-1.dns \n"+
+			"  logging/0 started                                dummyenv-1.dns \n"+
 			"\n",
 	)
 }
@@ -0,0 +1,159 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const statusHistoryDoc = `
+Show the recorded status transitions of a unit or a machine, most
+recent first. Juju keeps a bounded history of the statuses of each unit
+and machine, so that failures that have since been resolved can still
+be investigated.
+
+Older entries can be shown by passing the time of the oldest entry
+displayed to --before. Times are given either as a date (2006-01-02) or
+as an RFC 3339 timestamp (2006-01-02T15:04:05Z), which may include
+fractional seconds. Entry times are shown with fractional seconds so
+that entries recorded within the same second are not skipped.
+
+Examples:
+
+    juju status-history mysql/0
+    juju status-history -n 50 0/lxc/1
+    juju status-history --before 2014-11-05T10:00:00Z mysql/0
+`
+
+// StatusHistoryCommand shows the status history of a unit or machine.
+type StatusHistoryCommand struct {
+	envcmd.EnvCommandBase
+	out cmd.Output
+
+	size   int
+	before string
+
+	tag        names.Tag
+	beforeTime time.Time
+}
+
+// Info implements Command.Info.
+func (c *StatusHistoryCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "status-history",
+		Args:    "<unit> | <machine>",
+		Purpose: "show the status history of a unit or machine",
+		Doc:     statusHistoryDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *StatusHistoryCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.IntVar(&c.size, "n", 20, "show at most this many entries")
+	f.StringVar(&c.before, "before", "", "only show entries recorded before this time")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatStatusHistoryTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *StatusHistoryCommand) Init(args []string) (err error) {
+	if len(args) == 0 {
+		return errors.New("no unit or machine specified")
+	}
+	switch entity := args[0]; {
+	case names.IsValidUnit(entity):
+		c.tag = names.NewUnitTag(entity)
+	case names.IsValidMachine(entity):
+		c.tag = names.NewMachineTag(entity)
+	default:
+		return errors.Errorf("%q is neither a unit nor a machine", entity)
+	}
+	if c.size <= 0 {
+		return errors.Errorf("invalid number of entries %d", c.size)
+	}
+	if c.beforeTime, err = parseAuditTime(c.before); err != nil {
+		return errors.Annotate(err, "invalid --before value")
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// StatusHistoryAPI defines the API methods that the status-history
+// command uses.
+type StatusHistoryAPI interface {
+	StatusHistory(tag names.Tag, size int, before time.Time) ([]params.StatusHistoryEntry, error)
+	Close() error
+}
+
+var getStatusHistoryAPI = func(c *StatusHistoryCommand) (StatusHistoryAPI, error) {
+	return c.NewAPIClient()
+}
+
+// statusHistoryEntry holds a status history entry formatted for output.
+type statusHistoryEntry struct {
+	Time   string                 `yaml:"time" json:"time"`
+	Status string                 `yaml:"status" json:"status"`
+	Info   string                 `yaml:"info,omitempty" json:"info,omitempty"`
+	Data   map[string]interface{} `yaml:"data,omitempty" json:"data,omitempty"`
+}
+
+// Run implements Command.Run.
+func (c *StatusHistoryCommand) Run(ctx *cmd.Context) error {
+	client, err := getStatusHistoryAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	history, err := client.StatusHistory(c.tag, c.size, c.beforeTime)
+	if err != nil {
+		return err
+	}
+	formatted := make([]statusHistoryEntry, len(history))
+	for i, entry := range history {
+		formatted[i] = statusHistoryEntry{
+			Time:   entry.Updated.UTC().Format(time.RFC3339Nano),
+			Status: string(entry.Status),
+			Info:   entry.Info,
+			Data:   entry.Data,
+		}
+	}
+	return c.out.Write(ctx, formatted)
+}
+
+func formatStatusHistoryTabular(value interface{}) ([]byte, error) {
+	entries, ok := value.([]statusHistoryEntry)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", entries, value)
+	}
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	fmt.Fprintf(tw, "TIME\tSTATUS\tINFO\n")
+	for _, entry := range entries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", entry.Time, entry.Status, entry.Info)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
@@ -0,0 +1,172 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type StatusHistorySuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeStatusHistoryAPI
+}
+
+var _ = gc.Suite(&StatusHistorySuite{})
+
+type fakeStatusHistoryAPI struct {
+	tag     names.Tag
+	size    int
+	before  time.Time
+	history []params.StatusHistoryEntry
+	err     error
+}
+
+func (f *fakeStatusHistoryAPI) StatusHistory(tag names.Tag, size int, before time.Time) ([]params.StatusHistoryEntry, error) {
+	f.tag = tag
+	f.size = size
+	f.before = before
+	return f.history, f.err
+}
+
+func (f *fakeStatusHistoryAPI) Close() error {
+	return nil
+}
+
+func (s *StatusHistorySuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeStatusHistoryAPI{
+		history: []params.StatusHistoryEntry{{
+			Status:  params.StatusStarted,
+			Updated: time.Date(2014, 11, 5, 10, 5, 0, 0, time.UTC),
+		}, {
+			Status:  params.StatusError,
+			Info:    `hook failed: "install"`,
+			Data:    map[string]interface{}{"hook": "install"},
+			Updated: time.Date(2014, 11, 5, 10, 0, 0, 0, time.UTC),
+		}},
+	}
+	s.PatchValue(&getStatusHistoryAPI, func(c *StatusHistoryCommand) (StatusHistoryAPI, error) {
+		return s.fake, nil
+	})
+}
+
+func (s *StatusHistorySuite) TestArgParsing(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		tag      names.Tag
+		size     int
+		before   time.Time
+		errMatch string
+	}{{
+		errMatch: "no unit or machine specified",
+	}, {
+		args: []string{"mysql/0"},
+		tag:  names.NewUnitTag("mysql/0"),
+		size: 20,
+	}, {
+		args: []string{"0/lxc/1"},
+		tag:  names.NewMachineTag("0/lxc/1"),
+		size: 20,
+	}, {
+		args:     []string{"mysql"},
+		errMatch: `"mysql" is neither a unit nor a machine`,
+	}, {
+		args: []string{"-n", "5", "mysql/0"},
+		tag:  names.NewUnitTag("mysql/0"),
+		size: 5,
+	}, {
+		args:     []string{"-n", "0", "mysql/0"},
+		errMatch: "invalid number of entries 0",
+	}, {
+		args:   []string{"--before", "2014-11-05T10:00:00Z", "mysql/0"},
+		tag:    names.NewUnitTag("mysql/0"),
+		size:   20,
+		before: time.Date(2014, 11, 5, 10, 0, 0, 0, time.UTC),
+	}, {
+		args:   []string{"--before", "2014-11-05T10:00:00.25Z", "mysql/0"},
+		tag:    names.NewUnitTag("mysql/0"),
+		size:   20,
+		before: time.Date(2014, 11, 5, 10, 0, 0, 250000000, time.UTC),
+	}, {
+		args:     []string{"--before", "yesterday", "mysql/0"},
+		errMatch: `invalid --before value: "yesterday" is neither a date nor an RFC 3339 time`,
+	}, {
+		args:     []string{"mysql/0", "extra"},
+		errMatch: `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := &StatusHistoryCommand{}
+		err := testing.InitCommand(envcmd.Wrap(command), test.args)
+		if test.errMatch != "" {
+			c.Check(err, gc.ErrorMatches, test.errMatch)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(command.tag, gc.Equals, test.tag)
+		c.Check(command.size, gc.Equals, test.size)
+		c.Check(command.beforeTime, gc.Equals, test.before)
+	}
+}
+
+func (s *StatusHistorySuite) TestRunTabular(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&StatusHistoryCommand{}), "-n", "2", "mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.tag, gc.Equals, names.NewUnitTag("mysql/0"))
+	c.Assert(s.fake.size, gc.Equals, 2)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"TIME                  STATUS   INFO\n"+
+		"2014-11-05T10:05:00Z  started  \n"+
+		"2014-11-05T10:00:00Z  error    hook failed: \"install\"\n")
+}
+
+func (s *StatusHistorySuite) TestRunYAML(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&StatusHistoryCommand{}), "--format", "yaml", "0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.tag, gc.Equals, names.NewMachineTag("0"))
+	var result []map[string]interface{}
+	err = goyaml.Unmarshal(ctx.Stdout.(*bytes.Buffer).Bytes(), &result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, []map[string]interface{}{{
+		"time":   "2014-11-05T10:05:00Z",
+		"status": "started",
+	}, {
+		"time":   "2014-11-05T10:00:00Z",
+		"status": "error",
+		"info":   `hook failed: "install"`,
+		"data":   map[interface{}]interface{}{"hook": "install"},
+	}})
+}
+
+func (s *StatusHistorySuite) TestRunFractionalSeconds(c *gc.C) {
+	s.fake.history = []params.StatusHistoryEntry{{
+		Status:  params.StatusStarted,
+		Updated: time.Date(2014, 11, 5, 10, 0, 0, 750000000, time.UTC),
+	}, {
+		Status:  params.StatusInstalled,
+		Updated: time.Date(2014, 11, 5, 10, 0, 0, 250000000, time.UTC),
+	}}
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&StatusHistoryCommand{}), "mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"TIME                     STATUS     INFO\n"+
+		"2014-11-05T10:00:00.75Z  started    \n"+
+		"2014-11-05T10:00:00.25Z  installed  \n")
+}
+
+func (s *StatusHistorySuite) TestRunError(c *gc.C) {
+	s.fake.err = errors.New("boom")
+	_, err := testing.RunCommand(c, envcmd.Wrap(&StatusHistoryCommand{}), "mysql/0")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
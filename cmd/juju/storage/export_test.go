@@ -0,0 +1,18 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+// NewListCommand returns a ListCommand with the api provided as specified.
+func NewListCommand(api ListStorageAPI) *ListCommand {
+	return &ListCommand{
+		api: api,
+	}
+}
+
+// NewShowCommand returns a ShowCommand with the api provided as specified.
+func NewShowCommand(api ShowStorageAPI) *ShowCommand {
+	return &ShowCommand{
+		api: api,
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const listCommandDoc = `
+List the storage instances created for units in the environment, along
+with the location at which each is mounted on its unit's machine once
+it has been provisioned.
+
+Examples:
+    juju storage list
+    juju storage list --format yaml
+`
+
+// ListCommand lists the storage instances in the environment.
+type ListCommand struct {
+	envcmd.EnvCommandBase
+	api ListStorageAPI
+	out cmd.Output
+}
+
+// ListStorageAPI defines the API methods that the storage list
+// command uses.
+type ListStorageAPI interface {
+	ListStorage() ([]params.StorageInstance, error)
+	Close() error
+}
+
+// Info implements Command.Info.
+func (c *ListCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Purpose: "list storage instances",
+		Doc:     listCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *ListCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatListTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *ListCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *ListCommand) getListStorageAPI() (ListStorageAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewAPIClient()
+}
+
+// Run implements Command.Run.
+func (c *ListCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getListStorageAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	instances, err := client.ListStorage()
+	if err != nil {
+		return err
+	}
+	return c.out.Write(ctx, formatStorageInstances(instances))
+}
+
+func formatListTabular(value interface{}) ([]byte, error) {
+	instances, ok := value.(map[string]storageInstance)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", instances, value)
+	}
+	ids := make([]string, 0, len(instances))
+	for id := range instances {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	fmt.Fprintf(tw, "ID\tOWNER\tSOURCE\tSIZE\tLOCATION\n")
+	for _, id := range ids {
+		instance := instances[id]
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%dM\t%s\n",
+			id, instance.Owner, instance.Source, instance.Size, instance.Location,
+		)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/storage"
+	jujustorage "github.com/juju/juju/storage"
+	"github.com/juju/juju/testing"
+)
+
+type ListSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeStorageAPI
+}
+
+var _ = gc.Suite(&ListSuite{})
+
+type fakeStorageAPI struct {
+	instances []params.StorageInstance
+	ids       []string
+	err       error
+}
+
+func (f *fakeStorageAPI) ListStorage() ([]params.StorageInstance, error) {
+	return f.instances, f.err
+}
+
+func (f *fakeStorageAPI) ShowStorage(ids ...string) ([]params.StorageInstanceResult, error) {
+	f.ids = ids
+	if f.err != nil {
+		return nil, f.err
+	}
+	results := make([]params.StorageInstanceResult, len(ids))
+	for i, id := range ids {
+		results[i].Error = &params.Error{
+			Code:    params.CodeNotFound,
+			Message: "storage instance " + id + " not found",
+		}
+		for _, instance := range f.instances {
+			if instance.Id == id {
+				results[i] = params.StorageInstanceResult{Result: instance}
+			}
+		}
+	}
+	return results, nil
+}
+
+func (f *fakeStorageAPI) Close() error {
+	return nil
+}
+
+func newFakeStorageAPI() *fakeStorageAPI {
+	return &fakeStorageAPI{
+		instances: []params.StorageInstance{{
+			Id:       "data/0",
+			Name:     "data",
+			Owner:    "postgresql/0",
+			Source:   "loop",
+			Size:     1024,
+			Location: "/var/lib/juju/storage/data/0",
+			Filesystem: &jujustorage.Filesystem{
+				Type: "ext4",
+			},
+		}, {
+			Id:     "data/1",
+			Name:   "data",
+			Owner:  "postgresql/1",
+			Source: "loop",
+			Size:   1024,
+		}},
+	}
+}
+
+func (s *ListSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = newFakeStorageAPI()
+}
+
+func (s *ListSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(storage.NewListCommand(s.fake)), args...)
+}
+
+func (s *ListSuite) TestInit(c *gc.C) {
+	_, err := s.run(c, "data/0")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["data/0"\]`)
+}
+
+func (s *ListSuite) TestListTabular(c *gc.C) {
+	ctx, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"ID      OWNER         SOURCE  SIZE   LOCATION\n"+
+		"data/0  postgresql/0  loop    1024M  /var/lib/juju/storage/data/0\n"+
+		"data/1  postgresql/1  loop    1024M  \n")
+}
+
+func (s *ListSuite) TestListYAML(c *gc.C) {
+	ctx, err := s.run(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"data/0:\n"+
+		"  owner: postgresql/0\n"+
+		"  source: loop\n"+
+		"  size: 1024\n"+
+		"  location: /var/lib/juju/storage/data/0\n"+
+		"  filesystem: ext4\n"+
+		"data/1:\n"+
+		"  owner: postgresql/1\n"+
+		"  source: loop\n"+
+		"  size: 1024\n")
+}
+
+func (s *ListSuite) TestListError(c *gc.C) {
+	s.fake.err = errors.New("boom")
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -0,0 +1,90 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const showCommandDoc = `
+Show the details of one or more storage instances, given their ids as
+displayed by "juju storage list".
+
+Examples:
+    juju storage show data/0
+    juju storage show data/0 logs/1 --format json
+`
+
+// ShowCommand shows the details of storage instances.
+type ShowCommand struct {
+	envcmd.EnvCommandBase
+	api ShowStorageAPI
+	out cmd.Output
+	ids []string
+}
+
+// ShowStorageAPI defines the API methods that the storage show
+// command uses.
+type ShowStorageAPI interface {
+	ShowStorage(ids ...string) ([]params.StorageInstanceResult, error)
+	Close() error
+}
+
+// Info implements Command.Info.
+func (c *ShowCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "show",
+		Args:    "<storage id> ...",
+		Purpose: "show the details of storage instances",
+		Doc:     showCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *ShowCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+// Init implements Command.Init.
+func (c *ShowCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no storage instances specified")
+	}
+	c.ids = args
+	return nil
+}
+
+func (c *ShowCommand) getShowStorageAPI() (ShowStorageAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewAPIClient()
+}
+
+// Run implements Command.Run.
+func (c *ShowCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getShowStorageAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	results, err := client.ShowStorage(c.ids...)
+	if err != nil {
+		return err
+	}
+	instances := make([]params.StorageInstance, 0, len(results))
+	for i, result := range results {
+		if result.Error != nil {
+			return errors.Annotatef(result.Error, "cannot show storage instance %q", c.ids[i])
+		}
+		instances = append(instances, result.Result)
+	}
+	return c.out.Write(ctx, formatStorageInstances(instances))
+}
@@ -0,0 +1,53 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/storage"
+	"github.com/juju/juju/testing"
+)
+
+type ShowSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeStorageAPI
+}
+
+var _ = gc.Suite(&ShowSuite{})
+
+func (s *ShowSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = newFakeStorageAPI()
+}
+
+func (s *ShowSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(storage.NewShowCommand(s.fake)), args...)
+}
+
+func (s *ShowSuite) TestInit(c *gc.C) {
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "no storage instances specified")
+}
+
+func (s *ShowSuite) TestShow(c *gc.C) {
+	ctx, err := s.run(c, "data/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.ids, jc.DeepEquals, []string{"data/0"})
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"data/0:\n"+
+		"  owner: postgresql/0\n"+
+		"  source: loop\n"+
+		"  size: 1024\n"+
+		"  location: /var/lib/juju/storage/data/0\n"+
+		"  filesystem: ext4\n")
+}
+
+func (s *ShowSuite) TestShowNotFound(c *gc.C) {
+	_, err := s.run(c, "data/0", "logs/0")
+	c.Assert(err, gc.ErrorMatches, `cannot show storage instance "logs/0": storage instance logs/0 not found`)
+}
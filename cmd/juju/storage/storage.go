@@ -0,0 +1,66 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const storageCommandDoc = `
+"juju storage" provides commands to inspect the storage instances created
+for units. Storage instances are requested for each unit of a service with
+the --storage argument of "juju deploy".
+`
+
+const storageCommandPurpose = "inspect storage instances"
+
+// NewSuperCommand creates the storage supercommand and registers the
+// subcommands that it supports.
+func NewSuperCommand() cmd.Command {
+	storageCmd := cmd.NewSuperCommand(cmd.SuperCommandParams{
+		Name:        "storage",
+		Doc:         storageCommandDoc,
+		UsagePrefix: "juju",
+		Purpose:     storageCommandPurpose,
+	})
+	storageCmd.Register(envcmd.Wrap(&ListCommand{}))
+	storageCmd.Register(envcmd.Wrap(&ShowCommand{}))
+	return storageCmd
+}
+
+// storageInstance holds the details of a storage instance formatted
+// for output.
+type storageInstance struct {
+	Owner      string   `yaml:"owner" json:"owner"`
+	Source     string   `yaml:"source" json:"source"`
+	Size       uint64   `yaml:"size" json:"size"`
+	Options    string   `yaml:"options,omitempty" json:"options,omitempty"`
+	Location   string   `yaml:"location,omitempty" json:"location,omitempty"`
+	Filesystem string   `yaml:"filesystem,omitempty" json:"filesystem,omitempty"`
+	MountOpts  []string `yaml:"mount-options,omitempty" json:"mount-options,omitempty"`
+}
+
+// formatStorageInstances returns the given storage instances formatted
+// for output, keyed by id.
+func formatStorageInstances(instances []params.StorageInstance) map[string]storageInstance {
+	formatted := make(map[string]storageInstance, len(instances))
+	for _, instance := range instances {
+		info := storageInstance{
+			Owner:    instance.Owner,
+			Source:   instance.Source,
+			Size:     instance.Size,
+			Options:  instance.Options,
+			Location: instance.Location,
+		}
+		if instance.Filesystem != nil {
+			info.Filesystem = instance.Filesystem.Type
+			info.MountOpts = instance.Filesystem.MountOptions
+		}
+		formatted[instance.Id] = info
+	}
+	return formatted
+}
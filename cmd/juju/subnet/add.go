@@ -0,0 +1,113 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package subnet
+
+import (
+	"net"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/network"
+)
+
+const addCommandDoc = `
+Add a subnet to juju, given its CIDR. If a space is given, the subnet is
+added to that space, which must already exist.
+
+The provider-specific id of the subnet, its VLAN tag and the availability
+zone it is in can optionally be given. Subnets without a provider id are
+not used when starting machines with a spaces constraint.
+
+Examples:
+    juju subnet add 10.0.1.0/24
+    juju subnet add 10.0.1.0/24 db --provider-id subnet-42 --zone us-east-1a
+`
+
+// AddCommand adds a subnet to juju.
+type AddCommand struct {
+	envcmd.EnvCommandBase
+	api AddSubnetAPI
+
+	ProviderId string
+	VLANTag    int
+	Zone       string
+
+	CIDR  string
+	Space string
+}
+
+// AddSubnetAPI defines the API methods that the subnet add command
+// uses.
+type AddSubnetAPI interface {
+	AddSubnet(args params.AddSubnet) error
+	Close() error
+}
+
+// Info implements Command.Info.
+func (c *AddCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "add",
+		Args:    "<CIDR> [<space>]",
+		Purpose: "add a subnet",
+		Doc:     addCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *AddCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.ProviderId, "provider-id", "", "the provider-specific id of the subnet")
+	f.IntVar(&c.VLANTag, "vlan-tag", 0, "the VLAN tag of the subnet, if it is a VLAN")
+	f.StringVar(&c.Zone, "zone", "", "the availability zone the subnet is in")
+}
+
+// Init implements Command.Init.
+func (c *AddCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no CIDR specified")
+	}
+	if _, _, err := net.ParseCIDR(args[0]); err != nil {
+		return errors.Errorf("%q is not a valid CIDR", args[0])
+	}
+	c.CIDR = args[0]
+	args = args[1:]
+	if len(args) > 0 {
+		if !network.IsValidSpace(args[0]) {
+			return errors.Errorf("%q is not a valid space name", args[0])
+		}
+		c.Space = args[0]
+		args = args[1:]
+	}
+	if c.VLANTag < 0 || c.VLANTag > 4094 {
+		return errors.Errorf("invalid VLAN tag %d: must be between 0 and 4094", c.VLANTag)
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func (c *AddCommand) getAddSubnetAPI() (AddSubnetAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewAPIClient()
+}
+
+// Run implements Command.Run.
+func (c *AddCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAddSubnetAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	return client.AddSubnet(params.AddSubnet{
+		CIDR:       c.CIDR,
+		ProviderId: c.ProviderId,
+		VLANTag:    c.VLANTag,
+		Zone:       c.Zone,
+		Space:      c.Space,
+	})
+}
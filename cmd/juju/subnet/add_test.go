@@ -0,0 +1,99 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package subnet_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/subnet"
+	"github.com/juju/juju/testing"
+)
+
+type AddSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeSubnetAPI
+}
+
+var _ = gc.Suite(&AddSuite{})
+
+type fakeSubnetAPI struct {
+	added   []params.AddSubnet
+	subnets []params.Subnet
+	err     error
+}
+
+func (f *fakeSubnetAPI) AddSubnet(args params.AddSubnet) error {
+	f.added = append(f.added, args)
+	return f.err
+}
+
+func (f *fakeSubnetAPI) ListSubnets() ([]params.Subnet, error) {
+	return f.subnets, f.err
+}
+
+func (f *fakeSubnetAPI) Close() error {
+	return nil
+}
+
+func (s *AddSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeSubnetAPI{}
+}
+
+func (s *AddSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(subnet.NewAddCommand(s.fake)), args...)
+}
+
+func (s *AddSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		errMatch string
+	}{{
+		errMatch: "no CIDR specified",
+	}, {
+		args:     []string{"10.0.0.0"},
+		errMatch: `"10.0.0.0" is not a valid CIDR`,
+	}, {
+		args:     []string{"10.0.0.0/24", "DB"},
+		errMatch: `"DB" is not a valid space name`,
+	}, {
+		args:     []string{"--vlan-tag", "4095", "10.0.0.0/24"},
+		errMatch: "invalid VLAN tag 4095: must be between 0 and 4094",
+	}, {
+		args:     []string{"10.0.0.0/24", "db", "extra"},
+		errMatch: `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := s.run(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.errMatch)
+	}
+	c.Assert(s.fake.added, gc.HasLen, 0)
+}
+
+func (s *AddSuite) TestAdd(c *gc.C) {
+	_, err := s.run(c, "10.0.0.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.run(c, "--provider-id", "subnet-1", "--vlan-tag", "42", "--zone", "zone1", "10.0.1.0/24", "db")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.added, jc.DeepEquals, []params.AddSubnet{{
+		CIDR: "10.0.0.0/24",
+	}, {
+		CIDR:       "10.0.1.0/24",
+		ProviderId: "subnet-1",
+		VLANTag:    42,
+		Zone:       "zone1",
+		Space:      "db",
+	}})
+}
+
+func (s *AddSuite) TestAddError(c *gc.C) {
+	s.fake.err = errors.New("boom")
+	_, err := s.run(c, "10.0.0.0/24")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -0,0 +1,18 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package subnet
+
+// NewAddCommand returns an AddCommand with the api provided as specified.
+func NewAddCommand(api AddSubnetAPI) *AddCommand {
+	return &AddCommand{
+		api: api,
+	}
+}
+
+// NewListCommand returns a ListCommand with the api provided as specified.
+func NewListCommand(api ListSubnetsAPI) *ListCommand {
+	return &ListCommand{
+		api: api,
+	}
+}
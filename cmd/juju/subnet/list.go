@@ -0,0 +1,134 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package subnet
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const listCommandDoc = `
+List the subnets known to juju, along with the space each belongs to.
+
+Examples:
+    juju subnet list
+    juju subnet list --format yaml
+`
+
+// ListCommand lists the subnets known to juju.
+type ListCommand struct {
+	envcmd.EnvCommandBase
+	api ListSubnetsAPI
+	out cmd.Output
+}
+
+// ListSubnetsAPI defines the API methods that the subnet list command
+// uses.
+type ListSubnetsAPI interface {
+	ListSubnets() ([]params.Subnet, error)
+	Close() error
+}
+
+// Info implements Command.Info.
+func (c *ListCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Purpose: "list subnets",
+		Doc:     listCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *ListCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatListTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *ListCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *ListCommand) getListSubnetsAPI() (ListSubnetsAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewAPIClient()
+}
+
+// subnetInfo holds the details of a subnet formatted for output.
+type subnetInfo struct {
+	ProviderId string `yaml:"provider-id,omitempty" json:"provider-id,omitempty"`
+	VLANTag    int    `yaml:"vlan-tag,omitempty" json:"vlan-tag,omitempty"`
+	Zone       string `yaml:"zone,omitempty" json:"zone,omitempty"`
+	Space      string `yaml:"space,omitempty" json:"space,omitempty"`
+	Life       string `yaml:"life" json:"life"`
+}
+
+// Run implements Command.Run.
+func (c *ListCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getListSubnetsAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	subnets, err := client.ListSubnets()
+	if err != nil {
+		return err
+	}
+	formatted := make(map[string]subnetInfo, len(subnets))
+	for _, subnet := range subnets {
+		formatted[subnet.CIDR] = subnetInfo{
+			ProviderId: subnet.ProviderId,
+			VLANTag:    subnet.VLANTag,
+			Zone:       subnet.Zone,
+			Space:      subnet.Space,
+			Life:       string(subnet.Life),
+		}
+	}
+	return c.out.Write(ctx, formatted)
+}
+
+func formatListTabular(value interface{}) ([]byte, error) {
+	subnets, ok := value.(map[string]subnetInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", subnets, value)
+	}
+	cidrs := make([]string, 0, len(subnets))
+	for cidr := range subnets {
+		cidrs = append(cidrs, cidr)
+	}
+	sort.Strings(cidrs)
+
+	var out bytes.Buffer
+	const (
+		// To format things into columns.
+		minwidth = 0
+		tabwidth = 1
+		padding  = 2
+		padchar  = ' '
+		flags    = 0
+	)
+	tw := tabwriter.NewWriter(&out, minwidth, tabwidth, padding, padchar, flags)
+	fmt.Fprintf(tw, "CIDR\tSPACE\tZONE\tPROVIDER-ID\n")
+	for _, cidr := range cidrs {
+		subnet := subnets[cidr]
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", cidr, subnet.Space, subnet.Zone, subnet.ProviderId)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package subnet_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/subnet"
+	"github.com/juju/juju/testing"
+)
+
+type ListSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeSubnetAPI
+}
+
+var _ = gc.Suite(&ListSuite{})
+
+func (s *ListSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeSubnetAPI{
+		subnets: []params.Subnet{{
+			CIDR:       "10.0.1.0/24",
+			ProviderId: "subnet-1",
+			Zone:       "zone1",
+			Space:      "db",
+			Life:       params.Alive,
+		}, {
+			CIDR: "10.0.0.0/24",
+			Life: params.Alive,
+		}},
+	}
+}
+
+func (s *ListSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(subnet.NewListCommand(s.fake)), args...)
+}
+
+func (s *ListSuite) TestInit(c *gc.C) {
+	_, err := s.run(c, "10.0.0.0/24")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["10.0.0.0/24"\]`)
+}
+
+func (s *ListSuite) TestListTabular(c *gc.C) {
+	ctx, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"CIDR         SPACE  ZONE   PROVIDER-ID\n"+
+		"10.0.0.0/24                \n"+
+		"10.0.1.0/24  db     zone1  subnet-1\n")
+}
+
+func (s *ListSuite) TestListYAML(c *gc.C) {
+	ctx, err := s.run(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"10.0.0.0/24:\n"+
+		"  life: alive\n"+
+		"10.0.1.0/24:\n"+
+		"  provider-id: subnet-1\n"+
+		"  zone: zone1\n"+
+		"  space: db\n"+
+		"  life: alive\n")
+}
+
+func (s *ListSuite) TestListError(c *gc.C) {
+	s.fake.err = errors.New("boom")
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
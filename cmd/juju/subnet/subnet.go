@@ -0,0 +1,33 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package subnet
+
+import (
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const subnetCommandDoc = `
+"juju subnet" provides commands to manage the subnets known to juju.
+Subnets can be grouped into spaces (see "juju help space"), which can then
+be used with the spaces constraint to choose the subnets a machine is
+started in.
+`
+
+const subnetCommandPurpose = "manage subnets"
+
+// NewSuperCommand creates the subnet supercommand and registers the
+// subcommands that it supports.
+func NewSuperCommand() cmd.Command {
+	subnetCmd := cmd.NewSuperCommand(cmd.SuperCommandParams{
+		Name:        "subnet",
+		Doc:         subnetCommandDoc,
+		UsagePrefix: "juju",
+		Purpose:     subnetCommandPurpose,
+	})
+	subnetCmd.Register(envcmd.Wrap(&AddCommand{}))
+	subnetCmd.Register(envcmd.Wrap(&ListCommand{}))
+	return subnetCmd
+}
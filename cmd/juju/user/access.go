@@ -0,0 +1,154 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+)
+
+const grantAccessDoc = `
+Grant a user a level of access to the current environment. The level of
+access is one of:
+
+  read   the user can inspect the environment, but not change it
+  write  the user can also deploy and manage services and machines
+  admin  the user can also change the environment settings, upgrade or
+         destroy the environment, and control who else may access it
+
+If the user cannot yet access the environment, it is shared with them.
+
+Examples:
+  juju user grant foobar read
+  juju user grant foobar@ubuntuone admin
+
+See Also:
+  juju user revoke
+`
+
+const revokeAccessDoc = `
+Revoke a level of access to the current environment from a user, leaving
+them with the level below it. Revoking read access removes the user's
+access to the environment altogether.
+
+Examples:
+  juju user revoke foobar admin    (the user keeps write access)
+  juju user revoke foobar read     (the user can no longer log in)
+
+See Also:
+  juju user grant
+`
+
+// accessLevels lists the levels of environment access that may be
+// granted or revoked.
+var accessLevels = []string{"read", "write", "admin"}
+
+// AccessCommandBase holds the common code for the grant and revoke
+// commands.
+type AccessCommandBase struct {
+	UserCommandBase
+	user   names.UserTag
+	access string
+}
+
+// GrantCommand grants users access to an environment.
+type GrantCommand struct {
+	AccessCommandBase
+}
+
+// RevokeCommand revokes users' access to an environment.
+type RevokeCommand struct {
+	AccessCommandBase
+}
+
+// Info implements Command.Info.
+func (c *GrantCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "grant",
+		Args:    "<username> <read|write|admin>",
+		Purpose: "grant a user access to the current environment",
+		Doc:     grantAccessDoc,
+	}
+}
+
+// Info implements Command.Info.
+func (c *RevokeCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "revoke",
+		Args:    "<username> <read|write|admin>",
+		Purpose: "revoke a user's access to the current environment",
+		Doc:     revokeAccessDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *AccessCommandBase) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no username supplied")
+	}
+	if !names.IsValidUser(args[0]) {
+		return errors.NotValidf("username %q", args[0])
+	}
+	c.user = names.NewUserTag(args[0])
+	if len(args) == 1 {
+		return errors.New("no access level supplied")
+	}
+	c.access = args[1]
+	if !isAccessLevel(c.access) {
+		return errors.Errorf("access level %q not valid; expected one of read, write or admin", c.access)
+	}
+	return cmd.CheckEmpty(args[2:])
+}
+
+func isAccessLevel(access string) bool {
+	for _, level := range accessLevels {
+		if access == level {
+			return true
+		}
+	}
+	return false
+}
+
+// EnvironmentAccessAPI defines the API methods that the grant and
+// revoke commands use.
+type EnvironmentAccessAPI interface {
+	GrantEnvironmentAccess(user names.UserTag, access string) error
+	RevokeEnvironmentAccess(user names.UserTag, access string) error
+	Close() error
+}
+
+func (c *AccessCommandBase) getEnvironmentAccessAPI() (EnvironmentAccessAPI, error) {
+	return c.NewAPIClient()
+}
+
+var getEnvironmentAccessAPI = (*AccessCommandBase).getEnvironmentAccessAPI
+
+// Run implements Command.Run.
+func (c *GrantCommand) Run(ctx *cmd.Context) error {
+	client, err := getEnvironmentAccessAPI(&c.AccessCommandBase)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if err := client.GrantEnvironmentAccess(c.user, c.access); err != nil {
+		return errors.Trace(err)
+	}
+	ctx.Infof("Granted %s access to user %q", c.access, c.user.Username())
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *RevokeCommand) Run(ctx *cmd.Context) error {
+	client, err := getEnvironmentAccessAPI(&c.AccessCommandBase)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if err := client.RevokeEnvironmentAccess(c.user, c.access); err != nil {
+		return errors.Trace(err)
+	}
+	ctx.Infof("Revoked %s access from user %q", c.access, c.user.Username())
+	return nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/user"
+	"github.com/juju/juju/testing"
+)
+
+type AccessSuite struct {
+	BaseSuite
+	mock mockEnvironmentAccessAPI
+}
+
+var _ = gc.Suite(&AccessSuite{})
+
+func (s *AccessSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.mock = mockEnvironmentAccessAPI{}
+	s.PatchValue(user.GetEnvironmentAccessAPI, func(*user.AccessCommandBase) (user.EnvironmentAccessAPI, error) {
+		return &s.mock, nil
+	})
+}
+
+func (s *AccessSuite) grantCommand() cmd.Command {
+	return envcmd.Wrap(&user.GrantCommand{})
+}
+
+func (s *AccessSuite) revokeCommand() cmd.Command {
+	return envcmd.Wrap(&user.RevokeCommand{})
+}
+
+func (s *AccessSuite) testInit(c *gc.C, command user.AccessCommand) {
+	for i, test := range []struct {
+		args     []string
+		errMatch string
+		user     names.UserTag
+		access   string
+	}{
+		{
+			errMatch: "no username supplied",
+		}, {
+			args:     []string{"not/valid"},
+			errMatch: `username "not/valid" not valid`,
+		}, {
+			args:     []string{"bob"},
+			errMatch: "no access level supplied",
+		}, {
+			args:     []string{"bob", "superuser"},
+			errMatch: `access level "superuser" not valid; expected one of read, write or admin`,
+		}, {
+			args:     []string{"bob", "read", "extra"},
+			errMatch: `unrecognized args: \["extra"\]`,
+		}, {
+			args:   []string{"bob", "read"},
+			user:   names.NewLocalUserTag("bob"),
+			access: "read",
+		}, {
+			args:   []string{"bob@ubuntuone", "admin"},
+			user:   names.NewUserTag("bob@ubuntuone"),
+			access: "admin",
+		},
+	} {
+		c.Logf("test %d, args %v", i, test.args)
+		err := testing.InitCommand(command, test.args)
+		if test.errMatch == "" {
+			c.Assert(err, jc.ErrorIsNil)
+			c.Assert(command.User(), gc.Equals, test.user)
+			c.Assert(command.Access(), gc.Equals, test.access)
+		} else {
+			c.Assert(err, gc.ErrorMatches, test.errMatch)
+		}
+	}
+}
+
+func (s *AccessSuite) TestInit(c *gc.C) {
+	s.testInit(c, &user.GrantCommand{})
+	s.testInit(c, &user.RevokeCommand{})
+}
+
+func (s *AccessSuite) TestGrant(c *gc.C) {
+	ctx, err := testing.RunCommand(c, s.grantCommand(), "bob", "write")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mock.granted, gc.Equals, names.NewLocalUserTag("bob"))
+	c.Assert(s.mock.access, gc.Equals, "write")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "Granted write access to user \"bob@local\"\n")
+}
+
+func (s *AccessSuite) TestRevoke(c *gc.C) {
+	ctx, err := testing.RunCommand(c, s.revokeCommand(), "bob", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mock.revoked, gc.Equals, names.NewLocalUserTag("bob"))
+	c.Assert(s.mock.access, gc.Equals, "admin")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "Revoked admin access from user \"bob@local\"\n")
+}
+
+func (s *AccessSuite) TestGrantFails(c *gc.C) {
+	s.mock.err = errors.New("permission denied")
+	_, err := testing.RunCommand(c, s.grantCommand(), "bob", "write")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+type mockEnvironmentAccessAPI struct {
+	granted names.UserTag
+	revoked names.UserTag
+	access  string
+	err     error
+}
+
+var _ user.EnvironmentAccessAPI = (*mockEnvironmentAccessAPI)(nil)
+
+func (m *mockEnvironmentAccessAPI) Close() error {
+	return nil
+}
+
+func (m *mockEnvironmentAccessAPI) GrantEnvironmentAccess(user names.UserTag, access string) error {
+	m.granted, m.access = user, access
+	return m.err
+}
+
+func (m *mockEnvironmentAccessAPI) RevokeEnvironmentAccess(user names.UserTag, access string) error {
+	m.revoked, m.access = user, access
+	return m.err
+}
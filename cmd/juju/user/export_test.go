@@ -5,6 +5,7 @@ package user
 
 import (
 	"github.com/juju/cmd"
+	"github.com/juju/names"
 )
 
 var (
@@ -18,6 +19,8 @@ var (
 	GetConnectionCredentials = &getConnectionCredentials
 	// disable and enable
 	GetDisableUserAPI = &getDisableUserAPI
+	// grant and revoke
+	GetEnvironmentAccessAPI = &getEnvironmentAccessAPI
 
 	UserFriendlyDuration = userFriendlyDuration
 )
@@ -36,6 +39,26 @@ func (c *EnableCommand) Username() string {
 	return c.user
 }
 
+// AccessCommand is used for testing both Grant and Revoke commands.
+type AccessCommand interface {
+	cmd.Command
+	User() names.UserTag
+	Access() string
+}
+
+func (c *AccessCommandBase) User() names.UserTag {
+	return c.user
+}
+
+func (c *AccessCommandBase) Access() string {
+	return c.access
+}
+
+var (
+	_ AccessCommand = (*GrantCommand)(nil)
+	_ AccessCommand = (*RevokeCommand)(nil)
+)
+
 var (
 	_ DisenableCommand = (*DisableCommand)(nil)
 	_ DisenableCommand = (*EnableCommand)(nil)
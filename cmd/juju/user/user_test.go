@@ -27,9 +27,11 @@ var expectedUserCommmandNames = []string{
 	"change-password",
 	"disable",
 	"enable",
+	"grant",
 	"help",
 	"info",
 	"list",
+	"revoke",
 }
 
 func (s *UserCommandSuite) TestHelp(c *gc.C) {
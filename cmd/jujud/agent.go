@@ -14,6 +14,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/names"
 	"github.com/juju/utils"
+	"github.com/juju/utils/featureflag"
 	"github.com/juju/utils/fslock"
 	"launchpad.net/gnuflag"
 
@@ -23,12 +24,14 @@ import (
 	apideployer "github.com/juju/juju/api/deployer"
 	apirsyslog "github.com/juju/juju/api/rsyslog"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/feature"
 	"github.com/juju/juju/juju/paths"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/deployer"
+	"github.com/juju/juju/worker/logsender"
 	"github.com/juju/juju/worker/rsyslog"
 	"github.com/juju/juju/worker/upgrader"
 )
@@ -388,6 +391,24 @@ var newRsyslogConfigWorker = func(st *apirsyslog.State, agentConfig agent.Config
 	return rsyslog.NewRsyslogConfigWorker(st, mode, tag, namespace, addrs)
 }
 
+// newLogSender creates and returns a worker that sends the agent's
+// buffered log records to the API server.
+var newLogSender = logsender.New
+
+// logSenderBufferSize holds the number of log records an agent
+// buffers while waiting for them to be sent to the API server.
+const logSenderBufferSize = 10000
+
+// installBufferedLogWriter registers a log writer that buffers the
+// agent's log records for the log sender worker, if the db-log
+// feature flag is set. Otherwise it returns nil.
+func installBufferedLogWriter() (*logsender.BufferedLogWriter, error) {
+	if !featureflag.Enabled(feature.DbLog) {
+		return nil, nil
+	}
+	return logsender.InstallBufferedLogWriter(logSenderBufferSize)
+}
+
 // hookExecutionLock returns an *fslock.Lock suitable for use as a unit
 // hook execution lock. Other workers may also use this lock if they
 // require isolation from hook execution.
@@ -33,10 +33,13 @@ import (
 	"github.com/juju/juju/apiserver"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/jujud/reboot"
+	"github.com/juju/juju/container/docker"
 	"github.com/juju/juju/container/kvm"
 	"github.com/juju/juju/container/lxc"
+	"github.com/juju/juju/container/lxd"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/feature"
 	"github.com/juju/juju/instance"
 	jujunames "github.com/juju/juju/juju/names"
 	"github.com/juju/juju/juju/paths"
@@ -48,6 +51,7 @@ import (
 	"github.com/juju/juju/service"
 	"github.com/juju/juju/service/common"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/state/storage"
 	coretools "github.com/juju/juju/tools"
@@ -55,15 +59,19 @@ import (
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/apiaddressupdater"
 	"github.com/juju/juju/worker/authenticationworker"
+	"github.com/juju/juju/worker/backupscheduler"
 	"github.com/juju/juju/worker/certupdater"
 	"github.com/juju/juju/worker/charmrevisionworker"
 	"github.com/juju/juju/worker/cleaner"
+	"github.com/juju/juju/worker/dblogpruner"
 	"github.com/juju/juju/worker/deployer"
 	"github.com/juju/juju/worker/diskmanager"
+	"github.com/juju/juju/worker/envworkermanager"
 	"github.com/juju/juju/worker/firewaller"
 	"github.com/juju/juju/worker/instancepoller"
 	"github.com/juju/juju/worker/localstorage"
 	workerlogger "github.com/juju/juju/worker/logger"
+	"github.com/juju/juju/worker/logsender"
 	"github.com/juju/juju/worker/machiner"
 	"github.com/juju/juju/worker/metricworker"
 	"github.com/juju/juju/worker/minunitsworker"
@@ -75,6 +83,7 @@ import (
 	"github.com/juju/juju/worker/resumer"
 	"github.com/juju/juju/worker/rsyslog"
 	"github.com/juju/juju/worker/singular"
+	"github.com/juju/juju/worker/storageprovisioner"
 	"github.com/juju/juju/worker/terminationworker"
 	"github.com/juju/juju/worker/upgrader"
 )
@@ -103,7 +112,10 @@ var (
 	newNetworker             = networker.NewNetworker
 	newFirewaller            = firewaller.NewFirewaller
 	newDiskManager           = diskmanager.NewWorker
+	newStorageProvisioner    = storageprovisioner.NewWorker
 	newCertificateUpdater    = certupdater.NewCertificateUpdater
+	newDBLogPruner           = dblogpruner.New
+	newBackupScheduler       = backupscheduler.New
 
 	// reportOpenedAPI is exposed for tests to know when
 	// the State has been successfully opened.
@@ -152,6 +164,13 @@ func (a *MachineAgent) IsRestoreRunning() bool {
 	return a.restoring
 }
 
+// EndRestore will flag the agent to allow all commands again, after
+// a restore has failed and left the state server untouched.
+func (a *MachineAgent) EndRestore() {
+	a.restoreMode = false
+	a.restoring = false
+}
+
 // MachineAgent is a cmd.Command responsible for running a machine agent.
 type MachineAgent struct {
 	cmd.CommandBase
@@ -166,6 +185,7 @@ type MachineAgent struct {
 	restoring            bool
 	workersStarted       chan struct{}
 	st                   *state.State
+	bufferedLogs         *logsender.BufferedLogWriter
 
 	mongoInitMutex   sync.Mutex
 	mongoInitialized bool
@@ -231,6 +251,11 @@ func (a *MachineAgent) Run(_ *cmd.Context) error {
 	if err := setupLogging(agentConfig); err != nil {
 		return err
 	}
+	bufferedLogs, err := installBufferedLogWriter()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	a.bufferedLogs = bufferedLogs
 	logger.Infof("machine agent %v start (%s [%s])", a.Tag(), version.Current, runtime.Compiler)
 	if flags := featureflag.String(); flags != "" {
 		logger.Warningf("developer feature flags enabled: %s", flags)
@@ -253,7 +278,7 @@ func (a *MachineAgent) Run(_ *cmd.Context) error {
 	})
 	// At this point, all workers will have been configured to start
 	close(a.workersStarted)
-	err := a.runner.Wait()
+	err = a.runner.Wait()
 	switch err {
 	case worker.ErrTerminateAgent:
 		err = a.uninstallAgent(agentConfig)
@@ -321,8 +346,15 @@ func (a *MachineAgent) restoreChanged(st *state.State) error {
 	switch rinfo.Status() {
 	case state.RestorePending:
 		a.PrepareRestore()
+	case state.RestorePartial:
+		// The state server is inconsistent after a partial restore,
+		// so it stays blocked apart from retrying the restore.
+		a.EndRestore()
+		a.PrepareRestore()
 	case state.RestoreInProgress:
 		a.BeginRestore()
+	case state.RestoreFailed:
+		a.EndRestore()
 	}
 	return nil
 }
@@ -497,9 +529,15 @@ func (a *MachineAgent) APIWorker() (worker.Worker, error) {
 		return proxyupdater.New(st.Environment(), writeSystemFiles), nil
 	})
 
-	a.startWorkerAfterUpgrade(runner, "rsyslog", func() (worker.Worker, error) {
-		return newRsyslogConfigWorker(st.Rsyslog(), agentConfig, rsyslogMode)
-	})
+	if a.bufferedLogs != nil {
+		a.startWorkerAfterUpgrade(runner, "logsender", func() (worker.Worker, error) {
+			return newLogSender(a.bufferedLogs.Logs(), st.LogSink()), nil
+		})
+	} else {
+		a.startWorkerAfterUpgrade(runner, "rsyslog", func() (worker.Worker, error) {
+			return newRsyslogConfigWorker(st.Rsyslog(), agentConfig, rsyslogMode)
+		})
+	}
 	a.startWorkerAfterUpgrade(runner, "diskmanager", func() (worker.Worker, error) {
 		api, err := st.DiskManager()
 		if err != nil {
@@ -507,6 +545,14 @@ func (a *MachineAgent) APIWorker() (worker.Worker, error) {
 		}
 		return newDiskManager(diskmanager.DefaultListBlockDevices, api), nil
 	})
+	a.startWorkerAfterUpgrade(runner, "storageprovisioner", func() (worker.Worker, error) {
+		api, err := st.StorageProvisioner()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		storageDir := filepath.Join(agentConfig.DataDir(), "storage")
+		return newStorageProvisioner(api, storageDir), nil
+	})
 
 	// Start networker depending on configuration and job.
 	intrusiveMode := false
@@ -624,6 +670,22 @@ func (a *MachineAgent) setupContainerSupport(runner worker.Runner, st *api.State
 	if err == nil && supportsKvm {
 		supportedContainers = append(supportedContainers, instance.KVM)
 	}
+
+	supportsLXD, err := lxd.IsLXDSupported()
+	if err != nil {
+		logger.Warningf("determining lxd support: %v\nno lxd containers possible", err)
+	}
+	if err == nil && supportsLXD {
+		supportedContainers = append(supportedContainers, instance.LXD)
+	}
+
+	supportsDocker, err := docker.IsDockerSupported()
+	if err != nil {
+		logger.Warningf("determining docker support: %v\nno docker containers possible", err)
+	}
+	if err == nil && supportsDocker {
+		supportedContainers = append(supportedContainers, instance.DOCKER)
+	}
 	return a.updateSupportedContainers(runner, st, entity.Tag(), supportedContainers, agentConfig)
 }
 
@@ -761,6 +823,22 @@ func (a *MachineAgent) StateWorker() (worker.Worker, error) {
 			a.startWorkerAfterUpgrade(singularRunner, "minunitsworker", func() (worker.Worker, error) {
 				return minunitsworker.NewMinUnitsWorker(st), nil
 			})
+			if featureflag.Enabled(feature.DbLog) {
+				a.startWorkerAfterUpgrade(singularRunner, "dblogpruner", func() (worker.Worker, error) {
+					return newDBLogPruner(st, dblogpruner.NewLogPruneParams()), nil
+				})
+			}
+			a.startWorkerAfterUpgrade(singularRunner, "backupscheduler", func() (worker.Worker, error) {
+				backupPaths := backups.Paths{
+					DataDir: agentConfig.DataDir(),
+					LogsDir: agentConfig.LogDir(),
+				}
+				backupper := backupscheduler.NewStateBackupper(st, backupPaths, m.Id())
+				return newBackupScheduler(st, backupper), nil
+			})
+			a.startWorkerAfterUpgrade(singularRunner, "env-worker-manager", func() (worker.Worker, error) {
+				return envworkermanager.NewEnvWorkerManager(st, a.envWorkersStarter(st)), nil
+			})
 		case state.JobManageStateDeprecated:
 			// Legacy environments may set this, but we ignore it.
 		default:
@@ -770,6 +848,49 @@ func (a *MachineAgent) StateWorker() (worker.Worker, error) {
 	return newCloseWorker(runner, st), nil
 }
 
+// envWorkersStarter returns a function that starts the workers managing
+// a hosted environment: the ones run for the state server's own
+// environment by StateWorker and APIWorker under JobManageEnviron.
+func (a *MachineAgent) envWorkersStarter(st *state.State) envworkermanager.StartEnvWorkerFunc {
+	return func(uuid string) (_ worker.Worker, err error) {
+		agentConfig := a.CurrentConfig()
+		envTag := names.NewEnvironTag(uuid)
+		envSt, err := st.ForEnviron(envTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		defer func() {
+			if err != nil {
+				envSt.Close()
+			}
+		}()
+		envConfig, err := envSt.EnvironConfig()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		info := agentConfig.APIInfo()
+		info.EnvironTag = envTag
+		apiSt, err := apiOpen(info, api.DialOpts{})
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot connect to environment API")
+		}
+
+		runner := newRunner(connectionIsFatal(apiSt), moreImportant)
+		runner.StartWorker("environ-provisioner", func() (worker.Worker, error) {
+			return provisioner.NewEnvironProvisioner(apiSt.Provisioner(), agentConfig), nil
+		})
+		if envConfig.FirewallMode() != config.FwNone {
+			runner.StartWorker("firewaller", func() (worker.Worker, error) {
+				return newFirewaller(apiSt.Firewaller())
+			})
+		}
+		runner.StartWorker("cleaner", func() (worker.Worker, error) {
+			return cleaner.NewCleaner(envSt), nil
+		})
+		return newCloseWorker(newCloseWorker(runner, apiSt), envSt), nil
+	}
+}
+
 // stateWorkerDialOpts is a mongo.DialOpts suitable
 // for use by StateWorker to dial mongo.
 //
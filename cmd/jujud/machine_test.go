@@ -14,6 +14,7 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/loggo"
 	"github.com/juju/names"
 	gitjujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -37,6 +38,7 @@ import (
 	lxctesting "github.com/juju/juju/container/lxc/testing"
 	"github.com/juju/juju/environs/config"
 	envtesting "github.com/juju/juju/environs/testing"
+	"github.com/juju/juju/feature"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju"
 	jujutesting "github.com/juju/juju/juju/testing"
@@ -54,15 +56,19 @@ import (
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/authenticationworker"
+	"github.com/juju/juju/worker/backupscheduler"
 	"github.com/juju/juju/worker/certupdater"
+	"github.com/juju/juju/worker/dblogpruner"
 	"github.com/juju/juju/worker/deployer"
 	"github.com/juju/juju/worker/diskmanager"
 	"github.com/juju/juju/worker/instancepoller"
+	"github.com/juju/juju/worker/logsender"
 	"github.com/juju/juju/worker/networker"
 	"github.com/juju/juju/worker/peergrouper"
 	"github.com/juju/juju/worker/proxyupdater"
 	"github.com/juju/juju/worker/rsyslog"
 	"github.com/juju/juju/worker/singular"
+	"github.com/juju/juju/worker/storageprovisioner"
 	"github.com/juju/juju/worker/upgrader"
 )
 
@@ -1035,6 +1041,68 @@ func (s *MachineSuite) testMachineAgentRsyslogConfigWorker(c *gc.C, job state.Ma
 	})
 }
 
+func (s *MachineSuite) TestMachineAgentRunsLogSenderWithDbLog(c *gc.C) {
+	s.agentSuite.SetFeatureFlags(feature.DbLog)
+	s.agentSuite.AddCleanup(func(*gc.C) { loggo.RemoveWriter("buffered-logs") })
+	s.agentSuite.PatchValue(&newRsyslogConfigWorker, func(_ *apirsyslog.State, _ agent.Config, _ rsyslog.RsyslogMode) (worker.Worker, error) {
+		c.Errorf("rsyslog worker unexpectedly started")
+		return newDummyWorker(), nil
+	})
+	created := make(chan struct{}, 1)
+	s.agentSuite.PatchValue(&newLogSender, func(_ <-chan params.LogRecord, _ logsender.LogSink) worker.Worker {
+		select {
+		case created <- struct{}{}:
+		default:
+		}
+		return newDummyWorker()
+	})
+	s.assertJobWithAPI(c, state.JobHostUnits, func(conf agent.Config, st *api.State) {
+		select {
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timeout while waiting for log sender worker to be created")
+		case <-created:
+		}
+	})
+}
+
+func (s *MachineSuite) TestManageEnvironRunsDBLogPrunerWithDbLog(c *gc.C) {
+	s.agentSuite.SetFeatureFlags(feature.DbLog)
+	s.agentSuite.AddCleanup(func(*gc.C) { loggo.RemoveWriter("buffered-logs") })
+	started := make(chan struct{}, 1)
+	s.agentSuite.PatchValue(&newDBLogPruner, func(_ dblogpruner.LogPruner, _ *dblogpruner.LogPruneParams) worker.Worker {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		return newDummyWorker()
+	})
+	s.assertJobWithState(c, state.JobManageEnviron, func(agent.Config, *state.State) {
+		select {
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timeout while waiting for db log pruner worker to start")
+		case <-started:
+		}
+	})
+}
+
+func (s *MachineSuite) TestManageEnvironRunsBackupScheduler(c *gc.C) {
+	started := make(chan struct{}, 1)
+	s.agentSuite.PatchValue(&newBackupScheduler, func(_ backupscheduler.State, _ backupscheduler.Backupper) worker.Worker {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		return newDummyWorker()
+	})
+	s.assertJobWithState(c, state.JobManageEnviron, func(agent.Config, *state.State) {
+		select {
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timeout while waiting for backup scheduler worker to start")
+		case <-started:
+		}
+	})
+}
+
 func (s *MachineSuite) TestMachineAgentRunsAPIAddressUpdaterWorker(c *gc.C) {
 	// Start the machine agent.
 	m, _, _ := s.primeAgent(c, version.Current, state.JobHostUnits)
@@ -1083,6 +1151,28 @@ func (s *MachineSuite) TestMachineAgentRunsDiskManagerWorker(c *gc.C) {
 	}
 }
 
+func (s *MachineSuite) TestMachineAgentRunsStorageProvisionerWorker(c *gc.C) {
+	// Start the machine agent.
+	m, _, _ := s.primeAgent(c, version.Current, state.JobHostUnits)
+	a := s.newAgent(c, m)
+	go func() { c.Check(a.Run(nil), jc.ErrorIsNil) }()
+	defer func() { c.Check(a.Stop(), jc.ErrorIsNil) }()
+
+	started := make(chan struct{})
+	newWorker := func(storageprovisioner.StorageInstanceAccessor, string) worker.Worker {
+		close(started)
+		return worker.NewNoOpWorker()
+	}
+	s.PatchValue(&newStorageProvisioner, newWorker)
+
+	// Wait for worker to be started.
+	select {
+	case <-started:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timeout while waiting for storageprovisioner worker to start")
+	}
+}
+
 func (s *MachineSuite) TestDiskManagerWorkerUpdatesState(c *gc.C) {
 	expected := []storage.BlockDevice{{DeviceName: "whatever"}}
 	s.PatchValue(&diskmanager.DefaultListBlockDevices, func() ([]storage.BlockDevice, error) {
@@ -1344,6 +1434,23 @@ func (s *MachineSuite) TestMachineAgentRestoreRequiresPrepare(c *gc.C) {
 	c.Assert(a.IsRestoreRunning(), jc.IsFalse)
 }
 
+func (s *MachineSuite) TestMachineAgentEndRestore(c *gc.C) {
+	// Start the machine agent.
+	m, _, _ := s.primeAgent(c, version.Current, state.JobHostUnits)
+	a := s.newAgent(c, m)
+	go func() { c.Check(a.Run(nil), jc.ErrorIsNil) }()
+	defer func() { c.Check(a.Stop(), jc.ErrorIsNil) }()
+	err := a.PrepareRestore()
+	c.Assert(err, jc.ErrorIsNil)
+	err = a.BeginRestore()
+	c.Assert(err, jc.ErrorIsNil)
+	a.EndRestore()
+	c.Assert(a.IsRestorePreparing(), jc.IsFalse)
+	c.Assert(a.IsRestoreRunning(), jc.IsFalse)
+	err = a.PrepareRestore()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 // MachineWithCharmsSuite provides infrastructure for tests which need to
 // work with charms.
 type MachineWithCharmsSuite struct {
@@ -21,6 +21,7 @@ import (
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/apiaddressupdater"
 	workerlogger "github.com/juju/juju/worker/logger"
+	"github.com/juju/juju/worker/logsender"
 	"github.com/juju/juju/worker/proxyupdater"
 	"github.com/juju/juju/worker/rsyslog"
 	"github.com/juju/juju/worker/uniter"
@@ -34,8 +35,9 @@ type UnitAgent struct {
 	cmd.CommandBase
 	tomb tomb.Tomb
 	AgentConf
-	UnitName string
-	runner   worker.Runner
+	UnitName     string
+	runner       worker.Runner
+	bufferedLogs *logsender.BufferedLogWriter
 }
 
 // Info returns usage information for the command.
@@ -82,6 +84,11 @@ func (a *UnitAgent) Run(ctx *cmd.Context) error {
 	if err := setupLogging(agentConfig); err != nil {
 		return err
 	}
+	bufferedLogs, err := installBufferedLogWriter()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	a.bufferedLogs = bufferedLogs
 	agentLogger.Infof("unit agent %v start (%s [%s])", a.Tag().String(), version.Current, runtime.Compiler)
 	if flags := featureflag.String(); flags != "" {
 		logger.Warningf("developer feature flags enabled: %s", flags)
@@ -89,7 +96,7 @@ func (a *UnitAgent) Run(ctx *cmd.Context) error {
 
 	network.InitializeFromConfig(agentConfig)
 	a.runner.StartWorker("api", a.APIWorkers)
-	err := agentDone(a.runner.Wait())
+	err = agentDone(a.runner.Wait())
 	a.tomb.Kill(err)
 	return err
 }
@@ -147,9 +154,15 @@ func (a *UnitAgent) APIWorkers() (worker.Worker, error) {
 		}
 		return apiaddressupdater.NewAPIAddressUpdater(uniterFacade, a), nil
 	})
-	runner.StartWorker("rsyslog", func() (worker.Worker, error) {
-		return newRsyslogConfigWorker(st.Rsyslog(), agentConfig, rsyslog.RsyslogModeForwarding)
-	})
+	if a.bufferedLogs != nil {
+		runner.StartWorker("logsender", func() (worker.Worker, error) {
+			return newLogSender(a.bufferedLogs.Logs(), st.LogSink()), nil
+		})
+	} else {
+		runner.StartWorker("rsyslog", func() (worker.Worker, error) {
+			return newRsyslogConfigWorker(st.Rsyslog(), agentConfig, rsyslog.RsyslogModeForwarding)
+		})
+	}
 	return newCloseWorker(runner, st), nil
 }
 
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/juju/cmd"
+	"github.com/juju/loggo"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/proxy"
@@ -21,8 +22,10 @@ import (
 	agenttools "github.com/juju/juju/agent/tools"
 	apienvironment "github.com/juju/juju/api/environment"
 	apirsyslog "github.com/juju/juju/api/rsyslog"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/environs/config"
 	envtesting "github.com/juju/juju/environs/testing"
+	"github.com/juju/juju/feature"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
@@ -30,6 +33,7 @@ import (
 	"github.com/juju/juju/tools"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/logsender"
 	"github.com/juju/juju/worker/proxyupdater"
 	"github.com/juju/juju/worker/rsyslog"
 	"github.com/juju/juju/worker/upgrader"
@@ -288,6 +292,34 @@ func (s *UnitSuite) TestRsyslogConfigWorker(c *gc.C) {
 	}
 }
 
+func (s *UnitSuite) TestLogSenderWithDbLog(c *gc.C) {
+	s.agentSuite.SetFeatureFlags(feature.DbLog)
+	s.agentSuite.AddCleanup(func(*gc.C) { loggo.RemoveWriter("buffered-logs") })
+	s.agentSuite.PatchValue(&newRsyslogConfigWorker, func(_ *apirsyslog.State, _ agent.Config, _ rsyslog.RsyslogMode) (worker.Worker, error) {
+		c.Errorf("rsyslog worker unexpectedly started")
+		return newDummyWorker(), nil
+	})
+	created := make(chan struct{}, 1)
+	s.agentSuite.PatchValue(&newLogSender, func(_ <-chan params.LogRecord, _ logsender.LogSink) worker.Worker {
+		select {
+		case created <- struct{}{}:
+		default:
+		}
+		return newDummyWorker()
+	})
+
+	_, unit, _, _ := s.primeAgent(c)
+	a := s.newAgent(c, unit)
+	go func() { c.Check(a.Run(nil), gc.IsNil) }()
+	defer func() { c.Check(a.Stop(), gc.IsNil) }()
+
+	select {
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timeout while waiting for log sender worker to be created")
+	case <-created:
+	}
+}
+
 func (s *UnitSuite) TestAgentSetsToolsVersion(c *gc.C) {
 	_, unit, _, _ := s.primeAgent(c)
 	vers := version.Current
@@ -67,6 +67,9 @@ talk to it.
 It verifies that the existing bootstrap instance is
 not running. The given constraints will be used
 to choose the new instance.
+
+This plugin is superseded by "juju backups restore",
+which restores backups through the API.
 `
 
 type restoreCommand struct {
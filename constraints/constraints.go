@@ -15,6 +15,7 @@ import (
 
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju/arch"
+	"github.com/juju/juju/network"
 )
 
 // The following constants list the supported constraint attribute names, as defined
@@ -29,6 +30,8 @@ const (
 	Tags         = "tags"
 	InstanceType = "instance-type"
 	Networks     = "networks"
+	Spaces       = "spaces"
+	Zones        = "zones"
 )
 
 // Value describes a user's requirements of the hardware on which units
@@ -78,6 +81,18 @@ type Value struct {
 	// negative values are accepted, and the difference is the latter
 	// have a "^" prefix to the name.
 	Networks *[]string `json:"networks,omitempty" yaml:"networks,omitempty"`
+
+	// Spaces, if not nil, holds a list of juju space names that the
+	// machine must (or must not) have subnets in. Positive and
+	// negative values are accepted, and the difference is the latter
+	// have a "^" prefix to the name.
+	Spaces *[]string `json:"spaces,omitempty" yaml:"spaces,omitempty"`
+
+	// Zones, if not nil, holds a list of availability zone names in
+	// which the machine may be started. An empty list is treated the
+	// same as a nil (unspecified) list, except an empty list will
+	// override any default zones, where a nil list will not.
+	Zones *[]string `json:"zones,omitempty" yaml:"zones,omitempty"`
 }
 
 // fieldNames records a mapping from the constraint tag to struct field name.
@@ -118,10 +133,22 @@ func (v *Value) HasInstanceType() bool {
 // extractNetworks returns the list of networks to include or exclude
 // (without the "^" prefixes).
 func (v *Value) extractNetworks() (include, exclude []string) {
-	if v.Networks == nil {
+	return extractIncludeExclude(v.Networks)
+}
+
+// extractSpaces returns the list of spaces to include or exclude
+// (without the "^" prefixes).
+func (v *Value) extractSpaces() (include, exclude []string) {
+	return extractIncludeExclude(v.Spaces)
+}
+
+// extractIncludeExclude splits the given names into those to include
+// and those to exclude, stripping the "^" prefixes of the latter.
+func extractIncludeExclude(names *[]string) (include, exclude []string) {
+	if names == nil {
 		return nil, nil
 	}
-	for _, name := range *v.Networks {
+	for _, name := range *names {
 		if strings.HasPrefix(name, "^") {
 			exclude = append(exclude, strings.TrimPrefix(name, "^"))
 		} else {
@@ -151,6 +178,31 @@ func (v *Value) HaveNetworks() bool {
 	return v.Networks != nil && len(*v.Networks) > 0
 }
 
+// IncludeSpaces returns a list of spaces to include when starting a
+// machine, if specified.
+func (v *Value) IncludeSpaces() []string {
+	include, _ := v.extractSpaces()
+	return include
+}
+
+// ExcludeSpaces returns a list of spaces to exclude when starting a
+// machine, if specified. They are given in the spaces constraint with
+// a "^" prefix to the name, which is stripped before returning.
+func (v *Value) ExcludeSpaces() []string {
+	_, exclude := v.extractSpaces()
+	return exclude
+}
+
+// HaveSpaces returns whether any space constraints were specified.
+func (v *Value) HaveSpaces() bool {
+	return v.Spaces != nil && len(*v.Spaces) > 0
+}
+
+// HaveZones returns whether any zone constraints were specified.
+func (v *Value) HaveZones() bool {
+	return v.Zones != nil && len(*v.Zones) > 0
+}
+
 // String expresses a constraints.Value in the language in which it was specified.
 func (v Value) String() string {
 	var strs []string
@@ -191,6 +243,14 @@ func (v Value) String() string {
 		s := strings.Join(*v.Networks, ",")
 		strs = append(strs, "networks="+s)
 	}
+	if v.Spaces != nil {
+		s := strings.Join(*v.Spaces, ",")
+		strs = append(strs, "spaces="+s)
+	}
+	if v.Zones != nil {
+		s := strings.Join(*v.Zones, ",")
+		strs = append(strs, "zones="+s)
+	}
 	return strings.Join(strs, " ")
 }
 
@@ -330,6 +390,10 @@ func (v *Value) setRaw(raw string) error {
 		err = v.setInstanceType(str)
 	case Networks:
 		err = v.setNetworks(str)
+	case Spaces:
+		err = v.setSpaces(str)
+	case Zones:
+		err = v.setZones(str)
 	default:
 		return fmt.Errorf("unknown constraint %q", name)
 	}
@@ -376,6 +440,14 @@ func (v *Value) SetYAML(tag string, value interface{}) bool {
 			if err == nil {
 				err = v.validateNetworks(networks)
 			}
+		case Spaces:
+			var spaces *[]string
+			spaces, err = parseYamlStrings("spaces", val)
+			if err == nil {
+				err = v.validateSpaces(spaces)
+			}
+		case Zones:
+			v.Zones, err = parseYamlStrings("zones", val)
 		default:
 			return false
 		}
@@ -494,6 +566,39 @@ func (v *Value) validateNetworks(networks *[]string) error {
 	return nil
 }
 
+func (v *Value) setSpaces(str string) error {
+	if v.Spaces != nil {
+		return fmt.Errorf("already set")
+	}
+	spaces := parseCommaDelimited(str)
+	if err := v.validateSpaces(spaces); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (v *Value) validateSpaces(spaces *[]string) error {
+	if spaces == nil {
+		return nil
+	}
+	for _, name := range *spaces {
+		name = strings.TrimPrefix(name, "^")
+		if !network.IsValidSpace(name) {
+			return fmt.Errorf("%q is not a valid space name", name)
+		}
+	}
+	v.Spaces = spaces
+	return nil
+}
+
+func (v *Value) setZones(str string) error {
+	if v.Zones != nil {
+		return fmt.Errorf("already set")
+	}
+	v.Zones = parseCommaDelimited(str)
+	return nil
+}
+
 func parseUint64(str string) (*uint64, error) {
 	var value uint64
 	if str != "" {
@@ -525,7 +630,8 @@ func parseSize(str string) (*uint64, error) {
 }
 
 // parseCommaDelimited returns the items in the value s. We expect the
-// tags to be comma delimited strings. It is used for tags and networks.
+// tags to be comma delimited strings. It is used for tags, networks,
+// spaces and zones.
 func parseCommaDelimited(s string) *[]string {
 	if s == "" {
 		return &[]string{}
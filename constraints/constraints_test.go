@@ -280,6 +280,42 @@ var parseConstraintsTests = []struct {
 		args:    []string{"networks="},
 	},
 
+	// spaces
+	{
+		summary: "single space",
+		args:    []string{"spaces=db"},
+	}, {
+		summary: "multiple spaces - positive and negative",
+		args:    []string{"spaces=db,^admin,web-tier"},
+	}, {
+		summary: "no spaces",
+		args:    []string{"spaces="},
+	}, {
+		summary: "invalid space name",
+		args:    []string{"spaces=Db"},
+		err:     `bad "spaces" constraint: "Db" is not a valid space name`,
+	}, {
+		summary: "double set spaces together",
+		args:    []string{"spaces=db spaces=web"},
+		err:     `bad "spaces" constraint: already set`,
+	},
+
+	// zones
+	{
+		summary: "single zone",
+		args:    []string{"zones=us-east-1a"},
+	}, {
+		summary: "multiple zones",
+		args:    []string{"zones=us-east-1a,us-east-1b"},
+	}, {
+		summary: "no zones",
+		args:    []string{"zones="},
+	}, {
+		summary: "double set zones together",
+		args:    []string{"zones=az1 zones=az2"},
+		err:     `bad "zones" constraint: already set`,
+	},
+
 	// instance type
 	{
 		summary: "set instance type",
@@ -373,6 +409,32 @@ func (s *ConstraintsSuite) TestIncludeExcludeAndHaveNetworks(c *gc.C) {
 	c.Check(con.HaveNetworks(), jc.IsTrue)
 }
 
+func (s *ConstraintsSuite) TestIncludeExcludeAndHaveSpaces(c *gc.C) {
+	con := constraints.MustParse("spaces=db,^admin,web,^storage")
+	c.Assert(con.Spaces, gc.Not(gc.IsNil))
+	c.Check(*con.Spaces, gc.HasLen, 4)
+	c.Check(con.IncludeSpaces(), jc.SameContents, []string{"db", "web"})
+	c.Check(con.ExcludeSpaces(), jc.SameContents, []string{"admin", "storage"})
+	c.Check(con.HaveSpaces(), jc.IsTrue)
+	con = constraints.MustParse("mem=4G")
+	c.Check(con.HaveSpaces(), jc.IsFalse)
+	con = constraints.MustParse("mem=4G spaces=")
+	c.Check(con.HaveSpaces(), jc.IsFalse)
+	con = constraints.MustParse("mem=4G spaces=^db")
+	c.Check(con.HaveSpaces(), jc.IsTrue)
+}
+
+func (s *ConstraintsSuite) TestHaveZones(c *gc.C) {
+	con := constraints.MustParse("zones=az1,az2")
+	c.Assert(con.Zones, gc.Not(gc.IsNil))
+	c.Check(*con.Zones, jc.DeepEquals, []string{"az1", "az2"})
+	c.Check(con.HaveZones(), jc.IsTrue)
+	con = constraints.MustParse("mem=4G")
+	c.Check(con.HaveZones(), jc.IsFalse)
+	con = constraints.MustParse("mem=4G zones=")
+	c.Check(con.HaveZones(), jc.IsFalse)
+}
+
 func (s *ConstraintsSuite) TestInvalidNetworks(c *gc.C) {
 	invalidNames := []string{
 		"%ne$t", "^net#2", "_", "tcp:ip",
@@ -459,6 +521,12 @@ var constraintsRoundtripTests = []roundTrip{
 	{"Networks1", constraints.Value{Networks: nil}},
 	{"Networks2", constraints.Value{Networks: &[]string{}}},
 	{"Networks3", constraints.Value{Networks: &[]string{"net1", "^net2"}}},
+	{"Spaces1", constraints.Value{Spaces: nil}},
+	{"Spaces2", constraints.Value{Spaces: &[]string{}}},
+	{"Spaces3", constraints.Value{Spaces: &[]string{"db", "^admin"}}},
+	{"Zones1", constraints.Value{Zones: nil}},
+	{"Zones2", constraints.Value{Zones: &[]string{}}},
+	{"Zones3", constraints.Value{Zones: &[]string{"az1", "az2"}}},
 	{"InstanceType1", constraints.Value{InstanceType: strp("")}},
 	{"InstanceType2", constraints.Value{InstanceType: strp("foo")}},
 	{"All", constraints.Value{
@@ -470,6 +538,8 @@ var constraintsRoundtripTests = []roundTrip{
 		RootDisk:     uint64p(24000000000),
 		Tags:         &[]string{"foo", "bar"},
 		Networks:     &[]string{"net1", "^net2"},
+		Spaces:       &[]string{"db", "^admin"},
+		Zones:        &[]string{"az1", "az2"},
 		InstanceType: strp("foo"),
 	}},
 }
@@ -0,0 +1,307 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// apiVersion is the version of the Docker remote API spoken by the client.
+const apiVersion = "v1.18"
+
+// RestartPolicy describes when the Docker daemon restarts a container.
+type RestartPolicy struct {
+	Name string
+}
+
+// HostConfig holds the host-dependent settings of a container.
+type HostConfig struct {
+	Privileged    bool
+	Binds         []string `json:",omitempty"`
+	NetworkMode   string   `json:",omitempty"`
+	RestartPolicy RestartPolicy
+}
+
+// ContainerConfig holds the parameters used to create a container.
+type ContainerConfig struct {
+	Image      string
+	Hostname   string   `json:",omitempty"`
+	Cmd        []string `json:",omitempty"`
+	HostConfig HostConfig
+}
+
+// ContainerSummary is the short description of a container returned
+// when listing containers.
+type ContainerSummary struct {
+	Id     string
+	Names  []string
+	Status string
+}
+
+// Name returns the name of the container, without the leading slash
+// used by the Docker daemon.
+func (c *ContainerSummary) Name() string {
+	if len(c.Names) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(c.Names[0], "/")
+}
+
+// ContainerState holds the runtime state of a container.
+type ContainerState struct {
+	Running bool
+}
+
+// NetworkSettings describes how a container is connected to the network.
+type NetworkSettings struct {
+	IPAddress string
+	Bridge    string
+}
+
+// ContainerInfo is the full description of a container.
+type ContainerInfo struct {
+	Id              string
+	Name            string
+	State           ContainerState
+	NetworkSettings NetworkSettings
+}
+
+// Client talks to a Docker daemon through its remote API over a unix
+// socket.
+type Client struct {
+	socketPath string
+	http       *http.Client
+}
+
+// NewClient returns a client for the Docker daemon listening on the
+// given unix socket.
+func NewClient(socketPath string) *Client {
+	transport := &http.Transport{
+		Dial: func(string, string) (net.Conn, error) {
+			return net.Dial("unix", socketPath)
+		},
+	}
+	return &Client{
+		socketPath: socketPath,
+		http:       &http.Client{Transport: transport},
+	}
+}
+
+// Ping checks that the daemon is reachable.
+func (c *Client) Ping() error {
+	resp, err := c.do("GET", "/_ping", nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// PullImage fetches the given image from its registry.
+func (c *Client) PullImage(repository, tag string) error {
+	query := url.Values{"fromImage": {repository}, "tag": {tag}}
+	resp, err := c.do("POST", "/images/create", query, nil)
+	if err != nil {
+		return errors.Annotatef(err, "cannot pull image %s:%s", repository, tag)
+	}
+	defer resp.Body.Close()
+	if err := readStream(resp.Body); err != nil {
+		return errors.Annotatef(err, "cannot pull image %s:%s", repository, tag)
+	}
+	return nil
+}
+
+// ImageExists reports whether the named image is held by the daemon.
+func (c *Client) ImageExists(name string) (bool, error) {
+	err := c.call("GET", "/images/"+url.QueryEscape(name)+"/json", nil, nil, nil)
+	if errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Annotatef(err, "cannot inspect image %s", name)
+	}
+	return true, nil
+}
+
+// BuildImage builds an image from the given Dockerfile and tags it
+// with name.
+func (c *Client) BuildImage(name, dockerfile string) error {
+	var buf bytes.Buffer
+	archive := tar.NewWriter(&buf)
+	header := &tar.Header{
+		Name: "Dockerfile",
+		Mode: 0644,
+		Size: int64(len(dockerfile)),
+	}
+	if err := archive.WriteHeader(header); err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := archive.Write([]byte(dockerfile)); err != nil {
+		return errors.Trace(err)
+	}
+	if err := archive.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	query := url.Values{"t": {name}, "rm": {"1"}}
+	resp, err := c.send("POST", "/build", query, "application/x-tar", &buf)
+	if err != nil {
+		return errors.Annotatef(err, "cannot build image %s", name)
+	}
+	defer resp.Body.Close()
+	if err := readStream(resp.Body); err != nil {
+		return errors.Annotatef(err, "cannot build image %s", name)
+	}
+	return nil
+}
+
+// readStream consumes the progress messages streamed by the daemon
+// while it pulls or builds an image, any of which may report that the
+// operation has failed.
+func readStream(r io.Reader) error {
+	decoder := json.NewDecoder(r)
+	for {
+		var message struct {
+			Error string `json:"error"`
+		}
+		if err := decoder.Decode(&message); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if message.Error != "" {
+			return errors.New(message.Error)
+		}
+	}
+}
+
+// CreateContainer creates a new container with the given name and
+// returns its id.
+func (c *Client) CreateContainer(name string, config ContainerConfig) (string, error) {
+	query := url.Values{"name": {name}}
+	var result struct {
+		Id string
+	}
+	if err := c.call("POST", "/containers/create", query, config, &result); err != nil {
+		return "", errors.Annotatef(err, "cannot create container %q", name)
+	}
+	return result.Id, nil
+}
+
+// StartContainer starts the named container.
+func (c *Client) StartContainer(name string) error {
+	if err := c.call("POST", "/containers/"+url.QueryEscape(name)+"/start", nil, nil, nil); err != nil {
+		return errors.Annotatef(err, "cannot start container %q", name)
+	}
+	return nil
+}
+
+// StopContainer stops the named container. Stopping a container that
+// is not running is not an error.
+func (c *Client) StopContainer(name string) error {
+	query := url.Values{"t": {"10"}}
+	if err := c.call("POST", "/containers/"+url.QueryEscape(name)+"/stop", query, nil, nil); err != nil {
+		return errors.Annotatef(err, "cannot stop container %q", name)
+	}
+	return nil
+}
+
+// RemoveContainer removes the named container and its volumes.
+func (c *Client) RemoveContainer(name string) error {
+	query := url.Values{"force": {"1"}, "v": {"1"}}
+	if err := c.call("DELETE", "/containers/"+url.QueryEscape(name), query, nil, nil); err != nil {
+		return errors.Annotatef(err, "cannot remove container %q", name)
+	}
+	return nil
+}
+
+// ListContainers returns all containers known to the daemon, whether
+// running or not.
+func (c *Client) ListContainers() ([]ContainerSummary, error) {
+	query := url.Values{"all": {"1"}}
+	var result []ContainerSummary
+	if err := c.call("GET", "/containers/json", query, nil, &result); err != nil {
+		return nil, errors.Annotate(err, "cannot list containers")
+	}
+	return result, nil
+}
+
+// InspectContainer returns the full description of the named container.
+func (c *Client) InspectContainer(name string) (*ContainerInfo, error) {
+	var result ContainerInfo
+	if err := c.call("GET", "/containers/"+url.QueryEscape(name)+"/json", nil, nil, &result); err != nil {
+		return nil, errors.Annotatef(err, "cannot inspect container %q", name)
+	}
+	return &result, nil
+}
+
+// call sends a request to the daemon and decodes any JSON response
+// into result.
+func (c *Client) call(method, path string, query url.Values, body, result interface{}) error {
+	resp, err := c.do(method, path, query, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if result == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return errors.Annotatef(err, "cannot decode Docker response to %s %s", method, path)
+	}
+	return nil
+}
+
+// do sends a request to the daemon with a JSON encoded body, turning
+// error responses into Go errors. The caller is responsible for closing
+// the response body.
+func (c *Client) do(method, path string, query url.Values, body interface{}) (*http.Response, error) {
+	if body == nil {
+		return c.send(method, path, query, "", nil)
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return c.send(method, path, query, "application/json", bytes.NewReader(data))
+}
+
+// send sends a request to the daemon, turning error responses into Go
+// errors. The caller is responsible for closing the response body.
+func (c *Client) send(method, path string, query url.Values, contentType string, body io.Reader) (*http.Response, error) {
+	// The host part of the URL is ignored as we always dial the socket.
+	u := "http://docker/" + apiVersion + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(method, u, body)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot connect to Docker daemon at %q", c.socketPath)
+	}
+	if resp.StatusCode < 300 || resp.StatusCode == http.StatusNotModified {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	data, _ := ioutil.ReadAll(resp.Body)
+	message := strings.TrimSpace(string(data))
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errors.NewNotFound(nil, message)
+	}
+	return nil, errors.New(message)
+}
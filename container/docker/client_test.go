@@ -0,0 +1,141 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package docker_test
+
+import (
+	"path/filepath"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/container/docker"
+	dockertesting "github.com/juju/juju/container/docker/testing"
+)
+
+type ClientSuite struct {
+	dockertesting.TestSuite
+	client *docker.Client
+}
+
+var _ = gc.Suite(&ClientSuite{})
+
+func (s *ClientSuite) SetUpTest(c *gc.C) {
+	s.TestSuite.SetUpTest(c)
+	s.client = docker.NewClient(s.Server.SocketPath)
+}
+
+func (s *ClientSuite) TestPing(c *gc.C) {
+	err := s.client.Ping()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.Server.Requests(), jc.DeepEquals, []string{"GET /_ping"})
+}
+
+func (s *ClientSuite) TestPingNoDaemon(c *gc.C) {
+	client := docker.NewClient(filepath.Join(c.MkDir(), "docker.sock"))
+	err := client.Ping()
+	c.Assert(err, gc.ErrorMatches, `cannot connect to Docker daemon at ".*docker.sock": .*`)
+}
+
+func (s *ClientSuite) TestPullImage(c *gc.C) {
+	err := s.client.PullImage("ubuntu", "trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.Server.HasImage("ubuntu:trusty"), jc.IsTrue)
+}
+
+func (s *ClientSuite) TestPullImageError(c *gc.C) {
+	s.Server.Fail("POST", "/images/create", "registry unavailable")
+	err := s.client.PullImage("ubuntu", "trusty")
+	c.Assert(err, gc.ErrorMatches, "cannot pull image ubuntu:trusty: registry unavailable")
+}
+
+func (s *ClientSuite) TestImageExists(c *gc.C) {
+	exists, err := s.client.ImageExists("ubuntu:trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(exists, jc.IsFalse)
+
+	s.Server.AddImage("ubuntu:trusty")
+	exists, err = s.client.ImageExists("ubuntu:trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(exists, jc.IsTrue)
+}
+
+func (s *ClientSuite) TestImageExistsError(c *gc.C) {
+	s.Server.Fail("GET", "/images/ubuntu:trusty/json", "daemon busy")
+	_, err := s.client.ImageExists("ubuntu:trusty")
+	c.Assert(err, gc.ErrorMatches, "cannot inspect image ubuntu:trusty: daemon busy")
+}
+
+func (s *ClientSuite) TestBuildImage(c *gc.C) {
+	s.Server.AddImage("ubuntu:trusty")
+	dockerfile := "FROM ubuntu:trusty\nCMD [\"/sbin/init\"]\n"
+	err := s.client.BuildImage("custom:trusty", dockerfile)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.Server.HasImage("custom:trusty"), jc.IsTrue)
+	c.Assert(s.Server.Dockerfile("custom:trusty"), gc.Equals, dockerfile)
+}
+
+func (s *ClientSuite) TestBuildImageError(c *gc.C) {
+	// The daemon reports build failures in the response stream.
+	err := s.client.BuildImage("custom:trusty", "FROM ubuntu:trusty\n")
+	c.Assert(err, gc.ErrorMatches, "cannot build image custom:trusty: no such image: ubuntu:trusty")
+	c.Assert(s.Server.HasImage("custom:trusty"), jc.IsFalse)
+}
+
+func (s *ClientSuite) TestContainerLifecycle(c *gc.C) {
+	err := s.client.PullImage("ubuntu", "trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	id, err := s.client.CreateContainer("one", docker.ContainerConfig{
+		Image:      "ubuntu:trusty",
+		Cmd:        []string{"/sbin/init"},
+		HostConfig: docker.HostConfig{Privileged: true},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	created := s.Server.Container("one")
+	c.Assert(created, gc.NotNil)
+	c.Assert(created.Id, gc.Equals, id)
+	c.Assert(created.Config.HostConfig.Privileged, jc.IsTrue)
+	c.Assert(created.Running, jc.IsFalse)
+
+	err = s.client.StartContainer("one")
+	c.Assert(err, jc.ErrorIsNil)
+	info, err := s.client.InspectContainer("one")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.State.Running, jc.IsTrue)
+	c.Assert(info.NetworkSettings.IPAddress, gc.Not(gc.Equals), "")
+
+	err = s.client.StopContainer("one")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.Server.Container("one").Running, jc.IsFalse)
+
+	// Stopping a stopped container is not an error.
+	err = s.client.StopContainer("one")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.client.RemoveContainer("one")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.Server.Container("one"), gc.IsNil)
+}
+
+func (s *ClientSuite) TestCreateContainerMissingImage(c *gc.C) {
+	_, err := s.client.CreateContainer("one", docker.ContainerConfig{Image: "ubuntu:trusty"})
+	c.Assert(err, gc.ErrorMatches, `cannot create container "one": no such image: ubuntu:trusty`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ClientSuite) TestInspectContainerNotFound(c *gc.C) {
+	_, err := s.client.InspectContainer("missing")
+	c.Assert(err, gc.ErrorMatches, `cannot inspect container "missing": no such container: missing`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ClientSuite) TestListContainers(c *gc.C) {
+	s.Server.AddContainer("one", true)
+	s.Server.AddContainer("two", false)
+	containers, err := s.client.ListContainers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 2)
+	c.Assert(containers[0].Name(), gc.Equals, "one")
+	c.Assert(containers[1].Name(), gc.Equals, "two")
+}
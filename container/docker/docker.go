@@ -0,0 +1,327 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package docker
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+	"github.com/juju/utils/arch"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/environs/cloudinit"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/service"
+	"github.com/juju/juju/version"
+)
+
+var (
+	logger = loggo.GetLogger("juju.container.docker")
+
+	// SocketPath is the unix socket the Docker daemon listens on.
+	// It is a variable so that tests can point it at a fake server.
+	SocketPath = "/var/run/docker.sock"
+
+	// BaseImageRepository holds the stock Ubuntu images, tagged by
+	// series, that container images are built from.
+	BaseImageRepository = "ubuntu"
+
+	// ImageRepository holds the images containers are created from,
+	// tagged by series. They are built locally from the base images
+	// so that their init process runs cloud-init, which uses the
+	// NoCloud seed written by the manager to start the machine agent.
+	ImageRepository = "juju-cloudinit"
+
+	// DefaultDockerBridge is the host bridge containers are attached
+	// to when the agent config does not name one. The daemon is
+	// configured to use it by the container initialiser.
+	DefaultDockerBridge = "br0"
+
+	runtimeGOOS = runtime.GOOS
+)
+
+// unsupportedSeries holds the Ubuntu series no docker.io package is
+// available for.
+var unsupportedSeries = set.NewStrings("precise", "quantal", "raring", "saucy")
+
+// supportedArches holds the architectures the docker.io package is
+// built for.
+var supportedArches = set.NewStrings(arch.AMD64, arch.I386, arch.ARMHF)
+
+// imageDockerfile is used to build the image for a series from the
+// stock image. The stock images divert initctl and forbid services from
+// starting, and do not include cloud-init, so none of the machine
+// agent's services would run.
+const imageDockerfile = `FROM %s:%s
+RUN rm -f /usr/sbin/policy-rc.d /sbin/initctl && dpkg-divert --local --rename --remove /sbin/initctl
+RUN apt-get update && DEBIAN_FRONTEND=noninteractive apt-get install -y cloud-init openssh-server
+RUN echo 'datasource_list: [ NoCloud, None ]' > /etc/cloud/cloud.cfg.d/90_dpkg.cfg
+CMD ["/sbin/init"]
+`
+
+const (
+	// seedDir is where the cloud-init NoCloud seed is mounted inside
+	// each container.
+	seedDir = "/var/lib/cloud/seed/nocloud-net"
+
+	// containerLogDir is where the host log directory is mounted
+	// inside each container.
+	containerLogDir = "/var/log/juju"
+)
+
+// IsDockerSupported reports whether this machine can run Docker
+// containers. The daemon need not be running, as it is installed by the
+// container initialiser. Hosts booted with systemd are not supported,
+// as the daemon's systemd unit ignores the options the initialiser
+// writes. It is a variable to allow us to override behaviour in the
+// tests.
+var IsDockerSupported = func() (bool, error) {
+	if runtimeGOOS != "linux" {
+		return false, nil
+	}
+	host := version.Current
+	if host.OS != version.Ubuntu || unsupportedSeries.Contains(host.Series) {
+		return false, nil
+	}
+	if service.SeriesInitSystem(host.Series) != service.InitSystemUpstart {
+		return false, nil
+	}
+	return supportedArches.Contains(host.Arch), nil
+}
+
+// NewContainerManager returns a manager object that can start and stop
+// docker containers. The containers that are created are namespaced by the
+// name parameter.
+func NewContainerManager(conf container.ManagerConfig) (container.Manager, error) {
+	name := conf.PopValue(container.ConfigName)
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	logDir := conf.PopValue(container.ConfigLogDir)
+	if logDir == "" {
+		logDir = agent.DefaultLogDir
+	}
+	conf.WarnAboutUnused()
+	return &containerManager{name: name, logdir: logDir}, nil
+}
+
+// containerManager handles all of the business logic at the juju specific
+// level. It makes sure that the cloud-init seed is written out where the
+// container can see it, and that only containers belonging to this manager
+// are listed.
+type containerManager struct {
+	name   string
+	logdir string
+}
+
+var _ container.Manager = (*containerManager)(nil)
+
+func (manager *containerManager) client() *Client {
+	return NewClient(SocketPath)
+}
+
+// writeSeed writes the user-data and meta-data files used by the
+// cloud-init NoCloud datasource into a seed directory below the
+// container directory, and returns the seed directory.
+func writeSeed(name, directory, userDataFilename string) (string, error) {
+	userData, err := ioutil.ReadFile(userDataFilename)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	seed := filepath.Join(directory, "seed")
+	if err := os.MkdirAll(seed, 0755); err != nil {
+		return "", errors.Trace(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(seed, "user-data"), userData, 0644); err != nil {
+		return "", errors.Trace(err)
+	}
+	metaData := fmt.Sprintf("instance-id: %s\nlocal-hostname: %s\n", name, name)
+	if err := ioutil.WriteFile(filepath.Join(seed, "meta-data"), []byte(metaData), 0644); err != nil {
+		return "", errors.Trace(err)
+	}
+	return seed, nil
+}
+
+// ensureImage builds the image for the given series unless the daemon
+// already holds it, and returns the image name.
+func ensureImage(client *Client, series string) (string, error) {
+	image := ImageRepository + ":" + series
+	exists, err := client.ImageExists(image)
+	if err != nil || exists {
+		return image, err
+	}
+	logger.Tracef("pull the %s:%s image", BaseImageRepository, series)
+	if err := client.PullImage(BaseImageRepository, series); err != nil {
+		return "", errors.Annotate(err, "docker image download failed")
+	}
+	logger.Tracef("build the %s image", image)
+	dockerfile := fmt.Sprintf(imageDockerfile, BaseImageRepository, series)
+	if err := client.BuildImage(image, dockerfile); err != nil {
+		return "", errors.Annotate(err, "docker image build failed")
+	}
+	return image, nil
+}
+
+// checkContainerBridge verifies that the running container is attached
+// to the bridge requested by the broker. The daemon attaches every
+// container to the single bridge it was started with, so a mismatch
+// means the daemon has not been configured by the container initialiser
+// and the container is not reachable from the host network.
+func checkContainerBridge(client *Client, name string, network *container.NetworkConfig) error {
+	if network == nil || network.Device == "" {
+		return nil
+	}
+	info, err := client.InspectContainer(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if bridge := info.NetworkSettings.Bridge; bridge != network.Device {
+		return errors.Errorf("container attached to bridge %q, not %q", bridge, network.Device)
+	}
+	return nil
+}
+
+func (manager *containerManager) CreateContainer(
+	machineConfig *cloudinit.MachineConfig,
+	series string,
+	network *container.NetworkConfig,
+) (instance.Instance, *instance.HardwareCharacteristics, error) {
+
+	name := names.NewMachineTag(machineConfig.MachineId).String()
+	if manager.name != "" {
+		name = fmt.Sprintf("%s-%s", manager.name, name)
+	}
+
+	// Create the cloud-init.
+	directory, err := container.NewDirectory(name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create container directory: %v", err)
+	}
+	logger.Tracef("write cloud-init")
+	userDataFilename, err := container.WriteUserData(machineConfig, directory)
+	if err != nil {
+		err = errors.Annotate(err, "failed to write user data")
+		logger.Infof(err.Error())
+		return nil, nil, err
+	}
+	seed, err := writeSeed(name, directory, userDataFilename)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "failed to write cloud-init seed")
+	}
+
+	client := manager.client()
+	image, err := ensureImage(client, series)
+	if err != nil {
+		logger.Infof(err.Error())
+		return nil, nil, err
+	}
+
+	logger.Tracef("create the container, constraints: %v", machineConfig.Constraints)
+	config := ContainerConfig{
+		Image:    image,
+		Hostname: name,
+		Cmd:      []string{"/sbin/init"},
+		HostConfig: HostConfig{
+			// The machine agent manages services and mounts inside
+			// the container, so it needs to be privileged.
+			Privileged: true,
+			Binds: []string{
+				seed + ":" + seedDir + ":ro",
+				manager.logdir + ":" + containerLogDir,
+			},
+			// The daemon's bridge is the host bridge, so bridge
+			// mode connects the container to the host network.
+			NetworkMode:   "bridge",
+			RestartPolicy: RestartPolicy{Name: "always"},
+		},
+	}
+	if _, err := client.CreateContainer(name, config); err != nil {
+		err = errors.Annotate(err, "docker container creation failed")
+		logger.Infof(err.Error())
+		return nil, nil, err
+	}
+	if err := client.StartContainer(name); err != nil {
+		err = errors.Annotate(err, "docker container startup failed")
+		logger.Infof(err.Error())
+		manager.removeContainer(client, name)
+		return nil, nil, err
+	}
+	if err := checkContainerBridge(client, name, network); err != nil {
+		err = errors.Annotate(err, "docker container network misconfigured")
+		logger.Infof(err.Error())
+		manager.removeContainer(client, name)
+		return nil, nil, err
+	}
+	logger.Tracef("docker container created")
+
+	arch := version.Current.Arch
+	hardware := &instance.HardwareCharacteristics{
+		Arch: &arch,
+	}
+	return &dockerInstance{client: client, id: name}, hardware, nil
+}
+
+// removeContainer cleans up after a container that could not be
+// started properly.
+func (manager *containerManager) removeContainer(client *Client, name string) {
+	if err := client.RemoveContainer(name); err != nil {
+		logger.Errorf("failed to remove docker container: %v", err)
+	}
+	if err := container.RemoveDirectory(name); err != nil {
+		logger.Errorf("failed to remove container directory: %v", err)
+	}
+}
+
+func (manager *containerManager) IsInitialized() bool {
+	_, err := os.Stat(SocketPath)
+	return err == nil
+}
+
+func (manager *containerManager) DestroyContainer(id instance.Id) error {
+	name := string(id)
+	client := manager.client()
+	if err := client.StopContainer(name); err != nil {
+		logger.Errorf("failed to stop docker container: %v", err)
+		return err
+	}
+	if err := client.RemoveContainer(name); err != nil {
+		logger.Errorf("failed to remove docker container: %v", err)
+		return err
+	}
+	return container.RemoveDirectory(name)
+}
+
+func (manager *containerManager) ListContainers() (result []instance.Instance, err error) {
+	client := manager.client()
+	containers, err := client.ListContainers()
+	if err != nil {
+		logger.Errorf("failed getting all instances: %v", err)
+		return
+	}
+	managerPrefix := fmt.Sprintf("%s-", manager.name)
+	for _, summary := range containers {
+		// Filter out those not starting with our name.
+		name := summary.Name()
+		if !strings.HasPrefix(name, managerPrefix) {
+			continue
+		}
+		info, err := client.InspectContainer(name)
+		if err != nil {
+			return nil, err
+		}
+		if info.State.Running {
+			result = append(result, &dockerInstance{client: client, id: name})
+		}
+	}
+	return
+}
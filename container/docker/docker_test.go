@@ -0,0 +1,245 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package docker_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/arch"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/container/docker"
+	dockertesting "github.com/juju/juju/container/docker/testing"
+	containertesting "github.com/juju/juju/container/testing"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/version"
+)
+
+type DockerSuite struct {
+	dockertesting.TestSuite
+	manager container.Manager
+}
+
+var _ = gc.Suite(&DockerSuite{})
+
+func (s *DockerSuite) SetUpTest(c *gc.C) {
+	s.TestSuite.SetUpTest(c)
+	var err error
+	s.manager, err = docker.NewContainerManager(container.ManagerConfig{
+		container.ConfigName:   "test",
+		container.ConfigLogDir: "/var/log/juju-test",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	// The container tests request the "nic42" bridge, which the
+	// initialiser would have configured the daemon with.
+	s.Server.Bridge = "nic42"
+}
+
+func (*DockerSuite) TestManagerNameNeeded(c *gc.C) {
+	manager, err := docker.NewContainerManager(container.ManagerConfig{container.ConfigName: ""})
+	c.Assert(err, gc.ErrorMatches, "name is required")
+	c.Assert(manager, gc.IsNil)
+}
+
+func (*DockerSuite) TestManagerWarnsAboutUnknownOption(c *gc.C) {
+	_, err := docker.NewContainerManager(container.ManagerConfig{
+		container.ConfigName: "BillyBatson",
+		"shazam":             "Captain Marvel",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(c.GetTestLog(), jc.Contains, `WARNING juju.container unused config option: "shazam" -> "Captain Marvel"`)
+}
+
+func (s *DockerSuite) TestIsInitialized(c *gc.C) {
+	c.Assert(s.manager.IsInitialized(), jc.IsTrue)
+	s.PatchValue(&docker.SocketPath, filepath.Join(c.MkDir(), "docker.sock"))
+	c.Assert(s.manager.IsInitialized(), jc.IsFalse)
+}
+
+func (s *DockerSuite) patchHost(goos, series, hostArch string) {
+	s.PatchValue(docker.RuntimeGOOS, goos)
+	s.PatchValue(&version.Current, version.Binary{
+		Number: version.Current.Number,
+		Series: series,
+		Arch:   hostArch,
+		OS:     version.MustOSFromSeries(series),
+	})
+}
+
+func (s *DockerSuite) TestIsDockerSupported(c *gc.C) {
+	for i, test := range []struct {
+		goos      string
+		series    string
+		arch      string
+		supported bool
+	}{
+		{"linux", "trusty", arch.AMD64, true},
+		{"linux", "utopic", arch.ARMHF, true},
+		{"linux", "precise", arch.AMD64, false},
+		{"linux", "trusty", arch.PPC64EL, false},
+		{"linux", "vivid", arch.AMD64, false},
+		{"windows", "win2012r2", arch.AMD64, false},
+	} {
+		c.Logf("test %d: %s %s/%s", i, test.goos, test.series, test.arch)
+		s.patchHost(test.goos, test.series, test.arch)
+		supported, err := docker.IsDockerSupported()
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(supported, gc.Equals, test.supported)
+	}
+}
+
+func (s *DockerSuite) TestIsDockerSupportedNoSocket(c *gc.C) {
+	// The daemon is installed by the initialiser, so it need not be
+	// running yet.
+	s.patchHost("linux", "trusty", arch.AMD64)
+	s.PatchValue(&docker.SocketPath, filepath.Join(c.MkDir(), "docker.sock"))
+	supported, err := docker.IsDockerSupported()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(supported, jc.IsTrue)
+}
+
+func (s *DockerSuite) TestListInitiallyEmpty(c *gc.C) {
+	containers, err := s.manager.ListContainers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 0)
+}
+
+func (s *DockerSuite) TestListMatchesManagerName(c *gc.C) {
+	s.Server.AddContainer("test-match1", true)
+	s.Server.AddContainer("test-match2", true)
+	s.Server.AddContainer("testNoMatch", true)
+	s.Server.AddContainer("other", true)
+	containers, err := s.manager.ListContainers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 2)
+	expectedIds := []instance.Id{"test-match1", "test-match2"}
+	ids := []instance.Id{containers[0].Id(), containers[1].Id()}
+	c.Assert(ids, jc.SameContents, expectedIds)
+}
+
+func (s *DockerSuite) TestListMatchesRunningContainers(c *gc.C) {
+	s.Server.AddContainer("test-running", true)
+	s.Server.AddContainer("test-stopped", false)
+	containers, err := s.manager.ListContainers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 1)
+	c.Assert(string(containers[0].Id()), gc.Equals, "test-running")
+}
+
+func (s *DockerSuite) TestCreateContainer(c *gc.C) {
+	inst := containertesting.CreateContainer(c, s.manager, "1/docker/0")
+	name := string(inst.Id())
+	c.Assert(name, gc.Equals, "test-machine-1-docker-0")
+	directory := filepath.Join(s.ContainerDir, name)
+	userData := containertesting.AssertCloudInit(c, filepath.Join(directory, "cloud-init"))
+
+	// The user data is handed to cloud-init in the container through
+	// a NoCloud seed.
+	seed := filepath.Join(directory, "seed")
+	seedUserData, err := ioutil.ReadFile(filepath.Join(seed, "user-data"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(seedUserData), gc.Equals, string(userData))
+	metaData, err := ioutil.ReadFile(filepath.Join(seed, "meta-data"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(metaData), gc.Equals, "instance-id: test-machine-1-docker-0\nlocal-hostname: test-machine-1-docker-0\n")
+
+	// The container image is built from the stock image so that it
+	// runs cloud-init.
+	c.Assert(s.Server.HasImage("ubuntu:quantal"), jc.IsTrue)
+	dockerfile := s.Server.Dockerfile("juju-cloudinit:quantal")
+	c.Assert(dockerfile, jc.HasPrefix, "FROM ubuntu:quantal\n")
+	c.Assert(dockerfile, jc.Contains, "apt-get install -y cloud-init")
+	c.Assert(dockerfile, jc.Contains, "datasource_list: [ NoCloud, None ]")
+	c.Assert(dockerfile, jc.Contains, `CMD ["/sbin/init"]`)
+
+	created := s.Server.Container(name)
+	c.Assert(created, gc.NotNil)
+	c.Assert(created.Running, jc.IsTrue)
+	c.Assert(created.Config, jc.DeepEquals, docker.ContainerConfig{
+		Image:    "juju-cloudinit:quantal",
+		Hostname: name,
+		Cmd:      []string{"/sbin/init"},
+		HostConfig: docker.HostConfig{
+			Privileged: true,
+			Binds: []string{
+				seed + ":/var/lib/cloud/seed/nocloud-net:ro",
+				"/var/log/juju-test:/var/log/juju",
+			},
+			NetworkMode:   "bridge",
+			RestartPolicy: docker.RestartPolicy{Name: "always"},
+		},
+	})
+	c.Assert(inst.Status(), gc.Equals, "running")
+}
+
+func (s *DockerSuite) TestCreateContainerUsesExistingImage(c *gc.C) {
+	s.Server.AddImage("juju-cloudinit:quantal")
+	containertesting.CreateContainer(c, s.manager, "1/docker/0")
+	c.Assert(s.Server.HasImage("ubuntu:quantal"), jc.IsFalse)
+	c.Assert(s.Server.Dockerfile("juju-cloudinit:quantal"), gc.Equals, "")
+}
+
+func (s *DockerSuite) TestCreateContainerPullFailure(c *gc.C) {
+	s.Server.Fail("POST", "/images/create", "registry unavailable")
+	_, err := containertesting.CreateContainerTest(c, s.manager, "1/docker/0")
+	c.Assert(err, gc.ErrorMatches, `.*docker image download failed: cannot pull image ubuntu:quantal: registry unavailable`)
+	c.Assert(s.Server.Container("test-machine-1-docker-0"), gc.IsNil)
+}
+
+func (s *DockerSuite) TestCreateContainerBuildFailure(c *gc.C) {
+	s.Server.Fail("POST", "/build", "out of space")
+	_, err := containertesting.CreateContainerTest(c, s.manager, "1/docker/0")
+	c.Assert(err, gc.ErrorMatches, `.*docker image build failed: cannot build image juju-cloudinit:quantal: out of space`)
+	c.Assert(s.Server.HasImage("juju-cloudinit:quantal"), jc.IsFalse)
+	c.Assert(s.Server.Container("test-machine-1-docker-0"), gc.IsNil)
+}
+
+func (s *DockerSuite) TestCreateContainerFailure(c *gc.C) {
+	s.Server.Fail("POST", "/containers/create", "out of space")
+	_, err := containertesting.CreateContainerTest(c, s.manager, "1/docker/0")
+	c.Assert(err, gc.ErrorMatches, `.*docker container creation failed: cannot create container "test-machine-1-docker-0": out of space`)
+}
+
+func (s *DockerSuite) TestCreateContainerStartFailure(c *gc.C) {
+	s.Server.Fail("POST", "/containers/test-machine-1-docker-0/start", "no such device")
+	_, err := containertesting.CreateContainerTest(c, s.manager, "1/docker/0")
+	c.Assert(err, gc.ErrorMatches, `.*docker container startup failed: cannot start container "test-machine-1-docker-0": no such device`)
+	c.Assert(s.Server.Container("test-machine-1-docker-0"), gc.IsNil)
+	c.Assert(filepath.Join(s.ContainerDir, "test-machine-1-docker-0"), jc.DoesNotExist)
+}
+
+func (s *DockerSuite) TestCreateContainerWrongBridge(c *gc.C) {
+	// An unconfigured daemon attaches containers to its own bridge,
+	// where they cannot be reached from the host network.
+	s.Server.Bridge = "docker0"
+	_, err := containertesting.CreateContainerTest(c, s.manager, "1/docker/0")
+	c.Assert(err, gc.ErrorMatches, `.*docker container network misconfigured: container attached to bridge "docker0", not "nic42"`)
+	c.Assert(s.Server.Container("test-machine-1-docker-0"), gc.IsNil)
+	c.Assert(filepath.Join(s.ContainerDir, "test-machine-1-docker-0"), jc.DoesNotExist)
+}
+
+func (s *DockerSuite) TestDestroyContainer(c *gc.C) {
+	inst := containertesting.CreateContainer(c, s.manager, "1/docker/0")
+
+	err := s.manager.DestroyContainer(inst.Id())
+	c.Assert(err, jc.ErrorIsNil)
+
+	name := string(inst.Id())
+	c.Assert(s.Server.Container(name), gc.IsNil)
+	// Check that the container dir is no longer in the container dir
+	c.Assert(filepath.Join(s.ContainerDir, name), jc.DoesNotExist)
+	// but instead, in the removed container dir
+	c.Assert(filepath.Join(s.RemovedDir, name), jc.IsDirectory)
+}
+
+func (s *DockerSuite) TestInstanceAddresses(c *gc.C) {
+	inst := containertesting.CreateContainer(c, s.manager, "1/docker/0")
+	addresses, err := inst.Addresses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addresses, jc.DeepEquals, network.NewAddresses("172.17.0.2"))
+}
@@ -0,0 +1,13 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package docker
+
+var (
+	RuntimeGOOS        = &runtimeGOOS
+	DaemonAttempt      = &daemonAttempt
+	DaemonDefaultsPath = &daemonDefaultsPath
+	SysClassNet        = &sysClassNet
+	RestartDaemon      = &restartDaemon
+	DaemonInstalled    = &daemonInstalled
+)
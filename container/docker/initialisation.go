@@ -0,0 +1,158 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package docker
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"github.com/juju/utils/apt"
+
+	"github.com/juju/juju/container"
+)
+
+var requiredPackages = []string{
+	"docker.io",
+}
+
+var (
+	// daemonDefaultsPath holds the options the Docker daemon is
+	// started with.
+	daemonDefaultsPath = "/etc/default/docker"
+
+	// sysClassNet is where the kernel describes the host's network
+	// interfaces.
+	sysClassNet = "/sys/class/net"
+
+	// daemonAttempt governs how long the initialiser waits for the
+	// Docker daemon to answer once it is restarted.
+	daemonAttempt = utils.AttemptStrategy{
+		Total: 30 * time.Second,
+		Delay: time.Second,
+	}
+
+	restartDaemon = func() error {
+		_, err := utils.RunCommand("service", "docker", "restart")
+		return err
+	}
+
+	// daemonInstalled reports whether the Docker daemon's package is
+	// installed.
+	daemonInstalled = func() bool {
+		return apt.IsPackageInstalled("docker.io")
+	}
+)
+
+// daemonDefaultsHeader marks the daemon options file as written by juju.
+const daemonDefaultsHeader = "# Written by juju: containers are attached to the host bridge.\n"
+
+// daemonDefaults attaches containers to the host bridge, where they are
+// addressed directly rather than masqueraded behind the host.
+//
+// Docker allocates the containers' addresses itself from the bridge's
+// subnet, without consulting the DHCP server that serves that network,
+// so the subnet's DHCP range must leave room for them.
+const daemonDefaults = daemonDefaultsHeader + `# Docker allocates container addresses from the bridge's subnet without
+# consulting DHCP; keep them out of the DHCP range, e.g. with --fixed-cidr.
+DOCKER_OPTS="--bridge=%s --ip-masq=false --iptables=false"
+`
+
+type containerInitialiser struct {
+	bridge string
+}
+
+// containerInitialiser implements container.Initialiser.
+var _ container.Initialiser = (*containerInitialiser)(nil)
+
+// NewContainerInitialiser returns an instance used to perform the steps
+// required to allow a host machine to run a Docker container attached
+// to the given host bridge. A Docker daemon that was installed other
+// than by juju is left alone, so the initialiser fails on such hosts.
+func NewContainerInitialiser(bridge string) container.Initialiser {
+	return &containerInitialiser{bridge}
+}
+
+// Initialise is specified on the container.Initialiser interface.
+// It installs the Docker daemon and restarts it attached to the host
+// bridge.
+func (ci *containerInitialiser) Initialise() error {
+	if err := checkHostBridge(ci.bridge); err != nil {
+		return errors.Trace(err)
+	}
+	if err := checkDaemonOwner(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := ensureDependencies(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := configureDaemon(ci.bridge); err != nil {
+		return errors.Annotate(err, "cannot configure docker daemon")
+	}
+	return waitForDaemon()
+}
+
+// checkHostBridge verifies that the named host interface is a bridge
+// containers can be attached to.
+func checkHostBridge(bridge string) error {
+	info, err := os.Stat(filepath.Join(sysClassNet, bridge, "bridge"))
+	if os.IsNotExist(err) {
+		return errors.NotFoundf("bridge %q", bridge)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if !info.IsDir() {
+		return errors.NotFoundf("bridge %q", bridge)
+	}
+	return nil
+}
+
+// checkDaemonOwner fails if a Docker daemon is already installed but
+// was not configured by juju, as reconfiguring it would drop its
+// options and restarting it would stop its containers.
+func checkDaemonOwner() error {
+	if !daemonInstalled() {
+		return nil
+	}
+	defaults, err := ioutil.ReadFile(daemonDefaultsPath)
+	if err == nil && strings.HasPrefix(string(defaults), daemonDefaultsHeader) {
+		return nil
+	} else if err != nil && !os.IsNotExist(err) {
+		return errors.Trace(err)
+	}
+	return errors.Errorf("docker is already installed and not configured by juju; refusing to reconfigure it")
+}
+
+func ensureDependencies() error {
+	return apt.GetInstall(requiredPackages...)
+}
+
+// configureDaemon attaches the Docker daemon to the bridge. The daemon
+// is only restarted, stopping its containers, if its options change.
+func configureDaemon(bridge string) error {
+	defaults := fmt.Sprintf(daemonDefaults, bridge)
+	existing, err := ioutil.ReadFile(daemonDefaultsPath)
+	if err == nil && string(existing) == defaults {
+		return nil
+	}
+	if err := ioutil.WriteFile(daemonDefaultsPath, []byte(defaults), 0644); err != nil {
+		return errors.Trace(err)
+	}
+	return restartDaemon()
+}
+
+func waitForDaemon() error {
+	var err error
+	for a := daemonAttempt.Start(); a.Next(); {
+		if err = NewClient(SocketPath).Ping(); err == nil {
+			return nil
+		}
+	}
+	return errors.Annotate(err, "docker daemon not available")
+}
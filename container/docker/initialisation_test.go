@@ -0,0 +1,130 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package docker_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	"github.com/juju/utils/apt"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/container/docker"
+	dockertesting "github.com/juju/juju/container/docker/testing"
+)
+
+type InitialiserSuite struct {
+	dockertesting.TestSuite
+	defaultsPath string
+	restarted    int
+}
+
+var _ = gc.Suite(&InitialiserSuite{})
+
+var aptInstall = []string{
+	"apt-get", "--option=Dpkg::Options::=--force-confold",
+	"--option=Dpkg::options::=--force-unsafe-io", "--assume-yes", "--quiet",
+	"install",
+}
+
+func (s *InitialiserSuite) SetUpTest(c *gc.C) {
+	s.TestSuite.SetUpTest(c)
+
+	// Describe the host's interfaces as the kernel does: only
+	// bridges have a "bridge" directory.
+	sysClassNet := c.MkDir()
+	err := os.MkdirAll(filepath.Join(sysClassNet, "br0", "bridge"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = os.MkdirAll(filepath.Join(sysClassNet, "eth0"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(docker.SysClassNet, sysClassNet)
+
+	s.defaultsPath = filepath.Join(c.MkDir(), "docker")
+	s.PatchValue(docker.DaemonDefaultsPath, s.defaultsPath)
+	s.restarted = 0
+	s.PatchValue(docker.RestartDaemon, func() error {
+		s.restarted++
+		return nil
+	})
+	s.PatchValue(docker.DaemonInstalled, func() bool { return false })
+}
+
+func (s *InitialiserSuite) TestInitialise(c *gc.C) {
+	cmdChan := s.HookCommandOutput(&apt.CommandOutput, []byte{}, nil)
+	err := docker.NewContainerInitialiser("br0").Initialise()
+	c.Assert(err, jc.ErrorIsNil)
+
+	cmd := <-cmdChan
+	c.Assert(cmd.Args, gc.DeepEquals, append(aptInstall, "docker.io"))
+
+	// The daemon is restarted attached to the host bridge.
+	defaults, err := ioutil.ReadFile(s.defaultsPath)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(defaults), jc.Contains, `DOCKER_OPTS="--bridge=br0 --ip-masq=false --iptables=false"`)
+	c.Assert(s.restarted, gc.Equals, 1)
+	c.Assert(s.Server.Requests(), jc.DeepEquals, []string{"GET /_ping"})
+}
+
+func (s *InitialiserSuite) TestInitialiseAgain(c *gc.C) {
+	s.HookCommandOutput(&apt.CommandOutput, []byte{}, nil)
+	err := docker.NewContainerInitialiser("br0").Initialise()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.restarted, gc.Equals, 1)
+
+	// The daemon juju configured is not restarted, and so keeps its
+	// containers running, when its options are unchanged.
+	s.PatchValue(docker.DaemonInstalled, func() bool { return true })
+	err = docker.NewContainerInitialiser("br0").Initialise()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.restarted, gc.Equals, 1)
+}
+
+func (s *InitialiserSuite) TestDaemonNotInstalledByJuju(c *gc.C) {
+	s.PatchValue(docker.DaemonInstalled, func() bool { return true })
+	options := []byte(`DOCKER_OPTS="--dns 8.8.8.8"` + "\n")
+	err := ioutil.WriteFile(s.defaultsPath, options, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = docker.NewContainerInitialiser("br0").Initialise()
+	c.Assert(err, gc.ErrorMatches, "docker is already installed and not configured by juju; refusing to reconfigure it")
+	defaults, err := ioutil.ReadFile(s.defaultsPath)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(defaults, gc.DeepEquals, options)
+	c.Assert(s.restarted, gc.Equals, 0)
+}
+
+func (s *InitialiserSuite) TestNotABridge(c *gc.C) {
+	err := docker.NewContainerInitialiser("eth0").Initialise()
+	c.Assert(err, gc.ErrorMatches, `bridge "eth0" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(s.defaultsPath, jc.DoesNotExist)
+}
+
+func (s *InitialiserSuite) TestMissingBridge(c *gc.C) {
+	err := docker.NewContainerInitialiser("br1").Initialise()
+	c.Assert(err, gc.ErrorMatches, `bridge "br1" not found`)
+	c.Assert(s.defaultsPath, jc.DoesNotExist)
+}
+
+func (s *InitialiserSuite) TestRestartFailure(c *gc.C) {
+	s.HookCommandOutput(&apt.CommandOutput, []byte{}, nil)
+	s.PatchValue(docker.RestartDaemon, func() error {
+		return errors.New("no such service")
+	})
+	err := docker.NewContainerInitialiser("br0").Initialise()
+	c.Assert(err, gc.ErrorMatches, "cannot configure docker daemon: no such service")
+}
+
+func (s *InitialiserSuite) TestDaemonNotAvailable(c *gc.C) {
+	s.HookCommandOutput(&apt.CommandOutput, []byte{}, nil)
+	s.PatchValue(docker.DaemonAttempt, utils.AttemptStrategy{Total: time.Millisecond})
+	s.PatchValue(&docker.SocketPath, filepath.Join(c.MkDir(), "docker.sock"))
+	err := docker.NewContainerInitialiser("br0").Initialise()
+	c.Assert(err, gc.ErrorMatches, "docker daemon not available: .*")
+}
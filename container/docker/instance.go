@@ -0,0 +1,75 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package docker
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+)
+
+type dockerInstance struct {
+	client *Client
+	id     string
+}
+
+var _ instance.Instance = (*dockerInstance)(nil)
+
+// Id implements instance.Instance.Id.
+func (docker *dockerInstance) Id() instance.Id {
+	return instance.Id(docker.id)
+}
+
+// Status implements instance.Instance.Status.
+func (docker *dockerInstance) Status() string {
+	info, err := docker.client.InspectContainer(docker.id)
+	if err != nil {
+		logger.Warningf("cannot get status of %s: %v", docker, err)
+		return "unknown"
+	}
+	if info.State.Running {
+		return "running"
+	}
+	return "stopped"
+}
+
+func (*dockerInstance) Refresh() error {
+	return nil
+}
+
+// Addresses implements instance.Instance.Addresses, returning the
+// address assigned to the container on the docker bridge.
+func (docker *dockerInstance) Addresses() ([]network.Address, error) {
+	info, err := docker.client.InspectContainer(docker.id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if info.NetworkSettings.IPAddress == "" {
+		return nil, nil
+	}
+	return network.NewAddresses(info.NetworkSettings.IPAddress), nil
+}
+
+// OpenPorts implements instance.Instance.OpenPorts.
+func (docker *dockerInstance) OpenPorts(machineId string, rules []network.IngressRule) error {
+	return fmt.Errorf("not implemented")
+}
+
+// ClosePorts implements instance.Instance.ClosePorts.
+func (docker *dockerInstance) ClosePorts(machineId string, rules []network.IngressRule) error {
+	return fmt.Errorf("not implemented")
+}
+
+// Ports implements instance.Instance.Ports.
+func (docker *dockerInstance) Ports(machineId string) ([]network.IngressRule, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+// Add a string representation of the id.
+func (docker *dockerInstance) String() string {
+	return fmt.Sprintf("docker:%s", docker.id)
+}
@@ -0,0 +1,303 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Functions defined in this file should *ONLY* be used for testing.  These
+// functions are exported for testing purposes only, and shouldn't be called
+// from code that isn't in a test file.
+
+package testing
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/juju/juju/container/docker"
+	containertesting "github.com/juju/juju/container/testing"
+)
+
+// Container is a container held by the fake Docker daemon.
+type Container struct {
+	Id      string
+	Name    string
+	Config  docker.ContainerConfig
+	Running bool
+	Address string
+}
+
+// Server is a fake Docker daemon serving a subset of the remote API on a
+// unix socket from in-memory state.
+type Server struct {
+	*containertesting.SocketServer
+
+	// Bridge is the bridge running containers are reported as
+	// attached to.
+	Bridge string
+
+	mu         sync.Mutex
+	images     map[string]bool
+	builds     map[string]string
+	containers map[string]*Container
+	nextId     int
+}
+
+// NewServer starts a fake Docker daemon listening on a socket inside dir.
+func NewServer(dir string) (*Server, error) {
+	srv := &Server{
+		Bridge:     "docker0",
+		images:     make(map[string]bool),
+		builds:     make(map[string]string),
+		containers: make(map[string]*Container),
+	}
+	socket, err := containertesting.NewSocketServer(filepath.Join(dir, "docker.sock"), srv)
+	if err != nil {
+		return nil, err
+	}
+	srv.SocketServer = socket
+	return srv, nil
+}
+
+// HasImage reports whether the given image has been pulled or built.
+func (srv *Server) HasImage(image string) bool {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	return srv.images[image]
+}
+
+// AddImage adds an image directly to the server's state.
+func (srv *Server) AddImage(image string) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.images[image] = true
+}
+
+// Dockerfile returns the Dockerfile the given image was built from, or
+// "" if it was not built.
+func (srv *Server) Dockerfile(image string) string {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	return srv.builds[image]
+}
+
+// AddContainer adds a container directly to the server's state.
+func (srv *Server) AddContainer(name string, running bool) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	c := srv.addContainer(name, docker.ContainerConfig{})
+	c.Running = running
+}
+
+// Container returns the named container, or nil if it does not exist.
+func (srv *Server) Container(name string) *Container {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if c, ok := srv.containers[name]; ok {
+		copied := *c
+		return &copied
+	}
+	return nil
+}
+
+// ServeHTTP implements http.Handler.
+func (srv *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	// Strip the API version prefix.
+	path := req.URL.Path
+	if parts := strings.SplitN(path, "/", 3); len(parts) == 3 && strings.HasPrefix(parts[1], "v") {
+		path = "/" + parts[2]
+	}
+	// Failures are arranged with paths relative to the API root, e.g.
+	// "/containers/create".
+	key := req.Method + " " + path
+	if failure, failed := srv.Record(req.Method, path); failed {
+		http.Error(w, failure, http.StatusInternalServerError)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case key == "GET /_ping":
+		fmt.Fprint(w, "OK")
+	case key == "POST /images/create":
+		srv.pullImage(w, req)
+	case key == "POST /build":
+		srv.buildImage(w, req)
+	case req.Method == "GET" && len(parts) == 3 && parts[0] == "images" && parts[2] == "json":
+		srv.inspectImage(w, parts[1])
+	case key == "POST /containers/create":
+		srv.createContainer(w, req)
+	case key == "GET /containers/json":
+		srv.listContainers(w)
+	case len(parts) >= 2 && parts[0] == "containers":
+		c, ok := srv.containers[parts[1]]
+		if !ok {
+			http.Error(w, fmt.Sprintf("no such container: %s", parts[1]), http.StatusNotFound)
+			return
+		}
+		srv.serveContainer(w, req, c, parts[2:])
+	default:
+		http.Error(w, fmt.Sprintf("%s not implemented", key), http.StatusNotImplemented)
+	}
+}
+
+func (srv *Server) pullImage(w http.ResponseWriter, req *http.Request) {
+	image := req.FormValue("fromImage") + ":" + req.FormValue("tag")
+	srv.images[image] = true
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "Pulling from " + image})
+	json.NewEncoder(w).Encode(map[string]string{"status": "Download complete"})
+}
+
+func (srv *Server) inspectImage(w http.ResponseWriter, image string) {
+	if !srv.images[image] {
+		http.Error(w, fmt.Sprintf("no such image: %s", image), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"Id": image})
+}
+
+func (srv *Server) buildImage(w http.ResponseWriter, req *http.Request) {
+	var dockerfile []byte
+	archive := tar.NewReader(req.Body)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if header.Name == "Dockerfile" {
+			if dockerfile, err = ioutil.ReadAll(archive); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+	}
+	if dockerfile == nil {
+		http.Error(w, "cannot locate Dockerfile", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	var base string
+	fmt.Sscanf(string(dockerfile), "FROM %s", &base)
+	encoder.Encode(map[string]string{"stream": "Step 0 : FROM " + base})
+	if !srv.images[base] {
+		encoder.Encode(map[string]string{"error": "no such image: " + base})
+		return
+	}
+	image := req.FormValue("t")
+	srv.images[image] = true
+	srv.builds[image] = string(dockerfile)
+	encoder.Encode(map[string]string{"stream": "Successfully built " + image})
+}
+
+func (srv *Server) createContainer(w http.ResponseWriter, req *http.Request) {
+	name := req.FormValue("name")
+	var config docker.ContainerConfig
+	if err := json.NewDecoder(req.Body).Decode(&config); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if _, ok := srv.containers[name]; ok {
+		http.Error(w, fmt.Sprintf("conflict: container name %q already in use", name), http.StatusConflict)
+		return
+	}
+	if !srv.images[config.Image] {
+		http.Error(w, fmt.Sprintf("no such image: %s", config.Image), http.StatusNotFound)
+		return
+	}
+	c := srv.addContainer(name, config)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{"Id": c.Id})
+}
+
+func (srv *Server) listContainers(w http.ResponseWriter) {
+	var result []docker.ContainerSummary
+	for _, c := range srv.containers {
+		status := "Exited (0) 1 seconds ago"
+		if c.Running {
+			status = "Up 1 seconds"
+		}
+		result = append(result, docker.ContainerSummary{
+			Id:     c.Id,
+			Names:  []string{"/" + c.Name},
+			Status: status,
+		})
+	}
+	sort.Sort(byId(result))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (srv *Server) serveContainer(w http.ResponseWriter, req *http.Request, c *Container, action []string) {
+	switch {
+	case req.Method == "GET" && len(action) == 1 && action[0] == "json":
+		info := docker.ContainerInfo{
+			Id:    c.Id,
+			Name:  "/" + c.Name,
+			State: docker.ContainerState{Running: c.Running},
+		}
+		if c.Running {
+			info.NetworkSettings = docker.NetworkSettings{
+				IPAddress: c.Address,
+				Bridge:    srv.Bridge,
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(info)
+	case req.Method == "POST" && len(action) == 1 && action[0] == "start":
+		if c.Running {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		c.Running = true
+		w.WriteHeader(http.StatusNoContent)
+	case req.Method == "POST" && len(action) == 1 && action[0] == "stop":
+		if !c.Running {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		c.Running = false
+		w.WriteHeader(http.StatusNoContent)
+	case req.Method == "DELETE" && len(action) == 0:
+		if c.Running && req.FormValue("force") != "1" {
+			http.Error(w, "conflict: container is running", http.StatusConflict)
+			return
+		}
+		delete(srv.containers, c.Name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "not implemented", http.StatusNotImplemented)
+	}
+}
+
+func (srv *Server) addContainer(name string, config docker.ContainerConfig) *Container {
+	srv.nextId++
+	c := &Container{
+		Id:      fmt.Sprintf("%064x", srv.nextId),
+		Name:    name,
+		Config:  config,
+		Address: fmt.Sprintf("172.17.0.%d", srv.nextId+1),
+	}
+	srv.containers[name] = c
+	return c
+}
+
+type byId []docker.ContainerSummary
+
+func (s byId) Len() int           { return len(s) }
+func (s byId) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byId) Less(i, j int) bool { return s[i].Id < s[j].Id }
@@ -9,8 +9,10 @@ import (
 	"fmt"
 
 	"github.com/juju/juju/container"
+	"github.com/juju/juju/container/docker"
 	"github.com/juju/juju/container/kvm"
 	"github.com/juju/juju/container/lxc"
+	"github.com/juju/juju/container/lxd"
 	"github.com/juju/juju/instance"
 )
 
@@ -22,6 +24,10 @@ func NewContainerManager(forType instance.ContainerType, conf container.ManagerC
 		return lxc.NewContainerManager(conf)
 	case instance.KVM:
 		return kvm.NewContainerManager(conf)
+	case instance.LXD:
+		return lxd.NewContainerManager(conf)
+	case instance.DOCKER:
+		return docker.NewContainerManager(conf)
 	}
 	return nil, fmt.Errorf("unknown container type: %q", forType)
 }
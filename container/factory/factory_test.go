@@ -29,6 +29,12 @@ func (*factorySuite) TestNewContainerManager(c *gc.C) {
 	}, {
 		containerType: instance.KVM,
 		valid:         true,
+	}, {
+		containerType: instance.LXD,
+		valid:         true,
+	}, {
+		containerType: instance.DOCKER,
+		valid:         true,
 	}, {
 		containerType: instance.NONE,
 		valid:         false,
@@ -0,0 +1,332 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// apiVersion is the version of the LXD REST API spoken by the client.
+const apiVersion = "1.0"
+
+// Response types returned by the LXD daemon.
+const (
+	responseSync  = "sync"
+	responseAsync = "async"
+	responseError = "error"
+)
+
+// Status codes used by the LXD daemon to describe containers and
+// background operations.
+const (
+	statusRunning = 103
+	statusSuccess = 200
+)
+
+// response is the envelope of every reply from the LXD daemon.
+type response struct {
+	Type       string          `json:"type"`
+	Status     string          `json:"status"`
+	StatusCode int             `json:"status_code"`
+	Operation  string          `json:"operation"`
+	Error      string          `json:"error"`
+	ErrorCode  int             `json:"error_code"`
+	Metadata   json.RawMessage `json:"metadata"`
+}
+
+// Operation describes a background operation run by the LXD daemon.
+type Operation struct {
+	Id         string `json:"id"`
+	Status     string `json:"status"`
+	StatusCode int    `json:"status_code"`
+	Err        string `json:"err"`
+}
+
+// ContainerSource describes where the root filesystem of a new container
+// comes from.
+type ContainerSource struct {
+	Type     string `json:"type"`
+	Mode     string `json:"mode,omitempty"`
+	Server   string `json:"server,omitempty"`
+	Protocol string `json:"protocol,omitempty"`
+	Alias    string `json:"alias,omitempty"`
+}
+
+// ContainerSpec holds the parameters used to create a container.
+type ContainerSpec struct {
+	Name     string            `json:"name"`
+	Profiles []string          `json:"profiles,omitempty"`
+	Config   map[string]string `json:"config,omitempty"`
+	Source   ContainerSource   `json:"source"`
+}
+
+// Container describes an existing container.
+type Container struct {
+	Name       string            `json:"name"`
+	Status     string            `json:"status"`
+	StatusCode int               `json:"status_code"`
+	Profiles   []string          `json:"profiles"`
+	Config     map[string]string `json:"config"`
+}
+
+// IsRunning returns whether the container is running.
+func (c *Container) IsRunning() bool {
+	return c.StatusCode == statusRunning
+}
+
+// NetworkAddress is an address assigned to a container network interface.
+type NetworkAddress struct {
+	Family  string `json:"family"`
+	Address string `json:"address"`
+	Scope   string `json:"scope"`
+}
+
+// NetworkInterface describes a container network interface.
+type NetworkInterface struct {
+	Addresses []NetworkAddress `json:"addresses"`
+}
+
+// ContainerState holds the runtime state of a container.
+type ContainerState struct {
+	Status     string                      `json:"status"`
+	StatusCode int                         `json:"status_code"`
+	Network    map[string]NetworkInterface `json:"network"`
+}
+
+// Profile is a named set of configuration and devices that can be
+// applied to containers.
+type Profile struct {
+	Name    string                       `json:"name"`
+	Config  map[string]string            `json:"config,omitempty"`
+	Devices map[string]map[string]string `json:"devices,omitempty"`
+}
+
+// Client talks to a LXD daemon through its REST API over a unix socket.
+type Client struct {
+	socketPath string
+	http       *http.Client
+}
+
+// NewClient returns a client for the LXD daemon listening on the
+// given unix socket.
+func NewClient(socketPath string) *Client {
+	transport := &http.Transport{
+		Dial: func(string, string) (net.Conn, error) {
+			return net.Dial("unix", socketPath)
+		},
+	}
+	return &Client{
+		socketPath: socketPath,
+		http:       &http.Client{Transport: transport},
+	}
+}
+
+// Ping checks that the daemon is reachable and speaks the expected
+// API version.
+func (c *Client) Ping() error {
+	resp, err := c.get("")
+	if err != nil {
+		return err
+	}
+	var info struct {
+		APIVersion string `json:"api_version"`
+	}
+	if err := json.Unmarshal(resp.Metadata, &info); err != nil {
+		return errors.Annotate(err, "cannot parse LXD daemon information")
+	}
+	if info.APIVersion != apiVersion {
+		return errors.Errorf("LXD daemon speaks API version %q, not %q", info.APIVersion, apiVersion)
+	}
+	return nil
+}
+
+// ListContainers returns the names of all containers known to the daemon.
+func (c *Client) ListContainers() ([]string, error) {
+	resp, err := c.get("/containers")
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot list containers")
+	}
+	var urls []string
+	if err := json.Unmarshal(resp.Metadata, &urls); err != nil {
+		return nil, errors.Annotate(err, "cannot list containers")
+	}
+	names := make([]string, len(urls))
+	for i, u := range urls {
+		names[i] = path.Base(u)
+	}
+	return names, nil
+}
+
+// Container returns the details of the named container.
+func (c *Client) Container(name string) (*Container, error) {
+	resp, err := c.get("/containers/" + url.QueryEscape(name))
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get container %q", name)
+	}
+	var result Container
+	if err := json.Unmarshal(resp.Metadata, &result); err != nil {
+		return nil, errors.Annotatef(err, "cannot get container %q", name)
+	}
+	return &result, nil
+}
+
+// ContainerState returns the runtime state of the named container.
+func (c *Client) ContainerState(name string) (*ContainerState, error) {
+	resp, err := c.get("/containers/" + url.QueryEscape(name) + "/state")
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get state of container %q", name)
+	}
+	var result ContainerState
+	if err := json.Unmarshal(resp.Metadata, &result); err != nil {
+		return nil, errors.Annotatef(err, "cannot get state of container %q", name)
+	}
+	return &result, nil
+}
+
+// CreateContainer creates a new container and waits for the daemon to
+// finish unpacking its image.
+func (c *Client) CreateContainer(spec ContainerSpec) error {
+	if err := c.run("POST", "/containers", spec); err != nil {
+		return errors.Annotatef(err, "cannot create container %q", spec.Name)
+	}
+	return nil
+}
+
+// StartContainer starts the named container.
+func (c *Client) StartContainer(name string) error {
+	return c.setState(name, "start")
+}
+
+// StopContainer forcibly stops the named container.
+func (c *Client) StopContainer(name string) error {
+	return c.setState(name, "stop")
+}
+
+func (c *Client) setState(name, action string) error {
+	body := map[string]interface{}{
+		"action":  action,
+		"timeout": 30,
+		"force":   action == "stop",
+	}
+	if err := c.run("PUT", "/containers/"+url.QueryEscape(name)+"/state", body); err != nil {
+		return errors.Annotatef(err, "cannot %s container %q", action, name)
+	}
+	return nil
+}
+
+// DeleteContainer removes the named container, which must be stopped.
+func (c *Client) DeleteContainer(name string) error {
+	if err := c.run("DELETE", "/containers/"+url.QueryEscape(name), nil); err != nil {
+		return errors.Annotatef(err, "cannot delete container %q", name)
+	}
+	return nil
+}
+
+// Profile returns the named profile. If it does not exist, an error
+// satisfying errors.IsNotFound is returned.
+func (c *Client) Profile(name string) (*Profile, error) {
+	resp, err := c.get("/profiles/" + url.QueryEscape(name))
+	if err != nil {
+		return nil, err
+	}
+	var result Profile
+	if err := json.Unmarshal(resp.Metadata, &result); err != nil {
+		return nil, errors.Annotatef(err, "cannot get profile %q", name)
+	}
+	return &result, nil
+}
+
+// CreateProfile creates a new profile.
+func (c *Client) CreateProfile(profile Profile) error {
+	if _, err := c.do("POST", "/profiles", profile); err != nil {
+		return errors.Annotatef(err, "cannot create profile %q", profile.Name)
+	}
+	return nil
+}
+
+// get issues a GET request for the given path below the API root.
+func (c *Client) get(path string) (*response, error) {
+	return c.do("GET", path, nil)
+}
+
+// run issues a request that starts a background operation and waits
+// for the operation to complete.
+func (c *Client) run(method, path string, body interface{}) error {
+	resp, err := c.do(method, path, body)
+	if err != nil {
+		return err
+	}
+	if resp.Type != responseAsync {
+		return nil
+	}
+	return c.wait(resp.Operation)
+}
+
+// wait blocks until the operation at the given URL has finished,
+// returning an error if it did not succeed.
+func (c *Client) wait(operation string) error {
+	id := path.Base(operation)
+	resp, err := c.get("/operations/" + url.QueryEscape(id) + "/wait")
+	if err != nil {
+		return err
+	}
+	var op Operation
+	if err := json.Unmarshal(resp.Metadata, &op); err != nil {
+		return errors.Annotatef(err, "cannot decode operation %q", id)
+	}
+	if op.StatusCode != statusSuccess {
+		if op.Err != "" {
+			return errors.New(op.Err)
+		}
+		return errors.Errorf("operation %q finished with status %q", id, op.Status)
+	}
+	return nil
+}
+
+// do sends a request to the daemon and decodes its response, turning
+// error responses into Go errors.
+func (c *Client) do(method, path string, body interface{}) (*response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	// The host part of the URL is ignored as we always dial the socket.
+	req, err := http.NewRequest(method, "http://lxd/"+apiVersion+path, reader)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	httpResp, err := c.http.Do(req)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot connect to LXD daemon at %q", c.socketPath)
+	}
+	defer httpResp.Body.Close()
+	var resp response
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return nil, errors.Annotatef(err, "cannot decode LXD response to %s %s", method, path)
+	}
+	if resp.Type == responseError {
+		if resp.ErrorCode == http.StatusNotFound {
+			return nil, errors.NewNotFound(nil, fmt.Sprintf("%s not found", strings.TrimPrefix(path, "/")))
+		}
+		return nil, errors.New(resp.Error)
+	}
+	return &resp, nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd_test
+
+import (
+	"path/filepath"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/container/lxd"
+	lxdtesting "github.com/juju/juju/container/lxd/testing"
+)
+
+type ClientSuite struct {
+	lxdtesting.TestSuite
+	client *lxd.Client
+}
+
+var _ = gc.Suite(&ClientSuite{})
+
+func (s *ClientSuite) SetUpTest(c *gc.C) {
+	s.TestSuite.SetUpTest(c)
+	s.client = lxd.NewClient(s.Server.SocketPath)
+}
+
+func (s *ClientSuite) TestPing(c *gc.C) {
+	err := s.client.Ping()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.Server.Requests(), jc.DeepEquals, []string{"GET "})
+}
+
+func (s *ClientSuite) TestPingUnsupportedVersion(c *gc.C) {
+	s.Server.APIVersion = "2.0"
+	err := s.client.Ping()
+	c.Assert(err, gc.ErrorMatches, `LXD daemon speaks API version "2.0", not "1.0"`)
+}
+
+func (s *ClientSuite) TestPingNoDaemon(c *gc.C) {
+	client := lxd.NewClient(filepath.Join(c.MkDir(), "unix.socket"))
+	err := client.Ping()
+	c.Assert(err, gc.ErrorMatches, `cannot connect to LXD daemon at ".*unix.socket": .*`)
+}
+
+func (s *ClientSuite) TestListContainers(c *gc.C) {
+	s.Server.AddContainer("one", true)
+	s.Server.AddContainer("two", false)
+	names, err := s.client.ListContainers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, jc.DeepEquals, []string{"one", "two"})
+}
+
+func (s *ClientSuite) TestListContainersError(c *gc.C) {
+	s.Server.Fail("GET", "/containers", "boom")
+	_, err := s.client.ListContainers()
+	c.Assert(err, gc.ErrorMatches, "cannot list containers: boom")
+}
+
+func (s *ClientSuite) TestContainer(c *gc.C) {
+	s.Server.AddContainer("one", true)
+	container, err := s.client.Container("one")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(container.Name, gc.Equals, "one")
+	c.Assert(container.IsRunning(), jc.IsTrue)
+}
+
+func (s *ClientSuite) TestContainerNotFound(c *gc.C) {
+	_, err := s.client.Container("missing")
+	c.Assert(err, gc.ErrorMatches, `cannot get container "missing": containers/missing not found`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ClientSuite) TestCreateStartStopDeleteContainer(c *gc.C) {
+	err := s.client.CreateContainer(lxd.ContainerSpec{
+		Name:     "one",
+		Profiles: []string{"default"},
+		Config:   map[string]string{"user.user-data": "#cloud-config\n"},
+		Source:   lxd.ContainerSource{Type: "image", Alias: "trusty"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	created := s.Server.Container("one")
+	c.Assert(created, gc.NotNil)
+	c.Assert(created.IsRunning(), jc.IsFalse)
+	c.Assert(created.Config["user.user-data"], gc.Equals, "#cloud-config\n")
+
+	err = s.client.StartContainer("one")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.Server.Container("one").IsRunning(), jc.IsTrue)
+
+	err = s.client.StopContainer("one")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.Server.Container("one").IsRunning(), jc.IsFalse)
+
+	err = s.client.DeleteContainer("one")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.Server.Container("one"), gc.IsNil)
+}
+
+func (s *ClientSuite) TestCreateContainerOperationFails(c *gc.C) {
+	s.Server.Fail("POST", "/containers", "image not found")
+	err := s.client.CreateContainer(lxd.ContainerSpec{
+		Name:   "one",
+		Source: lxd.ContainerSource{Type: "image", Alias: "trusty"},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot create container "one": image not found`)
+	c.Assert(s.Server.Container("one"), gc.IsNil)
+}
+
+func (s *ClientSuite) TestDeleteRunningContainerFails(c *gc.C) {
+	s.Server.AddContainer("one", true)
+	err := s.client.DeleteContainer("one")
+	c.Assert(err, gc.ErrorMatches, `cannot delete container "one": container is running`)
+}
+
+func (s *ClientSuite) TestContainerState(c *gc.C) {
+	s.Server.AddContainer("one", true)
+	state, err := s.client.ContainerState("one")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(state.StatusCode, gc.Equals, 103)
+	c.Assert(state.Network["eth0"].Addresses, gc.HasLen, 2)
+}
+
+func (s *ClientSuite) TestProfiles(c *gc.C) {
+	_, err := s.client.Profile("juju")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.client.CreateProfile(lxd.Profile{
+		Name: "juju",
+		Devices: map[string]map[string]string{
+			"eth0": {"type": "nic"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	profile, err := s.client.Profile("juju")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(profile.Devices, jc.DeepEquals, map[string]map[string]string{
+		"eth0": {"type": "nic"},
+	})
+
+	err = s.client.CreateProfile(lxd.Profile{Name: "juju"})
+	c.Assert(err, gc.ErrorMatches, `cannot create profile "juju": profile already exists`)
+}
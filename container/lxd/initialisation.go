@@ -0,0 +1,67 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"github.com/juju/utils/apt"
+
+	"github.com/juju/juju/container"
+)
+
+var requiredPackages = []string{
+	"lxd",
+}
+
+// daemonAttempt governs how long the initialiser waits for the LXD
+// daemon to answer once it is installed.
+var daemonAttempt = utils.AttemptStrategy{
+	Total: 30 * time.Second,
+	Delay: time.Second,
+}
+
+type containerInitialiser struct {
+	series string
+}
+
+// containerInitialiser implements container.Initialiser.
+var _ container.Initialiser = (*containerInitialiser)(nil)
+
+// NewContainerInitialiser returns an instance used to perform the steps
+// required to allow a host machine to run a LXD container.
+func NewContainerInitialiser(series string) container.Initialiser {
+	return &containerInitialiser{series}
+}
+
+// Initialise is specified on the container.Initialiser interface.
+// It installs the LXD daemon and waits for it to start.
+func (ci *containerInitialiser) Initialise() error {
+	if err := ensureDependencies(ci.series); err != nil {
+		return errors.Trace(err)
+	}
+	return waitForDaemon()
+}
+
+func ensureDependencies(series string) error {
+	args := requiredPackages
+	if series == "trusty" {
+		// The lxd package is only available for trusty from
+		// the backports pocket.
+		args = append([]string{"--target-release", "trusty-backports"}, args...)
+	}
+	return apt.GetInstall(args...)
+}
+
+func waitForDaemon() error {
+	var err error
+	for a := daemonAttempt.Start(); a.Next(); {
+		if err = NewClient(SocketPath).Ping(); err == nil {
+			return nil
+		}
+	}
+	return errors.Annotate(err, "lxd daemon not available")
+}
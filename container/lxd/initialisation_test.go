@@ -0,0 +1,55 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd_test
+
+import (
+	"path/filepath"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	"github.com/juju/utils/apt"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/container/lxd"
+	lxdtesting "github.com/juju/juju/container/lxd/testing"
+)
+
+type InitialiserSuite struct {
+	lxdtesting.TestSuite
+}
+
+var _ = gc.Suite(&InitialiserSuite{})
+
+var aptInstall = []string{
+	"apt-get", "--option=Dpkg::Options::=--force-confold",
+	"--option=Dpkg::options::=--force-unsafe-io", "--assume-yes", "--quiet",
+	"install",
+}
+
+func (s *InitialiserSuite) TestInstallsFromBackportsOnTrusty(c *gc.C) {
+	cmdChan := s.HookCommandOutput(&apt.CommandOutput, []byte{}, nil)
+	err := lxd.NewContainerInitialiser("trusty").Initialise()
+	c.Assert(err, jc.ErrorIsNil)
+
+	cmd := <-cmdChan
+	c.Assert(cmd.Args, gc.DeepEquals, append(aptInstall, "--target-release", "trusty-backports", "lxd"))
+}
+
+func (s *InitialiserSuite) TestInstalls(c *gc.C) {
+	cmdChan := s.HookCommandOutput(&apt.CommandOutput, []byte{}, nil)
+	err := lxd.NewContainerInitialiser("vivid").Initialise()
+	c.Assert(err, jc.ErrorIsNil)
+
+	cmd := <-cmdChan
+	c.Assert(cmd.Args, gc.DeepEquals, append(aptInstall, "lxd"))
+}
+
+func (s *InitialiserSuite) TestDaemonNotAvailable(c *gc.C) {
+	s.HookCommandOutput(&apt.CommandOutput, []byte{}, nil)
+	s.PatchValue(lxd.DaemonAttempt, utils.AttemptStrategy{Total: time.Millisecond})
+	s.PatchValue(&lxd.SocketPath, filepath.Join(c.MkDir(), "unix.socket"))
+	err := lxd.NewContainerInitialiser("vivid").Initialise()
+	c.Assert(err, gc.ErrorMatches, "lxd daemon not available: .*")
+}
@@ -0,0 +1,85 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+)
+
+type lxdInstance struct {
+	client *Client
+	id     string
+}
+
+var _ instance.Instance = (*lxdInstance)(nil)
+
+// Id implements instance.Instance.Id.
+func (lxd *lxdInstance) Id() instance.Id {
+	return instance.Id(lxd.id)
+}
+
+// Status implements instance.Instance.Status.
+func (lxd *lxdInstance) Status() string {
+	details, err := lxd.client.Container(lxd.id)
+	if err != nil {
+		logger.Warningf("cannot get status of %s: %v", lxd, err)
+		return "unknown"
+	}
+	if details.IsRunning() {
+		return "running"
+	}
+	return "stopped"
+}
+
+func (*lxdInstance) Refresh() error {
+	return nil
+}
+
+// Addresses implements instance.Instance.Addresses. Only global
+// addresses assigned to the container's interfaces are reported.
+func (lxd *lxdInstance) Addresses() ([]network.Address, error) {
+	state, err := lxd.client.ContainerState(lxd.id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var addresses []network.Address
+	for name, iface := range state.Network {
+		if name == "lo" {
+			continue
+		}
+		for _, addr := range iface.Addresses {
+			if addr.Scope != "global" {
+				continue
+			}
+			addresses = append(addresses, network.NewAddress(addr.Address, network.ScopeUnknown))
+		}
+	}
+	network.SortAddresses(addresses, false)
+	return addresses, nil
+}
+
+// OpenPorts implements instance.Instance.OpenPorts.
+func (lxd *lxdInstance) OpenPorts(machineId string, rules []network.IngressRule) error {
+	return fmt.Errorf("not implemented")
+}
+
+// ClosePorts implements instance.Instance.ClosePorts.
+func (lxd *lxdInstance) ClosePorts(machineId string, rules []network.IngressRule) error {
+	return fmt.Errorf("not implemented")
+}
+
+// Ports implements instance.Instance.Ports.
+func (lxd *lxdInstance) Ports(machineId string) ([]network.IngressRule, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+// Add a string representation of the id.
+func (lxd *lxdInstance) String() string {
+	return fmt.Sprintf("lxd:%s", lxd.id)
+}
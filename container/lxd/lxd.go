@@ -0,0 +1,293 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+	"github.com/juju/utils/arch"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/environs/cloudinit"
+	"github.com/juju/juju/environs/imagemetadata"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/version"
+)
+
+var (
+	logger = loggo.GetLogger("juju.container.lxd")
+
+	// SocketPath is the unix socket the LXD daemon listens on.
+	// It is a variable so that tests can point it at a fake server.
+	SocketPath = "/var/lib/lxd/unix.socket"
+
+	DefaultLxdBridge = "lxcbr0"
+
+	runtimeGOOS = runtime.GOOS
+)
+
+// unsupportedSeries holds the Ubuntu series no lxd package is
+// available for.
+var unsupportedSeries = set.NewStrings("precise", "quantal", "raring", "saucy", "utopic")
+
+// supportedArches holds the architectures the lxd package is built
+// for.
+var supportedArches = set.NewStrings(arch.AMD64, arch.I386, arch.ARMHF, arch.ARM64, arch.PPC64EL)
+
+const (
+	// userDataKey is the container config key the LXD daemon hands to
+	// cloud-init as user-data.
+	userDataKey = "user.user-data"
+
+	// containerLogDir is where the host log directory is mounted
+	// inside each container.
+	containerLogDir = "/var/log/juju"
+)
+
+// IsLXDSupported reports whether LXD containers can be run on this
+// machine, which must be running an Ubuntu series and architecture the
+// lxd package is available for. The daemon need not be installed yet:
+// the container initialiser installs it when the first LXD container
+// is added. It is a variable to allow us to override behaviour in the
+// tests.
+var IsLXDSupported = func() (bool, error) {
+	if runtimeGOOS != "linux" {
+		return false, nil
+	}
+	host := version.Current
+	if host.OS != version.Ubuntu || unsupportedSeries.Contains(host.Series) {
+		return false, nil
+	}
+	return supportedArches.Contains(host.Arch), nil
+}
+
+// NewContainerManager returns a manager object that can start and stop lxd
+// containers. The containers that are created are namespaced by the name
+// parameter.
+func NewContainerManager(conf container.ManagerConfig) (container.Manager, error) {
+	name := conf.PopValue(container.ConfigName)
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	logDir := conf.PopValue(container.ConfigLogDir)
+	if logDir == "" {
+		logDir = agent.DefaultLogDir
+	}
+	conf.WarnAboutUnused()
+	return &containerManager{name: name, logdir: logDir}, nil
+}
+
+// containerManager handles all of the business logic at the juju specific
+// level. It makes sure that the juju profile exists, that the user-data is
+// written out and handed to the daemon, and that only containers belonging
+// to this manager are listed.
+type containerManager struct {
+	name   string
+	logdir string
+}
+
+var _ container.Manager = (*containerManager)(nil)
+
+func (manager *containerManager) client() *Client {
+	return NewClient(SocketPath)
+}
+
+// profileName returns the name of the profile holding the devices shared
+// by all containers created by this manager on the given bridge.
+func (manager *containerManager) profileName(bridge string) string {
+	return fmt.Sprintf("%s-%s", manager.name, bridge)
+}
+
+// ensureProfile creates the profile that connects containers to the
+// bridge and mounts the host log directory, if it does not already exist.
+func (manager *containerManager) ensureProfile(client *Client, network *container.NetworkConfig) (string, error) {
+	bridge := DefaultLxdBridge
+	if network != nil && network.Device != "" {
+		bridge = network.Device
+	}
+	name := manager.profileName(bridge)
+	_, err := client.Profile(name)
+	if err == nil {
+		return name, nil
+	}
+	if !errors.IsNotFound(err) {
+		return "", errors.Trace(err)
+	}
+	logger.Debugf("creating profile %q", name)
+	profile := Profile{
+		Name: name,
+		Devices: map[string]map[string]string{
+			"eth0": {
+				"type":    "nic",
+				"nictype": "bridged",
+				"parent":  bridge,
+			},
+			"logdir": {
+				"type":   "disk",
+				"source": manager.logdir,
+				"path":   containerLogDir,
+			},
+		},
+	}
+	if err := client.CreateProfile(profile); err != nil {
+		return "", errors.Trace(err)
+	}
+	return name, nil
+}
+
+// imageSource returns the image the container for the given series
+// is created from.
+func imageSource(series, stream string) ContainerSource {
+	server := imagemetadata.UbuntuCloudImagesURL + "/releases"
+	if stream != "" && stream != imagemetadata.ReleasedStream {
+		server = imagemetadata.UbuntuCloudImagesURL + "/" + stream
+	}
+	return ContainerSource{
+		Type:     "image",
+		Mode:     "pull",
+		Server:   server,
+		Protocol: "simplestreams",
+		Alias:    series,
+	}
+}
+
+func (manager *containerManager) CreateContainer(
+	machineConfig *cloudinit.MachineConfig,
+	series string,
+	network *container.NetworkConfig,
+) (instance.Instance, *instance.HardwareCharacteristics, error) {
+
+	name := names.NewMachineTag(machineConfig.MachineId).String()
+	if manager.name != "" {
+		name = fmt.Sprintf("%s-%s", manager.name, name)
+	}
+
+	// Create the cloud-init.
+	directory, err := container.NewDirectory(name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create container directory: %v", err)
+	}
+	logger.Tracef("write cloud-init")
+	// Don't leave the directory behind if the container is not
+	// created, or it will never be cleaned up.
+	removeDirectory := func() {
+		if err := container.RemoveDirectory(name); err != nil {
+			logger.Errorf("failed to remove container directory: %v", err)
+		}
+	}
+	userDataFilename, err := container.WriteUserData(machineConfig, directory)
+	if err != nil {
+		err = errors.Annotate(err, "failed to write user data")
+		logger.Infof(err.Error())
+		removeDirectory()
+		return nil, nil, err
+	}
+	userData, err := ioutil.ReadFile(userDataFilename)
+	if err != nil {
+		removeDirectory()
+		return nil, nil, errors.Annotate(err, "failed to read user data")
+	}
+
+	client := manager.client()
+	profile, err := manager.ensureProfile(client, network)
+	if err != nil {
+		removeDirectory()
+		return nil, nil, errors.Annotate(err, "failed to set up lxd profile")
+	}
+
+	logger.Tracef("create the container, constraints: %v", machineConfig.Constraints)
+	spec := ContainerSpec{
+		Name:     name,
+		Profiles: []string{"default", profile},
+		Config: map[string]string{
+			userDataKey: string(userData),
+		},
+		Source: imageSource(series, machineConfig.ImageStream),
+	}
+	if err := client.CreateContainer(spec); err != nil {
+		err = errors.Annotate(err, "lxd container creation failed")
+		logger.Infof(err.Error())
+		removeDirectory()
+		return nil, nil, err
+	}
+	if err := client.StartContainer(name); err != nil {
+		err = errors.Annotate(err, "lxd container startup failed")
+		logger.Infof(err.Error())
+		// Don't leave the container behind, or it will never
+		// be cleaned up.
+		if err := client.DeleteContainer(name); err != nil {
+			logger.Errorf("failed to delete lxd container: %v", err)
+		} else {
+			removeDirectory()
+		}
+		return nil, nil, err
+	}
+	logger.Tracef("lxd container created")
+
+	arch := version.Current.Arch
+	hardware := &instance.HardwareCharacteristics{
+		Arch: &arch,
+	}
+	return &lxdInstance{client: client, id: name}, hardware, nil
+}
+
+func (manager *containerManager) IsInitialized() bool {
+	_, err := os.Stat(SocketPath)
+	return err == nil
+}
+
+func (manager *containerManager) DestroyContainer(id instance.Id) error {
+	name := string(id)
+	client := manager.client()
+	details, err := client.Container(name)
+	if err != nil {
+		logger.Errorf("failed to get lxd container: %v", err)
+		return err
+	}
+	// The daemon refuses to stop a container that is not running.
+	if details.IsRunning() {
+		if err := client.StopContainer(name); err != nil {
+			logger.Errorf("failed to stop lxd container: %v", err)
+			return err
+		}
+	}
+	if err := client.DeleteContainer(name); err != nil {
+		logger.Errorf("failed to delete lxd container: %v", err)
+		return err
+	}
+	return container.RemoveDirectory(name)
+}
+
+func (manager *containerManager) ListContainers() (result []instance.Instance, err error) {
+	client := manager.client()
+	containers, err := client.ListContainers()
+	if err != nil {
+		logger.Errorf("failed getting all instances: %v", err)
+		return
+	}
+	managerPrefix := fmt.Sprintf("%s-", manager.name)
+	for _, name := range containers {
+		// Filter out those not starting with our name.
+		if !strings.HasPrefix(name, managerPrefix) {
+			continue
+		}
+		details, err := client.Container(name)
+		if err != nil {
+			return nil, err
+		}
+		if details.IsRunning() {
+			result = append(result, &lxdInstance{client: client, id: name})
+		}
+	}
+	return
+}
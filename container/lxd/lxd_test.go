@@ -0,0 +1,241 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd_test
+
+import (
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/arch"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/container/lxd"
+	lxdtesting "github.com/juju/juju/container/lxd/testing"
+	containertesting "github.com/juju/juju/container/testing"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/version"
+)
+
+type LXDSuite struct {
+	lxdtesting.TestSuite
+	manager container.Manager
+}
+
+var _ = gc.Suite(&LXDSuite{})
+
+func (s *LXDSuite) SetUpTest(c *gc.C) {
+	s.TestSuite.SetUpTest(c)
+	var err error
+	s.manager, err = lxd.NewContainerManager(container.ManagerConfig{
+		container.ConfigName:   "test",
+		container.ConfigLogDir: "/var/log/juju-test",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (*LXDSuite) TestManagerNameNeeded(c *gc.C) {
+	manager, err := lxd.NewContainerManager(container.ManagerConfig{container.ConfigName: ""})
+	c.Assert(err, gc.ErrorMatches, "name is required")
+	c.Assert(manager, gc.IsNil)
+}
+
+func (*LXDSuite) TestManagerWarnsAboutUnknownOption(c *gc.C) {
+	_, err := lxd.NewContainerManager(container.ManagerConfig{
+		container.ConfigName: "BillyBatson",
+		"shazam":             "Captain Marvel",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(c.GetTestLog(), jc.Contains, `WARNING juju.container unused config option: "shazam" -> "Captain Marvel"`)
+}
+
+func (s *LXDSuite) TestIsInitialized(c *gc.C) {
+	c.Assert(s.manager.IsInitialized(), jc.IsTrue)
+	s.PatchValue(&lxd.SocketPath, filepath.Join(c.MkDir(), "unix.socket"))
+	c.Assert(s.manager.IsInitialized(), jc.IsFalse)
+}
+
+func (s *LXDSuite) patchHost(goos, series, hostArch string) {
+	s.PatchValue(lxd.RuntimeGOOS, goos)
+	s.PatchValue(&version.Current, version.Binary{
+		Number: version.Current.Number,
+		Series: series,
+		Arch:   hostArch,
+		OS:     version.MustOSFromSeries(series),
+	})
+}
+
+func (s *LXDSuite) TestIsLXDSupported(c *gc.C) {
+	for i, test := range []struct {
+		goos      string
+		series    string
+		arch      string
+		supported bool
+	}{
+		{"linux", "trusty", arch.AMD64, true},
+		{"linux", "trusty", arch.PPC64EL, true},
+		{"linux", "utopic", arch.AMD64, false},
+		{"linux", "precise", arch.AMD64, false},
+		{"linux", "trusty", "s390x", false},
+		{"windows", "win2012r2", arch.AMD64, false},
+	} {
+		c.Logf("test %d: %s %s/%s", i, test.goos, test.series, test.arch)
+		s.patchHost(test.goos, test.series, test.arch)
+		supported, err := lxd.IsLXDSupported()
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(supported, gc.Equals, test.supported)
+	}
+}
+
+func (s *LXDSuite) TestIsLXDSupportedNoSocket(c *gc.C) {
+	// The daemon is installed by the initialiser, so it need not be
+	// running yet.
+	s.patchHost("linux", "trusty", arch.AMD64)
+	s.PatchValue(&lxd.SocketPath, filepath.Join(c.MkDir(), "unix.socket"))
+	supported, err := lxd.IsLXDSupported()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(supported, jc.IsTrue)
+}
+
+func (s *LXDSuite) TestListInitiallyEmpty(c *gc.C) {
+	containers, err := s.manager.ListContainers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 0)
+}
+
+func (s *LXDSuite) TestListMatchesManagerName(c *gc.C) {
+	s.Server.AddContainer("test-match1", true)
+	s.Server.AddContainer("test-match2", true)
+	s.Server.AddContainer("testNoMatch", true)
+	s.Server.AddContainer("other", true)
+	containers, err := s.manager.ListContainers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 2)
+	expectedIds := []instance.Id{"test-match1", "test-match2"}
+	ids := []instance.Id{containers[0].Id(), containers[1].Id()}
+	c.Assert(ids, jc.SameContents, expectedIds)
+}
+
+func (s *LXDSuite) TestListMatchesRunningContainers(c *gc.C) {
+	s.Server.AddContainer("test-running", true)
+	s.Server.AddContainer("test-stopped", false)
+	containers, err := s.manager.ListContainers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 1)
+	c.Assert(string(containers[0].Id()), gc.Equals, "test-running")
+}
+
+func (s *LXDSuite) TestCreateContainer(c *gc.C) {
+	inst := containertesting.CreateContainer(c, s.manager, "1/lxd/0")
+	name := string(inst.Id())
+	c.Assert(name, gc.Equals, "test-machine-1-lxd-0")
+	cloudInitFilename := filepath.Join(s.ContainerDir, name, "cloud-init")
+	userData := containertesting.AssertCloudInit(c, cloudInitFilename)
+
+	created := s.Server.Container(name)
+	c.Assert(created, gc.NotNil)
+	c.Assert(created.IsRunning(), jc.IsTrue)
+	c.Assert(created.Profiles, jc.DeepEquals, []string{"default", "test-nic42"})
+	c.Assert(created.Config["user.user-data"], gc.Equals, string(userData))
+	c.Assert(inst.Status(), gc.Equals, "running")
+
+	source := s.Server.ContainerSource(name)
+	c.Assert(source, jc.DeepEquals, lxd.ContainerSource{
+		Type:     "image",
+		Mode:     "pull",
+		Server:   "http://cloud-images.ubuntu.com/releases",
+		Protocol: "simplestreams",
+		Alias:    "quantal",
+	})
+}
+
+func (s *LXDSuite) TestCreateContainerCreatesProfile(c *gc.C) {
+	containertesting.CreateContainer(c, s.manager, "1/lxd/0")
+	profile := s.Server.Profile("test-nic42")
+	c.Assert(profile, gc.NotNil)
+	c.Assert(profile.Devices, jc.DeepEquals, map[string]map[string]string{
+		"eth0": {
+			"type":    "nic",
+			"nictype": "bridged",
+			"parent":  "nic42",
+		},
+		"logdir": {
+			"type":   "disk",
+			"source": "/var/log/juju-test",
+			"path":   "/var/log/juju",
+		},
+	})
+
+	// A second container reuses the existing profile.
+	containertesting.CreateContainer(c, s.manager, "1/lxd/1")
+	created := 0
+	for _, req := range s.Server.Requests() {
+		if req == "POST /profiles" {
+			created++
+		}
+	}
+	c.Assert(created, gc.Equals, 1)
+}
+
+func (s *LXDSuite) TestCreateContainerUtilizesDailySimpleStream(c *gc.C) {
+	machineConfig, err := containertesting.MockMachineConfig("1/lxd/0")
+	c.Assert(err, jc.ErrorIsNil)
+	machineConfig.ImageStream = "daily"
+
+	inst := containertesting.CreateContainerWithMachineConfig(c, s.manager, machineConfig)
+	source := s.Server.ContainerSource(string(inst.Id()))
+	c.Assert(source.Server, gc.Equals, "http://cloud-images.ubuntu.com/daily")
+}
+
+func (s *LXDSuite) TestCreateContainerFailure(c *gc.C) {
+	s.Server.Fail("POST", "/containers", "image not found")
+	_, err := containertesting.CreateContainerTest(c, s.manager, "1/lxd/0")
+	c.Assert(err, gc.ErrorMatches, `.*lxd container creation failed: cannot create container "test-machine-1-lxd-0": image not found`)
+	c.Assert(filepath.Join(s.ContainerDir, "test-machine-1-lxd-0"), jc.DoesNotExist)
+}
+
+func (s *LXDSuite) TestCreateContainerStartFailure(c *gc.C) {
+	s.Server.Fail("PUT", "/containers/test-machine-1-lxd-0/state", "no memory")
+	_, err := containertesting.CreateContainerTest(c, s.manager, "1/lxd/0")
+	c.Assert(err, gc.ErrorMatches, `.*lxd container startup failed: .*no memory`)
+
+	// The container created before the failure is removed.
+	c.Assert(s.Server.Container("test-machine-1-lxd-0"), gc.IsNil)
+	c.Assert(filepath.Join(s.ContainerDir, "test-machine-1-lxd-0"), jc.DoesNotExist)
+}
+
+func (s *LXDSuite) TestDestroyContainer(c *gc.C) {
+	inst := containertesting.CreateContainer(c, s.manager, "1/lxd/0")
+
+	err := s.manager.DestroyContainer(inst.Id())
+	c.Assert(err, jc.ErrorIsNil)
+
+	name := string(inst.Id())
+	c.Assert(s.Server.Container(name), gc.IsNil)
+	// Check that the container dir is no longer in the container dir
+	c.Assert(filepath.Join(s.ContainerDir, name), jc.DoesNotExist)
+	// but instead, in the removed container dir
+	c.Assert(filepath.Join(s.RemovedDir, name), jc.IsDirectory)
+}
+
+func (s *LXDSuite) TestDestroyStoppedContainer(c *gc.C) {
+	s.Server.AddContainer("test-machine-1-lxd-0", false)
+
+	err := s.manager.DestroyContainer("test-machine-1-lxd-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.Server.Container("test-machine-1-lxd-0"), gc.IsNil)
+	for _, req := range s.Server.Requests() {
+		c.Check(req, gc.Not(gc.Equals), "PUT /containers/test-machine-1-lxd-0/state")
+	}
+}
+
+func (s *LXDSuite) TestInstanceAddresses(c *gc.C) {
+	inst := containertesting.CreateContainer(c, s.manager, "1/lxd/0")
+	addresses, err := inst.Addresses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addresses, jc.DeepEquals, []network.Address{
+		network.NewAddress("10.0.3.1", network.ScopeCloudLocal),
+	})
+}
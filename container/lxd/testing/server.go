@@ -0,0 +1,349 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Functions defined in this file should *ONLY* be used for testing.  These
+// functions are exported for testing purposes only, and shouldn't be called
+// from code that isn't in a test file.
+
+package testing
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/juju/juju/container/lxd"
+	containertesting "github.com/juju/juju/container/testing"
+)
+
+const (
+	statusRunning = 103
+	statusStopped = 102
+	statusSuccess = 200
+	statusFailure = 400
+)
+
+// Server is a fake LXD daemon serving a subset of the REST API on a
+// unix socket. Requests are served from in-memory state and every
+// background operation completes immediately.
+type Server struct {
+	*containertesting.SocketServer
+
+	// APIVersion is the API version reported by the server.
+	APIVersion string
+
+	mu         sync.Mutex
+	containers map[string]*lxd.Container
+	sources    map[string]lxd.ContainerSource
+	addresses  map[string]string
+	profiles   map[string]*lxd.Profile
+	operations map[string]lxd.Operation
+	nextOp     int
+	nextAddr   int
+}
+
+// NewServer starts a fake LXD daemon listening on a socket inside dir.
+func NewServer(dir string) (*Server, error) {
+	srv := &Server{
+		APIVersion: "1.0",
+		containers: make(map[string]*lxd.Container),
+		sources:    make(map[string]lxd.ContainerSource),
+		addresses:  make(map[string]string),
+		profiles: map[string]*lxd.Profile{
+			"default": {Name: "default"},
+		},
+		operations: make(map[string]lxd.Operation),
+	}
+	socket, err := containertesting.NewSocketServer(filepath.Join(dir, "unix.socket"), srv)
+	if err != nil {
+		return nil, err
+	}
+	srv.SocketServer = socket
+	return srv, nil
+}
+
+// AddContainer adds a container directly to the server's state.
+func (srv *Server) AddContainer(name string, running bool) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.addContainer(name, nil, nil)
+	if running {
+		srv.setStatus(name, statusRunning)
+	}
+}
+
+// Container returns the named container, or nil if it does not exist.
+func (srv *Server) Container(name string) *lxd.Container {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if c, ok := srv.containers[name]; ok {
+		copied := *c
+		return &copied
+	}
+	return nil
+}
+
+// ContainerSource returns the source the named container was created from.
+func (srv *Server) ContainerSource(name string) lxd.ContainerSource {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	return srv.sources[name]
+}
+
+// Profile returns the named profile, or nil if it does not exist.
+func (srv *Server) Profile(name string) *lxd.Profile {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if p, ok := srv.profiles[name]; ok {
+		copied := *p
+		return &copied
+	}
+	return nil
+}
+
+// ServeHTTP implements http.Handler.
+func (srv *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	// Failures are arranged with paths relative to the API root, e.g.
+	// "/containers". Requests that start background operations report
+	// the failure through the operation.
+	path := strings.TrimPrefix(req.URL.Path, "/1.0")
+	key := req.Method + " " + path
+	failure, failed := srv.Record(req.Method, path)
+	if failed && req.Method == "GET" {
+		writeError(w, http.StatusInternalServerError, failure)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case path == "" && req.Method == "GET":
+		writeSync(w, map[string]string{"api_version": srv.APIVersion})
+	case path == "/containers":
+		srv.serveContainers(w, req, failure)
+	case len(parts) == 2 && parts[0] == "containers":
+		srv.serveContainer(w, req, parts[1], failure)
+	case len(parts) == 3 && parts[0] == "containers" && parts[2] == "state":
+		srv.serveContainerState(w, req, parts[1], failure)
+	case path == "/profiles" && req.Method == "POST":
+		srv.createProfile(w, req, failure)
+	case len(parts) == 2 && parts[0] == "profiles" && req.Method == "GET":
+		profile, ok := srv.profiles[parts[1]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
+		writeSync(w, profile)
+	case len(parts) == 3 && parts[0] == "operations" && parts[2] == "wait" && req.Method == "GET":
+		op, ok := srv.operations[parts[1]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
+		writeSync(w, op)
+	default:
+		writeError(w, http.StatusNotImplemented, fmt.Sprintf("%s not implemented", key))
+	}
+}
+
+func (srv *Server) serveContainers(w http.ResponseWriter, req *http.Request, failure string) {
+	switch req.Method {
+	case "GET":
+		var names []string
+		for name := range srv.containers {
+			names = append(names, "/1.0/containers/"+name)
+		}
+		sort.Strings(names)
+		writeSync(w, names)
+	case "POST":
+		var spec lxd.ContainerSpec
+		if err := json.NewDecoder(req.Body).Decode(&spec); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if failure == "" {
+			if _, ok := srv.containers[spec.Name]; ok {
+				failure = "container already exists"
+			} else if spec.Source.Type != "image" || spec.Source.Alias == "" {
+				failure = "image source required"
+			}
+			for _, profile := range spec.Profiles {
+				if _, ok := srv.profiles[profile]; !ok {
+					failure = fmt.Sprintf("profile %q not found", profile)
+				}
+			}
+		}
+		if failure == "" {
+			srv.addContainer(spec.Name, spec.Profiles, spec.Config)
+			srv.sources[spec.Name] = spec.Source
+		}
+		srv.writeOperation(w, failure)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (srv *Server) serveContainer(w http.ResponseWriter, req *http.Request, name, failure string) {
+	c, ok := srv.containers[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	switch req.Method {
+	case "GET":
+		writeSync(w, c)
+	case "DELETE":
+		if failure == "" && c.StatusCode == statusRunning {
+			failure = "container is running"
+		}
+		if failure == "" {
+			delete(srv.containers, name)
+			delete(srv.sources, name)
+			delete(srv.addresses, name)
+		}
+		srv.writeOperation(w, failure)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (srv *Server) serveContainerState(w http.ResponseWriter, req *http.Request, name, failure string) {
+	c, ok := srv.containers[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	switch req.Method {
+	case "GET":
+		state := lxd.ContainerState{
+			Status:     c.Status,
+			StatusCode: c.StatusCode,
+			Network: map[string]lxd.NetworkInterface{
+				"lo": {Addresses: []lxd.NetworkAddress{
+					{Family: "inet", Address: "127.0.0.1", Scope: "local"},
+				}},
+			},
+		}
+		if c.StatusCode == statusRunning {
+			state.Network["eth0"] = lxd.NetworkInterface{Addresses: []lxd.NetworkAddress{
+				{Family: "inet", Address: srv.addresses[name], Scope: "global"},
+				{Family: "inet6", Address: "fe80::1", Scope: "link"},
+			}}
+		}
+		writeSync(w, state)
+	case "PUT":
+		var body struct {
+			Action string `json:"action"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if failure == "" {
+			switch body.Action {
+			case "start":
+				srv.setStatus(name, statusRunning)
+			case "stop":
+				if c.StatusCode != statusRunning {
+					failure = "The container is already stopped"
+					break
+				}
+				srv.setStatus(name, statusStopped)
+			default:
+				failure = fmt.Sprintf("unknown action %q", body.Action)
+			}
+		}
+		srv.writeOperation(w, failure)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (srv *Server) createProfile(w http.ResponseWriter, req *http.Request, failure string) {
+	var profile lxd.Profile
+	if err := json.NewDecoder(req.Body).Decode(&profile); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if failure != "" {
+		writeError(w, http.StatusInternalServerError, failure)
+		return
+	}
+	if _, ok := srv.profiles[profile.Name]; ok {
+		writeError(w, http.StatusConflict, "profile already exists")
+		return
+	}
+	srv.profiles[profile.Name] = &profile
+	writeSync(w, nil)
+}
+
+func (srv *Server) addContainer(name string, profiles []string, config map[string]string) {
+	srv.nextAddr++
+	srv.containers[name] = &lxd.Container{
+		Name:     name,
+		Profiles: profiles,
+		Config:   config,
+	}
+	srv.addresses[name] = fmt.Sprintf("10.0.3.%d", srv.nextAddr)
+	srv.setStatus(name, statusStopped)
+}
+
+func (srv *Server) setStatus(name string, code int) {
+	c := srv.containers[name]
+	c.StatusCode = code
+	c.Status = "Stopped"
+	if code == statusRunning {
+		c.Status = "Running"
+	}
+}
+
+// writeOperation records a completed background operation, failed if
+// failure is not empty, and writes an async response referring to it.
+func (srv *Server) writeOperation(w http.ResponseWriter, failure string) {
+	srv.nextOp++
+	op := lxd.Operation{
+		Id:         fmt.Sprintf("op-%d", srv.nextOp),
+		Status:     "Success",
+		StatusCode: statusSuccess,
+	}
+	if failure != "" {
+		op.Status = "Failure"
+		op.StatusCode = statusFailure
+		op.Err = failure
+	}
+	srv.operations[op.Id] = op
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"type":        "async",
+		"status":      "Operation created",
+		"status_code": 100,
+		"operation":   "/1.0/operations/" + op.Id,
+	})
+}
+
+func writeSync(w http.ResponseWriter, metadata interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"type":        "sync",
+		"status":      "Success",
+		"status_code": statusSuccess,
+		"metadata":    metadata,
+	})
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"type":       "error",
+		"error":      message,
+		"error_code": code,
+	})
+}
@@ -0,0 +1,46 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Functions defined in this file should *ONLY* be used for testing.  These
+// functions are exported for testing purposes only, and shouldn't be called
+// from code that isn't in a test file.
+
+package testing
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/container/lxd"
+	"github.com/juju/juju/testing"
+)
+
+// TestSuite starts a fake LXD daemon for each test and points the lxd
+// package at its socket.
+type TestSuite struct {
+	testing.BaseSuite
+	Server       *Server
+	ContainerDir string
+	RemovedDir   string
+}
+
+func (s *TestSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.ContainerDir = c.MkDir()
+	s.PatchValue(&container.ContainerDir, s.ContainerDir)
+	s.RemovedDir = c.MkDir()
+	s.PatchValue(&container.RemovedContainerDir, s.RemovedDir)
+	server, err := NewServer(c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+	s.Server = server
+	s.PatchValue(&lxd.SocketPath, server.SocketPath)
+}
+
+func (s *TestSuite) TearDownTest(c *gc.C) {
+	if s.Server != nil {
+		s.Server.Close()
+		s.Server = nil
+	}
+	s.BaseSuite.TearDownTest(c)
+}
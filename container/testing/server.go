@@ -0,0 +1,71 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"net"
+	"net/http"
+	"sync"
+)
+
+// SocketServer serves a fake container daemon on a unix socket. It
+// records the requests served and the failures arranged for them, for
+// the daemon's handler to consult with Record.
+type SocketServer struct {
+	SocketPath string
+
+	listener net.Listener
+
+	mu       sync.Mutex
+	requests []string
+	failures map[string]string
+}
+
+// NewSocketServer starts serving the given handler on a unix socket
+// at socketPath.
+func NewSocketServer(socketPath string, handler http.Handler) (*SocketServer, error) {
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, err
+	}
+	go http.Serve(listener, handler)
+	return &SocketServer{
+		SocketPath: socketPath,
+		listener:   listener,
+		failures:   make(map[string]string),
+	}, nil
+}
+
+// Close stops the server.
+func (srv *SocketServer) Close() error {
+	return srv.listener.Close()
+}
+
+// Requests returns the method and path of every request served so far.
+func (srv *SocketServer) Requests() []string {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	return append([]string(nil), srv.requests...)
+}
+
+// Fail arranges for the next request with the given method and path
+// to fail with the given message.
+func (srv *SocketServer) Fail(method, path, message string) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.failures[method+" "+path] = message
+}
+
+// Record records a request with the given method and path. It returns
+// the failure arranged for the request, if any, which is then
+// forgotten.
+func (srv *SocketServer) Record(method, path string) (failure string, failed bool) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	key := method + " " + path
+	srv.requests = append(srv.requests, key)
+	failure, failed = srv.failures[key]
+	delete(srv.failures, key)
+	return failure, failed
+}
@@ -37,6 +37,11 @@ type StartInstanceParams struct {
 	// this information to distribute instances for
 	// high availability.
 	DistributionGroup func() ([]instance.Id, error)
+
+	// Subnets, if non-empty, holds the subnets matching the
+	// spaces constraint of the machine being provisioned. The
+	// InstanceBroker should start the instance in one of them.
+	Subnets []network.BasicInfo
 }
 
 // StartInstanceResult holds the result of an
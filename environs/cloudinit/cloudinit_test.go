@@ -988,6 +988,30 @@ func (*cloudinitSuite) createMachineConfig(c *gc.C, environConfig *config.Config
 	return machineConfig
 }
 
+func (s *cloudinitSuite) TestCloudInitSystemdSeries(c *gc.C) {
+	environConfig := minimalConfig(c)
+	machineCfg := s.createMachineConfig(c, environConfig)
+	machineCfg.Series = "vivid"
+	cloudcfg := coreCloudinit.New()
+	udata, err := cloudinit.NewUserdataConfig(machineCfg, cloudcfg)
+	c.Assert(err, jc.ErrorIsNil)
+	err = udata.Configure()
+	c.Assert(err, jc.ErrorIsNil)
+
+	var scripts []string
+	for _, cmd := range cloudcfg.RunCmds() {
+		if script, ok := cmd.(string); ok {
+			scripts = append(scripts, script)
+		}
+	}
+	assertScriptMatch(c, scripts, `
+cat > /etc/systemd/system/jujud-machine-42\.service << 'EOF'\\n\[Unit\]\\nDescription=juju machine-42 agent.*
+systemctl daemon-reload
+systemctl enable jujud-machine-42\.service
+systemctl start jujud-machine-42\.service
+`, false)
+}
+
 func (s *cloudinitSuite) TestAptProxyNotWrittenIfNotSet(c *gc.C) {
 	environConfig := minimalConfig(c)
 	machineCfg := s.createMachineConfig(c, environConfig)
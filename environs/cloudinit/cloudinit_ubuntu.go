@@ -22,6 +22,9 @@ import (
 	"github.com/juju/juju/cloudinit"
 	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/service"
+	"github.com/juju/juju/service/common"
+	"github.com/juju/juju/service/systemd"
 	"github.com/juju/juju/service/upstart"
 )
 
@@ -312,23 +315,35 @@ func toolsDownloadCommand(curlCommand string, urls []string) string {
 func (w *ubuntuConfigure) addMachineAgentToBoot(tag string) error {
 	// Make the agent run via a symbolic link to the actual tools
 	// directory, so it can upgrade itself without needing to change
-	// the upstart script.
+	// the service configuration.
 	toolsDir := agenttool.ToolsDir(w.mcfg.DataDir, tag)
 	// TODO(dfc) ln -nfs, so it doesn't fail if for some reason that the target already exists
 	w.conf.AddScripts(fmt.Sprintf("ln -s %v %s", w.mcfg.Tools.Version, shquote(toolsDir)))
 
 	name := w.mcfg.MachineAgentServiceName
-	conf := upstart.MachineAgentUpstartService(
-		name, toolsDir, w.mcfg.DataDir, w.mcfg.LogDir, tag, w.mcfg.MachineId, osenv.FeatureFlags())
-	cmds, err := conf.InstallCommands()
+	conf := common.MachineAgentConf(
+		toolsDir, w.mcfg.DataDir, w.mcfg.LogDir, tag, w.mcfg.MachineId, osenv.FeatureFlags())
+	initSystem := service.SeriesInitSystem(w.mcfg.Series)
+	cmds, err := serviceInstallCommands(initSystem, name, conf)
 	if err != nil {
-		return errors.Annotatef(err, "cannot make cloud-init upstart script for the %s agent", tag)
+		return errors.Annotatef(err, "cannot make cloud-init %s script for the %s agent", initSystem, tag)
 	}
 	w.conf.AddRunCmd(cloudinit.LogProgressCmd("Starting Juju machine agent (%s)", name))
 	w.conf.AddScripts(cmds...)
 	return nil
 }
 
+// serviceInstallCommands returns the shell commands that install
+// and start the named service under the given init system.
+func serviceInstallCommands(initSystem, name string, conf common.Conf) ([]string, error) {
+	switch initSystem {
+	case service.InitSystemSystemd:
+		return systemd.NewService(name, conf).InstallCommands()
+	default:
+		return upstart.NewService(name, conf).InstallCommands()
+	}
+}
+
 func (w *ubuntuConfigure) Render() ([]byte, error) {
 	return w.renderer.Render(w.conf)
 }
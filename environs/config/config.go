@@ -6,6 +6,7 @@ package config
 import (
 	"fmt"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -22,6 +23,7 @@ import (
 
 	"github.com/juju/juju/cert"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/utils/cron"
 	"github.com/juju/juju/version"
 )
 
@@ -42,6 +44,39 @@ const (
 	// instance security groups.
 	FwNone = "none"
 
+	// MetricsSenderNone requests that charm metrics are discarded
+	// rather than sent anywhere.
+	MetricsSenderNone = "none"
+
+	// MetricsSenderCollector requests that charm metrics are sent to
+	// the metrics collection service.
+	MetricsSenderCollector = "collector"
+
+	// MetricsSenderWebhook requests that charm metrics are posted as
+	// JSON to the URL given by metrics-webhook-url.
+	MetricsSenderWebhook = "webhook"
+
+	// MetricsSenderSpool requests that charm metrics are appended as
+	// JSON lines to a file in the directory given by
+	// metrics-spool-dir on the state server.
+	MetricsSenderSpool = "spool"
+
+	// BackupsStorageState requests that backup archives are stored
+	// in the state database.
+	BackupsStorageState = "state"
+
+	// BackupsStorageProvider requests that backup archives are stored
+	// in the environment provider's storage.
+	BackupsStorageProvider = "provider"
+
+	// BackupsStorageLocal requests that backup archives are stored in
+	// the directory given by backups-storage-dir on the state server.
+	BackupsStorageLocal = "local"
+
+	// BackupsStorageS3 requests that backup archives are stored in
+	// the S3-compatible bucket given by the backups-s3-* settings.
+	BackupsStorageS3 = "s3"
+
 	// DefaultStatePort is the default port the state server is listening on.
 	DefaultStatePort int = 37017
 
@@ -65,6 +100,20 @@ const (
 	// refresh addresses from the provider each time.
 	DefaultBootstrapSSHAddressesDelay int = 10
 
+	// DefaultProvisionerStartAttempts is the number of times the
+	// provisioner tries to start an instance for a machine before
+	// giving up.
+	DefaultProvisionerStartAttempts int = 3
+
+	// DefaultProvisionerRetryDelay is the amount of time the
+	// provisioner waits before its first retry to start an instance,
+	// in seconds. The delay doubles after each further attempt.
+	DefaultProvisionerRetryDelay int = 10
+
+	// DefaultCharmStoreURL is the URL of the charm store API used
+	// when the environment does not specify one.
+	DefaultCharmStoreURL = "https://api.jujucharms.com/charmstore"
+
 	// fallbackLtsSeries is the latest LTS series we'll use, if we fail to
 	// obtain this information from the system.
 	fallbackLtsSeries string = "trusty"
@@ -101,6 +150,12 @@ const (
 	// ProvisionerHarvestModeKey stores the key for this setting.
 	ProvisionerHarvestModeKey = "provisioner-harvest-mode"
 
+	// ProvisionerStartAttemptsKey stores the key for this setting.
+	ProvisionerStartAttemptsKey = "provisioner-start-attempts"
+
+	// ProvisionerRetryDelayKey stores the key for this setting.
+	ProvisionerRetryDelayKey = "provisioner-retry-delay"
+
 	// AgentStreamKey stores the key for this setting.
 	AgentStreamKey = "agent-stream"
 
@@ -146,6 +201,51 @@ const (
 	// PreventAllChangesKey stores the value for this setting
 	PreventAllChangesKey = BlockKeyPrefix + "all-changes"
 
+	// MetricsSenderKey stores the key for this setting.
+	MetricsSenderKey = "metrics-sender"
+
+	// MetricsWebhookURLKey stores the key for this setting.
+	MetricsWebhookURLKey = "metrics-webhook-url"
+
+	// MetricsWebhookAuthKey stores the key for this setting.
+	MetricsWebhookAuthKey = "metrics-webhook-auth"
+
+	// MetricsSpoolDirKey stores the key for this setting.
+	MetricsSpoolDirKey = "metrics-spool-dir"
+
+	// BackupsScheduleKey stores the key for this setting.
+	BackupsScheduleKey = "backups-schedule"
+
+	// BackupsKeepLastKey stores the key for this setting.
+	BackupsKeepLastKey = "backups-keep-last"
+
+	// BackupsKeepDailyKey stores the key for this setting.
+	BackupsKeepDailyKey = "backups-keep-daily"
+
+	// BackupsKeepWeeklyKey stores the key for this setting.
+	BackupsKeepWeeklyKey = "backups-keep-weekly"
+
+	// BackupsStorageKey stores the key for this setting.
+	BackupsStorageKey = "backups-storage"
+
+	// BackupsStorageDirKey stores the key for this setting.
+	BackupsStorageDirKey = "backups-storage-dir"
+
+	// BackupsS3EndpointKey stores the key for this setting.
+	BackupsS3EndpointKey = "backups-s3-endpoint"
+
+	// BackupsS3BucketKey stores the key for this setting.
+	BackupsS3BucketKey = "backups-s3-bucket"
+
+	// BackupsS3AccessKeyKey stores the key for this setting.
+	BackupsS3AccessKeyKey = "backups-s3-access-key"
+
+	// BackupsS3SecretKeyKey stores the key for this setting.
+	BackupsS3SecretKeyKey = "backups-s3-secret-key"
+
+	// CharmStoreURLKey stores the key for this setting.
+	CharmStoreURLKey = "charm-store-url"
+
 	//
 	// Deprecated Settings Attributes
 	//
@@ -563,12 +663,43 @@ func Validate(cfg, old *Config) error {
 			" of key-value pairs, not %q", authToken)
 	}
 
+	if v, ok := cfg.defined[CharmStoreURLKey].(string); ok && v != "" {
+		u, err := url.Parse(v)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid %s %q", CharmStoreURLKey, v)
+		}
+	}
+
 	// Ensure that the given harvesting method is valid.
 	if hvstMeth, ok := cfg.defined[ProvisionerHarvestModeKey].(string); ok {
 		if _, err := ParseHarvestMode(hvstMeth); err != nil {
 			return err
 		}
 	}
+	for _, attr := range []string{ProvisionerStartAttemptsKey, ProvisionerRetryDelayKey} {
+		if v, ok := cfg.defined[attr].(int); ok && v < 0 {
+			return fmt.Errorf("%s must not be negative, got %d", attr, v)
+		}
+	}
+
+	if err := validateMetricsSender(cfg); err != nil {
+		return err
+	}
+
+	// Check the backups schedule and retention policy.
+	if schedule, ok := cfg.BackupsSchedule(); ok {
+		if _, err := cron.Parse(schedule); err != nil {
+			return errors.Annotatef(err, "invalid %s", BackupsScheduleKey)
+		}
+	}
+	for _, attr := range []string{BackupsKeepLastKey, BackupsKeepDailyKey, BackupsKeepWeeklyKey} {
+		if v, ok := cfg.defined[attr].(int); ok && v < 0 {
+			return fmt.Errorf("%s must not be negative, got %d", attr, v)
+		}
+	}
+	if err := validateBackupsStorage(cfg); err != nil {
+		return err
+	}
 
 	// Check the immutable config values.  These can't change
 	if old != nil {
@@ -603,6 +734,73 @@ func Validate(cfg, old *Config) error {
 	return nil
 }
 
+// validateMetricsSender checks that the metrics sender is known, and
+// that the attributes it requires are specified and valid.
+func validateMetricsSender(cfg *Config) error {
+	switch sender := cfg.MetricsSender(); sender {
+	case MetricsSenderNone, MetricsSenderCollector:
+	case MetricsSenderWebhook:
+		rawURL, ok := cfg.MetricsWebhookURL()
+		if !ok {
+			return fmt.Errorf("%s must be set when %s is %q", MetricsWebhookURLKey, MetricsSenderKey, sender)
+		}
+		u, err := url.Parse(rawURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid %s %q", MetricsWebhookURLKey, rawURL)
+		}
+	case MetricsSenderSpool:
+		dir, ok := cfg.MetricsSpoolDir()
+		if !ok {
+			return fmt.Errorf("%s must be set when %s is %q", MetricsSpoolDirKey, MetricsSenderKey, sender)
+		}
+		if !filepath.IsAbs(dir) {
+			return fmt.Errorf("%s %q is not an absolute path", MetricsSpoolDirKey, dir)
+		}
+	default:
+		return fmt.Errorf("invalid %s in environment configuration: %q", MetricsSenderKey, sender)
+	}
+	return nil
+}
+
+// validateBackupsStorage checks that the backups storage is known, and
+// that the attributes it requires are specified and valid. The
+// attributes of the other storages are checked too if they are set,
+// as archives stored in them earlier are still read from them.
+func validateBackupsStorage(cfg *Config) error {
+	storage := cfg.BackupsStorage()
+	switch storage {
+	case BackupsStorageState, BackupsStorageProvider, BackupsStorageLocal, BackupsStorageS3:
+	default:
+		return fmt.Errorf("invalid %s in environment configuration: %q", BackupsStorageKey, storage)
+	}
+
+	dir, ok := cfg.BackupsStorageDir()
+	if !ok && storage == BackupsStorageLocal {
+		return fmt.Errorf("%s must be set when %s is %q", BackupsStorageDirKey, BackupsStorageKey, storage)
+	}
+	if ok && !filepath.IsAbs(dir) {
+		return fmt.Errorf("%s %q is not an absolute path", BackupsStorageDirKey, dir)
+	}
+
+	endpoint, ok := cfg.BackupsS3Endpoint()
+	if !ok && storage != BackupsStorageS3 {
+		return nil
+	}
+	for _, attr := range []string{BackupsS3EndpointKey, BackupsS3BucketKey, BackupsS3AccessKeyKey, BackupsS3SecretKeyKey} {
+		if v, _ := cfg.defined[attr].(string); v == "" {
+			if storage == BackupsStorageS3 {
+				return fmt.Errorf("%s must be set when %s is %q", attr, BackupsStorageKey, storage)
+			}
+			return fmt.Errorf("%s must be set when %s is set", attr, BackupsS3EndpointKey)
+		}
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid %s %q", BackupsS3EndpointKey, endpoint)
+	}
+	return nil
+}
+
 func isEmpty(val interface{}) bool {
 	switch val := val.(type) {
 	case nil:
@@ -1006,6 +1204,15 @@ func (c *Config) CharmStoreAuth() (string, bool) {
 	return auth, auth != ""
 }
 
+// CharmStoreURL returns the URL of the charm store API from which
+// charm store bundles are fetched.
+func (c *Config) CharmStoreURL() string {
+	if v, _ := c.defined[CharmStoreURLKey].(string); v != "" {
+		return v
+	}
+	return DefaultCharmStoreURL
+}
+
 // ProvisionerHarvestMode reports the harvesting methodology the
 // provisioner should take.
 func (c *Config) ProvisionerHarvestMode() HarvestMode {
@@ -1022,6 +1229,28 @@ func (c *Config) ProvisionerHarvestMode() HarvestMode {
 	}
 }
 
+// ProvisionerStartAttempts returns the number of times the
+// provisioner tries to start an instance for a machine before
+// recording the failure on the machine. Both 0 and 1 disable
+// retrying; the default is used only when the setting is absent.
+func (c *Config) ProvisionerStartAttempts() int {
+	if v, ok := c.defined[ProvisionerStartAttemptsKey].(int); ok {
+		return v
+	}
+	return DefaultProvisionerStartAttempts
+}
+
+// ProvisionerRetryDelay returns the amount of time the provisioner
+// waits before its first retry to start an instance. A delay of 0
+// retries immediately; the default is used only when the setting is
+// absent.
+func (c *Config) ProvisionerRetryDelay() time.Duration {
+	if v, ok := c.defined[ProvisionerRetryDelayKey].(int); ok {
+		return time.Duration(v) * time.Second
+	}
+	return time.Duration(DefaultProvisionerRetryDelay) * time.Second
+}
+
 // ImageStream returns the simplestreams stream
 // used to identify which image ids to search
 // when starting an instance.
@@ -1072,6 +1301,113 @@ func (c *Config) DisableNetworkManagement() (bool, bool) {
 	return v, ok
 }
 
+// MetricsSender returns the name of the sender used to send the
+// metrics recorded by charms, one of MetricsSenderNone (the default),
+// MetricsSenderCollector, MetricsSenderWebhook or MetricsSenderSpool.
+func (c *Config) MetricsSender() string {
+	if v, _ := c.defined[MetricsSenderKey].(string); v != "" {
+		return v
+	}
+	return MetricsSenderNone
+}
+
+// MetricsWebhookURL returns the URL that the webhook metrics sender
+// posts metrics to.
+func (c *Config) MetricsWebhookURL() (string, bool) {
+	v, ok := c.defined[MetricsWebhookURLKey].(string)
+	return v, ok && v != ""
+}
+
+// MetricsWebhookAuth returns the value of the Authorization header
+// sent by the webhook metrics sender, if any.
+func (c *Config) MetricsWebhookAuth() (string, bool) {
+	v, ok := c.defined[MetricsWebhookAuthKey].(string)
+	return v, ok && v != ""
+}
+
+// MetricsSpoolDir returns the directory on the state server that the
+// spool metrics sender writes metrics to.
+func (c *Config) MetricsSpoolDir() (string, bool) {
+	v, ok := c.defined[MetricsSpoolDirKey].(string)
+	return v, ok && v != ""
+}
+
+// BackupsSchedule returns the schedule of the backups taken
+// automatically by the state servers, in the format accepted by
+// cron.Parse, and whether it is set.
+func (c *Config) BackupsSchedule() (string, bool) {
+	v, ok := c.defined[BackupsScheduleKey].(string)
+	return v, ok && v != ""
+}
+
+// BackupsKeepLast returns the number of the most recent scheduled
+// backups to keep, or 0 if they are not kept by count.
+func (c *Config) BackupsKeepLast() int {
+	v, _ := c.defined[BackupsKeepLastKey].(int)
+	return v
+}
+
+// BackupsKeepDaily returns the number of days for which the most
+// recent scheduled backup of the day is kept, or 0 if daily backups
+// are not kept.
+func (c *Config) BackupsKeepDaily() int {
+	v, _ := c.defined[BackupsKeepDailyKey].(int)
+	return v
+}
+
+// BackupsKeepWeekly returns the number of weeks for which the most
+// recent scheduled backup of the week is kept, or 0 if weekly backups
+// are not kept.
+func (c *Config) BackupsKeepWeekly() int {
+	v, _ := c.defined[BackupsKeepWeeklyKey].(int)
+	return v
+}
+
+// BackupsStorage returns the name of the storage new backup archives
+// are stored in, one of BackupsStorageState (the default),
+// BackupsStorageProvider, BackupsStorageLocal or BackupsStorageS3.
+func (c *Config) BackupsStorage() string {
+	if v, _ := c.defined[BackupsStorageKey].(string); v != "" {
+		return v
+	}
+	return BackupsStorageState
+}
+
+// BackupsStorageDir returns the directory on the state server that
+// the local backups storage keeps archives in.
+func (c *Config) BackupsStorageDir() (string, bool) {
+	v, ok := c.defined[BackupsStorageDirKey].(string)
+	return v, ok && v != ""
+}
+
+// BackupsS3Endpoint returns the URL of the S3-compatible service the
+// s3 backups storage keeps archives in.
+func (c *Config) BackupsS3Endpoint() (string, bool) {
+	v, ok := c.defined[BackupsS3EndpointKey].(string)
+	return v, ok && v != ""
+}
+
+// BackupsS3Bucket returns the name of the bucket the s3 backups
+// storage keeps archives in.
+func (c *Config) BackupsS3Bucket() string {
+	v, _ := c.defined[BackupsS3BucketKey].(string)
+	return v
+}
+
+// BackupsS3AccessKey returns the access key the s3 backups storage
+// authenticates with.
+func (c *Config) BackupsS3AccessKey() string {
+	v, _ := c.defined[BackupsS3AccessKeyKey].(string)
+	return v
+}
+
+// BackupsS3SecretKey returns the secret key the s3 backups storage
+// authenticates with.
+func (c *Config) BackupsS3SecretKey() string {
+	v, _ := c.defined[BackupsS3SecretKeyKey].(string)
+	return v
+}
+
 // UnknownAttrs returns a copy of the raw configuration attributes
 // that are supposedly specific to the environment type. They could
 // also be wrong attributes, though. Only the specific environment
@@ -1137,7 +1473,10 @@ var fields = schema.Fields{
 	"rsyslog-ca-cert":            schema.String(),
 	"logging-config":             schema.String(),
 	"charm-store-auth":           schema.String(),
+	CharmStoreURLKey:             schema.String(),
 	ProvisionerHarvestModeKey:    schema.String(),
+	ProvisionerStartAttemptsKey:  schema.ForceInt(),
+	ProvisionerRetryDelayKey:     schema.ForceInt(),
 	HttpProxyKey:                 schema.String(),
 	HttpsProxyKey:                schema.String(),
 	FtpProxyKey:                  schema.String(),
@@ -1161,6 +1500,20 @@ var fields = schema.Fields{
 	PreventDestroyEnvironmentKey: schema.Bool(),
 	PreventRemoveObjectKey:       schema.Bool(),
 	PreventAllChangesKey:         schema.Bool(),
+	MetricsSenderKey:             schema.String(),
+	MetricsWebhookURLKey:         schema.String(),
+	MetricsWebhookAuthKey:        schema.String(),
+	MetricsSpoolDirKey:           schema.String(),
+	BackupsScheduleKey:           schema.String(),
+	BackupsKeepLastKey:           schema.ForceInt(),
+	BackupsKeepDailyKey:          schema.ForceInt(),
+	BackupsKeepWeeklyKey:         schema.ForceInt(),
+	BackupsStorageKey:            schema.String(),
+	BackupsStorageDirKey:         schema.String(),
+	BackupsS3EndpointKey:         schema.String(),
+	BackupsS3BucketKey:           schema.String(),
+	BackupsS3AccessKeyKey:        schema.String(),
+	BackupsS3SecretKeyKey:        schema.String(),
 
 	// Deprecated fields, retain for backwards compatibility.
 	ToolsMetadataURLKey:    schema.String(),
@@ -1186,6 +1539,8 @@ var alwaysOptional = schema.Defaults{
 	"ca-private-key-path":        schema.Omit,
 	"logging-config":             schema.Omit,
 	ProvisionerHarvestModeKey:    schema.Omit,
+	ProvisionerStartAttemptsKey:  schema.Omit,
+	ProvisionerRetryDelayKey:     schema.Omit,
 	"bootstrap-timeout":          schema.Omit,
 	"bootstrap-retry-delay":      schema.Omit,
 	"bootstrap-addresses-delay":  schema.Omit,
@@ -1205,6 +1560,21 @@ var alwaysOptional = schema.Defaults{
 	PreventDestroyEnvironmentKey: DefaultPreventDestroyEnvironment,
 	PreventRemoveObjectKey:       DefaultPreventRemoveObject,
 	PreventAllChangesKey:         DefaultPreventAllChanges,
+	MetricsSenderKey:             schema.Omit,
+	MetricsWebhookURLKey:         schema.Omit,
+	MetricsWebhookAuthKey:        schema.Omit,
+	MetricsSpoolDirKey:           schema.Omit,
+	BackupsScheduleKey:           schema.Omit,
+	BackupsKeepLastKey:           schema.Omit,
+	BackupsKeepDailyKey:          schema.Omit,
+	BackupsKeepWeeklyKey:         schema.Omit,
+	BackupsStorageKey:            schema.Omit,
+	BackupsStorageDirKey:         schema.Omit,
+	BackupsS3EndpointKey:         schema.Omit,
+	BackupsS3BucketKey:           schema.Omit,
+	BackupsS3AccessKeyKey:        schema.Omit,
+	BackupsS3SecretKeyKey:        schema.Omit,
+	CharmStoreURLKey:             schema.Omit,
 
 	// Deprecated fields, retain for backwards compatibility.
 	ToolsMetadataURLKey:    "",
@@ -615,6 +615,208 @@ var configTests = []configTest{
 			"provisioner-harvest-mode": "yes please",
 		},
 		err: `unknown harvesting method: yes please`,
+	}, {
+		about:       "metrics-sender: webhook",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                 "my-type",
+			"name":                 "my-name",
+			"metrics-sender":       "webhook",
+			"metrics-webhook-url":  "https://metrics.example.com/juju",
+			"metrics-webhook-auth": "Bearer s3cr3t",
+		},
+	}, {
+		about:       "metrics-sender: webhook without URL",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":           "my-type",
+			"name":           "my-name",
+			"metrics-sender": "webhook",
+		},
+		err: `metrics-webhook-url must be set when metrics-sender is "webhook"`,
+	}, {
+		about:       "metrics-sender: webhook with invalid URL",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                "my-type",
+			"name":                "my-name",
+			"metrics-sender":      "webhook",
+			"metrics-webhook-url": "ftp://metrics.example.com",
+		},
+		err: `invalid metrics-webhook-url "ftp://metrics.example.com"`,
+	}, {
+		about:       "metrics-sender: spool",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":              "my-type",
+			"name":              "my-name",
+			"metrics-sender":    "spool",
+			"metrics-spool-dir": "/var/spool/juju-metrics",
+		},
+	}, {
+		about:       "metrics-sender: spool with relative directory",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":              "my-type",
+			"name":              "my-name",
+			"metrics-sender":    "spool",
+			"metrics-spool-dir": "metrics",
+		},
+		err: `metrics-spool-dir "metrics" is not an absolute path`,
+	}, {
+		about:       "metrics-sender: invalid",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":           "my-type",
+			"name":           "my-name",
+			"metrics-sender": "carrier-pigeon",
+		},
+		err: `invalid metrics-sender in environment configuration: "carrier-pigeon"`,
+	}, {
+		about:       "backups schedule and retention",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                "my-type",
+			"name":                "my-name",
+			"backups-schedule":    "0 3 * * *",
+			"backups-keep-last":   3,
+			"backups-keep-daily":  7,
+			"backups-keep-weekly": 4,
+		},
+	}, {
+		about:       "invalid backups schedule",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":             "my-type",
+			"name":             "my-name",
+			"backups-schedule": "0 25 * * *",
+		},
+		err: `invalid backups-schedule: invalid schedule "0 25 \* \* \*": invalid hour "25"`,
+	}, {
+		about:       "negative backups retention",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":               "my-type",
+			"name":               "my-name",
+			"backups-keep-daily": -1,
+		},
+		err: `backups-keep-daily must not be negative, got -1`,
+	}, {
+		about:       "local backups storage",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                "my-type",
+			"name":                "my-name",
+			"backups-storage":     "local",
+			"backups-storage-dir": "/var/backups/juju",
+		},
+	}, {
+		about:       "local backups storage without a directory",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":            "my-type",
+			"name":            "my-name",
+			"backups-storage": "local",
+		},
+		err: `backups-storage-dir must be set when backups-storage is "local"`,
+	}, {
+		about:       "relative backups storage directory",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                "my-type",
+			"name":                "my-name",
+			"backups-storage-dir": "backups",
+		},
+		err: `backups-storage-dir "backups" is not an absolute path`,
+	}, {
+		about:       "s3 backups storage",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                  "my-type",
+			"name":                  "my-name",
+			"backups-storage":       "s3",
+			"backups-s3-endpoint":   "https://s3.example.com",
+			"backups-s3-bucket":     "juju-backups",
+			"backups-s3-access-key": "access",
+			"backups-s3-secret-key": "secret",
+		},
+	}, {
+		about:       "s3 backups storage without a bucket",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                  "my-type",
+			"name":                  "my-name",
+			"backups-storage":       "s3",
+			"backups-s3-endpoint":   "https://s3.example.com",
+			"backups-s3-access-key": "access",
+			"backups-s3-secret-key": "secret",
+		},
+		err: `backups-s3-bucket must be set when backups-storage is "s3"`,
+	}, {
+		about:       "invalid backups s3 endpoint",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                  "my-type",
+			"name":                  "my-name",
+			"backups-s3-endpoint":   "s3.example.com",
+			"backups-s3-bucket":     "juju-backups",
+			"backups-s3-access-key": "access",
+			"backups-s3-secret-key": "secret",
+		},
+		err: `invalid backups-s3-endpoint "s3.example.com"`,
+	}, {
+		about:       "provisioner retry policy",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                       "my-type",
+			"name":                       "my-name",
+			"provisioner-start-attempts": 5,
+			"provisioner-retry-delay":    30,
+		},
+	}, {
+		about:       "provisioner retries disabled",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                       "my-type",
+			"name":                       "my-name",
+			"provisioner-start-attempts": 0,
+			"provisioner-retry-delay":    0,
+		},
+	}, {
+		about:       "negative provisioner start attempts",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                       "my-type",
+			"name":                       "my-name",
+			"provisioner-start-attempts": -1,
+		},
+		err: `provisioner-start-attempts must not be negative, got -1`,
+	}, {
+		about:       "invalid backups storage",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":            "my-type",
+			"name":            "my-name",
+			"backups-storage": "tape",
+		},
+		err: `invalid backups-storage in environment configuration: "tape"`,
+	}, {
+		about:       "charm-store-url",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":            "my-type",
+			"name":            "my-name",
+			"charm-store-url": "https://charmstore.example.com",
+		},
+	}, {
+		about:       "charm-store-url invalid",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":            "my-type",
+			"name":            "my-name",
+			"charm-store-url": "charmstore.example.com",
+		},
+		err: `invalid charm-store-url "charmstore.example.com"`,
 	}, {
 		about:       "default image stream",
 		useDefaults: config.UseDefaults,
@@ -1193,6 +1395,66 @@ func (test configTest) check(c *gc.C, home *gitjujutesting.FakeHome) {
 	} else {
 		c.Assert(cfg.ProvisionerHarvestMode(), gc.Equals, config.HarvestDestroyed)
 	}
+	if v, ok := test.attrs["metrics-sender"]; ok {
+		c.Assert(cfg.MetricsSender(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.MetricsSender(), gc.Equals, config.MetricsSenderNone)
+	}
+	if v, ok := test.attrs["metrics-webhook-url"]; ok {
+		webhookURL, ok := cfg.MetricsWebhookURL()
+		c.Assert(ok, jc.IsTrue)
+		c.Assert(webhookURL, gc.Equals, v)
+	}
+	if v, ok := test.attrs["metrics-webhook-auth"]; ok {
+		auth, ok := cfg.MetricsWebhookAuth()
+		c.Assert(ok, jc.IsTrue)
+		c.Assert(auth, gc.Equals, v)
+	}
+	if v, ok := test.attrs["metrics-spool-dir"]; ok {
+		dir, ok := cfg.MetricsSpoolDir()
+		c.Assert(ok, jc.IsTrue)
+		c.Assert(dir, gc.Equals, v)
+	}
+	if v, ok := test.attrs["backups-schedule"]; ok {
+		schedule, ok := cfg.BackupsSchedule()
+		c.Assert(ok, jc.IsTrue)
+		c.Assert(schedule, gc.Equals, v)
+	} else {
+		_, ok := cfg.BackupsSchedule()
+		c.Assert(ok, jc.IsFalse)
+	}
+	keepLast, _ := test.attrs["backups-keep-last"].(int)
+	c.Assert(cfg.BackupsKeepLast(), gc.Equals, keepLast)
+	keepDaily, _ := test.attrs["backups-keep-daily"].(int)
+	c.Assert(cfg.BackupsKeepDaily(), gc.Equals, keepDaily)
+	keepWeekly, _ := test.attrs["backups-keep-weekly"].(int)
+	c.Assert(cfg.BackupsKeepWeekly(), gc.Equals, keepWeekly)
+	if v, ok := test.attrs["backups-storage"]; ok {
+		c.Assert(cfg.BackupsStorage(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.BackupsStorage(), gc.Equals, config.BackupsStorageState)
+	}
+	if v, ok := test.attrs["backups-s3-endpoint"]; ok {
+		endpoint, ok := cfg.BackupsS3Endpoint()
+		c.Assert(ok, jc.IsTrue)
+		c.Assert(endpoint, gc.Equals, v)
+		c.Assert(cfg.BackupsS3Bucket(), gc.Equals, test.attrs["backups-s3-bucket"])
+	}
+	if v, ok := test.attrs["provisioner-start-attempts"]; ok {
+		c.Assert(cfg.ProvisionerStartAttempts(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.ProvisionerStartAttempts(), gc.Equals, config.DefaultProvisionerStartAttempts)
+	}
+	if v, ok := test.attrs["provisioner-retry-delay"].(int); ok {
+		c.Assert(cfg.ProvisionerRetryDelay(), gc.Equals, time.Duration(v)*time.Second)
+	} else {
+		c.Assert(cfg.ProvisionerRetryDelay(), gc.Equals, time.Duration(config.DefaultProvisionerRetryDelay)*time.Second)
+	}
+	if v, ok := test.attrs["charm-store-url"]; ok {
+		c.Assert(cfg.CharmStoreURL(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.CharmStoreURL(), gc.Equals, config.DefaultCharmStoreURL)
+	}
 	sshOpts := cfg.BootstrapSSHOpts()
 	test.assertDuration(
 		c,
@@ -59,6 +59,15 @@ type EnvironStorage interface {
 	Storage() storage.Storage
 }
 
+// IngressRestricter is implemented by environments whose firewalls
+// can restrict opened ports to source CIDRs. The OpenPorts methods of
+// other environments reject restricted ingress rules.
+type IngressRestricter interface {
+	// SupportsIngressRestrictions reports whether ingress rules
+	// may be restricted to source CIDRs.
+	SupportsIngressRestrictions() bool
+}
+
 // ConfigGetter implements access to an environment's configuration.
 type ConfigGetter interface {
 	// Config returns the configuration data with which the Environ was created.
@@ -175,20 +184,22 @@ type Environ interface {
 	// same remote environment may become invalid
 	Destroy() error
 
-	// OpenPorts opens the given port ranges for the whole environment.
+	// OpenPorts opens the given ingress rules for the whole environment.
 	// Must only be used if the environment was setup with the
-	// FwGlobal firewall mode.
-	OpenPorts(ports []network.PortRange) error
+	// FwGlobal firewall mode. Providers that cannot restrict ports to
+	// source CIDRs must return an error satisfying errors.IsNotSupported
+	// for restricted rules.
+	OpenPorts(rules []network.IngressRule) error
 
-	// ClosePorts closes the given port ranges for the whole environment.
+	// ClosePorts closes the given ingress rules for the whole environment.
 	// Must only be used if the environment was setup with the
 	// FwGlobal firewall mode.
-	ClosePorts(ports []network.PortRange) error
+	ClosePorts(rules []network.IngressRule) error
 
-	// Ports returns the port ranges opened for the whole environment.
+	// Ports returns the ingress rules opened for the whole environment.
 	// Must only be used if the environment was setup with the
 	// FwGlobal firewall mode.
-	Ports() ([]network.PortRange, error)
+	Ports() ([]network.IngressRule, error)
 
 	// Provider returns the EnvironProvider that created this Environ.
 	Provider() EnvironProvider
@@ -8,6 +8,7 @@ import (
 	"github.com/juju/utils"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/storage"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
@@ -23,14 +24,22 @@ func LegacyStorage(st *state.State) (storage.Storage, error) {
 	if err != nil {
 		return nil, fmt.Errorf("cannot get environment config: %v", err)
 	}
-	env, err := New(envConfig)
+	return ProviderStorage(envConfig)
+}
+
+// ProviderStorage creates an Environ from the given config and returns
+// its provider storage interface if it supports one. If the environment
+// does not support provider storage, then it will return an error
+// satisfying errors.IsNotSupported.
+func ProviderStorage(cfg *config.Config) (storage.Storage, error) {
+	env, err := New(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("cannot access environment: %v", err)
 	}
 	if env, ok := env.(EnvironStorage); ok {
 		return env.Storage(), nil
 	}
-	errmsg := fmt.Sprintf("%s provider does not support provider storage", envConfig.Type())
+	errmsg := fmt.Sprintf("%s provider does not support provider storage", cfg.Type())
 	return nil, errors.NewNotSupported(nil, errmsg)
 }
 
@@ -0,0 +1,13 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package feature defines the names of the feature flags that enable
+// in-development features. Feature flags are set with the
+// JUJU_DEV_FEATURE_FLAGS environment variable.
+package feature
+
+// DbLog enables the structured log pipeline: agents send their log
+// records over the API to be stored in the state database, and
+// debug-log reads them from there, instead of rsyslog forwarding
+// them to all-machines.log.
+const DbLog = "db-log"
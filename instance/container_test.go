@@ -29,6 +29,14 @@ func (s *InstanceSuite) TestParseContainerType(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(ctype, gc.Equals, instance.KVM)
 
+	ctype, err = instance.ParseContainerType("lxd")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctype, gc.Equals, instance.LXD)
+
+	ctype, err = instance.ParseContainerType("docker")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctype, gc.Equals, instance.DOCKER)
+
 	ctype, err = instance.ParseContainerType("none")
 	c.Assert(err, gc.ErrorMatches, `invalid container type "none"`)
 
@@ -45,6 +53,14 @@ func (s *InstanceSuite) TestParseContainerTypeOrNone(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(ctype, gc.Equals, instance.KVM)
 
+	ctype, err = instance.ParseContainerTypeOrNone("lxd")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctype, gc.Equals, instance.LXD)
+
+	ctype, err = instance.ParseContainerTypeOrNone("docker")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctype, gc.Equals, instance.DOCKER)
+
 	ctype, err = instance.ParseContainerTypeOrNone("none")
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(ctype, gc.Equals, instance.NONE)
@@ -32,18 +32,20 @@ type Instance interface {
 	// associated with the instance.
 	Addresses() ([]network.Address, error)
 
-	// OpenPorts opens the given ports on the instance, which
-	// should have been started with the given machine id.
-	OpenPorts(machineId string, ports []network.PortRange) error
+	// OpenPorts opens the given ingress rules on the instance, which
+	// should have been started with the given machine id. Providers
+	// that cannot restrict ports to source CIDRs must return an error
+	// satisfying errors.IsNotSupported for restricted rules.
+	OpenPorts(machineId string, rules []network.IngressRule) error
 
-	// ClosePorts closes the given ports on the instance, which
+	// ClosePorts closes the given ingress rules on the instance, which
 	// should have been started with the given machine id.
-	ClosePorts(machineId string, ports []network.PortRange) error
+	ClosePorts(machineId string, rules []network.IngressRule) error
 
-	// Ports returns the set of ports open on the instance, which
-	// should have been started with the given machine id.
-	// The ports are returned as sorted by SortPorts.
-	Ports(machineId string) ([]network.PortRange, error)
+	// Ports returns the set of ingress rules open on the instance,
+	// which should have been started with the given machine id.
+	// The rules are returned as sorted by SortIngressRules.
+	Ports(machineId string) ([]network.IngressRule, error)
 }
 
 // HardwareCharacteristics represents the characteristics of the instance (if known).
@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/storage"
 )
 
 // DeployServiceParams contains the arguments required to deploy the referenced
@@ -29,15 +30,20 @@ type DeployServiceParams struct {
 	// ToMachineSpec is either:
 	// - an existing machine/container id eg "1" or "1/lxc/2"
 	// - a new container on an existing machine eg "lxc:1"
+	// - a zone placement directive eg "zone=us-east-1a", which starts
+	//   each unit on a new machine in that availability zone
 	// Use string to avoid ambiguity around machine 0.
 	ToMachineSpec string
 	// Networks holds a list of networks to required to start on boot.
 	Networks []string
+	// Storage holds the directives according to which storage
+	// instances are created for each unit of the service.
+	Storage []storage.Directive
 }
 
 // DeployService takes a charm and various parameters and deploys it.
 func DeployService(st *state.State, args DeployServiceParams) (*state.Service, error) {
-	if args.NumUnits > 1 && args.ToMachineSpec != "" {
+	if args.NumUnits > 1 && args.ToMachineSpec != "" && !IsZonePlacement(args.ToMachineSpec) {
 		return nil, fmt.Errorf("cannot use --num-units with --to")
 	}
 	settings, err := args.Charm.Config().ValidateSettings(args.ConfigSettings)
@@ -88,6 +94,11 @@ func DeployService(st *state.State, args DeployServiceParams) (*state.Service, e
 			return nil, err
 		}
 	}
+	if len(args.Storage) > 0 {
+		if err := service.SetStorageDirectives(args.Storage); err != nil {
+			return nil, err
+		}
+	}
 	if args.Charm.Meta().Subordinate {
 		return service, nil
 	}
@@ -104,8 +115,19 @@ func DeployService(st *state.State, args DeployServiceParams) (*state.Service, e
 	return service, nil
 }
 
+// zonePlacementPrefix prefixes placement directives which request that
+// a new machine be started in a particular availability zone.
+const zonePlacementPrefix = "zone="
+
+// IsZonePlacement returns whether spec is a zone placement directive,
+// eg "zone=us-east-1a".
+func IsZonePlacement(spec string) bool {
+	return strings.HasPrefix(spec, zonePlacementPrefix) && len(spec) > len(zonePlacementPrefix)
+}
+
 // AddUnits starts n units of the given service and allocates machines
-// to them as necessary.
+// to them as necessary. If machineIdSpec is a zone placement directive,
+// each unit is assigned to a new machine in that zone.
 func AddUnits(st *state.State, svc *state.Service, n int, machineIdSpec string) ([]*state.Unit, error) {
 	units := make([]*state.Unit, n)
 	// Hard code for now till we implement a different approach.
@@ -121,7 +143,11 @@ func AddUnits(st *state.State, svc *state.Service, n int, machineIdSpec string)
 		if err != nil {
 			return nil, fmt.Errorf("cannot add unit %d/%d to service %q: %v", i+1, n, svc.Name(), err)
 		}
-		if machineIdSpec != "" {
+		if IsZonePlacement(machineIdSpec) {
+			if err := assignToNewMachine(st, unit, networks, machineIdSpec); err != nil {
+				return nil, err
+			}
+		} else if machineIdSpec != "" {
 			if n != 1 {
 				return nil, fmt.Errorf("cannot add multiple units of service %q to a single machine", svc.Name())
 			}
@@ -180,3 +206,26 @@ func AddUnits(st *state.State, svc *state.Service, n int, machineIdSpec string)
 	}
 	return units, nil
 }
+
+// assignToNewMachine creates a new machine with the given placement
+// directive, marked as dirty so that nothing else will grab it, and
+// assigns the unit to it.
+func assignToNewMachine(st *state.State, unit *state.Unit, networks []string, placement string) error {
+	unitCons, err := unit.Constraints()
+	if err != nil {
+		return err
+	}
+	template := state.MachineTemplate{
+		Series:            unit.Series(),
+		Jobs:              []state.MachineJob{state.JobHostUnits},
+		Dirty:             true,
+		Constraints:       *unitCons,
+		RequestedNetworks: networks,
+		Placement:         placement,
+	}
+	m, err := st.AddOneMachine(template)
+	if err != nil {
+		return fmt.Errorf("cannot assign unit %q to machine: %v", unit.Name(), err)
+	}
+	return unit.AssignToMachine(m)
+}
@@ -159,6 +159,26 @@ func (s *DeployLocalSuite) TestDeployWithForceMachineRejectsTooManyUnits(c *gc.C
 	c.Assert(err, gc.ErrorMatches, "cannot use --num-units with --to")
 }
 
+func (s *DeployLocalSuite) TestDeployToZoneAllowsManyUnits(c *gc.C) {
+	// Each unit is given a new machine with the zone placement
+	// directive, which is checked by the environment's prechecker.
+	_, err := juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:   "bob",
+			Charm:         s.charm,
+			NumUnits:      2,
+			ToMachineSpec: "zone=az1",
+		})
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "bob/0" to machine: cannot add a new machine: zone=az1 placement is invalid`)
+}
+
+func (s *DeployLocalSuite) TestIsZonePlacement(c *gc.C) {
+	c.Assert(juju.IsZonePlacement("zone=az1"), jc.IsTrue)
+	c.Assert(juju.IsZonePlacement("zone="), jc.IsFalse)
+	c.Assert(juju.IsZonePlacement("0"), jc.IsFalse)
+	c.Assert(juju.IsZonePlacement("lxc:0"), jc.IsFalse)
+}
+
 func (s *DeployLocalSuite) TestDeployForceMachineId(c *gc.C) {
 	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
@@ -33,6 +33,7 @@ import (
 	"github.com/juju/juju/environs/tools"
 	"github.com/juju/juju/juju"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/lease"
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/provider/dummy"
 	"github.com/juju/juju/state"
@@ -82,6 +83,7 @@ type JujuConnSuite struct {
 	oldJujuHome  string
 	DummyConfig  testing.Attrs
 	Factory      *factory.Factory
+	leaseStop    chan struct{} // stops the lease manager's worker loop
 }
 
 const AdminSecret = "dummy-secret"
@@ -258,6 +260,11 @@ func (s *JujuConnSuite) setUpConn(c *gc.C) {
 
 	s.BackingState = environ.(GetStater).GetStateInAPIServer()
 
+	// The API server relies on the lease manager's worker loop, which
+	// would otherwise be run by the machine agent on state servers.
+	s.leaseStop = make(chan struct{})
+	go lease.WorkerLoop(s.BackingState)(s.leaseStop)
+
 	s.State, err = newState(environ, s.BackingState.MongoConnectionInfo())
 	c.Assert(err, jc.ErrorIsNil)
 
@@ -508,6 +515,10 @@ func (s *JujuConnSuite) tearDownConn(c *gc.C) {
 		s.State = nil
 	}
 
+	if s.leaseStop != nil {
+		close(s.leaseStop)
+		s.leaseStop = nil
+	}
 	dummy.Reset()
 	utils.SetHome(s.oldHome)
 	osenv.SetJujuHome(s.oldJujuHome)
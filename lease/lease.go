@@ -166,6 +166,7 @@ func (m *leaseManager) workerLoop(stop <-chan struct{}) error {
 			lease := claimLease(leaseCache, claim)
 			if lease.Id != claim.Id {
 				m.claimLease <- lease
+				continue
 			}
 
 			m.leasePersistor.WriteToken(lease.Namespace, lease)
@@ -178,6 +179,7 @@ func (m *leaseManager) workerLoop(stop <-chan struct{}) error {
 			response.Err = releaseLease(leaseCache, claim.Token)
 			if response.Err != nil {
 				m.releaseLease <- response
+				continue
 			}
 
 			// Unwind our layers from most volatile to least.
@@ -106,6 +106,25 @@ func (s *leaseSuite) TestClaimLease(c *gc.C) {
 	c.Assert(toks[0].Id, gc.Equals, testId)
 }
 
+func (s *leaseSuite) TestClaimLeaseDenied(c *gc.C) {
+	stop := make(chan struct{})
+	go WorkerLoop(&stubLeasePersistor{})(stop)
+	defer func() { stop <- struct{}{} }()
+
+	mgr := Manager()
+	_, err := mgr.ClaimLease(testNamespace, testId, testDuration)
+	c.Assert(err, gc.IsNil)
+
+	ownerId, err := mgr.ClaimLease(testNamespace, "other", testDuration)
+	c.Assert(err, gc.Equals, LeaseClaimDeniedErr)
+	c.Assert(ownerId, gc.Equals, testId)
+
+	// The manager must still respond after denying a claim.
+	ownerId, err = mgr.ClaimLease(testNamespace, testId, testDuration)
+	c.Assert(err, gc.IsNil)
+	c.Assert(ownerId, gc.Equals, testId)
+}
+
 func (s *leaseSuite) TestReleaseLease(c *gc.C) {
 	stop := make(chan struct{})
 	go WorkerLoop(&stubLeasePersistor{})(stop)
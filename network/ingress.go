@@ -0,0 +1,158 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// IngressRule represents a range of ports opened to traffic coming
+// from the given source CIDRs. A rule without source CIDRs is open
+// to all addresses.
+type IngressRule struct {
+	PortRange
+	SourceCIDRs []string
+}
+
+// NewIngressRule returns an IngressRule opening the given port range
+// to the given source CIDRs, which are sorted and deduplicated so
+// that equivalent rules compare equal.
+func NewIngressRule(portRange PortRange, sourceCIDRs ...string) IngressRule {
+	return IngressRule{
+		PortRange:   portRange,
+		SourceCIDRs: NormaliseSourceCIDRs(sourceCIDRs),
+	}
+}
+
+// NormaliseSourceCIDRs returns the given CIDRs sorted and with
+// duplicates removed, or nil if there are none.
+func NormaliseSourceCIDRs(cidrs []string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, cidr := range cidrs {
+		if !seen[cidr] {
+			seen[cidr] = true
+			result = append(result, cidr)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Restricted reports whether the rule only opens its port range to
+// some source addresses.
+func (r IngressRule) Restricted() bool {
+	return len(r.SourceCIDRs) > 0
+}
+
+// Validate checks that the rule's port range and source CIDRs are
+// valid.
+func (r IngressRule) Validate() error {
+	if err := r.PortRange.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	return ValidateSourceCIDRs(r.SourceCIDRs)
+}
+
+// String implements Stringer.
+func (r IngressRule) String() string {
+	if !r.Restricted() {
+		return r.PortRange.String()
+	}
+	return fmt.Sprintf("%s from %s", r.PortRange, strings.Join(r.SourceCIDRs, ","))
+}
+
+// ValidateSourceCIDRs checks that all the given strings are valid
+// CIDRs.
+func ValidateSourceCIDRs(cidrs []string) error {
+	for _, cidr := range cidrs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return errors.NotValidf("source CIDR %q", cidr)
+		}
+	}
+	return nil
+}
+
+// IngressRulesForPortRanges returns rules opening each of the given
+// port ranges to the given source CIDRs.
+func IngressRulesForPortRanges(portRanges []PortRange, sourceCIDRs ...string) []IngressRule {
+	rules := make([]IngressRule, len(portRanges))
+	for i, portRange := range portRanges {
+		rules[i] = NewIngressRule(portRange, sourceCIDRs...)
+	}
+	return rules
+}
+
+// AnyCIDR is the IPv4 source CIDR matching every address. Rules
+// restricted to it are not restricted at all.
+const AnyCIDR = "0.0.0.0/0"
+
+// SplitIngressRules returns the given rules split into rules opening
+// their port range to at most one source CIDR each, sorted and with
+// duplicates removed. Rules that open a port range to AnyCIDR become
+// unrestricted. Comparing split rules tells exactly which source
+// CIDRs need to be opened or closed for each port range.
+func SplitIngressRules(rules []IngressRule) []IngressRule {
+	var split []IngressRule
+	seen := make(map[string]bool)
+	add := func(rule IngressRule) {
+		key := rule.String()
+		if !seen[key] {
+			seen[key] = true
+			split = append(split, rule)
+		}
+	}
+	for _, rule := range rules {
+		if !rule.Restricted() {
+			add(NewIngressRule(rule.PortRange))
+			continue
+		}
+		for _, cidr := range rule.SourceCIDRs {
+			if cidr == AnyCIDR {
+				add(NewIngressRule(rule.PortRange))
+			} else {
+				add(NewIngressRule(rule.PortRange, cidr))
+			}
+		}
+	}
+	SortIngressRules(split)
+	return split
+}
+
+type ingressRuleSlice []IngressRule
+
+func (r ingressRuleSlice) Len() int      { return len(r) }
+func (r ingressRuleSlice) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r ingressRuleSlice) Less(i, j int) bool {
+	if r[i].PortRange != r[j].PortRange {
+		return portRangeSlice{r[i].PortRange, r[j].PortRange}.Less(0, 1)
+	}
+	return r[i].String() < r[j].String()
+}
+
+// SortIngressRules sorts the given rules by port range, then by
+// source CIDRs.
+func SortIngressRules(rules []IngressRule) {
+	sort.Sort(ingressRuleSlice(rules))
+}
+
+// PortRangesForIngressRules returns the port ranges of the given
+// rules, for use by providers that cannot restrict ports to source
+// CIDRs. An error satisfying errors.IsNotSupported is returned if any
+// of the rules is restricted.
+func PortRangesForIngressRules(rules []IngressRule) ([]PortRange, error) {
+	portRanges := make([]PortRange, len(rules))
+	for i, rule := range rules {
+		if rule.Restricted() {
+			return nil, errors.NotSupportedf("restricting %v to source CIDRs", rule.PortRange)
+		}
+		portRanges[i] = rule.PortRange
+	}
+	return portRanges, nil
+}
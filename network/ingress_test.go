@@ -0,0 +1,95 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/testing"
+)
+
+type IngressRuleSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&IngressRuleSuite{})
+
+var httpRange = network.PortRange{FromPort: 80, ToPort: 80, Protocol: "tcp"}
+
+func (*IngressRuleSuite) TestNewIngressRuleSortsCIDRs(c *gc.C) {
+	rule := network.NewIngressRule(httpRange, "10.0.0.0/8", "192.168.0.0/16", "10.0.0.0/8")
+	c.Assert(rule.SourceCIDRs, jc.DeepEquals, []string{"10.0.0.0/8", "192.168.0.0/16"})
+	c.Assert(rule.Restricted(), jc.IsTrue)
+	c.Assert(rule.String(), gc.Equals, "80/tcp from 10.0.0.0/8,192.168.0.0/16")
+}
+
+func (*IngressRuleSuite) TestUnrestricted(c *gc.C) {
+	rule := network.NewIngressRule(httpRange)
+	c.Assert(rule.Restricted(), jc.IsFalse)
+	c.Assert(rule.String(), gc.Equals, "80/tcp")
+}
+
+func (*IngressRuleSuite) TestValidate(c *gc.C) {
+	rule := network.NewIngressRule(httpRange, "203.0.113.4/32")
+	c.Assert(rule.Validate(), jc.ErrorIsNil)
+
+	rule = network.NewIngressRule(httpRange, "203.0.113.4")
+	c.Assert(rule.Validate(), gc.ErrorMatches, `source CIDR "203.0.113.4" not valid`)
+
+	rule = network.NewIngressRule(network.PortRange{FromPort: 90, ToPort: 80, Protocol: "tcp"})
+	c.Assert(rule.Validate(), gc.NotNil)
+}
+
+func (*IngressRuleSuite) TestSortIngressRules(c *gc.C) {
+	sshRange := network.PortRange{FromPort: 22, ToPort: 22, Protocol: "tcp"}
+	rules := []network.IngressRule{
+		network.NewIngressRule(httpRange, "10.0.0.0/8"),
+		network.NewIngressRule(httpRange),
+		network.NewIngressRule(sshRange),
+	}
+	network.SortIngressRules(rules)
+	c.Assert(rules, jc.DeepEquals, []network.IngressRule{
+		network.NewIngressRule(sshRange),
+		network.NewIngressRule(httpRange),
+		network.NewIngressRule(httpRange, "10.0.0.0/8"),
+	})
+}
+
+func (*IngressRuleSuite) TestSplitIngressRules(c *gc.C) {
+	sshRange := network.PortRange{FromPort: 22, ToPort: 22, Protocol: "tcp"}
+	rules := []network.IngressRule{
+		network.NewIngressRule(httpRange, "10.0.0.0/8", "192.168.0.0/16"),
+		network.NewIngressRule(httpRange, "10.0.0.0/8"),
+		network.NewIngressRule(sshRange, network.AnyCIDR),
+		network.NewIngressRule(sshRange),
+	}
+	c.Assert(network.SplitIngressRules(rules), jc.DeepEquals, []network.IngressRule{
+		network.NewIngressRule(sshRange),
+		network.NewIngressRule(httpRange, "10.0.0.0/8"),
+		network.NewIngressRule(httpRange, "192.168.0.0/16"),
+	})
+	c.Assert(network.SplitIngressRules(nil), gc.HasLen, 0)
+}
+
+func (*IngressRuleSuite) TestNormaliseSourceCIDRs(c *gc.C) {
+	c.Assert(network.NormaliseSourceCIDRs(nil), gc.IsNil)
+	c.Assert(network.NormaliseSourceCIDRs([]string{"10.0.0.0/8", "10.0.0.0/8", "1.2.3.4/32"}),
+		jc.DeepEquals, []string{"1.2.3.4/32", "10.0.0.0/8"})
+}
+
+func (*IngressRuleSuite) TestPortRangesForIngressRules(c *gc.C) {
+	sshRange := network.PortRange{FromPort: 22, ToPort: 22, Protocol: "tcp"}
+	rules := network.IngressRulesForPortRanges([]network.PortRange{httpRange, sshRange})
+	portRanges, err := network.PortRangesForIngressRules(rules)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(portRanges, jc.DeepEquals, []network.PortRange{httpRange, sshRange})
+
+	rules = append(rules, network.NewIngressRule(httpRange, "10.0.0.0/8"))
+	_, err = network.PortRangesForIngressRules(rules)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, "restricting 80/tcp to source CIDRs not supported")
+}
@@ -0,0 +1,17 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network
+
+import (
+	"regexp"
+)
+
+// validSpaceName matches valid space names: lower case letters and
+// digits, optionally separated by single hyphens.
+var validSpaceName = regexp.MustCompile("^[a-z0-9]+(-[a-z0-9]+)*$")
+
+// IsValidSpace reports whether name is a valid space name.
+func IsValidSpace(name string) bool {
+	return validSpaceName.MatchString(name)
+}
@@ -0,0 +1,35 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network_test
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+)
+
+type SpaceSuite struct{}
+
+var _ = gc.Suite(&SpaceSuite{})
+
+func (*SpaceSuite) TestIsValidSpace(c *gc.C) {
+	for i, test := range []struct {
+		name  string
+		valid bool
+	}{
+		{"db", true},
+		{"db-tier2", true},
+		{"0", true},
+		{"", false},
+		{"DB", false},
+		{"-db", false},
+		{"db-", false},
+		{"db--tier", false},
+		{"db tier", false},
+		{"db_tier", false},
+	} {
+		c.Logf("test %d: %q", i, test.name)
+		c.Check(network.IsValidSpace(test.name), gc.Equals, test.valid)
+	}
+}
@@ -576,6 +576,8 @@ func (env *azureEnviron) selectInstanceTypeAndImage(constraint *instances.Instan
 var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.Tags,
+	constraints.Spaces,
+	constraints.Zones,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -1200,20 +1202,20 @@ func (env *azureEnviron) Destroy() error {
 
 // OpenPorts is specified in the Environ interface. However, Azure does not
 // support the global firewall mode.
-func (env *azureEnviron) OpenPorts(ports []network.PortRange) error {
+func (env *azureEnviron) OpenPorts(rules []network.IngressRule) error {
 	return nil
 }
 
 // ClosePorts is specified in the Environ interface. However, Azure does not
 // support the global firewall mode.
-func (env *azureEnviron) ClosePorts(ports []network.PortRange) error {
+func (env *azureEnviron) ClosePorts(rules []network.IngressRule) error {
 	return nil
 }
 
 // Ports is specified in the Environ interface.
-func (env *azureEnviron) Ports() ([]network.PortRange, error) {
+func (env *azureEnviron) Ports() ([]network.IngressRule, error) {
 	// TODO: implement this.
-	return []network.PortRange{}, nil
+	return []network.IngressRule{}, nil
 }
 
 // Provider is specified in the Environ interface.
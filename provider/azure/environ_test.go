@@ -1153,8 +1153,8 @@ func (s *environSuite) TestInitialPorts(c *gc.C) {
 		ports, err := inst.Ports("")
 		c.Assert(err, jc.ErrorIsNil)
 		portmap := make(map[network.PortRange]bool)
-		for _, portRange := range ports {
-			portmap[portRange] = true
+		for _, rule := range ports {
+			portmap[rule.PortRange] = true
 		}
 		apiPortRange := network.PortRange{
 			Protocol: "tcp",
@@ -1595,10 +1595,10 @@ func (s *environSuite) TestConstraintsValidator(c *gc.C) {
 	env := s.setupEnvWithDummyMetadata(c)
 	validator, err := env.ConstraintsValidator()
 	c.Assert(err, jc.ErrorIsNil)
-	cons := constraints.MustParse("arch=amd64 tags=bar cpu-power=10")
+	cons := constraints.MustParse("arch=amd64 tags=bar cpu-power=10 spaces=foo zones=az1")
 	unsupported, err := validator.Validate(cons)
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(unsupported, jc.SameContents, []string{"cpu-power", "tags"})
+	c.Assert(unsupported, jc.SameContents, []string{"cpu-power", "tags", "spaces", "zones"})
 }
 
 func (s *environSuite) TestConstraintsValidatorVocab(c *gc.C) {
@@ -120,10 +120,15 @@ func (azInstance *azureInstance) ipAddress() string {
 	return azInstance.roleInstance.IPAddress
 }
 
-// OpenPorts is specified in the Instance interface.
-func (azInstance *azureInstance) OpenPorts(machineId string, portRange []network.PortRange) error {
+// OpenPorts is specified in the Instance interface. Azure endpoints
+// cannot be restricted to source CIDRs.
+func (azInstance *azureInstance) OpenPorts(machineId string, rules []network.IngressRule) error {
+	portRanges, err := network.PortRangesForIngressRules(rules)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	return azInstance.apiCall(true, func(api *gwacl.ManagementAPI) error {
-		return azInstance.openEndpoints(api, portRange)
+		return azInstance.openEndpoints(api, portRanges)
 	})
 }
 
@@ -179,9 +184,13 @@ func (azInstance *azureInstance) openEndpoints(api *gwacl.ManagementAPI, portRan
 }
 
 // ClosePorts is specified in the Instance interface.
-func (azInstance *azureInstance) ClosePorts(machineId string, ports []network.PortRange) error {
+func (azInstance *azureInstance) ClosePorts(machineId string, rules []network.IngressRule) error {
+	portRanges, err := network.PortRangesForIngressRules(rules)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	return azInstance.apiCall(true, func(api *gwacl.ManagementAPI) error {
-		return azInstance.closeEndpoints(api, ports)
+		return azInstance.closeEndpoints(api, portRanges)
 	})
 }
 
@@ -261,15 +270,17 @@ next:
 }
 
 // Ports is specified in the Instance interface.
-func (azInstance *azureInstance) Ports(machineId string) (ports []network.PortRange, err error) {
+func (azInstance *azureInstance) Ports(machineId string) (rules []network.IngressRule, err error) {
+	var ports []network.PortRange
 	err = azInstance.apiCall(false, func(api *gwacl.ManagementAPI) error {
 		ports, err = azInstance.listPorts(api)
 		return err
 	})
 	if ports != nil {
 		network.SortPortRanges(ports)
+		rules = network.IngressRulesForPortRanges(ports)
 	}
-	return ports, err
+	return rules, err
 }
 
 // listPorts returns the slice of ports (network.Port) that this machine
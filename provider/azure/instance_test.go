@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"launchpad.net/gwacl"
@@ -171,9 +172,9 @@ func (s *instanceSuite) TestOpenPorts(c *gc.C) {
 
 	responses := preparePortChangeConversation(c, s.role)
 	record := gwacl.PatchManagementAPIResponses(responses)
-	err := s.instance.OpenPorts("machine-id", []network.PortRange{
+	err := s.instance.OpenPorts("machine-id", network.IngressRulesForPortRanges([]network.PortRange{
 		{79, 79, "tcp"}, {587, 587, "tcp"}, {9, 9, "udp"},
-	})
+	}))
 	c.Assert(err, jc.ErrorIsNil)
 
 	assertPortChangeConversation(c, *record, []expectedRequest{
@@ -197,13 +198,22 @@ func (s *instanceSuite) TestOpenPorts(c *gc.C) {
 	)
 }
 
+func (s *instanceSuite) TestOpenPortsRestrictedNotSupported(c *gc.C) {
+	record := gwacl.PatchManagementAPIResponses(preparePortChangeConversation(c, s.role))
+	err := s.instance.OpenPorts("machine-id", []network.IngressRule{
+		network.NewIngressRule(network.PortRange{80, 80, "tcp"}, "10.0.0.0/8"),
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Check(*record, gc.HasLen, 0)
+}
+
 func (s *instanceSuite) TestOpenPortsFailsWhenUnableToGetRole(c *gc.C) {
 	responses := preparePortChangeConversation(c, s.role)
 	failPortChangeConversationAt(1, responses) // 1st request, GetRole
 	record := gwacl.PatchManagementAPIResponses(responses)
-	err := s.instance.OpenPorts("machine-id", []network.PortRange{
+	err := s.instance.OpenPorts("machine-id", network.IngressRulesForPortRanges([]network.PortRange{
 		{79, 79, "tcp"}, {587, 587, "tcp"}, {9, 9, "udp"},
-	})
+	}))
 	c.Check(err, gc.ErrorMatches, "GET request failed [(]500: Internal Server Error[)]")
 	c.Check(*record, gc.HasLen, 1)
 }
@@ -212,9 +222,9 @@ func (s *instanceSuite) TestOpenPortsFailsWhenUnableToUpdateRole(c *gc.C) {
 	responses := preparePortChangeConversation(c, s.role)
 	failPortChangeConversationAt(2, responses) // 2nd request, UpdateRole
 	record := gwacl.PatchManagementAPIResponses(responses)
-	err := s.instance.OpenPorts("machine-id", []network.PortRange{
+	err := s.instance.OpenPorts("machine-id", network.IngressRulesForPortRanges([]network.PortRange{
 		{79, 79, "tcp"}, {587, 587, "tcp"}, {9, 9, "udp"},
-	})
+	}))
 	c.Check(err, gc.ErrorMatches, "PUT request failed [(]500: Internal Server Error[)]")
 	c.Check(*record, gc.HasLen, 2)
 }
@@ -255,7 +265,7 @@ func (s *instanceSuite) TestClosePorts(c *gc.C) {
 		responses := preparePortChangeConversation(c, s.role)
 		record := gwacl.PatchManagementAPIResponses(responses)
 
-		err := s.instance.ClosePorts("machine-id", test.removePorts)
+		err := s.instance.ClosePorts("machine-id", network.IngressRulesForPortRanges(test.removePorts))
 		c.Assert(err, jc.ErrorIsNil)
 		assertPortChangeConversation(c, *record, []expectedRequest{
 			{"GET", ".*/deployments/deployment-one/roles/role-one"}, // GetRole
@@ -281,9 +291,9 @@ func (s *instanceSuite) TestClosePortsFailsWhenUnableToGetRole(c *gc.C) {
 	responses := preparePortChangeConversation(c, s.role)
 	failPortChangeConversationAt(1, responses) // 1st request, GetRole
 	record := gwacl.PatchManagementAPIResponses(responses)
-	err := s.instance.ClosePorts("machine-id", []network.PortRange{
+	err := s.instance.ClosePorts("machine-id", network.IngressRulesForPortRanges([]network.PortRange{
 		{79, 79, "tcp"}, {587, 587, "tcp"}, {9, 9, "udp"},
-	})
+	}))
 	c.Check(err, gc.ErrorMatches, "GET request failed [(]500: Internal Server Error[)]")
 	c.Check(*record, gc.HasLen, 1)
 }
@@ -292,9 +302,9 @@ func (s *instanceSuite) TestClosePortsFailsWhenUnableToUpdateRole(c *gc.C) {
 	responses := preparePortChangeConversation(c, s.role)
 	failPortChangeConversationAt(2, responses) // 2nd request, UpdateRole
 	record := gwacl.PatchManagementAPIResponses(responses)
-	err := s.instance.ClosePorts("machine-id", []network.PortRange{
+	err := s.instance.ClosePorts("machine-id", network.IngressRulesForPortRanges([]network.PortRange{
 		{79, 79, "tcp"}, {587, 587, "tcp"}, {9, 9, "udp"},
-	})
+	}))
 	c.Check(err, gc.ErrorMatches, "PUT request failed [(]500: Internal Server Error[)]")
 	c.Check(*record, gc.HasLen, 2)
 }
@@ -373,5 +383,5 @@ func (s *instanceSuite) testPorts(c *gc.C, maskStateServerPorts bool) {
 		expected = append(expected, network.PortRange{s.env.Config().APIPort(), s.env.Config().APIPort(), "tcp"})
 		network.SortPortRanges(expected)
 	}
-	c.Check(ports, gc.DeepEquals, expected)
+	c.Check(ports, gc.DeepEquals, network.IngressRulesForPortRanges(expected))
 }
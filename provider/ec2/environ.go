@@ -74,6 +74,7 @@ var _ environs.Environ = (*environ)(nil)
 var _ simplestreams.HasRegion = (*environ)(nil)
 var _ state.Prechecker = (*environ)(nil)
 var _ state.InstanceDistributor = (*environ)(nil)
+var _ environs.IngressRestricter = (*environ)(nil)
 
 type defaultVpc struct {
 	hasDefaultVpc bool
@@ -491,21 +492,46 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 	}
 	rootDiskSize := uint64(blockDeviceMappings[0].VolumeSize) * 1024
 
+	// If the machine's spaces constraint restricts the subnets it may
+	// be started in, only the zones holding one of them are tried.
+	var zoneSubnets map[string][]string
+	if len(args.Subnets) > 0 {
+		zoneSubnets, err = e.subnetsByZone(args.Subnets)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot get subnets")
+		}
+		err = errors.Errorf("no subnets matching the spaces constraint in availability zones %v", availabilityZones)
+	}
+zoneLoop:
 	for _, availZone := range availabilityZones {
-		instResp, err = runInstances(e.ec2(), &ec2.RunInstances{
-			AvailZone:           availZone,
-			ImageId:             spec.Image.Id,
-			MinCount:            1,
-			MaxCount:            1,
-			UserData:            userData,
-			InstanceType:        spec.InstanceType.Name,
-			SecurityGroups:      groups,
-			BlockDeviceMappings: blockDeviceMappings,
-		})
-		if isZoneConstrainedError(err) {
-			logger.Infof("%q is constrained, trying another availability zone", availZone)
-		} else {
-			break
+		// Without a spaces constraint the zone's default subnet is
+		// used; otherwise each matching subnet in the zone is tried
+		// in turn.
+		subnetIds := []string{""}
+		if zoneSubnets != nil {
+			subnetIds = zoneSubnets[availZone]
+		}
+		for _, subnetId := range subnetIds {
+			instResp, err = runInstances(e.ec2(), &ec2.RunInstances{
+				AvailZone:           availZone,
+				SubnetId:            subnetId,
+				ImageId:             spec.Image.Id,
+				MinCount:            1,
+				MaxCount:            1,
+				UserData:            userData,
+				InstanceType:        spec.InstanceType.Name,
+				SecurityGroups:      groups,
+				BlockDeviceMappings: blockDeviceMappings,
+			})
+			switch {
+			case isZoneConstrainedError(err):
+				logger.Infof("%q is constrained, trying another availability zone", availZone)
+				continue zoneLoop
+			case subnetId != "" && isSubnetConstrainedError(err):
+				logger.Infof("subnet %q is constrained, trying another subnet", subnetId)
+			default:
+				break zoneLoop
+			}
 		}
 	}
 	if err != nil {
@@ -544,6 +570,33 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 
 var runInstances = _runInstances
 
+var ec2Subnets = (*ec2.EC2).Subnets
+
+// subnetsByZone returns the provider ids of the given subnets, keyed
+// by the availability zone each of them is in.
+func (e *environ) subnetsByZone(subnets []network.BasicInfo) (map[string][]string, error) {
+	result := make(map[string][]string)
+	var ids []string
+	for _, subnet := range subnets {
+		// Subnets unknown to the provider cannot be used; asking
+		// for no ids at all would return every subnet.
+		if subnet.ProviderId != "" {
+			ids = append(ids, string(subnet.ProviderId))
+		}
+	}
+	if len(ids) == 0 {
+		return result, nil
+	}
+	resp, err := ec2Subnets(e.ec2(), ids, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, subnet := range resp.Subnets {
+		result[subnet.AvailZone] = append(result[subnet.AvailZone], subnet.Id)
+	}
+	return result, nil
+}
+
 // runInstances calls ec2.RunInstances for a fixed number of attempts until
 // RunInstances returns an error code that does not indicate an error that
 // may be caused by eventual consistency.
@@ -862,32 +915,41 @@ func (e *environ) Destroy() error {
 	return e.Storage().RemoveAll()
 }
 
-func portsToIPPerms(ports []network.PortRange) []ec2.IPPerm {
-	ipPerms := make([]ec2.IPPerm, len(ports))
-	for i, p := range ports {
+// anyCIDR is the source CIDR of permissions allowing access from
+// any address.
+const anyCIDR = network.AnyCIDR
+
+func rulesToIPPerms(rules []network.IngressRule) []ec2.IPPerm {
+	ipPerms := make([]ec2.IPPerm, len(rules))
+	for i, r := range rules {
+		sourceIPs := r.SourceCIDRs
+		if !r.Restricted() {
+			sourceIPs = []string{anyCIDR}
+		}
 		ipPerms[i] = ec2.IPPerm{
-			Protocol:  p.Protocol,
-			FromPort:  p.FromPort,
-			ToPort:    p.ToPort,
-			SourceIPs: []string{"0.0.0.0/0"},
+			Protocol:  r.Protocol,
+			FromPort:  r.FromPort,
+			ToPort:    r.ToPort,
+			SourceIPs: sourceIPs,
 		}
 	}
 	return ipPerms
 }
 
-func (e *environ) openPortsInGroup(name string, ports []network.PortRange) error {
-	if len(ports) == 0 {
+func (e *environ) openPortsInGroup(name string, rules []network.IngressRule) error {
+	if len(rules) == 0 {
 		return nil
 	}
-	// Give permissions for anyone to access the given ports.
+	// Give permissions for the rules' source addresses to access
+	// the given ports.
 	g, err := e.groupByName(name)
 	if err != nil {
 		return err
 	}
-	ipPerms := portsToIPPerms(ports)
+	ipPerms := rulesToIPPerms(rules)
 	_, err = e.ec2().AuthorizeSecurityGroup(g, ipPerms)
 	if err != nil && ec2ErrCode(err) == "InvalidPermission.Duplicate" {
-		if len(ports) == 1 {
+		if len(rules) == 1 {
 			return nil
 		}
 		// If there's more than one port and we get a duplicate error,
@@ -908,69 +970,75 @@ func (e *environ) openPortsInGroup(name string, ports []network.PortRange) error
 	return nil
 }
 
-func (e *environ) closePortsInGroup(name string, ports []network.PortRange) error {
-	if len(ports) == 0 {
+func (e *environ) closePortsInGroup(name string, rules []network.IngressRule) error {
+	if len(rules) == 0 {
 		return nil
 	}
-	// Revoke permissions for anyone to access the given ports.
+	// Revoke permissions for the rules' source addresses to access
+	// the given ports.
 	// Note that ec2 allows the revocation of permissions that aren't
 	// granted, so this is naturally idempotent.
 	g, err := e.groupByName(name)
 	if err != nil {
 		return err
 	}
-	_, err = e.ec2().RevokeSecurityGroup(g, portsToIPPerms(ports))
+	_, err = e.ec2().RevokeSecurityGroup(g, rulesToIPPerms(rules))
 	if err != nil {
 		return fmt.Errorf("cannot close ports: %v", err)
 	}
 	return nil
 }
 
-func (e *environ) portsInGroup(name string) (ports []network.PortRange, err error) {
+func (e *environ) portsInGroup(name string) (rules []network.IngressRule, err error) {
 	group, err := e.groupInfoByName(name)
 	if err != nil {
 		return nil, err
 	}
 	for _, p := range group.IPPerms {
-		if len(p.SourceIPs) != 1 {
+		if len(p.SourceIPs) == 0 {
 			logger.Warningf("unexpected IP permission found: %v", p)
 			continue
 		}
-		ports = append(ports, network.PortRange{
+		portRange := network.PortRange{
 			Protocol: p.Protocol,
 			FromPort: p.FromPort,
 			ToPort:   p.ToPort,
-		})
+		}
+		// Permissions for the same port range may have been
+		// granted to different source addresses for different
+		// services, so report each source address separately.
+		for _, sourceIP := range p.SourceIPs {
+			rules = append(rules, network.NewIngressRule(portRange, sourceIP))
+		}
 	}
-	network.SortPortRanges(ports)
-	return ports, nil
+	return network.SplitIngressRules(rules), nil
 }
 
-func (e *environ) OpenPorts(ports []network.PortRange) error {
+func (e *environ) OpenPorts(rules []network.IngressRule) error {
 	if e.Config().FirewallMode() != config.FwGlobal {
 		return fmt.Errorf("invalid firewall mode %q for opening ports on environment",
 			e.Config().FirewallMode())
 	}
-	if err := e.openPortsInGroup(e.globalGroupName(), ports); err != nil {
+	if err := e.openPortsInGroup(e.globalGroupName(), rules); err != nil {
 		return err
 	}
-	logger.Infof("opened ports in global group: %v", ports)
+	logger.Infof("opened ports in global group: %v", rules)
 	return nil
 }
 
-func (e *environ) ClosePorts(ports []network.PortRange) error {
+func (e *environ) ClosePorts(rules []network.IngressRule) error {
 	if e.Config().FirewallMode() != config.FwGlobal {
 		return fmt.Errorf("invalid firewall mode %q for closing ports on environment",
 			e.Config().FirewallMode())
 	}
-	if err := e.closePortsInGroup(e.globalGroupName(), ports); err != nil {
+	if err := e.closePortsInGroup(e.globalGroupName(), rules); err != nil {
 		return err
 	}
-	logger.Infof("closed ports in global group: %v", ports)
+	logger.Infof("closed ports in global group: %v", rules)
 	return nil
 }
 
-func (e *environ) Ports() ([]network.PortRange, error) {
+func (e *environ) Ports() ([]network.IngressRule, error) {
 	if e.Config().FirewallMode() != config.FwGlobal {
 		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from environment",
 			e.Config().FirewallMode())
@@ -978,6 +1046,11 @@ func (e *environ) Ports() ([]network.PortRange, error) {
 	return e.portsInGroup(e.globalGroupName())
 }
 
+// SupportsIngressRestrictions is specified on the IngressRestricter interface.
+func (*environ) SupportsIngressRestrictions() bool {
+	return true
+}
+
 func (*environ) Provider() environs.EnvironProvider {
 	return &providerInstance
 }
@@ -1226,6 +1299,17 @@ func isZoneConstrainedError(err error) bool {
 	return false
 }
 
+// isSubnetConstrainedError reports whether the error means that the
+// subnet an instance was to be started in cannot take it, so another
+// subnet in the same zone may be tried.
+func isSubnetConstrainedError(err error) bool {
+	switch ec2ErrCode(err) {
+	case "InsufficientFreeAddressesInSubnet", "InvalidSubnetID.NotFound":
+		return true
+	}
+	return false
+}
+
 // If the err is of type *ec2.Error, ec2ErrCode returns
 // its code, otherwise it returns the empty string.
 func ec2ErrCode(err error) string {
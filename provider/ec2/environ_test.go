@@ -79,11 +79,12 @@ func pInt(i uint64) *uint64 {
 	return &i
 }
 
-func (*Suite) TestPortsToIPPerms(c *gc.C) {
+func (*Suite) TestRulesToIPPerms(c *gc.C) {
 	testCases := []struct {
-		about    string
-		ports    []network.PortRange
-		expected []amzec2.IPPerm
+		about       string
+		ports       []network.PortRange
+		sourceCIDRs []string
+		expected    []amzec2.IPPerm
 	}{{
 		about: "single port",
 		ports: []network.PortRange{{
@@ -132,11 +133,25 @@ func (*Suite) TestPortsToIPPerms(c *gc.C) {
 			ToPort:    120,
 			SourceIPs: []string{"0.0.0.0/0"},
 		}},
+	}, {
+		about: "source CIDRs",
+		ports: []network.PortRange{{
+			FromPort: 80,
+			ToPort:   80,
+			Protocol: "tcp",
+		}},
+		sourceCIDRs: []string{"203.0.113.4/32", "10.0.0.0/8"},
+		expected: []amzec2.IPPerm{{
+			Protocol:  "tcp",
+			FromPort:  80,
+			ToPort:    80,
+			SourceIPs: []string{"10.0.0.0/8", "203.0.113.4/32"},
+		}},
 	}}
 
 	for i, t := range testCases {
 		c.Logf("test %d: %s", i, t.about)
-		ipperms := portsToIPPerms(t.ports)
+		ipperms := rulesToIPPerms(network.IngressRulesForPortRanges(t.ports, t.sourceCIDRs...))
 		c.Assert(ipperms, gc.DeepEquals, t.expected)
 	}
 }
@@ -45,6 +45,7 @@ var (
 	EC2AvailabilityZones        = &ec2AvailabilityZones
 	AvailabilityZoneAllocations = &availabilityZoneAllocations
 	RunInstances                = &runInstances
+	EC2Subnets                  = &ec2Subnets
 )
 
 // BucketStorage returns a storage instance addressing
@@ -558,6 +558,127 @@ func (t *localServerSuite) testStartInstanceAvailZoneOneConstrained(c *gc.C, run
 	c.Check(*hwc.AvailabilityZone, gc.Equals, "az2")
 }
 
+func (t *localServerSuite) patchSubnets(c *gc.C, subnets ...amzec2.Subnet) {
+	t.PatchValue(ec2.EC2Subnets, func(e *amzec2.EC2, ids []string, filter *amzec2.Filter) (*amzec2.SubnetsResp, error) {
+		c.Check(ids, jc.SameContents, []string{"subnet-1", "subnet-2"})
+		return &amzec2.SubnetsResp{Subnets: subnets}, nil
+	})
+}
+
+var spaceSubnets = []network.BasicInfo{
+	{CIDR: "10.0.1.0/24", ProviderId: "subnet-1"},
+	{CIDR: "10.0.2.0/24", ProviderId: "subnet-2"},
+}
+
+func (t *localServerSuite) TestStartInstanceInSubnet(c *gc.C) {
+	env := t.Prepare(c)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	mock := mockAvailabilityZoneAllocations{
+		result: []common.AvailabilityZoneInstances{
+			{ZoneName: "az1"}, {ZoneName: "az2"},
+		},
+	}
+	t.PatchValue(ec2.AvailabilityZoneAllocations, mock.AvailabilityZoneAllocations)
+	t.patchSubnets(c,
+		amzec2.Subnet{Id: "subnet-1", AvailZone: "az2"},
+		amzec2.Subnet{Id: "subnet-2", AvailZone: "az3"},
+	)
+
+	// Only az2 holds one of the subnets, so az1 is not tried.
+	var runArgs []string
+	realRunInstances := *ec2.RunInstances
+	t.PatchValue(ec2.RunInstances, func(e *amzec2.EC2, ri *amzec2.RunInstances) (*amzec2.RunInstancesResp, error) {
+		runArgs = append(runArgs, ri.AvailZone+"/"+ri.SubnetId)
+		// The test server knows nothing of subnets.
+		ri.SubnetId = ""
+		return realRunInstances(e, ri)
+	})
+	_, _, _, err = testing.StartInstanceWithParams(env, "1", environs.StartInstanceParams{
+		Subnets: spaceSubnets,
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(runArgs, gc.DeepEquals, []string{"az2/subnet-1"})
+}
+
+func (t *localServerSuite) TestStartInstanceTriesOtherSubnetsInZone(c *gc.C) {
+	env := t.Prepare(c)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	mock := mockAvailabilityZoneAllocations{
+		result: []common.AvailabilityZoneInstances{{ZoneName: "az1"}},
+	}
+	t.PatchValue(ec2.AvailabilityZoneAllocations, mock.AvailabilityZoneAllocations)
+	t.patchSubnets(c,
+		amzec2.Subnet{Id: "subnet-1", AvailZone: "az1"},
+		amzec2.Subnet{Id: "subnet-2", AvailZone: "az1"},
+	)
+
+	// The first subnet is full, so the instance is started in the
+	// other one in the same zone.
+	var runArgs []string
+	realRunInstances := *ec2.RunInstances
+	t.PatchValue(ec2.RunInstances, func(e *amzec2.EC2, ri *amzec2.RunInstances) (*amzec2.RunInstancesResp, error) {
+		runArgs = append(runArgs, ri.AvailZone+"/"+ri.SubnetId)
+		if ri.SubnetId == "subnet-1" {
+			return nil, &amzec2.Error{Code: "InsufficientFreeAddressesInSubnet"}
+		}
+		ri.SubnetId = ""
+		return realRunInstances(e, ri)
+	})
+	_, _, _, err = testing.StartInstanceWithParams(env, "1", environs.StartInstanceParams{
+		Subnets: spaceSubnets,
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(runArgs, gc.DeepEquals, []string{"az1/subnet-1", "az1/subnet-2"})
+}
+
+func (t *localServerSuite) TestStartInstanceSubnetsWithoutProviderId(c *gc.C) {
+	env := t.Prepare(c)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	mock := mockAvailabilityZoneAllocations{
+		result: []common.AvailabilityZoneInstances{{ZoneName: "az1"}},
+	}
+	t.PatchValue(ec2.AvailabilityZoneAllocations, mock.AvailabilityZoneAllocations)
+	t.PatchValue(ec2.EC2Subnets, func(*amzec2.EC2, []string, *amzec2.Filter) (*amzec2.SubnetsResp, error) {
+		c.Fatalf("unexpected Subnets call")
+		return nil, nil
+	})
+	_, _, _, err = testing.StartInstanceWithParams(env, "1", environs.StartInstanceParams{
+		Subnets: []network.BasicInfo{{CIDR: "10.0.1.0/24"}},
+	}, nil)
+	c.Assert(err, gc.ErrorMatches, `cannot run instances: no subnets matching the spaces constraint in availability zones \[az1\]`)
+}
+
+func (t *localServerSuite) TestStartInstanceNoSubnetInZones(c *gc.C) {
+	env := t.Prepare(c)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	mock := mockAvailabilityZoneAllocations{
+		result: []common.AvailabilityZoneInstances{
+			{ZoneName: "az1"}, {ZoneName: "az2"},
+		},
+	}
+	t.PatchValue(ec2.AvailabilityZoneAllocations, mock.AvailabilityZoneAllocations)
+	t.patchSubnets(c,
+		amzec2.Subnet{Id: "subnet-1", AvailZone: "az3"},
+		amzec2.Subnet{Id: "subnet-2", AvailZone: "az3"},
+	)
+	t.PatchValue(ec2.RunInstances, func(e *amzec2.EC2, ri *amzec2.RunInstances) (*amzec2.RunInstancesResp, error) {
+		c.Fatalf("unexpected RunInstances call")
+		return nil, nil
+	})
+	_, _, _, err = testing.StartInstanceWithParams(env, "1", environs.StartInstanceParams{
+		Subnets: spaceSubnets,
+	}, nil)
+	c.Assert(err, gc.ErrorMatches, `cannot run instances: no subnets matching the spaces constraint in availability zones \[az1 az2\]`)
+}
+
 func (t *localServerSuite) TestAddresses(c *gc.C) {
 	env := t.Prepare(c)
 	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{})
@@ -585,6 +706,38 @@ func (t *localServerSuite) TestAddresses(c *gc.C) {
 	}
 }
 
+func (t *localServerSuite) TestInstancePortsPerSourceCIDR(c *gc.C) {
+	env := t.Prepare(c)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	inst, _ := testing.AssertStartInstance(c, env, "1")
+
+	httpRange := network.PortRange{FromPort: 80, ToPort: 80, Protocol: "tcp"}
+	err = inst.OpenPorts("1", []network.IngressRule{
+		network.NewIngressRule(httpRange, "192.168.0.0/16"),
+		network.NewIngressRule(httpRange, "10.0.0.0/8"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	rules, err := inst.Ports("1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, []network.IngressRule{
+		network.NewIngressRule(httpRange, "10.0.0.0/8"),
+		network.NewIngressRule(httpRange, "192.168.0.0/16"),
+	})
+
+	// Closing the port range for one source CIDR leaves it open to
+	// the other.
+	err = inst.ClosePorts("1", []network.IngressRule{
+		network.NewIngressRule(httpRange, "192.168.0.0/16"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	rules, err = inst.Ports("1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, []network.IngressRule{
+		network.NewIngressRule(httpRange, "10.0.0.0/8"),
+	})
+}
+
 func (t *localServerSuite) TestConstraintsValidatorUnsupported(c *gc.C) {
 	env := t.Prepare(c)
 	validator, err := env.ConstraintsValidator()
@@ -86,7 +86,11 @@ func getPorts(envName string, rules []cloudapi.FirewallRule) []network.PortRange
 	return portRanges
 }
 
-func (env *joyentEnviron) OpenPorts(ports []network.PortRange) error {
+func (env *joyentEnviron) OpenPorts(rules []network.IngressRule) error {
+	ports, err := network.PortRangesForIngressRules(rules)
+	if err != nil {
+		return err
+	}
 	if env.Config().FirewallMode() != config.FwGlobal {
 		return fmt.Errorf("invalid firewall mode %q for opening ports on environment", env.Config().FirewallMode())
 	}
@@ -119,7 +123,11 @@ func (env *joyentEnviron) OpenPorts(ports []network.PortRange) error {
 	return nil
 }
 
-func (env *joyentEnviron) ClosePorts(ports []network.PortRange) error {
+func (env *joyentEnviron) ClosePorts(rules []network.IngressRule) error {
+	ports, err := network.PortRangesForIngressRules(rules)
+	if err != nil {
+		return err
+	}
 	if env.Config().FirewallMode() != config.FwGlobal {
 		return fmt.Errorf("invalid firewall mode %q for closing ports on environment", env.Config().FirewallMode())
 	}
@@ -152,7 +160,7 @@ func (env *joyentEnviron) ClosePorts(ports []network.PortRange) error {
 	return nil
 }
 
-func (env *joyentEnviron) Ports() ([]network.PortRange, error) {
+func (env *joyentEnviron) Ports() ([]network.IngressRule, error) {
 	if env.Config().FirewallMode() != config.FwGlobal {
 		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from environment", env.Config().FirewallMode())
 	}
@@ -162,5 +170,5 @@ func (env *joyentEnviron) Ports() ([]network.PortRange, error) {
 		return nil, fmt.Errorf("cannot get firewall rules: %v", err)
 	}
 
-	return getPorts(env.Config().Name(), fwRules), nil
+	return network.IngressRulesForPortRanges(getPorts(env.Config().Name(), fwRules)), nil
 }
@@ -59,6 +59,8 @@ func (env *joyentEnviron) machineFullName(machineId string) string {
 var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.Tags,
+	constraints.Spaces,
+	constraints.Zones,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
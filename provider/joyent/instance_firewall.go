@@ -32,7 +32,11 @@ func createFirewallRuleVm(envName string, machineId string, portRange network.Po
 	return fmt.Sprintf(firewallRuleVm, envName, machineId, strings.ToLower(portRange.Protocol), portList)
 }
 
-func (inst *joyentInstance) OpenPorts(machineId string, ports []network.PortRange) error {
+func (inst *joyentInstance) OpenPorts(machineId string, rules []network.IngressRule) error {
+	ports, err := network.PortRangesForIngressRules(rules)
+	if err != nil {
+		return err
+	}
 	if inst.env.Config().FirewallMode() != config.FwInstance {
 		return fmt.Errorf("invalid firewall mode %q for opening ports on instance", inst.env.Config().FirewallMode())
 	}
@@ -66,7 +70,11 @@ func (inst *joyentInstance) OpenPorts(machineId string, ports []network.PortRang
 	return nil
 }
 
-func (inst *joyentInstance) ClosePorts(machineId string, ports []network.PortRange) error {
+func (inst *joyentInstance) ClosePorts(machineId string, rules []network.IngressRule) error {
+	ports, err := network.PortRangesForIngressRules(rules)
+	if err != nil {
+		return err
+	}
 	if inst.env.Config().FirewallMode() != config.FwInstance {
 		return fmt.Errorf("invalid firewall mode %q for closing ports on instance", inst.env.Config().FirewallMode())
 	}
@@ -100,7 +108,7 @@ func (inst *joyentInstance) ClosePorts(machineId string, ports []network.PortRan
 	return nil
 }
 
-func (inst *joyentInstance) Ports(machineId string) ([]network.PortRange, error) {
+func (inst *joyentInstance) Ports(machineId string) ([]network.IngressRule, error) {
 	if inst.env.Config().FirewallMode() != config.FwInstance {
 		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from instance", inst.env.Config().FirewallMode())
 	}
@@ -111,5 +119,5 @@ func (inst *joyentInstance) Ports(machineId string) ([]network.PortRange, error)
 		return nil, fmt.Errorf("cannot get firewall rules: %v", err)
 	}
 
-	return getPorts(inst.env.Config().Name(), fwRules), nil
+	return network.IngressRulesForPortRanges(getPorts(inst.env.Config().Name(), fwRules)), nil
 }
@@ -341,6 +341,8 @@ var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.InstanceType,
 	constraints.Tags,
+	constraints.Spaces,
+	constraints.Zones,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -557,17 +559,17 @@ func (env *localEnviron) Destroy() error {
 }
 
 // OpenPorts is specified in the Environ interface.
-func (env *localEnviron) OpenPorts(ports []network.PortRange) error {
+func (env *localEnviron) OpenPorts(rules []network.IngressRule) error {
 	return fmt.Errorf("open ports not implemented")
 }
 
 // ClosePorts is specified in the Environ interface.
-func (env *localEnviron) ClosePorts(ports []network.PortRange) error {
+func (env *localEnviron) ClosePorts(rules []network.IngressRule) error {
 	return fmt.Errorf("close ports not implemented")
 }
 
 // Ports is specified in the Environ interface.
-func (env *localEnviron) Ports() ([]network.PortRange, error) {
+func (env *localEnviron) Ports() ([]network.IngressRule, error) {
 	return nil, nil
 }
 
@@ -798,12 +798,23 @@ func (environ *maasEnviron) StartInstance(args environs.StartInstanceParams) (
 	requestedNetworks := args.MachineConfig.Networks
 	includeNetworks := append(args.Constraints.IncludeNetworks(), requestedNetworks...)
 	excludeNetworks := args.Constraints.ExcludeNetworks()
+	var subnets []string
+	for _, subnet := range args.Subnets {
+		// Subnets unknown to MAAS cannot be asked for.
+		if subnet.ProviderId != "" {
+			subnets = append(subnets, string(subnet.ProviderId))
+		}
+	}
+	if len(args.Subnets) > 0 && len(subnets) == 0 {
+		return nil, errors.New("cannot run instances: no subnets matching the spaces constraint are known to MAAS")
+	}
 
 	snArgs := selectNodeArgs{
 		AvailabilityZones: availabilityZones,
 		NodeName:          nodeName,
 		IncludeNetworks:   includeNetworks,
 		ExcludeNetworks:   excludeNetworks,
+		Subnets:           subnets,
 	}
 	node, err := environ.selectNode(snArgs)
 	if err != nil {
@@ -888,24 +899,40 @@ type selectNodeArgs struct {
 	Constraints       constraints.Value
 	IncludeNetworks   []string
 	ExcludeNetworks   []string
+
+	// Subnets, if non-empty, holds the names of the MAAS networks
+	// matching the spaces constraint. The node is acquired
+	// connected to one of them.
+	Subnets []string
 }
 
 func (environ *maasEnviron) selectNode(args selectNodeArgs) (*gomaasapi.MAASObject, error) {
 	var err error
 	var node gomaasapi.MAASObject
 
-	for i, zoneName := range args.AvailabilityZones {
+	subnets := args.Subnets
+	if len(subnets) == 0 {
+		subnets = []string{""}
+	}
+	attempts := len(args.AvailabilityZones) * len(subnets)
+	for i := 0; i < attempts; i++ {
+		zoneName := args.AvailabilityZones[i/len(subnets)]
+		subnet := subnets[i%len(subnets)]
+		includeNetworks := args.IncludeNetworks
+		if subnet != "" {
+			includeNetworks = append(includeNetworks[:len(includeNetworks):len(includeNetworks)], subnet)
+		}
 		node, err = environ.acquireNode(
 			args.NodeName,
 			zoneName,
 			args.Constraints,
-			args.IncludeNetworks,
+			includeNetworks,
 			args.ExcludeNetworks,
 		)
 
 		if err, ok := err.(gomaasapi.ServerError); ok && err.StatusCode == http.StatusConflict {
-			if i+1 < len(args.AvailabilityZones) {
-				logger.Infof("could not acquire a node in zone %q, trying another zone", zoneName)
+			if i+1 < attempts {
+				logger.Infof("could not acquire a node in zone %q on network %q, trying another", zoneName, subnet)
 				continue
 			}
 		}
@@ -1217,17 +1244,17 @@ func (environ *maasEnviron) Destroy() error {
 }
 
 // MAAS does not do firewalling so these port methods do nothing.
-func (*maasEnviron) OpenPorts([]network.PortRange) error {
+func (*maasEnviron) OpenPorts([]network.IngressRule) error {
 	logger.Debugf("unimplemented OpenPorts() called")
 	return nil
 }
 
-func (*maasEnviron) ClosePorts([]network.PortRange) error {
+func (*maasEnviron) ClosePorts([]network.IngressRule) error {
 	logger.Debugf("unimplemented ClosePorts() called")
 	return nil
 }
 
-func (*maasEnviron) Ports() ([]network.PortRange, error) {
+func (*maasEnviron) Ports() ([]network.IngressRule, error) {
 	logger.Debugf("unimplemented Ports() called")
 	return nil, nil
 }
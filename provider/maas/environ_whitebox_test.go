@@ -297,6 +297,24 @@ func (suite *environSuite) TestSelectNodeInvalidZone(c *gc.C) {
 	c.Assert(fmt.Sprintf("%s", err), gc.Equals, "cannot run instances: gomaasapi: got error back from server: 409 Conflict ()")
 }
 
+func (suite *environSuite) TestSelectNodeInSubnet(c *gc.C) {
+	env := suite.makeEnviron()
+	suite.testMAASObject.TestServer.NewNode(`{"system_id": "node0", "hostname": "host0"}`)
+	suite.getNetwork("net-a", 1, 0)
+	suite.testMAASObject.TestServer.ConnectNodeToNetwork("node0", "net-a")
+
+	snArgs := selectNodeArgs{
+		AvailabilityZones: []string{""},
+		Subnets:           []string{"net-a"},
+	}
+
+	node, err := env.selectNode(snArgs)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(node, gc.NotNil)
+	values := suite.testMAASObject.TestServer.NodeOperationRequestValues()["node0"][0]
+	c.Assert(values["networks"], jc.DeepEquals, []string{"net-a"})
+}
+
 func (suite *environSuite) TestAcquireNode(c *gc.C) {
 	env := suite.makeEnviron()
 	suite.testMAASObject.TestServer.NewNode(`{"system_id": "node0", "hostname": "host0"}`)
@@ -337,6 +337,8 @@ var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.InstanceType,
 	constraints.Tags,
+	constraints.Spaces,
+	constraints.Zones,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -346,15 +348,15 @@ func (e *manualEnviron) ConstraintsValidator() (constraints.Validator, error) {
 	return validator, nil
 }
 
-func (e *manualEnviron) OpenPorts(ports []network.PortRange) error {
+func (e *manualEnviron) OpenPorts(rules []network.IngressRule) error {
 	return nil
 }
 
-func (e *manualEnviron) ClosePorts(ports []network.PortRange) error {
+func (e *manualEnviron) ClosePorts(rules []network.IngressRule) error {
 	return nil
 }
 
-func (e *manualEnviron) Ports() ([]network.PortRange, error) {
+func (e *manualEnviron) Ports() ([]network.IngressRule, error) {
 	return nil, nil
 }
 
@@ -350,7 +350,7 @@ func ResolveNetwork(e environs.Environ, networkName string) (string, error) {
 	return e.(*environ).resolveNetwork(networkName)
 }
 
-var PortsToRuleInfo = portsToRuleInfo
+var RulesToRuleInfo = rulesToRuleInfo
 var RuleMatchesPortRange = ruleMatchesPortRange
 
 var MakeServiceURL = &makeServiceURL
@@ -362,6 +362,7 @@ var _ environs.Environ = (*environ)(nil)
 var _ simplestreams.HasRegion = (*environ)(nil)
 var _ state.Prechecker = (*environ)(nil)
 var _ state.InstanceDistributor = (*environ)(nil)
+var _ environs.IngressRestricter = (*environ)(nil)
 
 type openstackInstance struct {
 	e        *environ
@@ -495,43 +496,43 @@ func convertNovaAddresses(publicIP string, addresses map[string][]nova.IPAddress
 
 // TODO: following 30 lines nearly verbatim from environs/ec2
 
-func (inst *openstackInstance) OpenPorts(machineId string, ports []network.PortRange) error {
+func (inst *openstackInstance) OpenPorts(machineId string, rules []network.IngressRule) error {
 	if inst.e.Config().FirewallMode() != config.FwInstance {
 		return fmt.Errorf("invalid firewall mode %q for opening ports on instance",
 			inst.e.Config().FirewallMode())
 	}
 	name := inst.e.machineGroupName(machineId)
-	if err := inst.e.openPortsInGroup(name, ports); err != nil {
+	if err := inst.e.openPortsInGroup(name, rules); err != nil {
 		return err
 	}
-	logger.Infof("opened ports in security group %s: %v", name, ports)
+	logger.Infof("opened ports in security group %s: %v", name, rules)
 	return nil
 }
 
-func (inst *openstackInstance) ClosePorts(machineId string, ports []network.PortRange) error {
+func (inst *openstackInstance) ClosePorts(machineId string, rules []network.IngressRule) error {
 	if inst.e.Config().FirewallMode() != config.FwInstance {
 		return fmt.Errorf("invalid firewall mode %q for closing ports on instance",
 			inst.e.Config().FirewallMode())
 	}
 	name := inst.e.machineGroupName(machineId)
-	if err := inst.e.closePortsInGroup(name, ports); err != nil {
+	if err := inst.e.closePortsInGroup(name, rules); err != nil {
 		return err
 	}
-	logger.Infof("closed ports in security group %s: %v", name, ports)
+	logger.Infof("closed ports in security group %s: %v", name, rules)
 	return nil
 }
 
-func (inst *openstackInstance) Ports(machineId string) ([]network.PortRange, error) {
+func (inst *openstackInstance) Ports(machineId string) ([]network.IngressRule, error) {
 	if inst.e.Config().FirewallMode() != config.FwInstance {
 		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from instance",
 			inst.e.Config().FirewallMode())
 	}
 	name := inst.e.machineGroupName(machineId)
-	portRanges, err := inst.e.portsInGroup(name)
+	rules, err := inst.e.portsInGroup(name)
 	if err != nil {
 		return nil, err
 	}
-	return portRanges, nil
+	return rules, nil
 }
 
 func (e *environ) ecfg() *environConfig {
@@ -583,6 +584,7 @@ func (e *environ) SupportAddressAllocation(netId network.Id) (bool, error) {
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.CpuPower,
+	constraints.Spaces,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -1338,30 +1340,45 @@ func (e *environ) machinesFilter() *nova.Filter {
 	return filter
 }
 
-// portsToRuleInfo maps port ranges to nova rules
-func portsToRuleInfo(groupId string, ports []network.PortRange) []nova.RuleInfo {
-	rules := make([]nova.RuleInfo, len(ports))
-	for i, portRange := range ports {
-		rules[i] = nova.RuleInfo{
-			ParentGroupId: groupId,
-			FromPort:      portRange.FromPort,
-			ToPort:        portRange.ToPort,
-			IPProtocol:    portRange.Protocol,
-			Cidr:          "0.0.0.0/0",
+// anyCIDR is the source CIDR of nova rules allowing access from
+// any address.
+const anyCIDR = network.AnyCIDR
+
+// ruleSourceCIDRs returns the CIDRs nova rules for the given ingress rule
+// should allow access from.
+func ruleSourceCIDRs(rule network.IngressRule) []string {
+	if !rule.Restricted() {
+		return []string{anyCIDR}
+	}
+	return rule.SourceCIDRs
+}
+
+// rulesToRuleInfo maps ingress rules to nova rules. Nova rules have
+// a single CIDR, so one is created for each of a rule's source CIDRs.
+func rulesToRuleInfo(groupId string, rules []network.IngressRule) []nova.RuleInfo {
+	var ruleInfos []nova.RuleInfo
+	for _, rule := range rules {
+		for _, cidr := range ruleSourceCIDRs(rule) {
+			ruleInfos = append(ruleInfos, nova.RuleInfo{
+				ParentGroupId: groupId,
+				FromPort:      rule.FromPort,
+				ToPort:        rule.ToPort,
+				IPProtocol:    rule.Protocol,
+				Cidr:          cidr,
+			})
 		}
 	}
-	return rules
+	return ruleInfos
 }
 
-func (e *environ) openPortsInGroup(name string, portRanges []network.PortRange) error {
+func (e *environ) openPortsInGroup(name string, rules []network.IngressRule) error {
 	novaclient := e.nova()
 	group, err := novaclient.SecurityGroupByName(name)
 	if err != nil {
 		return err
 	}
-	rules := portsToRuleInfo(group.Id, portRanges)
-	for _, rule := range rules {
-		_, err := novaclient.CreateSecurityGroupRule(rule)
+	for _, ruleInfo := range rulesToRuleInfo(group.Id, rules) {
+		_, err := novaclient.CreateSecurityGroupRule(ruleInfo)
 		if err != nil {
 			// TODO: if err is not rule already exists, raise?
 			logger.Debugf("error creating security group rule: %v", err.Error())
@@ -1380,8 +1397,17 @@ func ruleMatchesPortRange(rule nova.SecurityGroupRule, portRange network.PortRan
 		*rule.ToPort == portRange.ToPort
 }
 
-func (e *environ) closePortsInGroup(name string, portRanges []network.PortRange) error {
-	if len(portRanges) == 0 {
+// ruleCIDR returns the CIDR the supplied nova security group rule
+// allows access from, or anyCIDR if it has none.
+func ruleCIDR(rule nova.SecurityGroupRule) string {
+	if cidr := rule.IPRange["cidr"]; cidr != "" {
+		return cidr
+	}
+	return anyCIDR
+}
+
+func (e *environ) closePortsInGroup(name string, rules []network.IngressRule) error {
+	if len(rules) == 0 {
 		return nil
 	}
 	novaclient := e.nova()
@@ -1390,64 +1416,68 @@ func (e *environ) closePortsInGroup(name string, portRanges []network.PortRange)
 		return err
 	}
 	// TODO: Hey look ma, it's quadratic
-	for _, portRange := range portRanges {
-		for _, p := range (*group).Rules {
-			if !ruleMatchesPortRange(p, portRange) {
-				continue
-			}
-			err := novaclient.DeleteSecurityGroupRule(p.Id)
-			if err != nil {
-				return err
+	for _, rule := range rules {
+		for _, cidr := range ruleSourceCIDRs(rule) {
+			for _, p := range (*group).Rules {
+				if !ruleMatchesPortRange(p, rule.PortRange) || ruleCIDR(p) != cidr {
+					continue
+				}
+				err := novaclient.DeleteSecurityGroupRule(p.Id)
+				if err != nil {
+					return err
+				}
+				break
 			}
-			break
 		}
 	}
 	return nil
 }
 
-func (e *environ) portsInGroup(name string) (portRanges []network.PortRange, err error) {
+func (e *environ) portsInGroup(name string) (rules []network.IngressRule, err error) {
 	group, err := e.nova().SecurityGroupByName(name)
 	if err != nil {
 		return nil, err
 	}
+	// Nova rules have a single CIDR, so each one is reported as a
+	// separate ingress rule.
 	for _, p := range (*group).Rules {
-		portRanges = append(portRanges, network.PortRange{
+		portRange := network.PortRange{
 			Protocol: *p.IPProtocol,
 			FromPort: *p.FromPort,
 			ToPort:   *p.ToPort,
-		})
+		}
+		rules = append(rules, network.NewIngressRule(portRange, ruleCIDR(p)))
 	}
-	network.SortPortRanges(portRanges)
-	return portRanges, nil
+	return network.SplitIngressRules(rules), nil
 }
 
 // TODO: following 30 lines nearly verbatim from environs/ec2
 
-func (e *environ) OpenPorts(ports []network.PortRange) error {
+func (e *environ) OpenPorts(rules []network.IngressRule) error {
 	if e.Config().FirewallMode() != config.FwGlobal {
 		return fmt.Errorf("invalid firewall mode %q for opening ports on environment",
 			e.Config().FirewallMode())
 	}
-	if err := e.openPortsInGroup(e.globalGroupName(), ports); err != nil {
+	if err := e.openPortsInGroup(e.globalGroupName(), rules); err != nil {
 		return err
 	}
-	logger.Infof("opened ports in global group: %v", ports)
+	logger.Infof("opened ports in global group: %v", rules)
 	return nil
 }
 
-func (e *environ) ClosePorts(ports []network.PortRange) error {
+func (e *environ) ClosePorts(rules []network.IngressRule) error {
 	if e.Config().FirewallMode() != config.FwGlobal {
 		return fmt.Errorf("invalid firewall mode %q for closing ports on environment",
 			e.Config().FirewallMode())
 	}
-	if err := e.closePortsInGroup(e.globalGroupName(), ports); err != nil {
+	if err := e.closePortsInGroup(e.globalGroupName(), rules); err != nil {
 		return err
 	}
-	logger.Infof("closed ports in global group: %v", ports)
+	logger.Infof("closed ports in global group: %v", rules)
 	return nil
 }
 
-func (e *environ) Ports() ([]network.PortRange, error) {
+func (e *environ) Ports() ([]network.IngressRule, error) {
 	if e.Config().FirewallMode() != config.FwGlobal {
 		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from environment",
 			e.Config().FirewallMode())
@@ -1455,6 +1485,11 @@ func (e *environ) Ports() ([]network.PortRange, error) {
 	return e.portsInGroup(e.globalGroupName())
 }
 
+// SupportsIngressRestrictions is specified on the IngressRestricter interface.
+func (*environ) SupportsIngressRestrictions() bool {
+	return true
+}
+
 func (e *environ) Provider() environs.EnvironProvider {
 	return &providerInstance
 }
@@ -226,12 +226,13 @@ func (t *localTests) TestGetServerAddresses(c *gc.C) {
 	}
 }
 
-func (*localTests) TestPortsToRuleInfo(c *gc.C) {
+func (*localTests) TestRulesToRuleInfo(c *gc.C) {
 	groupId := "groupid"
 	testCases := []struct {
-		about    string
-		ports    []network.PortRange
-		expected []nova.RuleInfo
+		about       string
+		ports       []network.PortRange
+		sourceCIDRs []string
+		expected    []nova.RuleInfo
 	}{{
 		about: "single port",
 		ports: []network.PortRange{{
@@ -284,11 +285,32 @@ func (*localTests) TestPortsToRuleInfo(c *gc.C) {
 			Cidr:          "0.0.0.0/0",
 			ParentGroupId: groupId,
 		}},
+	}, {
+		about: "source CIDRs",
+		ports: []network.PortRange{{
+			FromPort: 80,
+			ToPort:   80,
+			Protocol: "tcp",
+		}},
+		sourceCIDRs: []string{"203.0.113.4/32", "10.0.0.0/8"},
+		expected: []nova.RuleInfo{{
+			IPProtocol:    "tcp",
+			FromPort:      80,
+			ToPort:        80,
+			Cidr:          "10.0.0.0/8",
+			ParentGroupId: groupId,
+		}, {
+			IPProtocol:    "tcp",
+			FromPort:      80,
+			ToPort:        80,
+			Cidr:          "203.0.113.4/32",
+			ParentGroupId: groupId,
+		}},
 	}}
 
 	for i, t := range testCases {
 		c.Logf("test %d: %s", i, t.about)
-		rules := openstack.PortsToRuleInfo(groupId, t.ports)
+		rules := openstack.RulesToRuleInfo(groupId, network.IngressRulesForPortRanges(t.ports, t.sourceCIDRs...))
 		c.Check(len(rules), gc.Equals, len(t.expected))
 		c.Check(rules, gc.DeepEquals, t.expected)
 	}
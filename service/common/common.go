@@ -1,5 +1,16 @@
 package common
 
+import (
+	"fmt"
+	"path"
+
+	"github.com/juju/utils"
+)
+
+const (
+	maxAgentFiles = 20000
+)
+
 // Conf is responsible for defining services. Its fields
 // represent elements of a service configuration.
 type Conf struct {
@@ -23,3 +34,25 @@ type Conf struct {
 	// ExtraScript allows to insert script before command execution
 	ExtraScript string
 }
+
+// MachineAgentConf returns the service configuration for a machine
+// agent based on the tag and machineId passed in. The configuration
+// is independent of the init system that will run the agent.
+func MachineAgentConf(toolsDir, dataDir, logDir, tag, machineId string, env map[string]string) Conf {
+	logFile := path.Join(logDir, tag+".log")
+	// The machine agent always starts with debug turned on.  The logger worker
+	// will update this to the system logging environment as soon as it starts.
+	return Conf{
+		Desc: fmt.Sprintf("juju %s agent", tag),
+		Limit: map[string]string{
+			"nofile": fmt.Sprintf("%d %d", maxAgentFiles, maxAgentFiles),
+		},
+		Cmd: path.Join(toolsDir, "jujud") +
+			" machine" +
+			" --data-dir " + utils.ShQuote(dataDir) +
+			" --machine-id " + machineId +
+			" --debug",
+		Out: logFile,
+		Env: env,
+	}
+}
@@ -3,18 +3,22 @@ package service
 import (
 	"fmt"
 	"io/ioutil"
+	"os"
 	"regexp"
 	"strings"
 
+	"github.com/juju/errors"
 	"github.com/juju/utils/exec"
 
 	"github.com/juju/juju/service/common"
+	"github.com/juju/juju/service/systemd"
 	"github.com/juju/juju/service/upstart"
 	"github.com/juju/juju/service/windows"
 	"github.com/juju/juju/version"
 )
 
 var _ Service = (*upstart.Service)(nil)
+var _ Service = (*systemd.Service)(nil)
 var _ Service = (*windows.Service)(nil)
 
 // Service represents a service running on the current system
@@ -43,13 +47,69 @@ type Service interface {
 	UpdateConfig(conf common.Conf)
 }
 
+// These are the names of the init systems recognised by juju.
+const (
+	InitSystemUpstart = "upstart"
+	InitSystemSystemd = "systemd"
+	InitSystemWindows = "windows"
+)
+
+// systemdRunDir is the directory that only exists when the running
+// system has been booted with systemd (see sd_booted(3)).
+var systemdRunDir = "/run/systemd/system"
+
+// systemdSeriesVersion is the first Ubuntu release that uses
+// systemd as its default init system (vivid).
+const systemdSeriesVersion = "15.04"
+
+// InitSystem returns the name of the init system used by
+// the current host.
+var InitSystem = func() string {
+	if version.Current.OS == version.Windows {
+		return InitSystemWindows
+	}
+	if fi, err := os.Stat(systemdRunDir); err == nil && fi.IsDir() {
+		return InitSystemSystemd
+	}
+	return InitSystemUpstart
+}
+
+// SeriesInitSystem returns the name of the init system used by
+// default on machines running the given series.
+func SeriesInitSystem(series string) string {
+	osType, err := version.GetOSFromSeries(series)
+	if err != nil {
+		return InitSystemUpstart
+	}
+	switch osType {
+	case version.Windows:
+		return InitSystemWindows
+	case version.Ubuntu:
+		// All Ubuntu series that predate systemd are known without
+		// consulting distro-info, so an unknown series is a newer one.
+		vers, err := version.SeriesVersion(series)
+		if err != nil || vers >= systemdSeriesVersion {
+			return InitSystemSystemd
+		}
+	}
+	return InitSystemUpstart
+}
+
 // NewService returns an interface to a service apropriate
 // for the current system
 func NewService(name string, conf common.Conf) Service {
-	switch version.Current.OS {
-	case version.Windows:
+	return NewServiceForInitSystem(InitSystem(), name, conf)
+}
+
+// NewServiceForInitSystem returns an interface to a service
+// managed by the named init system.
+func NewServiceForInitSystem(initSystem, name string, conf common.Conf) Service {
+	switch initSystem {
+	case InitSystemWindows:
 		svc := windows.NewService(name, conf)
 		return svc
+	case InitSystemSystemd:
+		return systemd.NewService(name, conf)
 	default:
 		return upstart.NewService(name, conf)
 	}
@@ -85,14 +145,62 @@ func upstartListServices(initDir string) ([]string, error) {
 	return services, nil
 }
 
-// ListServices lists all installed services on the running system
+var systemdServicesRe = regexp.MustCompile("^([a-zA-Z0-9-_:]+)\\.service$")
+
+func systemdListServices(initDir string) ([]string, error) {
+	var services []string
+	fis, err := ioutil.ReadDir(initDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, fi := range fis {
+		if groups := systemdServicesRe.FindStringSubmatch(fi.Name()); len(groups) > 0 {
+			services = append(services, groups[1])
+		}
+	}
+	return services, nil
+}
+
+// StartCommand returns a shell command that starts the named service
+// under the given init system, for scripts run on other machines.
+func StartCommand(initSystem, name string) (string, error) {
+	switch initSystem {
+	case InitSystemSystemd:
+		return "systemctl start " + name + ".service", nil
+	case InitSystemUpstart:
+		return "initctl start " + name, nil
+	}
+	return "", errors.NotSupportedf("init system %q", initSystem)
+}
+
+// StopCommand returns a shell command that stops the named service
+// under the given init system, for scripts run on other machines.
+func StopCommand(initSystem, name string) (string, error) {
+	switch initSystem {
+	case InitSystemSystemd:
+		return "systemctl stop " + name + ".service", nil
+	case InitSystemUpstart:
+		return "initctl stop " + name, nil
+	}
+	return "", errors.NotSupportedf("init system %q", initSystem)
+}
+
+// ListServices lists all installed services on the running system.
+// If initDir is empty, the default directory of the running system's
+// init system is used.
 func ListServices(initDir string) ([]string, error) {
-	switch version.Current.OS {
-	case version.Ubuntu:
-		return upstartListServices(initDir)
-	case version.Windows:
+	switch InitSystem() {
+	case InitSystemWindows:
 		return windowsListServices()
+	case InitSystemSystemd:
+		if initDir == "" {
+			initDir = systemd.InitDir
+		}
+		return systemdListServices(initDir)
 	default:
+		if initDir == "" {
+			initDir = upstart.InitDir
+		}
 		return upstartListServices(initDir)
 	}
 }
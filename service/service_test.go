@@ -0,0 +1,131 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/service"
+	"github.com/juju/juju/service/common"
+	"github.com/juju/juju/service/systemd"
+	"github.com/juju/juju/service/upstart"
+	coretesting "github.com/juju/juju/testing"
+)
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type serviceSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&serviceSuite{})
+
+func (s *serviceSuite) patchInitSystem(initSystem string) {
+	s.PatchValue(&service.InitSystem, func() string {
+		return initSystem
+	})
+}
+
+func (s *serviceSuite) TestNewServiceUpstart(c *gc.C) {
+	s.patchInitSystem(service.InitSystemUpstart)
+	svc := service.NewService("foo", common.Conf{})
+	c.Assert(svc, gc.FitsTypeOf, &upstart.Service{})
+}
+
+func (s *serviceSuite) TestNewServiceSystemd(c *gc.C) {
+	s.patchInitSystem(service.InitSystemSystemd)
+	svc := service.NewService("foo", common.Conf{})
+	c.Assert(svc, gc.FitsTypeOf, &systemd.Service{})
+	c.Assert(svc.(*systemd.Service).Conf.InitDir, gc.Equals, systemd.InitDir)
+}
+
+var seriesInitSystemTests = []struct {
+	series     string
+	initSystem string
+}{
+	{"precise", service.InitSystemUpstart},
+	{"trusty", service.InitSystemUpstart},
+	{"utopic", service.InitSystemUpstart},
+	{"vivid", service.InitSystemSystemd},
+	{"win2012r2", service.InitSystemWindows},
+	{"unknown", service.InitSystemUpstart},
+}
+
+func (s *serviceSuite) TestSeriesInitSystem(c *gc.C) {
+	for i, test := range seriesInitSystemTests {
+		c.Logf("test %d: %s", i, test.series)
+		c.Check(service.SeriesInitSystem(test.series), gc.Equals, test.initSystem)
+	}
+}
+
+func (s *serviceSuite) TestStartStopCommands(c *gc.C) {
+	for i, test := range []struct {
+		initSystem string
+		start      string
+		stop       string
+	}{{
+		initSystem: service.InitSystemUpstart,
+		start:      "initctl start jujud-machine-1",
+		stop:       "initctl stop jujud-machine-1",
+	}, {
+		initSystem: service.InitSystemSystemd,
+		start:      "systemctl start jujud-machine-1.service",
+		stop:       "systemctl stop jujud-machine-1.service",
+	}} {
+		c.Logf("test %d: %s", i, test.initSystem)
+		start, err := service.StartCommand(test.initSystem, "jujud-machine-1")
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(start, gc.Equals, test.start)
+		stop, err := service.StopCommand(test.initSystem, "jujud-machine-1")
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(stop, gc.Equals, test.stop)
+	}
+
+	_, err := service.StartCommand(service.InitSystemWindows, "jujud-machine-1")
+	c.Assert(err, gc.ErrorMatches, `init system "windows" not supported`)
+	_, err = service.StopCommand(service.InitSystemWindows, "jujud-machine-1")
+	c.Assert(err, gc.ErrorMatches, `init system "windows" not supported`)
+}
+
+func (s *serviceSuite) writeFiles(c *gc.C, dir string, names ...string) {
+	for _, name := range names {
+		err := ioutil.WriteFile(filepath.Join(dir, name), nil, 0644)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *serviceSuite) TestListServicesUpstart(c *gc.C) {
+	s.patchInitSystem(service.InitSystemUpstart)
+	dir := c.MkDir()
+	s.writeFiles(c, dir, "jujud-unit-foo-0.conf", "jujud-unit-bar-1.service", "other")
+	services, err := service.ListServices(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(services, gc.DeepEquals, []string{"jujud-unit-foo-0"})
+}
+
+func (s *serviceSuite) TestListServicesSystemd(c *gc.C) {
+	s.patchInitSystem(service.InitSystemSystemd)
+	dir := c.MkDir()
+	s.writeFiles(c, dir, "jujud-unit-foo-0.conf", "jujud-unit-bar-1.service", "jujud-machine-0.service")
+	services, err := service.ListServices(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	sort.Strings(services)
+	c.Assert(services, gc.DeepEquals, []string{"jujud-machine-0", "jujud-unit-bar-1"})
+}
+
+func (s *serviceSuite) TestListServicesDefaultDir(c *gc.C) {
+	s.patchInitSystem(service.InitSystemSystemd)
+	dir := c.MkDir()
+	s.PatchValue(&systemd.InitDir, dir)
+	s.writeFiles(c, dir, "jujud-unit-foo-0.service")
+	services, err := service.ListServices("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(services, gc.DeepEquals, []string{"jujud-unit-foo-0"})
+}
@@ -0,0 +1,336 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package systemd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/service/common"
+)
+
+// InitDir holds the default directory in which unit files are written.
+var InitDir = "/etc/systemd/system"
+
+var InstallStartRetryAttempts = utils.AttemptStrategy{
+	Total: 1 * time.Second,
+	Delay: 250 * time.Millisecond,
+}
+
+// Service provides visibility into and control over a systemd service.
+type Service struct {
+	Name string
+	Conf common.Conf
+}
+
+// NewService returns a new systemd service with the given name
+// and configuration.
+func NewService(name string, conf common.Conf) *Service {
+	if conf.InitDir == "" {
+		conf.InitDir = InitDir
+	}
+	return &Service{Name: name, Conf: conf}
+}
+
+// MachineAgentService returns the systemd config for a machine agent
+// based on the tag and machineId passed in.
+func MachineAgentService(name, toolsDir, dataDir, logDir, tag, machineId string, env map[string]string) *Service {
+	conf := common.MachineAgentConf(toolsDir, dataDir, logDir, tag, machineId, env)
+	return NewService(name, conf)
+}
+
+// unitName returns the name of the service's systemd unit.
+func (s *Service) unitName() string {
+	return s.Name + ".service"
+}
+
+// confPath returns the path to the service's unit file.
+func (s *Service) confPath() string {
+	return path.Join(s.Conf.InitDir, s.unitName())
+}
+
+func (s *Service) UpdateConfig(conf common.Conf) {
+	s.Conf = conf
+}
+
+// validate returns an error if the service is not adequately defined.
+func (s *Service) validate() error {
+	if s.Name == "" {
+		return errors.New("missing Name")
+	}
+	if s.Conf.InitDir == "" {
+		return errors.New("missing InitDir")
+	}
+	if s.Conf.Desc == "" {
+		return errors.New("missing Desc")
+	}
+	if s.Conf.Cmd == "" {
+		return errors.New("missing Cmd")
+	}
+	return nil
+}
+
+// render returns the systemd unit file for the service as a slice of bytes.
+func (s *Service) render() ([]byte, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	limits := make(map[string]string)
+	for name, value := range s.Conf.Limit {
+		// systemd applies a single value to both the soft and
+		// the hard limit, so use the hard limit when upstart
+		// style "soft hard" pairs are given.
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			continue
+		}
+		limits["Limit"+strings.ToUpper(name)] = fields[len(fields)-1]
+	}
+	var buf bytes.Buffer
+	err := confT.Execute(&buf, map[string]interface{}{
+		"Desc":      s.Conf.Desc,
+		"Env":       s.Conf.Env,
+		"Limit":     limits,
+		"ExecStart": execStart(s.Conf),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// execStart returns the value for the ExecStart directive of the
+// service. If the service needs shell features (output redirection or
+// an extra script) the command is wrapped in a bash invocation.
+func execStart(conf common.Conf) string {
+	if conf.Out == "" && conf.ExtraScript == "" {
+		return conf.Cmd
+	}
+	var script []string
+	if conf.ExtraScript != "" {
+		script = append(script, conf.ExtraScript)
+	}
+	cmd := "exec " + conf.Cmd
+	if conf.Out != "" {
+		out := utils.ShQuote(conf.Out)
+		// Ensure log files are properly protected
+		script = append(script,
+			"touch "+out,
+			"chown syslog:syslog "+out,
+			"chmod 0600 "+out,
+		)
+		cmd += " >> " + out + " 2>&1"
+	}
+	script = append(script, cmd)
+	return `/bin/bash -c "` + quote(strings.Join(script, "\n"), true) + `"`
+}
+
+// quote escapes s so that it can be placed inside a double quoted
+// value in a unit file. If execLine is true, "$" is escaped as well
+// so that systemd does not try to expand environment variables.
+func quote(s string, execLine bool) string {
+	var buf bytes.Buffer
+	for _, r := range s {
+		switch r {
+		case '\\', '"':
+			buf.WriteRune('\\')
+			buf.WriteRune(r)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '%':
+			buf.WriteString("%%")
+		case '$':
+			if execLine {
+				buf.WriteString("$$")
+			} else {
+				buf.WriteRune(r)
+			}
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
+
+// Installed returns whether the service's unit file exists in the
+// init directory.
+func (s *Service) Installed() bool {
+	_, err := os.Stat(s.confPath())
+	return err == nil
+}
+
+// Exists returns whether the service's unit file exists in the
+// init directory with the same content that this Service would have
+// if installed.
+func (s *Service) Exists() bool {
+	// In any error case, we just say it doesn't exist with this configuration.
+	// Subsequent calls into the Service will give the caller more useful errors.
+	_, same, _, err := s.existsAndSame()
+	if err != nil {
+		return false
+	}
+	return same
+}
+
+func (s *Service) existsAndSame() (exists, same bool, conf []byte, err error) {
+	expected, err := s.render()
+	if err != nil {
+		return false, false, nil, errors.Trace(err)
+	}
+	current, err := ioutil.ReadFile(s.confPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			// no existing config
+			return false, false, expected, nil
+		}
+		return false, false, nil, errors.Trace(err)
+	}
+	return true, bytes.Equal(current, expected), expected, nil
+}
+
+// Running returns true if the Service appears to be running.
+func (s *Service) Running() bool {
+	out, err := exec.Command("systemctl", "is-active", s.unitName()).CombinedOutput()
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(string(out)) == "active"
+}
+
+// Start starts the service.
+func (s *Service) Start() error {
+	if s.Running() {
+		return nil
+	}
+	err := runCommand("systemctl", "start", s.unitName())
+	if err != nil {
+		// Double check to see if we were started before our command ran.
+		if s.Running() {
+			return nil
+		}
+	}
+	return err
+}
+
+func runCommand(args ...string) error {
+	out, err := exec.Command(args[0], args[1:]...).CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	out = bytes.TrimSpace(out)
+	if len(out) > 0 {
+		return fmt.Errorf("exec %q: %v (%s)", args, err, out)
+	}
+	return fmt.Errorf("exec %q: %v", args, err)
+}
+
+// Stop stops the service.
+func (s *Service) Stop() error {
+	if !s.Running() {
+		return nil
+	}
+	return runCommand("systemctl", "stop", s.unitName())
+}
+
+// StopAndRemove stops the service and then deletes the service's
+// unit file from the init directory.
+func (s *Service) StopAndRemove() error {
+	if !s.Installed() {
+		return nil
+	}
+	if err := s.Stop(); err != nil {
+		return err
+	}
+	return s.Remove()
+}
+
+// Remove disables the service and deletes its unit file from the
+// init directory.
+func (s *Service) Remove() error {
+	if !s.Installed() {
+		return nil
+	}
+	if err := runCommand("systemctl", "disable", s.unitName()); err != nil {
+		return errors.Trace(err)
+	}
+	if err := os.Remove(s.confPath()); err != nil {
+		return errors.Trace(err)
+	}
+	return runCommand("systemctl", "daemon-reload")
+}
+
+// Install installs, enables and starts the service.
+func (s *Service) Install() error {
+	exists, same, conf, err := s.existsAndSame()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if same {
+		return nil
+	}
+	if exists {
+		if err := s.StopAndRemove(); err != nil {
+			return errors.Annotate(err, "systemd: could not remove installed service")
+		}
+	}
+	if err := ioutil.WriteFile(s.confPath(), conf, 0644); err != nil {
+		return errors.Trace(err)
+	}
+	if err := runCommand("systemctl", "daemon-reload"); err != nil {
+		return errors.Trace(err)
+	}
+	if err := runCommand("systemctl", "enable", s.unitName()); err != nil {
+		return errors.Trace(err)
+	}
+	for attempt := InstallStartRetryAttempts.Start(); attempt.Next(); {
+		if err = s.Start(); err == nil {
+			break
+		}
+	}
+	return err
+}
+
+// InstallCommands returns shell commands to install and start the service.
+func (s *Service) InstallCommands() ([]string, error) {
+	conf, err := s.render()
+	if err != nil {
+		return nil, err
+	}
+	return []string{
+		fmt.Sprintf("cat > %s << 'EOF'\n%sEOF\n", s.confPath(), conf),
+		"systemctl daemon-reload",
+		"systemctl enable " + s.unitName(),
+		"systemctl start " + s.unitName(),
+	}, nil
+}
+
+var confT = template.Must(template.New("").Funcs(template.FuncMap{
+	"quote": func(s string) string { return quote(s, false) },
+}).Parse(`
+[Unit]
+Description={{.Desc}}
+After=syslog.target
+After=network.target
+After=systemd-user-sessions.service
+
+[Service]
+{{range $k, $v := .Env}}Environment="{{$k}}={{$v|quote}}"
+{{end}}{{range $k, $v := .Limit}}{{$k}}={{$v}}
+{{end}}ExecStart={{.ExecStart}}
+Restart=on-failure
+TimeoutSec=300
+
+[Install]
+WantedBy=multi-user.target
+`[1:]))
@@ -0,0 +1,303 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package systemd_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/service/common"
+	"github.com/juju/juju/service/systemd"
+	coretesting "github.com/juju/juju/testing"
+)
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type SystemdSuite struct {
+	coretesting.BaseSuite
+	testPath string
+	service  *systemd.Service
+	initDir  string
+}
+
+var _ = gc.Suite(&SystemdSuite{})
+
+func (s *SystemdSuite) SetUpTest(c *gc.C) {
+	s.testPath = c.MkDir()
+	s.initDir = c.MkDir()
+	s.PatchEnvPathPrepend(s.testPath)
+	s.PatchValue(&systemd.InstallStartRetryAttempts, utils.AttemptStrategy{})
+	s.PatchValue(&systemd.InitDir, s.initDir)
+	s.service = systemd.NewService(
+		"some-service",
+		common.Conf{
+			Desc: "some service",
+			Cmd:  "some command",
+		},
+	)
+	s.fakeSystemctl(c, 0, 0)
+}
+
+// fakeSystemctl installs a systemctl tool which records the
+// activity of some-service in a file next to the tool. Starting
+// and stopping the service exit with the given codes, leaving the
+// service state unchanged if the code is non-zero.
+func (s *SystemdSuite) fakeSystemctl(c *gc.C, startExit, stopExit int) {
+	statePath := filepath.Join(s.testPath, "state")
+	logPath := filepath.Join(s.testPath, "log")
+	script := fmt.Sprintf(`#!/bin/bash --norc
+echo "$@" >> %[1]s
+case "$1" in
+is-active)
+  state=$(cat %[2]s 2>/dev/null || echo inactive)
+  echo $state
+  [ "$state" = "active" ]
+  ;;
+start)
+  [ %[3]d -eq 0 ] && echo active > %[2]s
+  exit %[3]d
+  ;;
+stop)
+  [ %[4]d -eq 0 ] && echo inactive > %[2]s
+  exit %[4]d
+  ;;
+esac
+`, logPath, statePath, startExit, stopExit)
+	err := ioutil.WriteFile(filepath.Join(s.testPath, "systemctl"), []byte(script), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *SystemdSuite) setActive(c *gc.C, active bool) {
+	state := "inactive"
+	if active {
+		state = "active"
+	}
+	err := ioutil.WriteFile(filepath.Join(s.testPath, "state"), []byte(state+"\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *SystemdSuite) calls(c *gc.C) []string {
+	data, err := ioutil.ReadFile(filepath.Join(s.testPath, "log"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	c.Assert(err, jc.ErrorIsNil)
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func (s *SystemdSuite) confPath() string {
+	return filepath.Join(s.initDir, "some-service.service")
+}
+
+func (s *SystemdSuite) TestInitDir(c *gc.C) {
+	svc := systemd.NewService("blah", common.Conf{})
+	c.Assert(svc.Conf.InitDir, gc.Equals, s.initDir)
+}
+
+func (s *SystemdSuite) TestInstalled(c *gc.C) {
+	c.Assert(s.service.Installed(), jc.IsFalse)
+	err := s.service.Install()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.Installed(), jc.IsTrue)
+}
+
+func (s *SystemdSuite) TestExists(c *gc.C) {
+	c.Assert(s.service.Exists(), jc.IsFalse)
+	err := s.service.Install()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.Exists(), jc.IsTrue)
+	s.service.Conf.Cmd = "something else"
+	c.Assert(s.service.Exists(), jc.IsFalse)
+}
+
+func (s *SystemdSuite) TestRunning(c *gc.C) {
+	c.Assert(s.service.Running(), jc.IsFalse)
+	s.setActive(c, true)
+	c.Assert(s.service.Running(), jc.IsTrue)
+	s.setActive(c, false)
+	c.Assert(s.service.Running(), jc.IsFalse)
+}
+
+func (s *SystemdSuite) TestStart(c *gc.C) {
+	s.fakeSystemctl(c, 99, 0)
+	c.Assert(s.service.Start(), gc.ErrorMatches, ".*exit status 99.*")
+	s.setActive(c, true)
+	c.Assert(s.service.Start(), gc.IsNil)
+	s.setActive(c, false)
+	s.fakeSystemctl(c, 0, 0)
+	c.Assert(s.service.Start(), gc.IsNil)
+	c.Assert(s.service.Running(), jc.IsTrue)
+}
+
+func (s *SystemdSuite) TestStop(c *gc.C) {
+	s.fakeSystemctl(c, 0, 99)
+	c.Assert(s.service.Stop(), gc.IsNil)
+	s.setActive(c, true)
+	c.Assert(s.service.Stop(), gc.ErrorMatches, ".*exit status 99.*")
+	s.fakeSystemctl(c, 0, 0)
+	c.Assert(s.service.Stop(), gc.IsNil)
+	c.Assert(s.service.Running(), jc.IsFalse)
+}
+
+func (s *SystemdSuite) TestInstallCallsSystemctl(c *gc.C) {
+	err := s.service.Install()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.calls(c), gc.DeepEquals, []string{
+		"daemon-reload",
+		"enable some-service.service",
+		"is-active some-service.service",
+		"start some-service.service",
+	})
+	c.Assert(s.service.Running(), jc.IsTrue)
+}
+
+func (s *SystemdSuite) TestRemoveMissing(c *gc.C) {
+	c.Assert(s.service.StopAndRemove(), gc.IsNil)
+	c.Assert(s.calls(c), gc.HasLen, 0)
+}
+
+func (s *SystemdSuite) TestStopAndRemove(c *gc.C) {
+	err := s.service.Install()
+	c.Assert(err, jc.ErrorIsNil)
+	s.fakeSystemctl(c, 0, 99)
+
+	// StopAndRemove will fail, as it calls stop.
+	c.Assert(s.service.StopAndRemove(), gc.ErrorMatches, ".*exit status 99.*")
+	_, err = os.Stat(s.confPath())
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Plain old Remove will succeed.
+	c.Assert(s.service.Remove(), gc.IsNil)
+	_, err = os.Stat(s.confPath())
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+	calls := s.calls(c)
+	c.Assert(calls[len(calls)-2:], gc.DeepEquals, []string{
+		"disable some-service.service",
+		"daemon-reload",
+	})
+}
+
+func (s *SystemdSuite) TestInstallErrors(c *gc.C) {
+	check := func(msg string) {
+		c.Assert(s.service.Install(), gc.ErrorMatches, msg)
+		_, err := s.service.InstallCommands()
+		c.Assert(err, gc.ErrorMatches, msg)
+	}
+	s.service.Conf = common.Conf{}
+	s.service.Name = ""
+	check("missing Name")
+	s.service.Name = "some-service"
+	check("missing InitDir")
+	s.service.Conf.InitDir = c.MkDir()
+	check("missing Desc")
+	s.service.Conf.Desc = "this is a systemd service"
+	check("missing Cmd")
+}
+
+const expectStart = `[Unit]
+Description=this is a systemd service
+After=syslog.target
+After=network.target
+After=systemd-user-sessions.service
+
+[Service]
+`
+
+const expectEnd = `Restart=on-failure
+TimeoutSec=300
+
+[Install]
+WantedBy=multi-user.target
+`
+
+func (s *SystemdSuite) dummyConf(c *gc.C) common.Conf {
+	return common.Conf{
+		Desc:    "this is a systemd service",
+		Cmd:     "/bin/do something",
+		InitDir: s.initDir,
+	}
+}
+
+func (s *SystemdSuite) assertInstall(c *gc.C, conf common.Conf, expectService string) {
+	expectContent := expectStart + expectService + expectEnd
+
+	s.service.Conf = conf
+	cmds, err := s.service.InstallCommands()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmds, gc.DeepEquals, []string{
+		"cat > " + s.confPath() + " << 'EOF'\n" + expectContent + "EOF\n",
+		"systemctl daemon-reload",
+		"systemctl enable some-service.service",
+		"systemctl start some-service.service",
+	})
+
+	s.fakeSystemctl(c, 99, 0)
+	err = s.service.Install()
+	c.Assert(err, gc.ErrorMatches, ".*exit status 99.*")
+	s.fakeSystemctl(c, 0, 0)
+	err = s.service.Install()
+	c.Assert(err, jc.ErrorIsNil)
+	content, err := ioutil.ReadFile(s.confPath())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(content), gc.Equals, expectContent)
+}
+
+func (s *SystemdSuite) TestInstallSimple(c *gc.C) {
+	conf := s.dummyConf(c)
+	s.assertInstall(c, conf, "ExecStart=/bin/do something\n")
+}
+
+func (s *SystemdSuite) TestInstallExtraScript(c *gc.C) {
+	conf := s.dummyConf(c)
+	conf.ExtraScript = `echo "$HOME 100%"`
+	s.assertInstall(c, conf,
+		`ExecStart=/bin/bash -c "echo \"$$HOME 100%%\"\nexec /bin/do something"`+"\n")
+}
+
+func (s *SystemdSuite) TestInstallOutput(c *gc.C) {
+	conf := s.dummyConf(c)
+	conf.Out = "/some/output/path"
+	s.assertInstall(c, conf, `ExecStart=/bin/bash -c "`+
+		`touch '/some/output/path'\n`+
+		`chown syslog:syslog '/some/output/path'\n`+
+		`chmod 0600 '/some/output/path'\n`+
+		`exec /bin/do something >> '/some/output/path' 2>&1"`+"\n")
+}
+
+func (s *SystemdSuite) TestInstallEnv(c *gc.C) {
+	conf := s.dummyConf(c)
+	conf.Env = map[string]string{"FOO": "bar baz", "QUX": `"ping" pong`}
+	s.assertInstall(c, conf, `Environment="FOO=bar baz"
+Environment="QUX=\"ping\" pong"
+ExecStart=/bin/do something
+`)
+}
+
+func (s *SystemdSuite) TestInstallLimit(c *gc.C) {
+	conf := s.dummyConf(c)
+	conf.Limit = map[string]string{"nofile": "65000 65000", "nproc": "20000"}
+	s.assertInstall(c, conf, `LimitNOFILE=65000
+LimitNPROC=20000
+ExecStart=/bin/do something
+`)
+}
+
+func (s *SystemdSuite) TestMachineAgentService(c *gc.C) {
+	svc := systemd.MachineAgentService(
+		"jujud-machine-0", "/var/lib/juju/tools/machine-0", "/var/lib/juju",
+		"/var/log/juju", "machine-0", "0", nil)
+	c.Assert(svc.Name, gc.Equals, "jujud-machine-0")
+	c.Assert(svc.Conf.InitDir, gc.Equals, s.initDir)
+	c.Assert(svc.Conf.Desc, gc.Equals, "juju machine-0 agent")
+	c.Assert(svc.Conf.Out, gc.Equals, "/var/log/juju/machine-0.log")
+	c.Assert(svc.Conf.Limit, jc.DeepEquals, map[string]string{"nofile": "20000 20000"})
+}
@@ -0,0 +1,140 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/audit"
+)
+
+// auditEntryDoc is the persistent representation of an audit.Entry.
+// Audit entries are stored in a capped collection, so they are
+// inserted directly rather than through transactions and are never
+// updated.
+type auditEntryDoc struct {
+	Id        bson.ObjectId `bson:"_id"`
+	EnvUUID   string        `bson:"env-uuid"`
+	Timestamp time.Time     `bson:"timestamp"`
+	User      string        `bson:"user"`
+	Facade    string        `bson:"facade"`
+	Version   int           `bson:"version"`
+	Method    string        `bson:"method"`
+	Arguments string        `bson:"arguments"`
+	Error     string        `bson:"error,omitempty"`
+}
+
+func (doc *auditEntryDoc) entry() audit.Entry {
+	return audit.Entry{
+		Timestamp: doc.Timestamp,
+		EnvUUID:   doc.EnvUUID,
+		User:      doc.User,
+		Facade:    doc.Facade,
+		Version:   doc.Version,
+		Method:    doc.Method,
+		Arguments: doc.Arguments,
+		Error:     doc.Error,
+	}
+}
+
+// AuditFilter restricts the audit entries returned by AuditEntries.
+// Zero-valued fields do not restrict the results.
+type AuditFilter struct {
+	// User holds the tag of the entity that performed the operations.
+	User string
+
+	// Facade and Method restrict the results to the given API calls.
+	Facade string
+	Method string
+
+	// After and Before restrict the results to operations
+	// recorded in the given time range (inclusive).
+	After  time.Time
+	Before time.Time
+
+	// Limit holds the maximum number of entries to return. When
+	// more entries match, the most recent ones are returned.
+	Limit int
+}
+
+// AddAuditEntry records the given entry in the audit log. If the
+// entry has no environment UUID or timestamp, those of the current
+// environment and time are used.
+func (st *State) AddAuditEntry(entry audit.Entry) error {
+	if entry.User == "" {
+		return errors.New("cannot add audit entry: missing user")
+	}
+	if entry.Facade == "" || entry.Method == "" {
+		return errors.New("cannot add audit entry: missing operation")
+	}
+	if entry.EnvUUID == "" {
+		entry.EnvUUID = st.EnvironUUID()
+	}
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+	doc := auditEntryDoc{
+		Id:        bson.NewObjectId(),
+		EnvUUID:   entry.EnvUUID,
+		Timestamp: entry.Timestamp.UTC(),
+		User:      entry.User,
+		Facade:    entry.Facade,
+		Version:   entry.Version,
+		Method:    entry.Method,
+		Arguments: entry.Arguments,
+		Error:     entry.Error,
+	}
+	auditLog, closer := st.getCollection(auditC)
+	defer closer()
+	if err := auditLog.Insert(&doc); err != nil {
+		return errors.Annotate(err, "cannot add audit entry")
+	}
+	return nil
+}
+
+// AuditEntries returns the entries in the audit log of the current
+// environment that match the given filter, oldest first.
+func (st *State) AuditEntries(filter AuditFilter) ([]audit.Entry, error) {
+	query := bson.D{}
+	if filter.User != "" {
+		query = append(query, bson.DocElem{"user", filter.User})
+	}
+	if filter.Facade != "" {
+		query = append(query, bson.DocElem{"facade", filter.Facade})
+	}
+	if filter.Method != "" {
+		query = append(query, bson.DocElem{"method", filter.Method})
+	}
+	timeRange := bson.D{}
+	if !filter.After.IsZero() {
+		timeRange = append(timeRange, bson.DocElem{"$gte", filter.After.UTC()})
+	}
+	if !filter.Before.IsZero() {
+		timeRange = append(timeRange, bson.DocElem{"$lte", filter.Before.UTC()})
+	}
+	if len(timeRange) > 0 {
+		query = append(query, bson.DocElem{"timestamp", timeRange})
+	}
+
+	auditLog, closer := st.getCollection(auditC)
+	defer closer()
+	// Sort newest first so that any limit selects the most
+	// recent entries, then reverse the results below.
+	q := auditLog.Find(query).Sort("-timestamp", "-_id")
+	if filter.Limit > 0 {
+		q = q.Limit(filter.Limit)
+	}
+	var docs []auditEntryDoc
+	if err := q.All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get audit entries")
+	}
+	entries := make([]audit.Entry, len(docs))
+	for i, doc := range docs {
+		entries[len(docs)-1-i] = doc.entry()
+	}
+	return entries, nil
+}
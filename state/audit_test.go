@@ -0,0 +1,144 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/audit"
+	"github.com/juju/juju/state"
+)
+
+type AuditSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&AuditSuite{})
+
+var auditEpoch = time.Date(2014, 11, 5, 10, 0, 0, 0, time.UTC)
+
+func (s *AuditSuite) addEntries(c *gc.C) {
+	for i, entry := range []audit.Entry{{
+		User:   "user-admin",
+		Facade: "Client",
+		Method: "ServiceDeploy",
+	}, {
+		User:   "user-bob",
+		Facade: "Client",
+		Method: "ServiceDeploy",
+		Error:  "permission denied",
+	}, {
+		User:      "user-admin",
+		Facade:    "Client",
+		Method:    "DestroyMachines",
+		Arguments: `{"MachineNames":["1"]}`,
+	}, {
+		User:   "user-bob",
+		Facade: "UserManager",
+		Method: "SetPassword",
+	}} {
+		entry.Timestamp = auditEpoch.Add(time.Duration(i) * time.Minute)
+		err := s.State.AddAuditEntry(entry)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *AuditSuite) TestAddAuditEntryDefaults(c *gc.C) {
+	before := time.Now().Add(-time.Second)
+	err := s.State.AddAuditEntry(audit.Entry{
+		User:   "user-admin",
+		Facade: "Client",
+		Method: "FullStatus",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	entries, err := s.State.AuditEntries(state.AuditFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 1)
+	c.Assert(entries[0].EnvUUID, gc.Equals, s.State.EnvironUUID())
+	c.Assert(entries[0].Timestamp.After(before), jc.IsTrue)
+	c.Assert(entries[0].Operation(), gc.Equals, "Client.FullStatus")
+	c.Assert(entries[0].Outcome(), gc.Equals, "success")
+}
+
+func (s *AuditSuite) TestAddAuditEntryValidates(c *gc.C) {
+	err := s.State.AddAuditEntry(audit.Entry{Facade: "Client", Method: "FullStatus"})
+	c.Assert(err, gc.ErrorMatches, "cannot add audit entry: missing user")
+	err = s.State.AddAuditEntry(audit.Entry{User: "user-admin", Facade: "Client"})
+	c.Assert(err, gc.ErrorMatches, "cannot add audit entry: missing operation")
+}
+
+func (s *AuditSuite) TestAuditEntriesOrderedOldestFirst(c *gc.C) {
+	s.addEntries(c)
+	entries, err := s.State.AuditEntries(state.AuditFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 4)
+	for i, entry := range entries {
+		c.Check(entry.Timestamp.Equal(auditEpoch.Add(time.Duration(i)*time.Minute)), jc.IsTrue)
+	}
+	c.Assert(entries[1].Error, gc.Equals, "permission denied")
+	c.Assert(entries[2].Arguments, gc.Equals, `{"MachineNames":["1"]}`)
+}
+
+var auditFilterTests = []struct {
+	about   string
+	filter  state.AuditFilter
+	methods []string
+}{{
+	about:   "by user",
+	filter:  state.AuditFilter{User: "user-bob"},
+	methods: []string{"ServiceDeploy", "SetPassword"},
+}, {
+	about:   "by facade",
+	filter:  state.AuditFilter{Facade: "Client"},
+	methods: []string{"ServiceDeploy", "ServiceDeploy", "DestroyMachines"},
+}, {
+	about:   "by operation",
+	filter:  state.AuditFilter{Facade: "Client", Method: "DestroyMachines"},
+	methods: []string{"DestroyMachines"},
+}, {
+	about: "by time range",
+	filter: state.AuditFilter{
+		After:  auditEpoch.Add(time.Minute),
+		Before: auditEpoch.Add(2 * time.Minute),
+	},
+	methods: []string{"ServiceDeploy", "DestroyMachines"},
+}, {
+	about:   "limit returns most recent",
+	filter:  state.AuditFilter{Limit: 2},
+	methods: []string{"DestroyMachines", "SetPassword"},
+}, {
+	about:  "no match",
+	filter: state.AuditFilter{User: "user-nobody"},
+}}
+
+func (s *AuditSuite) TestAuditEntriesFilter(c *gc.C) {
+	s.addEntries(c)
+	for i, test := range auditFilterTests {
+		c.Logf("test %d: %s", i, test.about)
+		entries, err := s.State.AuditEntries(test.filter)
+		c.Assert(err, jc.ErrorIsNil)
+		var methods []string
+		for _, entry := range entries {
+			methods = append(methods, entry.Method)
+		}
+		c.Check(methods, jc.DeepEquals, test.methods)
+	}
+}
+
+func (s *AuditSuite) TestAuditEntriesScopedToEnvironment(c *gc.C) {
+	err := s.State.AddAuditEntry(audit.Entry{
+		EnvUUID: "another-env",
+		User:    "user-admin",
+		Facade:  "Client",
+		Method:  "FullStatus",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	entries, err := s.State.AuditEntries(state.AuditFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 0)
+}
@@ -37,11 +37,14 @@ package backups
 
 import (
 	"io"
+	"os"
 	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/utils/filestorage"
+
+	"github.com/juju/juju/mongo"
 )
 
 const (
@@ -80,14 +83,47 @@ func StoreArchive(stor filestorage.FileStorage, meta *Metadata, file io.Reader)
 	return nil
 }
 
+// State represents the state methods needed to create a backup.
+type State interface {
+	DB
+
+	// MongoConnectionInfo returns information for connecting to
+	// the database.
+	MongoConnectionInfo() *mongo.MongoInfo
+}
+
+// CreateBackup creates and stores a new backup of the given state,
+// as taken on the given machine, and returns its metadata. The
+// session is used to find the databases to dump. The backup is
+// recorded as scheduled if requested, and encrypted with the key
+// unless it is nil.
+func CreateBackup(b Backups, st State, session DBSession, paths *Paths, machine, notes string, scheduled bool, key []byte) (*Metadata, error) {
+	dbInfo, err := NewDBInfo(st.MongoConnectionInfo(), session)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	meta, err := NewMetadataState(st, machine)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	meta.Notes = notes
+	meta.Scheduled = scheduled
+	if err := b.Create(meta, paths, dbInfo, key); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return meta, nil
+}
+
 // Backups is an abstraction around all juju backup-related functionality.
 type Backups interface {
 
 	// Create creates and stores a new juju backup archive. It updates
-	// the provided metadata.
-	Create(meta *Metadata, paths *Paths, dbInfo *DBInfo) error
+	// the provided metadata. The archive is encrypted with the key
+	// unless it is nil.
+	Create(meta *Metadata, paths *Paths, dbInfo *DBInfo, key []byte) error
 
-	// Add stores the backup archive and returns its new ID.
+	// Add stores the backup archive and returns its new ID. The
+	// archive is checked against the metadata's checksum.
 	Add(archive io.Reader, meta *Metadata) (string, error)
 
 	// Get returns the metadata and archive file associated with the ID.
@@ -98,6 +134,10 @@ type Backups interface {
 
 	// Remove deletes the backup from storage.
 	Remove(id string) error
+
+	// Restore replaces the state and configuration of the state
+	// server described by args with the ones in the backup.
+	Restore(id string, paths *Paths, args RestoreArgs) error
 }
 
 type backups struct {
@@ -113,9 +153,16 @@ func NewBackups(stor filestorage.FileStorage) Backups {
 }
 
 // Create creates and stores a new juju backup archive and updates the
-// provided metadata.
-func (b *backups) Create(meta *Metadata, paths *Paths, dbInfo *DBInfo) error {
+// provided metadata. The archive is encrypted with the key unless it
+// is nil.
+func (b *backups) Create(meta *Metadata, paths *Paths, dbInfo *DBInfo, key []byte) error {
 	meta.Started = time.Now().UTC()
+	if key != nil {
+		if err := ValidateKey(key); err != nil {
+			return errors.Trace(err)
+		}
+		meta.KeyID = KeyID(key)
+	}
 
 	// The metadata file will not contain the ID or the "finished" data.
 	// However, that information is not as critical. The alternatives
@@ -136,7 +183,7 @@ func (b *backups) Create(meta *Metadata, paths *Paths, dbInfo *DBInfo) error {
 	if err != nil {
 		return errors.Annotate(err, "while preparing for DB dump")
 	}
-	args := createArgs{filesToBackUp, dumper, metadataFile}
+	args := createArgs{filesToBackUp, dumper, metadataFile, key}
 	result, err := runCreate(&args)
 	if err != nil {
 		return errors.Annotate(err, "while creating backup archive")
@@ -158,14 +205,25 @@ func (b *backups) Create(meta *Metadata, paths *Paths, dbInfo *DBInfo) error {
 	return nil
 }
 
-// Add stores the backup archive and returns its new ID.
+// Add stores the backup archive and returns its new ID. The archive
+// is checked against the metadata's checksum as it is stored, and
+// removed again if it does not match.
 func (b *backups) Add(archive io.Reader, meta *Metadata) (string, error) {
 	// Store the archive.
-	err := storeArchive(b.storage, meta, archive)
+	hasher := ChecksumHasher()
+	err := storeArchive(b.storage, meta, io.TeeReader(archive, hasher))
 	if err != nil {
 		return "", errors.Annotate(err, "while storing backup archive")
 	}
 
+	// Verify the archive.
+	if err := VerifyChecksum(meta, EncodeChecksum(hasher)); err != nil {
+		if rerr := b.storage.Remove(meta.ID()); rerr != nil {
+			logger.Errorf("cannot remove corrupt backup %q: %v", meta.ID(), rerr)
+		}
+		return "", errors.Annotate(err, "while verifying backup archive")
+	}
+
 	return meta.ID(), nil
 }
 
@@ -206,3 +264,55 @@ func (b *backups) List() ([]*Metadata, error) {
 func (b *backups) Remove(id string) error {
 	return errors.Trace(b.storage.Remove(id))
 }
+
+// Restore replaces the state and configuration of the state server
+// described by args with the ones in the backup. The backup must have
+// been taken on the same machine. The archive is checked against the
+// metadata's checksum, and decrypted with the key in args if needed,
+// before anything is restored.
+func (b *backups) Restore(id string, paths *Paths, args RestoreArgs) error {
+	meta, stored, err := b.Get(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer stored.Close()
+
+	archive, err := openVerifiedArchive(meta, stored, args.EncryptionKey)
+	if err != nil {
+		return errors.Annotate(err, "while verifying backup archive")
+	}
+	defer archive.Close()
+
+	origin := meta.Origin
+	if origin.Machine == UnknownString {
+		// Backups uploaded without their metadata, which is the case
+		// for encrypted ones, only have it in the archive itself.
+		if origin, err = archiveOrigin(archive); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if origin.Machine != args.MachineId {
+		return errors.Errorf("cannot restore backup of machine %q onto machine %q", origin.Machine, args.MachineId)
+	}
+	if err := runRestore(archive, paths, args); err != nil {
+		return errors.Annotate(err, "while restoring backup")
+	}
+	return nil
+}
+
+// archiveOrigin returns the origin recorded in the metadata file of
+// the archive, which is rewound afterwards.
+func archiveOrigin(archive io.ReadSeeker) (Origin, error) {
+	ad, err := NewArchiveDataReader(archive)
+	if err != nil {
+		return Origin{}, errors.Annotate(err, "while reading backup archive")
+	}
+	if _, err := archive.Seek(0, os.SEEK_SET); err != nil {
+		return Origin{}, errors.Trace(err)
+	}
+	meta, err := ad.Metadata()
+	if err != nil {
+		return Origin{}, errors.Annotate(err, "while reading backup metadata")
+	}
+	return meta.Origin, nil
+}
@@ -5,6 +5,7 @@ package backups_test
 
 import (
 	"bytes"
+	"io"
 	"io/ioutil"
 	"time"
 
@@ -56,7 +57,7 @@ func (s *backupsSuite) checkFailure(c *gc.C, expected string) {
 	dbInfo := backups.DBInfo{"a", "b", "c", targets}
 	meta := backupstesting.NewMetadataStarted()
 	meta.Notes = "some notes"
-	err := s.api.Create(meta, &paths, &dbInfo)
+	err := s.api.Create(meta, &paths, &dbInfo, nil)
 
 	c.Check(err, gc.ErrorMatches, expected)
 }
@@ -96,7 +97,7 @@ func (s *backupsSuite) TestCreateOkay(c *gc.C) {
 	meta := backupstesting.NewMetadataStarted()
 	backupstesting.SetOrigin(meta, "<env ID>", "<machine ID>", "<hostname>")
 	meta.Notes = "some notes"
-	err := s.api.Create(meta, &paths, &dbInfo)
+	err := s.api.Create(meta, &paths, &dbInfo, nil)
 
 	// Test the call values.
 	s.Storage.CheckCalled(c, "spam", meta, archiveFile, "Add", "Metadata")
@@ -186,3 +187,180 @@ func (s *backupsSuite) TestStoreArchive(c *gc.C) {
 	c.Assert(meta.ID(), gc.Equals, "spam")
 	c.Assert(meta.Stored(), jc.DeepEquals, stored)
 }
+
+func (s *backupsSuite) TestRestore(c *gc.C) {
+	s.setStored("spam")
+	backupstesting.SetOrigin(s.Storage.Meta, "<env ID>", "0", "<hostname>")
+	s.Storage.File = ioutil.NopCloser(bytes.NewBufferString("<compressed tarball>"))
+
+	var receivedPaths *backups.Paths
+	var receivedArgs backups.RestoreArgs
+	var data []byte
+	s.PatchValue(backups.RunRestore, func(archive io.Reader, paths *backups.Paths, args backups.RestoreArgs) error {
+		receivedPaths = paths
+		receivedArgs = args
+		var err error
+		data, err = ioutil.ReadAll(archive)
+		return err
+	})
+
+	paths := backups.Paths{DataDir: "/var/lib/juju"}
+	args := backups.RestoreArgs{
+		MachineId:      "0",
+		InstanceId:     "inst-0",
+		PrivateAddress: "10.0.0.1",
+		PublicAddress:  "1.2.3.4",
+	}
+	err := s.api.Restore("spam", &paths, args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(receivedPaths, gc.Equals, &paths)
+	c.Check(receivedArgs, jc.DeepEquals, args)
+	c.Check(string(data), gc.Equals, "<compressed tarball>")
+}
+
+func (s *backupsSuite) TestRestoreWrongMachine(c *gc.C) {
+	s.setStored("spam")
+	backupstesting.SetOrigin(s.Storage.Meta, "<env ID>", "1", "<hostname>")
+	s.Storage.File = ioutil.NopCloser(bytes.NewBufferString("<compressed tarball>"))
+	s.PatchValue(backups.RunRestore, func(io.Reader, *backups.Paths, backups.RestoreArgs) error {
+		c.Fatalf("restore should not be run")
+		return nil
+	})
+
+	paths := backups.Paths{DataDir: "/var/lib/juju"}
+	err := s.api.Restore("spam", &paths, backups.RestoreArgs{MachineId: "0"})
+	c.Check(err, gc.ErrorMatches, `cannot restore backup of machine "1" onto machine "0"`)
+}
+
+func (s *backupsSuite) TestRestoreFailure(c *gc.C) {
+	s.setStored("spam")
+	backupstesting.SetOrigin(s.Storage.Meta, "<env ID>", "0", "<hostname>")
+	s.Storage.File = ioutil.NopCloser(bytes.NewBufferString("<compressed tarball>"))
+	s.PatchValue(backups.RunRestore, func(io.Reader, *backups.Paths, backups.RestoreArgs) error {
+		return errors.New("failed!")
+	})
+
+	paths := backups.Paths{DataDir: "/var/lib/juju"}
+	err := s.api.Restore("spam", &paths, backups.RestoreArgs{MachineId: "0"})
+	c.Check(err, gc.ErrorMatches, "while restoring backup: failed!")
+}
+
+func (s *backupsSuite) TestAddChecksumMismatch(c *gc.C) {
+	s.setStored("spam")
+	meta := backupstesting.NewMetadataStarted()
+	err := meta.MarkComplete(10, "<bogus checksum>")
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.api.Add(bytes.NewBufferString("<compressed tarball>"), meta)
+	c.Check(err, gc.ErrorMatches, `while verifying backup archive: checksum mismatch: expected "<bogus checksum>", got ".*"`)
+	c.Check(s.Storage.Calls, jc.DeepEquals, []string{"Add", "Metadata", "Remove"})
+	c.Check(s.Storage.IDArg, gc.Equals, "spam")
+}
+
+func (s *backupsSuite) setStoredArchive(c *gc.C, data []byte) {
+	s.setStored("spam")
+	backupstesting.SetOrigin(s.Storage.Meta, "<env ID>", "0", "<hostname>")
+	hasher := backups.ChecksumHasher()
+	hasher.Write(data)
+	err := s.Storage.Meta.MarkComplete(int64(len(data)), backups.EncodeChecksum(hasher))
+	c.Assert(err, jc.ErrorIsNil)
+	s.Storage.File = ioutil.NopCloser(bytes.NewBuffer(data))
+}
+
+func (s *backupsSuite) patchRunRestore(c *gc.C) *string {
+	var data string
+	s.PatchValue(backups.RunRestore, func(archive io.Reader, paths *backups.Paths, args backups.RestoreArgs) error {
+		raw, err := ioutil.ReadAll(archive)
+		data = string(raw)
+		return err
+	})
+	return &data
+}
+
+func (s *backupsSuite) TestRestoreChecksumMismatch(c *gc.C) {
+	s.setStoredArchive(c, []byte("<compressed tarball>"))
+	s.Storage.File = ioutil.NopCloser(bytes.NewBufferString("<corrupt tarball>"))
+	data := s.patchRunRestore(c)
+
+	paths := backups.Paths{DataDir: "/var/lib/juju"}
+	err := s.api.Restore("spam", &paths, backups.RestoreArgs{MachineId: "0"})
+	c.Check(err, gc.ErrorMatches, `while verifying backup archive: checksum mismatch: .*`)
+	c.Check(*data, gc.Equals, "")
+}
+
+func (s *backupsSuite) encrypt(c *gc.C, data string, key []byte) []byte {
+	var buf bytes.Buffer
+	encrypter, err := backups.NewEncryptingWriter(&buf, key)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = io.WriteString(encrypter, data)
+	c.Assert(err, jc.ErrorIsNil)
+	err = encrypter.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	return buf.Bytes()
+}
+
+func (s *backupsSuite) TestRestoreEncrypted(c *gc.C) {
+	key := []byte("some secret key!")
+	s.setStoredArchive(c, s.encrypt(c, "<compressed tarball>", key))
+	s.Storage.Meta.KeyID = backups.KeyID(key)
+	data := s.patchRunRestore(c)
+
+	paths := backups.Paths{DataDir: "/var/lib/juju"}
+	args := backups.RestoreArgs{MachineId: "0", EncryptionKey: key}
+	err := s.api.Restore("spam", &paths, args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(*data, gc.Equals, "<compressed tarball>")
+}
+
+func (s *backupsSuite) TestRestoreEncryptedNoKey(c *gc.C) {
+	key := []byte("some secret key!")
+	s.setStoredArchive(c, s.encrypt(c, "<compressed tarball>", key))
+	data := s.patchRunRestore(c)
+
+	paths := backups.Paths{DataDir: "/var/lib/juju"}
+	err := s.api.Restore("spam", &paths, backups.RestoreArgs{MachineId: "0"})
+	c.Check(err, gc.ErrorMatches, `while verifying backup archive: backup is encrypted with key [0-9a-f]{16}; key not provided`)
+	c.Check(*data, gc.Equals, "")
+}
+
+func (s *backupsSuite) TestRestoreEncryptedWrongKey(c *gc.C) {
+	s.setStoredArchive(c, s.encrypt(c, "<compressed tarball>", []byte("some secret key!")))
+	data := s.patchRunRestore(c)
+
+	paths := backups.Paths{DataDir: "/var/lib/juju"}
+	args := backups.RestoreArgs{MachineId: "0", EncryptionKey: []byte("another secret!!")}
+	err := s.api.Restore("spam", &paths, args)
+	c.Check(err, gc.ErrorMatches, `while verifying backup archive: backup archive is encrypted with key .*, not .*`)
+	c.Check(*data, gc.Equals, "")
+}
+
+func (s *backupsSuite) TestCreateEncryptedSetsKeyID(c *gc.C) {
+	received, testCreate := backups.NewTestCreate(nil)
+	s.PatchValue(backups.RunCreate, testCreate)
+	s.PatchValue(backups.TestGetFilesToBackUp, func(root string, paths *backups.Paths, oldmachine string) ([]string, error) {
+		return []string{}, nil
+	})
+	s.PatchValue(backups.GetDBDumper, func(*backups.DBInfo) (backups.DBDumper, error) {
+		return &fakeDumper{}, nil
+	})
+	s.setStored("spam")
+
+	key := []byte("some secret key!")
+	paths := backups.Paths{DataDir: "/var/lib/juju"}
+	dbInfo := backups.DBInfo{"a", "b", "c", set.NewStrings("juju")}
+	meta := backupstesting.NewMetadataStarted()
+	err := s.api.Create(meta, &paths, &dbInfo, key)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(meta.KeyID, gc.Equals, backups.KeyID(key))
+	c.Check(backups.ExposeCreateArgsKey(received), jc.DeepEquals, key)
+}
+
+func (s *backupsSuite) TestCreateKeyTooShort(c *gc.C) {
+	paths := backups.Paths{DataDir: "/var/lib/juju"}
+	dbInfo := backups.DBInfo{"a", "b", "c", set.NewStrings("juju")}
+	meta := backupstesting.NewMetadataStarted()
+	err := s.api.Create(meta, &paths, &dbInfo, []byte("short"))
+	c.Check(err, gc.ErrorMatches, "encryption key too short: .*")
+}
@@ -31,6 +31,8 @@ type createArgs struct {
 	filesToBackUp  []string
 	db             DBDumper
 	metadataReader io.Reader
+	// encryptionKey is the key to encrypt the archive with, if any.
+	encryptionKey []byte
 }
 
 type createResult struct {
@@ -47,6 +49,7 @@ func create(args *createArgs) (_ *createResult, err error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	builder.encryptionKey = args.encryptionKey
 	defer func() {
 		if cerr := builder.cleanUp(); cerr != nil {
 			cerr.Log(logger)
@@ -98,6 +101,9 @@ type builder struct {
 	db DBDumper
 	// checksum is the checksum of the archive file.
 	checksum string
+	// encryptionKey is the key to encrypt the archive file with. The
+	// archive is not encrypted if it is nil.
+	encryptionKey []byte
 	// archiveFile is the backup archive file.
 	archiveFile io.WriteCloser
 	// bundleFile is the inner archive file containing all the juju
@@ -313,8 +319,23 @@ func (b *builder) buildArchiveAndChecksum() error {
 	// that users can compare the published checksum against the
 	// checksum of the file without having to decompress it first.
 	hasher := hash.NewHashingWriter(b.archiveFile, sha1.New())
-	if err := b.buildArchive(hasher); err != nil {
-		return errors.Trace(err)
+	if b.encryptionKey == nil {
+		if err := b.buildArchive(hasher); err != nil {
+			return errors.Trace(err)
+		}
+	} else {
+		// The checksum is of the encrypted archive, as that is
+		// what gets stored and downloaded.
+		encrypter, err := NewEncryptingWriter(hasher, b.encryptionKey)
+		if err != nil {
+			return errors.Annotate(err, "while preparing to encrypt archive")
+		}
+		if err := b.buildArchive(encrypter); err != nil {
+			return errors.Trace(err)
+		}
+		if err := encrypter.Close(); err != nil {
+			return errors.Annotate(err, "while encrypting archive")
+		}
 	}
 
 	// Save the SHA1 checksum.
@@ -4,7 +4,9 @@
 package backups_test
 
 import (
+	"io"
 	"os"
+	"path/filepath"
 
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -52,6 +54,43 @@ func (s *createSuite) TestLegacy(c *gc.C) {
 	s.checkArchive(c, file, expected)
 }
 
+func (s *createSuite) TestEncrypted(c *gc.C) {
+	meta := backupstesting.NewMetadataStarted()
+	metadataFile, err := meta.AsJSONBuffer()
+	c.Assert(err, jc.ErrorIsNil)
+	_, testFiles, expected := s.createTestFiles(c)
+
+	key := []byte("0123456789abcdef")
+	dumper := &TestDBDumper{}
+	args := backups.NewTestCreateArgs(testFiles, dumper, metadataFile)
+	backups.SetCreateArgsKey(args, key)
+	result, err := backups.Create(args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	archiveFile, size, checksum := backups.ExposeCreateResult(result)
+	file, ok := archiveFile.(*os.File)
+	c.Assert(ok, jc.IsTrue)
+
+	// The size and checksum are those of the encrypted archive.
+	s.checkSize(c, file, size)
+	s.checkChecksum(c, file, checksum)
+
+	keyID, err := backups.ArchiveKeyID(file)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(keyID, gc.Equals, backups.KeyID(key))
+	resetFile(c, file)
+
+	decrypter, err := backups.NewDecryptingReader(file, key)
+	c.Assert(err, jc.ErrorIsNil)
+	plain, err := os.Create(filepath.Join(c.MkDir(), "juju-backup.tar.gz"))
+	c.Assert(err, jc.ErrorIsNil)
+	defer plain.Close()
+	_, err = io.Copy(plain, decrypter)
+	c.Assert(err, jc.ErrorIsNil)
+	resetFile(c, plain)
+	s.checkArchive(c, plain, expected)
+}
+
 func (s *createSuite) TestMetadataFileMissing(c *gc.C) {
 	var testFiles []string
 	dumper := &TestDBDumper{}
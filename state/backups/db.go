@@ -210,3 +210,74 @@ func listDatabases(dumpDir string) (set.Strings, error) {
 	}
 	return databases, nil
 }
+
+const restoreName = "mongorestore"
+
+// DBRestorer is any type that restores something from a dump dir.
+type DBRestorer interface {
+	// Restore replaces the databases with the ones in dumpDir.
+	Restore(dumpDir string) error
+}
+
+var getMongorestorePath = func() (string, error) {
+	mongod, err := mongo.Path()
+	if err != nil {
+		return "", errors.Annotate(err, "failed to get mongod path")
+	}
+	mongoRestorePath := filepath.Join(filepath.Dir(mongod), restoreName)
+
+	if _, err := os.Stat(mongoRestorePath); err == nil {
+		// It already exists so no need to continue.
+		return mongoRestorePath, nil
+	}
+
+	path, err := exec.LookPath(restoreName)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return path, nil
+}
+
+type mongoRestorer struct {
+	// dbPath is the path to the mongo data files.
+	dbPath string
+	// binPath is the path to the restore executable.
+	binPath string
+}
+
+// NewDBRestorer returns a new value with a Restore method for
+// replacing the juju state database, whose files are in dbPath, with
+// the databases dumped by a DBDumper. The database server must not be
+// running while the files are replaced.
+func NewDBRestorer(dbPath string) (DBRestorer, error) {
+	mongorestorePath, err := getMongorestorePath()
+	if err != nil {
+		return nil, errors.Annotate(err, "mongorestore not available")
+	}
+
+	restorer := mongoRestorer{
+		dbPath:  dbPath,
+		binPath: mongorestorePath,
+	}
+	return &restorer, nil
+}
+
+func (mr *mongoRestorer) options(dumpDir string) []string {
+	options := []string{
+		"--drop",
+		"--dbpath", mr.dbPath,
+		dumpDir,
+	}
+	return options
+}
+
+// Restore replaces the juju state-related databases with the ones
+// in the dump dir. The databases that are not in the dump are left
+// untouched.
+func (mr *mongoRestorer) Restore(dumpDir string) error {
+	options := mr.options(dumpDir)
+	if err := runCommand(mr.binPath, options...); err != nil {
+		return errors.Annotate(err, "error restoring databases")
+	}
+	return nil
+}
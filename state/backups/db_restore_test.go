@@ -0,0 +1,65 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/testing"
+)
+
+type restorerSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&restorerSuite{}) // Register the suite.
+
+func (s *restorerSuite) TestRestoreRanCommand(c *gc.C) {
+	s.PatchValue(backups.GetMongorestorePath, func() (string, error) {
+		return "bogusmongorestore", nil
+	})
+	var ranCmd string
+	var ranArgs []string
+	s.PatchValue(backups.RunCommand, func(cmd string, args ...string) error {
+		ranCmd = cmd
+		ranArgs = args
+		return nil
+	})
+
+	restorer, err := backups.NewDBRestorer("/var/lib/juju/db")
+	c.Assert(err, jc.ErrorIsNil)
+	err = restorer.Restore("/tmp/dump")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(ranCmd, gc.Equals, "bogusmongorestore")
+	c.Check(ranArgs, jc.DeepEquals, []string{
+		"--drop", "--dbpath", "/var/lib/juju/db", "/tmp/dump",
+	})
+}
+
+func (s *restorerSuite) TestRestoreFailed(c *gc.C) {
+	s.PatchValue(backups.GetMongorestorePath, func() (string, error) {
+		return "bogusmongorestore", nil
+	})
+	s.PatchValue(backups.RunCommand, func(cmd string, args ...string) error {
+		return errors.New("failed!")
+	})
+
+	restorer, err := backups.NewDBRestorer("/var/lib/juju/db")
+	c.Assert(err, jc.ErrorIsNil)
+	err = restorer.Restore("/tmp/dump")
+	c.Check(err, gc.ErrorMatches, "error restoring databases: failed!")
+}
+
+func (s *restorerSuite) TestNewDBRestorerNoMongorestore(c *gc.C) {
+	s.PatchValue(backups.GetMongorestorePath, func() (string, error) {
+		return "", errors.New("not found")
+	})
+
+	_, err := backups.NewDBRestorer("/var/lib/juju/db")
+	c.Check(err, gc.ErrorMatches, "mongorestore not available: not found")
+}
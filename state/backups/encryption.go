@@ -0,0 +1,244 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+
+	"github.com/juju/errors"
+)
+
+// An encrypted backup archive is laid out as follows:
+//
+//   magic (8 bytes) | key ID (8 bytes) | IV (16 bytes) | ciphertext | MAC (32 bytes)
+//
+// The ciphertext is the plain archive encrypted with AES-256 in CTR
+// mode.  The MAC is an HMAC-SHA256 of everything that precedes it.
+// The encryption and MAC keys are both derived from the user-supplied
+// key, so the user only has to keep track of a single key.  The key
+// ID identifies that key without revealing it.
+
+const (
+	encryptionMagic = "JUJUBKE1"
+	keyIDSize       = 8
+	macSize         = sha256.Size
+	headerSize      = len(encryptionMagic) + keyIDSize + aes.BlockSize
+
+	// MinKeySize is the minimum size, in bytes, of a backup
+	// encryption key.
+	MinKeySize = 16
+)
+
+// ErrIntegrity is returned when reading an encrypted backup archive
+// that is corrupt or has been tampered with.
+var ErrIntegrity = errors.New("backup archive failed integrity check")
+
+// deriveKey derives a key for the given purpose from the user-supplied
+// key.
+func deriveKey(key []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
+
+func rawKeyID(key []byte) []byte {
+	return deriveKey(key, "juju backups key id")[:keyIDSize]
+}
+
+// KeyID returns the ID of the given encryption key, as recorded in
+// the metadata of the backups encrypted with it.
+func KeyID(key []byte) string {
+	return hex.EncodeToString(rawKeyID(key))
+}
+
+// ValidateKey checks that the key can be used to encrypt backups.
+func ValidateKey(key []byte) error {
+	if len(key) < MinKeySize {
+		return errors.Errorf("encryption key too short: need at least %d bytes, got %d", MinKeySize, len(key))
+	}
+	return nil
+}
+
+// newCipher returns the stream cipher and MAC for the given key and IV.
+func newCipher(key, iv []byte) (cipher.Stream, hash.Hash, error) {
+	block, err := aes.NewCipher(deriveKey(key, "juju backups encryption"))
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	stream := cipher.NewCTR(block, iv)
+	mac := hmac.New(sha256.New, deriveKey(key, "juju backups authentication"))
+	return stream, mac, nil
+}
+
+// ArchiveKeyID returns the ID of the key the archive read from r is
+// encrypted with, or "" if it is not encrypted.  It consumes the start
+// of the archive.
+func ArchiveKeyID(r io.Reader) (string, error) {
+	header := make([]byte, headerSize)
+	n, err := io.ReadFull(r, header)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return "", nil
+	}
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if !bytes.HasPrefix(header[:n], []byte(encryptionMagic)) {
+		return "", nil
+	}
+	return hex.EncodeToString(header[len(encryptionMagic) : len(encryptionMagic)+keyIDSize]), nil
+}
+
+type encryptingWriter struct {
+	w      io.Writer
+	stream cipher.Stream
+	mac    hash.Hash
+	buf    []byte
+}
+
+// NewEncryptingWriter returns a writer that encrypts everything
+// written to it with the given key and writes the result to w.  The
+// writer must be closed to complete the encrypted archive; closing it
+// does not close w.
+func NewEncryptingWriter(w io.Writer, key []byte) (io.WriteCloser, error) {
+	if err := ValidateKey(key); err != nil {
+		return nil, errors.Trace(err)
+	}
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, errors.Annotate(err, "cannot generate IV")
+	}
+	stream, mac, err := newCipher(key, iv)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	header := make([]byte, 0, headerSize)
+	header = append(header, encryptionMagic...)
+	header = append(header, rawKeyID(key)...)
+	header = append(header, iv...)
+	if _, err := w.Write(header); err != nil {
+		return nil, errors.Trace(err)
+	}
+	mac.Write(header)
+
+	return &encryptingWriter{
+		w:      w,
+		stream: stream,
+		mac:    mac,
+	}, nil
+}
+
+// Write implements io.Writer.
+func (e *encryptingWriter) Write(p []byte) (int, error) {
+	if cap(e.buf) < len(p) {
+		e.buf = make([]byte, len(p))
+	}
+	ciphertext := e.buf[:len(p)]
+	e.stream.XORKeyStream(ciphertext, p)
+	e.mac.Write(ciphertext)
+	if _, err := e.w.Write(ciphertext); err != nil {
+		return 0, errors.Trace(err)
+	}
+	return len(p), nil
+}
+
+// Close implements io.Closer.  It writes the MAC of the archive.
+func (e *encryptingWriter) Close() error {
+	_, err := e.w.Write(e.mac.Sum(nil))
+	return errors.Trace(err)
+}
+
+type decryptingReader struct {
+	r      io.Reader
+	stream cipher.Stream
+	mac    hash.Hash
+	// buf holds ciphertext read ahead from r.  The last macSize bytes
+	// read are held back, as they may be the MAC.
+	buf   []byte
+	chunk []byte
+	eof   bool
+	done  bool
+}
+
+// NewDecryptingReader returns a reader that decrypts the archive read
+// from r with the given key.  The archive's MAC is checked once the
+// end of r is reached: if it does not match, ErrIntegrity is returned
+// instead of io.EOF, and everything read so far must be discarded.
+func NewDecryptingReader(r io.Reader, key []byte) (io.Reader, error) {
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, errors.New("backup archive is not encrypted")
+		}
+		return nil, errors.Trace(err)
+	}
+	if !bytes.HasPrefix(header, []byte(encryptionMagic)) {
+		return nil, errors.New("backup archive is not encrypted")
+	}
+	keyID := header[len(encryptionMagic) : len(encryptionMagic)+keyIDSize]
+	if !bytes.Equal(keyID, rawKeyID(key)) {
+		return nil, errors.Errorf("backup archive is encrypted with key %s, not %s", hex.EncodeToString(keyID), KeyID(key))
+	}
+	iv := header[len(encryptionMagic)+keyIDSize:]
+	stream, mac, err := newCipher(key, iv)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	mac.Write(header)
+
+	return &decryptingReader{
+		r:      r,
+		stream: stream,
+		mac:    mac,
+		chunk:  make([]byte, 32*1024),
+	}, nil
+}
+
+// Read implements io.Reader.
+func (d *decryptingReader) Read(p []byte) (int, error) {
+	if d.done {
+		return 0, io.EOF
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for !d.eof && len(d.buf) < len(p)+macSize {
+		n, err := d.r.Read(d.chunk)
+		d.buf = append(d.buf, d.chunk[:n]...)
+		if err == io.EOF {
+			d.eof = true
+		} else if err != nil {
+			return 0, errors.Trace(err)
+		}
+	}
+
+	available := len(d.buf) - macSize
+	if available <= 0 {
+		// We are at the end of the archive; all that is left is
+		// the MAC.
+		d.done = true
+		if available < 0 || !hmac.Equal(d.buf, d.mac.Sum(nil)) {
+			return 0, ErrIntegrity
+		}
+		return 0, io.EOF
+	}
+
+	n := available
+	if n > len(p) {
+		n = len(p)
+	}
+	ciphertext := d.buf[:n]
+	d.mac.Write(ciphertext)
+	d.stream.XORKeyStream(p[:n], ciphertext)
+	d.buf = append(d.buf[:0], d.buf[n:]...)
+	return n, nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/testing"
+)
+
+type encryptionSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&encryptionSuite{}) // Register the suite.
+
+var testKey = []byte("0123456789abcdef")
+
+func (s *encryptionSuite) encrypt(c *gc.C, data string) []byte {
+	var buf bytes.Buffer
+	encrypter, err := backups.NewEncryptingWriter(&buf, testKey)
+	c.Assert(err, jc.ErrorIsNil)
+	// Write in two parts to check the stream is continuous.
+	_, err = io.WriteString(encrypter, data[:len(data)/2])
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = io.WriteString(encrypter, data[len(data)/2:])
+	c.Assert(err, jc.ErrorIsNil)
+	err = encrypter.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	return buf.Bytes()
+}
+
+func (s *encryptionSuite) TestRoundTrip(c *gc.C) {
+	data := string(bytes.Repeat([]byte("<compressed archive data>"), 5000))
+	encrypted := s.encrypt(c, data)
+	c.Check(bytes.Contains(encrypted, []byte("<compressed archive data>")), jc.IsFalse)
+
+	decrypter, err := backups.NewDecryptingReader(bytes.NewReader(encrypted), testKey)
+	c.Assert(err, jc.ErrorIsNil)
+	decrypted, err := ioutil.ReadAll(decrypter)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(decrypted), gc.Equals, data)
+}
+
+func (s *encryptionSuite) TestArchiveKeyID(c *gc.C) {
+	encrypted := s.encrypt(c, "<compressed archive data>")
+
+	keyID, err := backups.ArchiveKeyID(bytes.NewReader(encrypted))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(keyID, gc.Equals, backups.KeyID(testKey))
+	c.Check(keyID, gc.HasLen, 16)
+
+	keyID, err = backups.ArchiveKeyID(bytes.NewBufferString("<compressed archive data>"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(keyID, gc.Equals, "")
+}
+
+func (s *encryptionSuite) TestKeyTooShort(c *gc.C) {
+	_, err := backups.NewEncryptingWriter(&bytes.Buffer{}, []byte("short"))
+	c.Check(err, gc.ErrorMatches, "encryption key too short: need at least 16 bytes, got 5")
+}
+
+func (s *encryptionSuite) TestDecryptWrongKey(c *gc.C) {
+	encrypted := s.encrypt(c, "<compressed archive data>")
+	otherKey := []byte("fedcba9876543210")
+
+	_, err := backups.NewDecryptingReader(bytes.NewReader(encrypted), otherKey)
+	c.Check(err, gc.ErrorMatches, "backup archive is encrypted with key [0-9a-f]{16}, not [0-9a-f]{16}")
+}
+
+func (s *encryptionSuite) TestDecryptNotEncrypted(c *gc.C) {
+	_, err := backups.NewDecryptingReader(bytes.NewBufferString("<compressed archive data>"), testKey)
+	c.Check(err, gc.ErrorMatches, "backup archive is not encrypted")
+}
+
+func (s *encryptionSuite) TestDecryptTampered(c *gc.C) {
+	encrypted := s.encrypt(c, "<compressed archive data>")
+	encrypted[len(encrypted)-40] ^= 1
+
+	decrypter, err := backups.NewDecryptingReader(bytes.NewReader(encrypted), testKey)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = ioutil.ReadAll(decrypter)
+	c.Check(err, gc.Equals, backups.ErrIntegrity)
+}
+
+func (s *encryptionSuite) TestDecryptTruncated(c *gc.C) {
+	encrypted := s.encrypt(c, "<compressed archive data>")
+	encrypted = encrypted[:len(encrypted)-1]
+
+	decrypter, err := backups.NewDecryptingReader(bytes.NewReader(encrypted), testKey)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = ioutil.ReadAll(decrypter)
+	c.Check(err, gc.Equals, backups.ErrIntegrity)
+}
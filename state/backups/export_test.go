@@ -27,10 +27,19 @@ var (
 	StoreArchiveRef      = &storeArchive
 	GetMongodumpPath     = &getMongodumpPath
 	RunCommand           = &runCommand
+	RunRestore           = &runRestore
+	Restore              = restore
+	GetDBRestorer        = &getDBRestorer
+	MongoService         = &mongoService
+	GetMongorestorePath  = &getMongorestorePath
+	OpenTarget           = openTarget
+	FilesystemRoot       = &filesystemRoot
+	AgentAddressScript   = setAgentAddressScript
 )
 
 var _ filestorage.DocStorage = (*backupsDocStorage)(nil)
 var _ filestorage.RawFileStorage = (*backupBlobStorage)(nil)
+var _ filestorage.RawFileStorage = (*targetFileStorage)(nil)
 
 func getBackupDBWrapper(st *state.State) *storageDBWrapper {
 	envUUID := st.EnvironTag().Id()
@@ -106,6 +115,17 @@ func ExposeCreateArgs(args *createArgs) ([]string, DBDumper) {
 	return args.filesToBackUp, args.db
 }
 
+// SetCreateArgsKey sets the encryption key in a create() args value.
+func SetCreateArgsKey(args *createArgs, key []byte) {
+	args.encryptionKey = key
+}
+
+// ExposeCreateArgsKey extracts the encryption key in a create() args
+// value.
+func ExposeCreateArgsKey(args *createArgs) []byte {
+	return args.encryptionKey
+}
+
 // NewTestCreateResult builds a new create() result.
 func NewTestCreateResult(file io.ReadCloser, size int64, checksum string) *createResult {
 	result := createResult{
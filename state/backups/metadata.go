@@ -62,6 +62,13 @@ type Metadata struct {
 	Origin Origin
 	// Notes is an optional user-supplied annotation.
 	Notes string
+	// KeyID identifies the key the archive is encrypted with.  It is
+	// empty if the archive is not encrypted.
+	KeyID string
+	// Scheduled records whether the backup was created on schedule
+	// rather than on request.  Only scheduled backups are subject to
+	// retention policies.
+	Scheduled bool
 }
 
 // NewMetadata returns a new Metadata for a state backup archive.  Only
@@ -130,6 +137,8 @@ type flatMetadata struct {
 	Started     time.Time
 	Finished    time.Time
 	Notes       string
+	KeyID       string
+	Scheduled   bool
 	Environment string
 	Machine     string
 	Hostname    string
@@ -149,6 +158,8 @@ func (m *Metadata) AsJSONBuffer() (io.Reader, error) {
 
 		Started:     m.Started,
 		Notes:       m.Notes,
+		KeyID:       m.KeyID,
+		Scheduled:   m.Scheduled,
 		Environment: m.Origin.Environment,
 		Machine:     m.Origin.Machine,
 		Hostname:    m.Origin.Hostname,
@@ -195,6 +206,8 @@ func NewMetadataJSONReader(in io.Reader) (*Metadata, error) {
 		meta.Finished = &flat.Finished
 	}
 	meta.Notes = flat.Notes
+	meta.KeyID = flat.KeyID
+	meta.Scheduled = flat.Scheduled
 	meta.Origin = Origin{
 		Environment: flat.Environment,
 		Machine:     flat.Machine,
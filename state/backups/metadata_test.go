@@ -52,6 +52,8 @@ func (s *metadataSuite) TestAsJSONBuffer(c *gc.C) {
 		`"Started":"2014-09-09T11:59:34Z",`+
 		`"Finished":"2014-09-09T12:00:34Z",`+
 		`"Notes":"",`+
+		`"KeyID":"",`+
+		`"Scheduled":false,`+
 		`"Environment":"asdf-zxcv-qwe",`+
 		`"Machine":"0",`+
 		`"Hostname":"myhost",`+
@@ -69,6 +71,8 @@ func (s *metadataSuite) TestNewMetadataJSONReader(c *gc.C) {
 		`"Started":"2014-09-09T11:59:34Z",` +
 		`"Finished":"2014-09-09T12:00:34Z",` +
 		`"Notes":"",` +
+		`"KeyID":"0123456789abcdef",` +
+		`"Scheduled":true,` +
 		`"Environment":"asdf-zxcv-qwe",` +
 		`"Machine":"0",` +
 		`"Hostname":"myhost",` +
@@ -85,6 +89,8 @@ func (s *metadataSuite) TestNewMetadataJSONReader(c *gc.C) {
 	c.Check(meta.Started.Unix(), gc.Equals, int64(1410263974))
 	c.Check(meta.Finished.Unix(), gc.Equals, int64(1410264034))
 	c.Check(meta.Notes, gc.Equals, "")
+	c.Check(meta.KeyID, gc.Equals, "0123456789abcdef")
+	c.Check(meta.Scheduled, jc.IsTrue)
 	c.Check(meta.Origin.Environment, gc.Equals, "asdf-zxcv-qwe")
 	c.Check(meta.Origin.Machine, gc.Equals, "0")
 	c.Check(meta.Origin.Hostname, gc.Equals, "myhost")
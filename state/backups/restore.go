@@ -0,0 +1,337 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"text/template"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/juju/paths"
+	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/service"
+	"github.com/juju/juju/service/common"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/utils/ssh"
+)
+
+var (
+	runRestore     = restore
+	getDBRestorer  = NewDBRestorer
+	filesystemRoot = func() string { return string(os.PathSeparator) }
+	mongoService   = func() service.Service {
+		return service.NewService(mongo.ServiceName(""), common.Conf{})
+	}
+	openState = func(info *mongo.MongoInfo) (*state.State, error) {
+		return state.Open(info, mongo.DefaultDialOpts(), nil)
+	}
+	runViaSSH = sshScript
+)
+
+// openStateAttempt is used to wait for the restored database server
+// to come up.
+var openStateAttempt = utils.AttemptStrategy{
+	Total: 2 * time.Minute,
+	Delay: 5 * time.Second,
+}
+
+// RestoreArgs holds the details of the state server that a backup is
+// restored onto.
+type RestoreArgs struct {
+	// MachineId is the id of the state server's machine. It must be
+	// the one the backup was taken on.
+	MachineId string
+	// InstanceId is the id of the instance the machine runs on, which
+	// may differ from the one in the backup if the state server was
+	// provisioned afresh.
+	InstanceId instance.Id
+	// PrivateAddress and PublicAddress are the state server's
+	// addresses.
+	PrivateAddress string
+	PublicAddress  string
+	// EncryptionKey is the key the backup is encrypted with, if it
+	// is encrypted.
+	EncryptionKey []byte
+}
+
+// partialRestoreError is returned by restore when it fails after the
+// database has been replaced, leaving the state server with the
+// backup's database but not with all of its files and settings.
+type partialRestoreError struct {
+	error
+}
+
+// IsPartialRestore reports whether the error was returned by a restore
+// that failed after replacing the database.
+func IsPartialRestore(err error) bool {
+	_, ok := errors.Cause(err).(*partialRestoreError)
+	return ok
+}
+
+// restore replaces the state and configuration of the state server
+// with the ones in the given backup archive, then points all the
+// other machine agents to the state server. The state server's
+// agent must be restarted afterwards to pick up the changes.
+func restore(archive io.Reader, paths *Paths, args RestoreArgs) (err error) {
+	workspace, err := NewArchiveWorkspaceReader(archive)
+	if err != nil {
+		return errors.Annotate(err, "while unpacking backup archive")
+	}
+	defer workspace.Close()
+
+	// Replace the database.
+	logger.Infof("restoring databases")
+	restorer, err := getDBRestorer(filepath.Join(paths.DataDir, "db"))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	svc := mongoService()
+	if err := svc.Stop(); err != nil {
+		return errors.Annotate(err, "cannot stop mongo")
+	}
+	// Once mongo is stopped, it must be started again even if the
+	// databases cannot be restored, so that the state server is left
+	// running on its previous databases.
+	mongoStarted := false
+	defer func() {
+		if mongoStarted {
+			return
+		}
+		if err := svc.Start(); err != nil {
+			logger.Errorf("cannot restart mongo after failed restore: %v", err)
+		}
+	}()
+	if err := restorer.Restore(workspace.DBDumpDir); err != nil {
+		return errors.Trace(err)
+	}
+	// From here on the database is the backup's, so a failure leaves
+	// the state server only partially restored.
+	defer func() {
+		if err != nil {
+			err = &partialRestoreError{err}
+		}
+	}()
+	mongoStarted = true
+	if err := svc.Start(); err != nil {
+		return errors.Annotate(err, "cannot start mongo")
+	}
+
+	// Replace the agent configuration, certificates, keys and tools.
+	logger.Infof("restoring files")
+	if err := workspace.UnpackFilesBundle(filesystemRoot()); err != nil {
+		return errors.Annotate(err, "cannot restore files")
+	}
+
+	// The restored agent configuration holds the credentials needed
+	// to connect to the restored database.
+	tag := names.NewMachineTag(args.MachineId)
+	conf, err := agent.ReadConfig(agent.ConfigPath(paths.DataDir, tag))
+	if err != nil {
+		return errors.Annotate(err, "cannot read restored agent configuration")
+	}
+	servingInfo, ok := conf.StateServingInfo()
+	if !ok {
+		return errors.Errorf("restored agent configuration of %s has no state serving info", tag)
+	}
+	apiHostPorts := [][]network.HostPort{
+		network.AddressesWithPort(network.NewAddresses(args.PrivateAddress), servingInfo.APIPort),
+	}
+	conf.SetAPIHostPorts(apiHostPorts)
+	if err := conf.Write(); err != nil {
+		return errors.Annotate(err, "cannot update restored agent configuration")
+	}
+	mongoInfo, ok := conf.MongoInfo()
+	if !ok {
+		return errors.Errorf("restored agent configuration of %s has no mongo info", tag)
+	}
+
+	st, err := openRestoredState(mongoInfo)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer st.Close()
+
+	logger.Infof("updating state server records")
+	if err := updateStateServer(st, args, apiHostPorts); err != nil {
+		return errors.Trace(err)
+	}
+
+	logger.Infof("updating machine agents")
+	identity := filepath.Join(paths.DataDir, agent.SystemIdentity)
+	if err := updateAllMachines(st, args, identity); err != nil {
+		return errors.Trace(err)
+	}
+
+	rinfo, err := st.EnsureRestoreInfo()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(rinfo.SetStatus(state.RestoreFinished))
+}
+
+// openRestoredState connects to the restored database, waiting for
+// its server to come up.
+func openRestoredState(info *mongo.MongoInfo) (st *state.State, err error) {
+	for a := openStateAttempt.Start(); a.Next(); {
+		st, err = openState(info)
+		if err == nil {
+			return st, nil
+		}
+		logger.Debugf("restored database not ready yet: %v", err)
+	}
+	return nil, errors.Annotate(err, "cannot connect to restored database")
+}
+
+// updateStateServer records the state server's new instance and
+// addresses in the restored state.
+func updateStateServer(st *state.State, args RestoreArgs, apiHostPorts [][]network.HostPort) error {
+	if err := st.ResetStateServer(args.MachineId, args.InstanceId); err != nil {
+		return errors.Trace(err)
+	}
+	machine, err := st.Machine(args.MachineId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	addrs := []network.Address{
+		network.NewAddress(args.PrivateAddress, network.ScopeCloudLocal),
+	}
+	if args.PublicAddress != "" && args.PublicAddress != args.PrivateAddress {
+		addrs = append(addrs, network.NewAddress(args.PublicAddress, network.ScopePublic))
+	}
+	if err := machine.SetAddresses(addrs...); err != nil {
+		return errors.Annotate(err, "cannot update state server addresses")
+	}
+	if err := st.SetAPIHostPorts(apiHostPorts); err != nil {
+		return errors.Annotate(err, "cannot update API addresses")
+	}
+	return nil
+}
+
+// updateAllMachines connects over SSH to all the machines in the
+// environment, other than the state server, and points their agents
+// to the state server. All machines are tried even if some fail.
+func updateAllMachines(st *state.State, args RestoreArgs, identity string) error {
+	machines, err := st.AllMachines()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	done := make(chan error)
+	pending := 0
+	for _, machine := range machines {
+		if machine.Id() == args.MachineId || machine.IsManager() || machine.Life() == state.Dead {
+			continue
+		}
+		addr := network.SelectInternalAddress(machine.Addresses(), false)
+		if addr == "" {
+			logger.Warningf("cannot update machine %s: no address", machine.Id())
+			continue
+		}
+		script, err := setAgentAddressScript(machine.Series(), args.PrivateAddress)
+		if err != nil {
+			logger.Errorf("cannot update machine %s: %v", machine.Id(), err)
+			continue
+		}
+		pending++
+		go func(id, addr, script string) {
+			err := runViaSSH(addr, script, identity)
+			if err != nil {
+				logger.Errorf("failed to update machine %s: %v", id, err)
+				err = errors.Annotatef(err, "machine %s", id)
+			} else {
+				logger.Infof("updated machine %s", id)
+			}
+			done <- err
+		}(machine.Id(), addr, script)
+	}
+	var firstErr error
+	for ; pending > 0; pending-- {
+		if err := <-done; err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return errors.Annotate(firstErr, "cannot update all machines")
+}
+
+var agentAddressTemplate = template.Must(template.New("").Parse(`
+set -exu
+cd {{.AgentsDir}}
+for agent in *
+do
+	{{.StopCommand}}
+	sed -i.old -r "/^(stateaddresses|apiaddresses):/{
+		n
+		s/- .*(:[0-9]+)/- {{.Address}}\1/
+	}" $agent/agent.conf
+
+	# If we're processing a unit agent's directly
+	# and it has some relations, reset
+	# the stored version of all of them to
+	# ensure that any relation hooks will
+	# fire.
+	if [[ $agent = unit-* ]]
+	then
+		find $agent/state/relations -type f -exec sed -i -r 's/change-version: [0-9]+$/change-version: 0/' {} \;
+	fi
+	{{.StartCommand}}
+done
+`))
+
+// setAgentAddressScript returns a script that points all the agents
+// on a machine running the given series to the state server at the
+// given address, which does not include a port. The agents' directory
+// and init system are those used by the series.
+func setAgentAddressScript(series, addr string) (string, error) {
+	dataDir, err := paths.DataDir(series)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	// The agents' services are named after the agent directories,
+	// which the script loops over as $agent.
+	initSystem := service.SeriesInitSystem(series)
+	stopCommand, err := service.StopCommand(initSystem, "jujud-$agent")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	startCommand, err := service.StartCommand(initSystem, "jujud-$agent")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	var buf bytes.Buffer
+	err = agentAddressTemplate.Execute(&buf, struct {
+		AgentsDir    string
+		StopCommand  string
+		StartCommand string
+		Address      string
+	}{path.Join(dataDir, "agents"), stopCommand, startCommand, addr})
+	if err != nil {
+		panic(errors.Annotate(err, "template error"))
+	}
+	return buf.String(), nil
+}
+
+// sshScript runs the given script as root on the machine at the given
+// address, authenticating with the given identity file.
+func sshScript(addr, script, identity string) error {
+	var options ssh.Options
+	options.SetIdentities(identity)
+	userAddr := "ubuntu@" + addr
+	cmd := ssh.Command(userAddr, []string{"sudo", "-n", "bash", "-c " + utils.ShQuote(script)}, &options)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return errors.Annotatef(err, "ssh command failed: %q", stderr.String())
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/service"
+	"github.com/juju/juju/state/backups"
+	backupstesting "github.com/juju/juju/state/backups/testing"
+	"github.com/juju/juju/testing"
+)
+
+type restoreSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&restoreSuite{}) // Register the suite.
+
+// fakeService records the calls made to start and stop it.
+type fakeService struct {
+	service.Service
+	calls []string
+}
+
+func (s *fakeService) Start() error {
+	s.calls = append(s.calls, "Start")
+	return nil
+}
+
+func (s *fakeService) Stop() error {
+	s.calls = append(s.calls, "Stop")
+	return nil
+}
+
+type fakeRestorer struct {
+	err error
+}
+
+func (r *fakeRestorer) Restore(dumpDir string) error {
+	return r.err
+}
+
+func (s *restoreSuite) TestRestoreRestartsMongoOnFailure(c *gc.C) {
+	svc := &fakeService{}
+	s.PatchValue(backups.MongoService, func() service.Service {
+		return svc
+	})
+	s.PatchValue(backups.GetDBRestorer, func(string) (backups.DBRestorer, error) {
+		return &fakeRestorer{err: errors.New("failed!")}, nil
+	})
+	archive, err := backupstesting.NewArchiveBasic(backupstesting.NewMetadata())
+	c.Assert(err, jc.ErrorIsNil)
+
+	paths := backups.Paths{DataDir: c.MkDir()}
+	err = backups.Restore(archive, &paths, backups.RestoreArgs{MachineId: "0"})
+	c.Assert(err, gc.ErrorMatches, "failed!")
+	c.Check(svc.calls, jc.DeepEquals, []string{"Stop", "Start"})
+	c.Check(backups.IsPartialRestore(err), jc.IsFalse)
+}
+
+func (s *restoreSuite) TestRestorePartialAfterDatabaseReplaced(c *gc.C) {
+	svc := &fakeService{}
+	s.PatchValue(backups.MongoService, func() service.Service {
+		return svc
+	})
+	s.PatchValue(backups.GetDBRestorer, func(string) (backups.DBRestorer, error) {
+		return &fakeRestorer{}, nil
+	})
+	root := c.MkDir()
+	s.PatchValue(backups.FilesystemRoot, func() string { return root })
+	archive, err := backupstesting.NewArchiveBasic(backupstesting.NewMetadata())
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The database is replaced, but there is no agent configuration
+	// to go with it.
+	paths := backups.Paths{DataDir: c.MkDir()}
+	err = backups.Restore(archive, &paths, backups.RestoreArgs{MachineId: "0"})
+	c.Assert(err, gc.ErrorMatches, "cannot read restored agent configuration: .*")
+	c.Check(backups.IsPartialRestore(err), jc.IsTrue)
+	c.Check(svc.calls, jc.DeepEquals, []string{"Stop", "Start"})
+}
+
+func (s *restoreSuite) TestAgentAddressScript(c *gc.C) {
+	for i, test := range []struct {
+		series string
+		stop   string
+		start  string
+	}{{
+		series: "trusty",
+		stop:   "initctl stop jujud-$agent",
+		start:  "initctl start jujud-$agent",
+	}, {
+		series: "vivid",
+		stop:   "systemctl stop jujud-$agent.service",
+		start:  "systemctl start jujud-$agent.service",
+	}} {
+		c.Logf("test %d: %s", i, test.series)
+		script, err := backups.AgentAddressScript(test.series, "10.0.0.1")
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(script, jc.Contains, "cd /var/lib/juju/agents\n")
+		c.Check(script, jc.Contains, "\t"+test.stop+"\n")
+		c.Check(script, jc.Contains, "\t"+test.start+"\n")
+		c.Check(script, jc.Contains, "s/- .*(:[0-9]+)/- 10.0.0.1\\1/")
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ScheduledNotes holds the notes of the backups created on schedule.
+const ScheduledNotes = "scheduled backup"
+
+// RetentionPolicy determines which scheduled backups are kept. A
+// backup is kept if it is one of the KeepLast most recent backups,
+// or the most recent backup of one of the KeepDaily most recent days
+// with backups, or the most recent backup of one of the KeepWeekly
+// most recent weeks with backups. Days and weeks are in UTC, and
+// weeks start on Monday.
+//
+// The zero policy keeps all backups.
+type RetentionPolicy struct {
+	KeepLast   int
+	KeepDaily  int
+	KeepWeekly int
+}
+
+// IsZero reports whether the policy keeps all backups.
+func (p RetentionPolicy) IsZero() bool {
+	return p.KeepLast == 0 && p.KeepDaily == 0 && p.KeepWeekly == 0
+}
+
+// Expired returns the scheduled backups, among the given ones, that
+// are not kept by the policy, most recent first.
+func (p RetentionPolicy) Expired(metas []*Metadata) []*Metadata {
+	if p.IsZero() {
+		return nil
+	}
+	var scheduled []*Metadata
+	for _, meta := range metas {
+		if meta.Scheduled {
+			scheduled = append(scheduled, meta)
+		}
+	}
+	sort.Sort(newestFirst(scheduled))
+
+	keep := make(map[*Metadata]bool)
+	for i, meta := range scheduled {
+		if i < p.KeepLast {
+			keep[meta] = true
+		}
+	}
+	keepNewestPer(scheduled, p.KeepDaily, keep, func(meta *Metadata) string {
+		return meta.Started.UTC().Format("2006-01-02")
+	})
+	keepNewestPer(scheduled, p.KeepWeekly, keep, func(meta *Metadata) string {
+		year, week := meta.Started.UTC().ISOWeek()
+		return fmt.Sprintf("%d-%d", year, week)
+	})
+
+	var expired []*Metadata
+	for _, meta := range scheduled {
+		if !keep[meta] {
+			expired = append(expired, meta)
+		}
+	}
+	return expired
+}
+
+// keepNewestPer marks as kept the newest of the given backups, which
+// must be ordered newest first, in each of the count most recent
+// periods returned by period.
+func keepNewestPer(metas []*Metadata, count int, keep map[*Metadata]bool, period func(*Metadata) string) {
+	seen := make(map[string]bool)
+	for _, meta := range metas {
+		if len(seen) == count {
+			return
+		}
+		p := period(meta)
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		keep[meta] = true
+	}
+}
+
+type newestFirst []*Metadata
+
+func (m newestFirst) Len() int           { return len(m) }
+func (m newestFirst) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
+func (m newestFirst) Less(i, j int) bool { return m[i].Started.After(m[j].Started) }
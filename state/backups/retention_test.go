@@ -0,0 +1,79 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/backups"
+)
+
+type retentionSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&retentionSuite{})
+
+// newScheduled returns the metadata of a scheduled backup with the
+// given id, started at the given number of hours after midnight on
+// Monday, 2014-11-03.
+func newScheduled(id string, hours int) *backups.Metadata {
+	meta := backups.NewMetadata()
+	meta.SetID(id)
+	meta.Started = time.Date(2014, 11, 3, 0, 0, 0, 0, time.UTC).Add(time.Duration(hours) * time.Hour)
+	meta.Scheduled = true
+	return meta
+}
+
+func ids(metas []*backups.Metadata) []string {
+	var result []string
+	for _, meta := range metas {
+		result = append(result, meta.ID())
+	}
+	return result
+}
+
+func (s *retentionSuite) TestExpired(c *gc.C) {
+	manual := newScheduled("manual", 0)
+	manual.Scheduled = false
+	// Notes do not make a backup scheduled.
+	manual.Notes = backups.ScheduledNotes
+	metas := []*backups.Metadata{
+		manual,
+		newScheduled("mon-03", 3),
+		newScheduled("mon-15", 15),
+		newScheduled("tue-03", 24+3),
+		newScheduled("wed-03", 2*24+3),
+		newScheduled("next-mon-03", 7*24+3),
+		newScheduled("next-tue-03", 8*24+3),
+	}
+	for i, test := range []struct {
+		policy   backups.RetentionPolicy
+		expected []string
+	}{{
+		policy: backups.RetentionPolicy{},
+	}, {
+		policy:   backups.RetentionPolicy{KeepLast: 2},
+		expected: []string{"wed-03", "tue-03", "mon-15", "mon-03"},
+	}, {
+		policy:   backups.RetentionPolicy{KeepDaily: 3},
+		expected: []string{"tue-03", "mon-15", "mon-03"},
+	}, {
+		policy:   backups.RetentionPolicy{KeepWeekly: 2},
+		expected: []string{"next-mon-03", "tue-03", "mon-15", "mon-03"},
+	}, {
+		policy:   backups.RetentionPolicy{KeepLast: 1, KeepDaily: 2, KeepWeekly: 2},
+		expected: []string{"tue-03", "mon-15", "mon-03"},
+	}, {
+		policy:   backups.RetentionPolicy{KeepDaily: 10},
+		expected: []string{"mon-03"},
+	}} {
+		c.Logf("test %d: %+v", i, test.policy)
+		c.Check(ids(test.policy.Expired(metas)), jc.DeepEquals, test.expected)
+	}
+}
@@ -41,9 +41,19 @@ type storageMetaDoc struct {
 
 	// backup
 
-	Started  int64  `bson:"started,minsize"`
-	Finished int64  `bson:"finished,minsize"`
-	Notes    string `bson:"notes,omitempty"`
+	Started   int64  `bson:"started,minsize"`
+	Finished  int64  `bson:"finished,minsize"`
+	Notes     string `bson:"notes,omitempty"`
+	KeyID     string `bson:"keyid,omitempty"`
+	Scheduled bool   `bson:"scheduled,omitempty"`
+
+	// archive location, if not the state database
+
+	Target           string `bson:"target,omitempty"`
+	TargetDir        string `bson:"targetdir,omitempty"`
+	TargetS3Endpoint string `bson:"targets3endpoint,omitempty"`
+	TargetS3Bucket   string `bson:"targets3bucket,omitempty"`
+	Location         string `bson:"location,omitempty"`
 
 	// origin
 
@@ -53,6 +63,16 @@ type storageMetaDoc struct {
 	Version     version.Number `bson:"version"`
 }
 
+// targetSpec returns the spec of the target the archive is stored in.
+func (doc *storageMetaDoc) targetSpec() TargetSpec {
+	return TargetSpec{
+		Name:       doc.Target,
+		Dir:        doc.TargetDir,
+		S3Endpoint: doc.TargetS3Endpoint,
+		S3Bucket:   doc.TargetS3Bucket,
+	}
+}
+
 func (doc *storageMetaDoc) isFileInfoComplete() bool {
 	if doc.Checksum == "" {
 		return false
@@ -120,6 +140,8 @@ func docAsMetadata(doc *storageMetaDoc) *Metadata {
 	meta := NewMetadata()
 	meta.Started = metadocUnixToTime(doc.Started)
 	meta.Notes = doc.Notes
+	meta.KeyID = doc.KeyID
+	meta.Scheduled = doc.Scheduled
 
 	meta.Origin.Environment = doc.Environment
 	meta.Origin.Machine = doc.Machine
@@ -170,6 +192,8 @@ func newStorageMetaDoc(meta *Metadata) storageMetaDoc {
 		doc.Finished = metadocTimeToUnix(*meta.Finished)
 	}
 	doc.Notes = meta.Notes
+	doc.KeyID = meta.KeyID
+	doc.Scheduled = meta.Scheduled
 
 	doc.Environment = meta.Origin.Environment
 	doc.Machine = meta.Origin.Machine
@@ -367,6 +391,27 @@ func setStorageStoredTime(dbWrap *storageDBWrapper, id string, stored time.Time)
 	return nil
 }
 
+// setStorageTarget updates the backup metadata associated with "id"
+// to record the target and location its archive is stored at. If "id"
+// does not match any stored records, an error satisfying
+// juju/errors.IsNotFound() is returned.
+func setStorageTarget(dbWrap *storageDBWrapper, id string, spec TargetSpec, location string) error {
+	op := dbWrap.txnOpUpdate(id,
+		bson.DocElem{"target", spec.Name},
+		bson.DocElem{"targetdir", spec.Dir},
+		bson.DocElem{"targets3endpoint", spec.S3Endpoint},
+		bson.DocElem{"targets3bucket", spec.S3Bucket},
+		bson.DocElem{"location", location},
+	)
+	if err := dbWrap.runTransaction([]txn.Op{op}); err != nil {
+		if errors.Cause(err) == txn.ErrAborted {
+			return errors.NotFoundf("backup metadata %q", id)
+		}
+		return errors.Annotate(err, "while running transaction")
+	}
+	return nil
+}
+
 //---------------------------
 // metadata storage
 
@@ -534,14 +579,7 @@ type DB interface {
 }
 
 // NewStorage returns a new FileStorage to use for storing backup
-// archives (and metadata).
+// archives (and metadata) in the state database.
 func NewStorage(st DB) filestorage.FileStorage {
-	envUUID := st.EnvironTag().Id()
-	db := st.MongoSession().DB(storageDBName)
-	dbWrap := newStorageDBWrapper(db, storageMetaName, envUUID)
-	defer dbWrap.Close()
-
-	files := newFileStorage(dbWrap, backupStorageRoot)
-	docs := newMetadataStorage(dbWrap)
-	return filestorage.NewFileStorage(docs, files)
+	return NewTargetStorage(st, nil)
 }
@@ -4,6 +4,11 @@
 package backups_test
 
 import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
@@ -11,6 +16,8 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/filestorage"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/backups"
 	statetesting "github.com/juju/juju/state/testing"
@@ -55,6 +62,8 @@ func (s *storageSuite) metadata(c *gc.C) *backups.Metadata {
 	meta.Origin.Environment = s.State.EnvironUUID()
 	meta.Origin.Machine = "0"
 	meta.Origin.Hostname = "localhost"
+	meta.KeyID = "0123456789abcdef"
+	meta.Scheduled = true
 	err := meta.MarkComplete(int64(42), "some hash")
 	c.Assert(err, jc.ErrorIsNil)
 	return meta
@@ -66,6 +75,8 @@ func (s *storageSuite) checkMeta(c *gc.C, meta, expected *backups.Metadata, id s
 		c.Check(meta.ID(), gc.Equals, id)
 	}
 	c.Check(meta.Notes, gc.Equals, expected.Notes)
+	c.Check(meta.KeyID, gc.Equals, expected.KeyID)
+	c.Check(meta.Scheduled, gc.Equals, expected.Scheduled)
 	c.Check(meta.Started.Unix(), gc.Equals, expected.Started.Unix())
 	c.Check(meta.Checksum(), gc.Equals, expected.Checksum())
 	c.Check(meta.ChecksumFormat(), gc.Equals, expected.ChecksumFormat())
@@ -166,3 +177,129 @@ func (s *storageSuite) TestSetBackupStoredTimeNotFound(c *gc.C) {
 
 	c.Check(err, jc.Satisfies, errors.IsNotFound)
 }
+
+// fakeTargets provides a single local target.
+type fakeTargets struct {
+	dflt string
+	dir  string
+}
+
+func (t *fakeTargets) Default() (backups.TargetSpec, error) {
+	return backups.TargetSpec{Name: t.dflt, Dir: t.dir}, nil
+}
+
+func (t *fakeTargets) Open(spec backups.TargetSpec) (backups.Target, error) {
+	if spec.Name != config.BackupsStorageLocal {
+		return nil, errors.NotFoundf("target %q", spec.Name)
+	}
+	return filestorage.NewFileStorageWriter(spec.Dir)
+}
+
+// archiveData is the content of the archives added by the target
+// storage tests, which matches the size in s.metadata().
+var archiveData = strings.Repeat("x", 42)
+
+func (s *storageSuite) addArchive(c *gc.C, targets backups.Targets, meta *backups.Metadata) string {
+	stor := backups.NewTargetStorage(s.State, targets)
+	defer stor.Close()
+	id, err := stor.Add(meta, bytes.NewBufferString(archiveData))
+	c.Assert(err, jc.ErrorIsNil)
+	return id
+}
+
+func (s *storageSuite) checkArchive(c *gc.C, targets backups.Targets, id string) {
+	stor := backups.NewTargetStorage(s.State, targets)
+	defer stor.Close()
+	_, archive, err := stor.Get(id)
+	c.Assert(err, jc.ErrorIsNil)
+	defer archive.Close()
+	data, err := ioutil.ReadAll(archive)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, archiveData)
+}
+
+func (s *storageSuite) TestTargetStorage(c *gc.C) {
+	targets := &fakeTargets{config.BackupsStorageLocal, c.MkDir()}
+	id := s.addArchive(c, targets, s.metadata(c))
+
+	filename := filepath.Join(targets.dir, "backups", id+".tar.gz")
+	data, err := ioutil.ReadFile(filename)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, archiveData)
+	s.checkArchive(c, targets, id)
+
+	stor := backups.NewTargetStorage(s.State, targets)
+	defer stor.Close()
+	err = stor.Remove(id)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = os.Stat(filename)
+	c.Check(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *storageSuite) TestTargetStorageState(c *gc.C) {
+	targets := &fakeTargets{config.BackupsStorageState, c.MkDir()}
+	id := s.addArchive(c, targets, s.metadata(c))
+
+	_, err := os.Stat(filepath.Join(targets.dir, "backups"))
+	c.Check(err, jc.Satisfies, os.IsNotExist)
+	s.checkArchive(c, targets, id)
+}
+
+func (s *storageSuite) TestTargetStorageDefaultChanged(c *gc.C) {
+	targets := &fakeTargets{config.BackupsStorageState, c.MkDir()}
+	meta := s.metadata(c)
+	inState := s.addArchive(c, targets, meta)
+	targets.dflt = config.BackupsStorageLocal
+	meta = s.metadata(c)
+	meta.Started = meta.Started.Add(time.Minute)
+	inLocal := s.addArchive(c, targets, meta)
+
+	// Each archive is still fetched from where it was stored.
+	s.checkArchive(c, targets, inState)
+	targets.dflt = config.BackupsStorageState
+	s.checkArchive(c, targets, inLocal)
+}
+
+func (s *storageSuite) TestTargetStorageLocationChanged(c *gc.C) {
+	targets := &fakeTargets{config.BackupsStorageLocal, c.MkDir()}
+	id := s.addArchive(c, targets, s.metadata(c))
+
+	// The archive is fetched from where it was stored, not from where
+	// the target is now.
+	targets.dir = c.MkDir()
+	s.checkArchive(c, targets, id)
+}
+
+func (s *storageSuite) TestConfiguredStorageConfigChanged(c *gc.C) {
+	dir := c.MkDir()
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"backups-storage":     "local",
+		"backups-storage-dir": dir,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	stor := backups.NewConfiguredStorage(s.State, nil)
+	defer stor.Close()
+	id, err := stor.Add(s.metadata(c), bytes.NewBufferString(archiveData))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.UpdateEnvironConfig(map[string]interface{}{
+		"backups-storage-dir": c.MkDir(),
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, archive, err := stor.Get(id)
+	c.Assert(err, jc.ErrorIsNil)
+	defer archive.Close()
+	data, err := ioutil.ReadAll(archive)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, archiveData)
+	c.Check(filepath.Join(dir, "backups", id+".tar.gz"), jc.IsNonEmptyFile)
+}
+
+func (s *storageSuite) TestTargetStorageUnavailable(c *gc.C) {
+	targets := &fakeTargets{config.BackupsStorageS3, c.MkDir()}
+	stor := backups.NewTargetStorage(s.State, targets)
+	defer stor.Close()
+	_, err := stor.Add(s.metadata(c), bytes.NewBufferString(archiveData))
+
+	c.Check(err, gc.ErrorMatches, `.*cannot open s3 backups storage: target "s3" not found`)
+}
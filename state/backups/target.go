@@ -0,0 +1,342 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"io"
+	"path"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/filestorage"
+	"launchpad.net/goamz/aws"
+	"launchpad.net/goamz/s3"
+
+	"github.com/juju/juju/environs/config"
+	envfilestorage "github.com/juju/juju/environs/filestorage"
+	"github.com/juju/juju/environs/storage"
+)
+
+// Target is a place outside the state database where backup archives
+// are stored. Each archive is stored under a location within the
+// target, which is recorded in its metadata. Any
+// environs/storage.Storage is a Target.
+type Target interface {
+	// Get returns the archive stored at the given location.
+	Get(location string) (io.ReadCloser, error)
+
+	// Put stores the archive at the given location.
+	Put(location string, archive io.Reader, size int64) error
+
+	// Remove removes the archive stored at the given location.
+	Remove(location string) error
+}
+
+// TargetSpec describes a target: its name, one of the
+// config.BackupsStorage* values, and where it is. The spec of the
+// target an archive is stored in is recorded in its metadata, so the
+// archive can still be found after the configuration changes.
+type TargetSpec struct {
+	Name string
+
+	// Dir is the directory of a local target.
+	Dir string
+
+	// S3Endpoint and S3Bucket locate an S3 target.
+	S3Endpoint string
+	S3Bucket   string
+}
+
+// Targets gives access to the targets backup archives are stored in.
+// The state database is not a Target and is never opened through
+// Targets.
+type Targets interface {
+	// Default returns the spec of the target new archives are stored
+	// in.
+	Default() (TargetSpec, error)
+
+	// Open returns the target with the given spec.
+	Open(spec TargetSpec) (Target, error)
+}
+
+// NewTargetStorage returns a new FileStorage that keeps backup
+// metadata in the state database and stores new archives in the
+// default target. Archives are fetched and removed from wherever their
+// metadata says they were stored, so changing the default target does
+// not lose access to existing archives.
+func NewTargetStorage(st DB, targets Targets) filestorage.FileStorage {
+	envUUID := st.EnvironTag().Id()
+	db := st.MongoSession().DB(storageDBName)
+	dbWrap := newStorageDBWrapper(db, storageMetaName, envUUID)
+	defer dbWrap.Close()
+
+	files := newFileStorage(dbWrap, backupStorageRoot)
+	if targets != nil {
+		files = newTargetFileStorage(dbWrap, files, targets)
+	}
+	docs := newMetadataStorage(dbWrap)
+	return filestorage.NewFileStorage(docs, files)
+}
+
+// ConfiguredDB is a DB that also exposes the environment
+// configuration.
+type ConfiguredDB interface {
+	DB
+
+	// EnvironConfig returns the current environment configuration.
+	EnvironConfig() (*config.Config, error)
+}
+
+// ProviderStorageFunc returns the provider storage of the environment
+// described by the given configuration. This package does not know
+// about providers, so the provider target is supplied by its callers.
+type ProviderStorageFunc func(cfg *config.Config) (storage.Storage, error)
+
+// NewConfiguredStorage returns a new FileStorage that stores new
+// backup archives in the storage selected by the environment
+// configuration. The provider target is opened with providerStorage,
+// which may be nil if it is not available. See NewTargetStorage.
+func NewConfiguredStorage(st ConfiguredDB, providerStorage ProviderStorageFunc) filestorage.FileStorage {
+	return NewTargetStorage(st, &configTargets{st, providerStorage})
+}
+
+// configTargets provides the targets described by the environment
+// configuration.
+type configTargets struct {
+	st              ConfiguredDB
+	providerStorage ProviderStorageFunc
+}
+
+// Default implements Targets.
+func (t *configTargets) Default() (TargetSpec, error) {
+	cfg, err := t.st.EnvironConfig()
+	if err != nil {
+		return TargetSpec{}, errors.Annotate(err, "cannot get environment config")
+	}
+	return configTargetSpec(cfg), nil
+}
+
+// Open implements Targets. The target is found where the spec says;
+// only the credentials are taken from the current configuration.
+func (t *configTargets) Open(spec TargetSpec) (Target, error) {
+	cfg, err := t.st.EnvironConfig()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get environment config")
+	}
+	return openTarget(cfg, spec, t.providerStorage)
+}
+
+// configTargetSpec returns the spec of the default target described
+// by the given environment configuration.
+func configTargetSpec(cfg *config.Config) TargetSpec {
+	spec := TargetSpec{Name: cfg.BackupsStorage()}
+	switch spec.Name {
+	case config.BackupsStorageLocal:
+		spec.Dir, _ = cfg.BackupsStorageDir()
+	case config.BackupsStorageS3:
+		spec.S3Endpoint, _ = cfg.BackupsS3Endpoint()
+		spec.S3Bucket = cfg.BackupsS3Bucket()
+	}
+	return spec
+}
+
+// openTarget returns the target with the given spec. Any location
+// missing from the spec, as in the metadata of archives stored before
+// locations were recorded, is taken from the given environment
+// configuration.
+func openTarget(cfg *config.Config, spec TargetSpec, providerStorage ProviderStorageFunc) (Target, error) {
+	switch spec.Name {
+	case config.BackupsStorageProvider:
+		if providerStorage == nil {
+			return nil, errors.NotSupportedf("backups storage in %q provider", cfg.Type())
+		}
+		stor, err := providerStorage(cfg)
+		return stor, errors.Trace(err)
+	case config.BackupsStorageLocal:
+		dir := spec.Dir
+		if dir == "" {
+			dir, _ = cfg.BackupsStorageDir()
+		}
+		if dir == "" {
+			return nil, errors.NotFoundf("%s", config.BackupsStorageDirKey)
+		}
+		stor, err := envfilestorage.NewFileStorageWriter(dir)
+		return stor, errors.Trace(err)
+	case config.BackupsStorageS3:
+		endpoint, bucketName := spec.S3Endpoint, spec.S3Bucket
+		if endpoint == "" {
+			endpoint, _ = cfg.BackupsS3Endpoint()
+			bucketName = cfg.BackupsS3Bucket()
+		}
+		if endpoint == "" {
+			return nil, errors.NotFoundf("%s", config.BackupsS3EndpointKey)
+		}
+		auth := aws.Auth{
+			AccessKey: cfg.BackupsS3AccessKey(),
+			SecretKey: cfg.BackupsS3SecretKey(),
+		}
+		region := aws.Region{
+			Name:       "backups",
+			S3Endpoint: endpoint,
+			Sign:       aws.SignV2,
+		}
+		bucket := s3.New(auth, region).Bucket(bucketName)
+		return &s3Target{bucket: bucket}, nil
+	}
+	return nil, errors.NotValidf("backups storage %q", spec.Name)
+}
+
+// s3Target is a Target that stores archives in an S3 bucket.
+type s3Target struct {
+	sync.Mutex
+	madeBucket bool
+	bucket     *s3.Bucket
+}
+
+// makeBucket makes the bucket if it does not already exist. This is
+// done only once for each target.
+func (t *s3Target) makeBucket() error {
+	t.Lock()
+	defer t.Unlock()
+	if t.madeBucket {
+		return nil
+	}
+	// PutBucket returns 409 BucketAlreadyOwnedByYou on all but the
+	// original s3.amazonaws.com endpoint if the bucket exists.
+	err := t.bucket.PutBucket(s3.Private)
+	if s3Err, ok := err.(*s3.Error); ok && s3Err.Code == "BucketAlreadyOwnedByYou" {
+		err = nil
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	t.madeBucket = true
+	return nil
+}
+
+// Get implements Target.
+func (t *s3Target) Get(location string) (io.ReadCloser, error) {
+	archive, err := t.bucket.GetReader(location)
+	if s3Err, ok := err.(*s3.Error); ok && s3Err.StatusCode == 404 {
+		return nil, errors.NotFoundf("archive %q", location)
+	}
+	return archive, errors.Trace(err)
+}
+
+// Put implements Target.
+func (t *s3Target) Put(location string, archive io.Reader, size int64) error {
+	if err := t.makeBucket(); err != nil {
+		return errors.Annotate(err, "cannot make S3 backups bucket")
+	}
+	err := t.bucket.PutReader(location, archive, size, "binary/octet-stream", s3.Private)
+	return errors.Annotatef(err, "cannot write archive %q to S3 backups bucket", location)
+}
+
+// Remove implements Target.
+func (t *s3Target) Remove(location string) error {
+	return errors.Trace(t.bucket.Del(location))
+}
+
+// targetFileStorage is a RawFileStorage that stores archives in the
+// default target, recording where each one went in its metadata.
+// Archives without a recorded target are in the state database.
+type targetFileStorage struct {
+	dbWrap  *storageDBWrapper
+	state   filestorage.RawFileStorage
+	targets Targets
+}
+
+func newTargetFileStorage(dbWrap *storageDBWrapper, state filestorage.RawFileStorage, targets Targets) filestorage.RawFileStorage {
+	return &targetFileStorage{
+		dbWrap:  dbWrap.Copy(),
+		state:   state,
+		targets: targets,
+	}
+}
+
+// stored returns the target and location the identified archive is
+// stored at. A nil target means the archive is in the state database.
+func (s *targetFileStorage) stored(id string) (Target, string, error) {
+	dbWrap := s.dbWrap.Copy()
+	defer dbWrap.Close()
+
+	doc, err := getStorageMetadata(dbWrap, id)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	if doc.Target == "" || doc.Target == config.BackupsStorageState {
+		return nil, "", nil
+	}
+	target, err := s.targets.Open(doc.targetSpec())
+	if err != nil {
+		return nil, "", errors.Annotatef(err, "cannot open %s backups storage", doc.Target)
+	}
+	return target, doc.Location, nil
+}
+
+// File returns the identified file from storage.
+func (s *targetFileStorage) File(id string) (io.ReadCloser, error) {
+	target, location, err := s.stored(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if target == nil {
+		return s.state.File(id)
+	}
+	file, err := target.Get(location)
+	return file, errors.Trace(err)
+}
+
+// AddFile adds the file to the default target.
+func (s *targetFileStorage) AddFile(id string, file io.Reader, size int64) error {
+	spec, err := s.targets.Default()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if spec.Name == config.BackupsStorageState {
+		return s.state.AddFile(id, file, size)
+	}
+	target, err := s.targets.Open(spec)
+	if err != nil {
+		return errors.Annotatef(err, "cannot open %s backups storage", spec.Name)
+	}
+
+	// Use of path.Join instead of filepath.Join is intentional - this
+	// is a storage path not a filesystem path.
+	location := path.Join(backupStorageRoot, id+".tar.gz")
+	if err := target.Put(location, file, size); err != nil {
+		return errors.Annotatef(err, "cannot store archive in %s backups storage", spec.Name)
+	}
+
+	dbWrap := s.dbWrap.Copy()
+	defer dbWrap.Close()
+	if err := setStorageTarget(dbWrap, id, spec, location); err != nil {
+		if err := target.Remove(location); err != nil {
+			logger.Errorf("cannot remove archive of backup %q: %v", id, err)
+		}
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// RemoveFile removes the identified file from storage.
+func (s *targetFileStorage) RemoveFile(id string) error {
+	target, location, err := s.stored(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if target == nil {
+		return s.state.RemoveFile(id)
+	}
+	return errors.Trace(target.Remove(location))
+}
+
+// Close closes the storage.
+func (s *targetFileStorage) Close() error {
+	err := s.state.Close()
+	if closeErr := s.dbWrap.Close(); err == nil {
+		err = closeErr
+	}
+	return errors.Trace(err)
+}
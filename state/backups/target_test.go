@@ -0,0 +1,120 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"launchpad.net/goamz/s3/s3test"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/filestorage"
+	"github.com/juju/juju/environs/storage"
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/testing"
+)
+
+type targetSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&targetSuite{})
+
+// checkRoundTrip stores an archive in the target, reads it back and
+// removes it.
+func (s *targetSuite) checkRoundTrip(c *gc.C, target backups.Target) {
+	err := target.Put("backups/spam.tar.gz", bytes.NewBufferString("<archive>"), 9)
+	c.Assert(err, jc.ErrorIsNil)
+
+	archive, err := target.Get("backups/spam.tar.gz")
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadAll(archive)
+	archive.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, "<archive>")
+
+	err = target.Remove("backups/spam.tar.gz")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = target.Get("backups/spam.tar.gz")
+	c.Check(err, gc.NotNil)
+}
+
+func (s *targetSuite) TestOpenLocal(c *gc.C) {
+	cfg := testing.CustomEnvironConfig(c, testing.Attrs{
+		"backups-storage-dir": c.MkDir(),
+	})
+	target, err := backups.OpenTarget(cfg, backups.TargetSpec{Name: "local"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.checkRoundTrip(c, target)
+}
+
+func (s *targetSuite) TestOpenLocalRecordedDir(c *gc.C) {
+	// The recorded directory is used, not the configured one.
+	dir := c.MkDir()
+	cfg := testing.CustomEnvironConfig(c, testing.Attrs{
+		"backups-storage-dir": c.MkDir(),
+	})
+	target, err := backups.OpenTarget(cfg, backups.TargetSpec{Name: "local", Dir: dir}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = target.Put("backups/spam.tar.gz", bytes.NewBufferString("<archive>"), 9)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(filepath.Join(dir, "backups", "spam.tar.gz"), jc.IsNonEmptyFile)
+}
+
+func (s *targetSuite) TestOpenLocalNotConfigured(c *gc.C) {
+	cfg := testing.EnvironConfig(c)
+	_, err := backups.OpenTarget(cfg, backups.TargetSpec{Name: "local"}, nil)
+	c.Check(err, gc.ErrorMatches, "backups-storage-dir not found")
+}
+
+func (s *targetSuite) TestOpenS3(c *gc.C) {
+	srv, err := s3test.NewServer(&s3test.Config{})
+	c.Assert(err, jc.ErrorIsNil)
+	defer srv.Quit()
+
+	cfg := testing.CustomEnvironConfig(c, testing.Attrs{
+		"backups-s3-endpoint":   srv.URL(),
+		"backups-s3-bucket":     "juju-backups",
+		"backups-s3-access-key": "access",
+		"backups-s3-secret-key": "secret",
+	})
+	target, err := backups.OpenTarget(cfg, backups.TargetSpec{Name: "s3"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.checkRoundTrip(c, target)
+}
+
+func (s *targetSuite) TestOpenProvider(c *gc.C) {
+	cfg := testing.CustomEnvironConfig(c, testing.Attrs{
+		"backups-storage-dir": c.MkDir(),
+	})
+	var opened *config.Config
+	target, err := backups.OpenTarget(cfg, backups.TargetSpec{Name: "provider"}, func(cfg *config.Config) (storage.Storage, error) {
+		opened = cfg
+		dir, _ := cfg.BackupsStorageDir()
+		return filestorage.NewFileStorageWriter(dir)
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(opened, gc.Equals, cfg)
+
+	s.checkRoundTrip(c, target)
+}
+
+func (s *targetSuite) TestOpenProviderNotAvailable(c *gc.C) {
+	cfg := testing.EnvironConfig(c)
+	_, err := backups.OpenTarget(cfg, backups.TargetSpec{Name: "provider"}, nil)
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *targetSuite) TestOpenUnknown(c *gc.C) {
+	cfg := testing.EnvironConfig(c)
+	_, err := backups.OpenTarget(cfg, backups.TargetSpec{Name: "tape"}, nil)
+	c.Check(err, gc.ErrorMatches, `backups storage "tape" not valid`)
+}
@@ -37,18 +37,23 @@ type FakeBackups struct {
 	MetaArg *backups.Metadata
 	// ArchiveArg holds the backup archive that was passed in.
 	ArchiveArg io.Reader
+	// RestoreArgsArg holds the RestoreArgs that was passed in.
+	RestoreArgsArg backups.RestoreArgs
+	// KeyArg holds the encryption key that was passed in.
+	KeyArg []byte
 }
 
 var _ backups.Backups = (*FakeBackups)(nil)
 
 // Create creates and stores a new juju backup archive and returns
 // its associated metadata.
-func (b *FakeBackups) Create(meta *backups.Metadata, paths *backups.Paths, dbInfo *backups.DBInfo) error {
+func (b *FakeBackups) Create(meta *backups.Metadata, paths *backups.Paths, dbInfo *backups.DBInfo, key []byte) error {
 	b.Calls = append(b.Calls, "Create")
 
 	b.PathsArg = paths
 	b.DBInfoArg = dbInfo
 	b.MetaArg = meta
+	b.KeyArg = key
 
 	if b.Meta != nil {
 		*meta = *b.Meta
@@ -89,6 +94,15 @@ func (b *FakeBackups) Remove(id string) error {
 	return b.Error
 }
 
+// Restore restores the backup onto the state server.
+func (b *FakeBackups) Restore(id string, paths *backups.Paths, args backups.RestoreArgs) error {
+	b.Calls = append(b.Calls, "Restore")
+	b.IDArg = id
+	b.PathsArg = paths
+	b.RestoreArgsArg = args
+	return b.Error
+}
+
 // TODO(ericsnow) FakeStorage should probably move over to the utils repo.
 
 // FakeStorage is a FileStorage implementation to use when testing
@@ -0,0 +1,127 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/juju/errors"
+)
+
+// ChecksumHasher returns a new hash for computing backup archive
+// checksums in the default checksum format.
+func ChecksumHasher() hash.Hash {
+	return sha1.New()
+}
+
+// EncodeChecksum returns the checksum in the hasher, as recorded in
+// backup metadata.
+func EncodeChecksum(hasher hash.Hash) string {
+	return base64.StdEncoding.EncodeToString(hasher.Sum(nil))
+}
+
+// VerifyChecksum checks that the checksum matches the one in the
+// metadata.  Metadata without a checksum is not checked.
+func VerifyChecksum(meta *Metadata, checksum string) error {
+	expected := meta.Checksum()
+	if expected == "" {
+		return nil
+	}
+	if checksum != expected {
+		return errors.Errorf("checksum mismatch: expected %q, got %q", expected, checksum)
+	}
+	return nil
+}
+
+// tempArchive is a backup archive in a temporary file that is removed
+// when closed.
+type tempArchive struct {
+	*os.File
+}
+
+// Close implements io.Closer.
+func (t *tempArchive) Close() error {
+	closeErr := t.File.Close()
+	if err := os.Remove(t.Name()); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(closeErr)
+}
+
+func newTempArchive(r io.Reader, w io.Writer) (_ *tempArchive, err error) {
+	file, err := ioutil.TempFile("", tempPrefix)
+	if err != nil {
+		return nil, errors.Annotate(err, "while creating temp file")
+	}
+	archive := &tempArchive{file}
+	defer func() {
+		if err != nil {
+			archive.Close()
+		}
+	}()
+	target := io.Writer(file)
+	if w != nil {
+		target = io.MultiWriter(file, w)
+	}
+	if _, err := io.Copy(target, r); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, err := file.Seek(0, os.SEEK_SET); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return archive, nil
+}
+
+// openVerifiedArchive copies the stored archive to a temporary file,
+// checking it against the metadata's checksum, and decrypts it if it
+// is encrypted.  The returned archive must be closed to remove the
+// temporary files.
+func openVerifiedArchive(meta *Metadata, archive io.Reader, key []byte) (*tempArchive, error) {
+	if meta.Checksum() == "" {
+		logger.Warningf("backup %q has no checksum; not verifying it", meta.ID())
+	}
+
+	hasher := ChecksumHasher()
+	stored, err := newTempArchive(archive, hasher)
+	if err != nil {
+		return nil, errors.Annotate(err, "while reading backup archive")
+	}
+	if err := VerifyChecksum(meta, EncodeChecksum(hasher)); err != nil {
+		stored.Close()
+		return nil, errors.Trace(err)
+	}
+
+	// The metadata of uploaded archives may not say whether they are
+	// encrypted, so we check the archive itself.
+	keyID, err := ArchiveKeyID(stored)
+	if err == nil {
+		_, err = stored.Seek(0, os.SEEK_SET)
+	}
+	if err != nil {
+		stored.Close()
+		return nil, errors.Trace(err)
+	}
+	if keyID == "" {
+		return stored, nil
+	}
+	defer stored.Close()
+	if key == nil {
+		return nil, errors.Errorf("backup is encrypted with key %s; key not provided", keyID)
+	}
+
+	decrypter, err := NewDecryptingReader(stored, key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	plain, err := newTempArchive(decrypter, nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "while decrypting backup archive")
+	}
+	return plain, nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// backupsScheduleGlobalKey is the global key of the status of the
+// backups taken on schedule in an environment.
+const backupsScheduleGlobalKey = "backups-schedule"
+
+// SetBackupsScheduleStatus records the status of the backups taken
+// on schedule: StatusMaintenance while a backup is being taken, and
+// then StatusActive or StatusError, with the reason, depending on
+// whether it succeeded.
+func (st *State) SetBackupsScheduleStatus(status Status, info string, data map[string]interface{}) error {
+	switch status {
+	case StatusActive, StatusMaintenance:
+	case StatusError:
+		if info == "" {
+			return errors.Errorf("cannot set status %q without info", status)
+		}
+	default:
+		return errors.Errorf("cannot set invalid status %q", status)
+	}
+	doc := statusDoc{
+		EnvUUID:    st.EnvironUUID(),
+		Status:     status,
+		StatusInfo: info,
+		StatusData: data,
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		_, err := getStatus(st, backupsScheduleGlobalKey)
+		if errors.IsNotFound(err) {
+			return []txn.Op{createStatusOp(st, backupsScheduleGlobalKey, doc)}, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{updateStatusOp(st, backupsScheduleGlobalKey, doc)}, nil
+	}
+	if err := st.run(jujutxn.TransactionSource(buildTxn)); err != nil {
+		return errors.Annotate(err, "cannot set backups schedule status")
+	}
+	if err := recordStatusHistory(st, backupsScheduleGlobalKey, doc); err != nil {
+		logger.Warningf("%v", err)
+	}
+	return nil
+}
+
+// BackupsScheduleStatus returns the status of the backups taken on
+// schedule. It returns a NotFound error if no backup has been
+// scheduled yet.
+func (st *State) BackupsScheduleStatus() (Status, string, map[string]interface{}, error) {
+	doc, err := getStatus(st, backupsScheduleGlobalKey)
+	if err != nil {
+		return "", "", nil, errors.Trace(err)
+	}
+	return doc.Status, doc.StatusInfo, doc.StatusData, nil
+}
+
+// BackupsScheduleStatusHistory returns the recorded status
+// transitions of the backups taken on schedule that match the given
+// filter, newest first.
+func (st *State) BackupsScheduleStatusHistory(filter StatusHistoryFilter) ([]StatusInfo, error) {
+	return statusHistory(st, backupsScheduleGlobalKey, filter)
+}
@@ -0,0 +1,62 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type BackupsScheduleSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&BackupsScheduleSuite{})
+
+func (s *BackupsScheduleSuite) TestStatusInitiallyNotFound(c *gc.C) {
+	_, _, _, err := s.State.BackupsScheduleStatus()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *BackupsScheduleSuite) TestSetStatus(c *gc.C) {
+	err := s.State.SetBackupsScheduleStatus(state.StatusMaintenance, "creating backup", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetBackupsScheduleStatus(state.StatusError, "cannot create backup", map[string]interface{}{"id": "spam"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	status, info, data, err := s.State.BackupsScheduleStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(status, gc.Equals, state.StatusError)
+	c.Check(info, gc.Equals, "cannot create backup")
+	c.Check(data, jc.DeepEquals, map[string]interface{}{"id": "spam"})
+
+	err = s.State.SetBackupsScheduleStatus(state.StatusActive, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	status, info, data, err = s.State.BackupsScheduleStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(status, gc.Equals, state.StatusActive)
+	c.Check(info, gc.Equals, "")
+	c.Check(data, gc.HasLen, 0)
+
+	history, err := s.State.BackupsScheduleStatusHistory(state.StatusHistoryFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(statuses(history), jc.DeepEquals, []state.Status{
+		state.StatusActive,
+		state.StatusError,
+		state.StatusMaintenance,
+	})
+}
+
+func (s *BackupsScheduleSuite) TestSetInvalidStatus(c *gc.C) {
+	err := s.State.SetBackupsScheduleStatus(state.StatusStarted, "", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot set invalid status "started"`)
+	err = s.State.SetBackupsScheduleStatus(state.StatusError, "", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot set status "error" without info`)
+
+	_, _, _, err = s.State.BackupsScheduleStatus()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -22,6 +22,7 @@ const (
 	cleanupRemovedUnit                 cleanupKind = "removedUnit"
 	cleanupServicesForDyingEnvironment cleanupKind = "services"
 	cleanupForceDestroyedMachine       cleanupKind = "machine"
+	cleanupStatusHistory               cleanupKind = "statusHistory"
 )
 
 // cleanupDoc represents a potentially large set of documents that should be
@@ -84,6 +85,8 @@ func (st *State) Cleanup() error {
 			err = st.cleanupServicesForDyingEnvironment()
 		case cleanupForceDestroyedMachine:
 			err = st.cleanupForceDestroyedMachine(doc.Prefix)
+		case cleanupStatusHistory:
+			err = st.cleanupStatusHistory(doc.Prefix)
 		default:
 			err = fmt.Errorf("unknown cleanup kind %q", doc.Kind)
 		}
@@ -123,6 +126,19 @@ func (st *State) cleanupRelationSettings(prefix string) error {
 	return nil
 }
 
+// cleanupStatusHistory removes the status history of the entity with
+// the given global key. History entries are not written through
+// transactions, so they are removed here rather than in the entity's
+// removal transaction.
+func (st *State) cleanupStatusHistory(globalKey string) error {
+	history, closer := st.getCollection(statusesHistoryC)
+	defer closer()
+	if _, err := history.RemoveAll(bson.D{{"entityid", globalKey}}); err != nil {
+		return fmt.Errorf("cannot remove status history of %q: %v", globalKey, err)
+	}
+	return nil
+}
+
 // cleanupServicesForDyingEnvironment sets all services to Dying, if they are
 // not already Dying or Dead. It's expected to be used when an environment is
 // destroyed.
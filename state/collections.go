@@ -63,12 +63,14 @@ var multiEnvCollections = set.NewStrings(
 	actionNotificationsC,
 	actionsC,
 	annotationsC,
+	auditC,
 	blockDevicesC,
 	charmsC,
 	cleanupsC,
 	constraintsC,
 	containerRefsC,
 	instanceDataC,
+	logsC,
 	machinesC,
 	meterStatusC,
 	minUnitsC,
@@ -83,7 +85,10 @@ var multiEnvCollections = set.NewStrings(
 	servicesC,
 	settingsC,
 	settingsrefsC,
+	spacesC,
 	statusesC,
+	statusesHistoryC,
+	storageInstancesC,
 	subnetsC,
 	unitsC,
 )
@@ -27,6 +27,8 @@ type constraintsDoc struct {
 	Container    *instance.ContainerType
 	Tags         *[]string `bson:",omitempty"`
 	Networks     *[]string `bson:",omitempty"`
+	Spaces       *[]string `bson:",omitempty"`
+	Zones        *[]string `bson:",omitempty"`
 }
 
 func (doc constraintsDoc) value() constraints.Value {
@@ -40,6 +42,8 @@ func (doc constraintsDoc) value() constraints.Value {
 		Container:    doc.Container,
 		Tags:         doc.Tags,
 		Networks:     doc.Networks,
+		Spaces:       doc.Spaces,
+		Zones:        doc.Zones,
 	}
 }
 
@@ -55,6 +59,8 @@ func newConstraintsDoc(st *State, cons constraints.Value) constraintsDoc {
 		Container:    cons.Container,
 		Tags:         cons.Tags,
 		Networks:     cons.Networks,
+		Spaces:       cons.Spaces,
+		Zones:        cons.Zones,
 	}
 }
 
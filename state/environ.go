@@ -96,6 +96,58 @@ func (st *State) GetEnvironment(tag names.EnvironTag) (*Environment, error) {
 	return env, nil
 }
 
+// AllEnvironments returns all the environments in the system, including
+// the state server environment.
+func (st *State) AllEnvironments() ([]*Environment, error) {
+	environments, closer := st.getCollection(environmentsC)
+	defer closer()
+
+	var docs []environmentDoc
+	if err := environments.Find(nil).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get all environments")
+	}
+	return st.environmentsFromDocs(docs), nil
+}
+
+// EnvironmentsForUser returns the environments the given user has
+// access to.
+func (st *State) EnvironmentsForUser(user names.UserTag) ([]*Environment, error) {
+	envUsers, closer := st.getCollection(envUsersC)
+	defer closer()
+
+	var userDocs []envUserDoc
+	if err := envUsers.Find(bson.D{{"user", user.Username()}}).All(&userDocs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get environments for %q", user.Username())
+	}
+	uuids := make([]string, len(userDocs))
+	for i, doc := range userDocs {
+		uuids[i] = doc.EnvUUID
+	}
+
+	environments, closer := st.getCollection(environmentsC)
+	defer closer()
+
+	var docs []environmentDoc
+	if err := environments.Find(bson.D{{"_id", bson.D{{"$in", uuids}}}}).All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get environments for %q", user.Username())
+	}
+	return st.environmentsFromDocs(docs), nil
+}
+
+func (st *State) environmentsFromDocs(docs []environmentDoc) []*Environment {
+	result := make([]*Environment, len(docs))
+	for i, doc := range docs {
+		env := &Environment{st: st, doc: doc}
+		env.annotator = annotator{
+			globalKey: environGlobalKey,
+			tag:       env.Tag(),
+			st:        st,
+		}
+		result[i] = env
+	}
+	return result
+}
+
 // NewEnvironment creates a new environment with its own UUID and
 // prepares it for use. Environment and State instances for the new
 // environment are returned.
@@ -236,6 +288,44 @@ func (e *Environment) Destroy() error {
 	return err
 }
 
+// EnsureDead sets a Dying hosted environment to Dead once all of its
+// machines and services have been removed. It returns an error if the
+// environment is still Alive, still has machines or services, or is the
+// state server's own environment.
+func (e *Environment) EnsureDead() error {
+	if e.UUID() == e.doc.ServerUUID {
+		return errors.Errorf("cannot set state server environment to dead")
+	}
+	if e.Life() == Dead {
+		return nil
+	}
+	if e.Life() != Dying {
+		return errors.Errorf("environment is not dying")
+	}
+	for _, name := range []string{machinesC, servicesC} {
+		coll, closer := e.st.getRawCollection(name)
+		count, err := newStateCollection(coll, e.UUID()).Count()
+		closer()
+		if err != nil {
+			return errors.Annotatef(err, "cannot count %s", name)
+		}
+		if count > 0 {
+			return errors.Errorf("environment still has %d %s", count, name)
+		}
+	}
+	ops := []txn.Op{{
+		C:      environmentsC,
+		Id:     e.doc.UUID,
+		Update: bson.D{{"$set", bson.D{{"life", Dead}}}},
+		Assert: bson.D{{"life", Dying}},
+	}}
+	if err := e.st.runTransaction(ops); err != nil {
+		return errors.Annotate(err, "cannot set environment to dead")
+	}
+	e.doc.Life = Dead
+	return nil
+}
+
 // createEnvironmentOp returns the operation needed to create
 // an environment document with the given name and UUID.
 func createEnvironmentOp(st *State, owner names.UserTag, name, uuid, server string) txn.Op {
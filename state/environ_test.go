@@ -12,6 +12,7 @@ import (
 
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
 	"github.com/juju/juju/testing"
 )
 
@@ -111,6 +112,101 @@ func (s *EnvironSuite) TestStateServerEnvironmentAccessibleFromOtherEnvironments
 	})
 }
 
+func (s *EnvironSuite) TestAllEnvironments(c *gc.C) {
+	cfg, uuid := s.createTestEnvConfig(c)
+	_, st, err := s.State.NewEnvironment(cfg, names.NewUserTag("test@remote"))
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	envs, err := s.State.AllEnvironments()
+	c.Assert(err, jc.ErrorIsNil)
+	var uuids []string
+	for _, env := range envs {
+		uuids = append(uuids, env.UUID())
+	}
+	c.Assert(uuids, jc.SameContents, []string{s.envTag.Id(), uuid})
+}
+
+func (s *EnvironSuite) TestEnvironmentsForUser(c *gc.C) {
+	owner := names.NewUserTag("test@remote")
+	cfg, uuid := s.createTestEnvConfig(c)
+	_, st, err := s.State.NewEnvironment(cfg, owner)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	envs, err := s.State.EnvironmentsForUser(owner)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envs, gc.HasLen, 1)
+	c.Assert(envs[0].UUID(), gc.Equals, uuid)
+	c.Assert(envs[0].Name(), gc.Equals, "testing")
+	c.Assert(envs[0].Owner(), gc.Equals, owner)
+
+	envs, err = s.State.EnvironmentsForUser(s.owner)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envs, gc.HasLen, 1)
+	c.Assert(envs[0].UUID(), gc.Equals, s.envTag.Id())
+
+	envs, err = s.State.EnvironmentsForUser(names.NewUserTag("nobody@remote"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envs, gc.HasLen, 0)
+}
+
+func (s *EnvironSuite) TestWatchEnvironments(c *gc.C) {
+	w := s.State.WatchEnvironments()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChange(s.envTag.Id())
+	wc.AssertNoChange()
+
+	cfg, uuid := s.createTestEnvConfig(c)
+	env, st, err := s.State.NewEnvironment(cfg, names.NewUserTag("test@remote"))
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+	wc.AssertChange(uuid)
+	wc.AssertNoChange()
+
+	err = env.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange(uuid)
+	wc.AssertNoChange()
+}
+
+func (s *EnvironSuite) TestEnsureDead(c *gc.C) {
+	cfg, _ := s.createTestEnvConfig(c)
+	env, st, err := s.State.NewEnvironment(cfg, names.NewUserTag("test@remote"))
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	err = env.EnsureDead()
+	c.Assert(err, gc.ErrorMatches, "environment is not dying")
+
+	// A dying environment stays dying while it has machines.
+	machine, err := st.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = env.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = env.EnsureDead()
+	c.Assert(err, gc.ErrorMatches, "environment still has 1 machines")
+	c.Assert(env.Refresh(), jc.ErrorIsNil)
+	c.Assert(env.Life(), gc.Equals, state.Dying)
+
+	err = machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	err = env.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env.Refresh(), jc.ErrorIsNil)
+	c.Assert(env.Life(), gc.Equals, state.Dead)
+}
+
+func (s *EnvironSuite) TestEnsureDeadStateServerEnvironment(c *gc.C) {
+	env, err := s.State.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	err = env.EnsureDead()
+	c.Assert(err, gc.ErrorMatches, "cannot set state server environment to dead")
+}
+
 // createTestEnvConfig returns a new environment config and its UUID for testing.
 func (s *EnvironSuite) createTestEnvConfig(c *gc.C) (*config.Config, string) {
 	uuid, err := utils.NewUUID()
@@ -32,6 +32,53 @@ type envUserDoc struct {
 	CreatedBy      string     `bson:"createdby"`
 	DateCreated    time.Time  `bson:"datecreated"`
 	LastConnection *time.Time `bson:"lastconnection"`
+	Access         string     `bson:"access,omitempty"`
+}
+
+// EnvironmentAccess defines the level of access a user has to an
+// environment.
+type EnvironmentAccess string
+
+const (
+	// EnvironmentReadAccess allows a user to inspect, but not change,
+	// an environment.
+	EnvironmentReadAccess EnvironmentAccess = "read"
+
+	// EnvironmentWriteAccess allows a user to deploy and manage
+	// services and machines in an environment.
+	EnvironmentWriteAccess EnvironmentAccess = "write"
+
+	// EnvironmentAdminAccess allows a user to change the environment
+	// itself, including who else may access it.
+	EnvironmentAdminAccess EnvironmentAccess = "admin"
+)
+
+// Validate returns an error if the access level is not one of the
+// known levels.
+func (a EnvironmentAccess) Validate() error {
+	switch a {
+	case EnvironmentReadAccess, EnvironmentWriteAccess, EnvironmentAdminAccess:
+		return nil
+	}
+	return errors.NotValidf("environment access %q", string(a))
+}
+
+// Includes reports whether the access level a grants at least
+// the access of level other.
+func (a EnvironmentAccess) Includes(other EnvironmentAccess) bool {
+	return accessRank(a) >= accessRank(other)
+}
+
+func accessRank(a EnvironmentAccess) int {
+	switch a {
+	case EnvironmentReadAccess:
+		return 1
+	case EnvironmentWriteAccess:
+		return 2
+	case EnvironmentAdminAccess:
+		return 3
+	}
+	return 0
 }
 
 // ID returns the ID of the environment user.
@@ -74,6 +121,34 @@ func (e *EnvironmentUser) LastConnection() *time.Time {
 	return e.doc.LastConnection
 }
 
+// Access returns the level of access the user has to the environment.
+// Environment users created before access levels were introduced had
+// unrestricted access, and are reported as having admin access.
+func (e *EnvironmentUser) Access() EnvironmentAccess {
+	if e.doc.Access == "" {
+		return EnvironmentAdminAccess
+	}
+	return EnvironmentAccess(e.doc.Access)
+}
+
+// SetAccess changes the level of access the user has to the environment.
+func (e *EnvironmentUser) SetAccess(access EnvironmentAccess) error {
+	if err := access.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      envUsersC,
+		Id:     e.ID(),
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"access", string(access)}}}},
+	}}
+	if err := e.st.runTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot set access for envuser %q", e.ID())
+	}
+	e.doc.Access = string(access)
+	return nil
+}
+
 // UpdateLastConnection updates the last connection time of the environment user.
 func (e *EnvironmentUser) UpdateLastConnection() error {
 	timestamp := nowToTheSecond()
@@ -108,11 +183,24 @@ func (st *State) EnvironmentUser(user names.UserTag) (*EnvironmentUser, error) {
 	if err == mgo.ErrNotFound {
 		return nil, errors.NotFoundf("environment user %q", user.Username())
 	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	return envUser, nil
 }
 
-// AddEnvironmentUser adds a new user to the database.
+// AddEnvironmentUser adds a new user to the database. The user is
+// given write access to the environment.
 func (st *State) AddEnvironmentUser(user, createdBy names.UserTag) (*EnvironmentUser, error) {
+	return st.AddEnvironmentUserWithAccess(user, createdBy, EnvironmentWriteAccess)
+}
+
+// AddEnvironmentUserWithAccess adds a new user to the database with the
+// given level of access to the environment.
+func (st *State) AddEnvironmentUserWithAccess(user, createdBy names.UserTag, access EnvironmentAccess) (*EnvironmentUser, error) {
+	if err := access.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
 	var displayName string
 	// Ensure local user exists in state before adding them as an environment user.
 	if user.IsLocal() {
@@ -131,7 +219,7 @@ func (st *State) AddEnvironmentUser(user, createdBy names.UserTag) (*Environment
 	}
 
 	envuuid := st.EnvironUUID()
-	op, doc := createEnvUserOpAndDoc(envuuid, user, createdBy, displayName)
+	op, doc := createEnvUserOpAndDoc(envuuid, user, createdBy, displayName, access)
 	err := st.runTransaction([]txn.Op{op})
 	if err == txn.ErrAborted {
 		err = errors.New("env user already exists")
@@ -142,7 +230,7 @@ func (st *State) AddEnvironmentUser(user, createdBy names.UserTag) (*Environment
 	return &EnvironmentUser{st: st, doc: *doc}, nil
 }
 
-func createEnvUserOpAndDoc(envuuid string, user, createdBy names.UserTag, displayName string) (txn.Op, *envUserDoc) {
+func createEnvUserOpAndDoc(envuuid string, user, createdBy names.UserTag, displayName string, access EnvironmentAccess) (txn.Op, *envUserDoc) {
 	username := user.Username()
 	creatorname := createdBy.Username()
 	id := envUserID(envuuid, username)
@@ -153,6 +241,7 @@ func createEnvUserOpAndDoc(envuuid string, user, createdBy names.UserTag, displa
 		DisplayName: displayName,
 		CreatedBy:   creatorname,
 		DateCreated: nowToTheSecond(),
+		Access:      string(access),
 	}
 	op := txn.Op{
 		C:      envUsersC,
@@ -35,6 +35,7 @@ func (s *EnvUserSuite) TestAddEnvironmentUser(c *gc.C) {
 	c.Assert(envUser.CreatedBy(), gc.Equals, "createdby@local")
 	c.Assert(envUser.DateCreated().Equal(now) || envUser.DateCreated().After(now), jc.IsTrue)
 	c.Assert(envUser.LastConnection(), gc.IsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentWriteAccess)
 
 	envUser, err = s.State.EnvironmentUser(user.UserTag())
 	c.Assert(err, jc.ErrorIsNil)
@@ -45,6 +46,74 @@ func (s *EnvUserSuite) TestAddEnvironmentUser(c *gc.C) {
 	c.Assert(envUser.CreatedBy(), gc.Equals, "createdby@local")
 	c.Assert(envUser.DateCreated().Equal(now) || envUser.DateCreated().After(now), jc.IsTrue)
 	c.Assert(envUser.LastConnection(), gc.IsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentWriteAccess)
+}
+
+func (s *EnvUserSuite) TestAddEnvironmentUserWithAccess(c *gc.C) {
+	user := s.factory.MakeUser(c, &factory.UserParams{Name: "validusername", NoEnvUser: true})
+	createdBy := s.factory.MakeUser(c, &factory.UserParams{Name: "createdby"})
+	envUser, err := s.State.AddEnvironmentUserWithAccess(user.UserTag(), createdBy.UserTag(), state.EnvironmentReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentReadAccess)
+
+	envUser, err = s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentReadAccess)
+}
+
+func (s *EnvUserSuite) TestAddEnvironmentUserWithInvalidAccess(c *gc.C) {
+	user := s.factory.MakeUser(c, &factory.UserParams{Name: "validusername", NoEnvUser: true})
+	createdBy := s.factory.MakeUser(c, &factory.UserParams{Name: "createdby"})
+	_, err := s.State.AddEnvironmentUserWithAccess(user.UserTag(), createdBy.UserTag(), "superuser")
+	c.Assert(err, gc.ErrorMatches, `environment access "superuser" not valid`)
+}
+
+func (s *EnvUserSuite) TestEnvironmentOwnerHasAdminAccess(c *gc.C) {
+	envUser, err := s.State.EnvironmentUser(s.owner)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentAdminAccess)
+}
+
+func (s *EnvUserSuite) TestSetAccess(c *gc.C) {
+	user := s.factory.MakeUser(c, &factory.UserParams{Name: "validusername"})
+	envUser, err := s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = envUser.SetAccess(state.EnvironmentReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentReadAccess)
+
+	envUser, err = s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentReadAccess)
+}
+
+func (s *EnvUserSuite) TestSetInvalidAccess(c *gc.C) {
+	user := s.factory.MakeUser(c, &factory.UserParams{Name: "validusername"})
+	envUser, err := s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = envUser.SetAccess("superuser")
+	c.Assert(err, gc.ErrorMatches, `environment access "superuser" not valid`)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentWriteAccess)
+}
+
+func (s *EnvUserSuite) TestAccessIncludes(c *gc.C) {
+	for i, test := range []struct {
+		access   state.EnvironmentAccess
+		other    state.EnvironmentAccess
+		includes bool
+	}{
+		{state.EnvironmentReadAccess, state.EnvironmentReadAccess, true},
+		{state.EnvironmentReadAccess, state.EnvironmentWriteAccess, false},
+		{state.EnvironmentWriteAccess, state.EnvironmentReadAccess, true},
+		{state.EnvironmentWriteAccess, state.EnvironmentAdminAccess, false},
+		{state.EnvironmentAdminAccess, state.EnvironmentWriteAccess, true},
+		{state.EnvironmentAdminAccess, state.EnvironmentAdminAccess, true},
+	} {
+		c.Logf("test %d: %s includes %s", i, test.access, test.other)
+		c.Check(test.access.Includes(test.other), gc.Equals, test.includes)
+	}
 }
 
 func (s *EnvUserSuite) TestAddEnvironmentNoUserFails(c *gc.C) {
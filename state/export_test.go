@@ -31,15 +31,17 @@ const (
 )
 
 var (
-	ToolstorageNewStorage = &toolstorageNewStorage
-	MachineIdLessThan     = machineIdLessThan
-	NewAddress            = newAddress
-	StateServerAvailable  = &stateServerAvailable
-	GetOrCreatePorts      = getOrCreatePorts
-	GetPorts              = getPorts
-	PortsGlobalKey        = portsGlobalKey
-	CurrentUpgradeId      = currentUpgradeId
-	NowToTheSecond        = nowToTheSecond
+	ToolstorageNewStorage   = &toolstorageNewStorage
+	MachineIdLessThan       = machineIdLessThan
+	NewAddress              = newAddress
+	StateServerAvailable    = &stateServerAvailable
+	GetOrCreatePorts        = getOrCreatePorts
+	GetPorts                = getPorts
+	PortsGlobalKey          = portsGlobalKey
+	CurrentUpgradeId        = currentUpgradeId
+	NowToTheSecond          = nowToTheSecond
+	StatusHistoryMaxAge     = &statusHistoryMaxAge
+	StatusHistoryMaxEntries = &statusHistoryMaxEntries
 )
 
 type (
@@ -182,6 +184,7 @@ func GetPasswordHash(e Authenticator) string {
 
 func init() {
 	logSize = logSizeTests
+	auditLogSize = auditLogSizeTests
 }
 
 // TxnRevno returns the txn-revno field of the document
@@ -282,3 +285,18 @@ func GetCollection(st *State, name string) (stateCollection, func()) {
 func GetRawCollection(st *State, name string) (*mgo.Collection, func()) {
 	return st.getRawCollection(name)
 }
+
+func RemoveLeadershipSettings(st *State, serviceName string) error {
+	return st.runTransaction([]txn.Op{removeLeadershipSettingsOp(st, serviceName)})
+}
+
+func LeadershipSettingsExist(st *State, serviceName string) (bool, error) {
+	settings, closer := st.getCollection(settingsC)
+	defer closer()
+	count, err := settings.FindId(leadershipSettingsKey(serviceName)).Count()
+	return count > 0, err
+}
+
+func RemoveWorkloadStatus(st *State, u *Unit) error {
+	return st.runTransaction([]txn.Op{removeStatusOp(st, u.workloadGlobalKey())})
+}
@@ -0,0 +1,83 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// leadershipSettingsKey returns the settings collection key for the
+// settings shared by the leader of the given service with its
+// followers.
+func leadershipSettingsKey(serviceName string) string {
+	return fmt.Sprintf("s#%s#leader", serviceName)
+}
+
+// createLeadershipSettingsOp returns the operation needed to create
+// the empty leadership settings document for the given service.
+func createLeadershipSettingsOp(st *State, serviceName string) txn.Op {
+	return createSettingsOp(st, leadershipSettingsKey(serviceName), nil)
+}
+
+// removeLeadershipSettingsOp returns the operation needed to remove
+// the leadership settings document for the given service.
+func removeLeadershipSettingsOp(st *State, serviceName string) txn.Op {
+	return txn.Op{
+		C:      settingsC,
+		Id:     st.docID(leadershipSettingsKey(serviceName)),
+		Remove: true,
+	}
+}
+
+// LeaderSettings returns the settings written by the service's leader.
+// Services created before leader settings were introduced have no
+// settings document; an empty map is returned for those.
+func (s *Service) LeaderSettings() (map[string]string, error) {
+	settings, err := readSettings(s.st, leadershipSettingsKey(s.doc.Name))
+	if errors.IsNotFound(err) {
+		return map[string]string{}, nil
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot read leader settings for service %q", s.doc.Name)
+	}
+	result := make(map[string]string)
+	for key, value := range settings.Map() {
+		result[key] = fmt.Sprint(value)
+	}
+	return result, nil
+}
+
+// MergeLeaderSettings merges the supplied values into the service's
+// leader settings. A key with an empty value is deleted. It is the
+// caller's responsibility to ensure that only the service's current
+// leader is allowed to do so.
+func (s *Service) MergeLeaderSettings(values map[string]string) error {
+	key := leadershipSettingsKey(s.doc.Name)
+	settings, err := readSettings(s.st, key)
+	if errors.IsNotFound(err) {
+		settings, err = createSettings(s.st, key, nil)
+	}
+	if err != nil {
+		return errors.Annotatef(err, "cannot write leader settings for service %q", s.doc.Name)
+	}
+	for k, v := range values {
+		if v == "" {
+			settings.Delete(k)
+		} else {
+			settings.Set(k, v)
+		}
+	}
+	if _, err := settings.Write(); err != nil {
+		return errors.Annotatef(err, "cannot write leader settings for service %q", s.doc.Name)
+	}
+	return nil
+}
+
+// WatchLeaderSettings returns a watcher that notifies when the
+// service's leader settings change.
+func (s *Service) WatchLeaderSettings() NotifyWatcher {
+	return newEntityWatcher(s.st, settingsC, s.st.docID(leadershipSettingsKey(s.doc.Name)))
+}
@@ -0,0 +1,100 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/testing"
+)
+
+type LeaderSettingsSuite struct {
+	ConnSuite
+	service *state.Service
+}
+
+var _ = gc.Suite(&LeaderSettingsSuite{})
+
+func (s *LeaderSettingsSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.service = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+}
+
+func (s *LeaderSettingsSuite) TestInitiallyEmpty(c *gc.C) {
+	settings, err := s.service.LeaderSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, map[string]string{})
+}
+
+func (s *LeaderSettingsSuite) TestMerge(c *gc.C) {
+	err := s.service.MergeLeaderSettings(map[string]string{
+		"foo":     "bar",
+		"baz":     "qux",
+		"dot.key": "$value",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.MergeLeaderSettings(map[string]string{
+		"foo": "",
+		"baz": "quux",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	settings, err := s.service.LeaderSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, map[string]string{
+		"baz":     "quux",
+		"dot.key": "$value",
+	})
+}
+
+func (s *LeaderSettingsSuite) TestMissingSettingsDocument(c *gc.C) {
+	// Services created before leader settings existed have no
+	// settings document; it is created on first write.
+	err := state.RemoveLeadershipSettings(s.State, "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+
+	settings, err := s.service.LeaderSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, map[string]string{})
+
+	err = s.service.MergeLeaderSettings(map[string]string{"foo": "bar"})
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err = s.service.LeaderSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, map[string]string{"foo": "bar"})
+}
+
+func (s *LeaderSettingsSuite) TestWatch(c *gc.C) {
+	w := s.service.WatchLeaderSettings()
+	defer testing.AssertStop(c, w)
+
+	// Initial event.
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	err := s.service.MergeLeaderSettings(map[string]string{"foo": "bar"})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Writing the same values again does not trigger a change.
+	err = s.service.MergeLeaderSettings(map[string]string{"foo": "bar"})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	testing.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
+func (s *LeaderSettingsSuite) TestRemovedWithService(c *gc.C) {
+	err := s.service.MergeLeaderSettings(map[string]string{"foo": "bar"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	exists, err := state.LeadershipSettingsExist(s.State, "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(exists, jc.IsFalse)
+}
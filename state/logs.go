@@ -0,0 +1,225 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// LogRecord holds a structured log record sent by an agent.
+type LogRecord struct {
+	// Id uniquely identifies the record once it has been stored, and
+	// orders records by the time they were stored. It is ignored by
+	// AddLogs.
+	Id string
+
+	// Time holds the time at which the record was logged by the
+	// agent.
+	Time time.Time
+
+	// Entity holds the tag of the agent that logged the record.
+	Entity string
+
+	Module   string
+	Location string
+	Level    loggo.Level
+	Message  string
+}
+
+// LogFilter restricts the log records returned by Logs. Zero-valued
+// fields do not restrict the results.
+type LogFilter struct {
+	// IncludeEntity and ExcludeEntity hold entity tags that records
+	// must or must not have been logged by. Tags may end with a "*"
+	// wildcard, as in "unit-mysql-*".
+	IncludeEntity []string
+	ExcludeEntity []string
+
+	// IncludeModule and ExcludeModule hold prefixes of the logging
+	// modules that records must or must not have been logged to.
+	IncludeModule []string
+	ExcludeModule []string
+
+	// MinLevel holds the lowest level of records to return.
+	MinLevel loggo.Level
+
+	// After restricts the results to records stored after the
+	// record with the given id.
+	After string
+
+	// Last holds the maximum number of records to return. When more
+	// records match, the most recently stored ones are returned.
+	Last int
+}
+
+// logDoc records a log record. Logs are only ever inserted and
+// pruned, so they are written directly rather than through
+// transactions.
+type logDoc struct {
+	Id       bson.ObjectId `bson:"_id"`
+	EnvUUID  string        `bson:"env-uuid"`
+	Time     time.Time     `bson:"time"`
+	Entity   string        `bson:"entity"`
+	Module   string        `bson:"module"`
+	Location string        `bson:"location"`
+	Level    int           `bson:"level"`
+	Message  string        `bson:"message"`
+}
+
+func (doc *logDoc) record() LogRecord {
+	return LogRecord{
+		Id:       doc.Id.Hex(),
+		Time:     doc.Time,
+		Entity:   doc.Entity,
+		Module:   doc.Module,
+		Location: doc.Location,
+		Level:    loggo.Level(doc.Level),
+		Message:  doc.Message,
+	}
+}
+
+// AddLogs stores the given log records.
+func (st *State) AddLogs(records []LogRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	docs := make([]interface{}, len(records))
+	for i, record := range records {
+		docs[i] = &logDoc{
+			Id:       bson.NewObjectId(),
+			EnvUUID:  st.EnvironUUID(),
+			Time:     record.Time.UTC(),
+			Entity:   record.Entity,
+			Module:   record.Module,
+			Location: record.Location,
+			Level:    int(record.Level),
+			Message:  record.Message,
+		}
+	}
+	logs, closer := st.getCollection(logsC)
+	defer closer()
+	if err := logs.Insert(docs...); err != nil {
+		return errors.Annotate(err, "cannot add log records")
+	}
+	return nil
+}
+
+// Logs returns the stored log records that match the given filter,
+// in the order they were stored.
+func (st *State) Logs(filter LogFilter) ([]LogRecord, error) {
+	query, err := logsQuery(filter)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	logs, closer := st.getCollection(logsC)
+	defer closer()
+
+	q := logs.Find(query).Sort("_id")
+	if filter.Last > 0 {
+		q = logs.Find(query).Sort("-_id").Limit(filter.Last)
+	}
+	var docs []logDoc
+	if err := q.All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get log records")
+	}
+	records := make([]LogRecord, len(docs))
+	for i, doc := range docs {
+		records[i] = doc.record()
+	}
+	if filter.Last > 0 {
+		// The most recent records were fetched first.
+		for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+			records[i], records[j] = records[j], records[i]
+		}
+	}
+	return records, nil
+}
+
+func logsQuery(filter LogFilter) (bson.D, error) {
+	var query bson.D
+	if filter.After != "" {
+		if !bson.IsObjectIdHex(filter.After) {
+			return nil, errors.NotValidf("log record id %q", filter.After)
+		}
+		query = append(query, bson.DocElem{"_id", bson.D{{"$gt", bson.ObjectIdHex(filter.After)}}})
+	}
+	if filter.MinLevel > loggo.UNSPECIFIED {
+		query = append(query, bson.DocElem{"level", bson.D{{"$gte", int(filter.MinLevel)}}})
+	}
+	if cond := patternsCondition(filter.IncludeEntity, filter.ExcludeEntity, entityPattern); cond != nil {
+		query = append(query, bson.DocElem{"entity", cond})
+	}
+	if cond := patternsCondition(filter.IncludeModule, filter.ExcludeModule, modulePattern); cond != nil {
+		query = append(query, bson.DocElem{"module", cond})
+	}
+	return query, nil
+}
+
+// patternsCondition returns a condition matching values that match
+// any of the include patterns, if there are any, and none of the
+// exclude patterns, or nil if there are no patterns.
+func patternsCondition(include, exclude []string, pattern func(string) bson.RegEx) bson.D {
+	var cond bson.D
+	if len(include) > 0 {
+		cond = append(cond, bson.DocElem{"$in", regexes(include, pattern)})
+	}
+	if len(exclude) > 0 {
+		cond = append(cond, bson.DocElem{"$nin", regexes(exclude, pattern)})
+	}
+	return cond
+}
+
+func regexes(values []string, pattern func(string) bson.RegEx) []bson.RegEx {
+	result := make([]bson.RegEx, len(values))
+	for i, value := range values {
+		result[i] = pattern(value)
+	}
+	return result
+}
+
+// entityPattern matches an entity tag, which may end with a "*"
+// wildcard.
+func entityPattern(tag string) bson.RegEx {
+	quoted := regexp.QuoteMeta(tag)
+	quoted = strings.Replace(quoted, `\*`, ".*", -1)
+	return bson.RegEx{Pattern: "^" + quoted + "$"}
+}
+
+// modulePattern matches the logging modules with the given prefix.
+func modulePattern(prefix string) bson.RegEx {
+	return bson.RegEx{Pattern: "^" + regexp.QuoteMeta(prefix)}
+}
+
+// PruneLogs removes the stored log records that were logged before
+// minTime, and then the oldest records in excess of maxCount.
+func (st *State) PruneLogs(minTime time.Time, maxCount int) error {
+	logs, closer := st.getCollection(logsC)
+	defer closer()
+
+	_, err := logs.RemoveAll(bson.D{{"time", bson.D{{"$lt", minTime.UTC()}}}})
+	if err != nil {
+		return errors.Annotate(err, "cannot prune log records")
+	}
+	var newest struct {
+		Id bson.ObjectId `bson:"_id"`
+	}
+	err = logs.Find(nil).Sort("-_id").Skip(maxCount).Select(bson.D{{"_id", 1}}).One(&newest)
+	if err == mgo.ErrNotFound {
+		return nil
+	} else if err != nil {
+		return errors.Annotate(err, "cannot prune log records")
+	}
+	_, err = logs.RemoveAll(bson.D{{"_id", bson.D{{"$lte", newest.Id}}}})
+	if err != nil {
+		return errors.Annotate(err, "cannot prune log records")
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type LogsSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&LogsSuite{})
+
+var logsTime = time.Date(2014, 11, 5, 10, 0, 0, 0, time.UTC)
+
+func (s *LogsSuite) addLogs(c *gc.C, records ...state.LogRecord) {
+	for i := range records {
+		if records[i].Time.IsZero() {
+			records[i].Time = logsTime.Add(time.Duration(i) * time.Second)
+		}
+	}
+	err := s.State.AddLogs(records)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func messages(records []state.LogRecord) []string {
+	result := make([]string, len(records))
+	for i, record := range records {
+		result[i] = record.Message
+	}
+	return result
+}
+
+func (s *LogsSuite) TestAddLogs(c *gc.C) {
+	s.addLogs(c, state.LogRecord{
+		Entity:   "machine-0",
+		Module:   "juju.worker",
+		Location: "worker.go:42",
+		Level:    loggo.INFO,
+		Message:  "started",
+	}, state.LogRecord{
+		Entity:  "unit-mysql-0",
+		Module:  "juju.worker.uniter",
+		Level:   loggo.ERROR,
+		Message: "hook failed",
+	})
+
+	records, err := s.State.Logs(state.LogFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(records, gc.HasLen, 2)
+	c.Assert(records[0].Id, gc.Not(gc.Equals), "")
+	records[0].Id = ""
+	c.Assert(records[0], jc.DeepEquals, state.LogRecord{
+		Time:     logsTime,
+		Entity:   "machine-0",
+		Module:   "juju.worker",
+		Location: "worker.go:42",
+		Level:    loggo.INFO,
+		Message:  "started",
+	})
+	c.Assert(records[1].Message, gc.Equals, "hook failed")
+	c.Assert(records[1].Level, gc.Equals, loggo.ERROR)
+}
+
+func (s *LogsSuite) TestLogsFilter(c *gc.C) {
+	s.addLogs(c,
+		state.LogRecord{Entity: "machine-0", Module: "juju.worker", Level: loggo.DEBUG, Message: "m0 debug"},
+		state.LogRecord{Entity: "machine-1", Module: "juju.worker.firewaller", Level: loggo.INFO, Message: "m1 info"},
+		state.LogRecord{Entity: "unit-mysql-0", Module: "juju.worker.uniter", Level: loggo.WARNING, Message: "mysql warning"},
+		state.LogRecord{Entity: "unit-mysql-1", Module: "unit.mysql/1.install", Level: loggo.ERROR, Message: "mysql error"},
+		state.LogRecord{Entity: "unit-wordpress-0", Module: "juju.workers", Level: loggo.INFO, Message: "wordpress info"},
+	)
+
+	for i, test := range []struct {
+		about    string
+		filter   state.LogFilter
+		expected []string
+	}{{
+		about:    "no filter",
+		expected: []string{"m0 debug", "m1 info", "mysql warning", "mysql error", "wordpress info"},
+	}, {
+		about:    "include entity",
+		filter:   state.LogFilter{IncludeEntity: []string{"machine-1", "unit-mysql-*"}},
+		expected: []string{"m1 info", "mysql warning", "mysql error"},
+	}, {
+		about:    "exclude entity",
+		filter:   state.LogFilter{ExcludeEntity: []string{"machine-*"}},
+		expected: []string{"mysql warning", "mysql error", "wordpress info"},
+	}, {
+		about:    "include module prefix",
+		filter:   state.LogFilter{IncludeModule: []string{"juju.worker"}},
+		expected: []string{"m0 debug", "m1 info", "mysql warning", "wordpress info"},
+	}, {
+		about:    "exclude module",
+		filter:   state.LogFilter{ExcludeModule: []string{"juju.worker.uniter", "unit"}},
+		expected: []string{"m0 debug", "m1 info", "wordpress info"},
+	}, {
+		about:    "min level",
+		filter:   state.LogFilter{MinLevel: loggo.WARNING},
+		expected: []string{"mysql warning", "mysql error"},
+	}, {
+		about:    "last",
+		filter:   state.LogFilter{Last: 2},
+		expected: []string{"mysql error", "wordpress info"},
+	}, {
+		about: "combined",
+		filter: state.LogFilter{
+			IncludeEntity: []string{"unit-*"},
+			ExcludeModule: []string{"juju"},
+			MinLevel:      loggo.INFO,
+		},
+		expected: []string{"mysql error"},
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		records, err := s.State.Logs(test.filter)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(messages(records), jc.DeepEquals, test.expected)
+	}
+}
+
+func (s *LogsSuite) TestLogsAfter(c *gc.C) {
+	s.addLogs(c, state.LogRecord{Entity: "machine-0", Message: "first"})
+	records, err := s.State.Logs(state.LogFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(records, gc.HasLen, 1)
+
+	s.addLogs(c,
+		state.LogRecord{Entity: "machine-0", Message: "second"},
+		state.LogRecord{Entity: "machine-0", Message: "third"},
+	)
+	records, err = s.State.Logs(state.LogFilter{After: records[0].Id})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(messages(records), jc.DeepEquals, []string{"second", "third"})
+
+	_, err = s.State.Logs(state.LogFilter{After: "bad"})
+	c.Assert(err, gc.ErrorMatches, `log record id "bad" not valid`)
+}
+
+func (s *LogsSuite) TestPruneLogs(c *gc.C) {
+	for i, message := range []string{"a", "b", "c", "d", "e"} {
+		s.addLogs(c, state.LogRecord{
+			Entity:  "machine-0",
+			Time:    logsTime.Add(time.Duration(i) * time.Minute),
+			Message: message,
+		})
+	}
+
+	err := s.State.PruneLogs(logsTime.Add(time.Minute), 10)
+	c.Assert(err, jc.ErrorIsNil)
+	records, err := s.State.Logs(state.LogFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(messages(records), jc.DeepEquals, []string{"b", "c", "d", "e"})
+
+	err = s.State.PruneLogs(logsTime, 2)
+	c.Assert(err, jc.ErrorIsNil)
+	records, err = s.State.Logs(state.LogFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(messages(records), jc.DeepEquals, []string{"d", "e"})
+}
@@ -650,6 +650,7 @@ func (m *Machine) Remove() (err error) {
 		removeRequestedNetworksOp(m.st, m.globalKey()),
 		annotationRemoveOp(m.st, m.globalKey()),
 		removeRebootDocOp(m.st, m.globalKey()),
+		m.st.newCleanupOp(cleanupStatusHistory, m.globalKey()),
 	}
 	ifacesOps, err := m.removeNetworkInterfacesOps()
 	if err != nil {
@@ -735,6 +736,21 @@ func (m *Machine) SetAgentPresence() (*presence.Pinger, error) {
 	return p, nil
 }
 
+// SetAgentConnected signals that the agent for machine m is alive
+// for as long as it remains connected to the API server that owns r.
+// It returns the recorded connection.
+func (m *Machine) SetAgentConnected(r *presence.Recorder) (*presence.Connection, error) {
+	conn, err := r.Connect(m.globalKey())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// See SetAgentPresence.
+	if m.IsManager() {
+		m.st.pwatcher.Sync()
+	}
+	return conn, nil
+}
+
 // InstanceId returns the provider specific instance id for this
 // machine, or a NotProvisionedError, if not set.
 func (m *Machine) InstanceId() (instance.Id, error) {
@@ -1266,9 +1282,18 @@ func (m *Machine) SetStatus(status Status, info string, data map[string]interfac
 	if err := m.st.runTransaction(ops); err != nil {
 		return fmt.Errorf("cannot set status of machine %q: %v", m, onAbort(err, errNotAlive))
 	}
+	if err := recordStatusHistory(m.st, m.globalKey(), doc); err != nil {
+		logger.Warningf("%v", err)
+	}
 	return nil
 }
 
+// StatusHistory returns the recorded status transitions of the
+// machine that match the given filter, newest first.
+func (m *Machine) StatusHistory(filter StatusHistoryFilter) ([]StatusInfo, error) {
+	return statusHistory(m.st, m.globalKey(), filter)
+}
+
 // Clean returns true if the machine does not have any deployed units or containers.
 func (m *Machine) Clean() bool {
 	return m.doc.Clean
@@ -436,6 +436,29 @@ func (s *MachineSuite) TestMachineSetAgentPresence(c *gc.C) {
 	c.Assert(alive, jc.IsTrue)
 }
 
+func (s *MachineSuite) TestMachineSetAgentConnected(c *gc.C) {
+	recorder, err := s.State.NewPresenceRecorder(s.machine0.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	defer recorder.Stop()
+
+	conn, err := s.machine.SetAgentConnected(recorder)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(conn, gc.NotNil)
+
+	s.State.StartSync()
+	alive, err := s.machine.AgentPresence()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(alive, jc.IsTrue)
+
+	err = conn.Close()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.State.StartSync()
+	alive, err = s.machine.AgentPresence()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(alive, jc.IsFalse)
+}
+
 func (s *MachineSuite) TestTag(c *gc.C) {
 	c.Assert(s.machine.Tag().String(), gc.Equals, "machine-1")
 }
@@ -109,11 +109,21 @@ func (u *backingUnit) updated(st *State, store *multiwatcherStore, id interface{
 		}
 		info.Status = multiwatcher.Status(sdoc.Status)
 		info.StatusInfo = sdoc.StatusInfo
+		wsdoc, err := getStatus(st, unitWorkloadGlobalKey(u.Name))
+		if errors.IsNotFound(err) {
+			wsdoc.Status = StatusUnknown
+		} else if err != nil {
+			return err
+		}
+		info.WorkloadStatus = multiwatcher.Status(wsdoc.Status)
+		info.WorkloadStatusInfo = wsdoc.StatusInfo
 	} else {
 		// The entry already exists, so preserve the current status.
 		oldInfo := oldInfo.(*multiwatcher.UnitInfo)
 		info.Status = oldInfo.Status
 		info.StatusInfo = oldInfo.StatusInfo
+		info.WorkloadStatus = oldInfo.WorkloadStatus
+		info.WorkloadStatusInfo = oldInfo.WorkloadStatusInfo
 	}
 	publicAddress, privateAddress, err := getUnitAddresses(st, u.Name)
 	if err != nil {
@@ -287,7 +297,10 @@ func (a *backingAnnotation) mongoId() interface{} {
 type backingStatus statusDoc
 
 func (s *backingStatus) updated(st *State, store *multiwatcherStore, id interface{}) error {
-	parentId, ok := backingEntityIdForGlobalKey(st.localID(id.(string)))
+	localID := st.localID(id.(string))
+	parentKey := strings.TrimSuffix(localID, workloadStatusSuffix)
+	isWorkload := parentKey != localID
+	parentId, ok := backingEntityIdForGlobalKey(parentKey)
 	if !ok {
 		return nil
 	}
@@ -298,9 +311,14 @@ func (s *backingStatus) updated(st *State, store *multiwatcherStore, id interfac
 		return nil
 	case *multiwatcher.UnitInfo:
 		newInfo := *info
-		newInfo.Status = multiwatcher.Status(s.Status)
-		newInfo.StatusInfo = s.StatusInfo
-		newInfo.StatusData = s.StatusData
+		if isWorkload {
+			newInfo.WorkloadStatus = multiwatcher.Status(s.Status)
+			newInfo.WorkloadStatusInfo = s.StatusInfo
+		} else {
+			newInfo.Status = multiwatcher.Status(s.Status)
+			newInfo.StatusInfo = s.StatusInfo
+			newInfo.StatusData = s.StatusData
+		}
 		info0 = &newInfo
 	case *multiwatcher.MachineInfo:
 		newInfo := *info
@@ -167,13 +167,14 @@ func (s *storeManagerStateSuite) setUpScenario(c *gc.C) (entities entityInfoSlic
 		c.Assert(m.Tag().String(), gc.Equals, fmt.Sprintf("machine-%d", i+1))
 
 		add(&multiwatcher.UnitInfo{
-			Name:        fmt.Sprintf("wordpress/%d", i),
-			Service:     wordpress.Name(),
-			Series:      m.Series(),
-			MachineId:   m.Id(),
-			Ports:       []network.Port{},
-			Status:      multiwatcher.StatusPending,
-			Subordinate: false,
+			Name:           fmt.Sprintf("wordpress/%d", i),
+			Service:        wordpress.Name(),
+			Series:         m.Series(),
+			MachineId:      m.Id(),
+			Ports:          []network.Port{},
+			Status:         multiwatcher.StatusPending,
+			WorkloadStatus: multiwatcher.StatusUnknown,
+			Subordinate:    false,
 		})
 		pairs := map[string]string{"name": fmt.Sprintf("bar %d", i)}
 		err = wu.SetAnnotations(pairs)
@@ -222,12 +223,13 @@ func (s *storeManagerStateSuite) setUpScenario(c *gc.C) (entities entityInfoSlic
 		c.Assert(ok, jc.IsTrue)
 		c.Assert(deployer, gc.Equals, names.NewUnitTag(fmt.Sprintf("wordpress/%d", i)))
 		add(&multiwatcher.UnitInfo{
-			Name:        fmt.Sprintf("logging/%d", i),
-			Service:     "logging",
-			Series:      "quantal",
-			Ports:       []network.Port{},
-			Status:      multiwatcher.StatusPending,
-			Subordinate: true,
+			Name:           fmt.Sprintf("logging/%d", i),
+			Service:        "logging",
+			Series:         "quantal",
+			Ports:          []network.Port{},
+			Status:         multiwatcher.StatusPending,
+			WorkloadStatus: multiwatcher.StatusUnknown,
+			Subordinate:    true,
 		})
 	}
 	return
@@ -399,13 +401,14 @@ func (s *storeManagerStateSuite) TestChanged(c *gc.C) {
 				},
 				expectContents: []multiwatcher.EntityInfo{
 					&multiwatcher.UnitInfo{
-						Name:       "wordpress/0",
-						Service:    "wordpress",
-						Series:     "quantal",
-						MachineId:  "0",
-						Ports:      []network.Port{},
-						Status:     multiwatcher.StatusError,
-						StatusInfo: "failure",
+						Name:           "wordpress/0",
+						Service:        "wordpress",
+						Series:         "quantal",
+						MachineId:      "0",
+						Ports:          []network.Port{},
+						Status:         multiwatcher.StatusError,
+						StatusInfo:     "failure",
+						WorkloadStatus: multiwatcher.StatusUnknown,
 					}}}
 		}, func(c *gc.C, st *State) testCase {
 			wordpress := AddTestingService(c, st, "wordpress", AddTestingCharm(c, st, "wordpress"), s.owner)
@@ -473,6 +476,7 @@ func (s *storeManagerStateSuite) TestChanged(c *gc.C) {
 						Ports:          []network.Port{},
 						Status:         multiwatcher.StatusError,
 						StatusInfo:     "failure",
+						WorkloadStatus: multiwatcher.StatusUnknown,
 					}}}
 		},
 		// Service changes
@@ -751,6 +755,31 @@ func (s *storeManagerStateSuite) TestChanged(c *gc.C) {
 							"2nd-key": 2,
 							"3rd-key": true,
 						}}}}
+		}, func(c *gc.C, st *State) testCase {
+			wordpress := AddTestingService(c, st, "wordpress", AddTestingCharm(c, st, "wordpress"), s.owner)
+			u, err := wordpress.AddUnit()
+			c.Assert(err, jc.ErrorIsNil)
+			err = u.SetWorkloadStatus(StatusBlocked, "need a database")
+			c.Assert(err, jc.ErrorIsNil)
+
+			return testCase{
+				about: "workload status is changed if the unit exists in the store",
+				add: []multiwatcher.EntityInfo{&multiwatcher.UnitInfo{
+					Name:           "wordpress/0",
+					Status:         multiwatcher.StatusStarted,
+					WorkloadStatus: multiwatcher.StatusUnknown,
+				}},
+				change: watcher.Change{
+					C:  "statuses",
+					Id: st.docID("u#wordpress/0#workload"),
+				},
+				expectContents: []multiwatcher.EntityInfo{
+					&multiwatcher.UnitInfo{
+						Name:               "wordpress/0",
+						Status:             multiwatcher.StatusStarted,
+						WorkloadStatus:     multiwatcher.StatusBlocked,
+						WorkloadStatusInfo: "need a database",
+					}}}
 		},
 		// Machine status changes
 		func(c *gc.C, st *State) testCase {
@@ -1084,11 +1113,12 @@ func (s *storeManagerStateSuite) TestStateWatcher(c *gc.C) {
 		},
 	}, {
 		Entity: &multiwatcher.UnitInfo{
-			Name:      "wordpress/0",
-			Service:   "wordpress",
-			Series:    "quantal",
-			MachineId: "2",
-			Status:    "pending",
+			Name:           "wordpress/0",
+			Service:        "wordpress",
+			Series:         "quantal",
+			MachineId:      "2",
+			Status:         "pending",
+			WorkloadStatus: "unknown",
 		},
 	}})
 
@@ -5,6 +5,7 @@ package state
 
 import (
 	"encoding/json"
+	"sort"
 	"time"
 
 	"github.com/juju/errors"
@@ -224,6 +225,66 @@ func (st *State) CountofSentMetrics() (int, error) {
 	}).Count()
 }
 
+// UnitMetric holds the most recent value recorded for a metric by a
+// unit.
+type UnitMetric struct {
+	Unit     string
+	CharmURL string
+	Metric
+}
+
+// LatestMetrics returns the most recent value recorded for each metric
+// of each unit, ordered by unit and metric key. Metric batches that
+// have been sent to the collection service are included until they
+// are cleaned up.
+func (st *State) LatestMetrics() ([]UnitMetric, error) {
+	c, closer := st.getCollection(metricsC)
+	defer closer()
+	var docs []metricBatchDoc
+	err := c.Find(nil).Select(bson.M{
+		"unit":     1,
+		"charmurl": 1,
+		"metrics":  1,
+	}).All(&docs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	type unitKey struct {
+		unit, key string
+	}
+	latest := make(map[unitKey]UnitMetric)
+	for _, doc := range docs {
+		for _, metric := range doc.Metrics {
+			k := unitKey{doc.Unit, metric.Key}
+			if m, ok := latest[k]; ok && !metric.Time.After(m.Time) {
+				continue
+			}
+			latest[k] = UnitMetric{
+				Unit:     doc.Unit,
+				CharmURL: doc.CharmUrl,
+				Metric:   metric,
+			}
+		}
+	}
+	results := make([]UnitMetric, 0, len(latest))
+	for _, m := range latest {
+		results = append(results, m)
+	}
+	sort.Sort(unitMetricsByKey(results))
+	return results, nil
+}
+
+type unitMetricsByKey []UnitMetric
+
+func (m unitMetricsByKey) Len() int      { return len(m) }
+func (m unitMetricsByKey) Swap(i, j int) { m[i], m[j] = m[j], m[i] }
+func (m unitMetricsByKey) Less(i, j int) bool {
+	if m[i].Unit != m[j].Unit {
+		return m[i].Unit < m[j].Unit
+	}
+	return m[i].Key < m[j].Key
+}
+
 // MarshalJSON defines how the MetricBatch type should be
 // converted to json.
 func (m *MetricBatch) MarshalJSON() ([]byte, error) {
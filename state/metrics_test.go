@@ -296,3 +296,25 @@ func (s *MetricSuite) TestMetricValidation(c *gc.C) {
 		}
 	}
 }
+
+func (s *MetricSuite) TestLatestMetrics(c *gc.C) {
+	now := state.NowToTheSecond()
+	earlier := now.Add(-time.Minute)
+	s.factory.MakeMetric(c, &factory.MetricParams{Unit: s.unit, Time: &earlier, Metrics: []state.Metric{
+		{Key: "pings", Value: "5", Time: earlier},
+		{Key: "juju-units", Value: "1", Time: earlier},
+	}})
+	s.factory.MakeMetric(c, &factory.MetricParams{Unit: s.unit, Sent: true, Time: &now, Metrics: []state.Metric{
+		{Key: "pings", Value: "7", Time: now},
+	}})
+	metrics, err := s.State.LatestMetrics()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metrics, gc.HasLen, 2)
+	c.Assert(metrics[0].Unit, gc.Equals, "metered/0")
+	c.Assert(metrics[0].CharmURL, gc.Equals, "cs:quantal/metered")
+	c.Assert(metrics[0].Key, gc.Equals, "juju-units")
+	c.Assert(metrics[0].Value, gc.Equals, "1")
+	c.Assert(metrics[1].Key, gc.Equals, "pings")
+	c.Assert(metrics[1].Value, gc.Equals, "7")
+	c.Assert(metrics[1].Time.Equal(now), jc.IsTrue)
+}
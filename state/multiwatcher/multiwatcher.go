@@ -53,6 +53,29 @@ const (
 	StatusDown Status = "down"
 )
 
+const (
+	// The following statuses describe the workload of a unit, as
+	// reported by its charm, rather than the unit agent.
+
+	// The charm has not reported the workload status.
+	StatusUnknown Status = "unknown"
+
+	// The workload is not yet available because the unit is
+	// installing or reconfiguring it.
+	StatusMaintenance Status = "maintenance"
+
+	// The workload is waiting for some other part of the
+	// environment, such as a related service, to become available.
+	StatusWaiting Status = "waiting"
+
+	// The workload cannot run until an operator intervenes, for
+	// instance by adding a relation or changing the configuration.
+	StatusBlocked Status = "blocked"
+
+	// The workload is running and ready to serve.
+	StatusActive Status = "active"
+)
+
 // Valid returns true if status has a known value.
 func (status Status) Valid() bool {
 	switch status {
@@ -69,6 +92,21 @@ func (status Status) Valid() bool {
 	return true
 }
 
+// ValidWorkload returns true if status is a known workload status.
+func (status Status) ValidWorkload() bool {
+	switch status {
+	case
+		StatusUnknown,
+		StatusMaintenance,
+		StatusWaiting,
+		StatusBlocked,
+		StatusActive:
+	default:
+		return false
+	}
+	return true
+}
+
 // EntityInfo is implemented by all entity Info types.
 type EntityInfo interface {
 	// EntityId returns an identifier that will uniquely
@@ -216,7 +254,11 @@ type UnitInfo struct {
 	Status         Status
 	StatusInfo     string
 	StatusData     map[string]interface{}
-	Subordinate    bool
+	// WorkloadStatus and WorkloadStatusInfo hold the status of the
+	// unit's workload, as reported by its charm.
+	WorkloadStatus     Status
+	WorkloadStatusInfo string
+	Subordinate        bool
 }
 
 func (i *UnitInfo) EntityId() EntityId {
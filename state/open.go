@@ -134,7 +134,7 @@ func (st *State) envSetupOps(cfg *config.Config, serverUUID string, owner names.
 	if serverUUID == "" {
 		serverUUID = uuid
 	}
-	envUserOp, _ := createEnvUserOpAndDoc(uuid, owner, owner, owner.Name())
+	envUserOp, _ := createEnvUserOpAndDoc(uuid, owner, owner, owner.Name(), EnvironmentAdminAccess)
 	ops := []txn.Op{
 		createConstraintsOp(st, environGlobalKey, constraints.Value{}),
 		createSettingsOp(st, environGlobalKey, cfg.AllAttrs()),
@@ -169,6 +169,13 @@ var indexes = []struct {
 	{subnetsC, []string{"providerid"}, true, true},
 	{ipaddressesC, []string{"state"}, false, false},
 	{ipaddressesC, []string{"subnetid"}, false, false},
+	{auditC, []string{"env-uuid", "timestamp"}, false, false},
+	{auditC, []string{"env-uuid", "user", "timestamp"}, false, false},
+	{statusesHistoryC, []string{"env-uuid", "entityid", "updated"}, false, false},
+	{storageInstancesC, []string{"env-uuid", "owner"}, false, false},
+	{subnetsC, []string{"env-uuid", "spacename"}, false, false},
+	{logsC, []string{"env-uuid", "time"}, false, false},
+	{logsC, []string{"env-uuid", "entity"}, false, false},
 }
 
 // The capped collection used for transaction logs defaults to 10MB.
@@ -179,6 +186,13 @@ var (
 	logSizeTests = 1000000
 )
 
+// The capped collection used for the audit log defaults to 50MB.
+// It's tweaked in export_test.go to 1MB for the same reason.
+var (
+	auditLogSize      = 50000000
+	auditLogSizeTests = 1000000
+)
+
 func maybeUnauthorized(err error, msg string) error {
 	if err == nil {
 		return nil
@@ -235,6 +249,11 @@ func newState(session *mgo.Session, mongoInfo *mongo.MongoInfo, policy Policy) (
 	if err != nil && err.Error() != "collection already exists" {
 		return nil, maybeUnauthorized(err, "cannot create log collection")
 	}
+	auditLog := db.C(auditC)
+	err = auditLog.Create(&mgo.CollectionInfo{Capped: true, MaxBytes: auditLogSize})
+	if err != nil && err.Error() != "collection already exists" {
+		return nil, maybeUnauthorized(err, "cannot create audit log collection")
+	}
 	txns := db.C(txnsC)
 	err = txns.Create(&mgo.CollectionInfo{})
 	if err != nil && err.Error() != "collection already exists" {
@@ -0,0 +1,163 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package presence
+
+import (
+	"sync"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// ConnectionPresencer is implemented by entities whose agent
+// liveness can be recorded from their connections to an API server,
+// rather than by having a Pinger write to the database periodically.
+type ConnectionPresencer interface {
+	SetAgentConnected(r *Recorder) (*Connection, error)
+}
+
+// The connections collection holds a document for every key
+// connected to an API server:
+//
+// {
+//   "_id":    <server>#<key>,
+//   "server": <server>,
+//   "key":    <key>,
+// }
+//
+// Only the API servers themselves ping, using the key returned by
+// serverKey, so the database is written to when agents connect and
+// disconnect rather than every period for every agent. Watchers
+// consider a connected key alive for as long as its server is, so
+// the connections of a server that goes away without cleaning up
+// time out in the same way as a stopped Pinger, and are then removed
+// by the watchers. For this reason every Recorder must be identified
+// by a distinct server value.
+
+type connectionInfo struct {
+	Id     string `bson:"_id"`
+	Server string `bson:"server"`
+	Key    string `bson:"key"`
+}
+
+// Recorder records the keys connected to a single API server, and
+// broadcasts them through the database to the watchers of all the
+// state servers.
+type Recorder struct {
+	mu      sync.Mutex
+	conns   *mgo.Collection
+	server  string
+	pinger  *Pinger
+	counts  map[string]int
+	stopped bool
+}
+
+// NewRecorder returns a new Recorder for the API server identified
+// by server, and starts pinging to report that server as alive.
+func NewRecorder(base *mgo.Collection, server string) (*Recorder, error) {
+	r := &Recorder{
+		conns:  connectionsC(base),
+		server: server,
+		pinger: NewPinger(base, serverKey(server)),
+		counts: make(map[string]int),
+	}
+	if err := r.pinger.Start(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return r, nil
+}
+
+// Connect records that key has connected to the API server. The key
+// is considered alive until all of its connections are closed.
+func (r *Recorder) Connect(key string) (*Connection, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stopped {
+		return nil, errors.Errorf("presence recorder for %q is stopped", r.server)
+	}
+	if r.counts[key] == 0 {
+		session := r.conns.Database.Session.Copy()
+		defer session.Close()
+		doc := connectionInfo{r.server + "#" + key, r.server, key}
+		if _, err := r.conns.With(session).UpsertId(doc.Id, doc); err != nil {
+			return nil, errors.Annotatef(err, "cannot record connection for %q", key)
+		}
+		logger.Tracef("recorded connection for %q to %q", key, r.server)
+	}
+	r.counts[key]++
+	return &Connection{recorder: r, key: key}, nil
+}
+
+// disconnect forgets a single connection of key, and removes
+// its record when it was the last one.
+func (r *Recorder) disconnect(key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stopped {
+		return nil
+	}
+	r.counts[key]--
+	if r.counts[key] > 0 {
+		return nil
+	}
+	delete(r.counts, key)
+	session := r.conns.Database.Session.Copy()
+	defer session.Close()
+	err := r.conns.With(session).RemoveId(r.server + "#" + key)
+	if err != nil && err != mgo.ErrNotFound {
+		return errors.Annotatef(err, "cannot remove connection for %q", key)
+	}
+	logger.Tracef("removed connection for %q to %q", key, r.server)
+	return nil
+}
+
+// Stop stops the Recorder, immediately reporting the API server as
+// dead and removing the record of all its connections.
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stopped {
+		return nil
+	}
+	r.stopped = true
+	r.counts = nil
+	killErr := r.pinger.Kill()
+	session := r.conns.Database.Session.Copy()
+	defer session.Close()
+	if _, err := r.conns.With(session).RemoveAll(bson.D{{"server", r.server}}); err != nil {
+		return errors.Annotatef(err, "cannot remove connections to %q", r.server)
+	}
+	return errors.Trace(killErr)
+}
+
+// Connection represents a single connection recorded by a Recorder.
+type Connection struct {
+	mu       sync.Mutex
+	recorder *Recorder
+	key      string
+	closed   bool
+}
+
+// Close records that the connection has been closed. Closing a
+// connection more than once has no effect.
+func (c *Connection) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	return c.recorder.disconnect(c.key)
+}
+
+// serverKey returns the key pinged by the Recorder of the
+// given API server.
+func serverKey(server string) string {
+	return "apiserver#" + server
+}
+
+func connectionsC(base *mgo.Collection) *mgo.Collection {
+	return base.Database.C(base.Name + ".connections")
+}
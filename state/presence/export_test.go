@@ -24,3 +24,9 @@ func RealPeriod() {
 func FindAllBeings(w *Watcher) (map[int64]beingInfo, error) {
 	return w.findAllBeings()
 }
+
+// KillRecorderPinger stops r pinging without removing its
+// connections, as happens when its API server goes away.
+func KillRecorderPinger(r *Recorder) error {
+	return r.pinger.Kill()
+}
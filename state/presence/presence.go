@@ -4,7 +4,10 @@
 // The presence package implements an interface for observing liveness
 // of arbitrary keys (agents, processes, etc) on top of MongoDB.
 // The design works by periodically updating the database so that
-// watchers can tell an arbitrary key is alive.
+// watchers can tell an arbitrary key is alive. Alternatively, keys
+// can be reported alive through a Recorder, which records the agent
+// connections held by an API server and requires only the server
+// itself to ping.
 package presence
 
 import (
@@ -71,6 +74,15 @@ type Watcher struct {
 	beingKey map[int64]string
 	beingSeq map[string]int64
 
+	// connKeys holds the keys that are currently connected to
+	// a live API server, as recorded by a Recorder. Entries in
+	// this map are considered alive too.
+	connKeys map[string]bool
+
+	// deadServers holds the API servers with recorded connections
+	// that were not alive at the last sync.
+	deadServers map[string]bool
+
 	// watches has the per-key observer channels from Watch/Unwatch.
 	watches map[string][]chan<- Change
 
@@ -112,6 +124,7 @@ func NewWatcher(base *mgo.Collection) *Watcher {
 		beings:   beingsC(base),
 		beingKey: make(map[int64]string),
 		beingSeq: make(map[string]int64),
+		connKeys: make(map[string]bool),
 		watches:  make(map[string][]chan<- Change),
 		request:  make(chan interface{}),
 	}
@@ -291,8 +304,7 @@ func (w *Watcher) handle(req interface{}) {
 			}
 		}
 		w.watches[r.key] = append(w.watches[r.key], r.ch)
-		_, alive := w.beingSeq[r.key]
-		w.pending = append(w.pending, event{r.ch, r.key, alive})
+		w.pending = append(w.pending, event{r.ch, r.key, w.alive(r.key)})
 	case reqUnwatch:
 		watches := w.watches[r.key]
 		for i, ch := range watches {
@@ -309,8 +321,7 @@ func (w *Watcher) handle(req interface{}) {
 			}
 		}
 	case reqAlive:
-		_, alive := w.beingSeq[r.key]
-		r.result <- alive
+		r.result <- w.alive(r.key)
 	default:
 		panic(fmt.Errorf("unknown request: %T", req))
 	}
@@ -344,10 +355,43 @@ func (w *Watcher) findAllBeings() (map[int64]beingInfo, error) {
 	return beingInfos, nil
 }
 
+// alive returns whether the key is known to be alive, either
+// because it is pinging or because it is connected to a live
+// API server.
+func (w *Watcher) alive(key string) bool {
+	_, alive := w.beingSeq[key]
+	return alive || w.connKeys[key]
+}
+
 // sync updates the watcher knowledge from the database, and
-// queues events to observing channels. It fetches the last two time
-// slots and compares the union of both to the in-memory state.
+// queues events to observing channels for the watched keys
+// whose liveness has changed.
 func (w *Watcher) sync() error {
+	before := make(map[string]bool, len(w.watches))
+	for key := range w.watches {
+		before[key] = w.alive(key)
+	}
+	if err := w.syncPings(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := w.syncConnections(); err != nil {
+		return errors.Trace(err)
+	}
+	for key, chs := range w.watches {
+		alive := w.alive(key)
+		if alive == before[key] {
+			continue
+		}
+		for _, ch := range chs {
+			w.pending = append(w.pending, event{ch, key, alive})
+		}
+	}
+	return nil
+}
+
+// syncPings fetches the last two time slots and compares the
+// union of both to the in-memory state.
+func (w *Watcher) syncPings() error {
 	var allBeings map[int64]beingInfo
 	if len(w.beingKey) == 0 {
 		// The very first time we sync, we grab all ever-known beings,
@@ -440,28 +484,65 @@ func (w *Watcher) sync() error {
 					continue
 				}
 				logger.Tracef("found seq=%d alive with key %q", seq, being.Key)
-				for _, ch := range w.watches[being.Key] {
-					w.pending = append(w.pending, event{ch, being.Key, true})
-				}
 			}
 		}
 	}
 
 	// Pingers that were known to be alive and haven't reported
-	// in the last two slots are now considered dead. Dispatch
-	// the respective events and forget their sequences.
+	// in the last two slots are now considered dead. Forget
+	// their sequences.
 	for seq, key := range w.beingKey {
 		if dead[seq] || !alive[seq] {
+			logger.Tracef("found seq=%d dead with key %q", seq, key)
 			delete(w.beingKey, seq)
 			delete(w.beingSeq, key)
-			for _, ch := range w.watches[key] {
-				w.pending = append(w.pending, event{ch, key, false})
-			}
 		}
 	}
 	return nil
 }
 
+// syncConnections fetches all recorded agent connections and
+// considers alive the keys connected to an API server that is
+// itself alive, removing the connections of dead servers. It must
+// be called after syncPings, so that the liveness of the API
+// servers is up to date.
+func (w *Watcher) syncConnections() error {
+	session := w.base.Database.Session.Copy()
+	defer session.Close()
+	conns := connectionsC(w.base).With(session)
+	var docs []connectionInfo
+	if err := conns.Find(nil).All(&docs); err != nil {
+		return errors.Trace(err)
+	}
+	connKeys := make(map[string]bool)
+	deadServers := make(map[string]bool)
+	for _, doc := range docs {
+		if _, ok := w.beingSeq[serverKey(doc.Server)]; ok {
+			connKeys[doc.Key] = true
+		} else {
+			deadServers[doc.Server] = true
+		}
+	}
+	// Only a Recorder that is stopped removes its connections, so
+	// those of a server that went away without stopping it are
+	// removed here. A server must be seen dead by two consecutive
+	// syncs first, so that one whose first ping was missed is not
+	// mistaken for a dead one.
+	for server := range deadServers {
+		if !w.deadServers[server] {
+			continue
+		}
+		if _, err := conns.RemoveAll(bson.D{{"server", server}}); err != nil {
+			return errors.Annotatef(err, "cannot remove connections to %q", server)
+		}
+		logger.Debugf("removed connections to dead API server %q", server)
+		delete(deadServers, server)
+	}
+	w.connKeys = connKeys
+	w.deadServers = deadServers
+	return nil
+}
+
 // Pinger periodically reports that a specific key is alive, so that
 // watchers interested on that fact can react appropriately.
 type Pinger struct {
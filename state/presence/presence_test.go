@@ -483,3 +483,131 @@ func (s *PresenceSuite) TestFindAllBeings(c *gc.C) {
 		c.Fatalf("Sync failed to returned")
 	}
 }
+
+func (s *PresenceSuite) TestRecorderConnections(c *gc.C) {
+	w := presence.NewWatcher(s.presence)
+	defer w.Stop()
+	r, err := presence.NewRecorder(s.presence, "server-0")
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Stop()
+
+	ch := make(chan presence.Change, 1)
+	w.Watch("a", ch)
+	assertChange(c, ch, presence.Change{"a", false})
+
+	conn1, err := r.Connect("a")
+	c.Assert(err, jc.ErrorIsNil)
+	w.StartSync()
+	assertChange(c, ch, presence.Change{"a", true})
+
+	// The key stays alive while any of its connections remain.
+	conn2, err := r.Connect("a")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(conn1.Close(), jc.ErrorIsNil)
+	c.Assert(conn1.Close(), jc.ErrorIsNil)
+	w.StartSync()
+	assertNoChange(c, ch)
+
+	c.Assert(conn2.Close(), jc.ErrorIsNil)
+	w.StartSync()
+	assertChange(c, ch, presence.Change{"a", false})
+}
+
+func (s *PresenceSuite) TestRecorderWithPinger(c *gc.C) {
+	w := presence.NewWatcher(s.presence)
+	defer w.Stop()
+	r, err := presence.NewRecorder(s.presence, "server-0")
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Stop()
+	p := presence.NewPinger(s.presence, "a")
+	defer p.Stop()
+
+	ch := make(chan presence.Change, 1)
+	w.Watch("a", ch)
+	assertChange(c, ch, presence.Change{"a", false})
+
+	c.Assert(p.Start(), gc.IsNil)
+	conn, err := r.Connect("a")
+	c.Assert(err, jc.ErrorIsNil)
+	w.StartSync()
+	assertChange(c, ch, presence.Change{"a", true})
+
+	// Either source of liveness is enough.
+	c.Assert(p.Kill(), gc.IsNil)
+	w.StartSync()
+	assertNoChange(c, ch)
+
+	c.Assert(conn.Close(), jc.ErrorIsNil)
+	w.StartSync()
+	assertChange(c, ch, presence.Change{"a", false})
+}
+
+func (s *PresenceSuite) TestRecorderStop(c *gc.C) {
+	w := presence.NewWatcher(s.presence)
+	defer w.Stop()
+	r, err := presence.NewRecorder(s.presence, "server-0")
+	c.Assert(err, jc.ErrorIsNil)
+	other, err := presence.NewRecorder(s.presence, "server-1")
+	c.Assert(err, jc.ErrorIsNil)
+	defer other.Stop()
+
+	ch := make(chan presence.Change, 1)
+	w.Watch("a", ch)
+	assertChange(c, ch, presence.Change{"a", false})
+	chb := make(chan presence.Change, 1)
+	w.Watch("b", chb)
+	assertChange(c, chb, presence.Change{"b", false})
+
+	conn, err := r.Connect("a")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = other.Connect("b")
+	c.Assert(err, jc.ErrorIsNil)
+	w.StartSync()
+	assertChange(c, ch, presence.Change{"a", true})
+	assertChange(c, chb, presence.Change{"b", true})
+
+	// Stopping a recorder only affects its own connections.
+	c.Assert(r.Stop(), jc.ErrorIsNil)
+	w.StartSync()
+	assertChange(c, ch, presence.Change{"a", false})
+	assertNoChange(c, chb)
+
+	c.Assert(conn.Close(), jc.ErrorIsNil)
+	_, err = r.Connect("a")
+	c.Assert(err, gc.ErrorMatches, `presence recorder for "server-0" is stopped`)
+}
+
+func (s *PresenceSuite) TestWatcherRemovesConnectionsOfDeadServer(c *gc.C) {
+	w := presence.NewWatcher(s.presence)
+	defer w.Stop()
+	r, err := presence.NewRecorder(s.presence, "server-0")
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Stop()
+
+	ch := make(chan presence.Change, 1)
+	w.Watch("a", ch)
+	assertChange(c, ch, presence.Change{"a", false})
+	_, err = r.Connect("a")
+	c.Assert(err, jc.ErrorIsNil)
+	w.StartSync()
+	assertChange(c, ch, presence.Change{"a", true})
+
+	conns := s.presence.Database.C(s.presence.Name + ".connections")
+	count := func() int {
+		n, err := conns.Find(nil).Count()
+		c.Assert(err, jc.ErrorIsNil)
+		return n
+	}
+	c.Assert(count(), gc.Equals, 1)
+
+	// The server goes away without stopping its recorder. Its
+	// connections are kept until it has been seen dead twice.
+	c.Assert(presence.KillRecorderPinger(r), jc.ErrorIsNil)
+	w.StartSync()
+	assertChange(c, ch, presence.Change{"a", false})
+	c.Assert(count(), gc.Equals, 1)
+
+	w.Sync()
+	c.Assert(count(), gc.Equals, 0)
+	assertNoChange(c, ch)
+}
@@ -5,9 +5,12 @@ package state
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/instance"
 )
 
 // RestoreStatus is the type of the statuses
@@ -25,7 +28,16 @@ const (
 	RestoreInProgress RestoreStatus = "RESTORING"
 	// RestoreFinished it is set by restore upon a succesful run
 	RestoreFinished RestoreStatus = "RESTORED"
-	RestoreChecked  RestoreStatus = "CHECKED"
+	// RestoreChecked is set by the client once it has verified that
+	// the restored state server is working.
+	RestoreChecked RestoreStatus = "CHECKED"
+	// RestoreFailed is set by restore when it could not complete, so
+	// that the API is usable again.
+	RestoreFailed RestoreStatus = "FAILED"
+	// RestorePartial is set by restore when it failed after replacing
+	// the database, leaving the state server inconsistent. The API
+	// stays blocked, apart from retrying the restore.
+	RestorePartial RestoreStatus = "PARTIAL"
 )
 
 type restoreInfoDoc struct {
@@ -50,7 +62,7 @@ func (info *RestoreInfo) SetStatus(status RestoreStatus) error {
 	var assertSane bson.D
 
 	if status == RestoreInProgress {
-		assertSane = bson.D{{"status", RestorePending}}
+		assertSane = bson.D{{"status", bson.D{{"$in", []RestoreStatus{RestorePending, RestorePartial}}}}}
 	}
 	if status == RestoreChecked {
 		assertSane = bson.D{{"status", RestoreFinished}}
@@ -101,3 +113,51 @@ func (st *State) EnsureRestoreInfo() (*RestoreInfo, error) {
 
 	return &RestoreInfo{st: st, doc: doc}, nil
 }
+
+// ResetStateServer records the machine with the given id, running on
+// the given instance, as the only state server of the environment.
+// It is used once a backup has been restored onto a new or existing
+// state server; the other state servers in the backup are left
+// without a vote.
+func (st *State) ResetStateServer(machineId string, instId instance.Id) error {
+	if !names.IsValidMachine(machineId) {
+		return errors.NotValidf("machine id %q", machineId)
+	}
+	info, err := st.StateServerInfo()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      instanceDataC,
+		Id:     st.docID(machineId),
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"instanceid", instId}}}},
+	}, {
+		C:      machinesC,
+		Id:     st.docID(machineId),
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"novote", false}, {"hasvote", true}}}},
+	}, {
+		C:  stateServersC,
+		Id: environGlobalKey,
+		Update: bson.D{{"$set", bson.D{
+			{"machineids", []string{machineId}},
+			{"votingmachineids", []string{machineId}},
+		}}},
+	}}
+	for _, id := range info.MachineIds {
+		if id == machineId {
+			continue
+		}
+		ops = append(ops, txn.Op{
+			C:      machinesC,
+			Id:     st.docID(id),
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{{"novote", true}, {"hasvote", false}}}},
+		})
+	}
+	if err := st.runTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot reset state server to machine %s", machineId)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+)
+
+type RestoreSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&RestoreSuite{})
+
+func (s *RestoreSuite) TestRestoreInfoStatus(c *gc.C) {
+	info, err := s.State.EnsureRestoreInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Status(), gc.Equals, state.UnknownRestoreStatus)
+
+	err = info.SetStatus(state.RestoreInProgress)
+	c.Assert(err, gc.ErrorMatches, `cannot set restore status to "RESTORING": .*`)
+
+	for _, status := range []state.RestoreStatus{
+		state.RestorePending,
+		state.RestoreInProgress,
+		state.RestorePartial,
+		state.RestoreInProgress,
+		state.RestoreFailed,
+	} {
+		err = info.SetStatus(status)
+		c.Assert(err, jc.ErrorIsNil)
+		info, err = s.State.EnsureRestoreInfo()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(info.Status(), gc.Equals, status)
+	}
+}
+
+func (s *RestoreSuite) TestResetStateServer(c *gc.C) {
+	m0, err := s.State.AddMachine("quantal", state.JobManageEnviron)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m0.SetProvisioned("old-inst", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	m1, err := s.State.AddMachine("quantal", state.JobManageEnviron)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.ResetStateServer(m0.Id(), "new-inst")
+	c.Assert(err, jc.ErrorIsNil)
+
+	info, err := s.State.StateServerInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(info.MachineIds, jc.DeepEquals, []string{m0.Id()})
+	c.Check(info.VotingMachineIds, jc.DeepEquals, []string{m0.Id()})
+
+	err = m0.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	instId, err := m0.InstanceId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(instId, gc.Equals, instance.Id("new-inst"))
+	c.Check(m0.WantsVote(), jc.IsTrue)
+
+	err = m1.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(m1.WantsVote(), jc.IsFalse)
+	c.Check(m1.HasVote(), jc.IsFalse)
+}
+
+func (s *RestoreSuite) TestResetStateServerNotProvisioned(c *gc.C) {
+	m0, err := s.State.AddMachine("quantal", state.JobManageEnviron)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.ResetStateServer(m0.Id(), "new-inst")
+	c.Assert(err, gc.ErrorMatches, `cannot reset state server to machine 0: transaction aborted`)
+}
@@ -19,6 +19,8 @@ import (
 	"gopkg.in/mgo.v2/txn"
 
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/storage"
 )
 
 // Service represents the state of a service.
@@ -47,6 +49,15 @@ type serviceDoc struct {
 	OwnerTag          string     `bson:"ownertag"`
 	TxnRevno          int64      `bson:"txn-revno"`
 	MetricCredentials []byte     `bson:"metric-credentials"`
+
+	// ExposedSourceCIDRs restricts the addresses an exposed
+	// service's open ports are accessible from. When empty, the
+	// ports are accessible from any address.
+	ExposedSourceCIDRs []string `bson:"exposedsourcecidrs,omitempty"`
+
+	// Storage holds the storage directives according to which
+	// storage instances are created for each new unit.
+	Storage []storageDirectiveDoc `bson:"storage,omitempty"`
 }
 
 func newService(st *State, doc *serviceDoc) *Service {
@@ -229,6 +240,7 @@ func (s *Service) removeOps(asserts bson.D) []txn.Op {
 		Id:     settingsDocID,
 		Remove: true,
 	}}
+	ops = append(ops, removeLeadershipSettingsOp(s.st, s.doc.Name))
 	ops = append(ops, removeRequestedNetworksOp(s.st, s.globalKey()))
 	ops = append(ops, removeConstraintsOp(s.st, s.globalKey()))
 	return append(ops, annotationRemoveOp(s.st, s.globalKey()))
@@ -241,29 +253,52 @@ func (s *Service) IsExposed() bool {
 	return s.doc.Exposed
 }
 
-// SetExposed marks the service as exposed.
+// ExposedSourceCIDRs returns the CIDRs the open ports of an exposed
+// service are restricted to. An empty result means the ports are
+// accessible from any address.
+func (s *Service) ExposedSourceCIDRs() []string {
+	return append([]string(nil), s.doc.ExposedSourceCIDRs...)
+}
+
+// SetExposed marks the service as exposed. If any source CIDRs are
+// given, the service's open ports are only accessible from addresses
+// within them; otherwise they are accessible from any address.
 // See ClearExposed and IsExposed.
-func (s *Service) SetExposed() error {
-	return s.setExposed(true)
+func (s *Service) SetExposed(sourceCIDRs ...string) error {
+	if err := network.ValidateSourceCIDRs(sourceCIDRs); err != nil {
+		return errors.Annotatef(err, "cannot expose service %q", s)
+	}
+	return s.setExposed(true, network.NormaliseSourceCIDRs(sourceCIDRs))
 }
 
 // ClearExposed removes the exposed flag from the service.
 // See SetExposed and IsExposed.
 func (s *Service) ClearExposed() error {
-	return s.setExposed(false)
+	return s.setExposed(false, nil)
 }
 
-func (s *Service) setExposed(exposed bool) (err error) {
+func (s *Service) setExposed(exposed bool, sourceCIDRs []string) (err error) {
+	update := bson.D{
+		{"$set", bson.D{{"exposed", exposed}}},
+		{"$unset", bson.D{{"exposedsourcecidrs", nil}}},
+	}
+	if len(sourceCIDRs) > 0 {
+		update = bson.D{{"$set", bson.D{
+			{"exposed", exposed},
+			{"exposedsourcecidrs", sourceCIDRs},
+		}}}
+	}
 	ops := []txn.Op{{
 		C:      servicesC,
 		Id:     s.doc.DocID,
 		Assert: isAliveDoc,
-		Update: bson.D{{"$set", bson.D{{"exposed", exposed}}}},
+		Update: update,
 	}}
 	if err := s.st.runTransaction(ops); err != nil {
 		return fmt.Errorf("cannot set exposed flag for service %q to %v: %v", s, exposed, onAbort(err, errNotAlive))
 	}
 	s.doc.Exposed = exposed
+	s.doc.ExposedSourceCIDRs = sourceCIDRs
 	return nil
 }
 
@@ -599,6 +634,10 @@ func (s *Service) addUnitOps(principalName string, asserts bson.D) (string, []tx
 		Status:  StatusPending,
 		EnvUUID: s.st.EnvironUUID(),
 	}
+	wsdoc := statusDoc{
+		Status:  StatusUnknown,
+		EnvUUID: s.st.EnvironUUID(),
+	}
 	msdoc := meterStatusDoc{
 		Code: MeterNotSet,
 	}
@@ -610,6 +649,7 @@ func (s *Service) addUnitOps(principalName string, asserts bson.D) (string, []tx
 			Insert: udoc,
 		},
 		createStatusOp(s.st, globalKey, sdoc),
+		createStatusOp(s.st, unitWorkloadGlobalKey(name), wsdoc),
 		createMeterStatusOp(s.st, globalKey, msdoc),
 		{
 			C:      servicesC,
@@ -637,6 +677,11 @@ func (s *Service) addUnitOps(principalName string, asserts bson.D) (string, []tx
 		}
 		ops = append(ops, createConstraintsOp(s.st, globalKey, cons))
 	}
+	storageOps, err := createStorageInstancesOps(s.st, name, s.doc.Storage)
+	if err != nil {
+		return "", nil, err
+	}
+	ops = append(ops, storageOps...)
 	return name, ops, nil
 }
 
@@ -683,6 +728,10 @@ func (s *Service) removeUnitOps(u *Unit, asserts bson.D) ([]txn.Op, error) {
 	if err != nil {
 		return nil, err
 	}
+	storageOps, err := removeStorageInstancesOps(s.st, u.doc.Name)
+	if err != nil {
+		return nil, err
+	}
 
 	observedFieldsMatch := bson.D{
 		{"charmurl", u.doc.CharmURL},
@@ -696,11 +745,14 @@ func (s *Service) removeUnitOps(u *Unit, asserts bson.D) ([]txn.Op, error) {
 	},
 		removeConstraintsOp(s.st, u.globalKey()),
 		removeStatusOp(s.st, u.globalKey()),
+		removeStatusOp(s.st, u.workloadGlobalKey()),
 		removeMeterStatusOp(s.st, u.globalKey()),
 		annotationRemoveOp(s.st, u.globalKey()),
 		s.st.newCleanupOp(cleanupRemovedUnit, u.doc.Name),
+		s.st.newCleanupOp(cleanupStatusHistory, u.globalKey()),
 	)
 	ops = append(ops, portsOps...)
+	ops = append(ops, storageOps...)
 	if u.doc.CharmURL != nil {
 		decOps, err := settingsDecRefOps(s.st, s.doc.Name, u.doc.CharmURL)
 		if errors.IsNotFound(err) {
@@ -853,6 +905,45 @@ func (s *Service) SetConstraints(cons constraints.Value) (err error) {
 	return onAbort(s.st.runTransaction(ops), errNotAlive)
 }
 
+// StorageDirectives returns the storage directives according to which
+// storage instances are created for each new unit of the service.
+func (s *Service) StorageDirectives() []storage.Directive {
+	return fromStorageDirectiveDocs(s.doc.Storage)
+}
+
+// SetStorageDirectives sets the storage directives according to which
+// storage instances are created for each new unit of the service.
+// Storage directives can only be set before any units are added.
+func (s *Service) SetStorageDirectives(directives []storage.Directive) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set storage directives")
+	if err := validateStorageDirectives(directives); err != nil {
+		return err
+	}
+	if s.doc.Life != Alive {
+		return errNotAlive
+	}
+	docs := toStorageDirectiveDocs(directives)
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: append(isAliveDoc, bson.DocElem{"unitcount", 0}),
+		Update: bson.D{{"$set", bson.D{{"storage", docs}}}},
+	}}
+	if err := s.st.runTransaction(ops); err == txn.ErrAborted {
+		if err := s.Refresh(); err != nil {
+			return err
+		}
+		if s.doc.Life != Alive {
+			return errNotAlive
+		}
+		return errors.New("service already has units")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	s.doc.Storage = docs
+	return nil
+}
+
 // Networks returns the networks a service is associated with. Unlike
 // networks specified with constraints, these networks are required to
 // be present on machines hosting this service's units.
@@ -1017,6 +1017,39 @@ func (s *ServiceSuite) TestServiceExposed(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, notAliveErr)
 }
 
+func (s *ServiceSuite) TestServiceExposedSourceCIDRs(c *gc.C) {
+	c.Assert(s.mysql.ExposedSourceCIDRs(), gc.HasLen, 0)
+
+	err := s.mysql.SetExposed("203.0.113.4/32", "10.0.0.0/8", "10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsExposed(), jc.IsTrue)
+	c.Assert(s.mysql.ExposedSourceCIDRs(), jc.DeepEquals, []string{"10.0.0.0/8", "203.0.113.4/32"})
+
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.ExposedSourceCIDRs(), jc.DeepEquals, []string{"10.0.0.0/8", "203.0.113.4/32"})
+
+	// Exposing again without CIDRs opens the service to all addresses.
+	err = s.mysql.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.ExposedSourceCIDRs(), gc.HasLen, 0)
+
+	err = s.mysql.SetExposed("10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsExposed(), jc.IsFalse)
+	c.Assert(s.mysql.ExposedSourceCIDRs(), gc.HasLen, 0)
+
+	err = s.mysql.SetExposed("10.0.0.1")
+	c.Assert(err, gc.ErrorMatches, `cannot expose service "mysql": source CIDR "10.0.0.1" not valid`)
+	c.Assert(s.mysql.IsExposed(), jc.IsFalse)
+}
+
 func (s *ServiceSuite) TestAddUnit(c *gc.C) {
 	// Check that principal units can be added on their own.
 	unitZero, err := s.mysql.AddUnit()
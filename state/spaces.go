@@ -0,0 +1,147 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/network"
+)
+
+// Space represents a named group of subnets. Spaces are used by the
+// spaces constraint to select the subnets a machine is started in.
+type Space struct {
+	st  *State
+	doc spaceDoc
+}
+
+type spaceDoc struct {
+	DocID   string `bson:"_id"`
+	EnvUUID string `bson:"env-uuid"`
+	Life    Life   `bson:"life"`
+	Name    string `bson:"name"`
+}
+
+// Name returns the name of the space.
+func (s *Space) Name() string {
+	return s.doc.Name
+}
+
+// Life returns whether the space is Alive, Dying or Dead.
+func (s *Space) Life() Life {
+	return s.doc.Life
+}
+
+// String implements fmt.Stringer.
+func (s *Space) String() string {
+	return s.doc.Name
+}
+
+// Subnets returns the subnets that belong to the space, ordered by
+// CIDR.
+func (s *Space) Subnets() ([]*Subnet, error) {
+	return subnets(s.st, bson.D{{"spacename", s.doc.Name}})
+}
+
+// Refresh refreshes the contents of the space from the underlying
+// state. It returns an error that satisfies errors.IsNotFound if the
+// space has been removed.
+func (s *Space) Refresh() error {
+	doc, err := getSpaceDoc(s.st, s.doc.Name)
+	if err != nil {
+		return err
+	}
+	s.doc = *doc
+	return nil
+}
+
+func getSpaceDoc(st *State, name string) (*spaceDoc, error) {
+	spaces, closer := st.getCollection(spacesC)
+	defer closer()
+
+	var doc spaceDoc
+	err := spaces.FindId(name).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("space %q", name)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get space %q", name)
+	}
+	return &doc, nil
+}
+
+// AddSpace creates and returns a new space holding the subnets with
+// the given CIDRs. A subnet belongs to at most one space, so subnets
+// already part of another space are moved to the new one. If a space
+// with the same name already exists, an error satisfying
+// errors.IsAlreadyExists is returned.
+func (st *State) AddSpace(name string, subnetCIDRs []string) (space *Space, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add space %q", name)
+	if !network.IsValidSpace(name) {
+		return nil, errors.NotValidf("space name %q", name)
+	}
+	doc := spaceDoc{
+		DocID:   st.docID(name),
+		EnvUUID: st.EnvironUUID(),
+		Life:    Alive,
+		Name:    name,
+	}
+	ops := []txn.Op{{
+		C:      spacesC,
+		Id:     doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: doc,
+	}}
+	for _, cidr := range subnetCIDRs {
+		subnet, err := st.Subnet(cidr)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if subnet.Life() != Alive {
+			return nil, errors.Errorf("subnet %q is not alive", cidr)
+		}
+		ops = append(ops, txn.Op{
+			C:      subnetsC,
+			Id:     subnet.ID(),
+			Assert: isAliveDoc,
+			Update: bson.D{{"$set", bson.D{{"spacename", name}}}},
+		})
+	}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		if _, err := st.Space(name); err == nil {
+			return nil, errors.AlreadyExistsf("space %q", name)
+		}
+		return nil, errors.New("one or more subnets are no longer alive")
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &Space{st: st, doc: doc}, nil
+}
+
+// Space returns the space with the given name.
+func (st *State) Space(name string) (*Space, error) {
+	doc, err := getSpaceDoc(st, name)
+	if err != nil {
+		return nil, err
+	}
+	return &Space{st: st, doc: *doc}, nil
+}
+
+// AllSpaces returns all spaces in the environment, ordered by name.
+func (st *State) AllSpaces() ([]*Space, error) {
+	spaces, closer := st.getCollection(spacesC)
+	defer closer()
+
+	var docs []spaceDoc
+	if err := spaces.Find(nil).Sort("name").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get spaces")
+	}
+	result := make([]*Space, len(docs))
+	for i, doc := range docs {
+		result[i] = &Space{st: st, doc: doc}
+	}
+	return result, nil
+}
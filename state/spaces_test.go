@@ -0,0 +1,129 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type SpacesSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&SpacesSuite{})
+
+func (s *SpacesSuite) addSubnet(c *gc.C, cidr string) *state.Subnet {
+	subnet, err := s.State.AddSubnet(state.SubnetInfo{CIDR: cidr})
+	c.Assert(err, jc.ErrorIsNil)
+	return subnet
+}
+
+func subnetCIDRs(subnets []*state.Subnet) []string {
+	cidrs := make([]string, len(subnets))
+	for i, subnet := range subnets {
+		cidrs[i] = subnet.CIDR()
+	}
+	return cidrs
+}
+
+func (s *SpacesSuite) TestAddSpace(c *gc.C) {
+	s.addSubnet(c, "10.0.1.0/24")
+	s.addSubnet(c, "10.0.0.0/24")
+	s.addSubnet(c, "10.0.2.0/24")
+
+	space, err := s.State.AddSpace("db", []string{"10.0.1.0/24", "10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(space.Name(), gc.Equals, "db")
+	c.Assert(space.Life(), gc.Equals, state.Alive)
+
+	subnets, err := space.Subnets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnetCIDRs(subnets), jc.DeepEquals, []string{"10.0.0.0/24", "10.0.1.0/24"})
+
+	subnet, err := s.State.Subnet("10.0.1.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.SpaceName(), gc.Equals, "db")
+	subnet, err = s.State.Subnet("10.0.2.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.SpaceName(), gc.Equals, "")
+}
+
+func (s *SpacesSuite) TestAddSpaceMovesSubnets(c *gc.C) {
+	s.addSubnet(c, "10.0.0.0/24")
+	first, err := s.State.AddSpace("first", []string{"10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	second, err := s.State.AddSpace("second", []string{"10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	subnets, err := first.Subnets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnets, gc.HasLen, 0)
+	subnets, err = second.Subnets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnetCIDRs(subnets), jc.DeepEquals, []string{"10.0.0.0/24"})
+}
+
+func (s *SpacesSuite) TestAddSpaceErrors(c *gc.C) {
+	_, err := s.State.AddSpace("Not Valid", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot add space "Not Valid": space name "Not Valid" not valid`)
+	c.Assert(errors.IsNotValid(err), jc.IsTrue)
+
+	_, err = s.State.AddSpace("db", []string{"10.0.0.0/24"})
+	c.Assert(err, gc.ErrorMatches, `cannot add space "db": subnet "10.0.0.0/24" not found`)
+
+	subnet := s.addSubnet(c, "10.0.0.0/24")
+	err = subnet.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("db", []string{"10.0.0.0/24"})
+	c.Assert(err, gc.ErrorMatches, `cannot add space "db": subnet "10.0.0.0/24" is not alive`)
+
+	_, err = s.State.AddSpace("db", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("db", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot add space "db": space "db" already exists`)
+	c.Assert(errors.IsAlreadyExists(err), jc.IsTrue)
+}
+
+func (s *SpacesSuite) TestAllSpaces(c *gc.C) {
+	spaces, err := s.State.AllSpaces()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spaces, gc.HasLen, 0)
+
+	for _, name := range []string{"web", "db", "admin-net"} {
+		_, err := s.State.AddSpace(name, nil)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	spaces, err = s.State.AllSpaces()
+	c.Assert(err, jc.ErrorIsNil)
+	var names []string
+	for _, space := range spaces {
+		names = append(names, space.Name())
+	}
+	c.Assert(names, jc.DeepEquals, []string{"admin-net", "db", "web"})
+
+	space, err := s.State.Space("db")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(space.Name(), gc.Equals, "db")
+	_, err = s.State.Space("missing")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *SpacesSuite) TestAddSubnetInSpace(c *gc.C) {
+	_, err := s.State.AddSubnet(state.SubnetInfo{CIDR: "10.0.0.0/24", SpaceName: "db"})
+	c.Assert(err, gc.ErrorMatches, `cannot add subnet 10.0.0.0/24: space "db" not found`)
+
+	_, err = s.State.AddSpace("db", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	subnet, err := s.State.AddSubnet(state.SubnetInfo{CIDR: "10.0.0.0/24", SpaceName: "db"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.SpaceName(), gc.Equals, "db")
+
+	subnets, err := s.State.AllSubnets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnetCIDRs(subnets), jc.DeepEquals, []string{"10.0.0.0/24"})
+}
@@ -56,6 +56,7 @@ const (
 	unitsC             = "units"
 	subnetsC           = "subnets"
 	ipaddressesC       = "ipaddresses"
+	spacesC            = "spaces"
 
 	// actionsC and related collections store state of Actions that
 	// have been enqueued.
@@ -80,6 +81,9 @@ const (
 	rebootC       = "reboot"
 	blockDevicesC = "blockdevices"
 
+	// storageInstancesC holds the storage instances owned by units.
+	storageInstancesC = "storageinstances"
+
 	// leaseC is used to store lease tokens
 	leaseC = "lease"
 
@@ -92,6 +96,16 @@ const (
 	// toolsmetadataC is the collection used to store tools metadata.
 	toolsmetadataC = "toolsmetadata"
 
+	// statusesHistoryC holds the recent status transitions of units
+	// and machines.
+	statusesHistoryC = "statuseshistory"
+
+	// logsC holds the structured log records sent by agents.
+	logsC = "logs"
+
+	// auditC is the capped collection used to record the audit log.
+	auditC = "audit"
+
 	// These collections are used by the mgo transaction runner.
 	txnLogC = "txns.log"
 	txnsC   = "txns"
@@ -166,6 +180,23 @@ func (st *State) getPresence() *mgo.Collection {
 	return st.db.Session.DB("presence").C(presenceC)
 }
 
+// NewPresenceRecorder returns a presence.Recorder through which
+// the API server identified by tag reports the agents connected
+// to it as alive.
+func (st *State) NewPresenceRecorder(tag names.Tag) (*presence.Recorder, error) {
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// Each recorder is distinct even when the same server restarts.
+	server := fmt.Sprintf("%s-%s", tag, uuid)
+	r, err := presence.NewRecorder(st.getPresence(), server)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot start presence recorder")
+	}
+	return r, nil
+}
+
 // newDB returns a database connection using a new session, along with
 // a closer function for the session. This is useful where you need to work
 // with various collections in a single session, so don't want to call
@@ -230,6 +261,16 @@ func (st *State) ResumeTransactions() error {
 	return st.txnRunner(session).ResumeTransactions()
 }
 
+// PendingTransactionCount returns the number of transactions that
+// have been neither applied nor aborted.
+func (st *State) PendingTransactionCount() (int, error) {
+	txns, closer := st.getCollection(txnsC)
+	defer closer()
+	// Transactions in states below aborted (5) are still pending:
+	// preparing (1), prepared (2), aborting (3) and applying (4).
+	return txns.Find(bson.D{{"s", bson.D{{"$lt", 5}}}}).Count()
+}
+
 func (st *State) Watch() *Multiwatcher {
 	st.mu.Lock()
 	if st.allManager == nil {
@@ -1186,6 +1227,7 @@ func (st *State) AddService(name, owner string, ch *Charm, networks []string) (s
 		// and known before setting them.
 		createRequestedNetworksOp(st, svc.globalKey(), networks),
 		createSettingsOp(st, svc.settingsKey(), nil),
+		createLeadershipSettingsOp(st, name),
 		{
 			C:      settingsrefsC,
 			Id:     st.docID(svc.settingsKey()),
@@ -1299,6 +1341,7 @@ func (st *State) AddSubnet(args SubnetInfo) (subnet *Subnet, err error) {
 		AllocatableIPHigh: args.AllocatableIPHigh,
 		AllocatableIPLow:  args.AllocatableIPLow,
 		AvailabilityZone:  args.AvailabilityZone,
+		SpaceName:         args.SpaceName,
 	}
 	subnet = &Subnet{doc: subDoc, st: st}
 	err = subnet.Validate()
@@ -1311,15 +1354,23 @@ func (st *State) AddSubnet(args SubnetInfo) (subnet *Subnet, err error) {
 		Assert: txn.DocMissing,
 		Insert: subDoc,
 	}}
+	if args.SpaceName != "" {
+		ops = append(ops, txn.Op{
+			C:      spacesC,
+			Id:     st.docID(args.SpaceName),
+			Assert: isAliveDoc,
+		})
+	}
 
 	err = st.runTransaction(ops)
 	switch err {
 	case txn.ErrAborted:
 		if _, err = st.Subnet(args.CIDR); err == nil {
 			return nil, errors.AlreadyExistsf("subnet %q", args.CIDR)
-		} else if err != nil {
+		} else if !errors.IsNotFound(err) || args.SpaceName == "" {
 			return nil, errors.Trace(err)
 		}
+		return nil, errors.NotFoundf("space %q", args.SpaceName)
 	case nil:
 		// if the ProviderId was not unique adding the subnet can fail
 		// without an error. Refreshing catches this
@@ -1332,6 +1383,7 @@ func (st *State) AddSubnet(args SubnetInfo) (subnet *Subnet, err error) {
 	return nil, errors.Trace(err)
 }
 
+// Subnet returns the subnet with the given CIDR.
 func (st *State) Subnet(cidr string) (*Subnet, error) {
 	subnets, closer := st.getCollection(subnetsC)
 	defer closer()
@@ -1867,6 +1919,7 @@ type stateServersDoc struct {
 	EnvUUID          string `bson:"env-uuid"`
 	MachineIds       []string
 	VotingMachineIds []string
+	Degraded         bool `bson:"degraded,omitempty"`
 }
 
 // StateServerInfo holds information about currently
@@ -1886,6 +1939,10 @@ type StateServerInfo struct {
 	// configured to run a state server and to have a vote
 	// in peer election.
 	VotingMachineIds []string
+
+	// Degraded reports whether the voting state servers would
+	// lose their quorum if a single availability zone failed.
+	Degraded bool
 }
 
 // StateServerInfo returns information about
@@ -1929,9 +1986,25 @@ func (st *State) StateServerInfo() (*StateServerInfo, error) {
 		EnvironmentTag:   names.NewEnvironTag(doc.EnvUUID),
 		MachineIds:       doc.MachineIds,
 		VotingMachineIds: doc.VotingMachineIds,
+		Degraded:         doc.Degraded,
 	}, nil
 }
 
+// SetStateServersDegraded records whether the voting state servers
+// would lose their quorum if a single availability zone failed.
+func (st *State) SetStateServersDegraded(degraded bool) error {
+	ops := []txn.Op{{
+		C:      stateServersC,
+		Id:     environGlobalKey,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"degraded", degraded}}}},
+	}}
+	if err := st.runTransaction(ops); err != nil {
+		return errors.Annotate(err, "cannot set state server availability")
+	}
+	return nil
+}
+
 const stateServingInfoKey = "stateServingInfo"
 
 // StateServingInfo returns information for running a state server machine
@@ -3032,6 +3032,24 @@ func (s *StateSuite) TestStateServerInfoWithPreMigrationDoc(c *gc.C) {
 	c.Assert(ids.EnvironmentTag, gc.Equals, s.envTag)
 }
 
+func (s *StateSuite) TestSetStateServersDegraded(c *gc.C) {
+	info, err := s.State.StateServerInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Degraded, jc.IsFalse)
+
+	err = s.State.SetStateServersDegraded(true)
+	c.Assert(err, jc.ErrorIsNil)
+	info, err = s.State.StateServerInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Degraded, jc.IsTrue)
+
+	err = s.State.SetStateServersDegraded(false)
+	c.Assert(err, jc.ErrorIsNil)
+	info, err = s.State.StateServerInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Degraded, jc.IsFalse)
+}
+
 func (s *StateSuite) TestReopenWithNoMachines(c *gc.C) {
 	expected := &state.StateServerInfo{
 		EnvironmentTag: s.envTag,
@@ -3710,3 +3728,18 @@ func (s *SetAdminMongoPasswordSuite) TestSetAdminMongoPassword(c *gc.C) {
 	err = tryOpenState(mongoInfo)
 	c.Assert(err, jc.ErrorIsNil)
 }
+
+func (s *StateSuite) TestPendingTransactionCount(c *gc.C) {
+	count, err := s.State.PendingTransactionCount()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, gc.Equals, 0)
+
+	// Add a transaction that has been prepared but not applied.
+	txns := s.Session.DB("juju").C("txns")
+	err = txns.Insert(bson.D{{"_id", bson.NewObjectId()}, {"s", 2}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	count, err = s.State.PendingTransactionCount()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, gc.Equals, 1)
+}
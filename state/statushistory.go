@@ -0,0 +1,151 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// The status history of each entity is pruned whenever a new entry
+// is recorded, so that it holds at most statusHistoryMaxEntries
+// entries, none of them older than statusHistoryMaxAge.
+var (
+	statusHistoryMaxEntries = 100
+	statusHistoryMaxAge     = 7 * 24 * time.Hour
+)
+
+// StatusInfo holds a status of an entity as recorded in its status
+// history.
+type StatusInfo struct {
+	Status  Status
+	Info    string
+	Data    map[string]interface{}
+	Updated time.Time
+}
+
+// StatusHistoryFilter restricts the entries returned by StatusHistory.
+// Zero-valued fields do not restrict the results.
+type StatusHistoryFilter struct {
+	// Size holds the maximum number of entries to return. When more
+	// entries match, the most recent ones are returned.
+	Size int
+
+	// Before restricts the results to entries recorded strictly
+	// before the given time. It can be set to the time of the
+	// oldest entry previously returned to page back through the
+	// history.
+	Before time.Time
+}
+
+// historicalStatusDoc records a status of an entity at a point in
+// time. Status history entries are only ever inserted and pruned, so
+// they are written directly rather than through transactions.
+type historicalStatusDoc struct {
+	Id         bson.ObjectId          `bson:"_id"`
+	EnvUUID    string                 `bson:"env-uuid"`
+	EntityId   string                 `bson:"entityid"`
+	Status     Status                 `bson:"status"`
+	StatusInfo string                 `bson:"statusinfo"`
+	StatusData map[string]interface{} `bson:"statusdata,omitempty"`
+	Updated    time.Time              `bson:"updated"`
+}
+
+func (doc *historicalStatusDoc) statusInfo() StatusInfo {
+	return StatusInfo{
+		Status:  doc.Status,
+		Info:    doc.StatusInfo,
+		Data:    doc.StatusData,
+		Updated: doc.Updated,
+	}
+}
+
+// recordStatusHistory appends the given status to the status history
+// of the entity with the given global key, and prunes the entity's
+// history of entries that are too old or too many.
+func recordStatusHistory(st *State, globalKey string, doc statusDoc) error {
+	hdoc := historicalStatusDoc{
+		Id:         bson.NewObjectId(),
+		EnvUUID:    st.EnvironUUID(),
+		EntityId:   globalKey,
+		Status:     doc.Status,
+		StatusInfo: doc.StatusInfo,
+		StatusData: doc.StatusData,
+		Updated:    time.Now().UTC(),
+	}
+	history, closer := st.getCollection(statusesHistoryC)
+	defer closer()
+	if err := history.Insert(&hdoc); err != nil {
+		return errors.Annotatef(err, "cannot record status history of %q", globalKey)
+	}
+	if err := pruneStatusHistory(st, globalKey); err != nil {
+		return errors.Annotatef(err, "cannot prune status history of %q", globalKey)
+	}
+	return nil
+}
+
+// pruneStatusHistory removes the entries in the status history of the
+// entity with the given global key that are older than
+// statusHistoryMaxAge, or that exceed statusHistoryMaxEntries.
+func pruneStatusHistory(st *State, globalKey string) error {
+	history, closer := st.getCollection(statusesHistoryC)
+	defer closer()
+
+	cutoff := time.Now().Add(-statusHistoryMaxAge).UTC()
+	_, err := history.RemoveAll(bson.D{
+		{"entityid", globalKey},
+		{"updated", bson.D{{"$lt", cutoff}}},
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	var excess []struct {
+		Id bson.ObjectId `bson:"_id"`
+	}
+	err = history.Find(bson.D{{"entityid", globalKey}}).
+		Sort("-updated", "-_id").
+		Skip(statusHistoryMaxEntries).
+		Select(bson.D{{"_id", 1}}).
+		All(&excess)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(excess) == 0 {
+		return nil
+	}
+	ids := make([]bson.ObjectId, len(excess))
+	for i, doc := range excess {
+		ids[i] = doc.Id
+	}
+	_, err = history.RemoveAll(bson.D{{"_id", bson.D{{"$in", ids}}}})
+	return errors.Trace(err)
+}
+
+// statusHistory returns the status history of the entity with the
+// given global key that matches the given filter, newest first.
+func statusHistory(st *State, globalKey string, filter StatusHistoryFilter) ([]StatusInfo, error) {
+	query := bson.D{{"entityid", globalKey}}
+	if !filter.Before.IsZero() {
+		query = append(query, bson.DocElem{"updated", bson.D{{"$lt", filter.Before.UTC()}}})
+	}
+
+	history, closer := st.getCollection(statusesHistoryC)
+	defer closer()
+	q := history.Find(query).Sort("-updated", "-_id")
+	if filter.Size > 0 {
+		q = q.Limit(filter.Size)
+	}
+	var docs []historicalStatusDoc
+	if err := q.All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get status history of %q", globalKey)
+	}
+	results := make([]StatusInfo, len(docs))
+	for i, doc := range docs {
+		results[i] = doc.statusInfo()
+	}
+	return results, nil
+}
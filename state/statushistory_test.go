@@ -0,0 +1,249 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/state"
+)
+
+type StatusHistorySuite struct {
+	ConnSuite
+	unit    *state.Unit
+	machine *state.Machine
+}
+
+var _ = gc.Suite(&StatusHistorySuite{})
+
+func (s *StatusHistorySuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	service := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	var err error
+	s.unit, err = service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	s.machine, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *StatusHistorySuite) setUnitStatuses(c *gc.C, statuses ...state.Status) {
+	for _, status := range statuses {
+		var info string
+		if status == state.StatusError {
+			info = "hook failed"
+		}
+		err := s.unit.SetStatus(status, info, nil)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func statuses(history []state.StatusInfo) []state.Status {
+	result := make([]state.Status, len(history))
+	for i, info := range history {
+		result[i] = info.Status
+	}
+	return result
+}
+
+func (s *StatusHistorySuite) TestInitiallyEmpty(c *gc.C) {
+	history, err := s.unit.StatusHistory(state.StatusHistoryFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 0)
+}
+
+func (s *StatusHistorySuite) TestUnitStatusHistory(c *gc.C) {
+	s.setUnitStatuses(c,
+		state.StatusInstalled,
+		state.StatusStarted,
+		state.StatusError,
+		state.StatusStarted,
+	)
+	history, err := s.unit.StatusHistory(state.StatusHistoryFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statuses(history), jc.DeepEquals, []state.Status{
+		state.StatusStarted,
+		state.StatusError,
+		state.StatusStarted,
+		state.StatusInstalled,
+	})
+	c.Assert(history[1].Info, gc.Equals, "hook failed")
+	for i := 1; i < len(history); i++ {
+		c.Assert(history[i].Updated.After(history[i-1].Updated), jc.IsFalse)
+	}
+}
+
+func (s *StatusHistorySuite) TestMachineStatusHistory(c *gc.C) {
+	err := s.machine.SetStatus(state.StatusStarted, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetStatus(state.StatusError, "cannot start instance", map[string]interface{}{"foo": "bar"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.machine.StatusHistory(state.StatusHistoryFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statuses(history), jc.DeepEquals, []state.Status{
+		state.StatusError,
+		state.StatusStarted,
+	})
+	c.Assert(history[0].Info, gc.Equals, "cannot start instance")
+	c.Assert(history[0].Data, jc.DeepEquals, map[string]interface{}{"foo": "bar"})
+
+	// The unit's history is kept separately.
+	history, err = s.unit.StatusHistory(state.StatusHistoryFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 0)
+}
+
+func (s *StatusHistorySuite) TestInvalidStatusNotRecorded(c *gc.C) {
+	err := s.unit.SetStatus(state.StatusDown, "", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot set status "down"`)
+	history, err := s.unit.StatusHistory(state.StatusHistoryFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 0)
+}
+
+func (s *StatusHistorySuite) TestSize(c *gc.C) {
+	s.setUnitStatuses(c,
+		state.StatusInstalled,
+		state.StatusStarted,
+		state.StatusStopped,
+	)
+	history, err := s.unit.StatusHistory(state.StatusHistoryFilter{Size: 2})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statuses(history), jc.DeepEquals, []state.Status{
+		state.StatusStopped,
+		state.StatusStarted,
+	})
+}
+
+func (s *StatusHistorySuite) TestBefore(c *gc.C) {
+	s.addHistory(c, "u#wordpress/0", state.StatusInstalled, time.Hour)
+	s.addHistory(c, "u#wordpress/0", state.StatusStarted, 30*time.Minute)
+	s.setUnitStatuses(c, state.StatusStopped)
+
+	history, err := s.unit.StatusHistory(state.StatusHistoryFilter{Size: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statuses(history), jc.DeepEquals, []state.Status{state.StatusStopped})
+
+	history, err = s.unit.StatusHistory(state.StatusHistoryFilter{
+		Before: history[0].Updated,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statuses(history), jc.DeepEquals, []state.Status{
+		state.StatusStarted,
+		state.StatusInstalled,
+	})
+}
+
+func (s *StatusHistorySuite) TestPruneByEntries(c *gc.C) {
+	s.PatchValue(state.StatusHistoryMaxEntries, 3)
+	s.setUnitStatuses(c,
+		state.StatusInstalled,
+		state.StatusStarted,
+		state.StatusStopped,
+		state.StatusStarted,
+		state.StatusError,
+	)
+	history, err := s.unit.StatusHistory(state.StatusHistoryFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statuses(history), jc.DeepEquals, []state.Status{
+		state.StatusError,
+		state.StatusStarted,
+		state.StatusStopped,
+	})
+}
+
+func (s *StatusHistorySuite) TestPruneByAge(c *gc.C) {
+	s.PatchValue(state.StatusHistoryMaxAge, 2*time.Hour)
+	s.addHistory(c, "u#wordpress/0", state.StatusInstalled, 3*time.Hour)
+	s.addHistory(c, "u#wordpress/0", state.StatusStarted, time.Hour)
+	s.setUnitStatuses(c, state.StatusStopped)
+
+	history, err := s.unit.StatusHistory(state.StatusHistoryFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statuses(history), jc.DeepEquals, []state.Status{
+		state.StatusStopped,
+		state.StatusStarted,
+	})
+}
+
+func (s *StatusHistorySuite) TestBeforeWithinSameSecond(c *gc.C) {
+	now := time.Now().UTC().Truncate(time.Second)
+	s.addHistoryAt(c, "u#wordpress/0", state.StatusInstalled, now.Add(100*time.Millisecond))
+	s.addHistoryAt(c, "u#wordpress/0", state.StatusStarted, now.Add(200*time.Millisecond))
+
+	history, err := s.unit.StatusHistory(state.StatusHistoryFilter{Size: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statuses(history), jc.DeepEquals, []state.Status{state.StatusStarted})
+
+	history, err = s.unit.StatusHistory(state.StatusHistoryFilter{
+		Before: history[0].Updated,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statuses(history), jc.DeepEquals, []state.Status{state.StatusInstalled})
+}
+
+func (s *StatusHistorySuite) TestUnitHistoryRemovedWithUnit(c *gc.C) {
+	s.setUnitStatuses(c, state.StatusInstalled, state.StatusStarted)
+	s.assertHistoryCount(c, "u#wordpress/0", 2)
+
+	err := s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertHistoryCount(c, "u#wordpress/0", 0)
+}
+
+func (s *StatusHistorySuite) TestMachineHistoryRemovedWithMachine(c *gc.C) {
+	err := s.machine.SetStatus(state.StatusStarted, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.setUnitStatuses(c, state.StatusInstalled)
+	s.assertHistoryCount(c, "m#"+s.machine.Id(), 1)
+
+	err = s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertHistoryCount(c, "m#"+s.machine.Id(), 0)
+
+	// Other entities' history is left alone.
+	s.assertHistoryCount(c, "u#wordpress/0", 1)
+}
+
+func (s *StatusHistorySuite) assertHistoryCount(c *gc.C, globalKey string, expect int) {
+	history, closer := state.GetCollection(s.State, "statuseshistory")
+	defer closer()
+	count, err := history.Find(bson.D{{"entityid", globalKey}}).Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, gc.Equals, expect)
+}
+
+// addHistory records a status history entry for the entity with the
+// given global key, as if it had been recorded the given time ago.
+func (s *StatusHistorySuite) addHistory(c *gc.C, globalKey string, status state.Status, age time.Duration) {
+	s.addHistoryAt(c, globalKey, status, time.Now().Add(-age))
+}
+
+// addHistoryAt records a status history entry for the entity with the
+// given global key, as if it had been recorded at the given time.
+func (s *StatusHistorySuite) addHistoryAt(c *gc.C, globalKey string, status state.Status, when time.Time) {
+	history, closer := state.GetCollection(s.State, "statuseshistory")
+	defer closer()
+	err := history.Insert(bson.M{
+		"_id":        bson.NewObjectId(),
+		"env-uuid":   s.State.EnvironUUID(),
+		"entityid":   globalKey,
+		"status":     status,
+		"statusinfo": "",
+		"updated":    when.UTC(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
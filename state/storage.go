@@ -0,0 +1,332 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/storage"
+)
+
+// StorageInstance represents the state of a unit's storage instance,
+// created according to one of its service's storage directives.
+type StorageInstance struct {
+	st  *State
+	doc storageInstanceDoc
+}
+
+// storageInstanceDoc records a storage instance owned by a unit. The
+// location and filesystem are recorded once the machine agent of the
+// unit's machine has provisioned the storage.
+type storageInstanceDoc struct {
+	DocID      string         `bson:"_id"`
+	Id         string         `bson:"id"`
+	EnvUUID    string         `bson:"env-uuid"`
+	Owner      string         `bson:"owner"`
+	Name       string         `bson:"name"`
+	Source     string         `bson:"source"`
+	Size       uint64         `bson:"size"`
+	Options    string         `bson:"options,omitempty"`
+	Location   string         `bson:"location,omitempty"`
+	Filesystem *filesystemDoc `bson:"filesystem,omitempty"`
+}
+
+// filesystemDoc records the filesystem created for a storage instance.
+type filesystemDoc struct {
+	Type         string   `bson:"type"`
+	MountOptions []string `bson:"mountoptions,omitempty"`
+}
+
+// storageDirectiveDoc records a storage directive of a service.
+type storageDirectiveDoc struct {
+	Name    string `bson:"name"`
+	Source  string `bson:"source"`
+	Count   int    `bson:"count"`
+	Size    uint64 `bson:"size"`
+	Options string `bson:"options,omitempty"`
+}
+
+func newStorageInstance(st *State, doc *storageInstanceDoc) *StorageInstance {
+	return &StorageInstance{st: st, doc: *doc}
+}
+
+// Id returns the unique identifier of the storage instance, in the
+// form <storage name>/<number>.
+func (s *StorageInstance) Id() string {
+	return s.doc.Id
+}
+
+// Name returns the name of the service storage directive that the
+// storage instance was created from.
+func (s *StorageInstance) Name() string {
+	return s.doc.Name
+}
+
+// Owner returns the name of the unit that owns the storage instance.
+func (s *StorageInstance) Owner() string {
+	return s.doc.Owner
+}
+
+// Source returns the storage source of the storage instance.
+func (s *StorageInstance) Source() string {
+	return s.doc.Source
+}
+
+// Size returns the requested size of the storage instance in MiB.
+func (s *StorageInstance) Size() uint64 {
+	return s.doc.Size
+}
+
+// Options returns the source-specific options of the storage instance.
+func (s *StorageInstance) Options() string {
+	return s.doc.Options
+}
+
+// Location returns the path at which the storage instance is mounted
+// on its unit's machine, or "" if it has not yet been provisioned.
+func (s *StorageInstance) Location() string {
+	return s.doc.Location
+}
+
+// Filesystem returns the filesystem created for the storage instance,
+// or nil if it has not yet been provisioned.
+func (s *StorageInstance) Filesystem() *storage.Filesystem {
+	if s.doc.Filesystem == nil {
+		return nil
+	}
+	return &storage.Filesystem{
+		Type:         s.doc.Filesystem.Type,
+		MountOptions: s.doc.Filesystem.MountOptions,
+	}
+}
+
+// Provisioned reports whether the storage instance has been provisioned.
+func (s *StorageInstance) Provisioned() bool {
+	return s.doc.Location != ""
+}
+
+// Refresh refreshes the contents of the storage instance from the
+// underlying state. It returns an error that satisfies
+// errors.IsNotFound if the storage instance has been removed.
+func (s *StorageInstance) Refresh() error {
+	doc, err := getStorageInstanceDoc(s.st, s.doc.Id)
+	if err != nil {
+		return err
+	}
+	s.doc = *doc
+	return nil
+}
+
+// SetProvisioned records the location at which the storage instance
+// is mounted, and the filesystem that was created for it.
+func (s *StorageInstance) SetProvisioned(location string, fs storage.Filesystem) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set storage instance %q provisioned", s.doc.Id)
+	if location == "" {
+		return errors.New("empty location")
+	}
+	fsdoc := &filesystemDoc{
+		Type:         fs.Type,
+		MountOptions: fs.MountOptions,
+	}
+	ops := []txn.Op{{
+		C:      storageInstancesC,
+		Id:     s.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{
+			{"location", location},
+			{"filesystem", fsdoc},
+		}}},
+	}}
+	if err := s.st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("storage instance %q", s.doc.Id)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	s.doc.Location = location
+	s.doc.Filesystem = fsdoc
+	return nil
+}
+
+func getStorageInstanceDoc(st *State, id string) (*storageInstanceDoc, error) {
+	coll, closer := st.getCollection(storageInstancesC)
+	defer closer()
+
+	var doc storageInstanceDoc
+	err := coll.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("storage instance %q", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get storage instance %q", id)
+	}
+	return &doc, nil
+}
+
+// StorageInstance returns the storage instance with the given id.
+func (st *State) StorageInstance(id string) (*StorageInstance, error) {
+	doc, err := getStorageInstanceDoc(st, id)
+	if err != nil {
+		return nil, err
+	}
+	return newStorageInstance(st, doc), nil
+}
+
+// AllStorageInstances returns all storage instances in the environment.
+func (st *State) AllStorageInstances() ([]*StorageInstance, error) {
+	return storageInstances(st, nil)
+}
+
+// StorageInstances returns the storage instances owned by the unit.
+func (u *Unit) StorageInstances() ([]*StorageInstance, error) {
+	return storageInstances(u.st, bson.D{{"owner", u.doc.Name}})
+}
+
+// StorageInstances returns the storage instances owned by the units
+// assigned to the machine.
+func (m *Machine) StorageInstances() ([]*StorageInstance, error) {
+	units, err := m.Units()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(units) == 0 {
+		return nil, nil
+	}
+	unitNames := make([]string, len(units))
+	for i, unit := range units {
+		unitNames[i] = unit.Name()
+	}
+	return storageInstances(m.st, bson.D{{"owner", bson.D{{"$in", unitNames}}}})
+}
+
+func storageInstances(st *State, query bson.D) ([]*StorageInstance, error) {
+	coll, closer := st.getCollection(storageInstancesC)
+	defer closer()
+
+	var docs []storageInstanceDoc
+	if err := coll.Find(query).Sort("id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get storage instances")
+	}
+	instances := make([]*StorageInstance, len(docs))
+	for i := range docs {
+		instances[i] = newStorageInstance(st, &docs[i])
+	}
+	return instances, nil
+}
+
+// createStorageInstancesOps returns the txn operations necessary to
+// create the storage instances of a new unit of a service with the
+// given storage directives.
+func createStorageInstancesOps(st *State, unitName string, directives []storageDirectiveDoc) ([]txn.Op, error) {
+	var ops []txn.Op
+	for _, directive := range directives {
+		for i := 0; i < directive.Count; i++ {
+			seq, err := st.sequence("storage-" + directive.Name)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			id := fmt.Sprintf("%s/%d", directive.Name, seq)
+			docID := st.docID(id)
+			ops = append(ops, txn.Op{
+				C:      storageInstancesC,
+				Id:     docID,
+				Assert: txn.DocMissing,
+				Insert: &storageInstanceDoc{
+					DocID:   docID,
+					Id:      id,
+					EnvUUID: st.EnvironUUID(),
+					Owner:   unitName,
+					Name:    directive.Name,
+					Source:  directive.Source,
+					Size:    directive.Size,
+					Options: directive.Options,
+				},
+			})
+		}
+	}
+	return ops, nil
+}
+
+// removeStorageInstancesOps returns the txn operations necessary to
+// remove the storage instances owned by the unit with the given name.
+func removeStorageInstancesOps(st *State, unitName string) ([]txn.Op, error) {
+	coll, closer := st.getCollection(storageInstancesC)
+	defer closer()
+
+	var docs []struct {
+		DocID string `bson:"_id"`
+	}
+	err := coll.Find(bson.D{{"owner", unitName}}).Select(bson.D{{"_id", 1}}).All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get storage instances of unit %q", unitName)
+	}
+	ops := make([]txn.Op, len(docs))
+	for i, doc := range docs {
+		ops[i] = txn.Op{
+			C:      storageInstancesC,
+			Id:     doc.DocID,
+			Remove: true,
+		}
+	}
+	return ops, nil
+}
+
+// validateStorageDirectives checks that the given storage directives
+// are complete, and that no two of them share a name.
+func validateStorageDirectives(directives []storage.Directive) error {
+	names := make(map[string]bool)
+	for _, directive := range directives {
+		if directive.Name == "" {
+			return errors.New("storage name missing")
+		}
+		if directive.Source == "" {
+			return errors.Errorf("storage %q: %v", directive.Name, storage.ErrStorageSourceMissing)
+		}
+		if directive.Count < 0 {
+			return errors.Errorf("storage %q: count must not be negative", directive.Name)
+		}
+		if names[directive.Name] {
+			return errors.Errorf("storage %q specified more than once", directive.Name)
+		}
+		names[directive.Name] = true
+	}
+	return nil
+}
+
+func toStorageDirectiveDocs(directives []storage.Directive) []storageDirectiveDoc {
+	if len(directives) == 0 {
+		return nil
+	}
+	docs := make([]storageDirectiveDoc, len(directives))
+	for i, directive := range directives {
+		docs[i] = storageDirectiveDoc{
+			Name:    directive.Name,
+			Source:  directive.Source,
+			Count:   directive.Count,
+			Size:    directive.Size,
+			Options: directive.Options,
+		}
+	}
+	return docs
+}
+
+func fromStorageDirectiveDocs(docs []storageDirectiveDoc) []storage.Directive {
+	if len(docs) == 0 {
+		return nil
+	}
+	directives := make([]storage.Directive, len(docs))
+	for i, doc := range docs {
+		directives[i] = storage.Directive{
+			Name:    doc.Name,
+			Source:  doc.Source,
+			Count:   doc.Count,
+			Size:    doc.Size,
+			Options: doc.Options,
+		}
+	}
+	return directives
+}
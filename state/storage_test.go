@@ -0,0 +1,185 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/storage"
+)
+
+type StorageSuite struct {
+	ConnSuite
+	service *state.Service
+}
+
+var _ = gc.Suite(&StorageSuite{})
+
+func (s *StorageSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.service = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+}
+
+func storageInstanceIds(instances []*state.StorageInstance) []string {
+	ids := make([]string, len(instances))
+	for i, instance := range instances {
+		ids[i] = instance.Id()
+	}
+	return ids
+}
+
+func (s *StorageSuite) TestSetStorageDirectives(c *gc.C) {
+	c.Assert(s.service.StorageDirectives(), gc.HasLen, 0)
+	directives := []storage.Directive{
+		{Name: "data", Source: storage.LoopSource, Count: 2, Size: 1024},
+		{Name: "logs", Source: storage.LoopSource, Count: 1, Size: 512, Options: "filesystem=xfs"},
+	}
+	err := s.service.SetStorageDirectives(directives)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.StorageDirectives(), jc.DeepEquals, directives)
+
+	service, err := s.State.Service(s.service.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.StorageDirectives(), jc.DeepEquals, directives)
+}
+
+func (s *StorageSuite) TestSetStorageDirectivesInvalid(c *gc.C) {
+	for i, test := range []struct {
+		directives []storage.Directive
+		err        string
+	}{{
+		directives: []storage.Directive{{Source: storage.LoopSource, Count: 1}},
+		err:        "cannot set storage directives: storage name missing",
+	}, {
+		directives: []storage.Directive{{Name: "data", Count: 1}},
+		err:        `cannot set storage directives: storage "data": storage source missing`,
+	}, {
+		directives: []storage.Directive{{Name: "data", Source: storage.LoopSource, Count: -1}},
+		err:        `cannot set storage directives: storage "data": count must not be negative`,
+	}, {
+		directives: []storage.Directive{
+			{Name: "data", Source: storage.LoopSource, Count: 1},
+			{Name: "data", Source: storage.LoopSource, Count: 2},
+		},
+		err: `cannot set storage directives: storage "data" specified more than once`,
+	}} {
+		c.Logf("test %d", i)
+		err := s.service.SetStorageDirectives(test.directives)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *StorageSuite) TestSetStorageDirectivesWithUnits(c *gc.C) {
+	_, err := s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.SetStorageDirectives([]storage.Directive{
+		{Name: "data", Source: storage.LoopSource, Count: 1, Size: 1024},
+	})
+	c.Assert(err, gc.ErrorMatches, "cannot set storage directives: service already has units")
+}
+
+func (s *StorageSuite) TestAddUnitCreatesStorageInstances(c *gc.C) {
+	err := s.service.SetStorageDirectives([]storage.Directive{
+		{Name: "data", Source: storage.LoopSource, Count: 2, Size: 1024},
+		{Name: "logs", Source: storage.LoopSource, Count: 1, Size: 512},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	unit0, err := s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	unit1, err := s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	instances, err := unit0.StorageInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(storageInstanceIds(instances), jc.DeepEquals, []string{"data/0", "data/1", "logs/0"})
+	instance := instances[0]
+	c.Assert(instance.Name(), gc.Equals, "data")
+	c.Assert(instance.Owner(), gc.Equals, "wordpress/0")
+	c.Assert(instance.Source(), gc.Equals, storage.LoopSource)
+	c.Assert(instance.Size(), gc.Equals, uint64(1024))
+	c.Assert(instance.Provisioned(), jc.IsFalse)
+	c.Assert(instance.Filesystem(), gc.IsNil)
+
+	instances, err = unit1.StorageInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(storageInstanceIds(instances), jc.DeepEquals, []string{"data/2", "data/3", "logs/1"})
+
+	instances, err = s.State.AllStorageInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instances, gc.HasLen, 6)
+}
+
+func (s *StorageSuite) TestMachineStorageInstances(c *gc.C) {
+	err := s.service.SetStorageDirectives([]storage.Directive{
+		{Name: "data", Source: storage.LoopSource, Count: 1, Size: 1024},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	instances, err := machine.StorageInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instances, gc.HasLen, 0)
+
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	instances, err = machine.StorageInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(storageInstanceIds(instances), jc.DeepEquals, []string{"data/0"})
+}
+
+func (s *StorageSuite) TestSetProvisioned(c *gc.C) {
+	err := s.service.SetStorageDirectives([]storage.Directive{
+		{Name: "data", Source: storage.LoopSource, Count: 1, Size: 1024},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	instance, err := s.State.StorageInstance("data/0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = instance.SetProvisioned("", storage.Filesystem{Type: "ext4"})
+	c.Assert(err, gc.ErrorMatches, `cannot set storage instance "data/0" provisioned: empty location`)
+
+	fs := storage.Filesystem{Type: "ext4", MountOptions: []string{"noatime"}}
+	err = instance.SetProvisioned("/srv/data", fs)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instance.Provisioned(), jc.IsTrue)
+
+	instance, err = s.State.StorageInstance("data/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instance.Location(), gc.Equals, "/srv/data")
+	c.Assert(instance.Filesystem(), jc.DeepEquals, &fs)
+}
+
+func (s *StorageSuite) TestRemoveUnitRemovesStorageInstances(c *gc.C) {
+	err := s.service.SetStorageDirectives([]storage.Directive{
+		{Name: "data", Source: storage.LoopSource, Count: 1, Size: 1024},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	instance, err := s.State.StorageInstance("data/0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	err = instance.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *StorageSuite) TestStorageInstanceNotFound(c *gc.C) {
+	_, err := s.State.StorageInstance("data/0")
+	c.Assert(err, gc.ErrorMatches, `storage instance "data/0" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -35,6 +35,10 @@ type SubnetInfo struct {
 	// AvailabilityZone describes which availability zone this subnet is in. It can
 	// be empty if the provider does not support availability zones.
 	AvailabilityZone string
+
+	// SpaceName is the name of the space the subnet belongs to. It can
+	// be empty if the subnet is not part of any space.
+	SpaceName string
 }
 
 type Subnet struct {
@@ -53,6 +57,7 @@ type subnetDoc struct {
 
 	VLANTag          int    `bson:",omitempty"`
 	AvailabilityZone string `bson:",omitempty"`
+	SpaceName        string `bson:",omitempty"`
 }
 
 // Life returns whether the subnet is Alive, Dying or Dead.
@@ -151,6 +156,12 @@ func (s *Subnet) AvailabilityZone() string {
 	return s.doc.AvailabilityZone
 }
 
+// SpaceName returns the name of the space the subnet belongs to, or
+// the empty string if the subnet is not part of any space.
+func (s *Subnet) SpaceName() string {
+	return s.doc.SpaceName
+}
+
 // Validate validates the subnet, checking the CIDR, VLANTag and
 // AllocatableIPHigh and Low, if present.
 func (s *Subnet) Validate() error {
@@ -209,3 +220,23 @@ func (s *Subnet) Refresh() error {
 	}
 	return nil
 }
+
+// AllSubnets returns all subnets in the environment, ordered by CIDR.
+func (st *State) AllSubnets() ([]*Subnet, error) {
+	return subnets(st, nil)
+}
+
+func subnets(st *State, query bson.D) ([]*Subnet, error) {
+	subnetsColl, closer := st.getCollection(subnetsC)
+	defer closer()
+
+	var docs []subnetDoc
+	if err := subnetsColl.Find(query).Sort("cidr").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get subnets")
+	}
+	result := make([]*Subnet, len(docs))
+	for i, doc := range docs {
+		result[i] = &Subnet{st, doc}
+	}
+	return result, nil
+}
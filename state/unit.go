@@ -165,6 +165,22 @@ func (u *Unit) globalKey() string {
 	return unitGlobalKey(u.doc.Name)
 }
 
+// workloadStatusSuffix is appended to the global key of a unit to
+// form the global key of its workload status.
+const workloadStatusSuffix = "#workload"
+
+// unitWorkloadGlobalKey returns the global database key for the
+// workload status of the named unit.
+func unitWorkloadGlobalKey(name string) string {
+	return unitGlobalKey(name) + workloadStatusSuffix
+}
+
+// workloadGlobalKey returns the global database key for the workload
+// status of the unit.
+func (u *Unit) workloadGlobalKey() string {
+	return unitWorkloadGlobalKey(u.doc.Name)
+}
+
 // Life returns whether the unit is Alive, Dying or Dead.
 func (u *Unit) Life() Life {
 	return u.doc.Life
@@ -742,6 +758,72 @@ func (u *Unit) SetStatus(status Status, info string, data map[string]interface{}
 	if err != nil {
 		return fmt.Errorf("cannot set status of unit %q: %v", u, onAbort(err, ErrDead))
 	}
+	if err := recordStatusHistory(u.st, u.globalKey(), doc); err != nil {
+		logger.Warningf("%v", err)
+	}
+	return nil
+}
+
+// StatusHistory returns the recorded status transitions of the unit
+// that match the given filter, newest first.
+func (u *Unit) StatusHistory(filter StatusHistoryFilter) ([]StatusInfo, error) {
+	return statusHistory(u.st, u.globalKey(), filter)
+}
+
+// WorkloadStatus returns the status of the unit's workload, as last
+// reported by its charm. Units whose charm has not reported a status
+// have the StatusUnknown workload status.
+func (u *Unit) WorkloadStatus() (status Status, info string, err error) {
+	doc, err := getStatus(u.st, u.workloadGlobalKey())
+	if errors.IsNotFound(err) {
+		// Units created before workload statuses were introduced
+		// have no workload status document.
+		return StatusUnknown, "", nil
+	} else if err != nil {
+		return "", "", err
+	}
+	return doc.Status, doc.StatusInfo, nil
+}
+
+// SetWorkloadStatus sets the status of the unit's workload, along with
+// an optional message for the operator.
+func (u *Unit) SetWorkloadStatus(status Status, info string) error {
+	if status == StatusUnknown || !status.ValidWorkload() {
+		return errors.Errorf("cannot set invalid workload status %q", status)
+	}
+	doc := statusDoc{
+		EnvUUID:    u.st.EnvironUUID(),
+		Status:     status,
+		StatusInfo: info,
+	}
+	globalKey := u.workloadGlobalKey()
+	unit := &Unit{st: u.st, doc: u.doc}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := unit.Refresh(); errors.IsNotFound(err) {
+				return nil, ErrDead
+			} else if err != nil {
+				return nil, err
+			}
+			if unit.Life() == Dead {
+				return nil, ErrDead
+			}
+		}
+		statusOp := updateStatusOp(u.st, globalKey, doc)
+		if _, err := getStatus(u.st, globalKey); errors.IsNotFound(err) {
+			statusOp = createStatusOp(u.st, globalKey, doc)
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{{
+			C:      unitsC,
+			Id:     u.doc.DocID,
+			Assert: notDeadDoc,
+		}, statusOp}, nil
+	}
+	if err := u.st.run(buildTxn); err != nil {
+		return fmt.Errorf("cannot set workload status of unit %q: %v", u, err)
+	}
 	return nil
 }
 
@@ -964,6 +1046,17 @@ func (u *Unit) SetAgentPresence() (*presence.Pinger, error) {
 	return p, nil
 }
 
+// SetAgentConnected signals that the agent for unit u is alive
+// for as long as it remains connected to the API server that owns r.
+// It returns the recorded connection.
+func (u *Unit) SetAgentConnected(r *presence.Recorder) (*presence.Connection, error) {
+	conn, err := r.Connect(u.globalKey())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return conn, nil
+}
+
 // NotAssignedError indicates that a unit is not assigned to a machine (and, in
 // the case of subordinate units, that the unit's principal is not assigned).
 type NotAssignedError struct{ Unit *Unit }
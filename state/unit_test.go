@@ -579,6 +579,62 @@ func (s *UnitSuite) TestGetSetStatusWhileNotAlive(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "status not found")
 }
 
+func (s *UnitSuite) TestGetSetWorkloadStatus(c *gc.C) {
+	status, info, err := s.unit.WorkloadStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.StatusUnknown)
+	c.Assert(info, gc.Equals, "")
+
+	err = s.unit.SetWorkloadStatus(state.StatusUnknown, "")
+	c.Assert(err, gc.ErrorMatches, `cannot set invalid workload status "unknown"`)
+	err = s.unit.SetWorkloadStatus(state.StatusStarted, "")
+	c.Assert(err, gc.ErrorMatches, `cannot set invalid workload status "started"`)
+
+	err = s.unit.SetWorkloadStatus(state.StatusBlocked, "need a database")
+	c.Assert(err, jc.ErrorIsNil)
+	status, info, err = s.unit.WorkloadStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.StatusBlocked)
+	c.Assert(info, gc.Equals, "need a database")
+
+	err = s.unit.SetWorkloadStatus(state.StatusActive, "")
+	c.Assert(err, jc.ErrorIsNil)
+	status, info, err = s.unit.WorkloadStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.StatusActive)
+	c.Assert(info, gc.Equals, "")
+
+	// The agent status is unaffected.
+	status, _, _, err = s.unit.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.StatusPending)
+}
+
+func (s *UnitSuite) TestSetWorkloadStatusMissingDocument(c *gc.C) {
+	// Units created before workload statuses were introduced have
+	// no workload status document; it is created on first write.
+	err := state.RemoveWorkloadStatus(s.State, s.unit)
+	c.Assert(err, jc.ErrorIsNil)
+
+	status, _, err := s.unit.WorkloadStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.StatusUnknown)
+
+	err = s.unit.SetWorkloadStatus(state.StatusMaintenance, "installing")
+	c.Assert(err, jc.ErrorIsNil)
+	status, info, err := s.unit.WorkloadStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.StatusMaintenance)
+	c.Assert(info, gc.Equals, "installing")
+}
+
+func (s *UnitSuite) TestSetWorkloadStatusWhileDead(c *gc.C) {
+	err := s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.SetWorkloadStatus(state.StatusActive, "")
+	c.Assert(err, gc.ErrorMatches, `cannot set workload status of unit "wordpress/0": not found or dead`)
+}
+
 func (s *UnitSuite) TestGetSetStatusDataStandard(c *gc.C) {
 	err := s.unit.SetStatus(state.StatusStarted, "", nil)
 	c.Assert(err, jc.ErrorIsNil)
@@ -94,7 +94,7 @@ func AddStateUsersAsEnvironUsers(st *State) error {
 
 		_, err := st.EnvironmentUser(uTag)
 		if err != nil && errors.IsNotFound(err) {
-			_, err = st.AddEnvironmentUser(uTag, uTag)
+			_, err = st.AddEnvironmentUserWithAccess(uTag, uTag, EnvironmentAdminAccess)
 			if err != nil {
 				return errors.Trace(err)
 			}
@@ -159,6 +159,12 @@ func collFactory(st *State, collName string) func() (stateCollection, func()) {
 	}
 }
 
+// WatchEnvironments returns a StringsWatcher that notifies of changes
+// to the lifecycles of all the environments in the system.
+func (st *State) WatchEnvironments() StringsWatcher {
+	return newLifecycleWatcher(st, environmentsC, nil, nil)
+}
+
 // WatchServices returns a StringsWatcher that notifies of changes to
 // the lifecycles of the services in the environment.
 func (st *State) WatchServices() StringsWatcher {
@@ -16,6 +16,10 @@ const (
 	// storage service(s).
 	ProviderSource = "provider"
 
+	// LoopSource identifies loop devices backed by files on the
+	// machine to which the storage is attached.
+	LoopSource = "loop"
+
 	storageNameSnippet    = "(?:[a-z][a-z0-9]*(?:-[a-z0-9]+)*)"
 	storageSourceSnippet  = "(?:[a-z][a-z0-9]*)"
 	storageCountSnippet   = "-?[0-9]+"
@@ -5,6 +5,7 @@ package testing
 
 import (
 	"os"
+	"strings"
 
 	"github.com/juju/testing"
 	"github.com/juju/utils"
@@ -57,6 +58,13 @@ func (s *JujuOSEnvSuite) TearDownTest(c *gc.C) {
 	utils.SetHome(s.oldHomeEnv)
 }
 
+// SetFeatureFlags sets the given feature flags for the duration of
+// the test. The flags are reset by the next call to SetUpTest.
+func (s *JujuOSEnvSuite) SetFeatureFlags(flag ...string) {
+	os.Setenv(osenv.JujuFeatureFlagEnvKey, strings.Join(flag, ","))
+	featureflag.SetFlagsFromEnvironment(osenv.JujuFeatureFlagEnvKey)
+}
+
 // BaseSuite provides required functionality for all test suites
 // when embedded in a gocheck suite type:
 // - logger redirect
@@ -41,6 +41,7 @@ type UserParams struct {
 	Creator     names.Tag
 	NoEnvUser   bool
 	Disabled    bool
+	Access      state.EnvironmentAccess
 }
 
 // EnvUserParams defines the parameters for creating an environment user.
@@ -48,6 +49,7 @@ type EnvUserParams struct {
 	User        string
 	DisplayName string
 	CreatedBy   names.Tag
+	Access      state.EnvironmentAccess
 }
 
 // CharmParams defines the parameters for creating a charm.
@@ -147,7 +149,10 @@ func (factory *Factory) MakeUser(c *gc.C, params *UserParams) *state.User {
 		params.Name, params.DisplayName, params.Password, creatorUserTag.Name())
 	c.Assert(err, jc.ErrorIsNil)
 	if !params.NoEnvUser {
-		_, err := factory.st.AddEnvironmentUser(user.UserTag(), names.NewUserTag(user.CreatedBy()))
+		if params.Access == "" {
+			params.Access = state.EnvironmentWriteAccess
+		}
+		_, err := factory.st.AddEnvironmentUserWithAccess(user.UserTag(), names.NewUserTag(user.CreatedBy()), params.Access)
 		c.Assert(err, jc.ErrorIsNil)
 	}
 	if params.Disabled {
@@ -173,8 +178,11 @@ func (factory *Factory) MakeEnvUser(c *gc.C, params *EnvUserParams) *state.Envir
 		user := factory.MakeUser(c, nil)
 		params.CreatedBy = user.UserTag()
 	}
+	if params.Access == "" {
+		params.Access = state.EnvironmentWriteAccess
+	}
 	createdByUserTag := params.CreatedBy.(names.UserTag)
-	envUser, err := factory.st.AddEnvironmentUser(names.NewUserTag(params.User), createdByUserTag)
+	envUser, err := factory.st.AddEnvironmentUserWithAccess(names.NewUserTag(params.User), createdByUserTag, params.Access)
 	c.Assert(err, jc.ErrorIsNil)
 	return envUser
 }
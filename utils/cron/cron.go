@@ -0,0 +1,182 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package cron parses cron-like schedule specifications, as used by
+// crontab(5), and computes the times they describe.
+package cron
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// Schedule represents a parsed schedule specification.
+type Schedule struct {
+	spec   string
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+
+	// domStar and dowStar record whether the day of month and day
+	// of week fields were "*". When both are restricted, a day
+	// matches if either of them does, as in crontab(5).
+	domStar bool
+	dowStar bool
+}
+
+var shortcuts = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type fieldRange struct {
+	name     string
+	min, max int
+}
+
+var fieldRanges = []fieldRange{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// Parse parses a schedule specification made of five space-separated
+// fields: minute, hour, day of month, month and day of week. Each
+// field is "*" or a comma-separated list of values and ranges, as in
+// "1,15" or "1-5", which may be followed by a step, as in "*/15". The
+// day of week is given as 0 to 7, where both 0 and 7 are Sunday.
+// The shortcuts @yearly, @monthly, @weekly, @daily and @hourly are
+// also accepted.
+func Parse(spec string) (*Schedule, error) {
+	expanded := strings.TrimSpace(spec)
+	if shortcut, ok := shortcuts[expanded]; ok {
+		expanded = shortcut
+	}
+	fields := strings.Fields(expanded)
+	if len(fields) != len(fieldRanges) {
+		return nil, errors.Errorf("invalid schedule %q: expected 5 fields, got %d", spec, len(fields))
+	}
+	masks := make([]uint64, len(fields))
+	for i, field := range fields {
+		mask, err := parseField(field, fieldRanges[i])
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid schedule %q", spec)
+		}
+		masks[i] = mask
+	}
+	// Sunday may be given as 7.
+	if masks[4]&(1<<7) != 0 {
+		masks[4] |= 1
+	}
+	return &Schedule{
+		spec:    spec,
+		minute:  masks[0],
+		hour:    masks[1],
+		dom:     masks[2],
+		month:   masks[3],
+		dow:     masks[4],
+		domStar: fields[2] == "*",
+		dowStar: fields[4] == "*",
+	}, nil
+}
+
+func parseField(field string, r fieldRange) (uint64, error) {
+	var mask uint64
+	for _, part := range strings.Split(field, ",") {
+		lo, hi, step := r.min, r.max, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return 0, errors.Errorf("invalid step in %s %q", r.name, part)
+			}
+			step = n
+			part = part[:i]
+		}
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if lo, err = parseValue(bounds[0], r); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = parseValue(bounds[1], r); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				// "n/step" means from n to the maximum.
+				hi = r.max
+			}
+			if lo > hi {
+				return 0, errors.Errorf("invalid range in %s %q", r.name, part)
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			mask |= 1 << uint(v)
+		}
+	}
+	return mask, nil
+}
+
+func parseValue(s string, r fieldRange) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil || v < r.min || v > r.max {
+		return 0, errors.Errorf("invalid %s %q", r.name, s)
+	}
+	return v, nil
+}
+
+// String returns the specification the schedule was parsed from.
+func (s *Schedule) String() string {
+	return s.spec
+}
+
+// maxSearch bounds the search for the next matching time, so that
+// schedules that never match, such as "0 0 30 2 *", do not loop
+// forever.
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+// Next returns the first time strictly after t that matches the
+// schedule, in t's location, or the zero time if there is no such
+// time within the next five years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	limit := t.Add(maxSearch)
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	for !t.After(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
@@ -0,0 +1,103 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cron_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/utils/cron"
+)
+
+type cronSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&cronSuite{})
+
+// from is a Wednesday.
+var from = time.Date(2014, 11, 5, 10, 30, 0, 0, time.UTC)
+
+func (s *cronSuite) TestNext(c *gc.C) {
+	for i, test := range []struct {
+		spec     string
+		expected time.Time
+	}{{
+		spec:     "* * * * *",
+		expected: time.Date(2014, 11, 5, 10, 31, 0, 0, time.UTC),
+	}, {
+		spec:     "*/15 * * * *",
+		expected: time.Date(2014, 11, 5, 10, 45, 0, 0, time.UTC),
+	}, {
+		spec:     "30 10 * * *",
+		expected: time.Date(2014, 11, 6, 10, 30, 0, 0, time.UTC),
+	}, {
+		spec:     "0 3 * * *",
+		expected: time.Date(2014, 11, 6, 3, 0, 0, 0, time.UTC),
+	}, {
+		spec:     "@daily",
+		expected: time.Date(2014, 11, 6, 0, 0, 0, 0, time.UTC),
+	}, {
+		spec:     "@weekly",
+		expected: time.Date(2014, 11, 9, 0, 0, 0, 0, time.UTC),
+	}, {
+		spec:     "0 0 * * 7",
+		expected: time.Date(2014, 11, 9, 0, 0, 0, 0, time.UTC),
+	}, {
+		spec:     "0 12 * * 1-5",
+		expected: time.Date(2014, 11, 5, 12, 0, 0, 0, time.UTC),
+	}, {
+		spec:     "15,45 2 1 * *",
+		expected: time.Date(2014, 12, 1, 2, 15, 0, 0, time.UTC),
+	}, {
+		spec:     "0 0 1 1 *",
+		expected: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC),
+	}, {
+		// Either the day of month or the day of week may match.
+		spec:     "0 0 20 * 5",
+		expected: time.Date(2014, 11, 7, 0, 0, 0, 0, time.UTC),
+	}, {
+		spec:     "0 0 29 2 *",
+		expected: time.Date(2016, 2, 29, 0, 0, 0, 0, time.UTC),
+	}, {
+		spec: "0 0 30 2 *",
+	}} {
+		c.Logf("test %d: %s", i, test.spec)
+		schedule, err := cron.Parse(test.spec)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(schedule.Next(from), gc.Equals, test.expected)
+	}
+}
+
+func (s *cronSuite) TestParseErrors(c *gc.C) {
+	for i, test := range []struct {
+		spec string
+		err  string
+	}{{
+		spec: "* * * *",
+		err:  `invalid schedule "\* \* \* \*": expected 5 fields, got 4`,
+	}, {
+		spec: "60 * * * *",
+		err:  `invalid schedule "60 \* \* \* \*": invalid minute "60"`,
+	}, {
+		spec: "* * 0 * *",
+		err:  `invalid schedule "\* \* 0 \* \*": invalid day of month "0"`,
+	}, {
+		spec: "*/0 * * * *",
+		err:  `invalid schedule "\*/0 \* \* \* \*": invalid step in minute "\*/0"`,
+	}, {
+		spec: "* 5-2 * * *",
+		err:  `invalid schedule "\* 5-2 \* \* \*": invalid range in hour "5-2"`,
+	}, {
+		spec: "@fortnightly",
+		err:  `invalid schedule "@fortnightly": expected 5 fields, got 1`,
+	}} {
+		c.Logf("test %d: %s", i, test.spec)
+		_, err := cron.Parse(test.spec)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
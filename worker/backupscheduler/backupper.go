@@ -0,0 +1,66 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/replicaset"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+)
+
+var waitUntilReady = replicaset.WaitUntilReady
+
+type stateBackupper struct {
+	st        *state.State
+	paths     backups.Paths
+	machineID string
+}
+
+// NewStateBackupper returns a Backupper that backs up the given state
+// as taken on the machine with the given ID, the same way backups
+// requested through the API are.
+func NewStateBackupper(st *state.State, paths backups.Paths, machineID string) Backupper {
+	return &stateBackupper{
+		st:        st,
+		paths:     paths,
+		machineID: machineID,
+	}
+}
+
+// Create implements Backupper. The backup is recorded as scheduled,
+// so that it is subject to the environment's retention policy.
+func (b *stateBackupper) Create(notes string) (*backups.Metadata, error) {
+	stor := backups.NewConfiguredStorage(b.st, environs.ProviderStorage)
+	defer stor.Close()
+
+	session := b.st.MongoSession().Copy()
+	defer session.Close()
+
+	// Don't go if HA isn't ready.
+	if err := waitUntilReady(session, 60); err != nil {
+		return nil, errors.Annotatef(err, "HA not ready")
+	}
+	meta, err := backups.CreateBackup(backups.NewBackups(stor), b.st, session, &b.paths, b.machineID, notes, true, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return meta, nil
+}
+
+// List implements Backupper.
+func (b *stateBackupper) List() ([]*backups.Metadata, error) {
+	stor := backups.NewConfiguredStorage(b.st, environs.ProviderStorage)
+	defer stor.Close()
+	return backups.NewBackups(stor).List()
+}
+
+// Remove implements Backupper.
+func (b *stateBackupper) Remove(id string) error {
+	stor := backups.NewConfiguredStorage(b.st, environs.ProviderStorage)
+	defer stor.Close()
+	return backups.NewBackups(stor).Remove(id)
+}
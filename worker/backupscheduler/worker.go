@@ -0,0 +1,201 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package backupscheduler provides a worker that takes backups of
+// the state on the schedule given in the environment config, and
+// prunes the backups it took according to the configured retention
+// policy.
+package backupscheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/utils/cron"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.backupscheduler")
+
+// nextRun returns the time of the first scheduled backup after now.
+// It is a variable so tests can avoid waiting for it.
+var nextRun = func(schedule *cron.Schedule, now time.Time) time.Time {
+	return schedule.Next(now)
+}
+
+// State is the state used by the scheduler to follow the schedule and
+// record the outcome of the backups.
+type State interface {
+	EnvironConfig() (*config.Config, error)
+	WatchForEnvironConfigChanges() state.NotifyWatcher
+	SetBackupsScheduleStatus(status state.Status, info string, data map[string]interface{}) error
+}
+
+// Backupper creates, lists and removes backups.
+type Backupper interface {
+	// Create creates and stores a new backup with the given notes,
+	// and returns its metadata.
+	Create(notes string) (*backups.Metadata, error)
+
+	// List returns the metadata of all the stored backups.
+	List() ([]*backups.Metadata, error)
+
+	// Remove removes the stored backup with the given ID.
+	Remove(id string) error
+}
+
+type backupScheduler struct {
+	tomb      tomb.Tomb
+	st        State
+	backupper Backupper
+}
+
+// New returns a worker that takes backups with the given backupper on
+// the schedule given in the environment config.
+func New(st State, backupper Backupper) worker.Worker {
+	bs := &backupScheduler{
+		st:        st,
+		backupper: backupper,
+	}
+	go func() {
+		defer bs.tomb.Done()
+		bs.tomb.Kill(bs.loop())
+	}()
+	return bs
+}
+
+func (bs *backupScheduler) String() string {
+	return fmt.Sprintf("backup scheduler")
+}
+
+// Kill is defined on the worker.Worker interface.
+func (bs *backupScheduler) Kill() {
+	bs.tomb.Kill(nil)
+}
+
+// Wait is defined on the worker.Worker interface.
+func (bs *backupScheduler) Wait() error {
+	return bs.tomb.Wait()
+}
+
+func (bs *backupScheduler) loop() error {
+	w := bs.st.WatchForEnvironConfigChanges()
+	defer worker.Stop(w)
+
+	var (
+		schedule *cron.Schedule
+		policy   backups.RetentionPolicy
+		timer    <-chan time.Time
+	)
+	for {
+		select {
+		case <-bs.tomb.Dying():
+			return tomb.ErrDying
+		case _, ok := <-w.Changes():
+			if !ok {
+				return errors.New("environment config watcher closed")
+			}
+			cfg, err := bs.st.EnvironConfig()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			schedule, err = scheduleFromConfig(cfg)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			policy = backups.RetentionPolicy{
+				KeepLast:   cfg.BackupsKeepLast(),
+				KeepDaily:  cfg.BackupsKeepDaily(),
+				KeepWeekly: cfg.BackupsKeepWeekly(),
+			}
+			timer = bs.nextTimer(schedule)
+		case <-timer:
+			bs.backUp(policy)
+			timer = bs.nextTimer(schedule)
+		}
+	}
+}
+
+// scheduleFromConfig returns the backups schedule in the given
+// config, or nil if backups are not scheduled.
+func scheduleFromConfig(cfg *config.Config) (*cron.Schedule, error) {
+	spec, ok := cfg.BackupsSchedule()
+	if !ok {
+		logger.Debugf("backups are not scheduled")
+		return nil, nil
+	}
+	schedule, err := cron.Parse(spec)
+	if err != nil {
+		return nil, errors.Annotatef(err, "invalid %s", config.BackupsScheduleKey)
+	}
+	return schedule, nil
+}
+
+// nextTimer returns a channel that receives the time of the next
+// backup in the given schedule, or nil if no backup is scheduled.
+func (bs *backupScheduler) nextTimer(schedule *cron.Schedule) <-chan time.Time {
+	if schedule == nil {
+		return nil
+	}
+	now := time.Now()
+	next := nextRun(schedule, now)
+	if next.IsZero() {
+		logger.Warningf("backups schedule %q never runs", schedule)
+		return nil
+	}
+	logger.Debugf("next backup scheduled at %v", next)
+	return time.After(next.Sub(now))
+}
+
+// backUp takes a backup and prunes the expired ones, recording the
+// outcome in the backups schedule status. Failures are not fatal to
+// the worker, so that the next scheduled backup is still taken.
+func (bs *backupScheduler) backUp(policy backups.RetentionPolicy) {
+	bs.setStatus(state.StatusMaintenance, "creating backup", nil)
+	meta, err := bs.backupper.Create(backups.ScheduledNotes)
+	if err != nil {
+		logger.Errorf("cannot create scheduled backup: %v", err)
+		bs.setStatus(state.StatusError, fmt.Sprintf("cannot create backup: %v", err), nil)
+		return
+	}
+	logger.Infof("created scheduled backup %q", meta.ID())
+	data := map[string]interface{}{"backup-id": meta.ID()}
+	if err := bs.prune(policy); err != nil {
+		logger.Errorf("cannot prune scheduled backups: %v", err)
+		bs.setStatus(state.StatusError, fmt.Sprintf("cannot prune backups: %v", err), data)
+		return
+	}
+	bs.setStatus(state.StatusActive, "", data)
+}
+
+// prune removes the scheduled backups that have expired according to
+// the given policy.
+func (bs *backupScheduler) prune(policy backups.RetentionPolicy) error {
+	if policy.IsZero() {
+		return nil
+	}
+	metas, err := bs.backupper.List()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, meta := range policy.Expired(metas) {
+		logger.Infof("removing expired scheduled backup %q", meta.ID())
+		if err := bs.backupper.Remove(meta.ID()); err != nil {
+			return errors.Annotatef(err, "cannot remove backup %q", meta.ID())
+		}
+	}
+	return nil
+}
+
+func (bs *backupScheduler) setStatus(status state.Status, info string, data map[string]interface{}) {
+	if err := bs.st.SetBackupsScheduleStatus(status, info, data); err != nil {
+		logger.Errorf("cannot set backups schedule status: %v", err)
+	}
+}
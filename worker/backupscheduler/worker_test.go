@@ -0,0 +1,206 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/utils/cron"
+	"github.com/juju/juju/worker/backupscheduler"
+)
+
+type schedulerSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&schedulerSuite{})
+
+func (s *schedulerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	// Run the first scheduled backup immediately, and no other.
+	var once sync.Once
+	s.PatchValue(backupscheduler.NextRun, func(*cron.Schedule, time.Time) time.Time {
+		var next time.Time
+		once.Do(func() { next = time.Now() })
+		return next
+	})
+}
+
+type statusCall struct {
+	status state.Status
+	info   string
+	data   map[string]interface{}
+}
+
+type fakeState struct {
+	cfg      *config.Config
+	changes  chan struct{}
+	statuses chan statusCall
+}
+
+func newFakeState(c *gc.C, attrs coretesting.Attrs) *fakeState {
+	st := &fakeState{
+		cfg:      coretesting.CustomEnvironConfig(c, attrs),
+		changes:  make(chan struct{}, 1),
+		statuses: make(chan statusCall, 10),
+	}
+	st.changes <- struct{}{}
+	return st
+}
+
+func (st *fakeState) EnvironConfig() (*config.Config, error) {
+	return st.cfg, nil
+}
+
+func (st *fakeState) WatchForEnvironConfigChanges() state.NotifyWatcher {
+	return &fakeWatcher{changes: st.changes}
+}
+
+func (st *fakeState) SetBackupsScheduleStatus(status state.Status, info string, data map[string]interface{}) error {
+	st.statuses <- statusCall{status, info, data}
+	return nil
+}
+
+type fakeWatcher struct {
+	state.NotifyWatcher
+	changes chan struct{}
+}
+
+func (w *fakeWatcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+func (w *fakeWatcher) Stop() error {
+	return nil
+}
+
+type fakeBackupper struct {
+	mu        sync.Mutex
+	metas     []*backups.Metadata
+	createErr error
+}
+
+func (b *fakeBackupper) add(id string, started time.Time) {
+	meta := backups.NewMetadata()
+	meta.SetID(id)
+	meta.Started = started
+	meta.Notes = backups.ScheduledNotes
+	meta.Scheduled = true
+	b.metas = append(b.metas, meta)
+}
+
+func (b *fakeBackupper) ids() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ids := make([]string, len(b.metas))
+	for i, meta := range b.metas {
+		ids[i] = meta.ID()
+	}
+	return ids
+}
+
+func (b *fakeBackupper) Create(notes string) (*backups.Metadata, error) {
+	if b.createErr != nil {
+		return nil, b.createErr
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	meta := backups.NewMetadata()
+	meta.SetID(fmt.Sprintf("backup-%d", len(b.metas)))
+	meta.Notes = notes
+	meta.Scheduled = true
+	b.metas = append(b.metas, meta)
+	return meta, nil
+}
+
+func (b *fakeBackupper) List() ([]*backups.Metadata, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]*backups.Metadata(nil), b.metas...), nil
+}
+
+func (b *fakeBackupper) Remove(id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, meta := range b.metas {
+		if meta.ID() == id {
+			b.metas = append(b.metas[:i], b.metas[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+func (s *schedulerSuite) waitStatus(c *gc.C, st *fakeState) statusCall {
+	select {
+	case call := <-st.statuses:
+		return call
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for backups schedule status")
+	}
+	panic("unreachable")
+}
+
+func (s *schedulerSuite) TestCreatesAndPrunesBackups(c *gc.C) {
+	st := newFakeState(c, coretesting.Attrs{
+		"backups-schedule":  "@daily",
+		"backups-keep-last": 2,
+	})
+	b := &fakeBackupper{}
+	b.add("old-0", time.Now().Add(-2*time.Hour))
+	b.add("old-1", time.Now().Add(-time.Hour))
+	w := backupscheduler.New(st, b)
+	defer func() {
+		w.Kill()
+		c.Check(w.Wait(), jc.ErrorIsNil)
+	}()
+
+	call := s.waitStatus(c, st)
+	c.Check(call.status, gc.Equals, state.StatusMaintenance)
+	call = s.waitStatus(c, st)
+	c.Check(call.status, gc.Equals, state.StatusActive)
+	c.Check(call.data, jc.DeepEquals, map[string]interface{}{"backup-id": "backup-2"})
+	c.Check(b.ids(), jc.DeepEquals, []string{"old-1", "backup-2"})
+}
+
+func (s *schedulerSuite) TestCreateFailureSetsErrorStatus(c *gc.C) {
+	st := newFakeState(c, coretesting.Attrs{"backups-schedule": "@daily"})
+	b := &fakeBackupper{createErr: errors.New("boom")}
+	w := backupscheduler.New(st, b)
+	defer func() {
+		w.Kill()
+		c.Check(w.Wait(), jc.ErrorIsNil)
+	}()
+
+	call := s.waitStatus(c, st)
+	c.Check(call.status, gc.Equals, state.StatusMaintenance)
+	call = s.waitStatus(c, st)
+	c.Check(call.status, gc.Equals, state.StatusError)
+	c.Check(call.info, gc.Equals, "cannot create backup: boom")
+}
+
+func (s *schedulerSuite) TestNotScheduled(c *gc.C) {
+	st := newFakeState(c, nil)
+	w := backupscheduler.New(st, &fakeBackupper{})
+	defer func() {
+		w.Kill()
+		c.Check(w.Wait(), jc.ErrorIsNil)
+	}()
+
+	select {
+	case call := <-st.statuses:
+		c.Fatalf("unexpected status %v", call)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
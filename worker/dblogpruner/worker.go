@@ -0,0 +1,54 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package dblogpruner
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/worker"
+)
+
+// LogPruneParams specifies how the log records stored in state are
+// pruned.
+type LogPruneParams struct {
+	// MaxLogAge holds the age beyond which records are removed.
+	MaxLogAge time.Duration
+
+	// MaxCount holds the number of records that are kept.
+	MaxCount int
+
+	// PruneInterval holds the time between prunings.
+	PruneInterval time.Duration
+}
+
+// NewLogPruneParams returns the default LogPruneParams: records are
+// kept for three days, up to a million of them, and pruned every five
+// minutes.
+func NewLogPruneParams() *LogPruneParams {
+	return &LogPruneParams{
+		MaxLogAge:     3 * 24 * time.Hour,
+		MaxCount:      1000 * 1000,
+		PruneInterval: 5 * time.Minute,
+	}
+}
+
+// LogPruner is the state used by the pruner to remove log records.
+type LogPruner interface {
+	PruneLogs(minTime time.Time, maxCount int) error
+}
+
+// New returns a worker that periodically prunes the log records
+// stored in state.
+func New(st LogPruner, params *LogPruneParams) worker.Worker {
+	prune := func(stop <-chan struct{}) error {
+		minTime := time.Now().Add(-params.MaxLogAge)
+		if err := st.PruneLogs(minTime, params.MaxCount); err != nil {
+			return errors.Trace(err)
+		}
+		return nil
+	}
+	return worker.NewPeriodicWorker(prune, params.PruneInterval)
+}
@@ -0,0 +1,71 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package dblogpruner_test
+
+import (
+	"errors"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/dblogpruner"
+)
+
+type prunerSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&prunerSuite{})
+
+type pruneCall struct {
+	minTime  time.Time
+	maxCount int
+}
+
+type fakeLogPruner struct {
+	calls chan pruneCall
+	err   error
+}
+
+func (f *fakeLogPruner) PruneLogs(minTime time.Time, maxCount int) error {
+	f.calls <- pruneCall{minTime, maxCount}
+	return f.err
+}
+
+func (s *prunerSuite) TestPrunesPeriodically(c *gc.C) {
+	st := &fakeLogPruner{calls: make(chan pruneCall, 10)}
+	params := &dblogpruner.LogPruneParams{
+		MaxLogAge:     time.Hour,
+		MaxCount:      100,
+		PruneInterval: coretesting.ShortWait,
+	}
+	w := dblogpruner.New(st, params)
+	defer func() {
+		w.Kill()
+		c.Check(w.Wait(), jc.ErrorIsNil)
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case call := <-st.calls:
+			c.Assert(call.maxCount, gc.Equals, 100)
+			age := time.Since(call.minTime)
+			c.Assert(age >= time.Hour, jc.IsTrue)
+			c.Assert(age < time.Hour+coretesting.LongWait, jc.IsTrue)
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for logs to be pruned")
+		}
+	}
+}
+
+func (s *prunerSuite) TestPruneError(c *gc.C) {
+	st := &fakeLogPruner{
+		calls: make(chan pruneCall, 1),
+		err:   errors.New("boom"),
+	}
+	w := dblogpruner.New(st, dblogpruner.NewLogPruneParams())
+	c.Assert(w.Wait(), gc.ErrorMatches, "boom")
+}
@@ -23,9 +23,10 @@ import (
 	"github.com/juju/juju/version"
 )
 
-// InitDir is the default upstart init directory.
-// This is a var so it can be overridden by tests.
-var InitDir = "/etc/init"
+// InitDir is the directory in which unit agent services are
+// installed. If empty, the default directory of the host's init
+// system is used. This is a var so it can be overridden by tests.
+var InitDir = ""
 
 // APICalls defines the interface to the API that the simple context needs.
 type APICalls interface {
@@ -43,8 +44,9 @@ type SimpleContext struct {
 	// running the deployer.
 	agentConfig agent.Config
 
-	// initDir specifies the directory used by upstart on the local system.
-	// It is typically set to "/etc/init".
+	// initDir specifies the directory used by the init system on the
+	// local system. It is typically empty, in which case the init
+	// system's default directory is used.
 	initDir string
 }
 
@@ -133,7 +135,7 @@ func (ctx *SimpleContext) DeployUnit(unitName, initialPassword string) (err erro
 	}
 	defer removeOnErr(&err, conf.Dir())
 
-	// Install an upstart job that runs the unit agent.
+	// Install a service that runs the unit agent.
 	logPath := path.Join(logDir, tag.String()+".log")
 	cmd := strings.Join([]string{
 		filepath.FromSlash(path.Join(toolsDir, jujunames.Jujud)), "unit",
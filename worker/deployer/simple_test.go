@@ -13,11 +13,13 @@ import (
 	"sort"
 
 	"github.com/juju/names"
+	jujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/agent/tools"
+	"github.com/juju/juju/service"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/testing"
 	coretools "github.com/juju/juju/tools"
@@ -136,6 +138,7 @@ type SimpleToolsFixture struct {
 	initDir  string
 	origPath string
 	binDir   string
+	restore  jujutesting.Restorer
 }
 
 var fakeJujud = "#!/bin/bash --norc\n# fake-jujud\nexit 0\n"
@@ -143,6 +146,9 @@ var fakeJujud = "#!/bin/bash --norc\n# fake-jujud\nexit 0\n"
 func (fix *SimpleToolsFixture) SetUp(c *gc.C, dataDir string) {
 	fix.dataDir = dataDir
 	fix.initDir = c.MkDir()
+	fix.restore = jujutesting.PatchValue(&service.InitSystem, func() string {
+		return service.InitSystemUpstart
+	})
 	fix.logDir = c.MkDir()
 	toolsDir := tools.SharedToolsDir(fix.dataDir, version.Current)
 	err := os.MkdirAll(toolsDir, 0755)
@@ -168,6 +174,7 @@ func (fix *SimpleToolsFixture) SetUp(c *gc.C, dataDir string) {
 
 func (fix *SimpleToolsFixture) TearDown(c *gc.C) {
 	os.Setenv("PATH", fix.origPath)
+	fix.restore()
 }
 
 func (fix *SimpleToolsFixture) makeBin(c *gc.C, name, script string) {
@@ -0,0 +1,135 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package envworkermanager
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.envworkermanager")
+
+// undertakerPeriod is how often a dying environment is checked to see
+// whether it is empty and can be made dead.
+var undertakerPeriod = 30 * time.Second
+
+// StartEnvWorkerFunc starts the worker that runs the environment workers
+// for the hosted environment with the given UUID.
+type StartEnvWorkerFunc func(envUUID string) (worker.Worker, error)
+
+// EnvWorkerManager runs the environment workers of each environment
+// hosted by the state server, starting them when an environment is
+// created and stopping them when it dies. A dying environment is made
+// dead once its workers have removed all its machines and services. The
+// state server's own
+// environment is left to the workers started by the machine agent.
+type EnvWorkerManager struct {
+	st     *state.State
+	start  StartEnvWorkerFunc
+	runner worker.Runner
+}
+
+// NewEnvWorkerManager returns a worker that runs a worker, started with
+// the given function, for each environment hosted by the state server.
+// A failed environment worker is restarted without affecting the
+// others.
+func NewEnvWorkerManager(st *state.State, start StartEnvWorkerFunc) worker.Worker {
+	m := &EnvWorkerManager{
+		st:     st,
+		start:  start,
+		runner: worker.NewRunner(neverFatal, neverImportant),
+	}
+	return worker.NewStringsWorker(m)
+}
+
+func neverFatal(error) bool {
+	return false
+}
+
+func neverImportant(error, error) bool {
+	return false
+}
+
+// SetUp implements worker.StringsWatchHandler.
+func (m *EnvWorkerManager) SetUp() (watcher.StringsWatcher, error) {
+	return m.st.WatchEnvironments(), nil
+}
+
+// Handle implements worker.StringsWatchHandler.
+func (m *EnvWorkerManager) Handle(uuids []string) error {
+	for _, uuid := range uuids {
+		if uuid == m.st.EnvironUUID() {
+			continue
+		}
+		if err := m.handleEnvironment(uuid); err != nil {
+			return errors.Annotatef(err, "environment %s", uuid)
+		}
+	}
+	return nil
+}
+
+func (m *EnvWorkerManager) handleEnvironment(uuid string) error {
+	env, err := m.st.GetEnvironment(names.NewEnvironTag(uuid))
+	if errors.IsNotFound(err) {
+		logger.Infof("stopping workers for removed environment %s", uuid)
+		return m.runner.StopWorker(uuid)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if env.Life() == state.Dead {
+		logger.Infof("stopping workers for dead environment %s", uuid)
+		if err := m.runner.StopWorker(undertakerName(uuid)); err != nil {
+			return errors.Trace(err)
+		}
+		return m.runner.StopWorker(uuid)
+	}
+	// Starting an already running worker does nothing.
+	err = m.runner.StartWorker(uuid, func() (worker.Worker, error) {
+		logger.Infof("starting workers for environment %s", uuid)
+		return m.start(uuid)
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if env.Life() == state.Dying {
+		return m.runner.StartWorker(undertakerName(uuid), func() (worker.Worker, error) {
+			return m.newUndertaker(uuid), nil
+		})
+	}
+	return nil
+}
+
+func undertakerName(uuid string) string {
+	return uuid + "-undertaker"
+}
+
+// newUndertaker returns a worker that makes the dying environment with
+// the given UUID dead once it is empty. The environments watcher then
+// reports the change and the environment's workers are stopped.
+func (m *EnvWorkerManager) newUndertaker(uuid string) worker.Worker {
+	return worker.NewPeriodicWorker(func(<-chan struct{}) error {
+		env, err := m.st.GetEnvironment(names.NewEnvironTag(uuid))
+		if errors.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if err := env.EnsureDead(); err != nil {
+			logger.Debugf("environment %s not yet dead: %v", uuid, err)
+		}
+		return nil
+	}, undertakerPeriod)
+}
+
+// TearDown implements worker.StringsWatchHandler.
+func (m *EnvWorkerManager) TearDown() error {
+	return worker.Stop(m.runner)
+}
@@ -0,0 +1,161 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package envworkermanager_test
+
+import (
+	stdtesting "testing"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/envworkermanager"
+)
+
+func TestPackage(t *stdtesting.T) {
+	coretesting.MgoTestPackage(t)
+}
+
+type suite struct {
+	testing.JujuConnSuite
+	started chan string
+	stopped chan string
+}
+
+var _ = gc.Suite(&suite{})
+
+var _ worker.StringsWatchHandler = (*envworkermanager.EnvWorkerManager)(nil)
+
+func (s *suite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.started = make(chan string, 10)
+	s.stopped = make(chan string, 10)
+	s.PatchValue(envworkermanager.UndertakerPeriod, coretesting.ShortWait)
+}
+
+// fakeEnvWorker records when it is started and stopped.
+type fakeEnvWorker struct {
+	tomb tomb.Tomb
+}
+
+func (s *suite) startEnvWorker(uuid string) (worker.Worker, error) {
+	w := &fakeEnvWorker{}
+	go func() {
+		defer w.tomb.Done()
+		<-w.tomb.Dying()
+		s.stopped <- uuid
+	}()
+	s.started <- uuid
+	return w, nil
+}
+
+func (w *fakeEnvWorker) Kill() {
+	w.tomb.Kill(nil)
+}
+
+func (w *fakeEnvWorker) Wait() error {
+	return w.tomb.Wait()
+}
+
+func (s *suite) assertEvent(c *gc.C, ch <-chan string, expected string) {
+	timeout := time.After(coretesting.LongWait)
+	for {
+		s.State.StartSync()
+		select {
+		case uuid := <-ch:
+			c.Assert(uuid, gc.Equals, expected)
+			return
+		case <-time.After(coretesting.ShortWait):
+		case <-timeout:
+			c.Fatalf("timed out waiting for %s", expected)
+		}
+	}
+}
+
+func (s *suite) assertNoEvent(c *gc.C, ch <-chan string) {
+	s.State.StartSync()
+	select {
+	case uuid := <-ch:
+		c.Fatalf("unexpected event for %s", uuid)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *suite) TestStartsWorkersForHostedEnvironments(c *gc.C) {
+	st := s.Factory.MakeEnvironment(c, nil)
+	defer st.Close()
+
+	m := envworkermanager.NewEnvWorkerManager(s.State, s.startEnvWorker)
+	defer func() { c.Assert(worker.Stop(m), jc.ErrorIsNil) }()
+
+	// The state server's own environment is skipped.
+	s.assertEvent(c, s.started, st.EnvironUUID())
+	s.assertNoEvent(c, s.started)
+
+	// Environments created later get workers too.
+	st2 := s.Factory.MakeEnvironment(c, nil)
+	defer st2.Close()
+	s.assertEvent(c, s.started, st2.EnvironUUID())
+}
+
+func (s *suite) TestKeepsWorkersForDyingEnvironments(c *gc.C) {
+	st := s.Factory.MakeEnvironment(c, nil)
+	defer st.Close()
+
+	m := envworkermanager.NewEnvWorkerManager(s.State, s.startEnvWorker)
+	defer func() { c.Assert(worker.Stop(m), jc.ErrorIsNil) }()
+	s.assertEvent(c, s.started, st.EnvironUUID())
+
+	// Workers keep running while the environment is dying, so they
+	// can clean up after it.
+	factory.NewFactory(st).MakeMachine(c, nil)
+	env, err := st.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	err = env.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertNoEvent(c, s.stopped)
+}
+
+func (s *suite) TestStopsWorkersWhenEnvironmentIsEmptied(c *gc.C) {
+	st := s.Factory.MakeEnvironment(c, nil)
+	defer st.Close()
+	machine := factory.NewFactory(st).MakeMachine(c, nil)
+
+	m := envworkermanager.NewEnvWorkerManager(s.State, s.startEnvWorker)
+	defer func() { c.Assert(worker.Stop(m), jc.ErrorIsNil) }()
+	s.assertEvent(c, s.started, st.EnvironUUID())
+
+	env, err := st.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	err = env.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertNoEvent(c, s.stopped)
+
+	// Once the environment's last machine is removed, the environment
+	// is made dead and its workers are stopped.
+	err = machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertEvent(c, s.stopped, st.EnvironUUID())
+	c.Assert(env.Refresh(), jc.ErrorIsNil)
+	c.Assert(env.Life(), gc.Equals, state.Dead)
+}
+
+func (s *suite) TestStopsWorkersWhenKilled(c *gc.C) {
+	st := s.Factory.MakeEnvironment(c, nil)
+	defer st.Close()
+
+	m := envworkermanager.NewEnvWorkerManager(s.State, s.startEnvWorker)
+	s.assertEvent(c, s.started, st.EnvironUUID())
+
+	c.Assert(worker.Stop(m), jc.ErrorIsNil)
+	s.assertEvent(c, s.stopped, st.EnvironUUID())
+}
@@ -39,7 +39,7 @@ type Firewaller struct {
 	serviceds       map[names.ServiceTag]*serviceData
 	exposedChange   chan *exposedChange
 	globalMode      bool
-	globalPortRef   map[network.PortRange]int
+	globalRuleRef   map[string]int
 	machinePorts    map[names.MachineTag]machineRanges
 }
 
@@ -88,7 +88,7 @@ func NewFirewaller(st *apifirewaller.State) (_ worker.Worker, err error) {
 	switch fw.environ.Config().FirewallMode() {
 	case config.FwGlobal:
 		fw.globalMode = true
-		fw.globalPortRef = make(map[network.PortRange]int)
+		fw.globalRuleRef = make(map[string]int)
 	case config.FwNone:
 		logger.Warningf("stopping firewaller - firewall-mode is %q", config.FwNone)
 		return nil, errors.Errorf("firewaller is disabled when firewall-mode is %q", config.FwNone)
@@ -163,6 +163,7 @@ func (fw *Firewaller) loop() error {
 			}
 		case change := <-fw.exposedChange:
 			change.serviced.exposed = change.exposed
+			change.serviced.sourceCIDRs = change.sourceCIDRs
 			unitds := []*unitData{}
 			for _, unitd := range change.serviced.unitds {
 				unitds = append(unitds, unitd)
@@ -181,7 +182,7 @@ func (fw *Firewaller) startMachine(tag names.MachineTag) error {
 		fw:           fw,
 		tag:          tag,
 		unitds:       make(map[names.UnitTag]*unitData),
-		openedPorts:  make([]network.PortRange, 0),
+		openedPorts:  make([]network.IngressRule, 0),
 		definedPorts: make(map[network.PortRange]names.UnitTag),
 	}
 	m, err := machined.machine()
@@ -272,14 +273,19 @@ func (fw *Firewaller) startService(service *apifirewaller.Service) error {
 	if err != nil {
 		return err
 	}
+	sourceCIDRs, err := service.ExposedSourceCIDRs()
+	if err != nil {
+		return err
+	}
 	serviced := &serviceData{
-		fw:      fw,
-		service: service,
-		exposed: exposed,
-		unitds:  make(map[names.UnitTag]*unitData),
+		fw:          fw,
+		service:     service,
+		exposed:     exposed,
+		sourceCIDRs: sourceCIDRs,
+		unitds:      make(map[names.UnitTag]*unitData),
 	}
 	fw.serviceds[service.Tag()] = serviced
-	go serviced.watchLoop(serviced.exposed)
+	go serviced.watchLoop(serviced.exposed, serviced.sourceCIDRs)
 	return nil
 }
 
@@ -287,11 +293,12 @@ func (fw *Firewaller) startService(service *apifirewaller.Service) error {
 // units and services with the opened and closed ports globally and
 // opens and closes the appropriate ports for the whole environment.
 func (fw *Firewaller) reconcileGlobal() error {
-	initialPortRanges, err := fw.environ.Ports()
+	initialRules, err := fw.environ.Ports()
 	if err != nil {
 		return err
 	}
-	collector := make(map[network.PortRange]bool)
+	initialRules = network.SplitIngressRules(initialRules)
+	collector := make(map[string]network.IngressRule)
 	for _, machined := range fw.machineds {
 		for portRange, unitTag := range machined.definedPorts {
 			unitd, known := machined.unitds[unitTag]
@@ -300,30 +307,32 @@ func (fw *Firewaller) reconcileGlobal() error {
 				continue
 			}
 			if unitd.serviced.exposed {
-				collector[portRange] = true
+				for _, rule := range unitd.serviced.ingressRules(portRange) {
+					collector[rule.String()] = rule
+				}
 			}
 		}
 	}
-	wantedPorts := []network.PortRange{}
-	for port := range collector {
-		wantedPorts = append(wantedPorts, port)
+	wantedRules := []network.IngressRule{}
+	for _, rule := range collector {
+		wantedRules = append(wantedRules, rule)
 	}
-	// Check which ports to open or to close.
-	toOpen := diffRanges(wantedPorts, initialPortRanges)
-	toClose := diffRanges(initialPortRanges, wantedPorts)
+	// Check which rules to open or to close.
+	toOpen := diffRules(wantedRules, initialRules)
+	toClose := diffRules(initialRules, wantedRules)
 	if len(toOpen) > 0 {
 		logger.Infof("opening global ports %v", toOpen)
 		if err := fw.environ.OpenPorts(toOpen); err != nil {
 			return err
 		}
-		network.SortPortRanges(toOpen)
+		network.SortIngressRules(toOpen)
 	}
 	if len(toClose) > 0 {
 		logger.Infof("closing global ports %v", toClose)
 		if err := fw.environ.ClosePorts(toClose); err != nil {
 			return err
 		}
-		network.SortPortRanges(toClose)
+		network.SortIngressRules(toClose)
 	}
 	return nil
 }
@@ -353,14 +362,15 @@ func (fw *Firewaller) reconcileInstances() error {
 			return err
 		}
 		machineId := machined.tag.Id()
-		initialPortRanges, err := instances[0].Ports(machineId)
+		initialRules, err := instances[0].Ports(machineId)
 		if err != nil {
 			return err
 		}
+		initialRules = network.SplitIngressRules(initialRules)
 
-		// Check which ports to open or to close.
-		toOpen := diffRanges(machined.openedPorts, initialPortRanges)
-		toClose := diffRanges(initialPortRanges, machined.openedPorts)
+		// Check which rules to open or to close.
+		toOpen := diffRules(machined.openedPorts, initialRules)
+		toClose := diffRules(initialRules, machined.openedPorts)
 		if len(toOpen) > 0 {
 			logger.Infof("opening instance port ranges %v for %q",
 				toOpen, machined.tag)
@@ -368,7 +378,7 @@ func (fw *Firewaller) reconcileInstances() error {
 				// TODO(mue) Add local retry logic.
 				return err
 			}
-			network.SortPortRanges(toOpen)
+			network.SortIngressRules(toOpen)
 		}
 		if len(toClose) > 0 {
 			logger.Infof("closing instance port ranges %v for %q",
@@ -377,7 +387,7 @@ func (fw *Firewaller) reconcileInstances() error {
 				// TODO(mue) Add local retry logic.
 				return err
 			}
-			network.SortPortRanges(toClose)
+			network.SortIngressRules(toClose)
 		}
 	}
 	return nil
@@ -498,8 +508,8 @@ func (fw *Firewaller) flushUnits(unitds []*unitData) error {
 
 // flushMachine opens and closes ports for the passed machine.
 func (fw *Firewaller) flushMachine(machined *machineData) error {
-	// Gather ports to open and close.
-	want := []network.PortRange{}
+	// Gather rules to open and close.
+	want := []network.IngressRule{}
 	for portRange, unitTag := range machined.definedPorts {
 		unitd, known := machined.unitds[unitTag]
 		if !known {
@@ -507,11 +517,11 @@ func (fw *Firewaller) flushMachine(machined *machineData) error {
 			continue
 		}
 		if unitd.serviced.exposed {
-			want = append(want, portRange)
+			want = append(want, unitd.serviced.ingressRules(portRange)...)
 		}
 	}
-	toOpen := diffRanges(want, machined.openedPorts)
-	toClose := diffRanges(machined.openedPorts, want)
+	toOpen := diffRules(want, machined.openedPorts)
+	toClose := diffRules(machined.openedPorts, want)
 	machined.openedPorts = want
 	if fw.globalMode {
 		return fw.flushGlobalPorts(toOpen, toClose)
@@ -519,23 +529,25 @@ func (fw *Firewaller) flushMachine(machined *machineData) error {
 	return fw.flushInstancePorts(machined, toOpen, toClose)
 }
 
-// flushGlobalPorts opens and closes global ports in the environment.
-// It keeps a reference count for ports so that only 0-to-1 and 1-to-0 events
-// modify the environment.
-func (fw *Firewaller) flushGlobalPorts(rawOpen, rawClose []network.PortRange) error {
-	// Filter which ports are really to open or close.
-	var toOpen, toClose []network.PortRange
-	for _, portRange := range rawOpen {
-		if fw.globalPortRef[portRange] == 0 {
-			toOpen = append(toOpen, portRange)
+// flushGlobalPorts opens and closes global ingress rules in the
+// environment. It keeps a reference count for rules so that only 0-to-1
+// and 1-to-0 events modify the environment.
+func (fw *Firewaller) flushGlobalPorts(rawOpen, rawClose []network.IngressRule) error {
+	// Filter which rules are really to open or close.
+	var toOpen, toClose []network.IngressRule
+	for _, rule := range rawOpen {
+		key := rule.String()
+		if fw.globalRuleRef[key] == 0 {
+			toOpen = append(toOpen, rule)
 		}
-		fw.globalPortRef[portRange]++
+		fw.globalRuleRef[key]++
 	}
-	for _, portRange := range rawClose {
-		fw.globalPortRef[portRange]--
-		if fw.globalPortRef[portRange] == 0 {
-			toClose = append(toClose, portRange)
-			delete(fw.globalPortRef, portRange)
+	for _, rule := range rawClose {
+		key := rule.String()
+		fw.globalRuleRef[key]--
+		if fw.globalRuleRef[key] == 0 {
+			toClose = append(toClose, rule)
+			delete(fw.globalRuleRef, key)
 		}
 	}
 	// Open and close the ports.
@@ -544,7 +556,7 @@ func (fw *Firewaller) flushGlobalPorts(rawOpen, rawClose []network.PortRange) er
 			// TODO(mue) Add local retry logic.
 			return err
 		}
-		network.SortPortRanges(toOpen)
+		network.SortIngressRules(toOpen)
 		logger.Infof("opened port ranges %v in environment", toOpen)
 	}
 	if len(toClose) > 0 {
@@ -552,14 +564,14 @@ func (fw *Firewaller) flushGlobalPorts(rawOpen, rawClose []network.PortRange) er
 			// TODO(mue) Add local retry logic.
 			return err
 		}
-		network.SortPortRanges(toClose)
+		network.SortIngressRules(toClose)
 		logger.Infof("closed port ranges %v in environment", toClose)
 	}
 	return nil
 }
 
 // flushInstancePorts opens and closes ports global on the machine.
-func (fw *Firewaller) flushInstancePorts(machined *machineData, toOpen, toClose []network.PortRange) error {
+func (fw *Firewaller) flushInstancePorts(machined *machineData, toOpen, toClose []network.IngressRule) error {
 	// If there's nothing to do, do nothing.
 	// This is important because when a machine is first created,
 	// it will have no instance id but also no open ports -
@@ -589,7 +601,7 @@ func (fw *Firewaller) flushInstancePorts(machined *machineData, toOpen, toClose
 			// TODO(mue) Add local retry logic.
 			return err
 		}
-		network.SortPortRanges(toOpen)
+		network.SortIngressRules(toOpen)
 		logger.Infof("opened port ranges %v on %q", toOpen, machined.tag)
 	}
 	if len(toClose) > 0 {
@@ -597,7 +609,7 @@ func (fw *Firewaller) flushInstancePorts(machined *machineData, toOpen, toClose
 			// TODO(mue) Add local retry logic.
 			return err
 		}
-		network.SortPortRanges(toClose)
+		network.SortIngressRules(toClose)
 		logger.Infof("closed port ranges %v on %q", toClose, machined.tag)
 	}
 	return nil
@@ -712,7 +724,7 @@ type machineData struct {
 	fw          *Firewaller
 	tag         names.MachineTag
 	unitds      map[names.UnitTag]*unitData
-	openedPorts []network.PortRange
+	openedPorts []network.IngressRule
 	// ports defined by units on this machine
 	definedPorts map[network.PortRange]names.UnitTag
 }
@@ -762,23 +774,37 @@ type unitData struct {
 	machined *machineData
 }
 
-// exposedChange contains the changed exposed flag for one specific service.
+// exposedChange contains the changed exposed flag and source CIDRs
+// for one specific service.
 type exposedChange struct {
-	serviced *serviceData
-	exposed  bool
+	serviced    *serviceData
+	exposed     bool
+	sourceCIDRs []string
 }
 
 // serviceData holds service details and watches exposure changes.
 type serviceData struct {
-	tomb    tomb.Tomb
-	fw      *Firewaller
-	service *apifirewaller.Service
-	exposed bool
-	unitds  map[names.UnitTag]*unitData
+	tomb        tomb.Tomb
+	fw          *Firewaller
+	service     *apifirewaller.Service
+	exposed     bool
+	sourceCIDRs []string
+	unitds      map[names.UnitTag]*unitData
+}
+
+// ingressRules returns the rules opening the given port range to the
+// service's exposed source CIDRs, one rule for each source CIDR. The
+// firewaller only deals in such split rules, so that changing a
+// service's source CIDRs opens and closes exactly the CIDRs that were
+// added and removed, even when other services share the port range.
+func (sd *serviceData) ingressRules(portRange network.PortRange) []network.IngressRule {
+	rule := network.NewIngressRule(portRange, sd.sourceCIDRs...)
+	return network.SplitIngressRules([]network.IngressRule{rule})
 }
 
-// watchLoop watches the service's exposed flag for changes.
-func (sd *serviceData) watchLoop(exposed bool) {
+// watchLoop watches the service's exposed flag and source CIDRs for
+// changes.
+func (sd *serviceData) watchLoop(exposed bool, sourceCIDRs []string) {
 	defer sd.tomb.Done()
 	w, err := sd.service.Watch()
 	if err != nil {
@@ -806,12 +832,17 @@ func (sd *serviceData) watchLoop(exposed bool) {
 				sd.fw.tomb.Kill(err)
 				return
 			}
-			if change == exposed {
+			changedCIDRs, err := sd.service.ExposedSourceCIDRs()
+			if err != nil {
+				sd.fw.tomb.Kill(err)
+				return
+			}
+			if change == exposed && sameSourceCIDRs(changedCIDRs, sourceCIDRs) {
 				continue
 			}
-			exposed = change
+			exposed, sourceCIDRs = change, changedCIDRs
 			select {
-			case sd.fw.exposedChange <- &exposedChange{sd, change}:
+			case sd.fw.exposedChange <- &exposedChange{sd, change, changedCIDRs}:
 			case <-sd.tomb.Dying():
 				return
 			}
@@ -825,12 +856,12 @@ func (sd *serviceData) Stop() error {
 	return sd.tomb.Wait()
 }
 
-// diffRanges returns all the port rangess that exist in A but not B.
-func diffRanges(A, B []network.PortRange) (missing []network.PortRange) {
+// diffRules returns all the ingress rules that exist in A but not B.
+func diffRules(A, B []network.IngressRule) (missing []network.IngressRule) {
 next:
 	for _, a := range A {
 		for _, b := range B {
-			if a == b {
+			if a.String() == b.String() {
 				continue next
 			}
 		}
@@ -839,6 +870,20 @@ next:
 	return
 }
 
+// sameSourceCIDRs reports whether the given normalised source CIDR
+// lists are equal.
+func sameSourceCIDRs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // parsePortsKey parses a ports document global key coming from the
 // ports watcher (e.g. "42:juju-public") and returns the machine and
 // network tags from its components (in the last example "machine-42"
@@ -64,8 +64,14 @@ func (s *firewallerBaseSuite) setUpTest(c *gc.C, firewallMode string) {
 }
 
 // assertPorts retrieves the open ports of the instance and compares them
-// to the expected.
+// to the expected, which are open to all addresses.
 func (s *firewallerBaseSuite) assertPorts(c *gc.C, inst instance.Instance, machineId string, expected []network.PortRange) {
+	s.assertIngressRules(c, inst, machineId, network.IngressRulesForPortRanges(expected))
+}
+
+// assertIngressRules retrieves the ingress rules of the instance and
+// compares them to the expected.
+func (s *firewallerBaseSuite) assertIngressRules(c *gc.C, inst instance.Instance, machineId string, expected []network.IngressRule) {
 	s.BackingState.StartSync()
 	start := time.Now()
 	for {
@@ -74,9 +80,7 @@ func (s *firewallerBaseSuite) assertPorts(c *gc.C, inst instance.Instance, machi
 			c.Fatal(err)
 			return
 		}
-		network.SortPortRanges(got)
-		network.SortPortRanges(expected)
-		if reflect.DeepEqual(got, expected) {
+		if sameIngressRules(got, expected) {
 			c.Succeed()
 			return
 		}
@@ -89,8 +93,14 @@ func (s *firewallerBaseSuite) assertPorts(c *gc.C, inst instance.Instance, machi
 }
 
 // assertEnvironPorts retrieves the open ports of environment and compares them
-// to the expected.
+// to the expected, which are open to all addresses.
 func (s *firewallerBaseSuite) assertEnvironPorts(c *gc.C, expected []network.PortRange) {
+	s.assertEnvironIngressRules(c, network.IngressRulesForPortRanges(expected))
+}
+
+// assertEnvironIngressRules retrieves the ingress rules of the
+// environment and compares them to the expected.
+func (s *firewallerBaseSuite) assertEnvironIngressRules(c *gc.C, expected []network.IngressRule) {
 	s.BackingState.StartSync()
 	start := time.Now()
 	for {
@@ -99,9 +109,7 @@ func (s *firewallerBaseSuite) assertEnvironPorts(c *gc.C, expected []network.Por
 			c.Fatal(err)
 			return
 		}
-		network.SortPortRanges(got)
-		network.SortPortRanges(expected)
-		if reflect.DeepEqual(got, expected) {
+		if sameIngressRules(got, expected) {
 			c.Succeed()
 			return
 		}
@@ -113,6 +121,15 @@ func (s *firewallerBaseSuite) assertEnvironPorts(c *gc.C, expected []network.Por
 	}
 }
 
+func sameIngressRules(got, expected []network.IngressRule) bool {
+	if len(got) == 0 && len(expected) == 0 {
+		return true
+	}
+	network.SortIngressRules(got)
+	network.SortIngressRules(expected)
+	return reflect.DeepEqual(got, expected)
+}
+
 func (s *firewallerBaseSuite) addUnit(c *gc.C, svc *state.Service) (*state.Unit, *state.Machine) {
 	units, err := juju.AddUnits(s.State, svc, 1, "")
 	c.Assert(err, jc.ErrorIsNil)
@@ -402,6 +419,78 @@ func (s *InstanceModeSuite) TestSetClearExposedService(c *gc.C) {
 	s.assertPorts(c, inst, m.Id(), nil)
 }
 
+func (s *InstanceModeSuite) TestExposedServiceSourceCIDRs(c *gc.C) {
+	fw, err := firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	svc := s.AddTestingService(c, "wordpress", s.charm)
+	err = svc.SetExposed("10.0.0.0/8", "203.0.113.4/32")
+	c.Assert(err, jc.ErrorIsNil)
+
+	u, m := s.addUnit(c, svc)
+	inst := s.startInstance(c, m)
+	err = u.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Each source CIDR gets its own rule.
+	s.assertIngressRules(c, inst, m.Id(), []network.IngressRule{
+		network.NewIngressRule(network.PortRange{80, 80, "tcp"}, "10.0.0.0/8"),
+		network.NewIngressRule(network.PortRange{80, 80, "tcp"}, "203.0.113.4/32"),
+	})
+
+	// Changing the source CIDRs replaces the rules.
+	err = svc.SetExposed("192.168.0.0/16")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertIngressRules(c, inst, m.Id(), []network.IngressRule{
+		network.NewIngressRule(network.PortRange{80, 80, "tcp"}, "192.168.0.0/16"),
+	})
+
+	// Exposing without source CIDRs opens the ports to all addresses.
+	err = svc.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPorts(c, inst, m.Id(), []network.PortRange{{80, 80, "tcp"}})
+
+	err = svc.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPorts(c, inst, m.Id(), nil)
+}
+
+func (s *InstanceModeSuite) TestNarrowAndWidenSourceCIDRs(c *gc.C) {
+	fw, err := firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	svc := s.AddTestingService(c, "wordpress", s.charm)
+	err = svc.SetExposed("10.0.0.0/8", "203.0.113.4/32")
+	c.Assert(err, jc.ErrorIsNil)
+	u, m := s.addUnit(c, svc)
+	inst := s.startInstance(c, m)
+	err = u.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	httpRange := network.PortRange{80, 80, "tcp"}
+	s.assertIngressRules(c, inst, m.Id(), []network.IngressRule{
+		network.NewIngressRule(httpRange, "10.0.0.0/8"),
+		network.NewIngressRule(httpRange, "203.0.113.4/32"),
+	})
+
+	// Narrowing the source CIDRs only closes the removed CIDR.
+	err = svc.SetExposed("10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertIngressRules(c, inst, m.Id(), []network.IngressRule{
+		network.NewIngressRule(httpRange, "10.0.0.0/8"),
+	})
+
+	// Widening them only opens the added CIDR.
+	err = svc.SetExposed("10.0.0.0/8", "192.168.0.0/16")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertIngressRules(c, inst, m.Id(), []network.IngressRule{
+		network.NewIngressRule(httpRange, "10.0.0.0/8"),
+		network.NewIngressRule(httpRange, "192.168.0.0/16"),
+	})
+}
+
 func (s *InstanceModeSuite) TestRemoveUnit(c *gc.C) {
 	fw, err := firewaller.NewFirewaller(s.firewaller)
 	c.Assert(err, jc.ErrorIsNil)
@@ -636,6 +725,92 @@ func (s *GlobalModeSuite) TestGlobalMode(c *gc.C) {
 	s.assertEnvironPorts(c, nil)
 }
 
+func (s *GlobalModeSuite) TestGlobalModeSourceCIDRs(c *gc.C) {
+	fw, err := firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	svc1 := s.AddTestingService(c, "wordpress", s.charm)
+	err = svc1.SetExposed("10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+	u1, m1 := s.addUnit(c, svc1)
+	s.startInstance(c, m1)
+	err = u1.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	svc2 := s.AddTestingService(c, "moinmoin", s.charm)
+	err = svc2.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	u2, m2 := s.addUnit(c, svc2)
+	s.startInstance(c, m2)
+	err = u2.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The same port range opened with different source CIDRs
+	// results in separate rules.
+	httpRange := network.PortRange{80, 80, "tcp"}
+	s.assertEnvironIngressRules(c, []network.IngressRule{
+		network.NewIngressRule(httpRange),
+		network.NewIngressRule(httpRange, "10.0.0.0/8"),
+	})
+
+	err = svc2.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertEnvironIngressRules(c, []network.IngressRule{
+		network.NewIngressRule(httpRange, "10.0.0.0/8"),
+	})
+
+	err = svc1.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertEnvironIngressRules(c, nil)
+}
+
+func (s *GlobalModeSuite) TestGlobalModeChangeSharedSourceCIDRs(c *gc.C) {
+	fw, err := firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	svc1 := s.AddTestingService(c, "wordpress", s.charm)
+	err = svc1.SetExposed("10.0.0.0/8", "192.168.0.0/16")
+	c.Assert(err, jc.ErrorIsNil)
+	u1, m1 := s.addUnit(c, svc1)
+	s.startInstance(c, m1)
+	err = u1.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	svc2 := s.AddTestingService(c, "moinmoin", s.charm)
+	err = svc2.SetExposed("10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+	u2, m2 := s.addUnit(c, svc2)
+	s.startInstance(c, m2)
+	err = u2.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	httpRange := network.PortRange{80, 80, "tcp"}
+	s.assertEnvironIngressRules(c, []network.IngressRule{
+		network.NewIngressRule(httpRange, "10.0.0.0/8"),
+		network.NewIngressRule(httpRange, "192.168.0.0/16"),
+	})
+
+	// Narrowing the first service's source CIDRs to one the
+	// second service doesn't use leaves the shared CIDR open.
+	err = svc1.SetExposed("192.168.0.0/16")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertEnvironIngressRules(c, []network.IngressRule{
+		network.NewIngressRule(httpRange, "10.0.0.0/8"),
+		network.NewIngressRule(httpRange, "192.168.0.0/16"),
+	})
+
+	// Widening the second service to all addresses closes the
+	// CIDR nothing uses any more.
+	err = svc2.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertEnvironIngressRules(c, []network.IngressRule{
+		network.NewIngressRule(httpRange),
+		network.NewIngressRule(httpRange, "192.168.0.0/16"),
+	})
+}
+
 func (s *GlobalModeSuite) TestStartWithUnexposedService(c *gc.C) {
 	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
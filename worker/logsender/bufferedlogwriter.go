@@ -0,0 +1,67 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logsender
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+const writerName = "buffered-logs"
+
+// BufferedLogWriter is a loggo.Writer which buffers log records until
+// they are sent to the API server by the log sender worker. When the
+// buffer is full, new records are dropped rather than blocking the
+// logging goroutine.
+type BufferedLogWriter struct {
+	records chan params.LogRecord
+}
+
+var _ loggo.Writer = (*BufferedLogWriter)(nil)
+
+// NewBufferedLogWriter returns a BufferedLogWriter with room for
+// maxLen records.
+func NewBufferedLogWriter(maxLen int) *BufferedLogWriter {
+	return &BufferedLogWriter{
+		records: make(chan params.LogRecord, maxLen),
+	}
+}
+
+// InstallBufferedLogWriter creates a BufferedLogWriter with room for
+// maxLen records and registers it with loggo, replacing any writer
+// previously installed by this function.
+func InstallBufferedLogWriter(maxLen int) (*BufferedLogWriter, error) {
+	writer := NewBufferedLogWriter(maxLen)
+	loggo.RemoveWriter(writerName)
+	if err := loggo.RegisterWriter(writerName, writer, loggo.TRACE); err != nil {
+		return nil, errors.Annotate(err, "cannot register buffered log writer")
+	}
+	return writer, nil
+}
+
+// Write implements loggo.Writer.
+func (w *BufferedLogWriter) Write(level loggo.Level, module, filename string, line int, timestamp time.Time, message string) {
+	record := params.LogRecord{
+		Time:     timestamp.UTC(),
+		Module:   module,
+		Location: fmt.Sprintf("%s:%d", filepath.Base(filename), line),
+		Level:    level,
+		Message:  message,
+	}
+	select {
+	case w.records <- record:
+	default:
+	}
+}
+
+// Logs returns the channel on which buffered records are delivered.
+func (w *BufferedLogWriter) Logs() <-chan params.LogRecord {
+	return w.records
+}
@@ -0,0 +1,53 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logsender
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/worker"
+)
+
+// maxBatchSize holds the maximum number of log records sent to the
+// API server in a single call.
+const maxBatchSize = 512
+
+// LogSink is the API used by the log sender to store log records.
+type LogSink interface {
+	WriteLogs(records []params.LogRecord) error
+}
+
+// New returns a worker that sends the log records received on the
+// given channel to the API server, batching together records that
+// arrive while a previous batch is being sent.
+func New(logs <-chan params.LogRecord, sink LogSink) worker.Worker {
+	return worker.NewSimpleWorker(func(stop <-chan struct{}) error {
+		for {
+			select {
+			case <-stop:
+				return nil
+			case record := <-logs:
+				batch := collectBatch(logs, []params.LogRecord{record})
+				if err := sink.WriteLogs(batch); err != nil {
+					return errors.Annotate(err, "cannot send log records")
+				}
+			}
+		}
+	})
+}
+
+// collectBatch adds the records already waiting on the channel to
+// the batch, up to maxBatchSize records.
+func collectBatch(logs <-chan params.LogRecord, batch []params.LogRecord) []params.LogRecord {
+	for len(batch) < maxBatchSize {
+		select {
+		case record := <-logs:
+			batch = append(batch, record)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
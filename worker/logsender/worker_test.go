@@ -0,0 +1,97 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logsender_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/logsender"
+)
+
+type workerSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&workerSuite{})
+
+type fakeLogSink struct {
+	batches chan []params.LogRecord
+	err     error
+}
+
+func (f *fakeLogSink) WriteLogs(records []params.LogRecord) error {
+	f.batches <- records
+	return f.err
+}
+
+func (s *workerSuite) TestBufferedLogWriter(c *gc.C) {
+	writer := logsender.NewBufferedLogWriter(2)
+	t0 := time.Date(2014, 11, 5, 10, 0, 0, 0, time.UTC)
+	writer.Write(loggo.INFO, "juju.worker", "/path/to/worker.go", 42, t0, "first")
+	writer.Write(loggo.ERROR, "juju.worker", "/path/to/worker.go", 43, t0, "second")
+	// The buffer is full, so this record is dropped.
+	writer.Write(loggo.ERROR, "juju.worker", "/path/to/worker.go", 44, t0, "third")
+
+	logs := writer.Logs()
+	c.Assert(<-logs, jc.DeepEquals, params.LogRecord{
+		Time:     t0,
+		Module:   "juju.worker",
+		Location: "worker.go:42",
+		Level:    loggo.INFO,
+		Message:  "first",
+	})
+	c.Assert((<-logs).Message, gc.Equals, "second")
+	select {
+	case record := <-logs:
+		c.Fatalf("unexpected log record %#v", record)
+	default:
+	}
+}
+
+func (s *workerSuite) TestSendsBatches(c *gc.C) {
+	logs := make(chan params.LogRecord, 10)
+	logs <- params.LogRecord{Message: "first"}
+	logs <- params.LogRecord{Message: "second"}
+	sink := &fakeLogSink{batches: make(chan []params.LogRecord, 10)}
+
+	w := logsender.New(logs, sink)
+	defer func() {
+		w.Kill()
+		c.Check(w.Wait(), jc.ErrorIsNil)
+	}()
+
+	var messages []string
+	for len(messages) < 3 {
+		select {
+		case batch := <-sink.batches:
+			for _, record := range batch {
+				messages = append(messages, record.Message)
+			}
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for log records")
+		}
+		if len(messages) == 2 {
+			logs <- params.LogRecord{Message: "third"}
+		}
+	}
+	c.Assert(messages, jc.DeepEquals, []string{"first", "second", "third"})
+}
+
+func (s *workerSuite) TestSendError(c *gc.C) {
+	logs := make(chan params.LogRecord, 1)
+	logs <- params.LogRecord{Message: "first"}
+	sink := &fakeLogSink{
+		batches: make(chan []params.LogRecord, 1),
+		err:     errors.New("boom"),
+	}
+	w := logsender.New(logs, sink)
+	c.Assert(w.Wait(), gc.ErrorMatches, "cannot send log records: boom")
+}
@@ -108,6 +108,9 @@ func isVotingMember(member *replicaset.Member) bool {
 func possiblePeerGroupChanges(info *peerGroupInfo, members map[*machine]*replicaset.Member) (toRemoveVote, toAddVote, toKeep []*machine) {
 	statuses := info.statusesMap(members)
 
+	// zoneVotes holds the number of voting members that will
+	// remain in each availability zone.
+	zoneVotes := make(map[string]int)
 	logger.Debugf("assessing possible peer group changes:")
 	for _, m := range info.machines {
 		member := members[m]
@@ -116,6 +119,7 @@ func possiblePeerGroupChanges(info *peerGroupInfo, members map[*machine]*replica
 		case m.wantsVote && isVoting:
 			logger.Debugf("machine %q is already voting", m.id)
 			toKeep = append(toKeep, m)
+			zoneVotes[m.zone]++
 		case m.wantsVote && !isVoting:
 			if status, ok := statuses[m]; ok && isReady(status) {
 				logger.Debugf("machine %q is a potential voter", m.id)
@@ -140,9 +144,65 @@ func possiblePeerGroupChanges(info *peerGroupInfo, members map[*machine]*replica
 	sort.Sort(byId(toRemoveVote))
 	sort.Sort(byId(toAddVote))
 	sort.Sort(byId(toKeep))
+	toAddVote = preferZoneDiversity(toAddVote, zoneVotes)
 	return toRemoveVote, toAddVote, toKeep
 }
 
+// preferZoneDiversity returns the given candidates reordered so that
+// each successive candidate is in the availability zone with the
+// fewest voting members so far, so that the votes are spread across
+// as many zones as possible. The zoneVotes map holds the number of
+// voting members already in each zone; it is updated as candidates
+// are chosen. Candidates in the same situation are kept in order.
+func preferZoneDiversity(candidates []*machine, zoneVotes map[string]int) []*machine {
+	remaining := append([]*machine(nil), candidates...)
+	ordered := make([]*machine, 0, len(candidates))
+	for len(remaining) > 0 {
+		best := 0
+		for i, m := range remaining {
+			if zoneVotes[m.zone] < zoneVotes[remaining[best].zone] {
+				best = i
+			}
+		}
+		m := remaining[best]
+		ordered = append(ordered, m)
+		zoneVotes[m.zone]++
+		remaining = append(remaining[:best], remaining[best+1:]...)
+	}
+	return ordered
+}
+
+// quorumInSingleZone reports whether the loss of a single
+// availability zone would leave the voting machines without a
+// quorum. A peer group with fewer than three voting members is not
+// highly available in the first place, and we cannot tell anything
+// if the zone of any voting machine is unknown, so false is
+// returned in both of those cases.
+func quorumInSingleZone(voting map[*machine]bool) bool {
+	zoneVotes := make(map[string]int)
+	total := 0
+	for m, isVoting := range voting {
+		if !isVoting {
+			continue
+		}
+		if m.zone == "" {
+			return false
+		}
+		zoneVotes[m.zone]++
+		total++
+	}
+	if total < 3 {
+		return false
+	}
+	quorum := total/2 + 1
+	for _, n := range zoneVotes {
+		if total-n < quorum {
+			return true
+		}
+	}
+	return false
+}
+
 // updateAddresses updates the members' addresses from the machines' addresses.
 // It reports whether any changes have been made.
 func updateAddresses(members map[*machine]*replicaset.Member, machines map[string]*machine) bool {
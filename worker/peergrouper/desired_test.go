@@ -183,6 +183,20 @@ func desiredPeerGroupTests(ipVersion TestIPVersion) []desiredPeerGroupTest {
 			members:       mkMembers("1v 2v 3v", ipVersion),
 			expectVoting:  []bool{true, true, true},
 			expectMembers: nil,
+		}, {
+			about:         "candidates in zones without a vote are preferred",
+			machines:      withZones(mkMachines("11v 12v 13v 14v", ipVersion), "a", "a", "b", "c"),
+			members:       mkMembers("1v 2 3 4", ipVersion),
+			statuses:      mkStatuses("1p 2s 3s 4s", ipVersion),
+			expectVoting:  []bool{true, false, true, true},
+			expectMembers: mkMembers("1v 2 3v 4v", ipVersion),
+		}, {
+			about:         "a candidate in a new zone takes the vote of a non-candidate",
+			machines:      withZones(mkMachines("11v 12v 13 14v 15v", ipVersion), "a", "b", "c", "a", "c"),
+			members:       mkMembers("1v 2v 3v 4 5", ipVersion),
+			statuses:      mkStatuses("1p 2s 3s 4s 5s", ipVersion),
+			expectVoting:  []bool{true, true, false, false, true},
+			expectMembers: mkMembers("1v 2v 3 4 5v", ipVersion),
 		}}
 }
 
@@ -281,6 +295,61 @@ func mkMachines(description string, ipVersion TestIPVersion) []*machine {
 	return ms
 }
 
+// withZones sets the availability zones of the given machines
+// in order, and returns them.
+func withZones(ms []*machine, zones ...string) []*machine {
+	for i, zone := range zones {
+		ms[i].zone = zone
+	}
+	return ms
+}
+
+func (*desiredPeerGroupSuite) TestQuorumInSingleZone(c *gc.C) {
+	for i, test := range []struct {
+		about  string
+		zones  []string
+		voting []bool
+		expect bool
+	}{{
+		about:  "single voting machine",
+		zones:  []string{"a"},
+		voting: []bool{true},
+	}, {
+		about:  "each voting machine in its own zone",
+		zones:  []string{"a", "b", "c"},
+		voting: []bool{true, true, true},
+	}, {
+		about:  "majority of voting machines in one zone",
+		zones:  []string{"a", "a", "b"},
+		voting: []bool{true, true, true},
+		expect: true,
+	}, {
+		about:  "non-voting machines are ignored",
+		zones:  []string{"a", "a", "b", "c"},
+		voting: []bool{true, false, true, true},
+	}, {
+		about:  "five voters with two in one zone",
+		zones:  []string{"a", "a", "b", "b", "c"},
+		voting: []bool{true, true, true, true, true},
+	}, {
+		about:  "five voters with three in one zone",
+		zones:  []string{"a", "a", "a", "b", "c"},
+		voting: []bool{true, true, true, true, true},
+		expect: true,
+	}, {
+		about:  "unknown zones",
+		zones:  []string{"", "", ""},
+		voting: []bool{true, true, true},
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		voting := make(map[*machine]bool)
+		for j, zone := range test.zones {
+			voting[&machine{id: fmt.Sprint(j), zone: zone}] = test.voting[j]
+		}
+		c.Check(quorumInSingleZone(voting), gc.Equals, test.expect)
+	}
+}
+
 func memberTag(id string) map[string]string {
 	return map[string]string{jujuMachineKey: id}
 }
@@ -226,6 +226,16 @@ func (st *fakeState) StateServerInfo() (*state.StateServerInfo, error) {
 	return deepCopy(st.stateServers.Get()).(*state.StateServerInfo), nil
 }
 
+func (st *fakeState) SetStateServersDegraded(degraded bool) error {
+	if err := errorFor("State.SetStateServersDegraded", degraded); err != nil {
+		return err
+	}
+	info := deepCopy(st.stateServers.Get()).(*state.StateServerInfo)
+	info.Degraded = degraded
+	st.stateServers.Set(info)
+	return nil
+}
+
 func (st *fakeState) WatchStateServerInfo() state.NotifyWatcher {
 	return WatchValue(&st.stateServers)
 }
@@ -244,6 +254,7 @@ type machineDoc struct {
 	instanceId     instance.Id
 	mongoHostPorts []network.HostPort
 	apiHostPorts   []network.HostPort
+	zone           string
 }
 
 func (m *fakeMachine) Refresh() error {
@@ -307,6 +318,12 @@ func (m *fakeMachine) APIHostPorts() []network.HostPort {
 	return m.doc.apiHostPorts
 }
 
+func (m *fakeMachine) AvailabilityZone() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.doc.zone
+}
+
 // mutate atomically changes the machineDoc of
 // the receiver by mutating it with the provided function.
 func (m *fakeMachine) mutate(f func(*machineDoc)) {
@@ -357,6 +374,12 @@ func (m *fakeMachine) setInstanceId(instanceId instance.Id) {
 	})
 }
 
+func (m *fakeMachine) setAvailabilityZone(zone string) {
+	m.mutate(func(doc *machineDoc) {
+		doc.zone = zone
+	})
+}
+
 // SetHasVote implements stateMachine.SetHasVote.
 func (m *fakeMachine) SetHasVote(hasVote bool) error {
 	if err := errorFor("Machine.SetHasVote", m.doc.id, hasVote); err != nil {
@@ -4,6 +4,7 @@
 package peergrouper
 
 import (
+	"github.com/juju/errors"
 	"gopkg.in/mgo.v2"
 
 	"github.com/juju/juju/network"
@@ -45,6 +46,25 @@ func (m *machineShim) MongoHostPorts() []network.HostPort {
 	return network.AddressesWithPort(m.Addresses(), m.mongoPort)
 }
 
+// AvailabilityZone returns the name of the availability zone
+// that the machine's instance is in, or the empty string if
+// it is not known.
+func (m *machineShim) AvailabilityZone() string {
+	hc, err := m.HardwareCharacteristics()
+	if errors.IsNotFound(err) {
+		// The machine has not been provisioned yet.
+		return ""
+	}
+	if err != nil {
+		logger.Warningf("cannot get hardware characteristics of machine %q: %v", m.Id(), err)
+		return ""
+	}
+	if hc.AvailabilityZone == nil {
+		return ""
+	}
+	return *hc.AvailabilityZone
+}
+
 type machineShim struct {
 	*state.Machine
 	mongoPort int
@@ -23,6 +23,7 @@ type stateInterface interface {
 	Machine(id string) (stateMachine, error)
 	WatchStateServerInfo() state.NotifyWatcher
 	StateServerInfo() (*state.StateServerInfo, error)
+	SetStateServersDegraded(degraded bool) error
 	MongoSession() mongoSession
 }
 
@@ -36,6 +37,7 @@ type stateMachine interface {
 	SetHasVote(hasVote bool) error
 	APIHostPorts() []network.HostPort
 	MongoHostPorts() []network.HostPort
+	AvailabilityZone() string
 }
 
 type mongoSession interface {
@@ -332,6 +334,27 @@ func (w *pgWorker) updateReplicaset() error {
 	if err := setHasVote(removed, false); err != nil {
 		return err
 	}
+	return w.updateDegraded(quorumInSingleZone(voting))
+}
+
+// updateDegraded records in the state whether the quorum of the
+// voting state servers depends on a single availability zone.
+func (w *pgWorker) updateDegraded(degraded bool) error {
+	info, err := w.st.StateServerInfo()
+	if err != nil {
+		return fmt.Errorf("cannot get state server info: %v", err)
+	}
+	if info.Degraded == degraded {
+		return nil
+	}
+	if degraded {
+		logger.Warningf("state server quorum depends on a single availability zone")
+	} else {
+		logger.Infof("state server quorum no longer depends on a single availability zone")
+	}
+	if err := w.st.SetStateServersDegraded(degraded); err != nil {
+		return fmt.Errorf("cannot set state server availability: %v", err)
+	}
 	return nil
 }
 
@@ -444,6 +467,7 @@ type machine struct {
 	wantsVote      bool
 	apiHostPorts   []network.HostPort
 	mongoHostPorts []network.HostPort
+	zone           string
 
 	worker         *pgWorker
 	stm            stateMachine
@@ -459,7 +483,7 @@ func (m *machine) String() string {
 }
 
 func (m *machine) GoString() string {
-	return fmt.Sprintf("&peergrouper.machine{id: %q, wantsVote: %v, hostPort: %q}", m.id, m.wantsVote, m.mongoHostPort())
+	return fmt.Sprintf("&peergrouper.machine{id: %q, wantsVote: %v, hostPort: %q, zone: %q}", m.id, m.wantsVote, m.mongoHostPort(), m.zone)
 }
 
 func (w *pgWorker) newMachine(stm stateMachine) *machine {
@@ -470,6 +494,7 @@ func (w *pgWorker) newMachine(stm stateMachine) *machine {
 		apiHostPorts:   stm.APIHostPorts(),
 		mongoHostPorts: stm.MongoHostPorts(),
 		wantsVote:      stm.WantsVote(),
+		zone:           stm.AvailabilityZone(),
 		machineWatcher: stm.Watch(),
 	}
 	w.start(m.loop)
@@ -520,6 +545,10 @@ func (m *machine) refresh() (bool, error) {
 		m.apiHostPorts = hps
 		changed = true
 	}
+	if zone := m.stm.AvailabilityZone(); zone != m.zone {
+		m.zone = zone
+		changed = true
+	}
 	return changed, nil
 }
 
@@ -14,6 +14,7 @@ import (
 
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker"
 )
@@ -174,6 +175,45 @@ func (s *workerSuite) TestSetsAndUpdatesMembers(c *gc.C) {
 	})
 }
 
+func (s *workerSuite) TestReportsDegradedWhenQuorumInSingleZone(c *gc.C) {
+	DoTestForIPv4AndIPv6(func(ipVersion TestIPVersion) {
+		s.PatchValue(&pollInterval, 5*time.Millisecond)
+
+		st := NewFakeState()
+		InitState(c, st, 3, ipVersion)
+		st.machine("10").setAvailabilityZone("zone-a")
+		st.machine("11").setAvailabilityZone("zone-a")
+		st.machine("12").setAvailabilityZone("zone-b")
+		st.session.InstantlyReady = true
+
+		infoWatcher := st.stateServers.Watch()
+		mustNext(c, infoWatcher)
+
+		w := newWorker(st, noPublisher{})
+		defer func() {
+			c.Check(worker.Stop(w), gc.IsNil)
+		}()
+
+		// Once all three machines vote, two of them share a zone, so
+		// losing that zone would lose the quorum.
+		for {
+			info := mustNext(c, infoWatcher).(*state.StateServerInfo)
+			if info.Degraded {
+				break
+			}
+		}
+
+		// Moving a machine to another zone spreads the votes again.
+		st.machine("11").setAvailabilityZone("zone-c")
+		for {
+			info := mustNext(c, infoWatcher).(*state.StateServerInfo)
+			if !info.Degraded {
+				break
+			}
+		}
+	})
+}
+
 func (s *workerSuite) TestHasVoteMaintainedEvenWhenReplicaSetFails(c *gc.C) {
 	DoTestForIPv4AndIPv6(func(ipVersion TestIPVersion) {
 		st := NewFakeState()
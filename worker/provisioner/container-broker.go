@@ -0,0 +1,126 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+)
+
+var _ environs.InstanceBroker = (*containerBroker)(nil)
+
+// containerBroker is an environs.InstanceBroker that starts and stops
+// containers of a single type with a container.Manager. It is shared
+// by the container types that need nothing beyond what the manager
+// provides.
+type containerBroker struct {
+	containerType instance.ContainerType
+	manager       container.Manager
+	api           APICalls
+	agentConfig   agent.Config
+	logger        loggo.Logger
+
+	// defaultBridge holds the bridge the containers are attached to
+	// when none is given in the agent config.
+	defaultBridge string
+}
+
+// newContainerBroker returns a broker for containers of the given
+// type, managed by the given manager.
+func newContainerBroker(
+	containerType instance.ContainerType,
+	manager container.Manager,
+	api APICalls,
+	agentConfig agent.Config,
+	defaultBridge string,
+) *containerBroker {
+	return &containerBroker{
+		containerType: containerType,
+		manager:       manager,
+		api:           api,
+		agentConfig:   agentConfig,
+		logger:        loggo.GetLogger(fmt.Sprintf("juju.provisioner.%s", containerType)),
+		defaultBridge: defaultBridge,
+	}
+}
+
+// StartInstance is specified in the Broker interface.
+func (broker *containerBroker) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	if args.MachineConfig.HasNetworks() {
+		return nil, errors.Errorf("starting %s containers with networks is not supported yet", broker.containerType)
+	}
+	machineId := args.MachineConfig.MachineId
+	broker.logger.Infof("starting %s container for machineId: %s", broker.containerType, machineId)
+
+	// TODO: Default to using the host network until we can configure.  Yes,
+	// this is using the LxcBridge value, we should put it in the api call for
+	// container config.
+	bridgeDevice := broker.agentConfig.Value(agent.LxcBridge)
+	if bridgeDevice == "" {
+		bridgeDevice = broker.defaultBridge
+	}
+	network := container.BridgeNetworkConfig(bridgeDevice)
+
+	series := args.Tools.OneSeries()
+	args.MachineConfig.MachineContainerType = broker.containerType
+	args.MachineConfig.Tools = args.Tools[0]
+
+	config, err := broker.api.ContainerConfig()
+	if err != nil {
+		broker.logger.Errorf("failed to get container config: %v", err)
+		return nil, err
+	}
+
+	if err := environs.PopulateMachineConfig(
+		args.MachineConfig,
+		config.ProviderType,
+		config.AuthorizedKeys,
+		config.SSLHostnameVerification,
+		config.Proxy,
+		config.AptProxy,
+		config.AptMirror,
+		config.PreferIPv6,
+		config.EnableOSRefreshUpdate,
+		config.EnableOSUpgrade,
+	); err != nil {
+		broker.logger.Errorf("failed to populate machine config: %v", err)
+		return nil, err
+	}
+
+	inst, hardware, err := broker.manager.CreateContainer(args.MachineConfig, series, network)
+	if err != nil {
+		broker.logger.Errorf("failed to start container: %v", err)
+		return nil, err
+	}
+	broker.logger.Infof("started %s container for machineId: %s, %s, %s", broker.containerType, machineId, inst.Id(), hardware.String())
+	return &environs.StartInstanceResult{
+		Instance: inst,
+		Hardware: hardware,
+	}, nil
+}
+
+// StopInstances shuts down the given instances.
+func (broker *containerBroker) StopInstances(ids ...instance.Id) error {
+	// TODO: potentially parallelise.
+	for _, id := range ids {
+		broker.logger.Infof("stopping %s container for instance: %s", broker.containerType, id)
+		if err := broker.manager.DestroyContainer(id); err != nil {
+			broker.logger.Errorf("container did not stop: %v", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// AllInstances only returns running containers.
+func (broker *containerBroker) AllInstances() (result []instance.Instance, err error) {
+	return broker.manager.ListContainers()
+}
@@ -0,0 +1,110 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner_test
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	instancetest "github.com/juju/juju/instance/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	coretesting "github.com/juju/juju/testing"
+	coretools "github.com/juju/juju/tools"
+	"github.com/juju/juju/version"
+)
+
+// containerBrokerFixture holds the tests shared by the brokers of the
+// container types backed by a fake daemon.
+type containerBrokerFixture struct {
+	containerType instance.ContainerType
+	broker        environs.InstanceBroker
+	agentConfig   agent.ConfigSetterWriter
+	containerDir  string
+	removedDir    string
+}
+
+func newBrokerAgentConfig(c *gc.C) agent.ConfigSetterWriter {
+	agentConfig, err := agent.NewAgentConfig(
+		agent.AgentConfigParams{
+			DataDir:           "/not/used/here",
+			Tag:               names.NewUnitTag("ubuntu/1"),
+			UpgradedToVersion: version.Current.Number,
+			Password:          "dummy-secret",
+			Nonce:             "nonce",
+			APIAddresses:      []string{"10.0.0.1:1234"},
+			CACert:            coretesting.CACert,
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	return agentConfig
+}
+
+// machineId returns the id of the nth container of the fixture's type
+// on machine 1.
+func (f *containerBrokerFixture) machineId(n int) string {
+	return fmt.Sprintf("1/%s/%d", f.containerType, n)
+}
+
+func (f *containerBrokerFixture) startInstanceParams(c *gc.C, machineId string) environs.StartInstanceParams {
+	machineNonce := "fake-nonce"
+	stateInfo := jujutesting.FakeStateInfo(machineId)
+	apiInfo := jujutesting.FakeAPIInfo(machineId)
+	machineConfig, err := environs.NewMachineConfig(machineId, machineNonce, "released", "quantal", nil, stateInfo, apiInfo)
+	c.Assert(err, jc.ErrorIsNil)
+	possibleTools := coretools.List{&coretools.Tools{
+		Version: version.MustParseBinary("2.3.4-quantal-amd64"),
+		URL:     "http://tools.testing.invalid/2.3.4-quantal-amd64.tgz",
+	}}
+	return environs.StartInstanceParams{
+		Constraints:   constraints.Value{},
+		Tools:         possibleTools,
+		MachineConfig: machineConfig,
+	}
+}
+
+func (f *containerBrokerFixture) startInstance(c *gc.C, n int) instance.Instance {
+	result, err := f.broker.StartInstance(f.startInstanceParams(c, f.machineId(n)))
+	c.Assert(err, jc.ErrorIsNil)
+	return result.Instance
+}
+
+func (f *containerBrokerFixture) assertInstances(c *gc.C, inst ...instance.Instance) {
+	results, err := f.broker.AllInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	instancetest.MatchInstances(c, results, inst...)
+}
+
+func (f *containerBrokerFixture) checkStopInstance(c *gc.C) {
+	inst0 := f.startInstance(c, 0)
+	inst1 := f.startInstance(c, 1)
+	inst2 := f.startInstance(c, 2)
+
+	err := f.broker.StopInstances(inst0.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	f.assertInstances(c, inst1, inst2)
+	c.Assert(filepath.Join(f.containerDir, string(inst0.Id())), jc.DoesNotExist)
+	c.Assert(filepath.Join(f.removedDir, string(inst0.Id())), jc.IsDirectory)
+
+	err = f.broker.StopInstances(inst1.Id(), inst2.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	f.assertInstances(c)
+}
+
+func (f *containerBrokerFixture) checkAllInstances(c *gc.C) {
+	inst0 := f.startInstance(c, 0)
+	inst1 := f.startInstance(c, 1)
+	f.assertInstances(c, inst0, inst1)
+
+	err := f.broker.StopInstances(inst1.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	inst2 := f.startInstance(c, 2)
+	f.assertInstances(c, inst0, inst2)
+}
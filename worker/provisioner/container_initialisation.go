@@ -15,8 +15,10 @@ import (
 	"github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/container"
+	"github.com/juju/juju/container/docker"
 	"github.com/juju/juju/container/kvm"
 	"github.com/juju/juju/container/lxc"
+	"github.com/juju/juju/container/lxd"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
@@ -179,6 +181,25 @@ func (cs *ContainerSetup) getContainerArtifacts(containerType instance.Container
 			logger.Errorf("failed to create new kvm broker")
 			return nil, nil, err
 		}
+	case instance.LXD:
+		series, err := cs.machine.Series()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		initialiser = lxd.NewContainerInitialiser(series)
+		broker, err = NewLxdBroker(cs.provisioner, cs.config, managerConfig)
+		if err != nil {
+			logger.Errorf("failed to create new lxd broker")
+			return nil, nil, err
+		}
+	case instance.DOCKER:
+		initialiser = docker.NewContainerInitialiser(dockerBridge(cs.config))
+		broker, err = NewDockerBroker(cs.provisioner, cs.config, managerConfig)
+		if err != nil {
+			logger.Errorf("failed to create new docker broker")
+			return nil, nil, err
+		}
 	default:
 		return nil, nil, fmt.Errorf("unknown container type: %v", containerType)
 	}
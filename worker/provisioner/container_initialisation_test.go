@@ -146,7 +146,7 @@ func (s *ContainerSetupSuite) TestContainerProvisionerStarted(c *gc.C) {
 			Constraints: s.defaultConstraints,
 		})
 		c.Assert(err, jc.ErrorIsNil)
-		err = m.SetSupportedContainers([]instance.ContainerType{instance.LXC, instance.KVM})
+		err = m.SetSupportedContainers(instance.ContainerTypes)
 		c.Assert(err, jc.ErrorIsNil)
 		err = m.SetAgentVersion(version.Current)
 		c.Assert(err, jc.ErrorIsNil)
@@ -181,7 +181,7 @@ func (s *ContainerSetupSuite) assertContainerInitialised(c *gc.C, ctype instance
 		Constraints: s.defaultConstraints,
 	})
 	c.Assert(err, jc.ErrorIsNil)
-	err = m.SetSupportedContainers([]instance.ContainerType{instance.LXC, instance.KVM})
+	err = m.SetSupportedContainers(instance.ContainerTypes)
 	c.Assert(err, jc.ErrorIsNil)
 	err = m.SetAgentVersion(version.Current)
 	c.Assert(err, jc.ErrorIsNil)
@@ -204,6 +204,8 @@ func (s *ContainerSetupSuite) TestContainerInitialised(c *gc.C) {
 	}{
 		{instance.LXC, []string{"--target-release", "precise-updates/cloud-tools", "lxc", "cloud-image-utils"}},
 		{instance.KVM, []string{"uvtool-libvirt", "uvtool"}},
+		{instance.LXD, []string{"lxd"}},
+		{instance.DOCKER, []string{"docker.io"}},
 	} {
 		s.assertContainerInitialised(c, test.ctype, test.packages)
 	}
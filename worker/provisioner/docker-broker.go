@@ -0,0 +1,35 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner
+
+import (
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/container/docker"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+)
+
+// NewDockerBroker returns a broker that starts and stops docker
+// containers.
+func NewDockerBroker(
+	api APICalls,
+	agentConfig agent.Config,
+	managerConfig container.ManagerConfig,
+) (environs.InstanceBroker, error) {
+	manager, err := docker.NewContainerManager(managerConfig)
+	if err != nil {
+		return nil, err
+	}
+	return newContainerBroker(instance.DOCKER, manager, api, agentConfig, docker.DefaultDockerBridge), nil
+}
+
+// dockerBridge returns the host bridge the docker daemon attaches
+// containers to.
+func dockerBridge(agentConfig agent.Config) string {
+	if bridge := agentConfig.Value(agent.LxcBridge); bridge != "" {
+		return bridge
+	}
+	return docker.DefaultDockerBridge
+}
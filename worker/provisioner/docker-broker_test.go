@@ -0,0 +1,70 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/container"
+	dockertesting "github.com/juju/juju/container/docker/testing"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/worker/provisioner"
+)
+
+type dockerBrokerSuite struct {
+	dockertesting.TestSuite
+	containerBrokerFixture
+}
+
+var _ = gc.Suite(&dockerBrokerSuite{})
+
+func (s *dockerBrokerSuite) SetUpTest(c *gc.C) {
+	s.TestSuite.SetUpTest(c)
+	s.containerBrokerFixture = containerBrokerFixture{
+		containerType: instance.DOCKER,
+		agentConfig:   newBrokerAgentConfig(c),
+		containerDir:  s.ContainerDir,
+		removedDir:    s.RemovedDir,
+	}
+	// The initialiser configures the daemon with the host bridge.
+	s.Server.Bridge = "br0"
+	managerConfig := container.ManagerConfig{container.ConfigName: "juju"}
+	var err error
+	s.broker, err = provisioner.NewDockerBroker(&fakeAPI{}, s.agentConfig, managerConfig)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *dockerBrokerSuite) TestStartInstance(c *gc.C) {
+	docker0 := s.startInstance(c, 0)
+	c.Assert(docker0.Id(), gc.Equals, instance.Id("juju-machine-1-docker-0"))
+	created := s.Server.Container(string(docker0.Id()))
+	c.Assert(created, gc.NotNil)
+	c.Assert(created.Running, jc.IsTrue)
+	c.Assert(created.Config.HostConfig.Privileged, jc.IsTrue)
+	c.Assert(created.Config.HostConfig.NetworkMode, gc.Equals, "bridge")
+}
+
+func (s *dockerBrokerSuite) TestStartInstanceWithBridgeEnviron(c *gc.C) {
+	s.agentConfig.SetValue(agent.LxcBridge, "virbr0")
+	s.Server.Bridge = "virbr0"
+	docker0 := s.startInstance(c, 0)
+	s.assertInstances(c, docker0)
+}
+
+func (s *dockerBrokerSuite) TestStartInstanceDaemonNotBridged(c *gc.C) {
+	s.Server.Bridge = "docker0"
+	_, err := s.broker.StartInstance(s.startInstanceParams(c, s.machineId(0)))
+	c.Assert(err, gc.ErrorMatches, `.*container attached to bridge "docker0", not "br0"`)
+	s.assertInstances(c)
+}
+
+func (s *dockerBrokerSuite) TestStopInstance(c *gc.C) {
+	s.checkStopInstance(c)
+}
+
+func (s *dockerBrokerSuite) TestAllInstances(c *gc.C) {
+	s.checkAllInstances(c)
+}
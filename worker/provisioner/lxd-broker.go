@@ -0,0 +1,25 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner
+
+import (
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/container/lxd"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+)
+
+// NewLxdBroker returns a broker that starts and stops lxd containers.
+func NewLxdBroker(
+	api APICalls,
+	agentConfig agent.Config,
+	managerConfig container.ManagerConfig,
+) (environs.InstanceBroker, error) {
+	manager, err := lxd.NewContainerManager(managerConfig)
+	if err != nil {
+		return nil, err
+	}
+	return newContainerBroker(instance.LXD, manager, api, agentConfig, lxd.DefaultLxdBridge), nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/container"
+	lxdtesting "github.com/juju/juju/container/lxd/testing"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/worker/provisioner"
+)
+
+type lxdBrokerSuite struct {
+	lxdtesting.TestSuite
+	containerBrokerFixture
+}
+
+var _ = gc.Suite(&lxdBrokerSuite{})
+
+func (s *lxdBrokerSuite) SetUpTest(c *gc.C) {
+	s.TestSuite.SetUpTest(c)
+	s.containerBrokerFixture = containerBrokerFixture{
+		containerType: instance.LXD,
+		agentConfig:   newBrokerAgentConfig(c),
+		containerDir:  s.ContainerDir,
+		removedDir:    s.RemovedDir,
+	}
+	managerConfig := container.ManagerConfig{container.ConfigName: "juju"}
+	var err error
+	s.broker, err = provisioner.NewLxdBroker(&fakeAPI{}, s.agentConfig, managerConfig)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *lxdBrokerSuite) TestStartInstance(c *gc.C) {
+	lxd0 := s.startInstance(c, 0)
+	c.Assert(lxd0.Id(), gc.Equals, instance.Id("juju-machine-1-lxd-0"))
+	created := s.Server.Container(string(lxd0.Id()))
+	c.Assert(created, gc.NotNil)
+	c.Assert(created.Profiles, jc.DeepEquals, []string{"default", "juju-lxcbr0"})
+}
+
+func (s *lxdBrokerSuite) TestStopInstance(c *gc.C) {
+	s.checkStopInstance(c)
+}
+
+func (s *lxdBrokerSuite) TestAllInstances(c *gc.C) {
+	s.checkAllInstances(c)
+}
@@ -143,6 +143,7 @@ func (p *provisioner) getStartTask(harvestMode config.HarvestMode) (ProvisionerT
 		p.broker,
 		auth,
 		envCfg.ImageStream(),
+		newRetryStrategy(envCfg),
 	)
 	return task, nil
 }
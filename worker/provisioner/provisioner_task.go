@@ -5,6 +5,8 @@ package provisioner
 
 import (
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/juju/errors"
@@ -23,6 +25,7 @@ import (
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
+	providercommon "github.com/juju/juju/provider/common"
 	"github.com/juju/juju/state/watcher"
 	coretools "github.com/juju/juju/tools"
 	"github.com/juju/juju/version"
@@ -57,6 +60,33 @@ type ToolsFinder interface {
 var _ MachineGetter = (*apiprovisioner.State)(nil)
 var _ ToolsFinder = (*apiprovisioner.State)(nil)
 
+// RetryStrategy defines how the provisioner task retries starting
+// an instance when the provider reports a transient error.
+type RetryStrategy struct {
+	// Attempts is the total number of times to try starting
+	// the instance. The instance is always tried at least once,
+	// so both 0 and 1 disable retrying.
+	Attempts int
+
+	// Delay is the time to wait before the first retry. It
+	// doubles after every further failed attempt. A zero Delay
+	// retries immediately.
+	Delay time.Duration
+}
+
+// newRetryStrategy returns the RetryStrategy configured for the
+// environment. It is a variable so it can be overridden in tests.
+var newRetryStrategy = func(cfg *config.Config) RetryStrategy {
+	return RetryStrategy{
+		Attempts: cfg.ProvisionerStartAttempts(),
+		Delay:    cfg.ProvisionerRetryDelay(),
+	}
+}
+
+// maxConcurrentStarts is the maximum number of machines the
+// provisioner task starts instances for at the same time.
+var maxConcurrentStarts = 10
+
 func NewProvisionerTask(
 	machineTag names.MachineTag,
 	harvestMode config.HarvestMode,
@@ -67,6 +97,7 @@ func NewProvisionerTask(
 	broker environs.InstanceBroker,
 	auth authentication.AuthenticationProvider,
 	imageStream string,
+	retryStrategy RetryStrategy,
 ) ProvisionerTask {
 	task := &provisionerTask{
 		machineTag:      machineTag,
@@ -79,7 +110,9 @@ func NewProvisionerTask(
 		harvestMode:     harvestMode,
 		harvestModeChan: make(chan config.HarvestMode, 1),
 		machines:        make(map[string]*apiprovisioner.Machine),
+		pendingZones:    make(map[serviceZone]int),
 		imageStream:     imageStream,
+		retryStrategy:   retryStrategy,
 	}
 	go func() {
 		defer task.tomb.Done()
@@ -98,12 +131,27 @@ type provisionerTask struct {
 	tomb            tomb.Tomb
 	auth            authentication.AuthenticationProvider
 	imageStream     string
+	retryStrategy   RetryStrategy
 	harvestMode     config.HarvestMode
 	harvestModeChan chan config.HarvestMode
 	// instance id -> instance
 	instances map[instance.Id]instance.Instance
 	// machine id -> machine
 	machines map[string]*apiprovisioner.Machine
+
+	// zoneMutex serialises the choice of availability zones, and
+	// guards pendingZones, which counts the instances of each service
+	// being started in each zone that are not yet recorded against
+	// their machines.
+	zoneMutex    sync.Mutex
+	pendingZones map[serviceZone]int
+}
+
+// serviceZone identifies the instances of a service in an
+// availability zone. Machines without units share the service "".
+type serviceZone struct {
+	service string
+	zone    string
 }
 
 // Kill implements worker.Worker.Kill.
@@ -482,45 +530,163 @@ func constructStartInstanceParams(
 		MachineConfig:     machineConfig,
 		Placement:         provisioningInfo.Placement,
 		DistributionGroup: machine.DistributionGroup,
+		Subnets:           provisioningInfo.Subnets,
 	}
 }
 
+// startMachines starts instances for the given machines, at most
+// maxConcurrentStarts at a time. A failure to start an instance is
+// recorded on its machine and does not affect the other machines; an
+// error is only returned if the task cannot carry on.
 func (task *provisionerTask) startMachines(machines []*apiprovisioner.Machine) error {
+	var wg sync.WaitGroup
+	errs := make(chan error, len(machines))
+	sem := make(chan struct{}, maxConcurrentStarts)
 	for _, m := range machines {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(m *apiprovisioner.Machine) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := task.provisionMachine(m); err != nil {
+				errs <- errors.Annotatef(err, "cannot start machine %v", m)
+			}
+		}(m)
+	}
+	wg.Wait()
+	close(errs)
+	// Report the first error, if any.
+	return <-errs
+}
 
-		pInfo, err := task.blockUntilProvisioned(m.ProvisioningInfo)
-		if err != nil {
-			return err
-		}
+// provisionMachine prepares and starts an instance for the given
+// machine.
+func (task *provisionerTask) provisionMachine(m *apiprovisioner.Machine) error {
+	pInfo, err := task.blockUntilProvisioned(m.ProvisioningInfo)
+	if err == tomb.ErrDying {
+		return err
+	} else if err != nil {
+		return task.setErrorStatus("cannot get provisioning info for machine %q: %v", m, err)
+	}
+
+	if pInfo.Constraints.HaveSpaces() && len(pInfo.Subnets) == 0 {
+		err := errors.New("no subnets match the spaces constraint")
+		return task.setErrorStatus("cannot start instance for machine %q: %v", m, err)
+	}
 
-		machineCfg, err := task.constructMachineConfig(m, task.auth, pInfo)
+	if pInfo.Constraints.HaveZones() {
+		placement, release, err := task.zonePlacement(m, pInfo)
 		if err != nil {
-			return err
+			return task.setErrorStatus("cannot start instance for machine %q: %v", m, err)
 		}
+		defer release()
+		pInfo.Placement = placement
+	}
 
-		assocProvInfoAndMachCfg(pInfo, machineCfg)
+	machineCfg, err := task.constructMachineConfig(m, task.auth, pInfo)
+	if err != nil {
+		return task.setErrorStatus("cannot create machine config for machine %q: %v", m, err)
+	}
 
-		possibleTools, err := task.toolsFinder.FindTools(
-			version.Current.Number,
-			pInfo.Series,
-			pInfo.Constraints.Arch,
-		)
-		if err != nil {
-			return task.setErrorStatus("cannot find tools for machine %q: %v", m, err)
+	assocProvInfoAndMachCfg(pInfo, machineCfg)
+
+	possibleTools, err := task.toolsFinder.FindTools(
+		version.Current.Number,
+		pInfo.Series,
+		pInfo.Constraints.Arch,
+	)
+	if err != nil {
+		return task.setErrorStatus("cannot find tools for machine %q: %v", m, err)
+	}
+
+	startInstanceParams := constructStartInstanceParams(
+		m,
+		machineCfg,
+		pInfo,
+		possibleTools,
+	)
+
+	return task.startMachine(m, pInfo, startInstanceParams)
+}
+
+// zonePlacement returns the placement directive to start the machine
+// with, given the zones constraint in its provisioning info. Unless the
+// machine already has a placement directive, the machine is placed in
+// the allowed zone with the fewest instances of its distribution
+// group, so that the units of a service are spread across zones. The
+// returned function must be called once the instance has been started
+// and recorded, or has failed to start.
+func (task *provisionerTask) zonePlacement(
+	m *apiprovisioner.Machine,
+	pInfo *params.ProvisioningInfo,
+) (string, func(), error) {
+	noRelease := func() {}
+	allowed := set.NewStrings(*pInfo.Constraints.Zones...)
+	if pInfo.Placement != "" {
+		if strings.HasPrefix(pInfo.Placement, "zone=") {
+			zone := strings.TrimPrefix(pInfo.Placement, "zone=")
+			if !allowed.Contains(zone) {
+				return "", nil, errors.Errorf("zone %q is not allowed by the zones constraint", zone)
+			}
 		}
+		return pInfo.Placement, noRelease, nil
+	}
+	zonedEnviron, ok := task.broker.(providercommon.ZonedEnviron)
+	if !ok {
+		logger.Warningf("ignoring zones constraint for machine %q: availability zones are not supported", m)
+		return "", noRelease, nil
+	}
+	group, err := m.DistributionGroup()
+	if err != nil {
+		return "", nil, errors.Annotate(err, "cannot get distribution group")
+	}
 
-		startInstanceParams := constructStartInstanceParams(
-			m,
-			machineCfg,
-			pInfo,
-			possibleTools,
-		)
+	// The pending instances are counted per service rather than per
+	// distribution group: the instances making up a group change as
+	// its machines are provisioned, but the services do not.
+	services := pInfo.Services
+	if len(services) == 0 {
+		services = []string{""}
+	}
 
-		if err := task.startMachine(m, pInfo, startInstanceParams); err != nil {
-			return errors.Annotatef(err, "cannot start machine %v", m)
+	task.zoneMutex.Lock()
+	defer task.zoneMutex.Unlock()
+	allocations, err := providercommon.AvailabilityZoneAllocations(zonedEnviron, group)
+	if err != nil {
+		return "", nil, errors.Annotate(err, "cannot get availability zone allocations")
+	}
+	var best string
+	var bestCount int
+	for _, zone := range allocations {
+		if !allowed.Contains(zone.ZoneName) {
+			continue
+		}
+		count := len(zone.Instances)
+		for _, service := range services {
+			count += task.pendingZones[serviceZone{service, zone.ZoneName}]
+		}
+		if best == "" || count < bestCount {
+			best, bestCount = zone.ZoneName, count
 		}
 	}
-	return nil
+	if best == "" {
+		return "", nil, errors.New("no available zones match the zones constraint")
+	}
+	for _, service := range services {
+		task.pendingZones[serviceZone{service, best}]++
+	}
+	release := func() {
+		task.zoneMutex.Lock()
+		defer task.zoneMutex.Unlock()
+		for _, service := range services {
+			key := serviceZone{service, best}
+			task.pendingZones[key]--
+			if task.pendingZones[key] == 0 {
+				delete(task.pendingZones, key)
+			}
+		}
+	}
+	return "zone=" + best, release, nil
 }
 
 func (task *provisionerTask) setErrorStatus(message string, machine *apiprovisioner.Machine, err error) error {
@@ -566,21 +732,14 @@ func (task *provisionerTask) startMachine(
 	startInstanceParams environs.StartInstanceParams,
 ) error {
 
-	result, err := task.broker.StartInstance(startInstanceParams)
-	if err != nil {
-		// If this is a retryable error, we retry once
-		if instance.IsRetryableCreationError(errors.Cause(err)) {
-			logger.Infof("retryable error received on start instance - retrying instance creation")
-			result, err = task.broker.StartInstance(startInstanceParams)
-			if err != nil {
-				return task.setErrorStatus("cannot start instance for machine after a retry %q: %v", machine, err)
-			}
-		} else {
-			// Set the state to error, so the machine will be skipped next
-			// time until the error is resolved, but don't return an
-			// error; just keep going with the other machines.
-			return task.setErrorStatus("cannot start instance for machine %q: %v", machine, err)
-		}
+	result, err := task.startInstance(machine, startInstanceParams)
+	if err == tomb.ErrDying {
+		return err
+	} else if err != nil {
+		// Set the state to error, so the machine will be skipped next
+		// time until the error is resolved, but don't return an
+		// error; just keep going with the other machines.
+		return task.setErrorStatus("cannot start instance for machine %q: %v", machine, err)
 	}
 
 	inst := result.Instance
@@ -604,6 +763,35 @@ func (task *provisionerTask) startMachine(
 	return nil
 }
 
+// startInstance starts an instance for the given machine, retrying
+// with exponential backoff as long as the provider reports retryable
+// errors, up to the number of attempts in the task's retry strategy.
+func (task *provisionerTask) startInstance(
+	machine *apiprovisioner.Machine,
+	startInstanceParams environs.StartInstanceParams,
+) (*environs.StartInstanceResult, error) {
+	delay := task.retryStrategy.Delay
+	for attempt := 1; ; attempt++ {
+		result, err := task.broker.StartInstance(startInstanceParams)
+		if err == nil {
+			return result, nil
+		}
+		if attempt >= task.retryStrategy.Attempts || !instance.IsRetryableCreationError(errors.Cause(err)) {
+			return nil, err
+		}
+		logger.Infof(
+			"retryable error starting instance for machine %v (attempt %d of %d), retrying in %v: %v",
+			machine, attempt, task.retryStrategy.Attempts, delay, err,
+		)
+		select {
+		case <-task.tomb.Dying():
+			return nil, tomb.ErrDying
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
 type provisioningInfo struct {
 	Constraints   constraints.Value
 	Series        string
@@ -6,6 +6,7 @@ package provisioner_test
 import (
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/juju/errors"
@@ -32,6 +33,7 @@ import (
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/network"
+	providercommon "github.com/juju/juju/provider/common"
 	"github.com/juju/juju/provider/dummy"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
@@ -77,6 +79,11 @@ func (s *CommonProvisionerSuite) SetUpTest(c *gc.C) {
 
 	s.JujuConnSuite.SetUpTest(c)
 
+	// Retry starting instances without waiting for long.
+	s.PatchValue(provisioner.NewRetryStrategy, func(*config.Config) provisioner.RetryStrategy {
+		return testRetryStrategy
+	})
+
 	// Create the operations channel with more than enough space
 	// for those tests that don't listen on it.
 	op := make(chan dummy.Operation, 500)
@@ -118,6 +125,11 @@ func (s *CommonProvisionerSuite) SetUpTest(c *gc.C) {
 	c.Assert(s.provisioner, gc.NotNil)
 }
 
+var testRetryStrategy = provisioner.RetryStrategy{
+	Attempts: 3,
+	Delay:    time.Millisecond,
+}
+
 // breakDummyProvider changes the environment config in state in a way
 // that causes the given environMethod of the dummy provider to return
 // an error, which is also returned as a message to be checked.
@@ -381,6 +393,26 @@ func (s *CommonProvisionerSuite) waitInstanceId(c *gc.C, m *state.Machine, expec
 	})
 }
 
+// waitErrorStatus waits until the supplied machine is no longer
+// pending, then asserts it is in error with the expected info.
+func (s *CommonProvisionerSuite) waitErrorStatus(c *gc.C, m *state.Machine, expectInfo string) {
+	timeout := time.After(coretesting.LongWait)
+	for {
+		status, info, _, err := m.Status()
+		c.Assert(err, jc.ErrorIsNil)
+		if status != state.StatusPending {
+			c.Assert(status, gc.Equals, state.StatusError)
+			c.Assert(info, gc.Equals, expectInfo)
+			return
+		}
+		select {
+		case <-time.After(coretesting.ShortWait):
+		case <-timeout:
+			c.Fatalf("machine %v is still pending", m)
+		}
+	}
+}
+
 func (s *CommonProvisionerSuite) newEnvironProvisioner(c *gc.C) provisioner.Provisioner {
 	machineTag := names.NewMachineTag("0")
 	agentConfig := s.AgentConfigForTag(c, machineTag)
@@ -572,7 +604,8 @@ func (s *ProvisionerSuite) TestProvisionerFailedStartInstanceWithInjectedCreatio
 
 	retryableError := instance.NewRetryableCreationError("container failed to start and was destroyed")
 	destroyError := errors.New("container failed to start and failed to destroy: manual cleanup of containers needed")
-	// send the error message TWICE, because the provisioner will retry only ONCE
+	// send a retryable error followed by one that isn't, which
+	// stops the provisioner from retrying any further
 	errorInjectionChannel <- retryableError
 	errorInjectionChannel <- destroyError
 
@@ -754,6 +787,69 @@ func (s *ProvisionerSuite) TestProvisioningMachinesWithRequestedNetworks(c *gc.C
 	s.waitRemoved(c, m)
 }
 
+func (s *ProvisionerSuite) TestProvisioningMachinesWithSpaces(c *gc.C) {
+	_, err := s.State.AddSubnet(state.SubnetInfo{CIDR: "0.10.0.0/8", ProviderId: "dummy-private"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("db", []string{"0.10.0.0/8"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	p := s.newEnvironProvisioner(c)
+	defer stop(c, p)
+
+	cons := constraints.MustParse(s.defaultConstraints.String(), "spaces=db")
+	m, err := s.BackingState.AddOneMachine(state.MachineTemplate{
+		Series:      coretesting.FakeDefaultSeries,
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Constraints: cons,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.BackingState.StartSync()
+	for {
+		select {
+		case o := <-s.op:
+			start, ok := o.(dummy.OpStartInstance)
+			if !ok {
+				continue
+			}
+			c.Assert(start.MachineId, gc.Equals, m.Id())
+			c.Assert(start.Subnets, jc.DeepEquals, []network.BasicInfo{
+				{CIDR: "0.10.0.0/8", ProviderId: "dummy-private"},
+			})
+			return
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("provisioner did not start an instance")
+		}
+	}
+}
+
+func (s *ProvisionerSuite) TestProvisionerSetsErrorStatusWhenNoSubnetsMatchSpaces(c *gc.C) {
+	p := s.newEnvironProvisioner(c)
+	defer stop(c, p)
+
+	cons := constraints.MustParse(s.defaultConstraints.String(), "spaces=db")
+	m, err := s.BackingState.AddOneMachine(state.MachineTemplate{
+		Series:      coretesting.FakeDefaultSeries,
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Constraints: cons,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkNoOperations(c)
+
+	t0 := time.Now()
+	for time.Since(t0) < coretesting.LongWait {
+		status, info, _, err := m.Status()
+		c.Assert(err, jc.ErrorIsNil)
+		if status == state.StatusPending {
+			time.Sleep(coretesting.ShortWait)
+			continue
+		}
+		c.Assert(status, gc.Equals, state.StatusError)
+		c.Assert(info, gc.Equals, "no subnets match the spaces constraint")
+		break
+	}
+}
+
 func (s *ProvisionerSuite) TestSetInstanceInfoFailureSetsErrorStatusAndStopsInstanceButKeepsGoing(c *gc.C) {
 	p := s.newEnvironProvisioner(c)
 	defer stop(c, p)
@@ -1062,6 +1158,7 @@ func (s *ProvisionerSuite) newProvisionerTask(
 		broker,
 		auth,
 		imagemetadata.ReleasedStream,
+		testRetryStrategy,
 	)
 }
 
@@ -1223,13 +1320,233 @@ func (s *ProvisionerSuite) TestProvisionerObservesMachineJobs(c *gc.C) {
 	}
 }
 
+func (s *ProvisionerSuite) TestProvisionerRetriesRetryableStartErrors(c *gc.C) {
+	broker := &retryingBroker{Environ: s.Environ, failures: 2, attempts: make(map[string]int)}
+	task := s.newProvisionerTask(c, config.HarvestAll, broker, s.provisioner, mockToolsFinder{})
+	defer stop(c, task)
+
+	// The instance starts on the last attempt.
+	m, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStartInstance(c, m)
+	c.Assert(broker.attemptsFor(m), gc.Equals, testRetryStrategy.Attempts)
+}
+
+func (s *ProvisionerSuite) TestProvisionerSetsErrorStatusAfterRetries(c *gc.C) {
+	broker := &retryingBroker{Environ: s.Environ, failures: 10, attempts: make(map[string]int)}
+	task := s.newProvisionerTask(c, config.HarvestAll, broker, s.provisioner, mockToolsFinder{})
+	defer stop(c, task)
+
+	m, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkNoOperations(c)
+
+	s.waitErrorStatus(c, m, "attempt 3 failed")
+	c.Assert(broker.attemptsFor(m), gc.Equals, testRetryStrategy.Attempts)
+}
+
+func (s *ProvisionerSuite) TestProvisionerStartsMachinesConcurrently(c *gc.C) {
+	s.PatchValue(provisioner.MaxConcurrentStarts, 2)
+	broker := &blockingBroker{
+		Environ:  s.Environ,
+		release:  make(chan struct{}),
+		inFlight: make(chan int, 3),
+	}
+
+	// Add the machines before starting the task, so they are all
+	// started in the same batch.
+	var machines []*state.Machine
+	for i := 0; i < 3; i++ {
+		m, err := s.addMachine()
+		c.Assert(err, jc.ErrorIsNil)
+		machines = append(machines, m)
+	}
+	task := s.newProvisionerTask(c, config.HarvestAll, broker, s.provisioner, mockToolsFinder{})
+	defer stop(c, task)
+
+	// Two instances are started at once, and the third one waits
+	// for one of them to finish.
+	for i := 0; i < 2; i++ {
+		select {
+		case <-broker.inFlight:
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for instances to start")
+		}
+	}
+	select {
+	case current := <-broker.inFlight:
+		c.Fatalf("unexpected start with %d instances in flight", current)
+	case <-time.After(coretesting.ShortWait):
+	}
+	close(broker.release)
+
+	started := make(map[string]bool)
+	for len(started) < len(machines) {
+		select {
+		case o := <-s.op:
+			if o, ok := o.(dummy.OpStartInstance); ok {
+				started[o.MachineId] = true
+			}
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for instances to start")
+		}
+	}
+	for _, m := range machines {
+		c.Check(started[m.Id()], jc.IsTrue)
+	}
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	c.Assert(broker.maxSeen, gc.Equals, 2)
+}
+
+func (s *ProvisionerSuite) TestProvisionerSpreadsMachinesAcrossAllowedZones(c *gc.C) {
+	broker := newZonedBroker(s.Environ, "az1", "az2", "az3")
+	cons := constraints.MustParse(s.defaultConstraints.String(), "zones=az2,az3")
+	var machines []*state.Machine
+	for i := 0; i < 2; i++ {
+		m, err := s.BackingState.AddOneMachine(state.MachineTemplate{
+			Series:      coretesting.FakeDefaultSeries,
+			Jobs:        []state.MachineJob{state.JobHostUnits},
+			Constraints: cons,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		machines = append(machines, m)
+	}
+	task := s.newProvisionerTask(c, config.HarvestAll, broker, s.provisioner, mockToolsFinder{})
+	defer stop(c, task)
+
+	// The machines are started in the same batch, and still land in
+	// different zones.
+	s.BackingState.StartSync()
+	started := make(map[string]bool)
+	for len(started) < len(machines) {
+		select {
+		case o := <-s.op:
+			if o, ok := o.(dummy.OpStartInstance); ok {
+				started[o.MachineId] = true
+			}
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for instances to start")
+		}
+	}
+	placements := set.NewStrings()
+	for _, m := range machines {
+		placements.Add(broker.placementFor(m))
+	}
+	c.Assert(placements.SortedValues(), jc.DeepEquals, []string{"zone=az2", "zone=az3"})
+}
+
+func (s *ProvisionerSuite) addUnitMachine(c *gc.C, svc *state.Service, cons constraints.Value) *state.Machine {
+	m, err := s.BackingState.AddOneMachine(state.MachineTemplate{
+		Series:      "quantal",
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Constraints: cons,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := svc.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(m)
+	c.Assert(err, jc.ErrorIsNil)
+	return m
+}
+
+func (s *ProvisionerSuite) TestProvisionerSpreadsEachDistributionGroup(c *gc.C) {
+	broker := newZonedBroker(s.Environ, "az1", "az2")
+	broker.gate = make(chan struct{})
+	cons := constraints.MustParse(s.defaultConstraints.String(), "zones=az1,az2")
+	charm := s.AddTestingCharm(c, "dummy")
+	var machines []*state.Machine
+	for i, name := range []string{"wordpress", "mysql"} {
+		// Each service already has a unit on an instance in az1.
+		svc := s.AddTestingService(c, name, charm)
+		existing := s.addUnitMachine(c, svc, cons)
+		instId := instance.Id(fmt.Sprintf("existing-%d", i))
+		err := existing.SetProvisioned(instId, "fake_nonce", nil)
+		c.Assert(err, jc.ErrorIsNil)
+		broker.instanceZones[instId] = "az1"
+		machines = append(machines, s.addUnitMachine(c, svc, cons))
+	}
+	task := s.newProvisionerTask(c, config.HarvestAll, broker, s.provisioner, mockToolsFinder{})
+	defer stop(c, task)
+	defer broker.openGate()
+
+	// Both placements are chosen while neither instance has been
+	// recorded; the pending instance of one service must not push
+	// the other service's unit into the zone it already uses.
+	s.BackingState.StartSync()
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if broker.placementCount() == len(machines) {
+			break
+		}
+		if !a.HasNext() {
+			c.Fatalf("timed out waiting for placements")
+		}
+	}
+	broker.openGate()
+	for _, m := range machines {
+		c.Check(broker.placementFor(m), gc.Equals, "zone=az2")
+	}
+}
+
+func (s *ProvisionerSuite) TestProvisionerSpreadsConcurrentStartsOfService(c *gc.C) {
+	broker := newZonedBroker(s.Environ, "az1", "az2", "az3")
+	broker.gate = make(chan struct{})
+	cons := constraints.MustParse(s.defaultConstraints.String(), "zones=az1,az2,az3")
+	svc := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "dummy"))
+	var machines []*state.Machine
+	for i := 0; i < 3; i++ {
+		machines = append(machines, s.addUnitMachine(c, svc, cons))
+	}
+	task := s.newProvisionerTask(c, config.HarvestAll, broker, s.provisioner, mockToolsFinder{})
+	defer stop(c, task)
+	defer broker.openGate()
+
+	// All three placements are chosen while none of the instances
+	// has been recorded, and each lands in a different zone.
+	s.BackingState.StartSync()
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if broker.placementCount() == len(machines) {
+			break
+		}
+		if !a.HasNext() {
+			c.Fatalf("timed out waiting for placements")
+		}
+	}
+	broker.openGate()
+	placements := set.NewStrings()
+	for _, m := range machines {
+		placements.Add(broker.placementFor(m))
+	}
+	c.Assert(placements.SortedValues(), jc.DeepEquals, []string{"zone=az1", "zone=az2", "zone=az3"})
+}
+
+func (s *ProvisionerSuite) TestProvisionerSetsErrorStatusWhenNoZonesMatch(c *gc.C) {
+	broker := newZonedBroker(s.Environ, "az1", "az2")
+	task := s.newProvisionerTask(c, config.HarvestAll, broker, s.provisioner, mockToolsFinder{})
+	defer stop(c, task)
+
+	cons := constraints.MustParse(s.defaultConstraints.String(), "zones=az4")
+	m, err := s.BackingState.AddOneMachine(state.MachineTemplate{
+		Series:      coretesting.FakeDefaultSeries,
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Constraints: cons,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkNoOperations(c)
+
+	s.waitErrorStatus(c, m, "no available zones match the zones constraint")
+}
+
 type mockBroker struct {
 	environs.Environ
+	mu         sync.Mutex
 	retryCount map[string]int
 	ids        []string
 }
 
 func (b *mockBroker) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
 	// All machines except machines 3, 4 are provisioned successfully the first time.
 	// Machines 3 is provisioned after some attempts have been made.
 	// Machine 4 is never provisioned.
@@ -1245,6 +1562,150 @@ func (b *mockBroker) StartInstance(args environs.StartInstanceParams) (*environs
 	return nil, fmt.Errorf("error: some error")
 }
 
+// retryingBroker fails to start instances with a retryable error
+// until it has been called failures times for a machine.
+type retryingBroker struct {
+	environs.Environ
+	mu       sync.Mutex
+	failures int
+	attempts map[string]int
+}
+
+func (b *retryingBroker) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	b.mu.Lock()
+	id := args.MachineConfig.MachineId
+	b.attempts[id]++
+	attempts := b.attempts[id]
+	b.mu.Unlock()
+	if attempts <= b.failures {
+		return nil, instance.NewRetryableCreationError(fmt.Sprintf("attempt %d failed", attempts))
+	}
+	return b.Environ.StartInstance(args)
+}
+
+func (b *retryingBroker) attemptsFor(m *state.Machine) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.attempts[m.Id()]
+}
+
+// blockingBroker blocks starting instances until released, and
+// records the largest number of instances being started at once.
+type blockingBroker struct {
+	environs.Environ
+	release chan struct{}
+
+	mu       sync.Mutex
+	current  int
+	maxSeen  int
+	inFlight chan int
+}
+
+func (b *blockingBroker) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	b.mu.Lock()
+	b.current++
+	if b.current > b.maxSeen {
+		b.maxSeen = b.current
+	}
+	current := b.current
+	b.mu.Unlock()
+	b.inFlight <- current
+	<-b.release
+	b.mu.Lock()
+	b.current--
+	b.mu.Unlock()
+	return b.Environ.StartInstance(args)
+}
+
+// zonedBroker adds availability zones to an environ, recording the
+// placement directive each instance was started with and treating a
+// zone placement as the zone of the new instance. If gate is set,
+// instances are not started until it is opened.
+type zonedBroker struct {
+	environs.Environ
+	zones    []string
+	gate     chan struct{}
+	gateOnce sync.Once
+
+	mu            sync.Mutex
+	placements    map[string]string
+	instanceZones map[instance.Id]string
+}
+
+var _ providercommon.ZonedEnviron = (*zonedBroker)(nil)
+
+func newZonedBroker(env environs.Environ, zones ...string) *zonedBroker {
+	return &zonedBroker{
+		Environ:       env,
+		zones:         zones,
+		placements:    make(map[string]string),
+		instanceZones: make(map[instance.Id]string),
+	}
+}
+
+func (b *zonedBroker) AvailabilityZones() ([]providercommon.AvailabilityZone, error) {
+	zones := make([]providercommon.AvailabilityZone, len(b.zones))
+	for i, zone := range b.zones {
+		zones[i] = fakeZone(zone)
+	}
+	return zones, nil
+}
+
+func (b *zonedBroker) InstanceAvailabilityZoneNames(ids []instance.Id) ([]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	zones := make([]string, len(ids))
+	for i, id := range ids {
+		zones[i] = b.instanceZones[id]
+	}
+	return zones, nil
+}
+
+func (b *zonedBroker) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	b.mu.Lock()
+	b.placements[args.MachineConfig.MachineId] = args.Placement
+	b.mu.Unlock()
+	if b.gate != nil {
+		<-b.gate
+	}
+	result, err := b.Environ.StartInstance(args)
+	if err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	b.instanceZones[result.Instance.Id()] = strings.TrimPrefix(args.Placement, "zone=")
+	b.mu.Unlock()
+	return result, nil
+}
+
+func (b *zonedBroker) openGate() {
+	b.gateOnce.Do(func() {
+		close(b.gate)
+	})
+}
+
+func (b *zonedBroker) placementCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.placements)
+}
+
+func (b *zonedBroker) placementFor(m *state.Machine) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.placements[m.Id()]
+}
+
+type fakeZone string
+
+func (z fakeZone) Name() string {
+	return string(z)
+}
+
+func (fakeZone) Available() bool {
+	return true
+}
+
 type mockToolsFinder struct {
 }
 
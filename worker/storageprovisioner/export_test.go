@@ -0,0 +1,10 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+var (
+	DoWork     = doWork
+	RunCommand = &runCommand
+	ProcMounts = &procMounts
+)
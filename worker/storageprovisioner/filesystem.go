@@ -0,0 +1,104 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/storage"
+)
+
+// procMounts is the file listing the filesystems mounted on the machine.
+var procMounts = "/proc/mounts"
+
+// filesystemPreferences returns the filesystem preferences of a storage
+// instance with the given options, most preferred first. A filesystem
+// type may be requested with a "filesystem=<type>" option; the default
+// filesystem type is always tried last.
+func filesystemPreferences(options string) []storage.FilesystemPreference {
+	var prefs []storage.FilesystemPreference
+	for _, option := range strings.Split(options, ",") {
+		kv := strings.SplitN(strings.TrimSpace(option), "=", 2)
+		if len(kv) == 2 && kv[0] == "filesystem" && kv[1] != "" {
+			prefs = append(prefs, storage.FilesystemPreference{
+				Filesystem: storage.Filesystem{Type: kv[1]},
+			})
+		}
+	}
+	return append(prefs, storage.FilesystemPreference{
+		Filesystem: storage.Filesystem{Type: storage.DefaultFilesystemType},
+	})
+}
+
+// ensureFilesystem returns the filesystem on the block device with the
+// given path, first creating one according to the given preferences if
+// the device has none.
+func ensureFilesystem(devicePath string, prefs []storage.FilesystemPreference) (storage.Filesystem, error) {
+	// blkid exits non-zero if the device has no recognisable
+	// filesystem, so we only take note of its output.
+	fsType, _ := runCommand("blkid", "-o", "value", "-s", "TYPE", devicePath)
+	if fsType != "" {
+		logger.Debugf("block device %q already has a %q filesystem", devicePath, fsType)
+		for _, pref := range prefs {
+			if pref.Type == fsType {
+				return pref.Filesystem, nil
+			}
+		}
+		return storage.Filesystem{Type: fsType}, nil
+	}
+	for _, pref := range prefs {
+		logger.Debugf("attempting to create %q filesystem on %q", pref.Type, devicePath)
+		args := append([]string{}, pref.MkfsOptions...)
+		args = append(args, devicePath)
+		if _, err := runCommand("mkfs."+pref.Type, args...); err != nil {
+			logger.Debugf("cannot create %q filesystem on %q: %v", pref.Type, devicePath, err)
+			continue
+		}
+		logger.Infof("created %q filesystem on %q", pref.Type, devicePath)
+		return pref.Filesystem, nil
+	}
+	return storage.Filesystem{}, errors.Errorf("cannot create filesystem on %q", devicePath)
+}
+
+// ensureMounted mounts the block device with the given path at the
+// given mount point, unless something is already mounted there.
+func ensureMounted(devicePath, mountPoint string, fs storage.Filesystem) error {
+	mounted, err := isMounted(mountPoint)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if mounted {
+		logger.Debugf("%q is already mounted", mountPoint)
+		return nil
+	}
+	args := []string{"-t", fs.Type}
+	if len(fs.MountOptions) > 0 {
+		args = append(args, "-o", strings.Join(fs.MountOptions, ","))
+	}
+	args = append(args, devicePath, mountPoint)
+	if _, err := runCommand("mount", args...); err != nil {
+		return errors.Annotatef(err, "cannot mount %q at %q", devicePath, mountPoint)
+	}
+	return nil
+}
+
+func isMounted(mountPoint string) (bool, error) {
+	f, err := os.Open(procMounts)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[1] == mountPoint {
+			return true, nil
+		}
+	}
+	return false, errors.Trace(scanner.Err())
+}
@@ -0,0 +1,69 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// runCommand runs the given command and returns its combined output.
+// It is a variable so that tests can intercept the commands run.
+var runCommand = func(name string, args ...string) (string, error) {
+	output, err := exec.Command(name, args...).CombinedOutput()
+	output = bytes.TrimSpace(output)
+	if err != nil {
+		return "", errors.Annotatef(err, "%s failed (%q)", name, output)
+	}
+	return string(output), nil
+}
+
+// ensureLoopDevice ensures that a sparse file of the given size in MiB
+// exists at the given path, and that it is attached to a loop device,
+// and returns the path of the loop device.
+func ensureLoopDevice(backingFile string, sizeMiB uint64) (string, error) {
+	if err := ensureBackingFile(backingFile, sizeMiB); err != nil {
+		return "", errors.Annotatef(err, "cannot create loop device backing file %q", backingFile)
+	}
+	// "losetup -j" lists the loop devices the file is attached to,
+	// one per line, in the form "/dev/loop0: [...]: (<file>)".
+	output, err := runCommand("losetup", "-j", backingFile)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if output != "" {
+		firstLine := strings.SplitN(output, "\n", 2)[0]
+		return strings.SplitN(firstLine, ":", 2)[0], nil
+	}
+	devicePath, err := runCommand("losetup", "-f", "--show", backingFile)
+	if err != nil {
+		return "", errors.Annotatef(err, "cannot attach loop device to %q", backingFile)
+	}
+	return devicePath, nil
+}
+
+func ensureBackingFile(path string, sizeMiB uint64) error {
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	} else if !os.IsNotExist(err) {
+		return errors.Trace(err)
+	}
+	if sizeMiB == 0 {
+		return errors.New("loop storage requires a size")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return errors.Trace(err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer f.Close()
+	return errors.Trace(f.Truncate(int64(sizeMiB) * 1024 * 1024))
+}
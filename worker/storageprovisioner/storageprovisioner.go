@@ -0,0 +1,110 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package storageprovisioner defines a worker that provisions the
+// storage instances of the units assigned to the machine it runs on.
+// For each storage instance that has not yet been provisioned, the
+// worker obtains a block device from the instance's storage source,
+// creates a filesystem on it, mounts it, and records where it is
+// mounted. Each machine agent runs this worker.
+package storageprovisioner
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.storageprovisioner")
+
+// provisionPeriod is the time period between checks for storage
+// instances that need provisioning.
+const provisionPeriod = 30 * time.Second
+
+// StorageInstanceAccessor is an interface used to retrieve the storage
+// instances of the units assigned to the machine, and to record those
+// that have been provisioned.
+type StorageInstanceAccessor interface {
+	StorageInstances() ([]params.StorageInstance, error)
+	SetProvisioned(...params.StorageProvisioned) error
+}
+
+// NewWorker returns a worker that provisions the storage instances of
+// the units assigned to the machine, keeping loop device backing files
+// and mount points under the given directory.
+func NewWorker(accessor StorageInstanceAccessor, storageDir string) worker.Worker {
+	f := func(stop <-chan struct{}) error {
+		return doWork(accessor, storageDir)
+	}
+	return worker.NewPeriodicWorker(f, provisionPeriod)
+}
+
+func doWork(accessor StorageInstanceAccessor, storageDir string) error {
+	instances, err := accessor.StorageInstances()
+	if err != nil {
+		return errors.Annotate(err, "cannot get storage instances")
+	}
+	var provisioned []params.StorageProvisioned
+	for _, instance := range instances {
+		if instance.Location != "" {
+			continue
+		}
+		result, err := provision(instance, storageDir)
+		if err != nil {
+			// Carry on with the other storage instances; this
+			// one will be retried next time around.
+			logger.Errorf("cannot provision storage instance %q: %v", instance.Id, err)
+			continue
+		}
+		logger.Infof("storage instance %q mounted at %q", instance.Id, result.Location)
+		provisioned = append(provisioned, *result)
+	}
+	if len(provisioned) > 0 {
+		if err := accessor.SetProvisioned(provisioned...); err != nil {
+			return errors.Annotate(err, "cannot record provisioned storage instances")
+		}
+	}
+	return nil
+}
+
+// provision obtains a block device for the storage instance, creates
+// a filesystem on it if it has none, and mounts it. Each step checks
+// whether it has already been done, so that provisioning can be
+// retried if the agent was interrupted part way through.
+func provision(instance params.StorageInstance, storageDir string) (*params.StorageProvisioned, error) {
+	var devicePath string
+	var err error
+	switch instance.Source {
+	case storage.LoopSource:
+		backingFile := filepath.Join(storageDir, "loop", filepath.FromSlash(instance.Id))
+		devicePath, err = ensureLoopDevice(backingFile, instance.Size)
+	default:
+		return nil, errors.NotSupportedf("storage source %q", instance.Source)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	fs, err := ensureFilesystem(devicePath, filesystemPreferences(instance.Options))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	mountPoint := filepath.Join(storageDir, filepath.FromSlash(instance.Id))
+	if err := os.MkdirAll(mountPoint, 0755); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := ensureMounted(devicePath, mountPoint, fs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &params.StorageProvisioned{
+		Id:         instance.Id,
+		Location:   mountPoint,
+		Filesystem: fs,
+	}, nil
+}
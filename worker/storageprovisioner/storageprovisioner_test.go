@@ -0,0 +1,202 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/storage"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/storageprovisioner"
+)
+
+var _ = gc.Suite(&StorageProvisionerWorkerSuite{})
+
+type StorageProvisionerWorkerSuite struct {
+	coretesting.BaseSuite
+	storageDir string
+	mounts     string
+	commands   []string
+	outputs    map[string]string
+	failures   map[string]bool
+}
+
+func (s *StorageProvisionerWorkerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.storageDir = c.MkDir()
+	s.mounts = filepath.Join(c.MkDir(), "mounts")
+	err := ioutil.WriteFile(s.mounts, []byte("/dev/sda1 / ext4 rw 0 0\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(storageprovisioner.ProcMounts, s.mounts)
+
+	s.commands = nil
+	s.outputs = make(map[string]string)
+	s.failures = make(map[string]bool)
+	s.PatchValue(storageprovisioner.RunCommand, func(name string, args ...string) (string, error) {
+		command := strings.Join(append([]string{name}, args...), " ")
+		s.commands = append(s.commands, command)
+		if s.failures[name] {
+			return "", errors.New(name + " failed")
+		}
+		return s.outputs[name], nil
+	})
+}
+
+type fakeAccessor struct {
+	instances   []params.StorageInstance
+	provisioned []params.StorageProvisioned
+}
+
+func (f *fakeAccessor) StorageInstances() ([]params.StorageInstance, error) {
+	return f.instances, nil
+}
+
+func (f *fakeAccessor) SetProvisioned(provisioned ...params.StorageProvisioned) error {
+	f.provisioned = append(f.provisioned, provisioned...)
+	return nil
+}
+
+func loopInstance(id, options string) params.StorageInstance {
+	return params.StorageInstance{
+		Id:      id,
+		Name:    strings.Split(id, "/")[0],
+		Owner:   "postgresql/0",
+		Source:  storage.LoopSource,
+		Size:    1,
+		Options: options,
+	}
+}
+
+func (s *StorageProvisionerWorkerSuite) TestProvisionLoop(c *gc.C) {
+	s.outputs["losetup"] = "/dev/loop0"
+	s.failures["blkid"] = true
+	accessor := &fakeAccessor{
+		instances: []params.StorageInstance{loopInstance("data/0", "")},
+	}
+	err := storageprovisioner.DoWork(accessor, s.storageDir)
+	c.Assert(err, jc.ErrorIsNil)
+
+	backingFile := filepath.Join(s.storageDir, "loop", "data", "0")
+	mountPoint := filepath.Join(s.storageDir, "data", "0")
+	info, err := os.Stat(backingFile)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Size(), gc.Equals, int64(1024*1024))
+	info, err = os.Stat(mountPoint)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.IsDir(), jc.IsTrue)
+
+	c.Assert(s.commands, jc.DeepEquals, []string{
+		"losetup -j " + backingFile,
+		"losetup -f --show " + backingFile,
+		"blkid -o value -s TYPE /dev/loop0",
+		"mkfs.ext4 /dev/loop0",
+		"mount -t ext4 /dev/loop0 " + mountPoint,
+	})
+	c.Assert(accessor.provisioned, jc.DeepEquals, []params.StorageProvisioned{{
+		Id:         "data/0",
+		Location:   mountPoint,
+		Filesystem: storage.Filesystem{Type: "ext4"},
+	}})
+}
+
+func (s *StorageProvisionerWorkerSuite) TestFilesystemPreferences(c *gc.C) {
+	s.outputs["losetup"] = "/dev/loop0"
+	s.failures["blkid"] = true
+	s.failures["mkfs.btrfs"] = true
+	accessor := &fakeAccessor{
+		instances: []params.StorageInstance{loopInstance("data/0", "filesystem=btrfs,filesystem=xfs")},
+	}
+	err := storageprovisioner.DoWork(accessor, s.storageDir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.commands[3:5], jc.DeepEquals, []string{
+		"mkfs.btrfs /dev/loop0",
+		"mkfs.xfs /dev/loop0",
+	})
+	c.Assert(accessor.provisioned, gc.HasLen, 1)
+	c.Assert(accessor.provisioned[0].Filesystem, jc.DeepEquals, storage.Filesystem{Type: "xfs"})
+}
+
+func (s *StorageProvisionerWorkerSuite) TestResumeProvisioning(c *gc.C) {
+	// The loop device is already attached and has a filesystem, and
+	// the mount point is already mounted: nothing needs to be redone.
+	backingFile := filepath.Join(s.storageDir, "loop", "data", "0")
+	mountPoint := filepath.Join(s.storageDir, "data", "0")
+	err := os.MkdirAll(filepath.Dir(backingFile), 0700)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(backingFile, nil, 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(s.mounts, []byte("/dev/loop3 "+mountPoint+" ext4 rw 0 0\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	s.outputs["losetup"] = "/dev/loop3: [2049]:1234 (" + backingFile + ")"
+	s.outputs["blkid"] = "ext4"
+
+	accessor := &fakeAccessor{
+		instances: []params.StorageInstance{loopInstance("data/0", "")},
+	}
+	err = storageprovisioner.DoWork(accessor, s.storageDir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.commands, jc.DeepEquals, []string{
+		"losetup -j " + backingFile,
+		"blkid -o value -s TYPE /dev/loop3",
+	})
+	c.Assert(accessor.provisioned, jc.DeepEquals, []params.StorageProvisioned{{
+		Id:         "data/0",
+		Location:   mountPoint,
+		Filesystem: storage.Filesystem{Type: "ext4"},
+	}})
+}
+
+func (s *StorageProvisionerWorkerSuite) TestSkipsProvisionedAndUnsupported(c *gc.C) {
+	provisioned := loopInstance("data/0", "")
+	provisioned.Location = "/srv/data"
+	unsupported := loopInstance("data/1", "")
+	unsupported.Source = storage.ProviderSource
+	accessor := &fakeAccessor{
+		instances: []params.StorageInstance{provisioned, unsupported},
+	}
+	err := storageprovisioner.DoWork(accessor, s.storageDir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.commands, gc.HasLen, 0)
+	c.Assert(accessor.provisioned, gc.HasLen, 0)
+}
+
+func (s *StorageProvisionerWorkerSuite) TestWorker(c *gc.C) {
+	s.outputs["losetup"] = "/dev/loop0"
+	s.failures["blkid"] = true
+	done := make(chan struct{})
+	accessor := &notifyingAccessor{
+		fakeAccessor: fakeAccessor{
+			instances: []params.StorageInstance{loopInstance("data/0", "")},
+		},
+		done: done,
+	}
+	w := storageprovisioner.NewWorker(accessor, s.storageDir)
+	defer w.Wait()
+	defer w.Kill()
+
+	select {
+	case <-done:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for storage to be provisioned")
+	}
+}
+
+type notifyingAccessor struct {
+	fakeAccessor
+	done chan struct{}
+}
+
+func (f *notifyingAccessor) SetProvisioned(provisioned ...params.StorageProvisioned) error {
+	close(f.done)
+	return nil
+}
@@ -5,6 +5,7 @@ package filter
 
 import (
 	"sort"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -23,6 +24,11 @@ import (
 
 var filterLogger = loggo.GetLogger("juju.worker.uniter.filter")
 
+// leadershipCheckInterval is the interval at which the filter checks
+// (and thereby renews) the unit's service leadership. It must be
+// comfortably shorter than the leadership lease duration.
+var leadershipCheckInterval = 15 * time.Second
+
 // filter collects unit, service, and service config information from separate
 // state watchers, and presents it as events on channels designed specifically
 // for the convenience of the uniter.
@@ -37,18 +43,22 @@ type filter struct {
 	// The out* chans, when set to the corresponding out*On chan (rather than
 	// nil) indicate that an event of the appropriate type is ready to send
 	// to the client.
-	outConfig        chan struct{}
-	outConfigOn      chan struct{}
-	outAction        chan *hook.Info
-	outActionOn      chan *hook.Info
-	outUpgrade       chan *charm.URL
-	outUpgradeOn     chan *charm.URL
-	outResolved      chan params.ResolvedMode
-	outResolvedOn    chan params.ResolvedMode
-	outRelations     chan []int
-	outRelationsOn   chan []int
-	outMeterStatus   chan struct{}
-	outMeterStatusOn chan struct{}
+	outConfig           chan struct{}
+	outConfigOn         chan struct{}
+	outAction           chan *hook.Info
+	outActionOn         chan *hook.Info
+	outUpgrade          chan *charm.URL
+	outUpgradeOn        chan *charm.URL
+	outResolved         chan params.ResolvedMode
+	outResolvedOn       chan params.ResolvedMode
+	outRelations        chan []int
+	outRelationsOn      chan []int
+	outMeterStatus      chan struct{}
+	outMeterStatusOn    chan struct{}
+	outLeaderElected    chan struct{}
+	outLeaderElectedOn  chan struct{}
+	outLeaderSettings   chan struct{}
+	outLeaderSettingsOn chan struct{}
 	// The want* chans are used to indicate that the filter should send
 	// events if it has them available.
 	wantForcedUpgrade chan bool
@@ -98,33 +108,41 @@ type filter struct {
 	// meterStatusCode and meterStatusInfo reflect the meter status values of the unit.
 	meterStatusCode string
 	meterStatusInfo string
+
+	// isLeader holds whether the unit was the service leader when last
+	// checked.
+	isLeader bool
 }
 
 // NewFilter returns a filter that handles state changes pertaining to the
 // supplied unit.
 func NewFilter(st *uniter.State, unitTag names.UnitTag) (Filter, error) {
 	f := &filter{
-		st:                st,
-		outUnitDying:      make(chan struct{}),
-		outConfig:         make(chan struct{}),
-		outConfigOn:       make(chan struct{}),
-		outAction:         make(chan *hook.Info),
-		outActionOn:       make(chan *hook.Info),
-		outUpgrade:        make(chan *charm.URL),
-		outUpgradeOn:      make(chan *charm.URL),
-		outResolved:       make(chan params.ResolvedMode),
-		outResolvedOn:     make(chan params.ResolvedMode),
-		outRelations:      make(chan []int),
-		outRelationsOn:    make(chan []int),
-		outMeterStatus:    make(chan struct{}),
-		outMeterStatusOn:  make(chan struct{}),
-		wantForcedUpgrade: make(chan bool),
-		wantResolved:      make(chan struct{}),
-		discardConfig:     make(chan struct{}),
-		setCharm:          make(chan *charm.URL),
-		didSetCharm:       make(chan struct{}),
-		clearResolved:     make(chan struct{}),
-		didClearResolved:  make(chan struct{}),
+		st:                  st,
+		outUnitDying:        make(chan struct{}),
+		outConfig:           make(chan struct{}),
+		outConfigOn:         make(chan struct{}),
+		outAction:           make(chan *hook.Info),
+		outActionOn:         make(chan *hook.Info),
+		outUpgrade:          make(chan *charm.URL),
+		outUpgradeOn:        make(chan *charm.URL),
+		outResolved:         make(chan params.ResolvedMode),
+		outResolvedOn:       make(chan params.ResolvedMode),
+		outRelations:        make(chan []int),
+		outRelationsOn:      make(chan []int),
+		outMeterStatus:      make(chan struct{}),
+		outMeterStatusOn:    make(chan struct{}),
+		outLeaderElected:    make(chan struct{}),
+		outLeaderElectedOn:  make(chan struct{}),
+		outLeaderSettings:   make(chan struct{}),
+		outLeaderSettingsOn: make(chan struct{}),
+		wantForcedUpgrade:   make(chan bool),
+		wantResolved:        make(chan struct{}),
+		discardConfig:       make(chan struct{}),
+		setCharm:            make(chan *charm.URL),
+		didSetCharm:         make(chan struct{}),
+		clearResolved:       make(chan struct{}),
+		didClearResolved:    make(chan struct{}),
 	}
 	go func() {
 		defer f.tomb.Done()
@@ -174,6 +192,18 @@ func (f *filter) MeterStatusEvents() <-chan struct{} {
 	return f.outMeterStatusOn
 }
 
+// LeaderElectedEvents returns a channel that will receive a signal when the
+// unit becomes the leader of its service.
+func (f *filter) LeaderElectedEvents() <-chan struct{} {
+	return f.outLeaderElectedOn
+}
+
+// LeaderSettingsEvents returns a channel that will receive a signal when the
+// service's leader settings change while the unit is not the leader.
+func (f *filter) LeaderSettingsEvents() <-chan struct{} {
+	return f.outLeaderSettingsOn
+}
+
 // ConfigEvents returns a channel that will receive a signal whenever the service's
 // configuration changes, or when an event is explicitly requested.
 func (f *filter) ConfigEvents() <-chan struct{} {
@@ -336,6 +366,25 @@ func (f *filter) loop(unitTag names.UnitTag) (err error) {
 		return err
 	}
 	defer f.maybeStopWatcher(meterStatusw)
+	// Leadership is not supported by older API servers; in that case
+	// neither leadership checks nor leader settings changes happen.
+	var leadershipCheck <-chan time.Time
+	var leaderSettingsw apiwatcher.NotifyWatcher
+	var leaderSettingsChanges <-chan struct{}
+	if err = f.leadershipChanged(); errors.IsNotImplemented(err) {
+		filterLogger.Infof("leadership not supported by the API server")
+	} else if err != nil {
+		return errors.Trace(err)
+	} else {
+		leadershipCheck = time.After(leadershipCheckInterval)
+		leaderSettingsw, err = f.service.WatchLeaderSettings()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		leaderSettingsChanges = leaderSettingsw.Changes()
+	}
+	defer f.maybeStopWatcher(leaderSettingsw)
+	var seenLeaderSettingsChange bool
 	addressesw, err := f.unit.WatchAddresses()
 	if err != nil {
 		return err
@@ -407,6 +456,23 @@ func (f *filter) loop(unitTag names.UnitTag) (err error) {
 			if err = f.meterStatusChanged(); err != nil {
 				return errors.Trace(err)
 			}
+		case <-leadershipCheck:
+			filterLogger.Debugf("checking leadership")
+			if err = f.leadershipChanged(); err != nil {
+				return errors.Trace(err)
+			}
+			leadershipCheck = time.After(leadershipCheckInterval)
+		case _, ok = <-leaderSettingsChanges:
+			filterLogger.Debugf("got leader settings change")
+			if !ok {
+				return watcher.EnsureErr(leaderSettingsw)
+			}
+			// The initial event only reflects the settings as they
+			// were when the watcher was started.
+			if seenLeaderSettingsChange && !f.isLeader {
+				f.outLeaderSettings = f.outLeaderSettingsOn
+			}
+			seenLeaderSettingsChange = true
 		case ids, ok := <-actionsw.Changes():
 			filterLogger.Debugf("got %d actions", len(ids))
 			if !ok {
@@ -454,6 +520,12 @@ func (f *filter) loop(unitTag names.UnitTag) (err error) {
 		case f.outMeterStatus <- nothing:
 			filterLogger.Debugf("sent meter status change event")
 			f.outMeterStatus = nil
+		case f.outLeaderElected <- nothing:
+			filterLogger.Debugf("sent leader elected event")
+			f.outLeaderElected = nil
+		case f.outLeaderSettings <- nothing:
+			filterLogger.Debugf("sent leader settings changed event")
+			f.outLeaderSettings = nil
 
 		// Handle explicit requests.
 		case curl := <-f.setCharm:
@@ -542,6 +614,27 @@ func (f *filter) meterStatusChanged() error {
 	return nil
 }
 
+// leadershipChanged checks whether the unit is the leader of its
+// service, and prepares a leader elected event if it has just become
+// the leader. Leader settings events are of no interest to the leader,
+// so any pending one is dropped.
+func (f *filter) leadershipChanged() error {
+	isLeader, err := f.unit.IsLeader()
+	if err != nil {
+		return err
+	}
+	if isLeader && !f.isLeader {
+		filterLogger.Infof("unit is now the service leader")
+		f.outLeaderElected = f.outLeaderElectedOn
+		f.outLeaderSettings = nil
+	} else if !isLeader && f.isLeader {
+		filterLogger.Infof("unit is no longer the service leader")
+		f.outLeaderElected = nil
+	}
+	f.isLeader = isLeader
+	return nil
+}
+
 // unitChanged responds to changes in the unit.
 func (f *filter) unitChanged() error {
 	if err := f.unit.Refresh(); err != nil {
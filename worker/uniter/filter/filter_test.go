@@ -18,6 +18,8 @@ import (
 	apiuniter "github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
 	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/leadership"
+	"github.com/juju/juju/lease"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
@@ -708,3 +710,91 @@ func (s *FilterSuite) TestMeterStatusEvents(c *gc.C) {
 	}
 	assertChange()
 }
+
+func (s *FilterSuite) assertEvents(c *gc.C, events <-chan struct{}) (assertNoChange, assertChange func()) {
+	assertNoChange = func() {
+		s.BackingState.StartSync()
+		select {
+		case <-events:
+			c.Fatalf("unexpected event")
+		case <-time.After(coretesting.ShortWait):
+		}
+	}
+	assertChange = func() {
+		s.BackingState.StartSync()
+		select {
+		case _, ok := <-events:
+			c.Assert(ok, jc.IsTrue)
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out")
+		}
+		assertNoChange()
+	}
+	return assertNoChange, assertChange
+}
+
+func (s *FilterSuite) TestLeaderElectedEvents(c *gc.C) {
+	s.PatchValue(filter.LeadershipCheckInterval, 10*time.Millisecond)
+	manager := leadership.NewLeadershipManager(lease.Manager())
+	_, err := manager.ClaimLeadership("wordpress", "wordpress/1")
+	c.Assert(err, jc.ErrorIsNil)
+
+	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, f)
+	assertNoChange, assertChange := s.assertEvents(c, f.LeaderElectedEvents())
+
+	// Another unit is the leader.
+	assertNoChange()
+
+	// Once leadership is released, the unit claims it.
+	err = manager.ReleaseLeadership("wordpress", "wordpress/1")
+	c.Assert(err, jc.ErrorIsNil)
+	assertChange()
+}
+
+func (s *FilterSuite) TestLeaderElectedEventsInitialLeader(c *gc.C) {
+	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, f)
+	_, assertChange := s.assertEvents(c, f.LeaderElectedEvents())
+
+	// The only unit becomes leader straight away.
+	assertChange()
+}
+
+func (s *FilterSuite) TestLeaderSettingsEvents(c *gc.C) {
+	manager := leadership.NewLeadershipManager(lease.Manager())
+	_, err := manager.ClaimLeadership("wordpress", "wordpress/1")
+	c.Assert(err, jc.ErrorIsNil)
+
+	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, f)
+	assertNoChange, assertChange := s.assertEvents(c, f.LeaderSettingsEvents())
+
+	// Initial leader settings do not trigger an event.
+	assertNoChange()
+
+	err = s.wordpress.MergeLeaderSettings(map[string]string{"foo": "bar"})
+	c.Assert(err, jc.ErrorIsNil)
+	assertChange()
+
+	// Make sure bundled events arrive properly.
+	for i := 0; i < 5; i++ {
+		err = s.wordpress.MergeLeaderSettings(map[string]string{"foo": fmt.Sprint(i)})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	assertChange()
+}
+
+func (s *FilterSuite) TestLeaderSettingsEventsIgnoredByLeader(c *gc.C) {
+	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, f)
+	assertNoChange, _ := s.assertEvents(c, f.LeaderSettingsEvents())
+
+	err = s.wordpress.MergeLeaderSettings(map[string]string{"foo": "bar"})
+	c.Assert(err, jc.ErrorIsNil)
+	assertNoChange()
+}
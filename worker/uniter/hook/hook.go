@@ -11,6 +11,16 @@ import (
 	"gopkg.in/juju/charm.v4/hooks"
 )
 
+const (
+	// LeaderElected is run at least once on the service leader, and
+	// again whenever a unit newly becomes the service leader.
+	LeaderElected hooks.Kind = "leader-elected"
+
+	// LeaderSettingsChanged is run on units that are not the service
+	// leader whenever the leader settings change.
+	LeaderSettingsChanged hooks.Kind = "leader-settings-changed"
+)
+
 // Info holds details required to execute a hook. Not all fields are
 // relevant to all Kind values.
 type Info struct {
@@ -41,7 +51,8 @@ func (hi Info) Validate() error {
 			return fmt.Errorf("%q hook requires a remote unit", hi.Kind)
 		}
 		fallthrough
-	case hooks.Install, hooks.Start, hooks.ConfigChanged, hooks.UpgradeCharm, hooks.Stop, hooks.RelationBroken, hooks.CollectMetrics, hooks.MeterStatusChanged:
+	case hooks.Install, hooks.Start, hooks.ConfigChanged, hooks.UpgradeCharm, hooks.Stop, hooks.RelationBroken, hooks.CollectMetrics, hooks.MeterStatusChanged,
+		LeaderElected, LeaderSettingsChanged:
 		return nil
 	case hooks.Action:
 		if !names.IsValidAction(hi.ActionId) {
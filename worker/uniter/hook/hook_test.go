@@ -37,6 +37,8 @@ var validateTests = []struct {
 	{hook.Info{Kind: hooks.ConfigChanged}, ""},
 	{hook.Info{Kind: hooks.CollectMetrics}, ""},
 	{hook.Info{Kind: hooks.MeterStatusChanged}, ""},
+	{hook.Info{Kind: hook.LeaderElected}, ""},
+	{hook.Info{Kind: hook.LeaderSettingsChanged}, ""},
 	{
 		hook.Info{Kind: hooks.Action},
 		`action id "" cannot be parsed as an action tag`,
@@ -250,6 +250,10 @@ func modeAbideAliveLoop(u *Uniter) (Mode, error) {
 			return modeAbideDyingLoop(u)
 		case <-u.f.MeterStatusEvents():
 			hi = hook.Info{Kind: hooks.MeterStatusChanged}
+		case <-u.f.LeaderElectedEvents():
+			hi = hook.Info{Kind: hook.LeaderElected}
+		case <-u.f.LeaderSettingsEvents():
+			hi = hook.Info{Kind: hook.LeaderSettingsChanged}
 		case <-u.f.ConfigEvents():
 			hi = hook.Info{Kind: hooks.ConfigChanged}
 		case info := <-u.f.ActionEvents():
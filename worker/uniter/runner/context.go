@@ -56,6 +56,10 @@ type HookContext struct {
 	// configSettings holds the service configuration.
 	configSettings charm.Settings
 
+	// leaderSettings holds the cached leader settings of the unit's
+	// service.
+	leaderSettings map[string]string
+
 	// id identifies the context.
 	id string
 
@@ -234,6 +238,70 @@ func (ctx *HookContext) ConfigSettings() (charm.Settings, error) {
 	return result, nil
 }
 
+// IsLeader returns whether the unit is the leader of its service.
+func (ctx *HookContext) IsLeader() (bool, error) {
+	return ctx.unit.IsLeader()
+}
+
+// LeaderSettings returns the settings written by the leader of the
+// unit's service. The settings are read once per context, and are
+// subsequently only changed by WriteLeaderSettings.
+func (ctx *HookContext) LeaderSettings() (map[string]string, error) {
+	if ctx.leaderSettings == nil {
+		service, err := ctx.unit.Service()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ctx.leaderSettings, err = service.LeaderSettings()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	result := make(map[string]string)
+	for key, value := range ctx.leaderSettings {
+		result[key] = value
+	}
+	return result, nil
+}
+
+// WriteLeaderSettings merges the supplied settings into the leader
+// settings of the unit's service. It fails unless the unit is the
+// service's leader.
+func (ctx *HookContext) WriteLeaderSettings(settings map[string]string) error {
+	if err := ctx.unit.MergeLeaderSettings(settings); err != nil {
+		if params.IsCodeUnauthorized(err) {
+			return errors.New("not the leader")
+		}
+		return errors.Trace(err)
+	}
+	if ctx.leaderSettings != nil {
+		for key, value := range settings {
+			if value == "" {
+				delete(ctx.leaderSettings, key)
+			} else {
+				ctx.leaderSettings[key] = value
+			}
+		}
+	}
+	return nil
+}
+
+// WorkloadStatus returns the status of the unit's workload, as last
+// reported by its charm.
+func (ctx *HookContext) WorkloadStatus() (string, string, error) {
+	status, info, err := ctx.unit.WorkloadStatus()
+	if err != nil {
+		return "", "", errors.Trace(err)
+	}
+	return string(status), info, nil
+}
+
+// SetWorkloadStatus sets the status of the unit's workload, along
+// with an optional message for the operator.
+func (ctx *HookContext) SetWorkloadStatus(status, info string) error {
+	return ctx.unit.SetWorkloadStatus(params.Status(status), info)
+}
+
 // ActionName returns the name of the action.
 func (ctx *HookContext) ActionName() (string, error) {
 	if ctx.actionData == nil {
@@ -98,6 +98,27 @@ type Context interface {
 
 	// RequestReboot will set the reboot flag to true on the machine agent
 	RequestReboot(prio RebootPriority) error
+
+	// IsLeader returns whether the executing unit is the leader of its
+	// service.
+	IsLeader() (bool, error)
+
+	// LeaderSettings returns the settings written by the leader of the
+	// executing unit's service.
+	LeaderSettings() (map[string]string, error)
+
+	// WriteLeaderSettings merges the supplied settings into the leader
+	// settings of the executing unit's service. Keys with empty values
+	// are deleted. It fails if the executing unit is not the leader.
+	WriteLeaderSettings(map[string]string) error
+
+	// WorkloadStatus returns the status of the executing unit's
+	// workload, along with its message.
+	WorkloadStatus() (status, info string, err error)
+
+	// SetWorkloadStatus sets the status of the executing unit's
+	// workload, along with an optional message for the operator.
+	SetWorkloadStatus(status, info string) error
 }
 
 // ContextRelation expresses the capabilities of a hook with respect to a relation.
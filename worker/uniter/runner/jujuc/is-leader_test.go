@@ -0,0 +1,62 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type IsLeaderSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&IsLeaderSuite{})
+
+func (s *IsLeaderSuite) TestOutputFormat(c *gc.C) {
+	for i, t := range []struct {
+		isLeader bool
+		args     []string
+		out      string
+	}{
+		{true, nil, "True\n"},
+		{false, nil, "False\n"},
+		{true, []string{"--format", "json"}, "true\n"},
+		{false, []string{"--format", "yaml"}, "false\n"},
+	} {
+		c.Logf("test %d: %v", i, t.args)
+		hctx := s.GetHookContext(c, -1, "")
+		hctx.isLeader = t.isLeader
+		com, err := jujuc.NewCommand(hctx, cmdString("is-leader"))
+		c.Assert(err, jc.ErrorIsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Check(code, gc.Equals, 0)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Check(bufferString(ctx.Stdout), gc.Equals, t.out)
+	}
+}
+
+func (s *IsLeaderSuite) TestError(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.shouldError = true
+	com, err := jujuc.NewCommand(hctx, cmdString("is-leader"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, nil)
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: leadership status unknown: IsLeader error!\n")
+}
+
+func (s *IsLeaderSuite) TestUnknownArg(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("is-leader"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = testing.InitCommand(com, []string{"blah"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["blah"\]`)
+}
@@ -0,0 +1,64 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+)
+
+// LeaderGetCommand implements the leader-get command.
+type LeaderGetCommand struct {
+	cmd.CommandBase
+	ctx Context
+	Key string // The key to show. If empty, show all.
+	out cmd.Output
+}
+
+func NewLeaderGetCommand(ctx Context) cmd.Command {
+	return &LeaderGetCommand{ctx: ctx}
+}
+
+func (c *LeaderGetCommand) Info() *cmd.Info {
+	doc := `
+leader-get prints the value of a leadership setting specified by key. If no key
+is given, or if the key is "-", all keys and values will be printed.
+`
+	return &cmd.Info{
+		Name:    "leader-get",
+		Args:    "[<key>]",
+		Purpose: "print service leadership settings",
+		Doc:     doc,
+	}
+}
+
+func (c *LeaderGetCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+}
+
+func (c *LeaderGetCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+	c.Key = args[0]
+	if c.Key == "-" {
+		c.Key = ""
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *LeaderGetCommand) Run(ctx *cmd.Context) error {
+	settings, err := c.ctx.LeaderSettings()
+	if err != nil {
+		return errors.Annotate(err, "cannot read leadership settings")
+	}
+	if c.Key == "" {
+		return c.out.Write(ctx, settings)
+	}
+	if value, ok := settings[c.Key]; ok {
+		return c.out.Write(ctx, value)
+	}
+	return c.out.Write(ctx, nil)
+}
@@ -0,0 +1,66 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type LeaderGetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&LeaderGetSuite{})
+
+func (s *LeaderGetSuite) TestOutputFormat(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		out  string
+	}{
+		{nil, "master: mysql/0\nsecret: sekrit\n"},
+		{[]string{"-"}, "master: mysql/0\nsecret: sekrit\n"},
+		{[]string{"--format", "json"}, `{"master":"mysql/0","secret":"sekrit"}` + "\n"},
+		{[]string{"master"}, "mysql/0\n"},
+		{[]string{"master", "--format", "json"}, `"mysql/0"` + "\n"},
+		{[]string{"missing"}, ""},
+	} {
+		c.Logf("test %d: %v", i, t.args)
+		hctx := s.GetHookContext(c, -1, "")
+		hctx.leaderSettings = map[string]string{
+			"master": "mysql/0",
+			"secret": "sekrit",
+		}
+		com, err := jujuc.NewCommand(hctx, cmdString("leader-get"))
+		c.Assert(err, jc.ErrorIsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Check(code, gc.Equals, 0)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Check(bufferString(ctx.Stdout), gc.Equals, t.out)
+	}
+}
+
+func (s *LeaderGetSuite) TestError(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.shouldError = true
+	com, err := jujuc.NewCommand(hctx, cmdString("leader-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, nil)
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: cannot read leadership settings: LeaderSettings error!\n")
+}
+
+func (s *LeaderGetSuite) TestUnknownArg(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("leader-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = testing.InitCommand(com, []string{"key", "blah"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["blah"\]`)
+}
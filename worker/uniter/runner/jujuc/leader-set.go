@@ -0,0 +1,50 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/utils/keyvalues"
+)
+
+// LeaderSetCommand implements the leader-set command.
+type LeaderSetCommand struct {
+	cmd.CommandBase
+	ctx      Context
+	Settings map[string]string
+}
+
+func NewLeaderSetCommand(ctx Context) cmd.Command {
+	return &LeaderSetCommand{ctx: ctx}
+}
+
+func (c *LeaderSetCommand) Info() *cmd.Info {
+	doc := `
+leader-set immediately writes the key/value pairs to the state server, which
+will then inform non-leader units of the change. It will fail if called without
+arguments, or if called by a unit that is not currently service leader. Setting
+a key to an empty value deletes it.
+`
+	return &cmd.Info{
+		Name:    "leader-set",
+		Args:    "<key>=<value> [...]",
+		Purpose: "write service leadership settings",
+		Doc:     doc,
+	}
+}
+
+func (c *LeaderSetCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no settings specified")
+	}
+	var err error
+	c.Settings, err = keyvalues.Parse(args, true)
+	return err
+}
+
+func (c *LeaderSetCommand) Run(_ *cmd.Context) error {
+	err := c.ctx.WriteLeaderSettings(c.Settings)
+	return errors.Annotate(err, "cannot write leadership settings")
+}
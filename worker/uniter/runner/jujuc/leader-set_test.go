@@ -0,0 +1,70 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type LeaderSetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&LeaderSetSuite{})
+
+func (s *LeaderSetSuite) TestInit(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{
+		{nil, "no settings specified"},
+		{[]string{"foo"}, `expected "key=value", got "foo"`},
+		{[]string{"=bar"}, `expected "key=value", got "=bar"`},
+		{[]string{"foo=bar", "baz="}, ""},
+	} {
+		c.Logf("test %d: %v", i, t.args)
+		hctx := s.GetHookContext(c, -1, "")
+		com, err := jujuc.NewCommand(hctx, cmdString("leader-set"))
+		c.Assert(err, jc.ErrorIsNil)
+		err = testing.InitCommand(com, t.args)
+		if t.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, t.err)
+		}
+	}
+}
+
+func (s *LeaderSetSuite) TestRun(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.isLeader = true
+	hctx.leaderSettings = map[string]string{"master": "mysql/0", "old": "value"}
+	com, err := jujuc.NewCommand(hctx, cmdString("leader-set"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"master=mysql/1", "old=", "new=value"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(hctx.leaderSettings, gc.DeepEquals, map[string]string{
+		"master": "mysql/1",
+		"new":    "value",
+	})
+}
+
+func (s *LeaderSetSuite) TestRunNotLeader(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.leaderSettings = map[string]string{"master": "mysql/0"}
+	com, err := jujuc.NewCommand(hctx, cmdString("leader-set"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"master=mysql/1"})
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: cannot write leadership settings: not the leader\n")
+	c.Check(hctx.leaderSettings, gc.DeepEquals, map[string]string{"master": "mysql/0"})
+}
@@ -42,6 +42,11 @@ var newCommands = map[string]func(Context) cmd.Command{
 	"owner-get" + cmdSuffix:     NewOwnerGetCommand,
 	"add-metric" + cmdSuffix:    NewAddMetricCommand,
 	"juju-reboot" + cmdSuffix:   NewJujuRebootCommand,
+	"is-leader" + cmdSuffix:     NewIsLeaderCommand,
+	"leader-get" + cmdSuffix:    NewLeaderGetCommand,
+	"leader-set" + cmdSuffix:    NewLeaderSetCommand,
+	"status-get" + cmdSuffix:    NewStatusGetCommand,
+	"status-set" + cmdSuffix:    NewStatusSetCommand,
 }
 
 // CommandNames returns the names of all jujuc commands.
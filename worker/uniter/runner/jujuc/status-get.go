@@ -0,0 +1,58 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+)
+
+// StatusGetCommand implements the status-get command.
+type StatusGetCommand struct {
+	cmd.CommandBase
+	ctx         Context
+	includeData bool
+	out         cmd.Output
+}
+
+func NewStatusGetCommand(ctx Context) cmd.Command {
+	return &StatusGetCommand{ctx: ctx}
+}
+
+func (c *StatusGetCommand) Info() *cmd.Info {
+	doc := `
+status-get prints the status of the unit's workload, as last set by status-set.
+The status is "unknown" until the charm sets one. If --include-data is given,
+the status message is printed along with the status.
+`
+	return &cmd.Info{
+		Name:    "status-get",
+		Purpose: "print the status of the unit's workload",
+		Doc:     doc,
+	}
+}
+
+func (c *StatusGetCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+	f.BoolVar(&c.includeData, "include-data", false, "print the status message as well as the status")
+}
+
+func (c *StatusGetCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *StatusGetCommand) Run(ctx *cmd.Context) error {
+	status, info, err := c.ctx.WorkloadStatus()
+	if err != nil {
+		return errors.Annotate(err, "cannot read workload status")
+	}
+	if !c.includeData {
+		return c.out.Write(ctx, status)
+	}
+	return c.out.Write(ctx, map[string]string{
+		"status":  status,
+		"message": info,
+	})
+}
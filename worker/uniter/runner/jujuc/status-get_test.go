@@ -0,0 +1,67 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type StatusGetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&StatusGetSuite{})
+
+func (s *StatusGetSuite) TestOutputFormat(c *gc.C) {
+	for i, t := range []struct {
+		status string
+		args   []string
+		out    string
+	}{
+		{"", nil, "unknown\n"},
+		{"active", nil, "active\n"},
+		{"active", []string{"--format", "json"}, `"active"` + "\n"},
+		{"blocked", []string{"--include-data"}, "message: need a database\nstatus: blocked\n"},
+		{"blocked", []string{"--include-data", "--format", "json"},
+			`{"message":"need a database","status":"blocked"}` + "\n"},
+	} {
+		c.Logf("test %d: %v", i, t.args)
+		hctx := s.GetHookContext(c, -1, "")
+		hctx.workloadStatus = t.status
+		if t.status != "" {
+			hctx.workloadInfo = "need a database"
+		}
+		com, err := jujuc.NewCommand(hctx, cmdString("status-get"))
+		c.Assert(err, jc.ErrorIsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Check(code, gc.Equals, 0)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Check(bufferString(ctx.Stdout), gc.Equals, t.out)
+	}
+}
+
+func (s *StatusGetSuite) TestError(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.shouldError = true
+	com, err := jujuc.NewCommand(hctx, cmdString("status-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, nil)
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: cannot read workload status: WorkloadStatus error!\n")
+}
+
+func (s *StatusGetSuite) TestUnknownArg(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("status-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = testing.InitCommand(com, []string{"blah"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["blah"\]`)
+}
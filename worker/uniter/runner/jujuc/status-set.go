@@ -0,0 +1,77 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+)
+
+// validWorkloadStatuses holds the workload statuses that a charm may
+// set, in the order they are documented.
+var validWorkloadStatuses = []string{
+	"maintenance",
+	"blocked",
+	"waiting",
+	"active",
+}
+
+// StatusSetCommand implements the status-set command.
+type StatusSetCommand struct {
+	cmd.CommandBase
+	ctx     Context
+	Status  string
+	Message string
+}
+
+func NewStatusSetCommand(ctx Context) cmd.Command {
+	return &StatusSetCommand{ctx: ctx}
+}
+
+func (c *StatusSetCommand) Info() *cmd.Info {
+	doc := `
+status-set sets the status of the unit's workload, as shown by juju status,
+along with an optional message for the operator. The status must be one of:
+
+    maintenance: the unit is installing or reconfiguring its workload
+    blocked:     the unit cannot continue without operator intervention
+    waiting:     the unit is waiting for another service to become available
+    active:      the workload is running and ready to serve
+`
+	return &cmd.Info{
+		Name:    "status-set",
+		Args:    "<" + strings.Join(validWorkloadStatuses, "|") + "> [message]",
+		Purpose: "set the status of the unit's workload",
+		Doc:     doc,
+	}
+}
+
+func (c *StatusSetCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no status specified")
+	}
+	c.Status = args[0]
+	valid := false
+	for _, status := range validWorkloadStatuses {
+		if c.Status == status {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return errors.Errorf("invalid status %q, expected one of %v", c.Status, validWorkloadStatuses)
+	}
+	if len(args) > 1 {
+		c.Message = args[1]
+		args = args[1:]
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *StatusSetCommand) Run(_ *cmd.Context) error {
+	err := c.ctx.SetWorkloadStatus(c.Status, c.Message)
+	return errors.Annotate(err, "cannot set workload status")
+}
@@ -0,0 +1,71 @@
+// Copyright 2014 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type StatusSetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&StatusSetSuite{})
+
+func (s *StatusSetSuite) TestInit(c *gc.C) {
+	for i, t := range []struct {
+		args    []string
+		status  string
+		message string
+		err     string
+	}{
+		{nil, "", "", "no status specified"},
+		{[]string{"active"}, "active", "", ""},
+		{[]string{"blocked", "need a database"}, "blocked", "need a database", ""},
+		{[]string{"started"}, "", "", `invalid status "started", expected one of \[maintenance blocked waiting active\]`},
+		{[]string{"waiting", "for mysql", "extra"}, "", "", `unrecognized args: \["extra"\]`},
+	} {
+		c.Logf("test %d: %v", i, t.args)
+		hctx := s.GetHookContext(c, -1, "")
+		com, err := jujuc.NewCommand(hctx, cmdString("status-set"))
+		c.Assert(err, jc.ErrorIsNil)
+		err = testing.InitCommand(com, t.args)
+		if t.err != "" {
+			c.Check(err, gc.ErrorMatches, t.err)
+			continue
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		command := com.(*jujuc.StatusSetCommand)
+		c.Check(command.Status, gc.Equals, t.status)
+		c.Check(command.Message, gc.Equals, t.message)
+	}
+}
+
+func (s *StatusSetSuite) TestRun(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("status-set"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"maintenance", "installing packages"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(hctx.workloadStatus, gc.Equals, "maintenance")
+	c.Check(hctx.workloadInfo, gc.Equals, "installing packages")
+}
+
+func (s *StatusSetSuite) TestError(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.shouldError = true
+	com, err := jujuc.NewCommand(hctx, cmdString("status-set"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"active"})
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "error: cannot set workload status: SetWorkloadStatus error!\n")
+}
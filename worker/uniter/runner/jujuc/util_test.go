@@ -87,6 +87,10 @@ type Context struct {
 	canAddMetrics  bool
 	rebootPriority jujuc.RebootPriority
 	shouldError    bool
+	isLeader       bool
+	leaderSettings map[string]string
+	workloadStatus string
+	workloadInfo   string
 }
 
 func (c *Context) AddMetric(key, value string, created time.Time) error {
@@ -265,6 +269,60 @@ func (c *Context) RequestReboot(priority jujuc.RebootPriority) error {
 	}
 }
 
+func (c *Context) IsLeader() (bool, error) {
+	if c.shouldError {
+		return false, fmt.Errorf("IsLeader error!")
+	}
+	return c.isLeader, nil
+}
+
+func (c *Context) LeaderSettings() (map[string]string, error) {
+	if c.shouldError {
+		return nil, fmt.Errorf("LeaderSettings error!")
+	}
+	result := make(map[string]string)
+	for key, value := range c.leaderSettings {
+		result[key] = value
+	}
+	return result, nil
+}
+
+func (c *Context) WriteLeaderSettings(settings map[string]string) error {
+	if !c.isLeader {
+		return fmt.Errorf("not the leader")
+	}
+	if c.leaderSettings == nil {
+		c.leaderSettings = make(map[string]string)
+	}
+	for key, value := range settings {
+		if value == "" {
+			delete(c.leaderSettings, key)
+		} else {
+			c.leaderSettings[key] = value
+		}
+	}
+	return nil
+}
+
+func (c *Context) WorkloadStatus() (string, string, error) {
+	if c.shouldError {
+		return "", "", fmt.Errorf("WorkloadStatus error!")
+	}
+	if c.workloadStatus == "" {
+		return "unknown", "", nil
+	}
+	return c.workloadStatus, c.workloadInfo, nil
+}
+
+func (c *Context) SetWorkloadStatus(status, info string) error {
+	if c.shouldError {
+		return fmt.Errorf("SetWorkloadStatus error!")
+	}
+	c.workloadStatus = status
+	c.workloadInfo = info
+	return nil
+}
+
 func cmdString(cmd string) string {
 	return cmd + jujuc.CmdSuffix
 }
@@ -1352,6 +1352,25 @@ func (s *UniterSuite) TestUniterMeterStatusChanged(c *gc.C) {
 	s.runUniterTests(c, meterStatusEventTests)
 }
 
+var leadershipEventTests = []uniterTest{
+	ut(
+		"leader-elected hook runs when the unit becomes leader",
+		createCharm{
+			customize: func(c *gc.C, ctx *context, path string) {
+				ctx.writeHook(c, filepath.Join(path, "hooks", "leader-elected"), true)
+			},
+		},
+		serveCharm{},
+		createUniter{},
+		waitUnit{status: params.StatusStarted},
+		waitHooks{"install", "config-changed", "start", "leader-elected"},
+	),
+}
+
+func (s *UniterSuite) TestUniterLeadershipEvents(c *gc.C) {
+	s.runUniterTests(c, leadershipEventTests)
+}
+
 var collectMetricsEventTests = []uniterTest{
 	ut(
 		"collect-metrics event triggered by manual timer",